@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/buildinfo"
+)
+
+// Set at build time with -ldflags "-X main.version=... -X main.commit=...
+// -X main.buildTime=...", like validator-service.
+var (
+	version   string
+	commit    string
+	buildTime string
+)
+
+// printVersion prints this binary's build.
+func printVersion() {
+	build := buildinfo.Resolve(version, commit, buildTime)
+	fmt.Printf("loadgen %s (commit %s, built %s, %s)\n", build.Version, build.Commit, build.BuildTime, build.GoVersion)
+}
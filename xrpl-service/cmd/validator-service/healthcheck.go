@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/config"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/server"
+)
+
+const healthcheckTimeout = 3 * time.Second
+
+// runHealthcheck probes /startupz on the service's first configured listener
+// and returns the process exit code. It is the exec-form command for Docker
+// HEALTHCHECK, so the runtime image needs no curl or wget.
+func runHealthcheck(cfg *config.Config) int {
+	spec := "tcp:" + net.JoinHostPort(cfg.ListenAddr, strconv.Itoa(cfg.ListenPort))
+	if len(cfg.ListenSpecs) > 0 {
+		spec = cfg.ListenSpecs[0]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthcheckTimeout)
+	defer cancel()
+	if err := server.ProbeStartup(ctx, spec); err != nil {
+		fmt.Fprintf(os.Stderr, "healthcheck failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
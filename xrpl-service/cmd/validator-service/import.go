@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/config"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+)
+
+// runImport seeds the validator metadata cache from a historical dataset,
+// "import-validators <xrpscan|vhs> <file>", and returns the process exit
+// code. Run it while the service is stopped.
+func runImport(cfg *config.Config, args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: validator-service import-validators <xrpscan|vhs> <file>")
+		return 2
+	}
+	file, err := os.Open(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+		return 1
+	}
+	defer file.Close()
+
+	summary, err := validator.ImportMetadata(cfg.ValidatorMetadataCachePath, args[0], file, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("imported %s into %s: %d read, %d added, %d updated, %d skipped\n",
+		args[1], cfg.ValidatorMetadataCachePath, summary.Read, summary.Added, summary.Updated, summary.Skipped)
+	return 0
+}
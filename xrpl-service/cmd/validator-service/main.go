@@ -8,13 +8,16 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/brandon/xrpl-validator-service/internal/config"
-	"github.com/brandon/xrpl-validator-service/internal/geolocation"
-	"github.com/brandon/xrpl-validator-service/internal/metrics"
-	"github.com/brandon/xrpl-validator-service/internal/server"
-	"github.com/brandon/xrpl-validator-service/internal/transaction"
-	"github.com/brandon/xrpl-validator-service/internal/validator"
-	"github.com/brandon/xrpl-validator-service/internal/xrpl"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/census"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/config"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/decentralization"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/engine"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/health"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/preferences"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/report"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/server"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/stats"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,6 +25,17 @@ func main() {
 	// Load configuration
 	cfg := config.NewConfig()
 
+	// "healthcheck" probes an already running instance and exits
+	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+		os.Exit(runHealthcheck(cfg))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		os.Exit(runVersion())
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import-validators" {
+		os.Exit(runImport(cfg, os.Args[2:]))
+	}
+
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		panic(fmt.Sprintf("Invalid configuration: %v", err))
@@ -36,88 +50,234 @@ func main() {
 	}
 	logger.SetLevel(logLevel)
 
+	build := currentBuild()
 	logger.WithFields(logrus.Fields{
+		"version":             build.Version,
+		"commit":              build.Commit,
+		"instance_id":         engine.InstanceID(cfg),
 		"validator_json_rpc":  cfg.PublicXRPLJSONRPCURL,
 		"validator_websocket": cfg.PublicXRPLWebSocketURL,
 		"tx_json_rpc":         cfg.TransactionJSONRPCURL,
 		"tx_websocket":        cfg.TransactionWebSocketURL,
+		"tx_streams":          cfg.TransactionStreams,
 		"tx_buffer_size":      cfg.TransactionBufferSize,
 		"geo_enrichment_q":    cfg.GeoEnrichmentQSize,
 		"geo_enrichment_w":    cfg.GeoEnrichmentWorkers,
 		"max_geo_candidates":  cfg.MaxGeoCandidates,
+		"allowed_tx_results":  cfg.AllowedTxResults,
+		"tx_processor":        cfg.TxProcessorCommand,
 		"broadcast_buffer":    cfg.BroadcastBufferSize,
 		"ws_client_buffer":    cfg.WSClientBufferSize,
+		"geolite_enabled":     cfg.GeoLiteEnabled,
 		"geolite_db_path":     cfg.GeoLiteDBPath,
 		"network":             cfg.Network,
+		"role":                cfg.Role,
 		"listen_addr":         cfg.ListenAddr,
 		"listen_port":         cfg.ListenPort,
+		"listen_specs":        cfg.ListenSpecs,
+		"privacy_mode":        cfg.PrivacyMode,
+		"dev_mode":            cfg.DevMode,
+		"public_mode":         cfg.PublicMode,
 	}).Info("XRPL Validator Service starting")
+	if cfg.DevMode {
+		logger.Warn("DEV_MODE is enabled; POST /dev/inject accepts synthetic data from any client")
+	}
 
-	validatorClient := xrpl.NewClient(cfg.PublicXRPLJSONRPCURL, cfg.PublicXRPLWebSocketURL, logger)
-	txClient := xrpl.NewClient(cfg.TransactionJSONRPCURL, cfg.TransactionWebSocketURL, logger)
 	appCtx, appCancel := context.WithCancel(context.Background())
 	defer appCancel()
 
-	geoResolver, err := geolocation.NewResolver(logger, geolocation.ResolverConfig{
-		CachePath:          cfg.GeoCachePath,
-		GeoLiteDBPath:      cfg.GeoLiteDBPath,
-		GeoLiteDownloadURL: cfg.GeoLiteDownloadURL,
-		AutoDownload:       cfg.GeoLiteAutoDownload,
-	})
+	pipeline, err := engine.Start(appCtx, cfg, logger)
 	if err != nil {
-		logger.WithError(err).Fatal("Failed to initialize GeoLite resolver")
+		logger.WithError(err).Fatal("Failed to start ingestion pipeline")
 	}
-	defer func() {
-		if err := geoResolver.Close(); err != nil {
-			logger.WithError(err).Warn("Error closing GeoLite resolver")
-		}
-	}()
+	validatorSource, transactionSource := pipeline.Validators, pipeline.Transactions
+	ingestionControl := pipeline.Ingestion
 
-	// Create validator fetcher
-	validatorFetcher := validator.NewFetcher(
-		validatorClient,
-		time.Duration(cfg.ValidatorRefreshInterval)*time.Second,
-		geoResolver,
-		cfg.ValidatorListSites,
-		cfg.SecondaryValidatorRegistryURL,
-		cfg.ValidatorMetadataCachePath,
-		cfg.NetworkHealthJSONRPCURLs,
-		cfg.NetworkHealthRetries,
-		cfg.Network,
+	// Create server status poller
+	statusPoller := health.NewPoller(
+		validatorSource,
+		time.Duration(cfg.ServerStatusPollInterval)*time.Second,
+		time.Duration(cfg.LedgerLagThreshold)*time.Second,
 		logger,
 	)
-	validatorFetcher.Start(appCtx)
 
-	// Create transaction listener
-	transactionListener := transaction.NewListener(
-		txClient,
-		cfg.MinPaymentDrops,
-		geoResolver,
+	// Create pipeline watchdog
+	watchdog := health.NewWatchdog(
+		validatorSource,
+		transactionSource,
+		time.Duration(cfg.WatchdogTxStallSeconds)*time.Second,
+		time.Duration(cfg.ValidatorRefreshInterval)*time.Second,
 		logger,
-		transaction.ListenerOptions{
-			TransactionBufferSize: cfg.TransactionBufferSize,
-			GeoEnrichmentQSize:    cfg.GeoEnrichmentQSize,
-			GeoWorkerCount:        cfg.GeoEnrichmentWorkers,
-			MaxGeoCandidates:      cfg.MaxGeoCandidates,
-		},
 	)
-	if err := transactionListener.Start(appCtx); err != nil {
-		metrics.ValidatorFetchTotal.WithLabelValues("error").Inc() // Note: reusing for listener start
-		logger.WithError(err).Error("Failed to start transaction listener")
+
+	// Track accounts created on the ledger
+	newAccounts := stats.NewNewAccountTracker()
+	transactionSource.AddCallback(newAccounts.ObserveTransaction)
+
+	// Keep the last 24 hours of activity for /stats, /stats/timeline and
+	// /hotspots
+	activity := stats.NewActivity()
+	transactionSource.AddCallback(activity.ObserveTransaction)
+	validatorSource.AddCallback(func(*models.ValidatorUpdate) {
+		activity.ObserveValidators(validatorSource.GetValidators())
+	})
+	if validators := validatorSource.GetValidators(); len(validators) > 0 {
+		activity.ObserveValidators(validators)
+	}
+
+	// Create network metric anomaly detector
+	var anomalyDetector *health.AnomalyDetector
+	if cfg.AnomalyWindowSeconds > 0 {
+		anomalyDetector = health.NewAnomalyDetector(
+			validatorSource,
+			time.Duration(cfg.AnomalyWindowSeconds)*time.Second,
+			cfg.AnomalyZThreshold,
+			logger,
+		)
+		transactionSource.AddCallback(anomalyDetector.ObserveTransaction)
+	}
+
+	// Create validator set SLO monitor
+	var sloMonitor *health.SLOMonitor
+	sloBounds := health.SLOBounds{
+		MinValidators:      cfg.SLOMinValidators,
+		MaxValidators:      cfg.SLOMaxValidators,
+		MinCoveragePercent: cfg.SLOMinCoveragePercent,
+	}
+	if sloBounds.Enabled() {
+		sloMonitor = health.NewSLOMonitor(validatorSource, sloBounds, logger)
+	}
+
+	// Suspend the pipeline checks along with upstream ingestion
+	if ingestionControl != nil {
+		ingestionControl.Add(watchdog)
+		if anomalyDetector != nil {
+			ingestionControl.Add(anomalyDetector)
+		}
+	}
+
+	// Create network summary reporter
+	var reporter *report.Reporter
+	if cfg.ReportPeriod != "" {
+		var err error
+		reporter, err = report.NewReporter(validatorSource, cfg.ReportPeriod, cfg.Network, cfg.ReportWebhookURLs, cfg.ReportOutputDir, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create network reporter")
+		}
+		transactionSource.AddCallback(reporter.ObserveTransaction)
+	}
+
+	// Record decentralization snapshots for /decentralization/compare
+	var decentralizationHistory *decentralization.History
+	if cfg.DecentralizationHistoryPath != "" {
+		decentralizationHistory = decentralization.NewHistory(
+			validatorSource,
+			pipeline.ASNs,
+			cfg.DecentralizationHistoryPath,
+			time.Duration(cfg.DecentralizationInterval)*time.Second,
+			time.Duration(cfg.DecentralizationRetentionDays)*24*time.Hour,
+			nil,
+			logger,
+		)
+	}
+
+	// Load per-API-key stream defaults for /preferences
+	var clientPreferences *preferences.Store
+	if cfg.PreferencesPath != "" {
+		var err error
+		clientPreferences, err = preferences.Load(cfg.PreferencesPath, nil, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to load client preferences")
+		}
 	}
 
 	// Create HTTP server
 	httpServer := server.NewServer(
-		validatorFetcher,
-		transactionListener,
+		validatorSource,
+		transactionSource,
 		cfg.ListenAddr,
 		cfg.ListenPort,
 		cfg.CORSAllowedOrigins,
 		cfg.BroadcastBufferSize,
 		cfg.WSClientBufferSize,
 		logger,
+		server.ServerOptions{
+			StatusPoller:            statusPoller,
+			Watchdog:                watchdog,
+			AnomalyDetector:         anomalyDetector,
+			SLOMonitor:              sloMonitor,
+			NewAccounts:             newAccounts,
+			Activity:                activity,
+			Burn:                    pipeline.Burn,
+			Distributions:           pipeline.Distributions,
+			Ingestion:               ingestionControl,
+			GeoDB:                   pipeline.GeoDB,
+			PeerCollector:           pipeline.Peers,
+			IssuerGraphs:            pipeline.Issuers,
+			Watchlist:               pipeline.Watchlist,
+			Labels:                  pipeline.Labels,
+			Preferences:             clientPreferences,
+			Decentralization:        decentralizationHistory,
+			CensusDir:               cfg.CensusDir,
+			ResponseCacheTTL:        time.Duration(cfg.ResponseCacheTTL) * time.Second,
+			UpstreamLagTarget:       time.Duration(cfg.LoadUpstreamLagTarget) * time.Second,
+			CORSMaxAge:              time.Duration(cfg.CORSMaxAge) * time.Second,
+			TrustedProxies:          cfg.TrustedProxies,
+			OriginPolicies:          cfg.WSOriginPolicies,
+			Views:                   cfg.Views,
+			UNLPresets:              cfg.UNLPresets,
+			ListenSpecs:             cfg.ListenSpecs,
+			APIKeys:                 cfg.APIKeys,
+			AdminToken:              cfg.AdminToken,
+			ClientBandwidthLimit:    cfg.WSClientBandwidthLimit,
+			BandwidthExceededAction: cfg.WSBandwidthExceededAction,
+			PrivacyMode:             cfg.PrivacyMode,
+			CoordinatePrecision:     cfg.CoordinatePrecision,
+			DisplayWeightMode:       cfg.DisplayWeightMode,
+			DisplayWeightFiatRate:   cfg.DisplayWeightFiatRate,
+			Explorers:               cfg.Explorers,
+			DevMode:                 cfg.DevMode,
+			PublicMode:              cfg.PublicMode,
+			AdminListenSpec:         cfg.AdminListenSpec,
+			PublicRateLimit:         cfg.PublicRateLimit,
+			PublicRateBurst:         cfg.PublicRateBurst,
+			MaxConnectionsPerIP:     cfg.PublicMaxConnectionsPerIP,
+			IngestOnly:              cfg.Role == "ingest",
+			ExportSigningKey:        cfg.ExportKey(),
+			Build:                   build,
+			InstanceID:              engine.InstanceID(cfg),
+		},
 	)
 
+	// Write the daily validator census as the API publishes the validators
+	var censusRecorder *census.Recorder
+	if cfg.CensusDir != "" {
+		censusRecorder = census.NewRecorder(validatorSource, decentralizationHistory, census.Options{
+			Dir:       cfg.CensusDir,
+			UploadURL: cfg.CensusUploadURL,
+			Key:       cfg.ExportKey(),
+			Publish:   httpServer.PublishValidators,
+		}, nil, logger)
+	}
+
+	statusPoller.Start(appCtx)
+	watchdog.Start(appCtx)
+	if anomalyDetector != nil {
+		anomalyDetector.Start(appCtx)
+	}
+	if sloMonitor != nil {
+		sloMonitor.Start(appCtx)
+	}
+	if reporter != nil {
+		reporter.Start(appCtx)
+	}
+	if decentralizationHistory != nil {
+		decentralizationHistory.Start(appCtx)
+	}
+	if censusRecorder != nil {
+		censusRecorder.Start(appCtx)
+	}
+
 	// Start HTTP server in a goroutine
 	go func() {
 		logger.Info("HTTP Server started")
@@ -138,26 +298,32 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
 
-	// Stop transaction listener
-	if err := transactionListener.Stop(shutdownCtx); err != nil {
-		logger.WithError(err).Error("Error stopping transaction listener")
+	// Stop server status poller
+	statusPoller.Stop()
+	watchdog.Stop()
+	if anomalyDetector != nil {
+		anomalyDetector.Stop()
+	}
+	if sloMonitor != nil {
+		sloMonitor.Stop()
+	}
+	if reporter != nil {
+		reporter.Stop()
+	}
+	if decentralizationHistory != nil {
+		decentralizationHistory.Stop()
+	}
+	if censusRecorder != nil {
+		censusRecorder.Stop()
 	}
-
-	// Stop validator fetcher
-	validatorFetcher.Stop()
 
 	// Stop HTTP server
 	if err := httpServer.Stop(shutdownCtx); err != nil {
 		logger.WithError(err).Error("Error stopping HTTP server")
 	}
 
-	// Close XRPL clients
-	if err := validatorClient.Close(); err != nil {
-		logger.WithError(err).Error("Error closing validator source client")
-	}
-	if err := txClient.Close(); err != nil {
-		logger.WithError(err).Error("Error closing transaction source client")
-	}
+	// Stop validator and transaction sources
+	pipeline.Stop(shutdownCtx)
 
 	logger.Info("Service shutdown complete")
 }
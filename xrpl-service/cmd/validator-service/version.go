@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/buildinfo"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X main.version=v1.4.0 -X main.commit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/validator-service
+//
+// Values left empty fall back to what the Go toolchain embeds.
+var (
+	version   string
+	commit    string
+	buildTime string
+)
+
+// currentBuild describes this binary.
+func currentBuild() buildinfo.Build {
+	return buildinfo.Resolve(version, commit, buildTime)
+}
+
+// runVersion prints the build and returns the process exit code.
+func runVersion() int {
+	build := currentBuild()
+	fmt.Printf("validator-service %s (commit %s, built %s, %s)\n", build.Version, build.Commit, build.BuildTime, build.GoVersion)
+	return 0
+}
@@ -0,0 +1,58 @@
+// Package buildinfo describes the running build. The main packages set the
+// version, commit and build time with -ldflags "-X main.version=..." and
+// pass them to Resolve, which falls back to the module and VCS information
+// the Go toolchain embeds.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Build identifies one build of a command.
+type Build struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Resolve returns the build described by the ldflags-set values, filling
+// any left empty from the embedded build information. Version defaults to
+// "dev", and Commit and BuildTime to "unknown".
+func Resolve(version, commit, buildTime string) Build {
+	build := Build{Version: version, Commit: commit, BuildTime: buildTime, GoVersion: runtime.Version()}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if build.Version == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			build.Version = info.Main.Version
+		}
+		modified := false
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if build.Commit == "" {
+					build.Commit = setting.Value
+				}
+			case "vcs.time":
+				if build.BuildTime == "" {
+					build.BuildTime = setting.Value
+				}
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if commit == "" && build.Commit != "" && modified {
+			build.Commit += "-dirty"
+		}
+	}
+	if build.Version == "" {
+		build.Version = "dev"
+	}
+	if build.Commit == "" {
+		build.Commit = "unknown"
+	}
+	if build.BuildTime == "" {
+		build.BuildTime = "unknown"
+	}
+	return build
+}
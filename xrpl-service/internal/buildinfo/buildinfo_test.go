@@ -0,0 +1,24 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestResolvePrefersLdflagsValues(t *testing.T) {
+	build := Resolve("v1.4.0", "0123456789abcdef0123", "2026-01-02T03:04:05Z")
+	if build.Version != "v1.4.0" || build.Commit != "0123456789abcdef0123" || build.BuildTime != "2026-01-02T03:04:05Z" {
+		t.Fatalf("unexpected build %+v", build)
+	}
+	if build.GoVersion != runtime.Version() {
+		t.Fatalf("expected go version %s, got %s", runtime.Version(), build.GoVersion)
+	}
+}
+
+func TestResolveDefaults(t *testing.T) {
+	// Test binaries carry no VCS information.
+	build := Resolve("", "", "")
+	if build.Version == "" || build.Commit == "" || build.BuildTime == "" {
+		t.Fatalf("expected defaults, got %+v", build)
+	}
+}
@@ -0,0 +1,126 @@
+// Package cachefile reads and writes the versioned JSON cache files kept in
+// DATA_DIR, upgrading older formats in place so that a format change does
+// not throw away a warm cache.
+package cachefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// ErrNewerVersion is returned by Load for a file written by a newer release.
+var ErrNewerVersion = errors.New("cache file has a newer version")
+
+// Document is a cache file's top-level JSON object, keyed by field. Every
+// document has a "version" field.
+type Document map[string]json.RawMessage
+
+// MigrateFunc upgrades a document by one version in place. Load sets the
+// new version afterwards.
+type MigrateFunc func(doc Document) error
+
+// Format describes one cache file format.
+type Format struct {
+	// Name identifies the cache in errors, e.g. "geolocation cache".
+	Name string
+
+	// Version is the current version, written by Write.
+	Version int
+
+	// Migrations upgrade a document from the version they are keyed by to
+	// the next one. Upgrading from version v to Version needs a migration
+	// for every version from v to Version-1.
+	Migrations map[int]MigrateFunc
+}
+
+// Load reads the cache file at path and returns it at the current version.
+// An older file is first copied to BackupPath and then upgraded, and the
+// upgraded file replaces the original. A newer file is also backed up, since
+// the caller will overwrite it, and returns ErrNewerVersion. A missing file
+// returns an error satisfying os.IsNotExist.
+func (f Format) Load(path string) (data []byte, migratedFrom int, err error) {
+	data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	version, err := doc.version()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	if version == f.Version {
+		return data, 0, nil
+	}
+
+	if err := writeAtomic(BackupPath(path, version), data); err != nil {
+		return nil, 0, fmt.Errorf("failed to back up %s: %w", f.Name, err)
+	}
+	if version > f.Version {
+		return nil, 0, fmt.Errorf("%s version %d, this release reads %d: %w", f.Name, version, f.Version, ErrNewerVersion)
+	}
+
+	for from := version; from < f.Version; from++ {
+		migrate, ok := f.Migrations[from]
+		if !ok {
+			return nil, 0, fmt.Errorf("no migration for %s version %d", f.Name, from)
+		}
+		if err := migrate(doc); err != nil {
+			return nil, 0, fmt.Errorf("failed to migrate %s from version %d: %w", f.Name, from, err)
+		}
+		doc["version"] = json.RawMessage(strconv.Itoa(from + 1))
+	}
+
+	data, err = json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := writeAtomic(path, data); err != nil {
+		return nil, 0, fmt.Errorf("failed to write migrated %s: %w", f.Name, err)
+	}
+	return data, version, nil
+}
+
+// Write replaces the cache file at path with data, which must carry the
+// current version.
+func (f Format) Write(path string, data []byte) error {
+	return writeAtomic(path, data)
+}
+
+// BackupPath is where Load keeps a copy of a file at version before
+// replacing it.
+func BackupPath(path string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", path, version)
+}
+
+func (doc Document) version() (int, error) {
+	raw, ok := doc["version"]
+	if !ok {
+		return 0, errors.New("missing version")
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return 0, fmt.Errorf("invalid version: %w", err)
+	}
+	return version, nil
+}
+
+// writeAtomic writes data to a temporary file next to path and renames it
+// over path, so readers never see a partial file.
+func writeAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
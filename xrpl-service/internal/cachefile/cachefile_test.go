@@ -0,0 +1,101 @@
+package cachefile
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testFormat() Format {
+	return Format{
+		Name:    "test cache",
+		Version: 3,
+		Migrations: map[int]MigrateFunc{
+			1: func(doc Document) error {
+				doc["steps"] = json.RawMessage(`["v1"]`)
+				return nil
+			},
+			2: func(doc Document) error {
+				var steps []string
+				if err := json.Unmarshal(doc["steps"], &steps); err != nil {
+					return err
+				}
+				data, err := json.Marshal(append(steps, "v2"))
+				doc["steps"] = data
+				return err
+			},
+		},
+	}
+}
+
+func TestLoadMigratesOlderVersionsInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	original := []byte(`{"version":1,"entries":{"a":1}}`)
+	if err := os.WriteFile(path, original, 0o644); err != nil {
+		t.Fatalf("failed to write cache: %v", err)
+	}
+
+	data, migratedFrom, err := testFormat().Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if migratedFrom != 1 {
+		t.Fatalf("expected a migration from version 1, got %d", migratedFrom)
+	}
+
+	var payload struct {
+		Version int            `json:"version"`
+		Entries map[string]int `json:"entries"`
+		Steps   []string       `json:"steps"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("failed to parse migrated cache: %v", err)
+	}
+	if payload.Version != 3 || payload.Entries["a"] != 1 {
+		t.Fatalf("expected version 3 with entries kept, got %+v", payload)
+	}
+	if len(payload.Steps) != 2 || payload.Steps[0] != "v1" || payload.Steps[1] != "v2" {
+		t.Fatalf("expected both migrations in order, got %v", payload.Steps)
+	}
+
+	onDisk, _ := os.ReadFile(path)
+	if string(onDisk) != string(data) {
+		t.Fatal("expected the migrated cache to replace the file")
+	}
+	backup, err := os.ReadFile(BackupPath(path, 1))
+	if err != nil || string(backup) != string(original) {
+		t.Fatalf("expected the original to be backed up, got %q (%v)", backup, err)
+	}
+
+	if _, migratedFrom, err := testFormat().Load(path); err != nil || migratedFrom != 0 {
+		t.Fatalf("expected a current file to load as is, got %d (%v)", migratedFrom, err)
+	}
+}
+
+func TestLoadRejectsNewerAndUnmigratableVersions(t *testing.T) {
+	dir := t.TempDir()
+
+	newer := filepath.Join(dir, "newer.json")
+	os.WriteFile(newer, []byte(`{"version":4}`), 0o644)
+	if _, _, err := testFormat().Load(newer); !errors.Is(err, ErrNewerVersion) {
+		t.Fatalf("expected ErrNewerVersion, got %v", err)
+	}
+	if _, err := os.Stat(BackupPath(newer, 4)); err != nil {
+		t.Fatalf("expected the newer file to be backed up: %v", err)
+	}
+
+	unknown := filepath.Join(dir, "unknown.json")
+	os.WriteFile(unknown, []byte(`{"version":0}`), 0o644)
+	if _, _, err := testFormat().Load(unknown); err == nil {
+		t.Fatal("expected a version without a migration to fail")
+	}
+	if data, _ := os.ReadFile(unknown); string(data) != `{"version":0}` {
+		t.Fatalf("expected a failed migration to leave the file alone, got %q", data)
+	}
+
+	if _, _, err := testFormat().Load(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected a not-exist error, got %v", err)
+	}
+}
@@ -0,0 +1,348 @@
+// Package census writes a signed daily artifact of the validator set, their
+// locations, decentralization metrics and the sources they were fetched
+// from, building a public record of network geography that can be audited
+// and downloaded day by day.
+package census
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/decentralization"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/report"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+// checkInterval is how often the Recorder checks whether the day's census
+// is due. It bounds how late a census is written after midnight UTC or a
+// restart.
+const checkInterval = time.Minute
+
+// censusVersion is the version of the census payload.
+const censusVersion = 1
+
+// dateLayout formats the UTC day of a census.
+const dateLayout = "2006-01-02"
+
+// Artifact file names are census-<date>.json.
+const (
+	filePrefix = "census-"
+	fileSuffix = ".json"
+)
+
+// UploadPlaceholder is replaced by the artifact file name in the upload
+// URL.
+const UploadPlaceholder = "{name}"
+
+// Artifact is a census file: the payload and a detached Ed25519 signature
+// over its exact bytes.
+type Artifact struct {
+	PublicKey string          `json:"public_key"` // hex with the ED type prefix
+	Signature string          `json:"signature"`
+	Payload   json.RawMessage `json:"payload"` // a models.Census
+}
+
+// ValidatorSource provides the current validator set and when it was last
+// fetched. Sources that also report SourceStatus have it recorded.
+type ValidatorSource interface {
+	GetValidators() []*models.Validator
+	GetLastUpdate() time.Time
+}
+
+type sourceStatusSource interface {
+	SourceStatus() *models.SourceStatus
+}
+
+// Options configure a Recorder.
+type Options struct {
+	Dir       string             // directory artifacts are written to
+	UploadURL string             // when set, artifacts are PUT there with {name} replaced
+	Key       ed25519.PrivateKey // signs the artifacts
+
+	// Publish, when set, prepares validators the way the API serves them,
+	// e.g. applying privacy mode and coordinate precision.
+	Publish func([]*models.Validator) []*models.Validator
+}
+
+// Recorder writes one census per UTC day once the validator set has been
+// fetched. Artifacts are kept indefinitely.
+type Recorder struct {
+	source     ValidatorSource
+	history    *decentralization.History
+	opts       Options
+	httpClient *http.Client
+	clock      clock.Clock
+	logger     *logrus.Logger
+
+	mu       sync.Mutex
+	lastDate string // day of the last census written or found on disk
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRecorder creates a Recorder. history may be nil, leaving the spread
+// per country, AS number and operator out of the census.
+func NewRecorder(source ValidatorSource, history *decentralization.History, opts Options, clk clock.Clock, logger *logrus.Logger) *Recorder {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &Recorder{
+		source:     source,
+		history:    history,
+		opts:       opts,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		clock:      clock.OrReal(clk),
+		logger:     logger,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start writes censuses in the background.
+func (r *Recorder) Start(ctx context.Context) {
+	go func() {
+		ticker := r.clock.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		r.recordIfDue(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.stopChan:
+				return
+			case <-ticker.C():
+				r.recordIfDue(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops writing censuses.
+func (r *Recorder) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopChan)
+	})
+}
+
+// recordIfDue writes the census of the current UTC day unless it exists or
+// the validator set has not been fetched yet, and reports whether it did.
+// A failed write is retried at the next check.
+func (r *Recorder) recordIfDue(ctx context.Context) bool {
+	if r.source.GetLastUpdate().IsZero() {
+		return false
+	}
+	now := r.clock.Now()
+	date := now.UTC().Format(dateLayout)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lastDate == date {
+		return false
+	}
+	path := Path(r.opts.Dir, date)
+	if _, err := os.Stat(path); err == nil {
+		r.lastDate = date
+		return false
+	}
+
+	census := r.Compose(now)
+	data, err := r.sign(census)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to encode validator census")
+		return false
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		metrics.CensusDeliveriesTotal.WithLabelValues("file", "error").Inc()
+		r.logger.WithError(err).WithField("path", path).Warn("Failed to write validator census")
+		return false
+	}
+	metrics.CensusDeliveriesTotal.WithLabelValues("file", "ok").Inc()
+	r.lastDate = date
+
+	if r.opts.UploadURL != "" {
+		if err := r.upload(ctx, filepath.Base(path), data); err != nil {
+			metrics.CensusDeliveriesTotal.WithLabelValues("upload", "error").Inc()
+			r.logger.WithError(err).WithField("name", filepath.Base(path)).Warn("Failed to upload validator census")
+		} else {
+			metrics.CensusDeliveriesTotal.WithLabelValues("upload", "ok").Inc()
+		}
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"date":       date,
+		"validators": census.Count,
+		"path":       path,
+	}).Info("Wrote validator census")
+	return true
+}
+
+// Compose returns the census of the current validator set taken at now.
+func (r *Recorder) Compose(now time.Time) *models.Census {
+	validators := r.source.GetValidators()
+	if r.opts.Publish != nil {
+		validators = r.opts.Publish(validators)
+	}
+	published := make([]*models.Validator, 0, len(validators))
+	for _, v := range validators {
+		if v != nil {
+			published = append(published, v)
+		}
+	}
+	sort.Slice(published, func(i, j int) bool { return published[i].Address < published[j].Address })
+
+	census := &models.Census{
+		Version:           censusVersion,
+		Date:              now.UTC().Format(dateLayout),
+		GeneratedAt:       now.Unix(),
+		ValidatorsFetched: r.source.GetLastUpdate().Unix(),
+		Count:             len(published),
+		Validators:        published,
+		Decentralization:  report.Decentralization(published),
+	}
+	for _, v := range published {
+		if v.Network != "" {
+			census.Network = v.Network
+			break
+		}
+	}
+	if r.history != nil {
+		census.Spread = r.history.Current()
+	}
+	if source, ok := r.source.(sourceStatusSource); ok {
+		census.Sources = source.SourceStatus()
+	}
+	return census
+}
+
+// sign encodes census as an Artifact signed with the Recorder's key.
+func (r *Recorder) sign(census *models.Census) ([]byte, error) {
+	payload, err := json.Marshal(census)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&Artifact{
+		PublicKey: PublicKeyHex(r.opts.Key),
+		Signature: strings.ToUpper(hex.EncodeToString(ed25519.Sign(r.opts.Key, payload))),
+		Payload:   payload,
+	})
+}
+
+func (r *Recorder) upload(ctx context.Context, name string, data []byte) error {
+	uploadURL := strings.ReplaceAll(r.opts.UploadURL, UploadPlaceholder, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return xrpl.WrapTransportError(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &xrpl.HTTPStatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// Verify checks that an artifact is signed by publicKey and returns its
+// payload. The public_key embedded in the artifact must name the same key;
+// it is never trusted on its own.
+func Verify(data []byte, publicKey ed25519.PublicKey) (*models.Census, error) {
+	var artifact Artifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("parse census artifact: %w", err)
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid census public key")
+	}
+	if !strings.EqualFold(artifact.PublicKey, publicKeyHex(publicKey)) {
+		return nil, fmt.Errorf("census artifact is signed by another key")
+	}
+	signature, err := hex.DecodeString(artifact.Signature)
+	if err != nil || !ed25519.Verify(publicKey, artifact.Payload, signature) {
+		return nil, fmt.Errorf("census artifact signature does not verify")
+	}
+	var census models.Census
+	if err := json.Unmarshal(artifact.Payload, &census); err != nil {
+		return nil, fmt.Errorf("parse census payload: %w", err)
+	}
+	return &census, nil
+}
+
+// Path returns the artifact file of date in dir.
+func Path(dir, date string) string {
+	return filepath.Join(dir, filePrefix+date+fileSuffix)
+}
+
+// ValidDate reports whether date is a census date, YYYY-MM-DD.
+func ValidDate(date string) bool {
+	parsed, err := time.Parse(dateLayout, date)
+	return err == nil && parsed.Format(dateLayout) == date
+}
+
+// List returns the artifacts in dir, newest first. A missing directory
+// lists none.
+func List(dir string) ([]*models.CensusEntry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*models.CensusEntry{}, nil
+		}
+		return nil, err
+	}
+	entries := make([]*models.CensusEntry, 0, len(files))
+	for _, file := range files {
+		name := file.Name()
+		date, ok := strings.CutPrefix(name, filePrefix)
+		if !ok || file.IsDir() {
+			continue
+		}
+		if date, ok = strings.CutSuffix(date, fileSuffix); !ok || !ValidDate(date) {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, &models.CensusEntry{Date: date, Name: name, Size: info.Size()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Date > entries[j].Date })
+	return entries, nil
+}
+
+// PublicKeyHex formats an Ed25519 public key the way XRPL does: hex with
+// the ED type prefix.
+func PublicKeyHex(key ed25519.PrivateKey) string {
+	return publicKeyHex(key.Public().(ed25519.PublicKey))
+}
+
+func publicKeyHex(key ed25519.PublicKey) string {
+	return "ED" + strings.ToUpper(hex.EncodeToString(key))
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
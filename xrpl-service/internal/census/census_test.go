@@ -0,0 +1,159 @@
+package census
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+type fakeValidatorSet struct {
+	validators []*models.Validator
+	lastUpdate time.Time
+	sources    *models.SourceStatus
+}
+
+func (f *fakeValidatorSet) GetValidators() []*models.Validator { return f.validators }
+func (f *fakeValidatorSet) GetLastUpdate() time.Time           { return f.lastUpdate }
+func (f *fakeValidatorSet) SourceStatus() *models.SourceStatus { return f.sources }
+
+func testKey() ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+}
+
+func TestRecorderWritesOneSignedCensusPerDay(t *testing.T) {
+	dir := t.TempDir()
+	fake := clock.NewFake(time.Date(2024, 3, 1, 0, 5, 0, 0, time.UTC))
+	set := &fakeValidatorSet{sources: &models.SourceStatus{OK: []string{"validator_list"}, Failed: []string{}}}
+	recorder := NewRecorder(set, nil, Options{
+		Dir: dir,
+		Key: testKey(),
+		Publish: func(validators []*models.Validator) []*models.Validator {
+			out := make([]*models.Validator, 0, len(validators))
+			for _, v := range validators {
+				copy := *v
+				copy.Latitude = 0
+				out = append(out, &copy)
+			}
+			return out
+		},
+	}, fake, nil)
+
+	if recorder.recordIfDue(context.Background()) {
+		t.Fatal("expected no census before the first fetch")
+	}
+	set.lastUpdate = fake.Now()
+	set.validators = []*models.Validator{
+		{Address: "nB", CountryCode: "DE", Latitude: 52.5, Network: "mainnet"},
+		{Address: "nA", CountryCode: "US", Latitude: 40.7, Network: "mainnet"},
+	}
+	if !recorder.recordIfDue(context.Background()) {
+		t.Fatal("expected a census once validators are fetched")
+	}
+	fake.Advance(time.Hour)
+	if recorder.recordIfDue(context.Background()) {
+		t.Fatal("expected one census per day")
+	}
+
+	data, err := os.ReadFile(Path(dir, "2024-03-01"))
+	if err != nil {
+		t.Fatalf("read census: %v", err)
+	}
+	publicKey := testKey().Public().(ed25519.PublicKey)
+	census, err := Verify(data, publicKey)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if census.Date != "2024-03-01" || census.Count != 2 || census.Network != "mainnet" || census.Validators[0].Address != "nA" {
+		t.Fatalf("unexpected census %+v", census)
+	}
+	if census.Validators[0].Latitude != 0 {
+		t.Fatal("expected validators published through Publish")
+	}
+	if census.Decentralization.Countries != 2 || census.Sources == nil || census.Sources.OK[0] != "validator_list" {
+		t.Fatalf("expected decentralization metrics and source statuses, got %+v %+v", census.Decentralization, census.Sources)
+	}
+	if _, err := Verify([]byte(strings.Replace(string(data), `"nA"`, `"nZ"`, 1)), publicKey); err == nil {
+		t.Fatal("expected a tampered census to fail verification")
+	}
+	// An artifact re-signed with another key carries that key, and is
+	// rejected even though its signature matches it.
+	other := NewRecorder(set, nil, Options{Dir: t.TempDir(), Key: ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))}, fake, nil)
+	forged, err := other.sign(census)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := Verify(forged, publicKey); err == nil {
+		t.Fatal("expected a census signed by another key to fail verification")
+	}
+
+	// A restarted recorder finds the day's census on disk
+	restarted := NewRecorder(set, nil, Options{Dir: dir, Key: testKey()}, fake, nil)
+	if restarted.recordIfDue(context.Background()) {
+		t.Fatal("expected the existing census kept")
+	}
+	fake.Advance(24 * time.Hour)
+	if !restarted.recordIfDue(context.Background()) {
+		t.Fatal("expected the next day's census")
+	}
+
+	entries, err := List(dir)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Date != "2024-03-02" || entries[1].Name != "census-2024-03-01.json" || entries[1].Size != int64(len(data)) {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+}
+
+func TestRecorderUploadsCensus(t *testing.T) {
+	var uploaded string
+	var body []byte
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		uploaded = r.URL.Path
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer upstream.Close()
+
+	fake := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	set := &fakeValidatorSet{lastUpdate: fake.Now(), validators: []*models.Validator{{Address: "nA", CountryCode: "US"}}}
+	recorder := NewRecorder(set, nil, Options{Dir: t.TempDir(), UploadURL: upstream.URL + "/census/{name}", Key: testKey()}, fake, nil)
+	if !recorder.recordIfDue(context.Background()) {
+		t.Fatal("expected a census")
+	}
+	if uploaded != "/census/census-2024-03-01.json" {
+		t.Fatalf("unexpected upload path %q", uploaded)
+	}
+	if _, err := Verify(body, testKey().Public().(ed25519.PublicKey)); err != nil {
+		t.Fatalf("expected the signed artifact uploaded: %v", err)
+	}
+}
+
+func TestListSkipsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"census-2024-03-01.json", "census-2024-3-1.json", "census-2024-03-02.json.tmp", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	entries, err := List(dir)
+	if err != nil || len(entries) != 1 || entries[0].Date != "2024-03-01" {
+		t.Fatalf("expected only the census artifact, got %+v %v", entries, err)
+	}
+	if entries, err := List(filepath.Join(dir, "missing")); err != nil || len(entries) != 0 {
+		t.Fatalf("expected a missing directory to list none, got %+v %v", entries, err)
+	}
+}
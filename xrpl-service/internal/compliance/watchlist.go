@@ -0,0 +1,163 @@
+// Package compliance flags transactions that touch watchlisted countries or
+// accounts. Matching transactions are highlighted for compliance-oriented
+// deployments, never dropped. The watchlist runs as a transaction.Processor.
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Flag reasons. Country reasons are suffixed with ":<country code>".
+// Account reasons omit the address so they are safe to show in privacy
+// mode.
+const (
+	ReasonSourceCountry      = "source_country"
+	ReasonDestinationCountry = "destination_country"
+	ReasonSourceAccount      = "source_account"
+	ReasonDestinationAccount = "destination_account"
+)
+
+// File is the on-disk watchlist format, e.g.
+// {"countries":["KP","IR"],"accounts":["rExampleAccount..."]}.
+type File struct {
+	Countries []string `json:"countries"`
+	Accounts  []string `json:"accounts"`
+}
+
+// Watchlist matches transactions against watchlisted countries and
+// accounts. It is safe for concurrent use.
+type Watchlist struct {
+	countries map[string]struct{}
+	accounts  map[string]struct{}
+	logger    *logrus.Logger
+
+	enabled atomic.Bool
+	flagged atomic.Int64
+}
+
+// LoadWatchlist reads a watchlist file. The returned watchlist is enabled.
+func LoadWatchlist(path string, logger *logrus.Logger) (*Watchlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse watchlist %s: %w", path, err)
+	}
+	return NewWatchlist(file, logger)
+}
+
+// NewWatchlist validates file and returns an enabled watchlist.
+func NewWatchlist(file File, logger *logrus.Logger) (*Watchlist, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	w := &Watchlist{
+		countries: make(map[string]struct{}, len(file.Countries)),
+		accounts:  make(map[string]struct{}, len(file.Accounts)),
+		logger:    logger,
+	}
+	for _, country := range file.Countries {
+		code := strings.ToUpper(strings.TrimSpace(country))
+		if len(code) != 2 {
+			return nil, fmt.Errorf("invalid watchlist country %q: must be an ISO 3166-1 alpha-2 code", country)
+		}
+		w.countries[code] = struct{}{}
+	}
+	for _, account := range file.Accounts {
+		address := strings.TrimSpace(account)
+		if !strings.HasPrefix(address, "r") || len(address) < 25 || len(address) > 35 {
+			return nil, fmt.Errorf("invalid watchlist account %q", account)
+		}
+		w.accounts[address] = struct{}{}
+	}
+	if len(w.countries) == 0 && len(w.accounts) == 0 {
+		return nil, fmt.Errorf("watchlist has no countries or accounts")
+	}
+	w.enabled.Store(true)
+	return w, nil
+}
+
+// Name identifies the watchlist as a transaction processor.
+func (w *Watchlist) Name() string {
+	return "watchlist"
+}
+
+// Process sets tx.Flagged and tx.FlagReasons when tx touches the watchlist.
+// Country matches need the source or destination location at broadcast
+// time; locations resolved later are not re-checked.
+func (w *Watchlist) Process(ctx context.Context, tx *models.Transaction) error {
+	if !w.enabled.Load() {
+		return nil
+	}
+	reasons := w.match(tx)
+	if len(reasons) == 0 {
+		return nil
+	}
+	tx.Flagged = true
+	tx.FlagReasons = append(tx.FlagReasons, reasons...)
+	w.flagged.Add(1)
+	for _, reason := range reasons {
+		kind, _, _ := strings.Cut(reason, ":")
+		metrics.FlaggedTransactionsTotal.WithLabelValues(kind).Inc()
+	}
+	w.logger.WithFields(logrus.Fields{
+		"hash":    tx.Hash,
+		"reasons": reasons,
+	}).Debug("Transaction matched watchlist")
+	return nil
+}
+
+func (w *Watchlist) match(tx *models.Transaction) []string {
+	var reasons []string
+	if _, ok := w.accounts[tx.Account]; ok && tx.Account != "" {
+		reasons = append(reasons, ReasonSourceAccount)
+	}
+	if _, ok := w.accounts[tx.Destination]; ok && tx.Destination != "" {
+		reasons = append(reasons, ReasonDestinationAccount)
+	}
+	source, destination, _ := tx.RoleInfo()
+	if source != nil {
+		if _, ok := w.countries[source.CountryCode]; ok {
+			reasons = append(reasons, ReasonSourceCountry+":"+source.CountryCode)
+		}
+	}
+	if destination != nil {
+		if _, ok := w.countries[destination.CountryCode]; ok {
+			reasons = append(reasons, ReasonDestinationCountry+":"+destination.CountryCode)
+		}
+	}
+	return reasons
+}
+
+// SetEnabled turns flagging on or off without reloading the watchlist.
+func (w *Watchlist) SetEnabled(enabled bool) {
+	if w.enabled.Swap(enabled) != enabled {
+		w.logger.WithField("enabled", enabled).Info("Compliance watchlist toggled")
+	}
+}
+
+// Enabled reports whether transactions are being flagged.
+func (w *Watchlist) Enabled() bool {
+	return w.enabled.Load()
+}
+
+// Status summarizes the watchlist for the admin API.
+func (w *Watchlist) Status() *models.WatchlistStatus {
+	return &models.WatchlistStatus{
+		Enabled:      w.enabled.Load(),
+		Countries:    len(w.countries),
+		Accounts:     len(w.accounts),
+		FlaggedTotal: w.flagged.Load(),
+	}
+}
@@ -0,0 +1,111 @@
+package compliance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+const watchedAccount = "rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY"
+
+func TestWatchlistFlagsAccountsAndCountries(t *testing.T) {
+	w, err := NewWatchlist(File{Countries: []string{"kp"}, Accounts: []string{watchedAccount}}, nil)
+	if err != nil {
+		t.Fatalf("NewWatchlist: %v", err)
+	}
+
+	tx := &models.Transaction{
+		Hash:        "A",
+		Account:     "rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn",
+		Destination: watchedAccount,
+		Locations: []*models.GeoLocation{
+			{CountryCode: "US", Role: models.LocationRoleSource},
+			{CountryCode: "KP", Role: models.LocationRoleDestination},
+		},
+	}
+	if err := w.Process(context.Background(), tx); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	want := []string{ReasonDestinationAccount, "destination_country:KP"}
+	if !tx.Flagged || !reflect.DeepEqual(tx.FlagReasons, want) {
+		t.Fatalf("expected flagged with %v, got %v %v", want, tx.Flagged, tx.FlagReasons)
+	}
+
+	clean := &models.Transaction{Hash: "B", Account: "rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn"}
+	if err := w.Process(context.Background(), clean); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if clean.Flagged || clean.FlagReasons != nil {
+		t.Fatalf("expected unmatched transaction to stay unflagged, got %v", clean.FlagReasons)
+	}
+	if status := w.Status(); status.FlaggedTotal != 1 || status.Countries != 1 || status.Accounts != 1 || !status.Enabled {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestWatchlistToggle(t *testing.T) {
+	w, err := NewWatchlist(File{Accounts: []string{watchedAccount}}, nil)
+	if err != nil {
+		t.Fatalf("NewWatchlist: %v", err)
+	}
+	w.SetEnabled(false)
+
+	tx := &models.Transaction{Account: watchedAccount}
+	if err := w.Process(context.Background(), tx); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if tx.Flagged {
+		t.Fatal("expected a disabled watchlist not to flag")
+	}
+
+	w.SetEnabled(true)
+	if err := w.Process(context.Background(), tx); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if !tx.Flagged {
+		t.Fatal("expected a re-enabled watchlist to flag")
+	}
+}
+
+func TestNewWatchlistRejectsInvalidEntries(t *testing.T) {
+	tests := []struct {
+		name string
+		file File
+	}{
+		{name: "empty", file: File{}},
+		{name: "country name", file: File{Countries: []string{"North Korea"}}},
+		{name: "non-classic address", file: File{Accounts: []string{"X7AcgcsBL6XDcUb289X4mJ8djcdyKaB5hJDWMArnXr61cqZ"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewWatchlist(tt.file, nil); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadWatchlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlist.json")
+	if err := os.WriteFile(path, []byte(`{"countries":["IR","KP"],"accounts":["`+watchedAccount+`"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := LoadWatchlist(path, nil)
+	if err != nil {
+		t.Fatalf("LoadWatchlist: %v", err)
+	}
+	if status := w.Status(); status.Countries != 2 || status.Accounts != 1 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"countries":`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWatchlist(path, nil); err == nil {
+		t.Fatal("expected an error for malformed JSON")
+	}
+}
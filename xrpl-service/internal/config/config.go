@@ -1,13 +1,41 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/census"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/explorer"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/rules"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/server"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
 )
 
+// viewNamePattern is the form of a VIEWS name, which appears in URLs as
+// /t/{name}/.
+var viewNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// maxCoordinatePrecision is the most decimal places COORDINATE_PRECISION
+// accepts; 8 places are about a millimeter.
+const maxCoordinatePrecision = 8
+
+// minPeerProbeInterval is the shortest PEER_PROBE_INTERVAL accepted, so
+// validator hosts are not probed more than hourly.
+const minPeerProbeInterval = 3600
+
 type Config struct {
 	// External XRPL source configuration
 	PublicXRPLJSONRPCURL   string
@@ -16,37 +44,148 @@ type Config struct {
 	// Transaction Stream Source (external by default)
 	TransactionJSONRPCURL   string
 	TransactionWebSocketURL string
+	TransactionStreams      []string
+	TransactionPreview      bool // forward transactions_proposed payments provisionally
+	XRPLDNSRefreshInterval  int  // seconds, 0 disables
+	XRPLMessageBufferSize   int  // stream messages awaiting decode and dispatch
+	XRPLDecodeWorkers       int
+	XRPLMaxMessageBytes     int // larger stream messages close the connection
+	XRPLMaxMessageDepth     int // deeper stream messages are dropped before decoding
+
+	// Reconnect backoff shared by the XRPL and replica streams
+	ReconnectBackoffInitial   int     // seconds
+	ReconnectBackoffMax       int     // seconds
+	ReconnectBackoffJitter    float64 // fraction of each wait, 0 disables
+	ReconnectCircuitThreshold int     // consecutive failures, 0 never opens
+	ReconnectCircuitOpen      int     // seconds
+
+	// Outbound request ceilings per external host, shared by subsystems
+	OutboundBudgets    map[string]models.OutboundBudget
+	outboundBudgetsErr error
 
 	Network string
 
+	// Process role: all, ingest (upstream pipeline, feeding serve
+	// processes) or serve (stateless fan-out of an ingest process)
+	Role string
+
+	// Replica mode: mirror another instance instead of XRPL
+	ReplicaUpstreamURL string
+	ReplicaOrigin      string
+	ReplicaAPIKey      string
+
 	// Server Configuration
-	ListenPort         int
-	ListenAddr         string
-	CORSAllowedOrigins []string
+	ListenPort            int
+	ListenAddr            string
+	ListenSpecs           []string
+	CORSAllowedOrigins    []string
+	CORSMaxAge            int      // seconds browsers may cache preflights, 0 omits
+	TrustedProxies        []string // IPs and CIDRs whose X-Forwarded-For is believed
+	ResponseCacheTTL      int      // seconds, 0 disables
+	LoadUpstreamLagTarget int      // seconds of upstream lag /load reports as 100%
+	WSOriginPolicies      map[string]models.OriginPolicy
+	wsOriginPolicyErr     error
+	Views                 map[string]models.View
+	viewsErr              error
+	APIKeys               map[string]string // key -> name
+	apiKeysErr            error
+	PreferencesPath       string // empty disables client preferences
+	AdminToken            string
+	PrivacyMode           bool
+	CoordinatePrecision   int // decimal places of published coordinates, 0 unrounded
+	DisplayWeightMode     string
+	DisplayWeightFiatRate float64 // fiat price of one XRP for the fiat weight mode
+	Explorers             []models.Explorer
+	explorersErr          error
+	DevMode               bool
+	ExportSigningKey      string // hex Ed25519 seed signing /validators/export
+
+	// WebSocket bandwidth budget
+	WSClientBandwidthLimit    int // bytes per second, 0 disables
+	WSBandwidthExceededAction string
+
+	// Public mode: no dev endpoints, admin endpoints on a local listener,
+	// per-IP limits and no internal provenance in responses
+	PublicMode                bool
+	AdminListenSpec           string  // where admin endpoints and /metrics listen in public mode
+	PublicRateLimit           float64 // requests per second per client IP
+	PublicRateBurst           int
+	PublicMaxConnectionsPerIP int // concurrent WebSocket connections per client IP
 
 	// Validator Fetcher Configuration
-	ValidatorRefreshInterval      int // seconds
+	ValidatorRefreshInterval      int     // seconds
+	RefreshJitter                 float64 // fraction of each refresh interval
+	RefreshSplay                  bool
+	InstanceID                    string
 	ValidatorListSites            []string
+	ValidatorListPublisherKeys    [][]string // accepted publisher master key rotation chains, oldest first
+	publisherKeysErr              error
+	ListExpiryWarningDays         int               // alert on validator lists expiring within this many days, 0 only on expired ones
+	UNLPresets                    map[string]string // preset name -> validator list site attributed by ?unl=
+	unlPresetsErr                 error
 	SecondaryValidatorRegistryURL string
+	DataDir                       string
 	ValidatorMetadataCachePath    string
 	NetworkHealthJSONRPCURLs      []string
 	NetworkHealthRetries          int
+	ServerStatusPollInterval      int // seconds
+	LedgerLagThreshold            int // seconds
+	WatchdogTxStallSeconds        int // 0 disables the transaction check
+	AnomalyWindowSeconds          int // 0 disables anomaly detection
+	AnomalyZThreshold             float64
+	SLOMinValidators              int     // 0 disables
+	SLOMaxValidators              int     // 0 disables
+	SLOMinCoveragePercent         float64 // 0 disables
+	ReportPeriod                  string
+	ReportWebhookURLs             []string
+	ReportOutputDir               string
+	DecentralizationHistoryPath   string // empty disables decentralization snapshots
+	DecentralizationInterval      int    // seconds between snapshots
+	DecentralizationRetentionDays int
+	CensusDir                     string // empty disables the daily validator census
+	CensusUploadURL               string // artifacts are also PUT here, {name} replaced by the file name
+	PeersAdminJSONRPCURL          string
+	IssuerAccounts                []string
+	IssuerGraphRefreshInterval    int // seconds
+	IssuerGraphTopHolders         int
 	GeoCachePath                  string
+	GeoLiteEnabled                bool // false resolves locations from the geolocation cache only
 	GeoLiteDBPath                 string
 	GeoLiteDownloadURL            string
 	GeoLiteAutoDownload           bool
+	GeoLiteRefreshInterval        int // seconds, 0 disables
+	GeoLiteInitRetryInterval      int // seconds, 0 fails startup when the DB cannot be opened
+	GeoConfirmDBPath              string
+	GeoLiteASNDBPath              string
+	GeoConfirmCachePath           string
+	GeoProviders                  []models.GeoProvider // paid lookups for IPs GeoLite cannot place
+	geoProvidersErr               error
+	LocationLockTTLDays           int // 0 keeps locked locations indefinitely
+	PeerProbeInterval             int // seconds between peer port probes of a validator domain, 0 disables
 
 	// Transaction Configuration
 	MinPaymentDrops       int64
 	TransactionBufferSize int
 	GeoEnrichmentQSize    int
 	GeoEnrichmentWorkers  int
+	GeoEnrichmentSharding bool // route transactions to workers by source account
 	MaxGeoCandidates      int
+	AllowedTxResults      []string
+	FilterPreviewWindow   int // seconds of unfiltered traffic kept for /admin/filters/preview, 0 disables
+	TxProcessorCommand    string
+	TxProcessorTimeoutMS  int
+	WatchlistPath         string
+	LabelsPath            string // empty disables account labels
+	EnrichmentRules       []models.EnrichmentRule
+	enrichmentRulesErr    error
 	BroadcastBufferSize   int
 	WSClientBufferSize    int
 
 	// Logging Configuration
-	LogLevel string
+	LogLevel             string
+	DebugCaptureDir      string // empty disables capture of unparseable payloads
+	DebugCaptureMaxFiles int
+	DebugCaptureInterval int // seconds between captures per source
 }
 
 // NewConfig creates a new config from environment variables or defaults
@@ -56,37 +195,476 @@ func NewConfig() *Config {
 	publicJSONRPCURL := getEnv("PUBLIC_XRPL_JSON_RPC_URL", "https://xrplcluster.com")
 	publicWebSocketURL := getEnv("PUBLIC_XRPL_WEBSOCKET_URL", "wss://xrplcluster.com")
 	networkHealthJSONRPCURLs := getEnv("NETWORK_HEALTH_JSON_RPC_URLS", publicJSONRPCURL+",https://s2.ripple.com:51234")
+	wsOriginPolicies, wsOriginPolicyErr := parseOriginPolicies(getEnv("WS_ORIGIN_POLICIES", ""))
+	views, viewsErr := parseViews(getEnv("VIEWS", ""))
+	apiKeys, apiKeysErr := parseAPIKeys(getEnv("API_KEYS", ""))
+	enrichmentRules, enrichmentRulesErr := parseEnrichmentRules(getEnv("ENRICHMENT_RULES", ""))
+	outboundBudgets, outboundBudgetsErr := parseOutboundBudgets(getEnv("OUTBOUND_BUDGETS", ""))
+	publisherKeys, publisherKeysErr := parsePublisherKeyChains(getEnv("VALIDATOR_LIST_PUBLISHER_KEYS", ""))
+	geoProviders, geoProvidersErr := parseGeoProviders(getEnv("GEO_PROVIDERS", ""))
+	explorers, explorersErr := parseExplorers(getEnv("EXPLORERS", ""), getEnv("EXPLORER_TEMPLATES", ""))
+	unlPresets, unlPresetsErr := parseUNLPresets(getEnv("UNL_PRESETS", "dunl=https://vl.ripple.com,xrplf=https://unl.xrplf.org"))
+	dataDir := normalizePath(getEnv("DATA_DIR", ""))
+	if dataDir == "" {
+		dataDir = defaultDataDir()
+	}
 	cfg := &Config{
 		PublicXRPLJSONRPCURL:          publicJSONRPCURL,
 		PublicXRPLWebSocketURL:        publicWebSocketURL,
 		TransactionJSONRPCURL:         getEnv("TRANSACTION_JSON_RPC_URL", publicJSONRPCURL),
 		TransactionWebSocketURL:       getEnv("TRANSACTION_WEBSOCKET_URL", publicWebSocketURL),
+		TransactionStreams:            splitCSVPreserveOrder(strings.ToLower(getEnv("TRANSACTION_STREAMS", xrpl.StreamTransactions))),
+		TransactionPreview:            getEnvBool("TRANSACTION_PREVIEW", false),
+		XRPLDNSRefreshInterval:        getEnvInt("XRPL_DNS_REFRESH_INTERVAL", 60),
+		XRPLMessageBufferSize:         getEnvInt("XRPL_MESSAGE_BUFFER_SIZE", 4096),
+		XRPLDecodeWorkers:             getEnvInt("XRPL_DECODE_WORKERS", 2),
+		XRPLMaxMessageBytes:           getEnvInt("XRPL_MAX_MESSAGE_BYTES", 1<<20),
+		XRPLMaxMessageDepth:           getEnvInt("XRPL_MAX_MESSAGE_DEPTH", 64),
+		ReconnectBackoffInitial:       getEnvInt("RECONNECT_BACKOFF_INITIAL", 1),
+		ReconnectBackoffMax:           getEnvInt("RECONNECT_BACKOFF_MAX", 60),
+		ReconnectBackoffJitter:        getEnvFloat("RECONNECT_BACKOFF_JITTER", 0.2),
+		ReconnectCircuitThreshold:     getEnvInt("RECONNECT_CIRCUIT_THRESHOLD", 10),
+		ReconnectCircuitOpen:          getEnvInt("RECONNECT_CIRCUIT_OPEN", 300),
+		OutboundBudgets:               outboundBudgets,
+		outboundBudgetsErr:            outboundBudgetsErr,
 		Network:                       strings.ToLower(getEnv("XRPL_NETWORK", "mainnet")),
+		Role:                          strings.ToLower(strings.TrimSpace(getEnv("ROLE", "all"))),
+		ReplicaUpstreamURL:            strings.TrimSpace(getEnv("REPLICA_UPSTREAM_URL", "")),
+		ReplicaOrigin:                 strings.TrimSpace(getEnv("REPLICA_ORIGIN", "")),
+		ReplicaAPIKey:                 strings.TrimSpace(getEnv("REPLICA_API_KEY", "")),
 		ListenPort:                    getEnvInt("LISTEN_PORT", 8080),
 		ListenAddr:                    getEnv("LISTEN_ADDR", "0.0.0.0"),
+		ListenSpecs:                   splitCSVPreserveOrder(getEnv("LISTEN_SPECS", "")),
 		CORSAllowedOrigins:            splitCSV(corsOrigins),
+		CORSMaxAge:                    getEnvInt("CORS_MAX_AGE", 600),
+		TrustedProxies:                splitCSVPreserveOrder(getEnv("TRUSTED_PROXIES", "")),
+		ResponseCacheTTL:              getEnvInt("RESPONSE_CACHE_TTL", 5),
+		LoadUpstreamLagTarget:         getEnvInt("LOAD_UPSTREAM_LAG_TARGET", 10),
+		WSOriginPolicies:              wsOriginPolicies,
+		wsOriginPolicyErr:             wsOriginPolicyErr,
+		Views:                         views,
+		viewsErr:                      viewsErr,
+		APIKeys:                       apiKeys,
+		apiKeysErr:                    apiKeysErr,
+		PreferencesPath:               normalizePath(getEnv("PREFERENCES_PATH", filepath.Join(dataDir, "client-preferences.json"))),
+		AdminToken:                    strings.TrimSpace(getEnv("ADMIN_TOKEN", "")),
+		PrivacyMode:                   getEnvBool("PRIVACY_MODE", false),
+		CoordinatePrecision:           getEnvInt("COORDINATE_PRECISION", 4),
+		DisplayWeightMode:             strings.ToLower(getEnv("DISPLAY_WEIGHT_MODE", "xrp")),
+		DisplayWeightFiatRate:         getEnvFloat("DISPLAY_WEIGHT_FIAT_RATE", 0),
+		Explorers:                     explorers,
+		explorersErr:                  explorersErr,
+		DevMode:                       getEnvBool("DEV_MODE", false),
+		ExportSigningKey:              strings.TrimSpace(getEnv("EXPORT_SIGNING_KEY", "")),
+		WSClientBandwidthLimit:        getEnvInt("WS_CLIENT_BANDWIDTH_LIMIT", 0),
+		WSBandwidthExceededAction:     strings.ToLower(getEnv("WS_BANDWIDTH_EXCEEDED_ACTION", "throttle")),
+		PublicMode:                    getEnvBool("PUBLIC_MODE", false),
+		AdminListenSpec:               strings.TrimSpace(getEnv("ADMIN_LISTEN_SPEC", "127.0.0.1:8081")),
+		PublicRateLimit:               getEnvFloat("PUBLIC_RATE_LIMIT", 5),
+		PublicRateBurst:               getEnvInt("PUBLIC_RATE_BURST", 20),
+		PublicMaxConnectionsPerIP:     getEnvInt("PUBLIC_MAX_CONNECTIONS_PER_IP", 4),
 		ValidatorRefreshInterval:      getEnvInt("VALIDATOR_REFRESH_INTERVAL", 300), // 5 minutes
+		RefreshJitter:                 getEnvFloat("REFRESH_JITTER", 0.1),
+		RefreshSplay:                  getEnvBool("REFRESH_SPLAY", false),
+		InstanceID:                    strings.TrimSpace(getEnv("INSTANCE_ID", "")),
 		ValidatorListSites:            splitCSV(validatorListSites),
+		ValidatorListPublisherKeys:    publisherKeys,
+		publisherKeysErr:              publisherKeysErr,
+		ListExpiryWarningDays:         getEnvInt("VALIDATOR_LIST_EXPIRY_WARNING_DAYS", 7),
+		UNLPresets:                    unlPresets,
+		unlPresetsErr:                 unlPresetsErr,
 		SecondaryValidatorRegistryURL: getEnv("SECONDARY_VALIDATOR_REGISTRY_URL", "https://api.xrpscan.com/api/v1/validatorregistry"),
-		ValidatorMetadataCachePath:    getEnv("VALIDATOR_METADATA_CACHE_PATH", "data/validator-metadata-cache.json"),
+		DataDir:                       dataDir,
+		ValidatorMetadataCachePath:    normalizePath(getEnv("VALIDATOR_METADATA_CACHE_PATH", filepath.Join(dataDir, "validator-metadata-cache.json"))),
 		NetworkHealthJSONRPCURLs:      splitCSVPreserveOrder(networkHealthJSONRPCURLs),
 		NetworkHealthRetries:          getEnvInt("NETWORK_HEALTH_RETRIES", 2),
-		GeoCachePath:                  getEnv("GEO_CACHE_PATH", "data/geolocation-cache.json"),
-		GeoLiteDBPath:                 getEnv("GEOLITE_DB_PATH", "data/GeoLite2-City.mmdb"),
+		ServerStatusPollInterval:      getEnvInt("SERVER_STATUS_POLL_INTERVAL", 30),
+		LedgerLagThreshold:            getEnvInt("LEDGER_LAG_THRESHOLD", 10),
+		WatchdogTxStallSeconds:        getEnvInt("WATCHDOG_TX_STALL_SECONDS", 120),
+		AnomalyWindowSeconds:          getEnvInt("ANOMALY_WINDOW_SECONDS", 60),
+		AnomalyZThreshold:             getEnvFloat("ANOMALY_Z_THRESHOLD", 3),
+		SLOMinValidators:              getEnvInt("SLO_MIN_VALIDATORS", 0),
+		SLOMaxValidators:              getEnvInt("SLO_MAX_VALIDATORS", 0),
+		SLOMinCoveragePercent:         getEnvFloat("SLO_MIN_COVERAGE_PERCENT", 0),
+		ReportPeriod:                  strings.ToLower(strings.TrimSpace(getEnv("REPORT_PERIOD", ""))),
+		ReportWebhookURLs:             splitCSVPreserveOrder(getEnv("REPORT_WEBHOOK_URLS", "")),
+		ReportOutputDir:               normalizePath(getEnv("REPORT_OUTPUT_DIR", "")),
+		DecentralizationHistoryPath:   normalizePath(getEnv("DECENTRALIZATION_HISTORY_PATH", filepath.Join(dataDir, "decentralization-history.json"))),
+		CensusDir:                     normalizePath(getEnv("CENSUS_DIR", "")),
+		CensusUploadURL:               strings.TrimSpace(getEnv("CENSUS_UPLOAD_URL", "")),
+		DecentralizationInterval:      getEnvInt("DECENTRALIZATION_SNAPSHOT_INTERVAL", 86400),
+		DecentralizationRetentionDays: getEnvInt("DECENTRALIZATION_RETENTION_DAYS", 730),
+		PeersAdminJSONRPCURL:          strings.TrimSpace(getEnv("PEERS_ADMIN_JSON_RPC_URL", "")),
+		IssuerAccounts:                splitCSVPreserveOrder(getEnv("ISSUER_ACCOUNTS", "")),
+		IssuerGraphRefreshInterval:    getEnvInt("ISSUER_GRAPH_REFRESH_INTERVAL", 900), // 15 minutes
+		IssuerGraphTopHolders:         getEnvInt("ISSUER_GRAPH_TOP_HOLDERS", 50),
+		GeoCachePath:                  normalizePath(getEnv("GEO_CACHE_PATH", filepath.Join(dataDir, "geolocation-cache.json"))),
+		GeoLiteEnabled:                getEnvBool("GEOLITE_ENABLED", true),
+		GeoLiteDBPath:                 normalizePath(getEnv("GEOLITE_DB_PATH", filepath.Join(dataDir, "GeoLite2-City.mmdb"))),
 		GeoLiteDownloadURL:            getEnv("GEOLITE_DOWNLOAD_URL", "https://github.com/P3TERX/GeoLite.mmdb/raw/download/GeoLite2-City.mmdb"),
 		GeoLiteAutoDownload:           getEnvBool("GEOLITE_AUTO_DOWNLOAD", true),
+		GeoLiteRefreshInterval:        getEnvInt("GEOLITE_REFRESH_INTERVAL", 0),
+		GeoLiteInitRetryInterval:      getEnvInt("GEOLITE_INIT_RETRY_INTERVAL", 60),
+		GeoConfirmDBPath:              normalizePath(getEnv("GEO_CONFIRM_DB_PATH", "")),
+		GeoLiteASNDBPath:              normalizePath(getEnv("GEOLITE_ASN_DB_PATH", "")),
+		GeoConfirmCachePath:           normalizePath(getEnv("GEO_CONFIRM_CACHE_PATH", filepath.Join(dataDir, "geolocation-confirm-cache.json"))),
+		GeoProviders:                  geoProviders,
+		geoProvidersErr:               geoProvidersErr,
+		LocationLockTTLDays:           getEnvInt("LOCATION_LOCK_TTL_DAYS", 30),
+		PeerProbeInterval:             getEnvInt("PEER_PROBE_INTERVAL", 0),
 		MinPaymentDrops:               getEnvInt64("MIN_PAYMENT_DROPS", 1000000), // 1 XRP
 		TransactionBufferSize:         getEnvInt("TRANSACTION_BUFFER_SIZE", 2048),
 		GeoEnrichmentQSize:            getEnvInt("GEO_ENRICHMENT_QUEUE_SIZE", 2048),
-		GeoEnrichmentWorkers:          getEnvInt("GEO_ENRICHMENT_WORKERS", 8),
+		GeoEnrichmentWorkers:          getEnvInt("GEO_ENRICHMENT_WORKERS", 16),
+		GeoEnrichmentSharding:         getEnvBool("GEO_ENRICHMENT_SHARDING", false),
 		MaxGeoCandidates:              getEnvInt("MAX_GEO_CANDIDATES", 6),
+		AllowedTxResults:              splitCSVPreserveOrder(getEnv("ALLOWED_TX_RESULTS", "tesSUCCESS")),
+		FilterPreviewWindow:           getEnvInt("FILTER_PREVIEW_WINDOW", 900),
+		TxProcessorCommand:            strings.TrimSpace(getEnv("TX_PROCESSOR_COMMAND", "")),
+		TxProcessorTimeoutMS:          getEnvInt("TX_PROCESSOR_TIMEOUT_MS", 200),
+		WatchlistPath:                 normalizePath(getEnv("WATCHLIST_PATH", "")),
+		LabelsPath:                    normalizePath(getEnv("LABELS_PATH", filepath.Join(dataDir, "account-labels.json"))),
+		EnrichmentRules:               enrichmentRules,
+		enrichmentRulesErr:            enrichmentRulesErr,
 		BroadcastBufferSize:           getEnvInt("BROADCAST_BUFFER_SIZE", 2048),
 		WSClientBufferSize:            getEnvInt("WS_CLIENT_BUFFER_SIZE", 512),
 		LogLevel:                      getEnv("LOG_LEVEL", "info"),
+		DebugCaptureDir:               normalizePath(getEnv("DEBUG_CAPTURE_DIR", "")),
+		DebugCaptureMaxFiles:          getEnvInt("DEBUG_CAPTURE_MAX_FILES", 100),
+		DebugCaptureInterval:          getEnvInt("DEBUG_CAPTURE_INTERVAL", 60),
 	}
 	return cfg
 }
 
+// ExportKey returns the Ed25519 key derived from EXPORT_SIGNING_KEY, or nil
+// when it is unset. The key must have passed Validate.
+func (c *Config) ExportKey() ed25519.PrivateKey {
+	seed, err := hex.DecodeString(c.ExportSigningKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil
+	}
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+// ReconnectPolicy returns the backoff policy for stream reconnects.
+func (c *Config) ReconnectPolicy() xrpl.BackoffPolicy {
+	policy := xrpl.BackoffPolicy{
+		Initial:            time.Duration(c.ReconnectBackoffInitial) * time.Second,
+		Max:                time.Duration(c.ReconnectBackoffMax) * time.Second,
+		Jitter:             c.ReconnectBackoffJitter,
+		CircuitThreshold:   c.ReconnectCircuitThreshold,
+		CircuitOpenTimeout: time.Duration(c.ReconnectCircuitOpen) * time.Second,
+	}
+	// Zero policy fields take the defaults; here zero disables.
+	if policy.Jitter == 0 {
+		policy.Jitter = -1
+	}
+	if policy.CircuitThreshold == 0 {
+		policy.CircuitThreshold = -1
+	}
+	return policy
+}
+
+// parseOriginPolicies decodes WS_ORIGIN_POLICIES, a JSON object keyed by
+// origin, e.g. {"https://embed.example":{"max_connections":50,"channels":["transactions"],"max_messages_per_second":5}}.
+func parseOriginPolicies(raw string) (map[string]models.OriginPolicy, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var policies map[string]models.OriginPolicy
+	if err := json.Unmarshal([]byte(raw), &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// parseViews decodes VIEWS, a JSON object keyed by view name, e.g.
+// {"acme":{"allowed_origins":["https://acme.example"],"min_payment_drops":100000000000,"countries":["US","CA"]}}.
+func parseViews(raw string) (map[string]models.View, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var views map[string]models.View
+	if err := json.Unmarshal([]byte(raw), &views); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// parseOutboundBudgets decodes OUTBOUND_BUDGETS, a JSON object keyed by host
+// name or "*" for any other host, e.g.
+// {"xrplcluster.com":{"requests_per_second":10,"burst":20},"*":{"requests_per_second":5}}.
+func parseOutboundBudgets(raw string) (map[string]models.OutboundBudget, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var budgets map[string]models.OutboundBudget
+	if err := json.Unmarshal([]byte(raw), &budgets); err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// parseExplorers resolves EXPLORERS, the comma-separated explorers to link
+// to in order, against the presets and EXPLORER_TEMPLATES, a JSON object of
+// custom templates keyed by name, e.g.
+// {"testnet":{"transaction":"https://testnet.xrpl.org/transactions/{hash}"}}.
+func parseExplorers(names, templates string) ([]models.Explorer, error) {
+	var custom map[string]models.Explorer
+	if strings.TrimSpace(templates) != "" {
+		if err := json.Unmarshal([]byte(templates), &custom); err != nil {
+			return nil, fmt.Errorf("EXPLORER_TEMPLATES: %w", err)
+		}
+	}
+	return explorer.Resolve(splitCSVPreserveOrder(names), custom)
+}
+
+// parseGeoProviders decodes GEO_PROVIDERS, a JSON array of paid
+// geolocation providers tried in order, e.g.
+// [{"name":"ipinfo","token_file":"/run/secrets/ipinfo","monthly_quota":50000}].
+func parseGeoProviders(raw string) ([]models.GeoProvider, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var providers []models.GeoProvider
+	if err := json.Unmarshal([]byte(raw), &providers); err != nil {
+		return nil, err
+	}
+	for i := range providers {
+		providers[i].Name = strings.ToLower(strings.TrimSpace(providers[i].Name))
+		providers[i].TokenFile = normalizePath(providers[i].TokenFile)
+		providers[i].LicenseKeyFile = normalizePath(providers[i].LicenseKeyFile)
+	}
+	return providers, nil
+}
+
+// validateGeoProvider checks that p names a known provider and carries the
+// credentials it needs.
+func validateGeoProvider(p models.GeoProvider) error {
+	switch p.Name {
+	case "ipinfo":
+		if p.Token == "" && p.TokenFile == "" {
+			return fmt.Errorf("geolocation provider ipinfo requires token or token_file")
+		}
+	case "maxmind":
+		if p.AccountID == "" || (p.LicenseKey == "" && p.LicenseKeyFile == "") {
+			return fmt.Errorf("geolocation provider maxmind requires account_id and license_key or license_key_file")
+		}
+	default:
+		return fmt.Errorf("unknown geolocation provider %q (expected ipinfo or maxmind)", p.Name)
+	}
+	if p.RequestsPerSecond < 0 {
+		return fmt.Errorf("geolocation provider %s has a negative requests_per_second", p.Name)
+	}
+	if p.MonthlyQuota < 0 {
+		return fmt.Errorf("geolocation provider %s has a negative monthly_quota", p.Name)
+	}
+	if p.URL != "" {
+		parsed, err := url.Parse(p.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("geolocation provider %s URL must be an http(s) URL: %q", p.Name, p.URL)
+		}
+	}
+	return nil
+}
+
+// parseEnrichmentRules decodes ENRICHMENT_RULES, a JSON array of rules, e.g.
+// [{"name":"exchange_flow","when":"tags.source_label != \"\" && tags.dest_label != \"\"","set":{"category":"exchange_flow"}}].
+func parseEnrichmentRules(raw string) ([]models.EnrichmentRule, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var rules []models.EnrichmentRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// parseAPIKeys decodes API_KEYS, a comma-separated list of name:key pairs,
+// into a map from key to name.
+func parseAPIKeys(raw string) (map[string]string, error) {
+	entries := splitCSVPreserveOrder(raw)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	keys := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name, key, ok := strings.Cut(entry, ":")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !ok || name == "" || key == "" {
+			return nil, fmt.Errorf("entry %q is not name:key", entry)
+		}
+		if _, exists := keys[key]; exists {
+			return nil, fmt.Errorf("duplicate key for %s", name)
+		}
+		keys[key] = name
+	}
+	return keys, nil
+}
+
+// UNLPresetSites returns the distinct validator list sites of the UNL
+// presets, sorted.
+func (c *Config) UNLPresetSites() []string {
+	seen := make(map[string]struct{}, len(c.UNLPresets))
+	sites := make([]string, 0, len(c.UNLPresets))
+	for _, site := range c.UNLPresets {
+		if _, ok := seen[site]; ok {
+			continue
+		}
+		seen[site] = struct{}{}
+		sites = append(sites, site)
+	}
+	sort.Strings(sites)
+	return sites
+}
+
+// parseUNLPresets decodes UNL_PRESETS, a comma-separated list of name=url
+// pairs, into a map from preset name to validator list site.
+func parseUNLPresets(raw string) (map[string]string, error) {
+	entries := splitCSVPreserveOrder(raw)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	presets := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name, site, ok := strings.Cut(entry, "=")
+		name, site = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(site)
+		if !ok || name == "" || site == "" {
+			return nil, fmt.Errorf("entry %q is not name=url", entry)
+		}
+		if !viewNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("preset name %q must be lowercase letters, digits, '-' or '_'", name)
+		}
+		if parsed, err := url.Parse(site); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("preset %s has an invalid URL %q", name, site)
+		}
+		if _, exists := presets[name]; exists {
+			return nil, fmt.Errorf("duplicate preset %s", name)
+		}
+		presets[name] = site
+	}
+	return presets, nil
+}
+
+// parsePublisherKeyChains decodes VALIDATOR_LIST_PUBLISHER_KEYS: comma
+// separated rotation chains of hex publisher master keys, each listing a
+// publisher's keys oldest first joined by ">", e.g. "ED2677...>ED45D1...". A
+// single key is a chain of one.
+func parsePublisherKeyChains(raw string) ([][]string, error) {
+	entries := splitCSVPreserveOrder(raw)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	chains := make([][]string, 0, len(entries))
+	seen := make(map[string]struct{})
+	for _, entry := range entries {
+		var chain []string
+		for _, key := range strings.Split(entry, ">") {
+			key = strings.ToUpper(strings.TrimSpace(key))
+			if decoded, err := hex.DecodeString(key); err != nil || len(decoded) != 33 {
+				return nil, fmt.Errorf("%q is not a 33 byte hex public key", key)
+			}
+			if _, exists := seen[key]; exists {
+				return nil, fmt.Errorf("key %s appears more than once", key)
+			}
+			seen[key] = struct{}{}
+			chain = append(chain, key)
+		}
+		chains = append(chains, chain)
+	}
+	return chains, nil
+}
+
+// validatePublicMode checks the PUBLIC_MODE settings. Dev mode is refused
+// outright, and the admin listener must not be reachable from the network.
+func validatePublicMode(c *Config) error {
+	if c.DevMode {
+		return fmt.Errorf("DEV_MODE cannot be enabled in public mode")
+	}
+	if _, _, err := server.ParseListenSpec(c.AdminListenSpec); err != nil {
+		return fmt.Errorf("invalid ADMIN_LISTEN_SPEC: %w", err)
+	}
+	if !isLocalListenSpec(c.AdminListenSpec) {
+		return fmt.Errorf("admin listen spec must be a loopback address or unix socket in public mode: %s", c.AdminListenSpec)
+	}
+	for _, spec := range c.ListenSpecs {
+		if spec == c.AdminListenSpec {
+			return fmt.Errorf("admin listen spec must differ from the public listen specs: %s", spec)
+		}
+	}
+	if c.PublicRateLimit <= 0 {
+		return fmt.Errorf("public rate limit must be positive: %g", c.PublicRateLimit)
+	}
+	if c.PublicRateBurst <= 0 {
+		return fmt.Errorf("public rate burst must be positive: %d", c.PublicRateBurst)
+	}
+	if c.PublicMaxConnectionsPerIP <= 0 {
+		return fmt.Errorf("public max connections per IP must be positive: %d", c.PublicMaxConnectionsPerIP)
+	}
+	return nil
+}
+
+// isLocalListenSpec reports whether a valid listen spec is a unix socket or
+// a loopback host.
+func isLocalListenSpec(spec string) bool {
+	network, address, err := server.ParseListenSpec(spec)
+	if err != nil {
+		return false
+	}
+	if network == "unix" {
+		return true
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// validateTransactionStreams checks TRANSACTION_STREAMS: known streams with
+// exactly one of transactions and transactions_proposed, which already
+// carries validated transactions and would deliver them twice.
+func validateTransactionStreams(streams []string) error {
+	transactionStreams := 0
+	for _, stream := range streams {
+		if !xrpl.IsStream(stream) {
+			return fmt.Errorf("unknown transaction stream: %s", stream)
+		}
+		if stream == xrpl.StreamTransactions || stream == xrpl.StreamTransactionsProposed {
+			transactionStreams++
+		}
+	}
+	if transactionStreams != 1 {
+		return fmt.Errorf("transaction streams must include exactly one of transactions and transactions_proposed: %v", streams)
+	}
+	return nil
+}
+
+// isTxResultPattern reports whether pattern is an engine result code or a
+// "prefix*" pattern starting with a result class such as tec.
+func isTxResultPattern(pattern string) bool {
+	for _, class := range []string{"tes", "tec", "tef", "tel", "tem", "ter"} {
+		if strings.HasPrefix(pattern, class) {
+			return !strings.Contains(strings.TrimSuffix(pattern, "*"), "*")
+		}
+	}
+	return pattern == "*"
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
 func getEnv(key, defaultVal string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -112,6 +690,15 @@ func getEnvInt64(key string, defaultVal int64) int64 {
 	return defaultVal
 }
 
+func getEnvFloat(key string, defaultVal float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		if floatVal, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultVal
+}
+
 func getEnvBool(key string, defaultVal bool) bool {
 	if value, exists := os.LookupEnv(key); exists {
 		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
@@ -161,6 +748,11 @@ func (c *Config) Validate() error {
 	if c.ListenAddr == "" {
 		return fmt.Errorf("listen address cannot be empty")
 	}
+	for _, spec := range c.ListenSpecs {
+		if _, _, err := server.ParseListenSpec(spec); err != nil {
+			return err
+		}
+	}
 	if c.PublicXRPLJSONRPCURL == "" {
 		return fmt.Errorf("public XRPL JSON RPC URL cannot be empty")
 	}
@@ -173,12 +765,86 @@ func (c *Config) Validate() error {
 	if c.TransactionWebSocketURL == "" {
 		return fmt.Errorf("transaction WebSocket URL cannot be empty")
 	}
+	if c.XRPLDNSRefreshInterval < 0 {
+		return fmt.Errorf("XRPL DNS refresh interval cannot be negative: %d", c.XRPLDNSRefreshInterval)
+	}
+	if c.XRPLMessageBufferSize <= 0 {
+		return fmt.Errorf("XRPL message buffer size must be positive: %d", c.XRPLMessageBufferSize)
+	}
+	if c.XRPLDecodeWorkers <= 0 {
+		return fmt.Errorf("XRPL decode workers must be positive: %d", c.XRPLDecodeWorkers)
+	}
+	if c.XRPLMaxMessageBytes < 4096 {
+		return fmt.Errorf("XRPL max message bytes must be at least 4096: %d", c.XRPLMaxMessageBytes)
+	}
+	if c.XRPLMaxMessageDepth < 8 {
+		return fmt.Errorf("XRPL max message depth must be at least 8: %d", c.XRPLMaxMessageDepth)
+	}
+	if c.ReconnectBackoffInitial <= 0 {
+		return fmt.Errorf("reconnect backoff initial must be positive: %d", c.ReconnectBackoffInitial)
+	}
+	if c.ReconnectBackoffMax < c.ReconnectBackoffInitial {
+		return fmt.Errorf("reconnect backoff max must be at least the initial backoff: %d", c.ReconnectBackoffMax)
+	}
+	if c.ReconnectBackoffJitter < 0 || c.ReconnectBackoffJitter > 1 {
+		return fmt.Errorf("reconnect backoff jitter must be between 0 and 1: %g", c.ReconnectBackoffJitter)
+	}
+	if c.ReconnectCircuitThreshold < 0 {
+		return fmt.Errorf("reconnect circuit threshold must be non-negative: %d", c.ReconnectCircuitThreshold)
+	}
+	if c.ReconnectCircuitOpen <= 0 {
+		return fmt.Errorf("reconnect circuit open time must be positive: %d", c.ReconnectCircuitOpen)
+	}
 	if c.Network == "" {
 		return fmt.Errorf("network cannot be empty")
 	}
+	if c.publisherKeysErr != nil {
+		return fmt.Errorf("invalid VALIDATOR_LIST_PUBLISHER_KEYS: %w", c.publisherKeysErr)
+	}
+	if c.outboundBudgetsErr != nil {
+		return fmt.Errorf("invalid OUTBOUND_BUDGETS: %w", c.outboundBudgetsErr)
+	}
+	for host, budget := range c.OutboundBudgets {
+		if host == "" || strings.ContainsAny(host, ":/") {
+			return fmt.Errorf("outbound budget host %q must be a host name without scheme or port, or *", host)
+		}
+		if !(budget.RequestsPerSecond > 0) {
+			return fmt.Errorf("outbound budget for %s must have a positive requests_per_second", host)
+		}
+		if budget.Burst < 0 {
+			return fmt.Errorf("outbound budget for %s has a negative burst", host)
+		}
+	}
+	switch c.Role {
+	case "all":
+	case "ingest":
+		if c.ReplicaUpstreamURL != "" {
+			return fmt.Errorf("the ingest role subscribes to XRPL and cannot use a replica upstream URL")
+		}
+	case "serve":
+		if c.ReplicaUpstreamURL == "" {
+			return fmt.Errorf("the serve role requires a replica upstream URL pointing at an ingest process")
+		}
+	default:
+		return fmt.Errorf("role must be all, ingest or serve: %s", c.Role)
+	}
+	if c.ReplicaUpstreamURL != "" {
+		parsed, err := url.Parse(c.ReplicaUpstreamURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("replica upstream URL must be an http(s) URL: %s", c.ReplicaUpstreamURL)
+		}
+		if c.ReplicaOrigin == "" {
+			return fmt.Errorf("replica origin must be set when a replica upstream URL is configured")
+		}
+	}
 	if c.ValidatorRefreshInterval <= 0 {
 		return fmt.Errorf("validator refresh interval must be positive: %d", c.ValidatorRefreshInterval)
 	}
+	// Beyond half an interval, a late jittered refresh plus the splay could
+	// trip the watchdog's fetch stall check.
+	if c.RefreshJitter < 0 || c.RefreshJitter > 0.5 {
+		return fmt.Errorf("refresh jitter must be between 0 and 0.5: %g", c.RefreshJitter)
+	}
 	if len(c.ValidatorListSites) == 0 {
 		return fmt.Errorf("at least one validator list site must be specified")
 	}
@@ -194,14 +860,189 @@ func (c *Config) Validate() error {
 	if c.NetworkHealthRetries <= 0 {
 		return fmt.Errorf("network health retries must be positive: %d", c.NetworkHealthRetries)
 	}
+	if c.wsOriginPolicyErr != nil {
+		return fmt.Errorf("invalid WS_ORIGIN_POLICIES: %w", c.wsOriginPolicyErr)
+	}
+	for origin, policy := range c.WSOriginPolicies {
+		if origin != "*" && !containsString(c.CORSAllowedOrigins, origin) {
+			return fmt.Errorf("ws origin policy for %s is not in CORS_ALLOWED_ORIGINS", origin)
+		}
+		if policy.MaxConnections < 0 || policy.MaxMessagesPerSecond < 0 {
+			return fmt.Errorf("ws origin policy for %s has negative limits", origin)
+		}
+		for _, channel := range policy.Channels {
+			if !server.IsStreamChannel(channel) {
+				return fmt.Errorf("ws origin policy for %s has an unknown channel %q", origin, channel)
+			}
+		}
+	}
+	if c.viewsErr != nil {
+		return fmt.Errorf("invalid VIEWS: %w", c.viewsErr)
+	}
+	if c.explorersErr != nil {
+		return fmt.Errorf("invalid explorer links: %w", c.explorersErr)
+	}
+	for name, view := range c.Views {
+		if !viewNamePattern.MatchString(name) {
+			return fmt.Errorf("view name %q must be lowercase letters, digits, '-' or '_'", name)
+		}
+		if view.MinPaymentDrops < 0 {
+			return fmt.Errorf("view %s has a negative min_payment_drops", name)
+		}
+		for _, country := range view.Countries {
+			if len(country) != 2 || strings.ToUpper(country) != country {
+				return fmt.Errorf("view %s has an invalid country code %q", name, country)
+			}
+		}
+		for _, channel := range view.Channels {
+			if !server.IsStreamChannel(channel) {
+				return fmt.Errorf("view %s has an unknown channel %q", name, channel)
+			}
+		}
+	}
+	if c.ExportSigningKey != "" {
+		if seed, err := hex.DecodeString(c.ExportSigningKey); err != nil || len(seed) != ed25519.SeedSize {
+			return fmt.Errorf("export signing key must be a %d-byte hex Ed25519 seed", ed25519.SeedSize)
+		}
+	}
+	if c.unlPresetsErr != nil {
+		return fmt.Errorf("invalid UNL_PRESETS: %w", c.unlPresetsErr)
+	}
+	if c.apiKeysErr != nil {
+		return fmt.Errorf("invalid API_KEYS: %w", c.apiKeysErr)
+	}
+	if c.WSClientBandwidthLimit < 0 {
+		return fmt.Errorf("ws client bandwidth limit cannot be negative: %d", c.WSClientBandwidthLimit)
+	}
+	if c.WSBandwidthExceededAction != "throttle" && c.WSBandwidthExceededAction != "summary" {
+		return fmt.Errorf("ws bandwidth exceeded action must be throttle or summary: %s", c.WSBandwidthExceededAction)
+	}
+	if c.PublicMode {
+		if err := validatePublicMode(c); err != nil {
+			return err
+		}
+	}
+	if c.ResponseCacheTTL < 0 {
+		return fmt.Errorf("response cache TTL cannot be negative: %d", c.ResponseCacheTTL)
+	}
+	if c.ServerStatusPollInterval <= 0 {
+		return fmt.Errorf("server status poll interval must be positive: %d", c.ServerStatusPollInterval)
+	}
+	if c.LoadUpstreamLagTarget <= 0 {
+		return fmt.Errorf("load upstream lag target must be positive: %d", c.LoadUpstreamLagTarget)
+	}
+	if c.LedgerLagThreshold <= 0 {
+		return fmt.Errorf("ledger lag threshold must be positive: %d", c.LedgerLagThreshold)
+	}
+	if c.FilterPreviewWindow < 0 {
+		return fmt.Errorf("filter preview window must be non-negative: %d", c.FilterPreviewWindow)
+	}
+	if c.WatchdogTxStallSeconds < 0 {
+		return fmt.Errorf("watchdog transaction stall seconds must be non-negative: %d", c.WatchdogTxStallSeconds)
+	}
+	if c.AnomalyWindowSeconds < 0 {
+		return fmt.Errorf("anomaly window seconds must be non-negative: %d", c.AnomalyWindowSeconds)
+	}
+	if c.AnomalyWindowSeconds > 0 && !(c.AnomalyZThreshold > 0) {
+		return fmt.Errorf("anomaly z-score threshold must be positive: %g", c.AnomalyZThreshold)
+	}
+	if c.SLOMinValidators < 0 || c.SLOMaxValidators < 0 {
+		return fmt.Errorf("SLO validator bounds must be non-negative: %d, %d", c.SLOMinValidators, c.SLOMaxValidators)
+	}
+	if c.SLOMinValidators > 0 && c.SLOMaxValidators > 0 && c.SLOMaxValidators < c.SLOMinValidators {
+		return fmt.Errorf("SLO max validators must not be below the minimum: %d < %d", c.SLOMaxValidators, c.SLOMinValidators)
+	}
+	if !(c.SLOMinCoveragePercent >= 0 && c.SLOMinCoveragePercent <= 100) {
+		return fmt.Errorf("SLO min coverage percent must be between 0 and 100: %g", c.SLOMinCoveragePercent)
+	}
+	switch c.ReportPeriod {
+	case "", "daily", "weekly":
+	default:
+		return fmt.Errorf("report period must be daily or weekly: %s", c.ReportPeriod)
+	}
+	if c.ReportPeriod != "" && len(c.ReportWebhookURLs) == 0 && c.ReportOutputDir == "" {
+		return fmt.Errorf("report period requires a report webhook URL or output directory")
+	}
+	for _, webhookURL := range c.ReportWebhookURLs {
+		parsed, err := url.Parse(webhookURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("report webhook URL must be an http(s) URL: %s", webhookURL)
+		}
+	}
+	if c.DecentralizationHistoryPath != "" {
+		if c.DecentralizationInterval < 60 {
+			return fmt.Errorf("decentralization snapshot interval must be at least 60 seconds: %d", c.DecentralizationInterval)
+		}
+		if c.DecentralizationRetentionDays <= 0 {
+			return fmt.Errorf("decentralization retention days must be positive: %d", c.DecentralizationRetentionDays)
+		}
+	}
+	if c.CensusDir != "" && c.ExportSigningKey == "" {
+		return fmt.Errorf("validator census requires EXPORT_SIGNING_KEY to sign it")
+	}
+	if c.CensusUploadURL != "" {
+		if c.CensusDir == "" {
+			return fmt.Errorf("census upload URL requires CENSUS_DIR")
+		}
+		parsed, err := url.Parse(strings.ReplaceAll(c.CensusUploadURL, census.UploadPlaceholder, "census.json"))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || !strings.Contains(c.CensusUploadURL, census.UploadPlaceholder) {
+			return fmt.Errorf("census upload URL must be an http(s) URL containing {name}: %s", c.CensusUploadURL)
+		}
+	}
+	for _, issuer := range c.IssuerAccounts {
+		if !strings.HasPrefix(issuer, "r") || len(issuer) < 25 || len(issuer) > 35 {
+			return fmt.Errorf("invalid issuer account: %s", issuer)
+		}
+	}
+	if c.IssuerGraphRefreshInterval <= 0 {
+		return fmt.Errorf("issuer graph refresh interval must be positive: %d", c.IssuerGraphRefreshInterval)
+	}
+	if c.IssuerGraphTopHolders <= 0 {
+		return fmt.Errorf("issuer graph top holders must be positive: %d", c.IssuerGraphTopHolders)
+	}
 	if strings.TrimSpace(c.GeoCachePath) == "" {
 		return fmt.Errorf("geo cache path cannot be empty")
 	}
-	if strings.TrimSpace(c.GeoLiteDBPath) == "" {
-		return fmt.Errorf("GeoLite DB path cannot be empty")
+	if c.GeoLiteEnabled {
+		if strings.TrimSpace(c.GeoLiteDBPath) == "" {
+			return fmt.Errorf("GeoLite DB path cannot be empty")
+		}
+		if c.GeoLiteAutoDownload && strings.TrimSpace(c.GeoLiteDownloadURL) == "" {
+			return fmt.Errorf("GeoLite download URL cannot be empty when auto-download is enabled")
+		}
+		if c.GeoLiteRefreshInterval < 0 {
+			return fmt.Errorf("GeoLite refresh interval cannot be negative: %d", c.GeoLiteRefreshInterval)
+		}
+		if c.GeoLiteRefreshInterval > 0 && strings.TrimSpace(c.GeoLiteDownloadURL) == "" {
+			return fmt.Errorf("GeoLite download URL cannot be empty when refresh is enabled")
+		}
+		if c.GeoLiteInitRetryInterval < 0 {
+			return fmt.Errorf("GeoLite init retry interval cannot be negative: %d", c.GeoLiteInitRetryInterval)
+		}
+	} else if c.GeoConfirmDBPath != "" || c.GeoLiteASNDBPath != "" {
+		return fmt.Errorf("GEO_CONFIRM_DB_PATH and GEOLITE_ASN_DB_PATH require GEOLITE_ENABLED")
+	}
+	if c.geoProvidersErr != nil {
+		return fmt.Errorf("invalid GEO_PROVIDERS: %w", c.geoProvidersErr)
+	}
+	seenProviders := make(map[string]bool, len(c.GeoProviders))
+	for _, p := range c.GeoProviders {
+		if err := validateGeoProvider(p); err != nil {
+			return err
+		}
+		if seenProviders[p.Name] {
+			return fmt.Errorf("geolocation provider %s is configured more than once", p.Name)
+		}
+		seenProviders[p.Name] = true
 	}
-	if c.GeoLiteAutoDownload && strings.TrimSpace(c.GeoLiteDownloadURL) == "" {
-		return fmt.Errorf("GeoLite download URL cannot be empty when auto-download is enabled")
+	if c.ListExpiryWarningDays < 0 {
+		return fmt.Errorf("validator list expiry warning days cannot be negative: %d", c.ListExpiryWarningDays)
+	}
+	if c.LocationLockTTLDays < 0 {
+		return fmt.Errorf("location lock TTL days cannot be negative: %d", c.LocationLockTTLDays)
+	}
+	if c.PeerProbeInterval != 0 && c.PeerProbeInterval < minPeerProbeInterval {
+		return fmt.Errorf("peer probe interval must be 0 (disabled) or at least %d seconds: %d", minPeerProbeInterval, c.PeerProbeInterval)
 	}
 	if c.MinPaymentDrops <= 0 {
 		return fmt.Errorf("minimum payment drops must be positive: %d", c.MinPaymentDrops)
@@ -218,6 +1059,50 @@ func (c *Config) Validate() error {
 	if c.MaxGeoCandidates <= 0 {
 		return fmt.Errorf("max geo candidates must be positive: %d", c.MaxGeoCandidates)
 	}
+	if err := validateTransactionStreams(c.TransactionStreams); err != nil {
+		return err
+	}
+	if c.TransactionPreview && !slices.Contains(c.TransactionStreams, xrpl.StreamTransactionsProposed) {
+		return fmt.Errorf("TRANSACTION_PREVIEW requires %s in TRANSACTION_STREAMS", xrpl.StreamTransactionsProposed)
+	}
+	if len(c.AllowedTxResults) == 0 {
+		return fmt.Errorf("at least one allowed transaction result must be specified")
+	}
+	for _, result := range c.AllowedTxResults {
+		if !isTxResultPattern(result) {
+			return fmt.Errorf("invalid allowed transaction result: %s", result)
+		}
+	}
+	if c.enrichmentRulesErr != nil {
+		return fmt.Errorf("invalid ENRICHMENT_RULES: %w", c.enrichmentRulesErr)
+	}
+	if _, err := rules.NewEngine(c.EnrichmentRules, nil); err != nil {
+		return fmt.Errorf("invalid ENRICHMENT_RULES: %w", err)
+	}
+	if c.TxProcessorTimeoutMS <= 0 {
+		return fmt.Errorf("transaction processor timeout must be positive: %d", c.TxProcessorTimeoutMS)
+	}
+	if c.CoordinatePrecision < 0 || c.CoordinatePrecision > maxCoordinatePrecision {
+		return fmt.Errorf("coordinate precision must be between 0 and %d: %d", maxCoordinatePrecision, c.CoordinatePrecision)
+	}
+	switch c.DisplayWeightMode {
+	case "xrp", "log", "flat":
+	case "fiat":
+		if c.DisplayWeightFiatRate <= 0 {
+			return fmt.Errorf("display weight fiat rate must be positive in fiat mode: %g", c.DisplayWeightFiatRate)
+		}
+	default:
+		return fmt.Errorf("display weight mode must be xrp, log, fiat or flat: %s", c.DisplayWeightMode)
+	}
+	if c.DisplayWeightFiatRate < 0 {
+		return fmt.Errorf("display weight fiat rate cannot be negative: %g", c.DisplayWeightFiatRate)
+	}
+	if c.DebugCaptureDir != "" && c.DebugCaptureMaxFiles <= 0 {
+		return fmt.Errorf("debug capture max files must be positive: %d", c.DebugCaptureMaxFiles)
+	}
+	if c.DebugCaptureDir != "" && c.DebugCaptureInterval < 0 {
+		return fmt.Errorf("debug capture interval must be non-negative: %d", c.DebugCaptureInterval)
+	}
 	if c.BroadcastBufferSize <= 0 {
 		return fmt.Errorf("broadcast buffer size must be positive: %d", c.BroadcastBufferSize)
 	}
@@ -227,5 +1112,15 @@ func (c *Config) Validate() error {
 	if len(c.CORSAllowedOrigins) == 0 {
 		return fmt.Errorf("at least one CORS allowed origin must be specified")
 	}
+	if c.CORSMaxAge < 0 {
+		return fmt.Errorf("CORS max age cannot be negative: %d", c.CORSMaxAge)
+	}
+	for _, proxy := range c.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("invalid trusted proxy %q: must be an IP or CIDR", proxy)
+			}
+		}
+	}
 	return nil
 }
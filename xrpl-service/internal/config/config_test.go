@@ -2,7 +2,12 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
 )
 
 func TestNewConfig(t *testing.T) {
@@ -26,6 +31,12 @@ func TestNewConfig(t *testing.T) {
 	if cfg.TransactionWebSocketURL != "wss://xrplcluster.com" {
 		t.Errorf("Expected TransactionWebSocketURL 'wss://xrplcluster.com', got %s", cfg.TransactionWebSocketURL)
 	}
+	if len(cfg.TransactionStreams) != 1 || cfg.TransactionStreams[0] != "transactions" {
+		t.Errorf("Expected TransactionStreams [transactions], got %v", cfg.TransactionStreams)
+	}
+	if cfg.TransactionPreview {
+		t.Error("Expected TransactionPreview to default to false")
+	}
 	if cfg.Network != "mainnet" {
 		t.Errorf("Expected Network 'mainnet', got %s", cfg.Network)
 	}
@@ -41,12 +52,27 @@ func TestNewConfig(t *testing.T) {
 	if cfg.GeoEnrichmentQSize != 2048 {
 		t.Errorf("Expected GeoEnrichmentQSize 2048, got %d", cfg.GeoEnrichmentQSize)
 	}
-	if cfg.GeoEnrichmentWorkers != 8 {
-		t.Errorf("Expected GeoEnrichmentWorkers 8, got %d", cfg.GeoEnrichmentWorkers)
+	if cfg.GeoEnrichmentWorkers != 16 {
+		t.Errorf("Expected GeoEnrichmentWorkers 16, got %d", cfg.GeoEnrichmentWorkers)
+	}
+	if cfg.GeoEnrichmentSharding {
+		t.Error("Expected GeoEnrichmentSharding to be disabled by default")
+	}
+	if cfg.FilterPreviewWindow != 900 {
+		t.Errorf("Expected FilterPreviewWindow 900, got %d", cfg.FilterPreviewWindow)
 	}
 	if cfg.MaxGeoCandidates != 6 {
 		t.Errorf("Expected MaxGeoCandidates 6, got %d", cfg.MaxGeoCandidates)
 	}
+	if len(cfg.AllowedTxResults) != 1 || cfg.AllowedTxResults[0] != "tesSUCCESS" {
+		t.Errorf("Expected AllowedTxResults [tesSUCCESS], got %v", cfg.AllowedTxResults)
+	}
+	if cfg.WatchlistPath != "" {
+		t.Errorf("Expected no watchlist by default, got %s", cfg.WatchlistPath)
+	}
+	if cfg.TxProcessorCommand != "" || cfg.TxProcessorTimeoutMS != 200 {
+		t.Errorf("Expected no transaction processor with 200ms timeout, got %q %d", cfg.TxProcessorCommand, cfg.TxProcessorTimeoutMS)
+	}
 	if cfg.BroadcastBufferSize != 2048 {
 		t.Errorf("Expected BroadcastBufferSize 2048, got %d", cfg.BroadcastBufferSize)
 	}
@@ -77,21 +103,173 @@ func TestNewConfig(t *testing.T) {
 	if cfg.NetworkHealthRetries != 2 {
 		t.Errorf("Expected NetworkHealthRetries 2, got %d", cfg.NetworkHealthRetries)
 	}
-	if cfg.ValidatorMetadataCachePath != "data/validator-metadata-cache.json" {
+	if cfg.ServerStatusPollInterval != 30 {
+		t.Errorf("Expected ServerStatusPollInterval 30, got %d", cfg.ServerStatusPollInterval)
+	}
+	if len(cfg.ListenSpecs) != 0 {
+		t.Errorf("Expected no ListenSpecs by default, got %v", cfg.ListenSpecs)
+	}
+	if cfg.WSOriginPolicies != nil {
+		t.Errorf("Expected no WSOriginPolicies by default, got %+v", cfg.WSOriginPolicies)
+	}
+	if cfg.Views != nil {
+		t.Errorf("Expected no Views by default, got %+v", cfg.Views)
+	}
+	if len(cfg.Explorers) != 0 {
+		t.Errorf("Expected no Explorers by default, got %+v", cfg.Explorers)
+	}
+	if cfg.CORSMaxAge != 600 || len(cfg.TrustedProxies) != 0 {
+		t.Errorf("Expected CORS max age 600 and no trusted proxies by default, got %d and %v", cfg.CORSMaxAge, cfg.TrustedProxies)
+	}
+	if cfg.ExportSigningKey != "" || cfg.ExportKey() != nil {
+		t.Errorf("Expected no export signing key by default")
+	}
+	if cfg.OutboundBudgets != nil {
+		t.Errorf("Expected no OutboundBudgets by default, got %+v", cfg.OutboundBudgets)
+	}
+	if cfg.GeoProviders != nil {
+		t.Errorf("Expected no GeoProviders by default, got %+v", cfg.GeoProviders)
+	}
+	if cfg.ResponseCacheTTL != 5 {
+		t.Errorf("Expected ResponseCacheTTL 5, got %d", cfg.ResponseCacheTTL)
+	}
+	if cfg.LoadUpstreamLagTarget != 10 {
+		t.Errorf("Expected LoadUpstreamLagTarget 10, got %d", cfg.LoadUpstreamLagTarget)
+	}
+	if cfg.ValidatorListPublisherKeys != nil {
+		t.Errorf("Expected no validator list publisher keys by default, got %v", cfg.ValidatorListPublisherKeys)
+	}
+	if len(cfg.UNLPresets) != 2 || cfg.UNLPresets["dunl"] != "https://vl.ripple.com" || cfg.UNLPresets["xrplf"] != "https://unl.xrplf.org" {
+		t.Errorf("Unexpected default UNLPresets: %v", cfg.UNLPresets)
+	}
+	if cfg.APIKeys != nil || cfg.AdminToken != "" {
+		t.Errorf("Expected no API keys or admin token by default, got %v %q", cfg.APIKeys, cfg.AdminToken)
+	}
+	if cfg.XRPLDNSRefreshInterval != 60 {
+		t.Errorf("Expected XRPLDNSRefreshInterval 60, got %d", cfg.XRPLDNSRefreshInterval)
+	}
+	if cfg.XRPLMessageBufferSize != 4096 || cfg.XRPLDecodeWorkers != 2 {
+		t.Errorf("Expected XRPL message buffer 4096 with 2 decode workers, got %d and %d", cfg.XRPLMessageBufferSize, cfg.XRPLDecodeWorkers)
+	}
+	if cfg.XRPLMaxMessageBytes != 1<<20 || cfg.XRPLMaxMessageDepth != 64 {
+		t.Errorf("Expected XRPL message limits of 1 MiB and depth 64, got %d and %d", cfg.XRPLMaxMessageBytes, cfg.XRPLMaxMessageDepth)
+	}
+	if cfg.ReconnectBackoffInitial != 1 || cfg.ReconnectBackoffMax != 60 || cfg.ReconnectBackoffJitter != 0.2 ||
+		cfg.ReconnectCircuitThreshold != 10 || cfg.ReconnectCircuitOpen != 300 {
+		t.Errorf("Unexpected default reconnect backoff: %d %d %g %d %d", cfg.ReconnectBackoffInitial, cfg.ReconnectBackoffMax,
+			cfg.ReconnectBackoffJitter, cfg.ReconnectCircuitThreshold, cfg.ReconnectCircuitOpen)
+	}
+	if cfg.ReplicaUpstreamURL != "" {
+		t.Errorf("Expected replica mode to be disabled by default, got %s", cfg.ReplicaUpstreamURL)
+	}
+	if cfg.Role != "all" {
+		t.Errorf("Expected Role 'all', got %s", cfg.Role)
+	}
+	if cfg.WSClientBandwidthLimit != 0 {
+		t.Errorf("Expected WSClientBandwidthLimit 0, got %d", cfg.WSClientBandwidthLimit)
+	}
+	if cfg.PrivacyMode {
+		t.Errorf("Expected PrivacyMode false by default")
+	}
+	if cfg.CoordinatePrecision != 4 {
+		t.Errorf("Expected CoordinatePrecision 4, got %d", cfg.CoordinatePrecision)
+	}
+	if cfg.DisplayWeightMode != "xrp" || cfg.DisplayWeightFiatRate != 0 {
+		t.Errorf("Expected the xrp display weight mode without a fiat rate, got %s and %g", cfg.DisplayWeightMode, cfg.DisplayWeightFiatRate)
+	}
+	if cfg.DevMode {
+		t.Errorf("Expected DevMode false by default")
+	}
+	if cfg.PublicMode || cfg.AdminListenSpec != "127.0.0.1:8081" {
+		t.Errorf("Expected public mode off with admin on 127.0.0.1:8081 by default, got %v and %s", cfg.PublicMode, cfg.AdminListenSpec)
+	}
+	if cfg.PublicRateLimit != 5 || cfg.PublicRateBurst != 20 || cfg.PublicMaxConnectionsPerIP != 4 {
+		t.Errorf("Expected public limits 5/s, burst 20 and 4 connections, got %g, %d and %d", cfg.PublicRateLimit, cfg.PublicRateBurst, cfg.PublicMaxConnectionsPerIP)
+	}
+	if cfg.WSBandwidthExceededAction != "throttle" {
+		t.Errorf("Expected WSBandwidthExceededAction 'throttle', got %s", cfg.WSBandwidthExceededAction)
+	}
+	if cfg.LedgerLagThreshold != 10 {
+		t.Errorf("Expected LedgerLagThreshold 10, got %d", cfg.LedgerLagThreshold)
+	}
+	if cfg.WatchdogTxStallSeconds != 120 {
+		t.Errorf("Expected WatchdogTxStallSeconds 120, got %d", cfg.WatchdogTxStallSeconds)
+	}
+	if cfg.AnomalyWindowSeconds != 60 || cfg.AnomalyZThreshold != 3 {
+		t.Errorf("Expected anomaly window 60s at z=3, got %d %g", cfg.AnomalyWindowSeconds, cfg.AnomalyZThreshold)
+	}
+	if cfg.SLOMinValidators != 0 || cfg.SLOMaxValidators != 0 || cfg.SLOMinCoveragePercent != 0 {
+		t.Errorf("Expected SLO bounds disabled by default, got %d %d %g", cfg.SLOMinValidators, cfg.SLOMaxValidators, cfg.SLOMinCoveragePercent)
+	}
+	if cfg.DebugCaptureDir != "" || cfg.DebugCaptureMaxFiles != 100 || cfg.DebugCaptureInterval != 60 {
+		t.Errorf("Expected debug capture disabled with 100 files every 60s, got %q %d %d", cfg.DebugCaptureDir, cfg.DebugCaptureMaxFiles, cfg.DebugCaptureInterval)
+	}
+	if cfg.ReportPeriod != "" || len(cfg.ReportWebhookURLs) != 0 || cfg.ReportOutputDir != "" {
+		t.Errorf("Expected network reports disabled by default, got %q %v %q", cfg.ReportPeriod, cfg.ReportWebhookURLs, cfg.ReportOutputDir)
+	}
+	if cfg.PeersAdminJSONRPCURL != "" {
+		t.Errorf("Expected PeersAdminJSONRPCURL empty by default, got %s", cfg.PeersAdminJSONRPCURL)
+	}
+	if len(cfg.IssuerAccounts) != 0 || cfg.IssuerGraphRefreshInterval != 900 || cfg.IssuerGraphTopHolders != 50 {
+		t.Errorf("Expected no issuers refreshed every 900s with 50 holders, got %v %d %d", cfg.IssuerAccounts, cfg.IssuerGraphRefreshInterval, cfg.IssuerGraphTopHolders)
+	}
+	if cfg.DataDir == "" {
+		t.Error("Expected a default DataDir")
+	}
+	if cfg.ValidatorMetadataCachePath != filepath.Join(cfg.DataDir, "validator-metadata-cache.json") {
 		t.Errorf("Expected ValidatorMetadataCachePath default, got %s", cfg.ValidatorMetadataCachePath)
 	}
-	if cfg.GeoCachePath != "data/geolocation-cache.json" {
+	if cfg.GeoCachePath != filepath.Join(cfg.DataDir, "geolocation-cache.json") {
 		t.Errorf("Expected GeoCachePath default, got %s", cfg.GeoCachePath)
 	}
-	if cfg.GeoLiteDBPath != "data/GeoLite2-City.mmdb" {
+	if cfg.DecentralizationHistoryPath != filepath.Join(cfg.DataDir, "decentralization-history.json") || cfg.DecentralizationInterval != 86400 || cfg.DecentralizationRetentionDays != 730 {
+		t.Errorf("Unexpected decentralization history defaults: %s %d %d", cfg.DecentralizationHistoryPath, cfg.DecentralizationInterval, cfg.DecentralizationRetentionDays)
+	}
+	if cfg.LabelsPath != filepath.Join(cfg.DataDir, "account-labels.json") {
+		t.Errorf("Expected LabelsPath default, got %s", cfg.LabelsPath)
+	}
+	if cfg.PreferencesPath != filepath.Join(cfg.DataDir, "client-preferences.json") {
+		t.Errorf("Expected PreferencesPath default, got %s", cfg.PreferencesPath)
+	}
+	if cfg.CensusDir != "" || cfg.CensusUploadURL != "" {
+		t.Errorf("Expected the validator census disabled by default, got %s %s", cfg.CensusDir, cfg.CensusUploadURL)
+	}
+	if cfg.GeoLiteDBPath != filepath.Join(cfg.DataDir, "GeoLite2-City.mmdb") {
 		t.Errorf("Expected GeoLiteDBPath default, got %s", cfg.GeoLiteDBPath)
 	}
 	if cfg.GeoLiteDownloadURL != "https://github.com/P3TERX/GeoLite.mmdb/raw/download/GeoLite2-City.mmdb" {
 		t.Errorf("Expected GeoLiteDownloadURL default, got %s", cfg.GeoLiteDownloadURL)
 	}
+	if !cfg.GeoLiteEnabled {
+		t.Errorf("Expected GeoLiteEnabled default true")
+	}
 	if !cfg.GeoLiteAutoDownload {
 		t.Errorf("Expected GeoLiteAutoDownload default true")
 	}
+	if cfg.GeoLiteRefreshInterval != 0 {
+		t.Errorf("Expected GeoLite refresh disabled by default, got %d", cfg.GeoLiteRefreshInterval)
+	}
+	if cfg.GeoLiteInitRetryInterval != 60 {
+		t.Errorf("Expected GeoLiteInitRetryInterval 60, got %d", cfg.GeoLiteInitRetryInterval)
+	}
+	if cfg.GeoConfirmDBPath != "" {
+		t.Errorf("Expected no confirming geolocation DB by default, got %s", cfg.GeoConfirmDBPath)
+	}
+	if cfg.GeoLiteASNDBPath != "" {
+		t.Errorf("Expected no GeoLite ASN DB by default, got %s", cfg.GeoLiteASNDBPath)
+	}
+	if cfg.ListExpiryWarningDays != 7 {
+		t.Errorf("Expected ListExpiryWarningDays 7, got %d", cfg.ListExpiryWarningDays)
+	}
+	if cfg.LocationLockTTLDays != 30 {
+		t.Errorf("Expected LocationLockTTLDays 30, got %d", cfg.LocationLockTTLDays)
+	}
+	if cfg.PeerProbeInterval != 0 {
+		t.Errorf("Expected peer probing disabled by default, got %d", cfg.PeerProbeInterval)
+	}
+	if cfg.RefreshJitter != 0.1 || cfg.RefreshSplay || cfg.InstanceID != "" {
+		t.Errorf("Expected 10%% refresh jitter without splay by default, got %g %v %q", cfg.RefreshJitter, cfg.RefreshSplay, cfg.InstanceID)
+	}
 
 	expectedDefaultCORS := []string{
 		"http://127.0.0.1:3000",
@@ -112,10 +290,12 @@ func TestNewConfig(t *testing.T) {
 func TestNewConfigWithEnvVars(t *testing.T) {
 	os.Setenv("LISTEN_PORT", "9090")
 	os.Setenv("LISTEN_ADDR", "127.0.0.1")
+	os.Setenv("LISTEN_SPECS", "0.0.0.0:9090,[::]:9090,unix:/run/xrpl.sock")
 	os.Setenv("PUBLIC_XRPL_JSON_RPC_URL", "https://public.example")
 	os.Setenv("PUBLIC_XRPL_WEBSOCKET_URL", "wss://public.example")
 	os.Setenv("TRANSACTION_JSON_RPC_URL", "https://txrpc.example")
 	os.Setenv("TRANSACTION_WEBSOCKET_URL", "wss://txws.example")
+	os.Setenv("TRANSACTION_STREAMS", "transactions, Ledger,validations")
 	os.Setenv("XRPL_NETWORK", "testnet")
 	os.Setenv("VALIDATOR_REFRESH_INTERVAL", "600")
 	os.Setenv("VALIDATOR_LIST_SITES", "https://example.com/vl1,https://example.com/vl2")
@@ -123,27 +303,103 @@ func TestNewConfigWithEnvVars(t *testing.T) {
 	os.Setenv("VALIDATOR_METADATA_CACHE_PATH", "/tmp/validator-meta-cache.json")
 	os.Setenv("NETWORK_HEALTH_JSON_RPC_URLS", "https://health-1.example,https://health-2.example")
 	os.Setenv("NETWORK_HEALTH_RETRIES", "4")
+	os.Setenv("SERVER_STATUS_POLL_INTERVAL", "15")
+	os.Setenv("LEDGER_LAG_THRESHOLD", "20")
+	os.Setenv("RESPONSE_CACHE_TTL", "0")
+	os.Setenv("LOAD_UPSTREAM_LAG_TARGET", "30")
+	os.Setenv("WS_ORIGIN_POLICIES", `{"http://test.com":{"max_connections":2,"channels":["transactions"],"max_messages_per_second":1.5}}`)
+	os.Setenv("EXPLORERS", "livenet,testnet")
+	os.Setenv("EXPLORER_TEMPLATES", `{"testnet":{"transaction":"https://testnet.xrpl.org/transactions/{hash}"}}`)
+	os.Setenv("VIEWS", `{"acme":{"allowed_origins":["https://acme.example"],"min_payment_drops":5000000,"countries":["US","CA"]}}`)
+	os.Setenv("OUTBOUND_BUDGETS", `{"xrplcluster.com":{"requests_per_second":10,"burst":20},"*":{"requests_per_second":2.5}}`)
+	os.Setenv("GEO_PROVIDERS", `[{"name":"IPinfo","token":"t1","monthly_quota":50000},{"name":"maxmind","account_id":"42","license_key_file":"/run/secrets/maxmind","requests_per_second":5}]`)
+	os.Setenv("API_KEYS", "partner:k1,internal:k2")
+	os.Setenv("PREFERENCES_PATH", "/etc/xrpl/client-preferences.json")
+	os.Setenv("VALIDATOR_LIST_PUBLISHER_KEYS", "ed"+strings.Repeat("aa", 32)+" > ED"+strings.Repeat("BB", 32)+",ED"+strings.Repeat("CC", 32))
+	os.Setenv("UNL_PRESETS", "Main=https://vl.example.com, alt=https://vl.example.com")
+	os.Setenv("EXPORT_SIGNING_KEY", strings.Repeat("ab", 32))
+	os.Setenv("CORS_MAX_AGE", "7200")
+	os.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,192.168.1.1")
+	os.Setenv("ADMIN_TOKEN", "secret")
+	os.Setenv("XRPL_DNS_REFRESH_INTERVAL", "0")
+	os.Setenv("ROLE", "Serve")
+	os.Setenv("REPLICA_UPSTREAM_URL", "https://primary.example")
+	os.Setenv("REPLICA_ORIGIN", "https://edge.example")
+	os.Setenv("REPLICA_API_KEY", "edge-key")
+	os.Setenv("WS_CLIENT_BANDWIDTH_LIMIT", "65536")
+	os.Setenv("WS_BANDWIDTH_EXCEEDED_ACTION", "Summary")
+	os.Setenv("PRIVACY_MODE", "true")
+	os.Setenv("COORDINATE_PRECISION", "2")
+	os.Setenv("DISPLAY_WEIGHT_MODE", "FIAT")
+	os.Setenv("DISPLAY_WEIGHT_FIAT_RATE", "0.52")
+	os.Setenv("DEV_MODE", "true")
+	os.Setenv("PUBLIC_MODE", "true")
+	os.Setenv("ADMIN_LISTEN_SPEC", "unix:/run/xrpl-admin.sock")
+	os.Setenv("PUBLIC_RATE_LIMIT", "2.5")
+	os.Setenv("PUBLIC_RATE_BURST", "10")
+	os.Setenv("PUBLIC_MAX_CONNECTIONS_PER_IP", "2")
+	os.Setenv("PEERS_ADMIN_JSON_RPC_URL", "http://127.0.0.1:5005")
+	os.Setenv("ISSUER_ACCOUNTS", "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B, rchGBxcD1A1C2tdxF6papQYZ8kjRKMYcL")
+	os.Setenv("ISSUER_GRAPH_REFRESH_INTERVAL", "3600")
+	os.Setenv("ISSUER_GRAPH_TOP_HOLDERS", "20")
 	os.Setenv("GEO_CACHE_PATH", "/tmp/geo-cache.json")
 	os.Setenv("GEOLITE_DB_PATH", "/tmp/GeoLite2-City.mmdb")
 	os.Setenv("GEOLITE_DOWNLOAD_URL", "https://example.com/geolite.mmdb")
 	os.Setenv("GEOLITE_AUTO_DOWNLOAD", "false")
+	os.Setenv("GEOLITE_REFRESH_INTERVAL", "604800")
+	os.Setenv("GEOLITE_INIT_RETRY_INTERVAL", "0")
+	os.Setenv("GEO_CONFIRM_DB_PATH", "/tmp/dbip-city-lite.mmdb")
+	os.Setenv("GEO_CONFIRM_CACHE_PATH", "/tmp/geo-confirm-cache.json")
+	os.Setenv("GEOLITE_ASN_DB_PATH", "/tmp/GeoLite2-ASN.mmdb")
+	os.Setenv("REFRESH_JITTER", "0.25")
+	os.Setenv("REFRESH_SPLAY", "true")
+	os.Setenv("INSTANCE_ID", " edge-1 ")
 	os.Setenv("MIN_PAYMENT_DROPS", "2500000000")
 	os.Setenv("TRANSACTION_BUFFER_SIZE", "4096")
 	os.Setenv("GEO_ENRICHMENT_QUEUE_SIZE", "4096")
-	os.Setenv("GEO_ENRICHMENT_WORKERS", "16")
+	os.Setenv("GEO_ENRICHMENT_WORKERS", "24")
+	os.Setenv("GEO_ENRICHMENT_SHARDING", "true")
+	os.Setenv("FILTER_PREVIEW_WINDOW", "0")
 	os.Setenv("MAX_GEO_CANDIDATES", "10")
+	os.Setenv("ALLOWED_TX_RESULTS", "tesSUCCESS,tecPATH_DRY,tecUNFUNDED*")
+	os.Setenv("TX_PROCESSOR_COMMAND", "/usr/local/bin/tagger --strict")
+	os.Setenv("TX_PROCESSOR_TIMEOUT_MS", "50")
+	os.Setenv("WATCHLIST_PATH", "/etc/xrpl/watchlist.json")
+	os.Setenv("LABELS_PATH", "")
+	os.Setenv("DECENTRALIZATION_SNAPSHOT_INTERVAL", "3600")
+	os.Setenv("CENSUS_DIR", "/var/lib/xrpl/census")
+	os.Setenv("CENSUS_UPLOAD_URL", "https://bucket.example/census/{name}")
+	os.Setenv("DECENTRALIZATION_RETENTION_DAYS", "90")
+	os.Setenv("REPORT_PERIOD", "Weekly")
+	os.Setenv("ANOMALY_WINDOW_SECONDS", "30")
+	os.Setenv("ANOMALY_Z_THRESHOLD", "4.5")
+	os.Setenv("SLO_MIN_VALIDATORS", "30")
+	os.Setenv("SLO_MAX_VALIDATORS", "40")
+	os.Setenv("SLO_MIN_COVERAGE_PERCENT", "80")
+	os.Setenv("REPORT_WEBHOOK_URLS", "https://hooks.example/a, https://hooks.example/b")
+	os.Setenv("ENRICHMENT_RULES", `[{"name":"xrp","when":"amount_drops > 0","set":{"asset":"XRP"}}]`)
 	os.Setenv("BROADCAST_BUFFER_SIZE", "3000")
 	os.Setenv("WS_CLIENT_BUFFER_SIZE", "700")
 	os.Setenv("LOG_LEVEL", "debug")
+	os.Setenv("DEBUG_CAPTURE_DIR", "/tmp/captures")
+	os.Setenv("DEBUG_CAPTURE_MAX_FILES", "20")
+	os.Setenv("DEBUG_CAPTURE_INTERVAL", "300")
+	os.Setenv("RECONNECT_BACKOFF_INITIAL", "2")
+	os.Setenv("RECONNECT_BACKOFF_MAX", "120")
+	os.Setenv("RECONNECT_BACKOFF_JITTER", "0")
+	os.Setenv("RECONNECT_CIRCUIT_THRESHOLD", "0")
+	os.Setenv("RECONNECT_CIRCUIT_OPEN", "600")
 	os.Setenv("CORS_ALLOWED_ORIGINS", "http://example.com,http://test.com")
 
 	defer func() {
 		os.Unsetenv("LISTEN_PORT")
 		os.Unsetenv("LISTEN_ADDR")
+		os.Unsetenv("LISTEN_SPECS")
 		os.Unsetenv("PUBLIC_XRPL_JSON_RPC_URL")
 		os.Unsetenv("PUBLIC_XRPL_WEBSOCKET_URL")
 		os.Unsetenv("TRANSACTION_JSON_RPC_URL")
 		os.Unsetenv("TRANSACTION_WEBSOCKET_URL")
+		os.Unsetenv("TRANSACTION_STREAMS")
 		os.Unsetenv("XRPL_NETWORK")
 		os.Unsetenv("VALIDATOR_REFRESH_INTERVAL")
 		os.Unsetenv("VALIDATOR_LIST_SITES")
@@ -151,18 +407,92 @@ func TestNewConfigWithEnvVars(t *testing.T) {
 		os.Unsetenv("VALIDATOR_METADATA_CACHE_PATH")
 		os.Unsetenv("NETWORK_HEALTH_JSON_RPC_URLS")
 		os.Unsetenv("NETWORK_HEALTH_RETRIES")
+		os.Unsetenv("SERVER_STATUS_POLL_INTERVAL")
+		os.Unsetenv("LEDGER_LAG_THRESHOLD")
+		os.Unsetenv("RESPONSE_CACHE_TTL")
+		os.Unsetenv("LOAD_UPSTREAM_LAG_TARGET")
+		os.Unsetenv("WS_ORIGIN_POLICIES")
+		os.Unsetenv("VIEWS")
+		os.Unsetenv("EXPLORERS")
+		os.Unsetenv("EXPLORER_TEMPLATES")
+		os.Unsetenv("OUTBOUND_BUDGETS")
+		os.Unsetenv("GEO_PROVIDERS")
+		os.Unsetenv("API_KEYS")
+		os.Unsetenv("PREFERENCES_PATH")
+		os.Unsetenv("VALIDATOR_LIST_PUBLISHER_KEYS")
+		os.Unsetenv("UNL_PRESETS")
+		os.Unsetenv("EXPORT_SIGNING_KEY")
+		os.Unsetenv("CORS_MAX_AGE")
+		os.Unsetenv("TRUSTED_PROXIES")
+		os.Unsetenv("ADMIN_TOKEN")
+		os.Unsetenv("XRPL_DNS_REFRESH_INTERVAL")
+		os.Unsetenv("ROLE")
+		os.Unsetenv("REPLICA_UPSTREAM_URL")
+		os.Unsetenv("REPLICA_ORIGIN")
+		os.Unsetenv("REPLICA_API_KEY")
+		os.Unsetenv("WS_CLIENT_BANDWIDTH_LIMIT")
+		os.Unsetenv("WS_BANDWIDTH_EXCEEDED_ACTION")
+		os.Unsetenv("PRIVACY_MODE")
+		os.Unsetenv("COORDINATE_PRECISION")
+		os.Unsetenv("DISPLAY_WEIGHT_MODE")
+		os.Unsetenv("DISPLAY_WEIGHT_FIAT_RATE")
+		os.Unsetenv("DEV_MODE")
+		os.Unsetenv("PUBLIC_MODE")
+		os.Unsetenv("ADMIN_LISTEN_SPEC")
+		os.Unsetenv("PUBLIC_RATE_LIMIT")
+		os.Unsetenv("PUBLIC_RATE_BURST")
+		os.Unsetenv("PUBLIC_MAX_CONNECTIONS_PER_IP")
+		os.Unsetenv("PEERS_ADMIN_JSON_RPC_URL")
+		os.Unsetenv("ISSUER_ACCOUNTS")
+		os.Unsetenv("ISSUER_GRAPH_REFRESH_INTERVAL")
+		os.Unsetenv("ISSUER_GRAPH_TOP_HOLDERS")
 		os.Unsetenv("GEO_CACHE_PATH")
 		os.Unsetenv("GEOLITE_DB_PATH")
 		os.Unsetenv("GEOLITE_DOWNLOAD_URL")
 		os.Unsetenv("GEOLITE_AUTO_DOWNLOAD")
+		os.Unsetenv("GEOLITE_REFRESH_INTERVAL")
+		os.Unsetenv("GEOLITE_INIT_RETRY_INTERVAL")
+		os.Unsetenv("GEO_CONFIRM_DB_PATH")
+		os.Unsetenv("GEO_CONFIRM_CACHE_PATH")
+		os.Unsetenv("GEOLITE_ASN_DB_PATH")
+		os.Unsetenv("REFRESH_JITTER")
+		os.Unsetenv("REFRESH_SPLAY")
+		os.Unsetenv("INSTANCE_ID")
 		os.Unsetenv("MIN_PAYMENT_DROPS")
 		os.Unsetenv("TRANSACTION_BUFFER_SIZE")
 		os.Unsetenv("GEO_ENRICHMENT_QUEUE_SIZE")
 		os.Unsetenv("GEO_ENRICHMENT_WORKERS")
+		os.Unsetenv("GEO_ENRICHMENT_SHARDING")
+		os.Unsetenv("FILTER_PREVIEW_WINDOW")
 		os.Unsetenv("MAX_GEO_CANDIDATES")
+		os.Unsetenv("ALLOWED_TX_RESULTS")
+		os.Unsetenv("TX_PROCESSOR_COMMAND")
+		os.Unsetenv("TX_PROCESSOR_TIMEOUT_MS")
+		os.Unsetenv("WATCHLIST_PATH")
+		os.Unsetenv("LABELS_PATH")
+		os.Unsetenv("DECENTRALIZATION_SNAPSHOT_INTERVAL")
+		os.Unsetenv("CENSUS_DIR")
+		os.Unsetenv("CENSUS_UPLOAD_URL")
+		os.Unsetenv("DECENTRALIZATION_RETENTION_DAYS")
+		os.Unsetenv("REPORT_PERIOD")
+		os.Unsetenv("ANOMALY_WINDOW_SECONDS")
+		os.Unsetenv("ANOMALY_Z_THRESHOLD")
+		os.Unsetenv("SLO_MIN_VALIDATORS")
+		os.Unsetenv("SLO_MAX_VALIDATORS")
+		os.Unsetenv("SLO_MIN_COVERAGE_PERCENT")
+		os.Unsetenv("REPORT_WEBHOOK_URLS")
+		os.Unsetenv("ENRICHMENT_RULES")
 		os.Unsetenv("BROADCAST_BUFFER_SIZE")
 		os.Unsetenv("WS_CLIENT_BUFFER_SIZE")
 		os.Unsetenv("LOG_LEVEL")
+		os.Unsetenv("DEBUG_CAPTURE_DIR")
+		os.Unsetenv("DEBUG_CAPTURE_MAX_FILES")
+		os.Unsetenv("DEBUG_CAPTURE_INTERVAL")
+		os.Unsetenv("RECONNECT_BACKOFF_INITIAL")
+		os.Unsetenv("RECONNECT_BACKOFF_MAX")
+		os.Unsetenv("RECONNECT_BACKOFF_JITTER")
+		os.Unsetenv("RECONNECT_CIRCUIT_THRESHOLD")
+		os.Unsetenv("RECONNECT_CIRCUIT_OPEN")
 		os.Unsetenv("CORS_ALLOWED_ORIGINS")
 	}()
 
@@ -185,10 +515,13 @@ func TestNewConfigWithEnvVars(t *testing.T) {
 	if cfg.TransactionWebSocketURL != "wss://txws.example" {
 		t.Errorf("Expected TransactionWebSocketURL 'wss://txws.example', got %s", cfg.TransactionWebSocketURL)
 	}
+	if len(cfg.TransactionStreams) != 3 || cfg.TransactionStreams[1] != "ledger" || cfg.TransactionStreams[2] != "validations" {
+		t.Errorf("Expected TransactionStreams [transactions ledger validations], got %v", cfg.TransactionStreams)
+	}
 	if cfg.SecondaryValidatorRegistryURL != "https://example.com/registry" {
 		t.Errorf("Expected SecondaryValidatorRegistryURL 'https://example.com/registry', got %s", cfg.SecondaryValidatorRegistryURL)
 	}
-	if cfg.ValidatorMetadataCachePath != "/tmp/validator-meta-cache.json" {
+	if cfg.ValidatorMetadataCachePath != filepath.FromSlash("/tmp/validator-meta-cache.json") {
 		t.Errorf("Expected ValidatorMetadataCachePath '/tmp/validator-meta-cache.json', got %s", cfg.ValidatorMetadataCachePath)
 	}
 	expectedConfiguredHealthRPCURLs := []string{"https://health-1.example", "https://health-2.example"}
@@ -203,10 +536,128 @@ func TestNewConfigWithEnvVars(t *testing.T) {
 	if cfg.NetworkHealthRetries != 4 {
 		t.Errorf("Expected NetworkHealthRetries 4, got %d", cfg.NetworkHealthRetries)
 	}
-	if cfg.GeoCachePath != "/tmp/geo-cache.json" {
+	if cfg.ServerStatusPollInterval != 15 {
+		t.Errorf("Expected ServerStatusPollInterval 15, got %d", cfg.ServerStatusPollInterval)
+	}
+	expectedListenSpecs := []string{"0.0.0.0:9090", "[::]:9090", "unix:/run/xrpl.sock"}
+	if len(cfg.ListenSpecs) != len(expectedListenSpecs) {
+		t.Errorf("Expected ListenSpecs %v, got %v", expectedListenSpecs, cfg.ListenSpecs)
+	} else {
+		for i, spec := range expectedListenSpecs {
+			if cfg.ListenSpecs[i] != spec {
+				t.Errorf("Expected ListenSpecs[%d] %q, got %q", i, spec, cfg.ListenSpecs[i])
+			}
+		}
+	}
+	embedPolicy, ok := cfg.WSOriginPolicies["http://test.com"]
+	if !ok || embedPolicy.MaxConnections != 2 || len(embedPolicy.Channels) != 1 || embedPolicy.MaxMessagesPerSecond != 1.5 {
+		t.Errorf("Unexpected WSOriginPolicies: %+v", cfg.WSOriginPolicies)
+	}
+	if len(cfg.Explorers) != 2 || cfg.Explorers[0].Name != "livenet" || cfg.Explorers[1].Transaction != "https://testnet.xrpl.org/transactions/{hash}" {
+		t.Errorf("Unexpected Explorers: %+v", cfg.Explorers)
+	}
+	if acme, ok := cfg.Views["acme"]; !ok || acme.MinPaymentDrops != 5000000 || len(acme.Countries) != 2 || len(acme.AllowedOrigins) != 1 {
+		t.Errorf("Unexpected Views: %+v", cfg.Views)
+	}
+	if cluster := cfg.OutboundBudgets["xrplcluster.com"]; len(cfg.OutboundBudgets) != 2 || cluster.RequestsPerSecond != 10 || cluster.Burst != 20 || cfg.OutboundBudgets["*"].RequestsPerSecond != 2.5 {
+		t.Errorf("Unexpected OutboundBudgets: %+v", cfg.OutboundBudgets)
+	}
+	if len(cfg.GeoProviders) != 2 || cfg.GeoProviders[0].Name != "ipinfo" || cfg.GeoProviders[0].MonthlyQuota != 50000 ||
+		cfg.GeoProviders[1].AccountID != "42" || cfg.GeoProviders[1].LicenseKeyFile != filepath.FromSlash("/run/secrets/maxmind") || cfg.GeoProviders[1].RequestsPerSecond != 5 {
+		t.Errorf("Unexpected GeoProviders: %+v", cfg.GeoProviders)
+	}
+	if cfg.ResponseCacheTTL != 0 {
+		t.Errorf("Expected ResponseCacheTTL 0, got %d", cfg.ResponseCacheTTL)
+	}
+	if cfg.LoadUpstreamLagTarget != 30 {
+		t.Errorf("Expected LoadUpstreamLagTarget 30, got %d", cfg.LoadUpstreamLagTarget)
+	}
+	if cfg.CORSMaxAge != 7200 || len(cfg.TrustedProxies) != 2 || cfg.TrustedProxies[0] != "10.0.0.0/8" {
+		t.Errorf("Expected CORS_MAX_AGE and TRUSTED_PROXIES overrides, got %d and %v", cfg.CORSMaxAge, cfg.TrustedProxies)
+	}
+	if key := cfg.ExportKey(); len(key) != 64 {
+		t.Errorf("Expected an export key from EXPORT_SIGNING_KEY, got %d bytes", len(key))
+	}
+	if len(cfg.APIKeys) != 2 || cfg.APIKeys["k1"] != "partner" || cfg.APIKeys["k2"] != "internal" {
+		t.Errorf("Unexpected APIKeys: %v", cfg.APIKeys)
+	}
+	if cfg.PreferencesPath != filepath.FromSlash("/etc/xrpl/client-preferences.json") {
+		t.Errorf("Expected PreferencesPath /etc/xrpl/client-preferences.json, got %s", cfg.PreferencesPath)
+	}
+	if len(cfg.ValidatorListPublisherKeys) != 2 || len(cfg.ValidatorListPublisherKeys[0]) != 2 ||
+		cfg.ValidatorListPublisherKeys[0][0] != "ED"+strings.Repeat("AA", 32) || cfg.ValidatorListPublisherKeys[1][0] != "ED"+strings.Repeat("CC", 32) {
+		t.Errorf("Unexpected ValidatorListPublisherKeys: %v", cfg.ValidatorListPublisherKeys)
+	}
+	if len(cfg.UNLPresets) != 2 || cfg.UNLPresets["main"] != "https://vl.example.com" {
+		t.Errorf("Unexpected UNLPresets: %v", cfg.UNLPresets)
+	}
+	if sites := cfg.UNLPresetSites(); len(sites) != 1 || sites[0] != "https://vl.example.com" {
+		t.Errorf("Expected one distinct UNL preset site, got %v", sites)
+	}
+	if cfg.AdminToken != "secret" {
+		t.Errorf("Expected AdminToken 'secret', got %s", cfg.AdminToken)
+	}
+	if cfg.XRPLDNSRefreshInterval != 0 {
+		t.Errorf("Expected XRPLDNSRefreshInterval 0, got %d", cfg.XRPLDNSRefreshInterval)
+	}
+	if cfg.ReplicaUpstreamURL != "https://primary.example" || cfg.ReplicaOrigin != "https://edge.example" || cfg.ReplicaAPIKey != "edge-key" {
+		t.Errorf("Unexpected replica config: %s %s %s", cfg.ReplicaUpstreamURL, cfg.ReplicaOrigin, cfg.ReplicaAPIKey)
+	}
+	if cfg.Role != "serve" {
+		t.Errorf("Expected Role 'serve', got %s", cfg.Role)
+	}
+	if cfg.WSClientBandwidthLimit != 65536 {
+		t.Errorf("Expected WSClientBandwidthLimit 65536, got %d", cfg.WSClientBandwidthLimit)
+	}
+	if !cfg.PrivacyMode {
+		t.Errorf("Expected PrivacyMode true")
+	}
+	if cfg.CoordinatePrecision != 2 {
+		t.Errorf("Expected CoordinatePrecision 2, got %d", cfg.CoordinatePrecision)
+	}
+	if cfg.DisplayWeightMode != "fiat" || cfg.DisplayWeightFiatRate != 0.52 {
+		t.Errorf("Expected the fiat display weight mode at 0.52, got %s and %g", cfg.DisplayWeightMode, cfg.DisplayWeightFiatRate)
+	}
+	if !cfg.DevMode {
+		t.Errorf("Expected DevMode true")
+	}
+	if !cfg.PublicMode || cfg.AdminListenSpec != "unix:/run/xrpl-admin.sock" {
+		t.Errorf("Expected public mode with admin on unix:/run/xrpl-admin.sock, got %v and %s", cfg.PublicMode, cfg.AdminListenSpec)
+	}
+	if cfg.PublicRateLimit != 2.5 || cfg.PublicRateBurst != 10 || cfg.PublicMaxConnectionsPerIP != 2 {
+		t.Errorf("Expected public limits 2.5/s, burst 10 and 2 connections, got %g, %d and %d", cfg.PublicRateLimit, cfg.PublicRateBurst, cfg.PublicMaxConnectionsPerIP)
+	}
+	if cfg.WSBandwidthExceededAction != "summary" {
+		t.Errorf("Expected WSBandwidthExceededAction 'summary', got %s", cfg.WSBandwidthExceededAction)
+	}
+	if cfg.LedgerLagThreshold != 20 {
+		t.Errorf("Expected LedgerLagThreshold 20, got %d", cfg.LedgerLagThreshold)
+	}
+	if cfg.PeersAdminJSONRPCURL != "http://127.0.0.1:5005" {
+		t.Errorf("Expected PeersAdminJSONRPCURL 'http://127.0.0.1:5005', got %s", cfg.PeersAdminJSONRPCURL)
+	}
+	if len(cfg.IssuerAccounts) != 2 || cfg.IssuerAccounts[1] != "rchGBxcD1A1C2tdxF6papQYZ8kjRKMYcL" || cfg.IssuerGraphRefreshInterval != 3600 || cfg.IssuerGraphTopHolders != 20 {
+		t.Errorf("Unexpected issuer graph config: %v %d %d", cfg.IssuerAccounts, cfg.IssuerGraphRefreshInterval, cfg.IssuerGraphTopHolders)
+	}
+	if cfg.GeoCachePath != filepath.FromSlash("/tmp/geo-cache.json") {
 		t.Errorf("Expected GeoCachePath '/tmp/geo-cache.json', got %s", cfg.GeoCachePath)
 	}
-	if cfg.GeoLiteDBPath != "/tmp/GeoLite2-City.mmdb" {
+	if cfg.GeoLiteRefreshInterval != 604800 {
+		t.Errorf("Expected GeoLiteRefreshInterval 604800, got %d", cfg.GeoLiteRefreshInterval)
+	}
+	if cfg.GeoLiteInitRetryInterval != 0 {
+		t.Errorf("Expected GeoLiteInitRetryInterval 0, got %d", cfg.GeoLiteInitRetryInterval)
+	}
+	if cfg.GeoConfirmDBPath != filepath.FromSlash("/tmp/dbip-city-lite.mmdb") || cfg.GeoConfirmCachePath != filepath.FromSlash("/tmp/geo-confirm-cache.json") {
+		t.Errorf("Unexpected confirming geolocation paths: %s %s", cfg.GeoConfirmDBPath, cfg.GeoConfirmCachePath)
+	}
+	if cfg.GeoLiteASNDBPath != filepath.FromSlash("/tmp/GeoLite2-ASN.mmdb") {
+		t.Errorf("Expected GeoLiteASNDBPath '/tmp/GeoLite2-ASN.mmdb', got %s", cfg.GeoLiteASNDBPath)
+	}
+	if cfg.RefreshJitter != 0.25 || !cfg.RefreshSplay || cfg.InstanceID != "edge-1" {
+		t.Errorf("Unexpected refresh scheduling config: %g %v %q", cfg.RefreshJitter, cfg.RefreshSplay, cfg.InstanceID)
+	}
+	if cfg.GeoLiteDBPath != filepath.FromSlash("/tmp/GeoLite2-City.mmdb") {
 		t.Errorf("Expected GeoLiteDBPath '/tmp/GeoLite2-City.mmdb', got %s", cfg.GeoLiteDBPath)
 	}
 	if cfg.GeoLiteDownloadURL != "https://example.com/geolite.mmdb" {
@@ -221,12 +672,58 @@ func TestNewConfigWithEnvVars(t *testing.T) {
 	if cfg.GeoEnrichmentQSize != 4096 {
 		t.Errorf("Expected GeoEnrichmentQSize 4096, got %d", cfg.GeoEnrichmentQSize)
 	}
-	if cfg.GeoEnrichmentWorkers != 16 {
-		t.Errorf("Expected GeoEnrichmentWorkers 16, got %d", cfg.GeoEnrichmentWorkers)
+	if cfg.GeoEnrichmentWorkers != 24 {
+		t.Errorf("Expected GeoEnrichmentWorkers 24, got %d", cfg.GeoEnrichmentWorkers)
+	}
+	if !cfg.GeoEnrichmentSharding {
+		t.Error("Expected GeoEnrichmentSharding to be enabled")
+	}
+	if cfg.FilterPreviewWindow != 0 {
+		t.Errorf("Expected FilterPreviewWindow disabled, got %d", cfg.FilterPreviewWindow)
 	}
 	if cfg.MaxGeoCandidates != 10 {
 		t.Errorf("Expected MaxGeoCandidates 10, got %d", cfg.MaxGeoCandidates)
 	}
+	if len(cfg.AllowedTxResults) != 3 || cfg.AllowedTxResults[1] != "tecPATH_DRY" {
+		t.Errorf("Expected AllowedTxResults [tesSUCCESS tecPATH_DRY tecUNFUNDED*], got %v", cfg.AllowedTxResults)
+	}
+	if cfg.TxProcessorCommand != "/usr/local/bin/tagger --strict" || cfg.TxProcessorTimeoutMS != 50 {
+		t.Errorf("Unexpected transaction processor config: %q %d", cfg.TxProcessorCommand, cfg.TxProcessorTimeoutMS)
+	}
+	if cfg.WatchlistPath != filepath.FromSlash("/etc/xrpl/watchlist.json") {
+		t.Errorf("Expected WatchlistPath /etc/xrpl/watchlist.json, got %s", cfg.WatchlistPath)
+	}
+	if cfg.LabelsPath != "" {
+		t.Errorf("Expected an empty LABELS_PATH to disable labels, got %s", cfg.LabelsPath)
+	}
+	if cfg.DecentralizationInterval != 3600 || cfg.DecentralizationRetentionDays != 90 {
+		t.Errorf("Unexpected decentralization history config: %d %d", cfg.DecentralizationInterval, cfg.DecentralizationRetentionDays)
+	}
+	if cfg.CensusDir != filepath.FromSlash("/var/lib/xrpl/census") || cfg.CensusUploadURL != "https://bucket.example/census/{name}" {
+		t.Errorf("Unexpected census config: %s %s", cfg.CensusDir, cfg.CensusUploadURL)
+	}
+	if cfg.AnomalyWindowSeconds != 30 || cfg.AnomalyZThreshold != 4.5 {
+		t.Errorf("Unexpected anomaly config: %d %g", cfg.AnomalyWindowSeconds, cfg.AnomalyZThreshold)
+	}
+	if cfg.SLOMinValidators != 30 || cfg.SLOMaxValidators != 40 || cfg.SLOMinCoveragePercent != 80 {
+		t.Errorf("Unexpected SLO bounds: %d %d %g", cfg.SLOMinValidators, cfg.SLOMaxValidators, cfg.SLOMinCoveragePercent)
+	}
+	if cfg.DebugCaptureDir != "/tmp/captures" || cfg.DebugCaptureMaxFiles != 20 || cfg.DebugCaptureInterval != 300 {
+		t.Errorf("Unexpected debug capture config: %q %d %d", cfg.DebugCaptureDir, cfg.DebugCaptureMaxFiles, cfg.DebugCaptureInterval)
+	}
+	policy := cfg.ReconnectPolicy()
+	if policy.Initial != 2*time.Second || policy.Max != 2*time.Minute || policy.CircuitOpenTimeout != 10*time.Minute {
+		t.Errorf("Unexpected reconnect policy: %+v", policy)
+	}
+	if policy.Jitter >= 0 || policy.CircuitThreshold >= 0 {
+		t.Errorf("Expected zero jitter and threshold to disable them, got %+v", policy)
+	}
+	if cfg.ReportPeriod != "weekly" || len(cfg.ReportWebhookURLs) != 2 || cfg.ReportWebhookURLs[1] != "https://hooks.example/b" {
+		t.Errorf("Unexpected report config: %q %v", cfg.ReportPeriod, cfg.ReportWebhookURLs)
+	}
+	if len(cfg.EnrichmentRules) != 1 || cfg.EnrichmentRules[0].Set["asset"] != "XRP" {
+		t.Errorf("Unexpected EnrichmentRules: %+v", cfg.EnrichmentRules)
+	}
 	if cfg.BroadcastBufferSize != 3000 {
 		t.Errorf("Expected BroadcastBufferSize 3000, got %d", cfg.BroadcastBufferSize)
 	}
@@ -243,14 +740,37 @@ func validConfig() *Config {
 		PublicXRPLWebSocketURL:        "wss://xrplcluster.com",
 		TransactionJSONRPCURL:         "https://xrplcluster.com",
 		TransactionWebSocketURL:       "wss://xrplcluster.com",
+		TransactionStreams:            []string{"transactions"},
+		XRPLMessageBufferSize:         4096,
+		XRPLDecodeWorkers:             2,
+		XRPLMaxMessageBytes:           1 << 20,
+		XRPLMaxMessageDepth:           64,
+		ReconnectBackoffInitial:       1,
+		ReconnectBackoffMax:           60,
+		ReconnectBackoffJitter:        0.2,
+		ReconnectCircuitThreshold:     10,
+		ReconnectCircuitOpen:          300,
 		Network:                       "mainnet",
+		Role:                          "all",
 		ValidatorRefreshInterval:      300,
 		ValidatorListSites:            []string{"https://vl.ripple.com"},
 		SecondaryValidatorRegistryURL: "https://api.xrpscan.com/api/v1/validatorregistry",
 		ValidatorMetadataCachePath:    "data/validator-metadata-cache.json",
 		NetworkHealthJSONRPCURLs:      []string{"https://xrplcluster.com", "https://s2.ripple.com:51234"},
 		NetworkHealthRetries:          2,
+		ServerStatusPollInterval:      30,
+		LedgerLagThreshold:            10,
+		WatchdogTxStallSeconds:        120,
+		AnomalyWindowSeconds:          60,
+		AnomalyZThreshold:             3,
+		IssuerGraphRefreshInterval:    900,
+		IssuerGraphTopHolders:         50,
+		ResponseCacheTTL:              5,
+		LoadUpstreamLagTarget:         10,
+		WSBandwidthExceededAction:     "throttle",
+		DisplayWeightMode:             "xrp",
 		GeoCachePath:                  "data/geolocation-cache.json",
+		GeoLiteEnabled:                true,
 		GeoLiteDBPath:                 "data/GeoLite2-City.mmdb",
 		GeoLiteDownloadURL:            "https://github.com/P3TERX/GeoLite.mmdb/raw/download/GeoLite2-City.mmdb",
 		GeoLiteAutoDownload:           true,
@@ -259,6 +779,8 @@ func validConfig() *Config {
 		GeoEnrichmentQSize:            2048,
 		GeoEnrichmentWorkers:          8,
 		MaxGeoCandidates:              6,
+		AllowedTxResults:              []string{"tesSUCCESS"},
+		TxProcessorTimeoutMS:          200,
 		BroadcastBufferSize:           2048,
 		WSClientBufferSize:            512,
 		CORSAllowedOrigins:            []string{"http://localhost:3000"},
@@ -266,6 +788,13 @@ func validConfig() *Config {
 }
 
 func TestConfigValidate(t *testing.T) {
+	public := func(c *Config) {
+		c.PublicMode = true
+		c.AdminListenSpec = "127.0.0.1:8081"
+		c.PublicRateLimit = 5
+		c.PublicRateBurst = 20
+		c.PublicMaxConnectionsPerIP = 4
+	}
 	tests := []struct {
 		name    string
 		mutate  func(*Config)
@@ -282,15 +811,273 @@ func TestConfigValidate(t *testing.T) {
 		{name: "empty validator metadata cache path", mutate: func(c *Config) { c.ValidatorMetadataCachePath = "" }, wantErr: true},
 		{name: "empty network health rpc urls", mutate: func(c *Config) { c.NetworkHealthJSONRPCURLs = []string{} }, wantErr: true},
 		{name: "zero network health retries", mutate: func(c *Config) { c.NetworkHealthRetries = 0 }, wantErr: true},
+		{name: "zero server status poll interval", mutate: func(c *Config) { c.ServerStatusPollInterval = 0 }, wantErr: true},
+		{name: "zero response cache ttl", mutate: func(c *Config) { c.ResponseCacheTTL = 0 }, wantErr: false},
+		{name: "dual stack and unix listen specs", mutate: func(c *Config) {
+			c.ListenSpecs = []string{"0.0.0.0:8080", "tcp6:[::]:8080", "unix:/run/xrpl.sock"}
+		}, wantErr: false},
+		{name: "listen spec without port", mutate: func(c *Config) { c.ListenSpecs = []string{"0.0.0.0"} }, wantErr: true},
+		{name: "unix listen spec without path", mutate: func(c *Config) { c.ListenSpecs = []string{"unix:"} }, wantErr: true},
+		{name: "listen spec with bad port", mutate: func(c *Config) { c.ListenSpecs = []string{"tcp:0.0.0.0:99999"} }, wantErr: true},
+		{name: "ws origin policy for allowed origin", mutate: func(c *Config) {
+			c.WSOriginPolicies = map[string]models.OriginPolicy{"http://localhost:3000": {MaxConnections: 5}, "*": {MaxMessagesPerSecond: 10}}
+		}, wantErr: false},
+		{name: "ws origin policy for unknown origin", mutate: func(c *Config) {
+			c.WSOriginPolicies = map[string]models.OriginPolicy{"https://other.example": {MaxConnections: 5}}
+		}, wantErr: true},
+		{name: "ws origin policy with negative limit", mutate: func(c *Config) {
+			c.WSOriginPolicies = map[string]models.OriginPolicy{"*": {MaxConnections: -1}}
+		}, wantErr: true},
+		{name: "ws origin policy with known channels", mutate: func(c *Config) {
+			c.WSOriginPolicies = map[string]models.OriginPolicy{"*": {Channels: []string{"transactions", "tx_geo_update"}}}
+		}, wantErr: false},
+		{name: "ws origin policy with unknown channel", mutate: func(c *Config) {
+			c.WSOriginPolicies = map[string]models.OriginPolicy{"*": {Channels: []string{"transaction"}}}
+		}, wantErr: true},
+		{name: "malformed ws origin policies", mutate: func(c *Config) {
+			_, c.wsOriginPolicyErr = parseOriginPolicies("{not json")
+		}, wantErr: true},
+		{name: "view with filters", mutate: func(c *Config) {
+			c.Views = map[string]models.View{"acme-embed": {AllowedOrigins: []string{"https://acme.example"}, MinPaymentDrops: 1000000, Countries: []string{"US"}, Channels: []string{"transactions"}}}
+		}, wantErr: false},
+		{name: "view with invalid name", mutate: func(c *Config) {
+			c.Views = map[string]models.View{"Acme/Embed": {}}
+		}, wantErr: true},
+		{name: "view with negative threshold", mutate: func(c *Config) {
+			c.Views = map[string]models.View{"acme": {MinPaymentDrops: -1}}
+		}, wantErr: true},
+		{name: "view with invalid country", mutate: func(c *Config) {
+			c.Views = map[string]models.View{"acme": {Countries: []string{"usa"}}}
+		}, wantErr: true},
+		{name: "view with unknown channel", mutate: func(c *Config) {
+			c.Views = map[string]models.View{"acme": {Channels: []string{"transactions", "validators"}}}
+		}, wantErr: true},
+		{name: "malformed views", mutate: func(c *Config) {
+			_, c.viewsErr = parseViews("{not json")
+		}, wantErr: true},
+		{name: "unknown explorer", mutate: func(c *Config) {
+			_, c.explorersErr = parseExplorers("xrpscan,nope", "")
+		}, wantErr: true},
+		{name: "malformed explorer templates", mutate: func(c *Config) {
+			_, c.explorersErr = parseExplorers("xrpscan", "{not json")
+		}, wantErr: true},
+		{name: "explorer template without placeholder", mutate: func(c *Config) {
+			_, c.explorersErr = parseExplorers("mine", `{"mine":{"account":"https://example.com/account"}}`)
+		}, wantErr: true},
+		{name: "negative CORS max age", mutate: func(c *Config) {
+			c.CORSMaxAge = -1
+		}, wantErr: true},
+		{name: "trusted proxies", mutate: func(c *Config) {
+			c.TrustedProxies = []string{"10.0.0.0/8", "::1"}
+		}, wantErr: false},
+		{name: "invalid trusted proxy", mutate: func(c *Config) {
+			c.TrustedProxies = []string{"proxy.internal"}
+		}, wantErr: true},
+		{name: "export signing key", mutate: func(c *Config) {
+			c.ExportSigningKey = strings.Repeat("0f", 32)
+		}, wantErr: false},
+		{name: "short export signing key", mutate: func(c *Config) {
+			c.ExportSigningKey = "0f0f"
+		}, wantErr: true},
+		{name: "outbound budgets", mutate: func(c *Config) {
+			c.OutboundBudgets = map[string]models.OutboundBudget{"xrplcluster.com": {RequestsPerSecond: 10, Burst: 20}, "*": {RequestsPerSecond: 1}}
+		}, wantErr: false},
+		{name: "outbound budget for a URL", mutate: func(c *Config) {
+			c.OutboundBudgets = map[string]models.OutboundBudget{"https://xrplcluster.com": {RequestsPerSecond: 10}}
+		}, wantErr: true},
+		{name: "outbound budget without a rate", mutate: func(c *Config) {
+			c.OutboundBudgets = map[string]models.OutboundBudget{"xrplcluster.com": {Burst: 5}}
+		}, wantErr: true},
+		{name: "malformed outbound budgets", mutate: func(c *Config) {
+			_, c.outboundBudgetsErr = parseOutboundBudgets("{not json")
+		}, wantErr: true},
+		{name: "geolocation providers", mutate: func(c *Config) {
+			c.GeoProviders = []models.GeoProvider{{Name: "ipinfo", TokenFile: "/run/secrets/ipinfo"}, {Name: "maxmind", AccountID: "42", LicenseKey: "k", URL: "https://geolite.info"}}
+		}, wantErr: false},
+		{name: "unknown geolocation provider", mutate: func(c *Config) {
+			c.GeoProviders = []models.GeoProvider{{Name: "ipstack", Token: "t"}}
+		}, wantErr: true},
+		{name: "ipinfo provider without a token", mutate: func(c *Config) {
+			c.GeoProviders = []models.GeoProvider{{Name: "ipinfo"}}
+		}, wantErr: true},
+		{name: "maxmind provider without an account", mutate: func(c *Config) {
+			c.GeoProviders = []models.GeoProvider{{Name: "maxmind", LicenseKey: "k"}}
+		}, wantErr: true},
+		{name: "duplicate geolocation provider", mutate: func(c *Config) {
+			c.GeoProviders = []models.GeoProvider{{Name: "ipinfo", Token: "a"}, {Name: "ipinfo", Token: "b"}}
+		}, wantErr: true},
+		{name: "negative geolocation provider quota", mutate: func(c *Config) {
+			c.GeoProviders = []models.GeoProvider{{Name: "ipinfo", Token: "a", MonthlyQuota: -1}}
+		}, wantErr: true},
+		{name: "geolocation provider URL without scheme", mutate: func(c *Config) {
+			c.GeoProviders = []models.GeoProvider{{Name: "ipinfo", Token: "a", URL: "ipinfo.io"}}
+		}, wantErr: true},
+		{name: "malformed geolocation providers", mutate: func(c *Config) {
+			_, c.geoProvidersErr = parseGeoProviders("[{")
+		}, wantErr: true},
+		{name: "valid enrichment rule", mutate: func(c *Config) {
+			c.EnrichmentRules = []models.EnrichmentRule{{Name: "large", When: "amount_drops >= 1e9", Set: map[string]string{"size": "large"}}}
+		}, wantErr: false},
+		{name: "enrichment rule with unknown field", mutate: func(c *Config) {
+			c.EnrichmentRules = []models.EnrichmentRule{{Name: "bad", When: "labels.source != \"\"", Set: map[string]string{"x": "y"}}}
+		}, wantErr: true},
+		{name: "malformed enrichment rules", mutate: func(c *Config) {
+			_, c.enrichmentRulesErr = parseEnrichmentRules("[{")
+		}, wantErr: true},
+		{name: "zero transaction processor timeout", mutate: func(c *Config) { c.TxProcessorTimeoutMS = 0 }, wantErr: true},
+		{name: "negative dns refresh interval", mutate: func(c *Config) { c.XRPLDNSRefreshInterval = -1 }, wantErr: true},
+		{name: "zero message buffer", mutate: func(c *Config) { c.XRPLMessageBufferSize = 0 }, wantErr: true},
+		{name: "zero decode workers", mutate: func(c *Config) { c.XRPLDecodeWorkers = 0 }, wantErr: true},
+		{name: "tiny max message bytes", mutate: func(c *Config) { c.XRPLMaxMessageBytes = 1024 }, wantErr: true},
+		{name: "shallow max message depth", mutate: func(c *Config) { c.XRPLMaxMessageDepth = 4 }, wantErr: true},
+		{name: "unrounded coordinates", mutate: func(c *Config) { c.CoordinatePrecision = 0 }, wantErr: false},
+		{name: "log display weight", mutate: func(c *Config) { c.DisplayWeightMode = "log" }, wantErr: false},
+		{name: "fiat display weight", mutate: func(c *Config) { c.DisplayWeightMode, c.DisplayWeightFiatRate = "fiat", 0.5 }, wantErr: false},
+		{name: "fiat display weight without rate", mutate: func(c *Config) { c.DisplayWeightMode = "fiat" }, wantErr: true},
+		{name: "unknown display weight mode", mutate: func(c *Config) { c.DisplayWeightMode = "usd" }, wantErr: true},
+		{name: "coordinate precision too high", mutate: func(c *Config) { c.CoordinatePrecision = 9 }, wantErr: true},
+		{name: "zero reconnect backoff", mutate: func(c *Config) { c.ReconnectBackoffInitial = 0 }, wantErr: true},
+		{name: "reconnect max below initial", mutate: func(c *Config) { c.ReconnectBackoffMax = 0 }, wantErr: true},
+		{name: "reconnect jitter above 1", mutate: func(c *Config) { c.ReconnectBackoffJitter = 1.5 }, wantErr: true},
+		{name: "reconnect circuit disabled", mutate: func(c *Config) { c.ReconnectCircuitThreshold = 0 }, wantErr: false},
+		{name: "negative reconnect circuit threshold", mutate: func(c *Config) { c.ReconnectCircuitThreshold = -1 }, wantErr: true},
+		{name: "replica upstream with origin", mutate: func(c *Config) {
+			c.ReplicaUpstreamURL = "https://primary.example"
+			c.ReplicaOrigin = "https://edge.example"
+		}, wantErr: false},
+		{name: "replica upstream without origin", mutate: func(c *Config) { c.ReplicaUpstreamURL = "https://primary.example" }, wantErr: true},
+		{name: "replica upstream with ws scheme", mutate: func(c *Config) {
+			c.ReplicaUpstreamURL = "wss://primary.example"
+			c.ReplicaOrigin = "https://edge.example"
+		}, wantErr: true},
+		{name: "unknown role", mutate: func(c *Config) { c.Role = "edge" }, wantErr: true},
+		{name: "ingest role", mutate: func(c *Config) { c.Role = "ingest" }, wantErr: false},
+		{name: "ingest role with replica upstream", mutate: func(c *Config) {
+			c.Role = "ingest"
+			c.ReplicaUpstreamURL = "https://primary.example"
+			c.ReplicaOrigin = "https://edge.example"
+		}, wantErr: true},
+		{name: "serve role with replica upstream", mutate: func(c *Config) {
+			c.Role = "serve"
+			c.ReplicaUpstreamURL = "https://ingest.example"
+			c.ReplicaOrigin = "https://edge.example"
+		}, wantErr: false},
+		{name: "serve role without replica upstream", mutate: func(c *Config) { c.Role = "serve" }, wantErr: true},
+		{name: "malformed api keys", mutate: func(c *Config) {
+			_, c.apiKeysErr = parseAPIKeys("partner")
+		}, wantErr: true},
+		{name: "duplicate api keys", mutate: func(c *Config) {
+			_, c.apiKeysErr = parseAPIKeys("a:k1,b:k1")
+		}, wantErr: true},
+		{name: "malformed unl preset", mutate: func(c *Config) {
+			_, c.unlPresetsErr = parseUNLPresets("dunl")
+		}, wantErr: true},
+		{name: "unl preset with invalid url", mutate: func(c *Config) {
+			_, c.unlPresetsErr = parseUNLPresets("dunl=vl.ripple.com")
+		}, wantErr: true},
+		{name: "duplicate unl preset", mutate: func(c *Config) {
+			_, c.unlPresetsErr = parseUNLPresets("a=https://a.example,a=https://b.example")
+		}, wantErr: true},
+		{name: "short publisher key", mutate: func(c *Config) {
+			_, c.publisherKeysErr = parsePublisherKeyChains("ED" + strings.Repeat("AA", 16))
+		}, wantErr: true},
+		{name: "publisher key in two chains", mutate: func(c *Config) {
+			key := "ED" + strings.Repeat("AA", 32)
+			_, c.publisherKeysErr = parsePublisherKeyChains(key + "," + key + ">ED" + strings.Repeat("BB", 32))
+		}, wantErr: true},
+		{name: "negative ws bandwidth limit", mutate: func(c *Config) { c.WSClientBandwidthLimit = -1 }, wantErr: true},
+		{name: "unknown ws bandwidth action", mutate: func(c *Config) { c.WSBandwidthExceededAction = "close" }, wantErr: true},
+		{name: "public mode", mutate: public, wantErr: false},
+		{name: "public mode with dev mode", mutate: func(c *Config) { public(c); c.DevMode = true }, wantErr: true},
+		{name: "public mode admin on unix socket", mutate: func(c *Config) { public(c); c.AdminListenSpec = "unix:/run/xrpl-admin.sock" }, wantErr: false},
+		{name: "public mode admin on localhost", mutate: func(c *Config) { public(c); c.AdminListenSpec = "tcp6:localhost:8081" }, wantErr: false},
+		{name: "public mode admin on all interfaces", mutate: func(c *Config) { public(c); c.AdminListenSpec = "0.0.0.0:8081" }, wantErr: true},
+		{name: "public mode admin on public listener", mutate: func(c *Config) {
+			public(c)
+			c.ListenSpecs = []string{"127.0.0.1:8081"}
+		}, wantErr: true},
+		{name: "public mode zero rate limit", mutate: func(c *Config) { public(c); c.PublicRateLimit = 0 }, wantErr: true},
+		{name: "public mode zero burst", mutate: func(c *Config) { public(c); c.PublicRateBurst = 0 }, wantErr: true},
+		{name: "public mode zero connections per ip", mutate: func(c *Config) { public(c); c.PublicMaxConnectionsPerIP = 0 }, wantErr: true},
+		{name: "negative response cache ttl", mutate: func(c *Config) { c.ResponseCacheTTL = -1 }, wantErr: true},
+		{name: "zero load upstream lag target", mutate: func(c *Config) { c.LoadUpstreamLagTarget = 0 }, wantErr: true},
+		{name: "zero ledger lag threshold", mutate: func(c *Config) { c.LedgerLagThreshold = 0 }, wantErr: true},
+		{name: "negative filter preview window", mutate: func(c *Config) { c.FilterPreviewWindow = -1 }, wantErr: true},
+		{name: "zero watchdog stall disables", mutate: func(c *Config) { c.WatchdogTxStallSeconds = 0 }, wantErr: false},
+		{name: "negative watchdog stall", mutate: func(c *Config) { c.WatchdogTxStallSeconds = -1 }, wantErr: true},
+		{name: "negative anomaly window", mutate: func(c *Config) { c.AnomalyWindowSeconds = -1 }, wantErr: true},
+		{name: "zero anomaly threshold", mutate: func(c *Config) { c.AnomalyZThreshold = 0 }, wantErr: true},
+		{name: "zero anomaly threshold when disabled", mutate: func(c *Config) { c.AnomalyWindowSeconds = 0; c.AnomalyZThreshold = 0 }, wantErr: false},
+		{name: "negative SLO min validators", mutate: func(c *Config) { c.SLOMinValidators = -1 }, wantErr: true},
+		{name: "SLO max validators below min", mutate: func(c *Config) { c.SLOMinValidators = 35; c.SLOMaxValidators = 30 }, wantErr: true},
+		{name: "SLO max validators without min", mutate: func(c *Config) { c.SLOMaxValidators = 40 }, wantErr: false},
+		{name: "SLO coverage above 100", mutate: func(c *Config) { c.SLOMinCoveragePercent = 101 }, wantErr: true},
+		{name: "SLO coverage", mutate: func(c *Config) { c.SLOMinCoveragePercent = 80 }, wantErr: false},
+		{name: "debug capture without max files", mutate: func(c *Config) { c.DebugCaptureDir = "/tmp/captures"; c.DebugCaptureMaxFiles = 0 }, wantErr: true},
+		{name: "debug capture max files when disabled", mutate: func(c *Config) { c.DebugCaptureMaxFiles = 0 }, wantErr: false},
+		{name: "unknown report period", mutate: func(c *Config) { c.ReportPeriod = "monthly"; c.ReportOutputDir = "reports" }, wantErr: true},
+		{name: "report period without destination", mutate: func(c *Config) { c.ReportPeriod = "daily" }, wantErr: true},
+		{name: "short decentralization interval", mutate: func(c *Config) { c.DecentralizationHistoryPath = "history.json"; c.DecentralizationInterval = 10 }, wantErr: true},
+		{name: "zero decentralization retention", mutate: func(c *Config) { c.DecentralizationHistoryPath = "history.json"; c.DecentralizationRetentionDays = 0 }, wantErr: true},
+		{name: "decentralization history disabled", mutate: func(c *Config) { c.DecentralizationHistoryPath = ""; c.DecentralizationInterval = 0 }, wantErr: false},
+		{name: "census without signing key", mutate: func(c *Config) { c.CensusDir = "census" }, wantErr: true},
+		{name: "census upload without placeholder", mutate: func(c *Config) {
+			c.CensusDir = "census"
+			c.ExportSigningKey = strings.Repeat("0f", 32)
+			c.CensusUploadURL = "https://bucket.example/census"
+		}, wantErr: true},
+		{name: "census with signing key and upload", mutate: func(c *Config) {
+			c.CensusDir = "census"
+			c.ExportSigningKey = strings.Repeat("0f", 32)
+			c.CensusUploadURL = "https://bucket.example/census/{name}"
+		}, wantErr: false},
+		{name: "report period with output dir", mutate: func(c *Config) { c.ReportPeriod = "daily"; c.ReportOutputDir = "reports" }, wantErr: false},
+		{name: "invalid report webhook url", mutate: func(c *Config) { c.ReportWebhookURLs = []string{"ftp://hooks.example"} }, wantErr: true},
+		{name: "invalid issuer account", mutate: func(c *Config) { c.IssuerAccounts = []string{"bitstamp"} }, wantErr: true},
+		{name: "zero issuer graph refresh interval", mutate: func(c *Config) { c.IssuerGraphRefreshInterval = 0 }, wantErr: true},
+		{name: "zero issuer graph top holders", mutate: func(c *Config) { c.IssuerGraphTopHolders = 0 }, wantErr: true},
 		{name: "empty geo cache path", mutate: func(c *Config) { c.GeoCachePath = "" }, wantErr: true},
 		{name: "empty geolite db path", mutate: func(c *Config) { c.GeoLiteDBPath = "" }, wantErr: true},
 		{name: "empty geolite download when auto enabled", mutate: func(c *Config) { c.GeoLiteDownloadURL = "" }, wantErr: true},
 		{name: "empty geolite download when auto disabled", mutate: func(c *Config) { c.GeoLiteAutoDownload = false; c.GeoLiteDownloadURL = "" }, wantErr: false},
+		{name: "empty geolite download when refresh enabled", mutate: func(c *Config) {
+			c.GeoLiteAutoDownload = false
+			c.GeoLiteDownloadURL = ""
+			c.GeoLiteRefreshInterval = 3600
+		}, wantErr: true},
+		{name: "negative geolite refresh interval", mutate: func(c *Config) { c.GeoLiteRefreshInterval = -1 }, wantErr: true},
+		{name: "negative geolite init retry interval", mutate: func(c *Config) { c.GeoLiteInitRetryInterval = -1 }, wantErr: true},
+		{name: "geolite disabled without db path", mutate: func(c *Config) { c.GeoLiteEnabled = false; c.GeoLiteDBPath = "" }, wantErr: false},
+		{name: "geolite disabled with confirm db", mutate: func(c *Config) {
+			c.GeoLiteEnabled = false
+			c.GeoConfirmDBPath = "/tmp/dbip-city-lite.mmdb"
+		}, wantErr: true},
+		{name: "list expiry warning disabled", mutate: func(c *Config) { c.ListExpiryWarningDays = 0 }, wantErr: false},
+		{name: "negative list expiry warning", mutate: func(c *Config) { c.ListExpiryWarningDays = -1 }, wantErr: true},
+		{name: "location lock without expiry", mutate: func(c *Config) { c.LocationLockTTLDays = 0 }, wantErr: false},
+		{name: "negative location lock ttl", mutate: func(c *Config) { c.LocationLockTTLDays = -1 }, wantErr: true},
+		{name: "hourly peer probes", mutate: func(c *Config) { c.PeerProbeInterval = 3600 }, wantErr: false},
+		{name: "too frequent peer probes", mutate: func(c *Config) { c.PeerProbeInterval = 60 }, wantErr: true},
+		{name: "negative refresh jitter", mutate: func(c *Config) { c.RefreshJitter = -0.1 }, wantErr: true},
+		{name: "refresh jitter above half", mutate: func(c *Config) { c.RefreshJitter = 0.6 }, wantErr: true},
+		{name: "refresh jitter at half", mutate: func(c *Config) { c.RefreshJitter = 0.5 }, wantErr: false},
 		{name: "zero min payment", mutate: func(c *Config) { c.MinPaymentDrops = 0 }, wantErr: true},
 		{name: "zero transaction buffer size", mutate: func(c *Config) { c.TransactionBufferSize = 0 }, wantErr: true},
 		{name: "zero geo enrichment queue size", mutate: func(c *Config) { c.GeoEnrichmentQSize = 0 }, wantErr: true},
 		{name: "zero geo enrichment workers", mutate: func(c *Config) { c.GeoEnrichmentWorkers = 0 }, wantErr: true},
 		{name: "zero max geo candidates", mutate: func(c *Config) { c.MaxGeoCandidates = 0 }, wantErr: true},
+		{name: "proposed transactions with ledger stream", mutate: func(c *Config) { c.TransactionStreams = []string{"transactions_proposed", "ledger"} }, wantErr: false},
+		{name: "unknown transaction stream", mutate: func(c *Config) { c.TransactionStreams = []string{"transactions", "peer_status"} }, wantErr: true},
+		{name: "no transaction stream", mutate: func(c *Config) { c.TransactionStreams = []string{"ledger"} }, wantErr: true},
+		{name: "both transaction streams", mutate: func(c *Config) { c.TransactionStreams = []string{"transactions", "transactions_proposed"} }, wantErr: true},
+		{name: "preview with proposed transactions", mutate: func(c *Config) {
+			c.TransactionPreview = true
+			c.TransactionStreams = []string{"transactions_proposed"}
+		}, wantErr: false},
+		{name: "preview without proposed transactions", mutate: func(c *Config) { c.TransactionPreview = true }, wantErr: true},
+		{name: "no allowed tx results", mutate: func(c *Config) { c.AllowedTxResults = nil }, wantErr: true},
+		{name: "tec wildcard tx result", mutate: func(c *Config) { c.AllowedTxResults = []string{"tesSUCCESS", "tec*"} }, wantErr: false},
+		{name: "invalid tx result", mutate: func(c *Config) { c.AllowedTxResults = []string{"success"} }, wantErr: true},
 		{name: "zero broadcast buffer size", mutate: func(c *Config) { c.BroadcastBufferSize = 0 }, wantErr: true},
 		{name: "zero ws client buffer size", mutate: func(c *Config) { c.WSClientBufferSize = 0 }, wantErr: true},
 	}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// appDirName names the per-user data directory.
+const appDirName = "xrpl-validator-service"
+
+// legacyDataDir is the relative directory used before DATA_DIR existed.
+// It is still preferred when present so existing checkouts and the Docker
+// volume keep their caches.
+const legacyDataDir = "data"
+
+// defaultDataDir returns the directory caches and downloads live in when
+// DATA_DIR is unset: ./data if it already exists, otherwise the platform's
+// per-user data directory (XDG_DATA_HOME or ~/.local/share on Unix,
+// %APPDATA% on Windows, ~/Library/Application Support on macOS).
+func defaultDataDir() string {
+	if info, err := os.Stat(legacyDataDir); err == nil && info.IsDir() {
+		return legacyDataDir
+	}
+	if dir := userDataDir(runtime.GOOS, os.Getenv, os.UserHomeDir); dir != "" {
+		return filepath.Join(dir, appDirName)
+	}
+	return legacyDataDir
+}
+
+// userDataDir returns the base per-user data directory for goos, or "" if
+// it cannot be determined.
+func userDataDir(goos string, getenv func(string) string, homeDir func() (string, error)) string {
+	switch goos {
+	case "windows":
+		if dir := getenv("APPDATA"); dir != "" {
+			return dir
+		}
+		if dir := getenv("LOCALAPPDATA"); dir != "" {
+			return dir
+		}
+	case "darwin", "ios":
+		if home, err := homeDir(); err == nil && home != "" {
+			return filepath.Join(home, "Library", "Application Support")
+		}
+	default:
+		if dir := getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+			return dir
+		}
+		if home, err := homeDir(); err == nil && home != "" {
+			return filepath.Join(home, ".local", "share")
+		}
+	}
+	return ""
+}
+
+// normalizePath expands a leading ~, converts forward slashes to the
+// platform separator and cleans the result. Empty paths stay empty so
+// validation can reject them.
+func normalizePath(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ""
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + path[1:]
+		}
+	}
+	return filepath.Clean(filepath.FromSlash(path))
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUserDataDir(t *testing.T) {
+	env := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+	xdg := t.TempDir()
+	home := func() (string, error) { return "/home/pi", nil }
+	noHome := func() (string, error) { return "", errors.New("no home") }
+
+	tests := []struct {
+		name   string
+		goos   string
+		getenv func(string) string
+		home   func() (string, error)
+		want   string
+	}{
+		{name: "linux xdg", goos: "linux", getenv: env(map[string]string{"XDG_DATA_HOME": xdg}), home: home, want: xdg},
+		{name: "linux relative xdg ignored", goos: "linux", getenv: env(map[string]string{"XDG_DATA_HOME": "xdg"}), home: home, want: filepath.Join("/home/pi", ".local", "share")},
+		{name: "linux home", goos: "linux", getenv: env(nil), home: home, want: filepath.Join("/home/pi", ".local", "share")},
+		{name: "windows appdata", goos: "windows", getenv: env(map[string]string{"APPDATA": `C:\Users\pi\AppData\Roaming`}), home: home, want: `C:\Users\pi\AppData\Roaming`},
+		{name: "windows local appdata", goos: "windows", getenv: env(map[string]string{"LOCALAPPDATA": `C:\Users\pi\AppData\Local`}), home: home, want: `C:\Users\pi\AppData\Local`},
+		{name: "darwin", goos: "darwin", getenv: env(nil), home: home, want: filepath.Join("/home/pi", "Library", "Application Support")},
+		{name: "unknown", goos: "linux", getenv: env(nil), home: noHome, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userDataDir(tt.goos, tt.getenv, tt.home); got != tt.want {
+				t.Errorf("userDataDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultDataDirPrefersExistingDataDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "/srv/xdg")
+	t.Setenv("APPDATA", `C:\AppData`)
+
+	if dir := defaultDataDir(); dir == legacyDataDir {
+		t.Fatalf("expected a per-user data dir without ./data, got %q", dir)
+	}
+	if err := os.Mkdir(legacyDataDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if dir := defaultDataDir(); dir != legacyDataDir {
+		t.Fatalf("expected existing ./data to be kept, got %q", dir)
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	tests := map[string]string{
+		"":                        "",
+		"  ":                      "",
+		"data/geo.json":           filepath.Join("data", "geo.json"),
+		"./data//cache/../a.json": filepath.Join("data", "a.json"),
+		"~":                       home,
+		"~/xrpl/geo.json":         filepath.Join(home, "xrpl", "geo.json"),
+		"~other/geo.json":         filepath.Join("~other", "geo.json"),
+	}
+	for input, want := range tests {
+		if got := normalizePath(input); got != want {
+			t.Errorf("normalizePath(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestNewConfigDerivesPathsFromDataDir(t *testing.T) {
+	t.Setenv("DATA_DIR", "/var/lib/xrpl//service/")
+
+	cfg := NewConfig()
+	want := filepath.Clean("/var/lib/xrpl/service")
+	if cfg.DataDir != want {
+		t.Fatalf("expected DataDir %q, got %q", want, cfg.DataDir)
+	}
+	if cfg.GeoCachePath != filepath.Join(want, "geolocation-cache.json") ||
+		cfg.GeoLiteDBPath != filepath.Join(want, "GeoLite2-City.mmdb") ||
+		cfg.ValidatorMetadataCachePath != filepath.Join(want, "validator-metadata-cache.json") {
+		t.Fatalf("expected paths under DataDir, got %q, %q, %q", cfg.GeoCachePath, cfg.GeoLiteDBPath, cfg.ValidatorMetadataCachePath)
+	}
+}
@@ -0,0 +1,188 @@
+// Package debugcapture keeps redacted samples of upstream payloads that the
+// parsers could not handle, so parser gaps can be fixed from production
+// captures instead of guesses. Captures are rate limited per source and the
+// directory is bounded, so a persistently malformed feed cannot fill the
+// disk.
+package debugcapture
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+// Capture sources.
+const (
+	SourceTransaction   = "transaction"
+	SourceValidatorList = "validator_list"
+)
+
+const (
+	// maxStringLength truncates long string values, such as blobs an
+	// upstream added under an unknown key.
+	maxStringLength = 256
+
+	redacted = "[redacted]"
+)
+
+// redactedKeys are dropped from captures: signatures and signed blobs carry
+// no parsing information, and memos are free text written by users.
+var redactedKeys = map[string]struct{}{
+	"TxnSignature": {},
+	"Signers":      {},
+	"Memos":        {},
+	"signature":    {},
+	"blob":         {},
+	"manifest":     {},
+}
+
+// Sample is a captured payload as written to disk.
+type Sample struct {
+	Source     string      `json:"source"`
+	Reason     string      `json:"reason"`
+	CapturedAt int64       `json:"captured_at"` // unix milliseconds
+	Payload    interface{} `json:"payload"`
+}
+
+// Capturer writes samples to a directory. A nil Capturer captures nothing,
+// so callers need not check whether capture is enabled.
+type Capturer struct {
+	dir      string
+	maxFiles int
+	interval time.Duration
+	clock    clock.Clock
+	logger   *logrus.Logger
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// New creates a Capturer that keeps at most maxFiles samples in dir and
+// writes at most one per source each interval.
+func New(dir string, maxFiles int, interval time.Duration, clk clock.Clock, logger *logrus.Logger) *Capturer {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &Capturer{
+		dir:      dir,
+		maxFiles: maxFiles,
+		interval: interval,
+		clock:    clock.OrReal(clk),
+		logger:   logger,
+		last:     make(map[string]time.Time),
+	}
+}
+
+// Capture writes a redacted copy of payload with the reason it could not be
+// parsed, unless source was captured within the interval. It reports
+// whether a sample was written.
+func (c *Capturer) Capture(source string, reason error, payload interface{}) bool {
+	if c == nil {
+		return false
+	}
+	now := c.clock.Now()
+	c.mu.Lock()
+	if last, ok := c.last[source]; ok && now.Sub(last) < c.interval {
+		c.mu.Unlock()
+		metrics.DebugCapturesTotal.WithLabelValues(source, "rate_limited").Inc()
+		return false
+	}
+	c.last[source] = now
+	c.mu.Unlock()
+
+	sample := &Sample{Source: source, CapturedAt: now.UnixMilli(), Payload: Redact(payload)}
+	if reason != nil {
+		sample.Reason = reason.Error()
+	}
+	if err := c.write(sample, now); err != nil {
+		metrics.DebugCapturesTotal.WithLabelValues(source, "error").Inc()
+		c.logger.WithError(err).WithField("source", source).Warn("Failed to write debug capture")
+		return false
+	}
+	metrics.DebugCapturesTotal.WithLabelValues(source, "written").Inc()
+	return true
+}
+
+// write stores sample under a name that sorts by capture time and removes
+// the oldest samples beyond maxFiles. Writes are serialized so that pruning
+// sees every file.
+func (c *Capturer) write(sample *Sample, now time.Time) error {
+	data, err := json.MarshalIndent(sample, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%019d-%s.json", now.UnixNano(), sample.Source)
+	if err := os.WriteFile(filepath.Join(c.dir, name), data, 0o644); err != nil {
+		return err
+	}
+	return c.prune()
+}
+
+// prune removes the oldest samples beyond maxFiles. The caller holds c.mu.
+func (c *Capturer) prune() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	var samples []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			samples = append(samples, entry.Name())
+		}
+	}
+	if len(samples) <= c.maxFiles {
+		return nil
+	}
+	sort.Strings(samples)
+	for _, name := range samples[:len(samples)-c.maxFiles] {
+		if err := os.Remove(filepath.Join(c.dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Redact returns a copy of a decoded JSON value with signatures, signed
+// blobs and memos replaced and long strings truncated. The shape of the
+// value, which is what a parser fix needs, is kept.
+func Redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			if _, ok := redactedKeys[key]; ok {
+				out[key] = redacted
+				continue
+			}
+			out[key] = Redact(field)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = Redact(item)
+		}
+		return out
+	case string:
+		if len(v) > maxStringLength {
+			return v[:maxStringLength] + "..."
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,91 @@
+package debugcapture
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+)
+
+func TestCaptureRateLimitsAndBoundsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	capturer := New(dir, 2, time.Minute, fake, nil)
+	payload := map[string]interface{}{"type": "transaction"}
+
+	if !capturer.Capture(SourceTransaction, errors.New("missing transaction payload"), payload) {
+		t.Fatal("expected the first sample to be written")
+	}
+	if capturer.Capture(SourceTransaction, errors.New("again"), payload) {
+		t.Fatal("expected a second sample within the interval to be dropped")
+	}
+	if !capturer.Capture(SourceValidatorList, errors.New("no validators field"), payload) {
+		t.Fatal("expected sources to be rate limited separately")
+	}
+
+	fake.Advance(time.Minute)
+	if !capturer.Capture(SourceTransaction, errors.New("later"), payload) {
+		t.Fatal("expected a sample after the interval")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected the directory bounded to 2 samples, got %d", len(entries))
+	}
+	// The oldest sample went first.
+	data, err := os.ReadFile(filepath.Join(dir, entries[1].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sample Sample
+	if err := json.Unmarshal(data, &sample); err != nil {
+		t.Fatalf("invalid sample: %v", err)
+	}
+	if sample.Source != SourceTransaction || sample.Reason != "later" || sample.CapturedAt != fake.Now().UnixMilli() {
+		t.Fatalf("unexpected newest sample %+v", sample)
+	}
+}
+
+func TestRedactKeepsShape(t *testing.T) {
+	long := strings.Repeat("A", 1000)
+	redactedValue := Redact(map[string]interface{}{
+		"transaction": map[string]interface{}{
+			"Account":      "rAlice",
+			"TxnSignature": "3045...",
+			"Memos":        []interface{}{map[string]interface{}{"Memo": map[string]interface{}{"MemoData": "6869"}}},
+			"Unknown":      long,
+		},
+		"list":         []interface{}{map[string]interface{}{"manifest": "JAAA", "domain": "a.example"}},
+		"ledger_index": float64(85234121),
+	}).(map[string]interface{})
+
+	tx := redactedValue["transaction"].(map[string]interface{})
+	if tx["Account"] != "rAlice" || tx["TxnSignature"] != redacted || tx["Memos"] != redacted {
+		t.Fatalf("unexpected redaction %v", tx)
+	}
+	if unknown := tx["Unknown"].(string); len(unknown) != maxStringLength+3 {
+		t.Fatalf("expected long strings truncated, got %d characters", len(unknown))
+	}
+	entry := redactedValue["list"].([]interface{})[0].(map[string]interface{})
+	if entry["manifest"] != redacted || entry["domain"] != "a.example" {
+		t.Fatalf("unexpected list entry %v", entry)
+	}
+	if redactedValue["ledger_index"] != float64(85234121) {
+		t.Fatalf("expected numbers kept, got %v", redactedValue["ledger_index"])
+	}
+}
+
+func TestNilCapturerIsDisabled(t *testing.T) {
+	var capturer *Capturer
+	if capturer.Capture(SourceTransaction, errors.New("ignored"), nil) {
+		t.Fatal("expected a nil capturer to write nothing")
+	}
+}
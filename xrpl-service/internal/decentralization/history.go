@@ -0,0 +1,296 @@
+// Package decentralization records periodic snapshots of how validators are
+// spread across countries, AS numbers and operators, so that the service can
+// answer how the network has changed between two dates.
+package decentralization
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/cachefile"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// checkInterval is how often History checks whether a snapshot is due. It
+// bounds how late a snapshot is taken after a restart.
+const checkInterval = time.Minute
+
+const historyFileVersion = 1
+
+// historyFileFormat upgrades older history files on load. When bumping
+// historyFileVersion, register a migration from the previous version here.
+var historyFileFormat = cachefile.Format{
+	Name:       "decentralization history",
+	Version:    historyFileVersion,
+	Migrations: map[int]cachefile.MigrateFunc{},
+}
+
+type historyFile struct {
+	Version   int                                `json:"version"`
+	Snapshots []*models.DecentralizationSnapshot `json:"snapshots"`
+}
+
+// ErrNoSnapshots is returned by Compare before the first snapshot is taken.
+var ErrNoSnapshots = errors.New("no decentralization snapshots recorded")
+
+// ValidatorSource provides the current validator set and when it was last
+// fetched.
+type ValidatorSource interface {
+	GetValidators() []*models.Validator
+	GetLastUpdate() time.Time
+}
+
+// History takes a snapshot of the validator set every interval, keeps those
+// newer than the retention in a file, and compares snapshots.
+type History struct {
+	source    ValidatorSource
+	asns      validator.ASNProvider
+	path      string
+	interval  time.Duration
+	retention time.Duration
+	clock     clock.Clock
+	logger    *logrus.Logger
+
+	mu        sync.Mutex
+	snapshots []*models.DecentralizationSnapshot // oldest first
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewHistory creates a History persisted at path and loads its earlier
+// snapshots. asns may be nil, leaving AS number counts empty.
+func NewHistory(source ValidatorSource, asns validator.ASNProvider, path string, interval, retention time.Duration, clk clock.Clock, logger *logrus.Logger) *History {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	h := &History{
+		source:    source,
+		asns:      asns,
+		path:      path,
+		interval:  interval,
+		retention: retention,
+		clock:     clock.OrReal(clk),
+		logger:    logger,
+		stopChan:  make(chan struct{}),
+	}
+	h.load()
+	return h
+}
+
+// Start takes snapshots in the background. The first is taken once the
+// validator set has been fetched, unless a stored one is recent enough.
+func (h *History) Start(ctx context.Context) {
+	go func() {
+		ticker := h.clock.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		h.recordIfDue()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.stopChan:
+				return
+			case <-ticker.C():
+				h.recordIfDue()
+			}
+		}
+	}()
+}
+
+// Stop stops taking snapshots.
+func (h *History) Stop() {
+	h.stopOnce.Do(func() {
+		close(h.stopChan)
+	})
+}
+
+// recordIfDue stores a snapshot when the validator set has been fetched and
+// the last snapshot is at least an interval old, and reports whether it did.
+func (h *History) recordIfDue() bool {
+	if h.source.GetLastUpdate().IsZero() {
+		return false
+	}
+	now := h.clock.Now()
+	h.mu.Lock()
+	due := len(h.snapshots) == 0 || now.Sub(time.Unix(h.snapshots[len(h.snapshots)-1].Timestamp, 0)) >= h.interval
+	h.mu.Unlock()
+	if !due {
+		return false
+	}
+
+	snapshot := h.Current()
+	h.mu.Lock()
+	h.snapshots = append(h.snapshots, snapshot)
+	cutoff := now.Add(-h.retention).Unix()
+	for len(h.snapshots) > 1 && h.snapshots[0].Timestamp < cutoff {
+		h.snapshots = h.snapshots[1:]
+	}
+	err := h.persist()
+	h.mu.Unlock()
+	if err != nil {
+		h.logger.WithError(err).WithField("path", h.path).Warn("Failed to persist decentralization history")
+	}
+	h.logger.WithFields(logrus.Fields{
+		"validators": snapshot.Validators,
+		"countries":  len(snapshot.Countries),
+		"operators":  len(snapshot.Operators),
+	}).Info("Recorded decentralization snapshot")
+	return true
+}
+
+// Current returns a snapshot of the current validator set.
+func (h *History) Current() *models.DecentralizationSnapshot {
+	snapshot := &models.DecentralizationSnapshot{
+		Timestamp: h.clock.Now().Unix(),
+		Countries: make(map[string]int),
+		ASNs:      make(map[string]int),
+		Operators: make(map[string]int),
+	}
+	for _, v := range h.source.GetValidators() {
+		if v == nil {
+			continue
+		}
+		snapshot.Validators++
+		if v.CountryCode != "" && v.CountryCode != "XX" {
+			snapshot.Countries[v.CountryCode]++
+		}
+		operator := v.Operator
+		if operator == "" {
+			operator = v.Address
+		}
+		snapshot.Operators[operator]++
+		if h.asns != nil && v.Domain != "" {
+			asn, err := h.asns.ResolveDomainASN(v.Domain)
+			if err != nil {
+				h.logger.WithError(err).WithField("domain", v.Domain).Debug("Failed to resolve validator domain ASN")
+			}
+			if asn != 0 {
+				snapshot.ASNs[fmt.Sprintf("AS%d", asn)]++
+			}
+		}
+	}
+	return snapshot
+}
+
+// Compare returns the changes between the snapshots taken at from and to. A
+// zero to compares with the current validator set. Each side uses the last
+// snapshot taken at or before its time, or the first one when none was.
+func (h *History) Compare(from, to time.Time) (*models.DecentralizationComparison, error) {
+	fromSnapshot := h.at(from)
+	if fromSnapshot == nil {
+		return nil, ErrNoSnapshots
+	}
+	toSnapshot, live := h.Current(), true
+	if !to.IsZero() {
+		toSnapshot, live = h.at(to), false
+	}
+	return &models.DecentralizationComparison{
+		From:      models.DecentralizationPoint{Timestamp: fromSnapshot.Timestamp, Validators: fromSnapshot.Validators},
+		To:        models.DecentralizationPoint{Timestamp: toSnapshot.Timestamp, Validators: toSnapshot.Validators, Live: live},
+		Countries: deltas(fromSnapshot.Countries, toSnapshot.Countries),
+		ASNs:      deltas(fromSnapshot.ASNs, toSnapshot.ASNs),
+		Operators: deltas(fromSnapshot.Operators, toSnapshot.Operators),
+	}, nil
+}
+
+// at returns the last snapshot taken at or before t, the first snapshot if
+// t predates them all, or nil without snapshots.
+func (h *History) at(t time.Time) *models.DecentralizationSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.snapshots) == 0 {
+		return nil
+	}
+	i := sort.Search(len(h.snapshots), func(i int) bool { return h.snapshots[i].Timestamp > t.Unix() })
+	if i == 0 {
+		return h.snapshots[0]
+	}
+	return h.snapshots[i-1]
+}
+
+// deltas returns the change of every key in either count, largest changes
+// first.
+func deltas(from, to map[string]int) []*models.DecentralizationDelta {
+	out := make([]*models.DecentralizationDelta, 0, len(to))
+	for key, count := range to {
+		out = append(out, &models.DecentralizationDelta{Key: key, From: from[key], To: count, Change: count - from[key]})
+	}
+	for key, count := range from {
+		if _, ok := to[key]; !ok {
+			out = append(out, &models.DecentralizationDelta{Key: key, From: count, Change: -count})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := abs(out[i].Change), abs(out[j].Change)
+		if a != b {
+			return a > b
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func (h *History) load() {
+	data, migratedFrom, err := historyFileFormat.Load(h.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			h.logger.WithError(err).WithField("path", h.path).Warn("Failed to load decentralization history")
+		}
+		return
+	}
+	if migratedFrom != 0 {
+		h.logger.WithFields(logrus.Fields{
+			"path":   h.path,
+			"from":   migratedFrom,
+			"to":     historyFileVersion,
+			"backup": cachefile.BackupPath(h.path, migratedFrom),
+		}).Info("Migrated decentralization history")
+	}
+	var file historyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		h.logger.WithError(err).WithField("path", h.path).Warn("Failed to parse decentralization history")
+		return
+	}
+	snapshots := make([]*models.DecentralizationSnapshot, 0, len(file.Snapshots))
+	for _, snapshot := range file.Snapshots {
+		if snapshot != nil {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Timestamp < snapshots[j].Timestamp })
+
+	h.mu.Lock()
+	h.snapshots = snapshots
+	h.mu.Unlock()
+	h.logger.WithFields(logrus.Fields{
+		"path":      h.path,
+		"snapshots": len(snapshots),
+	}).Info("Loaded decentralization history")
+}
+
+// persist writes the snapshots to the history file. The caller holds h.mu.
+func (h *History) persist() error {
+	data, err := json.MarshalIndent(historyFile{Version: historyFileVersion, Snapshots: h.snapshots}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return historyFileFormat.Write(h.path, data)
+}
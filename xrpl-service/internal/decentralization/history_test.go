@@ -0,0 +1,98 @@
+package decentralization
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+type fakeValidatorSet struct {
+	validators []*models.Validator
+	lastUpdate time.Time
+}
+
+func (f *fakeValidatorSet) GetValidators() []*models.Validator { return f.validators }
+func (f *fakeValidatorSet) GetLastUpdate() time.Time           { return f.lastUpdate }
+
+type fakeASNs map[string]uint
+
+func (f fakeASNs) ResolveDomainASN(domain string) (uint, error) { return f[domain], nil }
+
+func TestHistoryRecordsDueSnapshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decentralization-history.json")
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	set := &fakeValidatorSet{}
+	history := NewHistory(set, nil, path, 24*time.Hour, 3*24*time.Hour, fake, nil)
+
+	if history.recordIfDue() {
+		t.Fatal("expected no snapshot before the first fetch")
+	}
+	set.lastUpdate = fake.Now()
+	set.validators = []*models.Validator{{Address: "nA1", CountryCode: "US", Operator: "a.example"}}
+	if !history.recordIfDue() {
+		t.Fatal("expected the first snapshot once validators are fetched")
+	}
+	fake.Advance(time.Hour)
+	if history.recordIfDue() {
+		t.Fatal("expected no snapshot within the interval")
+	}
+	for day := 0; day < 4; day++ {
+		fake.Advance(24 * time.Hour)
+		if !history.recordIfDue() {
+			t.Fatalf("expected a snapshot on day %d", day+1)
+		}
+	}
+
+	// Snapshots older than the retention are dropped, and the rest survive
+	// a restart.
+	reloaded := NewHistory(set, nil, path, 24*time.Hour, 3*24*time.Hour, fake, nil)
+	if len(reloaded.snapshots) != 4 {
+		t.Fatalf("expected 4 retained snapshots, got %d", len(reloaded.snapshots))
+	}
+	if first := time.Unix(reloaded.snapshots[0].Timestamp, 0); fake.Now().Sub(first) > 3*24*time.Hour {
+		t.Fatalf("expected snapshots within the retention, oldest at %s", first)
+	}
+}
+
+func TestHistoryCompare(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	set := &fakeValidatorSet{lastUpdate: fake.Now(), validators: []*models.Validator{
+		{Address: "nA1", Domain: "a.example", CountryCode: "US", Operator: "a.example"},
+		{Address: "nA2", Domain: "b.example", CountryCode: "US", Operator: "b.example"},
+	}}
+	asns := fakeASNs{"a.example": 64500, "b.example": 64501, "c.example": 64501}
+	history := NewHistory(set, asns, filepath.Join(t.TempDir(), "history.json"), 24*time.Hour, 365*24*time.Hour, fake, nil)
+
+	if _, err := history.Compare(time.Time{}, time.Time{}); !errors.Is(err, ErrNoSnapshots) {
+		t.Fatalf("expected ErrNoSnapshots, got %v", err)
+	}
+	history.recordIfDue()
+
+	fake.Advance(48 * time.Hour)
+	set.validators = []*models.Validator{
+		{Address: "nA2", Domain: "b.example", CountryCode: "US", Operator: "b.example"},
+		{Address: "nA3", Domain: "c.example", CountryCode: "DE", Operator: "c.example"},
+		{Address: "nA4", CountryCode: "DE"},
+	}
+
+	comparison, err := history.Compare(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	if comparison.From.Validators != 2 || comparison.To.Validators != 3 || !comparison.To.Live {
+		t.Fatalf("unexpected points %+v %+v", comparison.From, comparison.To)
+	}
+	if d := comparison.Countries; len(d) != 2 || d[0].Key != "DE" || d[0].Change != 2 || d[1].Key != "US" || d[1].Change != -1 {
+		t.Fatalf("unexpected country deltas %+v %+v", d[0], d[1])
+	}
+	if d := comparison.ASNs; len(d) != 2 || d[0].Key != "AS64500" || d[0].Change != -1 || d[1].Key != "AS64501" || d[1].Change != 1 {
+		t.Fatalf("unexpected ASN deltas %+v", d)
+	}
+	if len(comparison.Operators) != 4 {
+		t.Fatalf("expected deltas for 4 operators, got %+v", comparison.Operators)
+	}
+}
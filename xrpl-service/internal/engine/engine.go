@@ -0,0 +1,372 @@
+// Package engine starts the service's ingestion and enrichment pipeline:
+// the validator fetcher and transaction listener against XRPL nodes, with
+// the collectors that share their clients, or mirrors of another instance
+// in replica mode. It runs without the HTTP server, which the binary and
+// embedding programs add on top.
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/compliance"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/config"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/debugcapture"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/ingestion"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/issuers"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/labels"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/peers"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/replica"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/rules"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/server"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/stats"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/transaction"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/budget"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/geolocation"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+// Engine is a running pipeline.
+type Engine struct {
+	Validators   server.ValidatorSource
+	Transactions server.TransactionSource
+
+	// Set only when ingesting from XRPL rather than a replica upstream.
+	Watchlist     *compliance.Watchlist // nil without WATCHLIST_PATH
+	Labels        *labels.Store         // nil with an empty LABELS_PATH
+	ASNs          validator.ASNProvider // resolves validator domains to AS numbers
+	GeoDB         server.GeoDBInfo      // the GeoLite DB used for geolocation
+	Burn          *stats.BurnTracker
+	Distributions *stats.Distributions
+	Ingestion     *ingestion.Controller
+	Peers         *peers.Collector   // nil without PEERS_ADMIN_JSON_RPC_URL
+	Issuers       *issuers.Collector // nil without ISSUER_ACCOUNTS
+
+	stop func(context.Context)
+}
+
+// Start starts the pipeline described by cfg, which must be valid. The
+// pipeline runs until ctx is canceled or Stop is called. processors run
+// after the configured ones; a replica pipeline, whose transactions were
+// processed upstream, does not take any.
+func Start(ctx context.Context, cfg *config.Config, logger *logrus.Logger, processors ...transaction.Processor) (*Engine, error) {
+	if cfg.ReplicaUpstreamURL != "" {
+		if len(processors) > 0 {
+			return nil, fmt.Errorf("transaction processors are not supported with a replica upstream")
+		}
+		return startReplica(ctx, cfg, logger)
+	}
+	return startXRPL(ctx, cfg, logger, processors)
+}
+
+// Stop stops the pipeline and closes its upstream clients.
+func (e *Engine) Stop(ctx context.Context) {
+	e.stop(ctx)
+}
+
+// InstanceID is INSTANCE_ID, or the host name, which is unique per
+// container in most deployments.
+func InstanceID(cfg *config.Config) string {
+	if cfg.InstanceID != "" {
+		return cfg.InstanceID
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// startXRPL starts the validator fetcher and transaction listener against
+// XRPL nodes, plus the peer and issuer graph collectors when configured.
+// The components that query upstream are added to the ingestion
+// controller.
+func startXRPL(ctx context.Context, cfg *config.Config, logger *logrus.Logger, processors []transaction.Processor) (*Engine, error) {
+	e := &Engine{
+		Burn:          stats.NewBurnTracker(),
+		Distributions: stats.NewDistributions(),
+		Ingestion:     ingestion.NewController(logger),
+	}
+	if cfg.WatchlistPath != "" {
+		watchlist, err := compliance.LoadWatchlist(cfg.WatchlistPath, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load compliance watchlist: %w", err)
+		}
+		logger.WithFields(logrus.Fields{
+			"countries": watchlist.Status().Countries,
+			"accounts":  watchlist.Status().Accounts,
+		}).Info("Compliance watchlist loaded")
+		e.Watchlist = watchlist
+	}
+	if cfg.LabelsPath != "" {
+		store, err := labels.Load(cfg.LabelsPath, nil, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load account labels: %w", err)
+		}
+		logger.WithField("labels", len(store.List())).Info("Account labels loaded")
+		e.Labels = store
+	}
+	var ruleEngine *rules.Engine
+	if len(cfg.EnrichmentRules) > 0 {
+		var err error
+		ruleEngine, err = rules.NewEngine(cfg.EnrichmentRules, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile enrichment rules: %w", err)
+		}
+		logger.WithField("rules", ruleEngine.Len()).Info("Enrichment rules loaded")
+	}
+
+	// Redacted samples of payloads the parsers reject, when enabled.
+	var debugCapture *debugcapture.Capturer
+	if cfg.DebugCaptureDir != "" {
+		debugCapture = debugcapture.New(cfg.DebugCaptureDir, cfg.DebugCaptureMaxFiles, time.Duration(cfg.DebugCaptureInterval)*time.Second, nil, logger)
+		logger.WithField("dir", cfg.DebugCaptureDir).Info("Debug capture of unparseable upstream payloads enabled")
+	}
+
+	// Subsystems calling the same hosts share one budget per host.
+	var budgets *budget.Manager
+	if len(cfg.OutboundBudgets) > 0 {
+		budgets = budget.NewManager(cfg.OutboundBudgets, nil)
+		logger.WithField("destinations", len(cfg.OutboundBudgets)).Info("Outbound request budgets enabled")
+	}
+	clientOptions := func(subsystem string) xrpl.ClientOptions {
+		return xrpl.ClientOptions{
+			DNSRefreshInterval: time.Duration(cfg.XRPLDNSRefreshInterval) * time.Second,
+			Budget:             budgets,
+			Subsystem:          subsystem,
+			MessageBufferSize:  cfg.XRPLMessageBufferSize,
+			DecodeWorkers:      cfg.XRPLDecodeWorkers,
+			MaxMessageBytes:    int64(cfg.XRPLMaxMessageBytes),
+			MaxMessageDepth:    cfg.XRPLMaxMessageDepth,
+		}
+	}
+
+	// A GeoLite DB that cannot be opened degrades geolocation rather than
+	// failing startup, unless retries are disabled.
+	resolverConfig := geolocation.ResolverConfig{
+		CachePath:          cfg.GeoCachePath,
+		GeoLiteDBPath:      cfg.GeoLiteDBPath,
+		GeoLiteDownloadURL: cfg.GeoLiteDownloadURL,
+		AutoDownload:       cfg.GeoLiteAutoDownload,
+		ASNDBPath:          cfg.GeoLiteASNDBPath,
+		DisableGeoLite:     !cfg.GeoLiteEnabled,
+		Providers:          cfg.GeoProviders,
+		Budget:             budgets,
+	}
+	var geoResolver *geolocation.Resolver
+	var err error
+	if cfg.GeoLiteInitRetryInterval > 0 {
+		geoResolver = geolocation.NewResolverWithRetry(logger, resolverConfig, time.Duration(cfg.GeoLiteInitRetryInterval)*time.Second)
+	} else {
+		geoResolver, err = geolocation.NewResolver(logger, resolverConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize GeoLite resolver: %w", err)
+		}
+	}
+	geoResolver.StartGeoLiteRefresh(refreshSchedule(cfg, time.Duration(cfg.GeoLiteRefreshInterval)*time.Second))
+
+	validatorClient := xrpl.NewClient(cfg.PublicXRPLJSONRPCURL, cfg.PublicXRPLWebSocketURL, logger, clientOptions(budget.SubsystemFetcher))
+	txClient := xrpl.NewClient(cfg.TransactionJSONRPCURL, cfg.TransactionWebSocketURL, logger, clientOptions(budget.SubsystemEnrichment))
+
+	// An optional second DB confirms large validator moves.
+	var geoConfirmer validator.GeoLocationProvider
+	var confirmResolver *geolocation.Resolver
+	if cfg.GeoConfirmDBPath != "" && !geolocation.GeoLiteBuiltIn {
+		logger.Warn("Built without GeoLite support (nogeolite); ignoring GEO_CONFIRM_DB_PATH, large validator moves will be rejected")
+	} else if cfg.GeoConfirmDBPath != "" {
+		confirmResolver, err = geolocation.NewResolver(logger, geolocation.ResolverConfig{
+			CachePath:     cfg.GeoConfirmCachePath,
+			GeoLiteDBPath: cfg.GeoConfirmDBPath,
+		})
+		if err != nil {
+			logger.WithError(err).Warn("Failed to open confirming geolocation DB; large validator moves will be rejected")
+		} else {
+			geoConfirmer = confirmResolver
+		}
+	}
+
+	// Create validator fetcher
+	fetchSchedule := refreshSchedule(cfg, time.Duration(cfg.ValidatorRefreshInterval)*time.Second)
+	logger.WithFields(logrus.Fields{
+		"jitter": fetchSchedule.Jitter,
+		"splay":  fetchSchedule.Splay.String(),
+	}).Info("Validator refresh schedule")
+	validatorFetcher := validator.NewFetcher(
+		validatorClient,
+		time.Duration(cfg.ValidatorRefreshInterval)*time.Second,
+		geoResolver,
+		cfg.ValidatorListSites,
+		cfg.SecondaryValidatorRegistryURL,
+		cfg.ValidatorMetadataCachePath,
+		cfg.NetworkHealthJSONRPCURLs,
+		cfg.NetworkHealthRetries,
+		cfg.Network,
+		logger,
+		validator.FetcherOptions{
+			RefreshJitter:      fetchSchedule.Jitter,
+			RefreshSplay:       fetchSchedule.Splay,
+			GeoConfirmer:       geoConfirmer,
+			LocationLockTTL:    time.Duration(cfg.LocationLockTTLDays) * 24 * time.Hour,
+			PeerProbeInterval:  time.Duration(cfg.PeerProbeInterval) * time.Second,
+			PublisherKeyChains: cfg.ValidatorListPublisherKeys,
+			ListExpiryWarning:  time.Duration(cfg.ListExpiryWarningDays) * 24 * time.Hour,
+			AttributionSites:   cfg.UNLPresetSites(),
+			ASNProvider:        geoResolver,
+			Budget:             budgets,
+			DebugCapture:       debugCapture,
+		},
+	)
+	validatorFetcher.Start(ctx)
+	e.ASNs = geoResolver
+	e.GeoDB = geoResolver
+
+	// Create transaction listener
+	transactionListener := transaction.NewListener(
+		txClient,
+		cfg.MinPaymentDrops,
+		geoResolver,
+		logger,
+		transaction.ListenerOptions{
+			TransactionBufferSize: cfg.TransactionBufferSize,
+			GeoEnrichmentQSize:    cfg.GeoEnrichmentQSize,
+			GeoWorkerCount:        cfg.GeoEnrichmentWorkers,
+			ShardByAccount:        cfg.GeoEnrichmentSharding,
+			MaxGeoCandidates:      cfg.MaxGeoCandidates,
+			AllowedResults:        cfg.AllowedTxResults,
+			FilterPreviewWindow:   time.Duration(cfg.FilterPreviewWindow) * time.Second,
+			Streams:               cfg.TransactionStreams,
+			Preview:               cfg.TransactionPreview,
+			Reconnect:             cfg.ReconnectPolicy(),
+			DebugCapture:          debugCapture,
+		},
+	)
+	transactionListener.AddLedgerFeeCallback(e.Burn.ObserveLedger)
+	transactionListener.AddShapeCallback(e.Distributions.Observe)
+	if e.Watchlist != nil {
+		transactionListener.AddProcessor(e.Watchlist)
+	}
+	// Labels run before the rules so that rules can match on them.
+	if e.Labels != nil {
+		transactionListener.AddProcessor(e.Labels)
+	}
+	if ruleEngine != nil {
+		transactionListener.AddProcessor(ruleEngine)
+	}
+	var txProcessor *transaction.ExecProcessor
+	if cfg.TxProcessorCommand != "" {
+		txProcessor = transaction.NewExecProcessor(
+			strings.Fields(cfg.TxProcessorCommand),
+			time.Duration(cfg.TxProcessorTimeoutMS)*time.Millisecond,
+			logger,
+		)
+		transactionListener.AddProcessor(txProcessor)
+	}
+	for _, processor := range processors {
+		transactionListener.AddProcessor(processor)
+	}
+	if err := transactionListener.Start(ctx); err != nil {
+		metrics.ValidatorFetchTotal.WithLabelValues("error").Inc() // Note: reusing for listener start
+		logger.WithError(err).Error("Failed to start transaction listener")
+	}
+
+	// Create peer collector when a local admin endpoint is configured
+	if cfg.PeersAdminJSONRPCURL != "" {
+		peersClient := xrpl.NewClient(cfg.PeersAdminJSONRPCURL, "", logger, xrpl.ClientOptions{Budget: budgets, Subsystem: budget.SubsystemPeers})
+		e.Peers = peers.NewCollector(peersClient, geoResolver, time.Minute, nil, logger)
+	}
+
+	// Create issuer trust line graph collector
+	if len(cfg.IssuerAccounts) > 0 {
+		issuersClient := xrpl.NewClient(cfg.PublicXRPLJSONRPCURL, "", logger, xrpl.ClientOptions{Budget: budgets, Subsystem: budget.SubsystemIssuers})
+		e.Issuers = issuers.NewCollector(
+			issuersClient,
+			geoResolver,
+			cfg.IssuerAccounts,
+			time.Duration(cfg.IssuerGraphRefreshInterval)*time.Second,
+			cfg.IssuerGraphTopHolders,
+			logger,
+		)
+		e.Issuers.Start(ctx)
+	}
+
+	e.Ingestion.Add(transactionListener, validatorFetcher)
+	if e.Issuers != nil {
+		e.Ingestion.Add(e.Issuers)
+	}
+
+	e.Validators = validatorFetcher
+	e.Transactions = transactionListener
+	e.stop = func(shutdownCtx context.Context) {
+		if err := transactionListener.Stop(shutdownCtx); err != nil {
+			logger.WithError(err).Error("Error stopping transaction listener")
+		}
+		validatorFetcher.Stop()
+		if e.Issuers != nil {
+			e.Issuers.Stop()
+		}
+		if txProcessor != nil {
+			txProcessor.Close()
+		}
+
+		// Close XRPL clients
+		if err := validatorClient.Close(); err != nil {
+			logger.WithError(err).Error("Error closing validator source client")
+		}
+		if err := txClient.Close(); err != nil {
+			logger.WithError(err).Error("Error closing transaction source client")
+		}
+		if err := geoResolver.Close(); err != nil {
+			logger.WithError(err).Warn("Error closing GeoLite resolver")
+		}
+		if confirmResolver != nil {
+			if err := confirmResolver.Close(); err != nil {
+				logger.WithError(err).Warn("Error closing confirming geolocation resolver")
+			}
+		}
+	}
+	return e, nil
+}
+
+// refreshSchedule spaces a periodic upstream refresh with REFRESH_JITTER
+// and, when REFRESH_SPLAY is set, an offset derived from the instance
+// identity, so instances sharing an interval do not refresh together.
+func refreshSchedule(cfg *config.Config, interval time.Duration) clock.Schedule {
+	schedule := clock.Schedule{Interval: interval, Jitter: cfg.RefreshJitter}
+	if cfg.RefreshSplay {
+		schedule.Splay = clock.SplayFor(InstanceID(cfg), interval)
+	}
+	return schedule
+}
+
+// startReplica mirrors another instance's REST API and transaction stream
+// instead of talking to XRPL, so edge replicas add no upstream XRPL load.
+func startReplica(ctx context.Context, cfg *config.Config, logger *logrus.Logger) (*Engine, error) {
+	logger.WithField("upstream", cfg.ReplicaUpstreamURL).Info("Running in replica mode")
+
+	replicaStream, err := replica.NewStream(cfg.ReplicaUpstreamURL, cfg.ReplicaOrigin, cfg.ReplicaAPIKey, logger, replica.StreamOptions{
+		Reconnect: cfg.ReconnectPolicy(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replica transaction stream: %w", err)
+	}
+	replicaValidators := replica.NewValidators(
+		cfg.ReplicaUpstreamURL,
+		time.Duration(cfg.ValidatorRefreshInterval)*time.Second,
+		logger,
+	)
+	replicaValidators.Start(ctx)
+	replicaStream.Start(ctx)
+
+	return &Engine{
+		Validators:   replicaValidators,
+		Transactions: replicaStream,
+		stop: func(context.Context) {
+			replicaStream.Stop()
+			replicaValidators.Stop()
+		},
+	}, nil
+}
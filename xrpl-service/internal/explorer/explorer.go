@@ -0,0 +1,162 @@
+// Package explorer builds block explorer deep links for transactions and
+// validators from URL templates, so every frontend and consumer of the
+// service links to the same pages.
+package explorer
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// Template placeholders.
+const (
+	placeholderHash      = "{hash}"
+	placeholderAccount   = "{account}"
+	placeholderLedger    = "{ledger}"
+	placeholderValidator = "{validator}"
+)
+
+// Presets are the mainnet explorers selectable by name. Other networks
+// need custom templates.
+var Presets = map[string]models.Explorer{
+	"xrpscan": {
+		Name:        "xrpscan",
+		Transaction: "https://xrpscan.com/tx/{hash}",
+		Account:     "https://xrpscan.com/account/{account}",
+		Ledger:      "https://xrpscan.com/ledger/{ledger}",
+		Validator:   "https://xrpscan.com/validator/{validator}",
+	},
+	"bithomp": {
+		Name:        "bithomp",
+		Transaction: "https://bithomp.com/explorer/{hash}",
+		Account:     "https://bithomp.com/explorer/{account}",
+		Ledger:      "https://bithomp.com/ledger/{ledger}",
+	},
+	"livenet": {
+		Name:        "livenet",
+		Transaction: "https://livenet.xrpl.org/transactions/{hash}",
+		Account:     "https://livenet.xrpl.org/accounts/{account}",
+		Ledger:      "https://livenet.xrpl.org/ledgers/{ledger}",
+		Validator:   "https://livenet.xrpl.org/network/validators/{validator}",
+	},
+}
+
+// Resolve returns the explorers named, in order, taking each from custom
+// or else the presets.
+func Resolve(names []string, custom map[string]models.Explorer) ([]models.Explorer, error) {
+	explorers := make([]models.Explorer, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return nil, fmt.Errorf("explorer %s is listed more than once", name)
+		}
+		seen[name] = true
+		explorer, ok := custom[name]
+		if !ok {
+			if explorer, ok = Presets[name]; !ok {
+				return nil, fmt.Errorf("unknown explorer %s: not a preset or custom template", name)
+			}
+		}
+		explorer.Name = name
+		if err := Validate(explorer); err != nil {
+			return nil, err
+		}
+		explorers = append(explorers, explorer)
+	}
+	return explorers, nil
+}
+
+// Validate checks that e links at least one kind of page and that each of
+// its templates is an absolute http(s) URL with its placeholder.
+func Validate(e models.Explorer) error {
+	templates := []struct{ kind, template, placeholder string }{
+		{"transaction", e.Transaction, placeholderHash},
+		{"account", e.Account, placeholderAccount},
+		{"ledger", e.Ledger, placeholderLedger},
+		{"validator", e.Validator, placeholderValidator},
+	}
+	linked := false
+	for _, t := range templates {
+		if t.template == "" {
+			continue
+		}
+		linked = true
+		if !strings.Contains(t.template, t.placeholder) {
+			return fmt.Errorf("explorer %s %s template must contain %s", e.Name, t.kind, t.placeholder)
+		}
+		parsed, err := url.Parse(strings.ReplaceAll(t.template, t.placeholder, "x"))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("explorer %s %s template must be an http(s) URL: %s", e.Name, t.kind, t.template)
+		}
+	}
+	if !linked {
+		return fmt.Errorf("explorer %s has no templates", e.Name)
+	}
+	return nil
+}
+
+// Linker fills explorer links in. A nil Linker links nothing.
+type Linker struct {
+	explorers []models.Explorer
+}
+
+// NewLinker returns a Linker for explorers, or nil when there are none.
+func NewLinker(explorers []models.Explorer) *Linker {
+	if len(explorers) == 0 {
+		return nil
+	}
+	return &Linker{explorers: explorers}
+}
+
+// Transaction returns tx's links. Account links are left out unless
+// accounts is set, so truncated privacy mode addresses are not linked.
+func (l *Linker) Transaction(tx *models.Transaction, accounts bool) []*models.ExplorerLink {
+	if l == nil || tx == nil {
+		return nil
+	}
+	links := make([]*models.ExplorerLink, 0, len(l.explorers))
+	for _, e := range l.explorers {
+		link := &models.ExplorerLink{
+			Explorer:    e.Name,
+			Transaction: expand(e.Transaction, placeholderHash, tx.Hash),
+		}
+		if tx.LedgerIndex > 0 {
+			link.Ledger = expand(e.Ledger, placeholderLedger, strconv.FormatUint(uint64(tx.LedgerIndex), 10))
+		}
+		if accounts {
+			link.Account = expand(e.Account, placeholderAccount, tx.Account)
+			link.Destination = expand(e.Account, placeholderAccount, tx.Destination)
+		}
+		if *link != (models.ExplorerLink{Explorer: e.Name}) {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// Validator returns the links to the validator at address.
+func (l *Linker) Validator(address string) []*models.ExplorerLink {
+	if l == nil || address == "" {
+		return nil
+	}
+	var links []*models.ExplorerLink
+	for _, e := range l.explorers {
+		if validator := expand(e.Validator, placeholderValidator, address); validator != "" {
+			links = append(links, &models.ExplorerLink{Explorer: e.Name, Validator: validator})
+		}
+	}
+	return links
+}
+
+// expand substitutes value for placeholder in template, or returns "" when
+// either is empty.
+func expand(template, placeholder, value string) string {
+	if template == "" || value == "" {
+		return ""
+	}
+	return strings.ReplaceAll(template, placeholder, url.PathEscape(value))
+}
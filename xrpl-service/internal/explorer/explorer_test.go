@@ -0,0 +1,94 @@
+package explorer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestResolvePresetsAndCustomTemplates(t *testing.T) {
+	explorers, err := Resolve([]string{"testnet", "xrpscan"}, map[string]models.Explorer{
+		"testnet": {Transaction: "https://testnet.xrpl.org/transactions/{hash}"},
+	})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if len(explorers) != 2 || explorers[0].Name != "testnet" || explorers[1].Validator == "" {
+		t.Fatalf("expected the custom template then the xrpscan preset, got %+v", explorers)
+	}
+
+	for _, tc := range []struct {
+		names  []string
+		custom map[string]models.Explorer
+		want   string
+	}{
+		{names: []string{"nope"}, want: "unknown explorer"},
+		{names: []string{"xrpscan", "xrpscan"}, want: "more than once"},
+		{names: []string{"empty"}, custom: map[string]models.Explorer{"empty": {}}, want: "no templates"},
+		{names: []string{"bad"}, custom: map[string]models.Explorer{"bad": {Transaction: "https://example.com/tx/{account}"}}, want: "must contain {hash}"},
+		{names: []string{"bad"}, custom: map[string]models.Explorer{"bad": {Account: "javascript:{account}"}}, want: "http(s) URL"},
+	} {
+		if _, err := Resolve(tc.names, tc.custom); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Resolve(%v): expected error containing %q, got %v", tc.names, tc.want, err)
+		}
+	}
+}
+
+func TestPresetsAreValid(t *testing.T) {
+	for name, preset := range Presets {
+		if preset.Name != name {
+			t.Errorf("preset %s is named %s", name, preset.Name)
+		}
+		if err := Validate(preset); err != nil {
+			t.Errorf("preset %s: %v", name, err)
+		}
+	}
+}
+
+func TestLinkerTransaction(t *testing.T) {
+	linker := NewLinker([]models.Explorer{Presets["xrpscan"], Presets["bithomp"]})
+	tx := &models.Transaction{
+		Hash:        "ABC123",
+		LedgerIndex: 90000000,
+		Account:     "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		Destination: "rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY",
+	}
+
+	links := linker.Transaction(tx, true)
+	if len(links) != 2 {
+		t.Fatalf("expected links for both explorers, got %d", len(links))
+	}
+	want := models.ExplorerLink{
+		Explorer:    "xrpscan",
+		Transaction: "https://xrpscan.com/tx/ABC123",
+		Account:     "https://xrpscan.com/account/rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		Destination: "https://xrpscan.com/account/rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY",
+		Ledger:      "https://xrpscan.com/ledger/90000000",
+	}
+	if *links[0] != want {
+		t.Fatalf("unexpected xrpscan links %+v", links[0])
+	}
+
+	links = linker.Transaction(tx, false)
+	if links[1].Account != "" || links[1].Destination != "" || links[1].Transaction != "https://bithomp.com/explorer/ABC123" {
+		t.Fatalf("expected only transaction and ledger links without accounts, got %+v", links[1])
+	}
+}
+
+func TestLinkerValidator(t *testing.T) {
+	linker := NewLinker([]models.Explorer{Presets["bithomp"], Presets["livenet"]})
+	links := linker.Validator("nHUpJSKQTZdB1TDkbCREMuf8vEqFkk84BcvZDhsQsDufFDQVajam")
+	if len(links) != 1 || links[0].Explorer != "livenet" ||
+		links[0].Validator != "https://livenet.xrpl.org/network/validators/nHUpJSKQTZdB1TDkbCREMuf8vEqFkk84BcvZDhsQsDufFDQVajam" {
+		t.Fatalf("expected only the livenet validator page, got %+v", links)
+	}
+
+	var disabled *Linker
+	if disabled.Validator("n9") != nil || disabled.Transaction(&models.Transaction{Hash: "A"}, true) != nil {
+		t.Fatal("expected a nil linker to link nothing")
+	}
+	if NewLinker(nil) != nil {
+		t.Fatal("expected no linker without explorers")
+	}
+}
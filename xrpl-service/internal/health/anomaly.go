@@ -0,0 +1,364 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Anomaly metrics.
+const (
+	MetricTxRate           = "tx_rate"
+	MetricAvgPaymentXRP    = "avg_payment_xrp"
+	MetricValidatorCount   = "validator_count"
+	MetricGeoConcentration = "geo_concentration"
+)
+
+const (
+	// anomalyAlpha is the EWMA smoothing factor; each window moves the
+	// average a tenth of the way towards the new sample.
+	anomalyAlpha = 0.1
+
+	// anomalyWarmupSamples is how many windows a metric is observed before
+	// it is scored.
+	anomalyWarmupSamples = 10
+
+	// anomalyMinRelativeStdDev floors the standard deviation at a share of
+	// the mean, so a metric that has never moved, such as a stable
+	// validator count, does not alert on the first small change.
+	anomalyMinRelativeStdDev = 0.05
+
+	// minGeoConcentrationSamples is how many located transaction endpoints a
+	// window needs before its geographic concentration is scored.
+	minGeoConcentrationSamples = 10
+
+	dropsPerXRP = 1_000_000
+)
+
+// ValidatorCounter provides the current validator set.
+type ValidatorCounter interface {
+	GetValidators() []*models.Validator
+}
+
+// AnomalyCallback receives anomalies as they start and clear.
+type AnomalyCallback func(*models.Anomaly)
+
+// ewma tracks an exponentially weighted moving mean and variance.
+type ewma struct {
+	mean     float64
+	variance float64
+	samples  int
+}
+
+func (e *ewma) update(value float64) {
+	if e.samples == 0 {
+		e.mean = value
+		e.samples = 1
+		return
+	}
+	diff := value - e.mean
+	increment := anomalyAlpha * diff
+	e.mean += increment
+	e.variance = (1 - anomalyAlpha) * (e.variance + diff*increment)
+	e.samples++
+}
+
+func (e *ewma) stdDev() float64 {
+	return math.Max(math.Sqrt(e.variance), math.Max(anomalyMinRelativeStdDev*math.Abs(e.mean), 1e-9))
+}
+
+// anomalySample is one window's value of a metric.
+type anomalySample struct {
+	metric  string
+	value   float64
+	context map[string]interface{}
+}
+
+// AnomalyDetector scores transaction rate, average payment size, validator
+// count and geographic concentration once per window against their moving
+// averages and reports values whose z-score reaches the threshold.
+type AnomalyDetector struct {
+	validators ValidatorCounter
+	window     time.Duration
+	threshold  float64
+	logger     *logrus.Logger
+	now        func() time.Time
+
+	mu           sync.Mutex
+	transactions int64
+	payments     int64
+	paymentDrops int64
+	countries    map[string]int
+	located      int
+	stats        map[string]*ewma
+	active       map[string]*models.Anomaly
+	paused       bool
+	callbacks    []AnomalyCallback
+	stopChan     chan struct{}
+	stopOnce     sync.Once
+}
+
+// NewAnomalyDetector creates a detector that samples every window and
+// alerts at |z| >= threshold. validators may be nil to skip the validator
+// count.
+func NewAnomalyDetector(validators ValidatorCounter, window time.Duration, threshold float64, logger *logrus.Logger) *AnomalyDetector {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &AnomalyDetector{
+		validators: validators,
+		window:     window,
+		threshold:  threshold,
+		logger:     logger,
+		now:        time.Now,
+		countries:  make(map[string]int),
+		stats:      make(map[string]*ewma),
+		active:     make(map[string]*models.Anomaly),
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// AddCallback registers a callback for anomalies.
+func (d *AnomalyDetector) AddCallback(callback AnomalyCallback) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.callbacks = append(d.callbacks, callback)
+}
+
+// ObserveTransaction adds a broadcast transaction to the current window. It
+// is registered as a transaction callback.
+func (d *AnomalyDetector) ObserveTransaction(tx *models.Transaction) {
+	if tx == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.transactions++
+	for _, location := range tx.Locations {
+		if location != nil && location.CountryCode != "" {
+			d.countries[location.CountryCode]++
+			d.located++
+		}
+	}
+	if tx.TransactionType != "Payment" {
+		return
+	}
+	// Issued currency amounts carry no XRP value and are left out of the
+	// average.
+	if drops, err := strconv.ParseInt(tx.Amount, 10, 64); err == nil {
+		d.payments++
+		d.paymentDrops += drops
+	}
+}
+
+// Start begins sampling once per window.
+func (d *AnomalyDetector) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.window)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-d.stopChan:
+				return
+			case <-ticker.C:
+				d.Evaluate()
+			}
+		}
+	}()
+}
+
+// Pause stops scoring while ingestion is deliberately paused, so the empty
+// windows neither alert nor drag the moving averages down.
+func (d *AnomalyDetector) Pause(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.paused = true
+	return nil
+}
+
+// Resume scores windows again, starting with a fresh one.
+func (d *AnomalyDetector) Resume(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.paused = false
+	d.closeWindow(-1)
+	return nil
+}
+
+// Stop stops sampling.
+func (d *AnomalyDetector) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stopChan)
+	})
+}
+
+// Evaluate closes the current window, scores its samples and notifies
+// callbacks of anomalies that started or cleared.
+func (d *AnomalyDetector) Evaluate() {
+	d.mu.Lock()
+	if d.paused {
+		d.closeWindow(-1)
+		d.mu.Unlock()
+		return
+	}
+	d.mu.Unlock()
+
+	now := d.now()
+	validatorCount := -1
+	if d.validators != nil {
+		validatorCount = len(d.validators.GetValidators())
+	}
+
+	d.mu.Lock()
+	samples := d.closeWindow(validatorCount)
+	var anomalies []*models.Anomaly
+	for _, sample := range samples {
+		if anomaly := d.score(sample, now); anomaly != nil {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	callbacks := make([]AnomalyCallback, len(d.callbacks))
+	copy(callbacks, d.callbacks)
+	d.mu.Unlock()
+
+	for _, anomaly := range anomalies {
+		entry := d.logger.WithFields(logrus.Fields{
+			"metric":  anomaly.Metric,
+			"z_score": anomaly.ZScore,
+		})
+		if anomaly.Active {
+			entry.Warn("Anomaly: " + anomaly.Message)
+		} else {
+			entry.Info("Anomaly cleared")
+		}
+		for _, callback := range callbacks {
+			callback(anomaly)
+		}
+	}
+}
+
+// Active returns the anomalies that have not cleared, ordered by metric.
+func (d *AnomalyDetector) Active() []*models.Anomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]*models.Anomaly, 0, len(d.active))
+	for _, anomaly := range d.active {
+		copy := *anomaly
+		out = append(out, &copy)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Metric < out[j].Metric })
+	return out
+}
+
+// closeWindow turns the window's counters into samples and resets them. A
+// negative validatorCount skips that metric. The caller holds d.mu.
+func (d *AnomalyDetector) closeWindow(validatorCount int) []anomalySample {
+	samples := []anomalySample{{
+		metric:  MetricTxRate,
+		value:   float64(d.transactions) / d.window.Seconds(),
+		context: map[string]interface{}{"transactions": d.transactions},
+	}}
+	if d.payments > 0 {
+		samples = append(samples, anomalySample{
+			metric:  MetricAvgPaymentXRP,
+			value:   float64(d.paymentDrops) / float64(d.payments) / dropsPerXRP,
+			context: map[string]interface{}{"payments": d.payments},
+		})
+	}
+	if validatorCount >= 0 {
+		samples = append(samples, anomalySample{metric: MetricValidatorCount, value: float64(validatorCount)})
+	}
+	if d.located >= minGeoConcentrationSamples {
+		topCountry, topCount := "", 0
+		for country, count := range d.countries {
+			if count > topCount || (count == topCount && country < topCountry) {
+				topCountry, topCount = country, count
+			}
+		}
+		samples = append(samples, anomalySample{
+			metric: MetricGeoConcentration,
+			value:  float64(topCount) / float64(d.located),
+			context: map[string]interface{}{
+				"top_country":       topCountry,
+				"located_endpoints": d.located,
+			},
+		})
+	}
+
+	d.transactions = 0
+	d.payments = 0
+	d.paymentDrops = 0
+	d.countries = make(map[string]int)
+	d.located = 0
+	return samples
+}
+
+// score folds sample into its moving average and returns an anomaly when
+// the metric starts or stops being anomalous. The caller holds d.mu.
+func (d *AnomalyDetector) score(sample anomalySample, now time.Time) *models.Anomaly {
+	stats, ok := d.stats[sample.metric]
+	if !ok {
+		stats = &ewma{}
+		d.stats[sample.metric] = stats
+	}
+	scored := stats.samples >= anomalyWarmupSamples
+	mean, stdDev := stats.mean, stats.stdDev()
+	stats.update(sample.value)
+	if !scored {
+		return nil
+	}
+
+	z := (sample.value - mean) / stdDev
+	metrics.AnomalyZScore.WithLabelValues(sample.metric).Set(z)
+	// An active anomaly clears below half the threshold so a metric hovering
+	// around it does not flap.
+	previous := d.active[sample.metric]
+	clearBelow := d.threshold
+	if previous != nil {
+		clearBelow = d.threshold / 2
+	}
+	anomalous := math.Abs(z) >= clearBelow
+	if anomalous == (previous != nil) {
+		return nil
+	}
+
+	anomaly := &models.Anomaly{
+		Metric:        sample.metric,
+		Active:        anomalous,
+		Value:         sample.value,
+		Mean:          mean,
+		StdDev:        stdDev,
+		ZScore:        z,
+		WindowSeconds: int(d.window.Seconds()),
+		DetectedAt:    now.Unix(),
+		Context:       sample.context,
+	}
+	if !anomalous {
+		anomaly.Direction = previous.Direction
+		anomaly.DetectedAt = previous.DetectedAt
+		anomaly.Message = fmt.Sprintf("%s is back within its usual range", sample.metric)
+		delete(d.active, sample.metric)
+		return anomaly
+	}
+
+	anomaly.Direction = "spike"
+	if z < 0 {
+		anomaly.Direction = "drop"
+	}
+	anomaly.Message = fmt.Sprintf("%s %s: %.4g against a moving average of %.4g (z=%.1f)", sample.metric, anomaly.Direction, sample.value, mean, z)
+	metrics.AnomaliesTotal.WithLabelValues(sample.metric, anomaly.Direction).Inc()
+	stored := *anomaly
+	d.active[sample.metric] = &stored
+	return anomaly
+}
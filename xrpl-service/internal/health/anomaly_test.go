@@ -0,0 +1,114 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+type fakeValidatorCounter struct{ count int }
+
+func (f *fakeValidatorCounter) GetValidators() []*models.Validator {
+	return make([]*models.Validator, f.count)
+}
+
+func observePayments(d *AnomalyDetector, n int, amount string) {
+	for i := 0; i < n; i++ {
+		d.ObserveTransaction(&models.Transaction{TransactionType: "Payment", Amount: amount})
+	}
+}
+
+func TestAnomalyDetectorFlagsRateSpikeAndClears(t *testing.T) {
+	detector := NewAnomalyDetector(nil, time.Minute, 3, nil)
+	var anomalies []*models.Anomaly
+	detector.AddCallback(func(anomaly *models.Anomaly) { anomalies = append(anomalies, anomaly) })
+
+	for i := 0; i < anomalyWarmupSamples+5; i++ {
+		observePayments(detector, 60+i%3, "2000000")
+		detector.Evaluate()
+	}
+	if len(anomalies) != 0 {
+		t.Fatalf("expected no anomalies for steady traffic, got %+v", anomalies[0])
+	}
+
+	observePayments(detector, 600, "2000000")
+	detector.Evaluate()
+	if len(anomalies) != 1 {
+		t.Fatalf("expected one anomaly, got %d", len(anomalies))
+	}
+	spike := anomalies[0]
+	if spike.Metric != MetricTxRate || !spike.Active || spike.Direction != "spike" || spike.ZScore < 3 || spike.Context["transactions"] != int64(600) {
+		t.Fatalf("unexpected anomaly %+v", spike)
+	}
+	if active := detector.Active(); len(active) != 1 || active[0].Metric != MetricTxRate {
+		t.Fatalf("expected the anomaly to be active, got %+v", active)
+	}
+
+	observePayments(detector, 600, "2000000")
+	detector.Evaluate()
+	if len(anomalies) != 1 {
+		t.Fatalf("expected no repeat while the anomaly lasts, got %+v", anomalies[1:])
+	}
+
+	for i := 0; i < 5 && len(anomalies) == 1; i++ {
+		observePayments(detector, 61, "2000000")
+		detector.Evaluate()
+	}
+	if len(anomalies) != 2 || anomalies[1].Active || anomalies[1].DetectedAt != spike.DetectedAt {
+		t.Fatalf("expected the anomaly to clear, got %+v", anomalies)
+	}
+	if len(detector.Active()) != 0 {
+		t.Fatal("expected no active anomalies after clearing")
+	}
+}
+
+func TestAnomalyDetectorStableValidatorCountTolerance(t *testing.T) {
+	validators := &fakeValidatorCounter{count: 35}
+	detector := NewAnomalyDetector(validators, time.Minute, 3, nil)
+	var anomalies []*models.Anomaly
+	detector.AddCallback(func(anomaly *models.Anomaly) { anomalies = append(anomalies, anomaly) })
+
+	for i := 0; i < anomalyWarmupSamples; i++ {
+		detector.Evaluate()
+	}
+	validators.count = 34
+	detector.Evaluate()
+	if len(anomalies) != 0 {
+		t.Fatalf("expected one validator leaving not to alert, got %+v", anomalies[0])
+	}
+
+	validators.count = 20
+	detector.Evaluate()
+	if len(anomalies) != 1 || anomalies[0].Metric != MetricValidatorCount || anomalies[0].Direction != "drop" {
+		t.Fatalf("expected a validator_count drop, got %+v", anomalies)
+	}
+}
+
+func TestAnomalyDetectorGeoConcentration(t *testing.T) {
+	detector := NewAnomalyDetector(nil, time.Minute, 3, nil)
+	var anomalies []*models.Anomaly
+	detector.AddCallback(func(anomaly *models.Anomaly) {
+		if anomaly.Metric == MetricGeoConcentration {
+			anomalies = append(anomalies, anomaly)
+		}
+	})
+
+	countries := []string{"US", "JP", "DE", "GB", "SG"}
+	for i := 0; i < anomalyWarmupSamples+2; i++ {
+		for _, country := range countries {
+			for j := 0; j < 4; j++ {
+				detector.ObserveTransaction(&models.Transaction{Locations: []*models.GeoLocation{{CountryCode: country}}})
+			}
+		}
+		detector.Evaluate()
+	}
+
+	for j := 0; j < 20; j++ {
+		detector.ObserveTransaction(&models.Transaction{Locations: []*models.GeoLocation{{CountryCode: "KR"}}})
+	}
+	detector.Evaluate()
+	if len(anomalies) != 1 || anomalies[0].Value != 1 || anomalies[0].Context["top_country"] != "KR" {
+		t.Fatalf("expected a geo_concentration spike towards KR, got %+v", anomalies)
+	}
+}
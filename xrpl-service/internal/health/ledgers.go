@@ -0,0 +1,78 @@
+package health
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// LedgerHistory summarizes a server's complete_ledgers string.
+type LedgerHistory struct {
+	Ranges       []models.LedgerRange
+	Span         int64
+	OldestLedger uint32
+	NewestLedger uint32
+	Gaps         []models.LedgerRange
+}
+
+// ParseCompleteLedgers parses a complete_ledgers value such as
+// "32570-62000000,62000005-62000010" into sorted, merged ranges with the
+// missing ledgers between them reported as gaps.
+func ParseCompleteLedgers(raw string) LedgerHistory {
+	ranges := make([]models.LedgerRange, 0)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "empty" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		start, err := strconv.ParseUint(strings.TrimSpace(bounds[0]), 10, 32)
+		if err != nil {
+			continue
+		}
+		end := start
+		if len(bounds) == 2 {
+			if end, err = strconv.ParseUint(strings.TrimSpace(bounds[1]), 10, 32); err != nil {
+				continue
+			}
+		}
+		if end < start {
+			continue
+		}
+		ranges = append(ranges, models.LedgerRange{Start: uint32(start), End: uint32(end)})
+	}
+
+	history := LedgerHistory{}
+	if len(ranges) == 0 {
+		return history
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	merged := []models.LedgerRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if uint64(r.Start) <= uint64(last.End)+1 {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	history.Ranges = merged
+	history.OldestLedger = merged[0].Start
+	history.NewestLedger = merged[len(merged)-1].End
+	for i, r := range merged {
+		history.Span += int64(r.End) - int64(r.Start) + 1
+		if i > 0 {
+			history.Gaps = append(history.Gaps, models.LedgerRange{
+				Start: merged[i-1].End + 1,
+				End:   r.Start - 1,
+			})
+		}
+	}
+	return history
+}
@@ -0,0 +1,41 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestParseCompleteLedgers(t *testing.T) {
+	tests := []struct {
+		raw    string
+		span   int64
+		oldest uint32
+		gaps   []models.LedgerRange
+	}{
+		{raw: "", span: 0},
+		{raw: "empty", span: 0},
+		{raw: "100-199", span: 100, oldest: 100},
+		{raw: "500,300-300,100-199", span: 102, oldest: 100, gaps: []models.LedgerRange{{Start: 200, End: 299}, {Start: 301, End: 499}}},
+		{raw: "100-150,140-199,200-210", span: 111, oldest: 100},
+		{raw: "garbage,10-12,9-3", span: 3, oldest: 10},
+	}
+	for _, tt := range tests {
+		history := ParseCompleteLedgers(tt.raw)
+		if history.Span != tt.span {
+			t.Errorf("ParseCompleteLedgers(%q).Span = %d, want %d", tt.raw, history.Span, tt.span)
+		}
+		if history.OldestLedger != tt.oldest {
+			t.Errorf("ParseCompleteLedgers(%q).OldestLedger = %d, want %d", tt.raw, history.OldestLedger, tt.oldest)
+		}
+		if len(history.Gaps) != len(tt.gaps) {
+			t.Errorf("ParseCompleteLedgers(%q) gaps = %+v, want %+v", tt.raw, history.Gaps, tt.gaps)
+			continue
+		}
+		for i := range tt.gaps {
+			if history.Gaps[i] != tt.gaps[i] {
+				t.Errorf("ParseCompleteLedgers(%q) gap %d = %+v, want %+v", tt.raw, i, history.Gaps[i], tt.gaps[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPollInterval       = 30 * time.Second
+	defaultLedgerLagThreshold = 10 * time.Second
+	pollTimeout               = 10 * time.Second
+
+	// historyShrinkTolerance is the fraction of complete ledger span that may
+	// disappear between polls (e.g. online deletion) before it is reported.
+	historyShrinkTolerance = 0.1
+)
+
+// StatusSource provides on-demand XRPL server status.
+type StatusSource interface {
+	GetServerStatus(ctx context.Context) (*models.ServerStatus, error)
+}
+
+// StatusChangeCallback receives material server status changes.
+type StatusChangeCallback func(*models.ServerStatusChange)
+
+// Poller periodically polls server status, caches the latest result, and
+// notifies callbacks when server_state, ledger lag, ledger history, or an
+// alert condition changes materially.
+type Poller struct {
+	source             StatusSource
+	logger             *logrus.Logger
+	interval           time.Duration
+	ledgerLagThreshold time.Duration
+	mu                 sync.RWMutex
+	latest             *models.ServerStatus
+	latestAt           time.Time
+	lagging            bool
+	callbacks          []StatusChangeCallback
+	stopChan           chan struct{}
+	stopOnce           sync.Once
+}
+
+// NewPoller creates a new server status poller.
+func NewPoller(source StatusSource, interval time.Duration, ledgerLagThreshold time.Duration, logger *logrus.Logger) *Poller {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	if ledgerLagThreshold <= 0 {
+		ledgerLagThreshold = defaultLedgerLagThreshold
+	}
+	return &Poller{
+		source:             source,
+		logger:             logger,
+		interval:           interval,
+		ledgerLagThreshold: ledgerLagThreshold,
+		callbacks:          make([]StatusChangeCallback, 0),
+		stopChan:           make(chan struct{}),
+	}
+}
+
+// AddCallback registers a callback for material status changes.
+func (p *Poller) AddCallback(callback StatusChangeCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.callbacks = append(p.callbacks, callback)
+}
+
+// Start begins periodic polling.
+func (p *Poller) Start(ctx context.Context) {
+	go func() {
+		p.Poll(ctx)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.stopChan:
+				p.logger.Info("Server status poller stopped")
+				return
+			case <-ticker.C:
+				p.Poll(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops periodic polling.
+func (p *Poller) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopChan)
+	})
+}
+
+// Interval returns the configured polling interval.
+func (p *Poller) Interval() time.Duration {
+	return p.interval
+}
+
+// Poll fetches the current server status once and records it.
+func (p *Poller) Poll(ctx context.Context) {
+	if p.source == nil {
+		return
+	}
+	pollCtx, cancel := context.WithTimeout(ctx, pollTimeout)
+	defer cancel()
+
+	status, err := p.source.GetServerStatus(pollCtx)
+	if err != nil {
+		metrics.ServerStatusPollTotal.WithLabelValues("error").Inc()
+		p.logger.WithError(err).Warn("Server status poll failed")
+		return
+	}
+	metrics.ServerStatusPollTotal.WithLabelValues("success").Inc()
+	p.record(status)
+}
+
+// Latest returns the most recently polled status and when it was polled.
+func (p *Poller) Latest() (*models.ServerStatus, time.Time, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.latest == nil {
+		return nil, time.Time{}, false
+	}
+	copy := *p.latest
+	return &copy, p.latestAt, true
+}
+
+func (p *Poller) record(status *models.ServerStatus) {
+	if status == nil {
+		return
+	}
+	metrics.ServerPeerCount.Set(float64(status.PeerCount))
+	metrics.ServerUptimeSeconds.Set(float64(status.Uptime))
+
+	current := *status
+	if current.CompleteLedgerSpan == 0 && current.CompleteLedgers != "" {
+		history := ParseCompleteLedgers(current.CompleteLedgers)
+		current.CompleteLedgerSpan = history.Span
+		current.OldestLedger = history.OldestLedger
+		current.LedgerGaps = history.Gaps
+	}
+	metrics.ServerCompleteLedgerSpan.Set(float64(current.CompleteLedgerSpan))
+	metrics.ServerOldestLedger.Set(float64(current.OldestLedger))
+	metrics.ServerLedgerGapCount.Set(float64(len(current.LedgerGaps)))
+	metrics.ServerValidatedLedgerAge.Set(float64(current.ValidatedLedgerAge))
+	metrics.ServerCondition.WithLabelValues(ConditionAmendmentBlocked).Set(boolGauge(current.AmendmentBlocked))
+	metrics.ServerCondition.WithLabelValues(ConditionReportingMode).Set(boolGauge(current.ReportingMode))
+
+	lagging := time.Duration(current.ValidatedLedgerAge)*time.Second > p.ledgerLagThreshold
+
+	p.mu.Lock()
+	previous := p.latest
+	previousLagging := p.lagging
+	p.latest = &current
+	p.latestAt = time.Now()
+	p.lagging = lagging
+	callbacks := make([]StatusChangeCallback, len(p.callbacks))
+	copy(callbacks, p.callbacks)
+	p.mu.Unlock()
+
+	reasons := detectChanges(previous, previousLagging, &current, lagging)
+	if len(reasons) == 0 {
+		return
+	}
+	alerts := detectAlerts(previous, &current)
+	for _, alert := range alerts {
+		entry := p.logger.WithFields(logrus.Fields{
+			"condition":    alert.Condition,
+			"server_state": alert.ServerState,
+		})
+		if alert.Active {
+			entry.Error("Server alert: " + alert.Message)
+		} else {
+			entry.Info("Server alert cleared")
+		}
+	}
+	for _, reason := range reasons {
+		metrics.ServerStatusChangesTotal.WithLabelValues(reason).Inc()
+		if reason == "history_shrink" {
+			metrics.ServerLedgerHistoryShrinkTotal.Inc()
+			p.logger.WithFields(logrus.Fields{
+				"previous_span": previous.CompleteLedgerSpan,
+				"current_span":  current.CompleteLedgerSpan,
+			}).Warn("Server ledger history shrank unexpectedly")
+		}
+	}
+
+	change := &models.ServerStatusChange{
+		Current: &current,
+		Reasons: reasons,
+		Lagging: lagging,
+		Alerts:  alerts,
+	}
+	if previous != nil {
+		prevCopy := *previous
+		change.Previous = &prevCopy
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"reasons":      strings.Join(reasons, ","),
+		"server_state": current.ServerState,
+		"ledger_age":   current.ValidatedLedgerAge,
+	}).Info("Server status changed")
+
+	for _, callback := range callbacks {
+		callback(change)
+	}
+}
+
+func detectChanges(previous *models.ServerStatus, previousLagging bool, current *models.ServerStatus, lagging bool) []string {
+	if previous == nil {
+		return []string{"initial"}
+	}
+	reasons := make([]string, 0, 3)
+	if previous.ServerState != current.ServerState {
+		reasons = append(reasons, "server_state")
+	}
+	if previousLagging != lagging {
+		reasons = append(reasons, "ledger_lag")
+	}
+	if historyShrank(previous.CompleteLedgerSpan, current.CompleteLedgerSpan) {
+		reasons = append(reasons, "history_shrink")
+	}
+	if previous.AmendmentBlocked != current.AmendmentBlocked {
+		reasons = append(reasons, ConditionAmendmentBlocked)
+	}
+	if previous.ReportingMode != current.ReportingMode {
+		reasons = append(reasons, ConditionReportingMode)
+	}
+	return reasons
+}
+
+func boolGauge(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func historyShrank(previousSpan, currentSpan int64) bool {
+	if previousSpan <= 0 || currentSpan >= previousSpan {
+		return false
+	}
+	lost := float64(previousSpan - currentSpan)
+	return lost > float64(previousSpan)*historyShrinkTolerance
+}
@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+type stubStatusSource struct {
+	statuses []*models.ServerStatus
+	err      error
+	calls    int
+}
+
+func (s *stubStatusSource) GetServerStatus(ctx context.Context) (*models.ServerStatus, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	idx := s.calls - 1
+	if idx >= len(s.statuses) {
+		idx = len(s.statuses) - 1
+	}
+	return s.statuses[idx], nil
+}
+
+func TestPollEmitsInitialAndMaterialChanges(t *testing.T) {
+	source := &stubStatusSource{
+		statuses: []*models.ServerStatus{
+			{ServerState: "full", ValidatedLedgerAge: 2, PeerCount: 10},
+			{ServerState: "full", ValidatedLedgerAge: 3, PeerCount: 11},
+			{ServerState: "syncing", ValidatedLedgerAge: 3},
+			{ServerState: "syncing", ValidatedLedgerAge: 30},
+		},
+	}
+	poller := NewPoller(source, time.Minute, 10*time.Second, logrus.New())
+
+	var changes []*models.ServerStatusChange
+	poller.AddCallback(func(change *models.ServerStatusChange) {
+		changes = append(changes, change)
+	})
+
+	for i := 0; i < len(source.statuses); i++ {
+		poller.Poll(context.Background())
+	}
+
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 status changes, got %d", len(changes))
+	}
+	if changes[0].Reasons[0] != "initial" || changes[0].Previous != nil {
+		t.Fatalf("expected initial change without previous status, got %+v", changes[0])
+	}
+	if changes[1].Reasons[0] != "server_state" || changes[1].Previous.ServerState != "full" {
+		t.Fatalf("expected server_state change from full, got %+v", changes[1])
+	}
+	if changes[2].Reasons[0] != "ledger_lag" || !changes[2].Lagging {
+		t.Fatalf("expected ledger_lag change into lagging, got %+v", changes[2])
+	}
+
+	latest, _, ok := poller.Latest()
+	if !ok || latest.ValidatedLedgerAge != 30 {
+		t.Fatalf("expected latest status to be cached, got %+v", latest)
+	}
+}
+
+func TestPollErrorKeepsPreviousStatus(t *testing.T) {
+	source := &stubStatusSource{
+		statuses: []*models.ServerStatus{{ServerState: "full"}},
+	}
+	poller := NewPoller(source, time.Minute, 10*time.Second, logrus.New())
+	poller.Poll(context.Background())
+
+	source.err = errors.New("upstream down")
+	poller.Poll(context.Background())
+
+	latest, _, ok := poller.Latest()
+	if !ok || latest.ServerState != "full" {
+		t.Fatalf("expected previous status to remain cached, got %+v", latest)
+	}
+}
+
+func TestPollReportsHistoryShrink(t *testing.T) {
+	source := &stubStatusSource{
+		statuses: []*models.ServerStatus{
+			{ServerState: "full", CompleteLedgers: "1000-1999"},
+			{ServerState: "full", CompleteLedgers: "1050-2049"},
+			{ServerState: "full", CompleteLedgers: "1900-2099"},
+		},
+	}
+	poller := NewPoller(source, time.Minute, 10*time.Second, logrus.New())
+
+	var changes []*models.ServerStatusChange
+	poller.AddCallback(func(change *models.ServerStatusChange) {
+		changes = append(changes, change)
+	})
+	for i := 0; i < len(source.statuses); i++ {
+		poller.Poll(context.Background())
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("expected initial and shrink changes only, got %d", len(changes))
+	}
+	if changes[1].Reasons[0] != "history_shrink" {
+		t.Fatalf("expected history_shrink reason, got %+v", changes[1].Reasons)
+	}
+	if changes[1].Current.CompleteLedgerSpan != 200 || changes[1].Current.OldestLedger != 1900 {
+		t.Fatalf("expected parsed history on current status, got %+v", changes[1].Current)
+	}
+}
+
+func TestPollRaisesAlertsForAmendmentBlockedAndReportingMode(t *testing.T) {
+	source := &stubStatusSource{
+		statuses: []*models.ServerStatus{
+			{Connected: true, ServerState: "full", AmendmentBlocked: true},
+			{Connected: true, ServerState: "full", AmendmentBlocked: true},
+			{Connected: true, ServerState: "full", ReportingMode: true},
+		},
+	}
+	poller := NewPoller(source, time.Minute, 10*time.Second, logrus.New())
+
+	var changes []*models.ServerStatusChange
+	poller.AddCallback(func(change *models.ServerStatusChange) {
+		changes = append(changes, change)
+	})
+	for i := 0; i < len(source.statuses); i++ {
+		poller.Poll(context.Background())
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("expected initial and condition changes, got %d", len(changes))
+	}
+	if alerts := changes[0].Alerts; len(alerts) != 1 || alerts[0].Condition != ConditionAmendmentBlocked || !alerts[0].Active {
+		t.Fatalf("expected active amendment_blocked alert on initial poll, got %+v", alerts)
+	}
+	alerts := changes[1].Alerts
+	if len(alerts) != 2 || alerts[0].Active || !alerts[1].Active || alerts[1].Condition != ConditionReportingMode {
+		t.Fatalf("expected amendment_blocked to clear and reporting_mode to start, got %+v", alerts)
+	}
+	if len(changes[1].Reasons) != 2 || changes[1].Reasons[0] != ConditionAmendmentBlocked {
+		t.Fatalf("expected condition reasons, got %+v", changes[1].Reasons)
+	}
+}
+
+func TestNotReadyReasons(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *models.ServerStatus
+		want   []string
+	}{
+		{name: "full", status: &models.ServerStatus{Connected: true, ServerState: "full"}},
+		{name: "missing", status: nil, want: []string{"disconnected"}},
+		{name: "syncing", status: &models.ServerStatus{Connected: true, ServerState: "syncing"}, want: []string{"server_state:syncing"}},
+		{name: "amendment blocked", status: &models.ServerStatus{Connected: true, ServerState: "full", AmendmentBlocked: true}, want: []string{ConditionAmendmentBlocked}},
+		{name: "reporting", status: &models.ServerStatus{Connected: true, ServerState: "reporting", ReportingMode: true}, want: []string{ConditionReportingMode}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NotReadyReasons(tt.status)
+			if len(got) != len(tt.want) {
+				t.Fatalf("NotReadyReasons() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("NotReadyReasons() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
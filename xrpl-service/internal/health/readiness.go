@@ -0,0 +1,78 @@
+package health
+
+import "github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+
+// Server conditions that raise alerts.
+const (
+	ConditionAmendmentBlocked = "amendment_blocked"
+	ConditionReportingMode    = "reporting_mode"
+)
+
+// readyStates are the server_state values of a server that is following the
+// network closely enough to serve live data.
+var readyStates = map[string]struct{}{
+	"full":       {},
+	"proposing":  {},
+	"validating": {},
+}
+
+// NotReadyReasons returns why status does not describe a ready server, or nil
+// when it does. Amendment-blocked and reporting-mode servers are reported
+// explicitly, since their server_state alone can look healthy.
+func NotReadyReasons(status *models.ServerStatus) []string {
+	if status == nil || !status.Connected {
+		return []string{"disconnected"}
+	}
+	var reasons []string
+	if status.AmendmentBlocked {
+		reasons = append(reasons, ConditionAmendmentBlocked)
+	}
+	if status.ReportingMode {
+		reasons = append(reasons, ConditionReportingMode)
+	}
+	if _, ok := readyStates[status.ServerState]; !ok && !status.ReportingMode {
+		reasons = append(reasons, "server_state:"+status.ServerState)
+	}
+	return reasons
+}
+
+// detectAlerts returns the alert conditions that started or cleared between
+// previous and current. On the first poll only active conditions are reported.
+func detectAlerts(previous, current *models.ServerStatus) []*models.ServerAlert {
+	conditions := []struct {
+		name    string
+		was, is bool
+		message string
+	}{
+		{
+			name:    ConditionAmendmentBlocked,
+			was:     previous != nil && previous.AmendmentBlocked,
+			is:      current.AmendmentBlocked,
+			message: "server is amendment blocked and can no longer follow the network; upgrade rippled",
+		},
+		{
+			name:    ConditionReportingMode,
+			was:     previous != nil && previous.ReportingMode,
+			is:      current.ReportingMode,
+			message: "server is in reporting mode and does not take part in consensus",
+		},
+	}
+
+	var alerts []*models.ServerAlert
+	for _, condition := range conditions {
+		if condition.was == condition.is {
+			continue
+		}
+		alert := &models.ServerAlert{
+			Condition:   condition.name,
+			Active:      condition.is,
+			ServerState: current.ServerState,
+			Message:     condition.message,
+		}
+		if !condition.is {
+			alert.Message = condition.name + " cleared"
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
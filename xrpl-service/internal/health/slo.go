@@ -0,0 +1,196 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// SLO checks.
+const (
+	CheckValidatorCountLow    = "validator_count_low"
+	CheckValidatorCountHigh   = "validator_count_high"
+	CheckValidatorCoverageLow = "validator_coverage_low"
+)
+
+const sloCheckInterval = 30 * time.Second
+
+// SLOBounds are the expected ranges of the validator set. Zero values
+// disable a bound.
+type SLOBounds struct {
+	MinValidators      int
+	MaxValidators      int
+	MinCoveragePercent float64 // share of validators with coordinates
+}
+
+// Enabled reports whether any bound is set.
+func (b SLOBounds) Enabled() bool {
+	return b.MinValidators > 0 || b.MaxValidators > 0 || b.MinCoveragePercent > 0
+}
+
+// ValidatorSetSource provides the current validator set and when it was last
+// fetched.
+type ValidatorSetSource interface {
+	GetValidators() []*models.Validator
+	GetLastUpdate() time.Time
+}
+
+// SLOAlertCallback receives SLO alerts as they start and clear.
+type SLOAlertCallback func(*models.SLOAlert)
+
+// SLOMonitor compares the validator count and mapped coverage with
+// operator-configured bounds, catching silent parser or enrichment
+// regressions that would otherwise only show as a half-empty globe.
+type SLOMonitor struct {
+	source        ValidatorSetSource
+	bounds        SLOBounds
+	checkInterval time.Duration
+	logger        *logrus.Logger
+
+	mu        sync.Mutex
+	active    map[string]bool
+	callbacks []SLOAlertCallback
+	stopChan  chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewSLOMonitor creates a monitor for the given bounds.
+func NewSLOMonitor(source ValidatorSetSource, bounds SLOBounds, logger *logrus.Logger) *SLOMonitor {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &SLOMonitor{
+		source:        source,
+		bounds:        bounds,
+		checkInterval: sloCheckInterval,
+		logger:        logger,
+		active:        make(map[string]bool),
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// AddCallback registers a callback for alerts.
+func (m *SLOMonitor) AddCallback(callback SLOAlertCallback) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callbacks = append(m.callbacks, callback)
+}
+
+// Start begins periodic checks.
+func (m *SLOMonitor) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stopChan:
+				return
+			case <-ticker.C:
+				m.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops periodic checks.
+func (m *SLOMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopChan)
+	})
+}
+
+// Check evaluates the bounds against the current validator set and
+// notifies callbacks of alerts that started or cleared. Nothing is checked
+// before the first successful fetch.
+func (m *SLOMonitor) Check() {
+	if m.source.GetLastUpdate().IsZero() {
+		return
+	}
+	validators := m.source.GetValidators()
+	count, mapped := 0, 0
+	for _, v := range validators {
+		if v == nil {
+			continue
+		}
+		count++
+		if v.Latitude != 0 || v.Longitude != 0 {
+			mapped++
+		}
+	}
+	metrics.ValidatorMappedRatio.Set(ratio(mapped, count))
+
+	var alerts []*models.SLOAlert
+	m.mu.Lock()
+	if bound := m.bounds.MinValidators; bound > 0 {
+		alerts = m.transition(alerts, CheckValidatorCountLow, count < bound, float64(count), float64(bound),
+			fmt.Sprintf("%d validators, expected at least %d", count, bound))
+	}
+	if bound := m.bounds.MaxValidators; bound > 0 {
+		alerts = m.transition(alerts, CheckValidatorCountHigh, count > bound, float64(count), float64(bound),
+			fmt.Sprintf("%d validators, expected at most %d", count, bound))
+	}
+	// An empty set has no coverage to speak of; the count check covers it.
+	if bound := m.bounds.MinCoveragePercent; bound > 0 && count > 0 {
+		coverage := 100 * ratio(mapped, count)
+		alerts = m.transition(alerts, CheckValidatorCoverageLow, coverage < bound, coverage, bound,
+			fmt.Sprintf("%.1f%% of validators mapped (%d of %d), expected at least %g%%", coverage, mapped, count, bound))
+	}
+	callbacks := make([]SLOAlertCallback, len(m.callbacks))
+	copy(callbacks, m.callbacks)
+	m.mu.Unlock()
+
+	for _, alert := range alerts {
+		entry := m.logger.WithField("check", alert.Check)
+		if alert.Active {
+			entry.Error("SLO breached: " + alert.Message)
+		} else {
+			entry.Info("SLO check recovered")
+		}
+		for _, callback := range callbacks {
+			callback(alert)
+		}
+	}
+}
+
+// Breached returns the checks currently outside their bounds.
+func (m *SLOMonitor) Breached() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	checks := []string{}
+	for _, check := range []string{CheckValidatorCountLow, CheckValidatorCountHigh, CheckValidatorCoverageLow} {
+		if m.active[check] {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// transition records the state of check and appends an alert when it
+// changed. The caller holds m.mu.
+func (m *SLOMonitor) transition(alerts []*models.SLOAlert, check string, breached bool, value, bound float64, message string) []*models.SLOAlert {
+	metrics.SLOBreached.WithLabelValues(check).Set(boolGauge(breached))
+	if m.active[check] == breached {
+		return alerts
+	}
+	m.active[check] = breached
+	alert := &models.SLOAlert{Check: check, Active: breached, Value: value, Bound: bound, Message: message}
+	if breached {
+		metrics.SLOAlertsTotal.WithLabelValues(check).Inc()
+	}
+	return append(alerts, alert)
+}
+
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+type fakeValidatorSet struct {
+	validators []*models.Validator
+	lastUpdate time.Time
+}
+
+func (f *fakeValidatorSet) GetValidators() []*models.Validator { return f.validators }
+func (f *fakeValidatorSet) GetLastUpdate() time.Time           { return f.lastUpdate }
+
+func TestSLOMonitorAlertsOutsideBounds(t *testing.T) {
+	set := &fakeValidatorSet{}
+	monitor := NewSLOMonitor(set, SLOBounds{MinValidators: 3, MaxValidators: 4, MinCoveragePercent: 50}, nil)
+	var alerts []*models.SLOAlert
+	monitor.AddCallback(func(alert *models.SLOAlert) { alerts = append(alerts, alert) })
+
+	monitor.Check()
+	if len(alerts) != 0 {
+		t.Fatalf("expected no checks before the first fetch, got %+v", alerts)
+	}
+
+	set.lastUpdate = time.Unix(1_700_000_000, 0)
+	set.validators = []*models.Validator{
+		{Address: "nA1", Latitude: 48.85, Longitude: 2.35},
+		{Address: "nA2"},
+	}
+	monitor.Check()
+	if len(alerts) != 1 || alerts[0].Check != CheckValidatorCountLow || !alerts[0].Active || alerts[0].Value != 2 || alerts[0].Bound != 3 {
+		t.Fatalf("expected validator_count_low, got %+v", alerts)
+	}
+	monitor.Check()
+	if len(alerts) != 1 {
+		t.Fatalf("expected the alert to fire once, got %d", len(alerts))
+	}
+
+	set.validators = append(set.validators, &models.Validator{Address: "nA3"}, &models.Validator{Address: "nA4"})
+	monitor.Check()
+	if len(alerts) != 3 {
+		t.Fatalf("expected a recovery and a coverage alert, got %+v", alerts)
+	}
+	if alerts[1].Check != CheckValidatorCountLow || alerts[1].Active {
+		t.Fatalf("expected validator_count_low to recover, got %+v", alerts[1])
+	}
+	if alerts[2].Check != CheckValidatorCoverageLow || !alerts[2].Active || alerts[2].Value != 25 {
+		t.Fatalf("expected validator_coverage_low at 25%%, got %+v", alerts[2])
+	}
+	if breached := monitor.Breached(); len(breached) != 1 || breached[0] != CheckValidatorCoverageLow {
+		t.Fatalf("expected Breached to report coverage, got %v", breached)
+	}
+
+	set.validators = append(set.validators, &models.Validator{Address: "nA5", Latitude: 1, Longitude: 1})
+	monitor.Check()
+	if len(alerts) != 4 || alerts[3].Check != CheckValidatorCountHigh || !alerts[3].Active {
+		t.Fatalf("expected validator_count_high, got %+v", alerts)
+	}
+}
+
+func TestSLOBoundsEnabled(t *testing.T) {
+	if (SLOBounds{}).Enabled() {
+		t.Fatal("expected zero bounds to be disabled")
+	}
+	if !(SLOBounds{MinCoveragePercent: 80}).Enabled() {
+		t.Fatal("expected a coverage bound to enable the monitor")
+	}
+}
@@ -0,0 +1,240 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Watchdog checks.
+const (
+	CheckTransactionsStalled   = "transactions_stalled"
+	CheckValidatorFetchStalled = "validator_fetch_stalled"
+)
+
+const (
+	watchdogCheckInterval = 10 * time.Second
+
+	// fetchStallFactor is how many refresh intervals may pass without a
+	// successful validator fetch before the fetch cycle counts as stalled.
+	fetchStallFactor = 3
+)
+
+// FetchSource reports when validators were last fetched successfully.
+type FetchSource interface {
+	GetLastUpdate() time.Time
+}
+
+// StreamSource reports whether the transaction stream claims to be connected.
+type StreamSource interface {
+	IsSubscribed() bool
+}
+
+// WatchdogAlertCallback receives watchdog alerts as they start and clear.
+type WatchdogAlertCallback func(*models.WatchdogAlert)
+
+// Watchdog detects pipelines that are silently frozen: no transactions while
+// the upstream stream claims to be subscribed, or no successful validator
+// fetch within fetchStallFactor refresh intervals.
+type Watchdog struct {
+	fetchSource     FetchSource
+	streamSource    StreamSource
+	txStallAfter    time.Duration
+	fetchStallAfter time.Duration
+	checkInterval   time.Duration
+	logger          *logrus.Logger
+	now             func() time.Time
+
+	mu          sync.Mutex
+	startedAt   time.Time
+	lastTx      time.Time
+	streamSince time.Time
+	active      map[string]bool
+	paused      bool
+	resumedAt   time.Time
+	callbacks   []WatchdogAlertCallback
+	stopChan    chan struct{}
+	stopOnce    sync.Once
+}
+
+// NewWatchdog creates a watchdog. A zero txStallAfter disables the
+// transaction check; a zero refreshInterval disables the fetch check.
+func NewWatchdog(fetchSource FetchSource, streamSource StreamSource, txStallAfter, refreshInterval time.Duration, logger *logrus.Logger) *Watchdog {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &Watchdog{
+		fetchSource:     fetchSource,
+		streamSource:    streamSource,
+		txStallAfter:    txStallAfter,
+		fetchStallAfter: fetchStallFactor * refreshInterval,
+		checkInterval:   watchdogCheckInterval,
+		logger:          logger,
+		now:             time.Now,
+		startedAt:       time.Now(),
+		active:          make(map[string]bool),
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// AddCallback registers a callback for alerts.
+func (w *Watchdog) AddCallback(callback WatchdogAlertCallback) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, callback)
+}
+
+// ObserveTransaction records that a transaction made it through the
+// pipeline. The server calls it as each validated transaction reaches the
+// broadcast loop, so a hung hand-off between the listener and the clients
+// alerts too.
+func (w *Watchdog) ObserveTransaction(*models.Transaction) {
+	w.mu.Lock()
+	w.lastTx = w.now()
+	w.mu.Unlock()
+}
+
+// Start begins periodic checks.
+func (w *Watchdog) Start(ctx context.Context) {
+	w.mu.Lock()
+	w.startedAt = w.now()
+	w.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(w.checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopChan:
+				return
+			case <-ticker.C:
+				w.Check()
+			}
+		}
+	}()
+}
+
+// Pause suspends the checks while ingestion is deliberately paused, so the
+// missing transactions and fetches do not alert.
+func (w *Watchdog) Pause(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.paused = true
+	return nil
+}
+
+// Resume restarts the checks, giving both pipelines a full window from now.
+func (w *Watchdog) Resume(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.paused = false
+	w.resumedAt = w.now()
+	w.streamSince = time.Time{}
+	return nil
+}
+
+// Stop stops periodic checks.
+func (w *Watchdog) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopChan)
+	})
+}
+
+// Check evaluates every check once and notifies callbacks of alerts that
+// started or cleared.
+func (w *Watchdog) Check() {
+	now := w.now()
+	var alerts []*models.WatchdogAlert
+
+	w.mu.Lock()
+	if w.paused {
+		w.mu.Unlock()
+		return
+	}
+	if w.txStallAfter > 0 && w.streamSource != nil {
+		// The stall clock starts at the later of the last transaction and
+		// the moment the stream (re)connected, so reconnects get a full
+		// window before alerting.
+		subscribed := w.streamSource.IsSubscribed()
+		if !subscribed {
+			w.streamSince = time.Time{}
+		} else if w.streamSince.IsZero() {
+			w.streamSince = now
+		}
+		since := w.streamSince
+		if w.lastTx.After(since) {
+			since = w.lastTx
+		}
+		stalled := subscribed && now.Sub(since) > w.txStallAfter
+		alerts = w.transition(alerts, CheckTransactionsStalled, stalled, w.lastTx,
+			fmt.Sprintf("no transactions for %s while the upstream stream is subscribed", now.Sub(since).Round(time.Second)))
+	}
+	if w.fetchStallAfter > 0 && w.fetchSource != nil {
+		lastUpdate := w.fetchSource.GetLastUpdate()
+		since := lastUpdate
+		if since.IsZero() {
+			since = w.startedAt
+		}
+		if since.Before(w.resumedAt) {
+			since = w.resumedAt
+		}
+		stalled := now.Sub(since) > w.fetchStallAfter
+		alerts = w.transition(alerts, CheckValidatorFetchStalled, stalled, lastUpdate,
+			fmt.Sprintf("no successful validator fetch for %s", now.Sub(since).Round(time.Second)))
+	}
+	callbacks := make([]WatchdogAlertCallback, len(w.callbacks))
+	copy(callbacks, w.callbacks)
+	w.mu.Unlock()
+
+	for _, alert := range alerts {
+		entry := w.logger.WithField("check", alert.Check)
+		if alert.Active {
+			entry.Error("Watchdog: " + alert.Message)
+		} else {
+			entry.Info("Watchdog check recovered")
+		}
+		for _, callback := range callbacks {
+			callback(alert)
+		}
+	}
+}
+
+// Stalled returns the checks currently alerting.
+func (w *Watchdog) Stalled() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var checks []string
+	for _, check := range []string{CheckTransactionsStalled, CheckValidatorFetchStalled} {
+		if w.active[check] {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// transition records the state of check and appends an alert when it
+// changed. The caller holds w.mu.
+func (w *Watchdog) transition(alerts []*models.WatchdogAlert, check string, stalled bool, lastSeen time.Time, message string) []*models.WatchdogAlert {
+	metrics.WatchdogStalled.WithLabelValues(check).Set(boolGauge(stalled))
+	if w.active[check] == stalled {
+		return alerts
+	}
+	w.active[check] = stalled
+	alert := &models.WatchdogAlert{Check: check, Active: stalled}
+	if !lastSeen.IsZero() {
+		alert.LastSeen = lastSeen.Unix()
+	}
+	if stalled {
+		metrics.WatchdogAlertsTotal.WithLabelValues(check).Inc()
+		alert.Message = message
+	}
+	return append(alerts, alert)
+}
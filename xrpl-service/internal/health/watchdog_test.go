@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+type fakeFetchSource struct{ lastUpdate time.Time }
+
+func (f *fakeFetchSource) GetLastUpdate() time.Time { return f.lastUpdate }
+
+type fakeStreamSource struct{ subscribed atomic.Bool }
+
+func (f *fakeStreamSource) IsSubscribed() bool { return f.subscribed.Load() }
+
+func TestWatchdogAlertsOnStalledTransactions(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	stream := &fakeStreamSource{}
+	stream.subscribed.Store(true)
+	watchdog := NewWatchdog(nil, stream, time.Minute, 0, nil)
+	watchdog.now = func() time.Time { return clock }
+
+	var alerts []*models.WatchdogAlert
+	watchdog.AddCallback(func(alert *models.WatchdogAlert) { alerts = append(alerts, alert) })
+
+	watchdog.Check()
+	watchdog.ObserveTransaction(nil)
+	clock = clock.Add(50 * time.Second)
+	watchdog.Check()
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alerts inside the stall window, got %+v", alerts)
+	}
+
+	clock = clock.Add(20 * time.Second)
+	watchdog.Check()
+	if len(alerts) != 1 || alerts[0].Check != CheckTransactionsStalled || !alerts[0].Active || alerts[0].LastSeen == 0 {
+		t.Fatalf("expected transactions_stalled alert, got %+v", alerts)
+	}
+	if stalled := watchdog.Stalled(); len(stalled) != 1 || stalled[0] != CheckTransactionsStalled {
+		t.Fatalf("expected Stalled to report the check, got %v", stalled)
+	}
+
+	watchdog.Check()
+	if len(alerts) != 1 {
+		t.Fatalf("expected alert to fire once, got %d", len(alerts))
+	}
+
+	watchdog.ObserveTransaction(nil)
+	watchdog.Check()
+	if len(alerts) != 2 || alerts[1].Active {
+		t.Fatalf("expected recovery alert, got %+v", alerts)
+	}
+}
+
+func TestWatchdogIgnoresDisconnectedStream(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	stream := &fakeStreamSource{}
+	watchdog := NewWatchdog(nil, stream, time.Minute, 0, nil)
+	watchdog.now = func() time.Time { return clock }
+
+	var alerts []*models.WatchdogAlert
+	watchdog.AddCallback(func(alert *models.WatchdogAlert) { alerts = append(alerts, alert) })
+
+	clock = clock.Add(10 * time.Minute)
+	watchdog.Check()
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alert while disconnected, got %+v", alerts)
+	}
+
+	// A reconnect gets a full window before alerting.
+	stream.subscribed.Store(true)
+	watchdog.Check()
+	clock = clock.Add(30 * time.Second)
+	watchdog.Check()
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alert right after reconnecting, got %+v", alerts)
+	}
+}
+
+func TestWatchdogAlertsOnStalledFetchCycle(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	fetch := &fakeFetchSource{lastUpdate: clock}
+	watchdog := NewWatchdog(fetch, nil, 0, time.Minute, nil)
+	watchdog.now = func() time.Time { return clock }
+
+	var alerts []*models.WatchdogAlert
+	watchdog.AddCallback(func(alert *models.WatchdogAlert) { alerts = append(alerts, alert) })
+
+	clock = clock.Add(2 * time.Minute)
+	watchdog.Check()
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alert within 3x the refresh interval, got %+v", alerts)
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	watchdog.Check()
+	if len(alerts) != 1 || alerts[0].Check != CheckValidatorFetchStalled || !alerts[0].Active {
+		t.Fatalf("expected validator_fetch_stalled alert, got %+v", alerts)
+	}
+
+	fetch.lastUpdate = clock
+	watchdog.Check()
+	if len(alerts) != 2 || alerts[1].Active {
+		t.Fatalf("expected recovery alert, got %+v", alerts)
+	}
+}
+
+func TestWatchdogSilentWhilePaused(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	fetch := &fakeFetchSource{lastUpdate: clock}
+	stream := &fakeStreamSource{}
+	stream.subscribed.Store(true)
+	watchdog := NewWatchdog(fetch, stream, time.Minute, time.Minute, nil)
+	watchdog.now = func() time.Time { return clock }
+
+	var alerts []*models.WatchdogAlert
+	watchdog.AddCallback(func(alert *models.WatchdogAlert) { alerts = append(alerts, alert) })
+
+	watchdog.Check()
+	watchdog.Pause(context.Background())
+	clock = clock.Add(time.Hour)
+	watchdog.Check()
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alert while paused, got %+v", alerts)
+	}
+
+	// Both pipelines get a full window after resuming.
+	watchdog.Resume(context.Background())
+	clock = clock.Add(30 * time.Second)
+	watchdog.Check()
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alert right after resuming, got %+v", alerts)
+	}
+
+	clock = clock.Add(4 * time.Minute)
+	watchdog.Check()
+	if len(alerts) != 2 {
+		t.Fatalf("expected both checks to alert once the window passed, got %+v", alerts)
+	}
+}
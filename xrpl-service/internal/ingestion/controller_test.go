@@ -0,0 +1,86 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeComponent struct {
+	name     string
+	pauseErr error
+	log      *[]string
+}
+
+func (f *fakeComponent) Pause(ctx context.Context) error {
+	if f.pauseErr != nil {
+		return f.pauseErr
+	}
+	*f.log = append(*f.log, "pause "+f.name)
+	return nil
+}
+
+func (f *fakeComponent) Resume(ctx context.Context) error {
+	*f.log = append(*f.log, "resume "+f.name)
+	return nil
+}
+
+func TestControllerPausesAndResumesInOrder(t *testing.T) {
+	var log []string
+	controller := NewController(nil)
+	controller.now = func() time.Time { return time.Unix(1_700_000_000, 0) }
+	controller.Add(&fakeComponent{name: "stream", log: &log}, &fakeComponent{name: "fetcher", log: &log})
+
+	if err := controller.Pause(context.Background(), "provider maintenance"); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	status := controller.Status()
+	if !status.Paused || status.Since != 1_700_000_000 || status.Reason != "provider maintenance" {
+		t.Fatalf("unexpected paused status %+v", status)
+	}
+
+	// Pausing again only updates the reason.
+	if err := controller.Pause(context.Background(), "extended"); err != nil {
+		t.Fatalf("second Pause failed: %v", err)
+	}
+	if reason := controller.Status().Reason; reason != "extended" {
+		t.Fatalf("expected the reason to be updated, got %q", reason)
+	}
+
+	if err := controller.Resume(context.Background()); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if status := controller.Status(); status.Paused || status.Since != 0 || status.Reason != "" {
+		t.Fatalf("unexpected running status %+v", status)
+	}
+
+	want := []string{"pause stream", "pause fetcher", "resume fetcher", "resume stream"}
+	if len(log) != len(want) {
+		t.Fatalf("expected %v, got %v", want, log)
+	}
+	for i := range want {
+		if log[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, log)
+		}
+	}
+}
+
+func TestControllerRollsBackFailedPause(t *testing.T) {
+	var log []string
+	controller := NewController(nil)
+	controller.Add(
+		&fakeComponent{name: "stream", log: &log},
+		&fakeComponent{name: "fetcher", pauseErr: errors.New("not connected"), log: &log},
+	)
+
+	if err := controller.Pause(context.Background(), "maintenance"); err == nil {
+		t.Fatal("expected the pause to fail")
+	}
+	if controller.Paused() {
+		t.Fatal("expected ingestion to keep running after a failed pause")
+	}
+	if len(log) != 2 || log[0] != "pause stream" || log[1] != "resume stream" {
+		t.Fatalf("expected the stream to be resumed again, got %v", log)
+	}
+}
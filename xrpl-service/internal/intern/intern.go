@@ -0,0 +1,15 @@
+// Package intern deduplicates strings that repeat across many long-lived
+// records, such as country codes and city names in the geolocation and
+// validator caches, so that each distinct value is stored once.
+package intern
+
+import "unique"
+
+// String returns a canonical copy of s. Equal strings returned by String
+// share their backing memory, which is reclaimed once none is referenced.
+func String(s string) string {
+	if s == "" {
+		return ""
+	}
+	return unique.Make(s).Value()
+}
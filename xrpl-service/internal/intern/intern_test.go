@@ -0,0 +1,21 @@
+package intern
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestStringSharesEqualValues(t *testing.T) {
+	a := String(strings.Repeat("Frankfurt", 2))
+	b := String(strings.Repeat("Frankfurt", 2))
+	if a != b {
+		t.Fatalf("expected equal values, got %q and %q", a, b)
+	}
+	if unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Fatal("expected equal values to share memory")
+	}
+	if String("") != "" {
+		t.Fatal("expected the empty string to stay empty")
+	}
+}
@@ -0,0 +1,345 @@
+// Package issuers snapshots the trust lines of configured token issuers for
+// an issuer-centric network view.
+package issuers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// accountLinesPageSize is the limit requested per account_lines page.
+	accountLinesPageSize = 400
+
+	// maxAccountLinesPages caps how many pages are scanned per issuer.
+	// Large gateways have hundreds of thousands of lines; the snapshot is
+	// marked truncated instead of walking all of them.
+	maxAccountLinesPages = 25
+
+	defaultTopHolders = 50
+	refreshTimeout    = 2 * time.Minute
+)
+
+// AccountGeoResolver resolves XRPL accounts to geolocation through the
+// Domain set on their account root.
+type AccountGeoResolver interface {
+	ResolveAccountGeo(ctx context.Context, client xrpl.NodeClient, account string) (*models.GeoLocation, error)
+}
+
+// Collector periodically queries `gateway_balances` and `account_lines` for
+// each configured issuer and keeps the latest graph of its largest holders.
+type Collector struct {
+	client      xrpl.NodeClient
+	geoResolver AccountGeoResolver
+	issuers     []string
+	interval    time.Duration
+	topHolders  int
+	logger      *logrus.Logger
+	now         func() time.Time
+
+	mu       sync.RWMutex
+	graphs   map[string]*models.IssuerGraph
+	paused   bool
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewCollector creates a collector for issuers that refreshes every
+// interval and keeps the topHolders largest holders of each issuer.
+// geoResolver may be nil to skip holder geolocation.
+func NewCollector(
+	client xrpl.NodeClient,
+	geoResolver AccountGeoResolver,
+	issuers []string,
+	interval time.Duration,
+	topHolders int,
+	logger *logrus.Logger,
+) *Collector {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	if topHolders <= 0 {
+		topHolders = defaultTopHolders
+	}
+	return &Collector{
+		client:      client,
+		geoResolver: geoResolver,
+		issuers:     issuers,
+		interval:    interval,
+		topHolders:  topHolders,
+		logger:      logger,
+		now:         time.Now,
+		graphs:      make(map[string]*models.IssuerGraph),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start refreshes all issuers immediately and then once per interval.
+func (c *Collector) Start(ctx context.Context) {
+	go func() {
+		c.Refresh(ctx)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.stopChan:
+				return
+			case <-ticker.C:
+				c.Refresh(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops refreshing.
+func (c *Collector) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopChan)
+	})
+}
+
+// Pause skips refreshes until Resume; the last snapshots keep being served.
+func (c *Collector) Pause(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = true
+	return nil
+}
+
+// Resume lets refreshes run again from the next interval.
+func (c *Collector) Resume(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = false
+	return nil
+}
+
+// Tracks reports whether issuer is one of the configured issuers.
+func (c *Collector) Tracks(issuer string) bool {
+	for _, tracked := range c.issuers {
+		if tracked == issuer {
+			return true
+		}
+	}
+	return false
+}
+
+// Graph returns the latest snapshot of issuer, or nil before the first
+// successful refresh.
+func (c *Collector) Graph(issuer string) *models.IssuerGraph {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.graphs[issuer]
+}
+
+// Refresh snapshots every issuer. An issuer that fails keeps its previous
+// snapshot. Nothing is refreshed while paused.
+func (c *Collector) Refresh(ctx context.Context) {
+	c.mu.RLock()
+	paused := c.paused
+	c.mu.RUnlock()
+	if paused {
+		return
+	}
+	for _, issuer := range c.issuers {
+		refreshCtx, cancel := context.WithTimeout(ctx, refreshTimeout)
+		graph, err := c.snapshot(refreshCtx, issuer)
+		cancel()
+		if err != nil {
+			metrics.IssuerGraphRefreshTotal.WithLabelValues("error").Inc()
+			c.logger.WithError(err).WithField("issuer", issuer).Warn("Failed to refresh issuer graph")
+			continue
+		}
+		metrics.IssuerGraphRefreshTotal.WithLabelValues("success").Inc()
+		c.mu.Lock()
+		c.graphs[issuer] = graph
+		c.mu.Unlock()
+	}
+}
+
+// snapshot builds the graph of one issuer.
+func (c *Collector) snapshot(ctx context.Context, issuer string) (*models.IssuerGraph, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("XRPL client is nil")
+	}
+	obligations, err := c.fetchObligations(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	graph := &models.IssuerGraph{
+		Issuer:      issuer,
+		Obligations: obligations,
+		Holders:     []*models.IssuerHolder{},
+		UpdatedAt:   c.now().Unix(),
+	}
+
+	holders := make(map[string]*models.IssuerHolder)
+	var marker interface{}
+	for page := 0; ; page++ {
+		if page == maxAccountLinesPages {
+			graph.Truncated = true
+			break
+		}
+		lines, next, err := c.fetchLines(ctx, issuer, marker)
+		if err != nil {
+			return nil, err
+		}
+		graph.TrustLines += len(lines)
+		for _, line := range lines {
+			addLine(holders, obligations, line)
+		}
+		if next == nil {
+			break
+		}
+		marker = next
+	}
+
+	for _, holder := range holders {
+		graph.Holders = append(graph.Holders, holder)
+	}
+	sort.Slice(graph.Holders, func(i, j int) bool {
+		a, b := graph.Holders[i], graph.Holders[j]
+		if a.Share != b.Share {
+			return a.Share > b.Share
+		}
+		return a.Account < b.Account
+	})
+	if len(graph.Holders) > c.topHolders {
+		graph.Holders = graph.Holders[:c.topHolders]
+	}
+
+	c.locate(ctx, graph)
+	return graph, nil
+}
+
+// locate resolves the issuer and its top holders through their domains.
+// Accounts without a domain stay unlocated.
+func (c *Collector) locate(ctx context.Context, graph *models.IssuerGraph) {
+	if c.geoResolver == nil {
+		return
+	}
+	resolve := func(account string) *models.GeoLocation {
+		geo, err := c.geoResolver.ResolveAccountGeo(ctx, c.client, account)
+		if err != nil {
+			c.logger.WithError(err).WithField("account", account).Debug("Failed to resolve issuer graph account")
+			return nil
+		}
+		return geo
+	}
+	graph.Location = resolve(graph.Issuer)
+	for _, holder := range graph.Holders {
+		if holder.Location = resolve(holder.Account); holder.Location != nil {
+			graph.Located++
+		}
+	}
+}
+
+// fetchObligations returns the issuer's total issued balance per currency.
+func (c *Collector) fetchObligations(ctx context.Context, issuer string) (map[string]string, error) {
+	resp, err := c.client.Command(ctx, "gateway_balances", map[string]interface{}{
+		"account":      issuer,
+		"ledger_index": "validated",
+		"strict":       true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gateway_balances failed: %w", err)
+	}
+	result, err := commandResult(resp, "gateway_balances")
+	if err != nil {
+		return nil, err
+	}
+	obligations := make(map[string]string)
+	for currency, value := range jsonutil.Map(result, "obligations") {
+		if amount, ok := value.(string); ok {
+			obligations[currency] = amount
+		}
+	}
+	return obligations, nil
+}
+
+// fetchLines returns one page of the issuer's trust lines and the marker of
+// the next page, nil on the last one.
+func (c *Collector) fetchLines(ctx context.Context, issuer string, marker interface{}) ([]map[string]interface{}, interface{}, error) {
+	params := map[string]interface{}{
+		"account":      issuer,
+		"ledger_index": "validated",
+		"limit":        accountLinesPageSize,
+	}
+	if marker != nil {
+		params["marker"] = marker
+	}
+	resp, err := c.client.Command(ctx, "account_lines", params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("account_lines failed: %w", err)
+	}
+	result, err := commandResult(resp, "account_lines")
+	if err != nil {
+		return nil, nil, err
+	}
+	r := jsonutil.NewReader(result)
+	rawLines := r.Slice("lines")
+	if err := r.Err(); err != nil {
+		return nil, nil, fmt.Errorf("account_lines response missing lines: %w", err)
+	}
+	lines := make([]map[string]interface{}, 0, len(rawLines))
+	for _, raw := range rawLines {
+		if line := jsonutil.Object(raw); line != nil {
+			lines = append(lines, line)
+		}
+	}
+	return lines, result["marker"], nil
+}
+
+// addLine adds a trust line, as listed from the issuer's side, to its
+// holder. The issuer's balance is negative when it owes the holder, so only
+// negative balances are holdings.
+func addLine(holders map[string]*models.IssuerHolder, obligations map[string]string, line map[string]interface{}) {
+	account := jsonutil.String(line, "account")
+	currency := jsonutil.String(line, "currency")
+	balance, err := strconv.ParseFloat(jsonutil.String(line, "balance"), 64)
+	if account == "" || currency == "" || err != nil || balance >= 0 {
+		return
+	}
+	held := -balance
+	limit := jsonutil.String(line, "limit_peer")
+
+	holder, ok := holders[account]
+	if !ok {
+		holder = &models.IssuerHolder{Account: account}
+		holders[account] = holder
+	}
+	holder.Lines = append(holder.Lines, &models.TrustLine{
+		Currency: currency,
+		Balance:  strconv.FormatFloat(held, 'f', -1, 64),
+		Limit:    limit,
+	})
+	if total, err := strconv.ParseFloat(obligations[currency], 64); err == nil && total > 0 {
+		holder.Share = math.Max(holder.Share, held/total)
+	}
+}
+
+func commandResult(resp interface{}, method string) (map[string]interface{}, error) {
+	r := jsonutil.NewReader(resp)
+	result := r.Map("result")
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("unexpected %s response: %w", method, err)
+	}
+	if jsonutil.String(result, "status") == "error" {
+		return nil, fmt.Errorf("%s error: %v", method, result["error"])
+	}
+	return result, nil
+}
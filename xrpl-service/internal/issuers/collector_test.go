@@ -0,0 +1,107 @@
+package issuers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+const testIssuer = "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"
+
+type stubAccountResolver struct{}
+
+func (stubAccountResolver) ResolveAccountGeo(ctx context.Context, client xrpl.NodeClient, account string) (*models.GeoLocation, error) {
+	switch account {
+	case testIssuer:
+		return &models.GeoLocation{CountryCode: "LU", City: "Luxembourg"}, nil
+	case "rHolderA":
+		return &models.GeoLocation{CountryCode: "US", City: "New York"}, nil
+	}
+	return nil, nil
+}
+
+func issuerClient(pages [][]interface{}) *xrpl.MockClient {
+	return xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		switch method {
+		case "gateway_balances":
+			return map[string]interface{}{"result": map[string]interface{}{
+				"status":      "success",
+				"obligations": map[string]interface{}{"USD": "1000", "BTC": "10"},
+			}}, nil
+		case "account_lines":
+			page := 0
+			if marker, ok := params.(map[string]interface{})["marker"].(float64); ok {
+				page = int(marker)
+			}
+			result := map[string]interface{}{"status": "success", "lines": pages[page]}
+			if page+1 < len(pages) {
+				result["marker"] = float64(page + 1)
+			}
+			return map[string]interface{}{"result": result}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	})
+}
+
+func line(account, currency, balance string) map[string]interface{} {
+	return map[string]interface{}{"account": account, "currency": currency, "balance": balance, "limit_peer": "1000000"}
+}
+
+func TestRefreshRanksHoldersByShare(t *testing.T) {
+	client := issuerClient([][]interface{}{
+		{line("rHolderA", "USD", "-100"), line("rHolderB", "USD", "-300"), line("rEmpty", "USD", "0")},
+		{line("rHolderA", "BTC", "-5"), line("rHolderC", "BTC", "-0.5")},
+	})
+	collector := NewCollector(client, stubAccountResolver{}, []string{testIssuer}, time.Hour, 2, logrus.New())
+	collector.Refresh(context.Background())
+
+	graph := collector.Graph(testIssuer)
+	if graph == nil {
+		t.Fatal("expected a graph after refresh")
+	}
+	if graph.TrustLines != 5 || graph.Truncated || graph.Obligations["USD"] != "1000" {
+		t.Fatalf("unexpected graph totals %+v", graph)
+	}
+	if len(graph.Holders) != 2 || graph.Holders[0].Account != "rHolderA" || graph.Holders[0].Share != 0.5 || graph.Holders[1].Account != "rHolderB" {
+		t.Fatalf("expected rHolderA (50%% of BTC) then rHolderB, got %+v %+v", graph.Holders[0], graph.Holders[1])
+	}
+	if len(graph.Holders[0].Lines) != 2 || graph.Holders[0].Lines[0].Balance != "100" {
+		t.Fatalf("expected both lines of rHolderA with positive balances, got %+v", graph.Holders[0].Lines)
+	}
+	if graph.Location == nil || graph.Located != 1 || graph.Holders[0].Location.City != "New York" || graph.Holders[1].Location != nil {
+		t.Fatalf("expected the issuer and rHolderA located, got %+v", graph)
+	}
+	if calls := client.CommandCalls("account_lines"); calls != 2 {
+		t.Fatalf("expected two account_lines pages, got %d", calls)
+	}
+}
+
+func TestRefreshKeepsPreviousGraphOnError(t *testing.T) {
+	failing := false
+	client := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		if failing {
+			return nil, errors.New("connection refused")
+		}
+		return map[string]interface{}{"result": map[string]interface{}{"status": "success", "lines": []interface{}{}}}, nil
+	})
+	collector := NewCollector(client, nil, []string{testIssuer}, time.Hour, 10, logrus.New())
+	if collector.Graph(testIssuer) != nil || !collector.Tracks(testIssuer) || collector.Tracks("rOther") {
+		t.Fatal("unexpected initial collector state")
+	}
+
+	collector.Refresh(context.Background())
+	first := collector.Graph(testIssuer)
+	if first == nil {
+		t.Fatal("expected a graph after refresh")
+	}
+	failing = true
+	collector.Refresh(context.Background())
+	if collector.Graph(testIssuer) != first {
+		t.Fatal("expected a failed refresh to keep the previous graph")
+	}
+}
@@ -0,0 +1,256 @@
+// Package jsonutil reads values out of decoded JSON (map[string]interface{}
+// trees as produced by encoding/json) without type assertions that can
+// panic. Getters return the zero value for missing or mistyped values;
+// Reader collects an error for each required value that is not there.
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrMissing is wrapped by Reader errors for values that are absent.
+var ErrMissing = errors.New("missing value")
+
+// ErrType is wrapped by Reader errors for values of the wrong type.
+var ErrType = errors.New("unexpected type")
+
+// Object returns v as a JSON object, or nil.
+func Object(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+// Map returns the object at key of m, or nil.
+func Map(m map[string]interface{}, key string) map[string]interface{} {
+	return Object(m[key])
+}
+
+// Slice returns the array at key of m, or nil.
+func Slice(m map[string]interface{}, key string) []interface{} {
+	s, _ := m[key].([]interface{})
+	return s
+}
+
+// String returns the string at key of m, or "".
+func String(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// Bool returns the boolean at key of m, or false.
+func Bool(m map[string]interface{}, key string) bool {
+	b, _ := m[key].(bool)
+	return b
+}
+
+// Int64 returns the integer at key of m, or 0. Numbers, json.Number and
+// decimal strings (rippled encodes some counters as strings) are accepted;
+// fractions are truncated.
+func Int64(m map[string]interface{}, key string) int64 {
+	n, _ := toInt64(m[key])
+	return n
+}
+
+// Float64 returns the number at key of m, or 0. Numbers, json.Number and
+// numeric strings are accepted.
+func Float64(m map[string]interface{}, key string) float64 {
+	f, _ := toFloat64(m[key])
+	return f
+}
+
+// Get returns the value at a dot-separated path, e.g.
+// "result.info.validated_ledger.seq". Segments index objects by key and
+// arrays by decimal position, so "result.peers.0.address" is the address of
+// the first peer.
+func Get(m map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = m
+	for more := true; more; {
+		var segment string
+		segment, path, more = strings.Cut(path, ".")
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, current != nil
+}
+
+// GetMap returns the object at path, or nil.
+func GetMap(m map[string]interface{}, path string) map[string]interface{} {
+	v, _ := Get(m, path)
+	return Object(v)
+}
+
+// GetSlice returns the array at path, or nil.
+func GetSlice(m map[string]interface{}, path string) []interface{} {
+	v, _ := Get(m, path)
+	s, _ := v.([]interface{})
+	return s
+}
+
+// GetString returns the string at path, or "".
+func GetString(m map[string]interface{}, path string) string {
+	v, _ := Get(m, path)
+	s, _ := v.(string)
+	return s
+}
+
+// GetInt64 returns the integer at path, or 0, converted as by Int64.
+func GetInt64(m map[string]interface{}, path string) int64 {
+	v, _ := Get(m, path)
+	n, _ := toInt64(v)
+	return n
+}
+
+// Reader reads required values by path from one decoded document and
+// collects an error for each that is missing or mistyped, so a parser can
+// read every field and check once:
+//
+//	r := jsonutil.NewReader(resp)
+//	seq := r.Int64("result.info.validated_ledger.seq")
+//	state := r.String("result.info.server_state")
+//	if err := r.Err(); err != nil { ... }
+type Reader struct {
+	root map[string]interface{}
+	errs []error
+}
+
+// NewReader returns a Reader over v. A v that is not an object fails every
+// read.
+func NewReader(v interface{}) *Reader {
+	return &Reader{root: Object(v)}
+}
+
+// Err returns the collected errors joined, or nil.
+func (r *Reader) Err() error {
+	return errors.Join(r.errs...)
+}
+
+// value returns the value at path, recording ErrMissing when absent.
+func (r *Reader) value(path string) (interface{}, bool) {
+	v, ok := Get(r.root, path)
+	if !ok {
+		r.errs = append(r.errs, fmt.Errorf("%s: %w", path, ErrMissing))
+	}
+	return v, ok
+}
+
+func (r *Reader) typeError(path, want string, v interface{}) {
+	r.errs = append(r.errs, fmt.Errorf("%s: %w: want %s, got %T", path, ErrType, want, v))
+}
+
+// Map returns the required object at path.
+func (r *Reader) Map(path string) map[string]interface{} {
+	v, ok := r.value(path)
+	if !ok {
+		return nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		r.typeError(path, "object", v)
+	}
+	return m
+}
+
+// Slice returns the required array at path.
+func (r *Reader) Slice(path string) []interface{} {
+	v, ok := r.value(path)
+	if !ok {
+		return nil
+	}
+	s, ok := v.([]interface{})
+	if !ok {
+		r.typeError(path, "array", v)
+	}
+	return s
+}
+
+// String returns the required string at path.
+func (r *Reader) String(path string) string {
+	v, ok := r.value(path)
+	if !ok {
+		return ""
+	}
+	s, ok := v.(string)
+	if !ok {
+		r.typeError(path, "string", v)
+	}
+	return s
+}
+
+// Int64 returns the required integer at path, converted as by Int64.
+func (r *Reader) Int64(path string) int64 {
+	v, ok := r.value(path)
+	if !ok {
+		return 0
+	}
+	n, ok := toInt64(v)
+	if !ok {
+		r.typeError(path, "integer", v)
+	}
+	return n
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch value := v.(type) {
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) || value > math.MaxInt64 || value < math.MinInt64 {
+			return 0, false
+		}
+		return int64(value), true
+	case int64:
+		return value, true
+	case int:
+		return int64(value), true
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return n, true
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return 0, false
+		}
+		return toInt64(f)
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return value, true
+	case int64:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case json.Number:
+		f, err := value.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
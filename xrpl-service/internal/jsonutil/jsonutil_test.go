@@ -0,0 +1,81 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func decode(t *testing.T, raw string) map[string]interface{} {
+	t.Helper()
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestGettersReturnZeroValuesForMissingOrMistypedValues(t *testing.T) {
+	m := decode(t, `{"s":"x","n":42.9,"ns":"17","b":true,"o":{"k":"v"},"a":[1,2]}`)
+
+	if String(m, "s") != "x" || String(m, "n") != "" || String(nil, "s") != "" {
+		t.Fatal("unexpected String results")
+	}
+	if Int64(m, "n") != 42 || Int64(m, "ns") != 17 || Int64(m, "s") != 0 || Int64(m, "missing") != 0 {
+		t.Fatal("unexpected Int64 results")
+	}
+	if Float64(m, "n") != 42.9 || Float64(m, "ns") != 17 {
+		t.Fatal("unexpected Float64 results")
+	}
+	if !Bool(m, "b") || Bool(m, "s") {
+		t.Fatal("unexpected Bool results")
+	}
+	if Map(m, "o")["k"] != "v" || Map(m, "a") != nil || Map(nil, "o") != nil {
+		t.Fatal("unexpected Map results")
+	}
+	if len(Slice(m, "a")) != 2 || Slice(m, "o") != nil {
+		t.Fatal("unexpected Slice results")
+	}
+}
+
+func TestGetWalksObjectsAndArrays(t *testing.T) {
+	m := decode(t, `{"result":{"info":{"validated_ledger":{"seq":91000000}},"peers":[{"address":"1.2.3.4:51235"}]}}`)
+
+	if got := GetInt64(m, "result.info.validated_ledger.seq"); got != 91000000 {
+		t.Fatalf("expected the validated ledger sequence, got %d", got)
+	}
+	if got := GetString(m, "result.peers.0.address"); got != "1.2.3.4:51235" {
+		t.Fatalf("expected the first peer address, got %q", got)
+	}
+	for _, path := range []string{"result.peers.1.address", "result.peers.x", "result.info.validated_ledger.seq.more", "missing", ""} {
+		if v, ok := Get(m, path); ok {
+			t.Fatalf("expected %q to be missing, got %v", path, v)
+		}
+	}
+	if GetMap(m, "result.info") == nil || GetSlice(m, "result.peers") == nil {
+		t.Fatal("expected the nested object and array")
+	}
+}
+
+func TestReaderCollectsErrors(t *testing.T) {
+	r := NewReader(decode(t, `{"result":{"info":{"server_state":"full","peers":"many"}}}`))
+	if got := r.String("result.info.server_state"); got != "full" {
+		t.Fatalf("unexpected server state %q", got)
+	}
+	if err := r.Err(); err != nil {
+		t.Fatalf("expected no error yet, got %v", err)
+	}
+
+	r.Int64("result.info.peers")
+	r.Map("result.info.validated_ledger")
+	err := r.Err()
+	if !errors.Is(err, ErrType) || !errors.Is(err, ErrMissing) {
+		t.Fatalf("expected a type and a missing error, got %v", err)
+	}
+
+	r = NewReader("not an object")
+	r.Map("result")
+	if !errors.Is(r.Err(), ErrMissing) {
+		t.Fatalf("expected reads of a non-object to fail, got %v", r.Err())
+	}
+}
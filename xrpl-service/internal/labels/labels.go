@@ -0,0 +1,228 @@
+// Package labels keeps operator-submitted account labels, so newly
+// prominent accounts can be named at runtime instead of in a redeployed
+// processor. The store runs as a transaction.Processor and sets the
+// source_label and dest_label tags that enrichment rules can match on.
+package labels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/cachefile"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Transaction tags set from labels.
+const (
+	TagSourceLabel      = "source_label"
+	TagDestinationLabel = "dest_label"
+)
+
+// maxLabelLength bounds labels, which are shown on the globe.
+const maxLabelLength = 64
+
+const labelsFileVersion = 1
+
+// labelsFileFormat upgrades older labels files on load. When bumping
+// labelsFileVersion, register a migration from the previous version here.
+var labelsFileFormat = cachefile.Format{
+	Name:       "account labels",
+	Version:    labelsFileVersion,
+	Migrations: map[int]cachefile.MigrateFunc{},
+}
+
+type labelsFile struct {
+	Version int                    `json:"version"`
+	Labels  []*models.AccountLabel `json:"labels"`
+}
+
+// ErrInvalidLabel is returned by Put for a label that fails validation.
+var ErrInvalidLabel = errors.New("invalid account label")
+
+// Store holds account labels and persists every change to its file. It is
+// safe for concurrent use.
+type Store struct {
+	path   string
+	clock  clock.Clock
+	logger *logrus.Logger
+
+	mu     sync.RWMutex
+	labels map[string]*models.AccountLabel // Account -> label
+}
+
+// Load reads the labels file at path. A missing file starts an empty store;
+// the file is created by the first Put.
+func Load(path string, clk clock.Clock, logger *logrus.Logger) (*Store, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	s := &Store{
+		path:   path,
+		clock:  clock.OrReal(clk),
+		logger: logger,
+		labels: make(map[string]*models.AccountLabel),
+	}
+	data, _, err := labelsFileFormat.Load(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	var file labelsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse account labels %s: %w", path, err)
+	}
+	for _, label := range file.Labels {
+		if label == nil {
+			continue
+		}
+		if err := validate(label); err != nil {
+			return nil, fmt.Errorf("account labels %s: %w", path, err)
+		}
+		s.labels[label.Account] = label
+	}
+	return s, nil
+}
+
+// Put adds a label or corrects the existing label of the same account and
+// persists the store. Account, Label and SubmittedBy are required; the
+// timestamps are set by the store, keeping CreatedAt on corrections. It
+// returns the stored label and whether it was new.
+func (s *Store) Put(label models.AccountLabel) (*models.AccountLabel, bool, error) {
+	label.Account = strings.TrimSpace(label.Account)
+	label.Label = strings.TrimSpace(label.Label)
+	label.SubmittedBy = strings.TrimSpace(label.SubmittedBy)
+	label.Source = strings.TrimSpace(label.Source)
+	label.Status = strings.ToLower(strings.TrimSpace(label.Status))
+	if err := validate(&label); err != nil {
+		return nil, false, err
+	}
+
+	now := s.clock.Now().UnixMilli()
+	label.UpdatedAt = now
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, exists := s.labels[label.Account]
+	if exists {
+		label.CreatedAt = previous.CreatedAt
+	} else {
+		label.CreatedAt = now
+	}
+	s.labels[label.Account] = &label
+	if err := s.persist(); err != nil {
+		if exists {
+			s.labels[label.Account] = previous
+		} else {
+			delete(s.labels, label.Account)
+		}
+		return nil, false, fmt.Errorf("persist account labels: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"account":      label.Account,
+		"label":        label.Label,
+		"submitted_by": label.SubmittedBy,
+		"status":       label.Status,
+		"corrected":    exists,
+	}).Info("Account label saved")
+	copy := label
+	return &copy, !exists, nil
+}
+
+// List returns copies of every label, ordered by account.
+func (s *Store) List() []*models.AccountLabel {
+	s.mu.RLock()
+	labels := make([]*models.AccountLabel, 0, len(s.labels))
+	for _, label := range s.labels {
+		copy := *label
+		labels = append(labels, &copy)
+	}
+	s.mu.RUnlock()
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Account < labels[j].Account })
+	return labels
+}
+
+// Lookup returns the label applied to account. Pending and rejected labels
+// are not applied.
+func (s *Store) Lookup(account string) (string, bool) {
+	if account == "" {
+		return "", false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	label, ok := s.labels[account]
+	if !ok || label.Status == models.LabelStatusPending || label.Status == models.LabelStatusRejected {
+		return "", false
+	}
+	return label.Label, true
+}
+
+// Name identifies the store as a transaction processor.
+func (s *Store) Name() string {
+	return "labels"
+}
+
+// Process tags tx with the labels of its source and destination accounts.
+func (s *Store) Process(ctx context.Context, tx *models.Transaction) error {
+	source, sourceOK := s.Lookup(tx.Account)
+	destination, destinationOK := s.Lookup(tx.Destination)
+	if !sourceOK && !destinationOK {
+		return nil
+	}
+	if tx.Tags == nil {
+		tx.Tags = make(map[string]string, 2)
+	}
+	if sourceOK {
+		tx.Tags[TagSourceLabel] = source
+	}
+	if destinationOK {
+		tx.Tags[TagDestinationLabel] = destination
+	}
+	return nil
+}
+
+// persist writes the store to its file. The caller holds s.mu.
+func (s *Store) persist() error {
+	file := labelsFile{
+		Version: labelsFileVersion,
+		Labels:  make([]*models.AccountLabel, 0, len(s.labels)),
+	}
+	for _, label := range s.labels {
+		file.Labels = append(file.Labels, label)
+	}
+	sort.Slice(file.Labels, func(i, j int) bool { return file.Labels[i].Account < file.Labels[j].Account })
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return labelsFileFormat.Write(s.path, data)
+}
+
+func validate(label *models.AccountLabel) error {
+	if !strings.HasPrefix(label.Account, "r") || len(label.Account) < 25 || len(label.Account) > 35 {
+		return fmt.Errorf("%w: account %q is not a classic address", ErrInvalidLabel, label.Account)
+	}
+	if label.Label == "" || len(label.Label) > maxLabelLength {
+		return fmt.Errorf("%w: label must be 1 to %d characters", ErrInvalidLabel, maxLabelLength)
+	}
+	if label.SubmittedBy == "" {
+		return fmt.Errorf("%w: submitted_by is required", ErrInvalidLabel)
+	}
+	switch label.Status {
+	case "", models.LabelStatusPending, models.LabelStatusApproved, models.LabelStatusRejected:
+	default:
+		return fmt.Errorf("%w: status must be %s, %s or %s", ErrInvalidLabel,
+			models.LabelStatusPending, models.LabelStatusApproved, models.LabelStatusRejected)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+const (
+	exchangeAccount = "rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY"
+	otherAccount    = "rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn"
+)
+
+func TestStorePersistsCorrections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "account-labels.json")
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	store, err := Load(path, fake, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	label, created, err := store.Put(models.AccountLabel{Account: exchangeAccount, Label: "Exchange A", SubmittedBy: "ops"})
+	if err != nil || !created {
+		t.Fatalf("Put: %v, created %v", err, created)
+	}
+	createdAt := label.CreatedAt
+
+	fake.Advance(time.Hour)
+	label, created, err = store.Put(models.AccountLabel{
+		Account:     exchangeAccount,
+		Label:       " Exchange B ",
+		SubmittedBy: "reviewer",
+		Source:      "https://example.com/wallets",
+		Status:      "Approved",
+	})
+	if err != nil || created {
+		t.Fatalf("expected a correction, got %v, created %v", err, created)
+	}
+	if label.Label != "Exchange B" || label.Status != models.LabelStatusApproved || label.CreatedAt != createdAt || label.UpdatedAt != fake.Now().UnixMilli() {
+		t.Fatalf("unexpected corrected label %+v", label)
+	}
+
+	reloaded, err := Load(path, fake, nil)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	labels := reloaded.List()
+	if len(labels) != 1 || *labels[0] != *label {
+		t.Fatalf("expected the correction persisted, got %+v", labels)
+	}
+}
+
+func TestStoreTagsTransactions(t *testing.T) {
+	store, err := Load(filepath.Join(t.TempDir(), "account-labels.json"), nil, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, _, err := store.Put(models.AccountLabel{Account: exchangeAccount, Label: "Exchange", SubmittedBy: "ops"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, _, err := store.Put(models.AccountLabel{Account: otherAccount, Label: "Unverified", SubmittedBy: "ops", Status: models.LabelStatusPending}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	tx := &models.Transaction{Account: otherAccount, Destination: exchangeAccount}
+	if err := store.Process(context.Background(), tx); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if tx.Tags[TagDestinationLabel] != "Exchange" {
+		t.Fatalf("expected the destination labeled, got %v", tx.Tags)
+	}
+	if _, ok := tx.Tags[TagSourceLabel]; ok {
+		t.Fatalf("expected a pending label not to be applied, got %v", tx.Tags)
+	}
+
+	unlabeled := &models.Transaction{Account: otherAccount}
+	if err := store.Process(context.Background(), unlabeled); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if unlabeled.Tags != nil {
+		t.Fatalf("expected no tags, got %v", unlabeled.Tags)
+	}
+}
+
+func TestPutRejectsInvalidLabels(t *testing.T) {
+	store, err := Load(filepath.Join(t.TempDir(), "account-labels.json"), nil, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	tests := []struct {
+		name  string
+		label models.AccountLabel
+	}{
+		{name: "x-address", label: models.AccountLabel{Account: "X7AcgcsBL6XDcUb289X4mJ8djcdyKaB5hJDWMArnXr61cqZ", Label: "A", SubmittedBy: "ops"}},
+		{name: "empty label", label: models.AccountLabel{Account: exchangeAccount, Label: " ", SubmittedBy: "ops"}},
+		{name: "no provenance", label: models.AccountLabel{Account: exchangeAccount, Label: "A"}},
+		{name: "unknown status", label: models.AccountLabel{Account: exchangeAccount, Label: "A", SubmittedBy: "ops", Status: "maybe"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := store.Put(tt.label); !errors.Is(err, ErrInvalidLabel) {
+				t.Fatalf("expected ErrInvalidLabel, got %v", err)
+			}
+		})
+	}
+	if len(store.List()) != 0 {
+		t.Fatal("expected nothing stored")
+	}
+}
@@ -15,6 +15,21 @@ var (
 		[]string{"method", "endpoint", "status"},
 	)
 
+	HTTPResponseCacheTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_http_response_cache_total",
+			Help: "Total number of cacheable HTTP requests by cache result",
+		},
+		[]string{"route", "result"},
+	)
+
+	HTTPRateLimitedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_http_rate_limited_total",
+			Help: "Total number of HTTP requests rejected by the public mode per-IP rate limit",
+		},
+	)
+
 	HTTPRequestDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "xrpl_validator_http_request_duration_seconds",
@@ -32,6 +47,14 @@ var (
 		},
 	)
 
+	WebSocketSubprotocolConnectionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_websocket_subprotocol_connections_total",
+			Help: "Total number of WebSocket connections, by negotiated subprotocol",
+		},
+		[]string{"protocol"},
+	)
+
 	WebSocketConnectionsActive = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "xrpl_validator_websocket_connections_active",
@@ -39,6 +62,54 @@ var (
 		},
 	)
 
+	WebSocketConnectionsRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_websocket_connections_rejected_total",
+			Help: "Total number of WebSocket connections rejected by origin policy",
+		},
+		[]string{"reason"},
+	)
+
+	WebSocketMessagesDroppedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_websocket_messages_dropped_total",
+			Help: "Total number of WebSocket messages not delivered to a client",
+		},
+		[]string{"reason"},
+	)
+
+	WebSocketMessagesDowngradedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_websocket_messages_downgraded_total",
+			Help: "Total number of transactions sent as summaries to clients over their bandwidth budget",
+		},
+	)
+
+	WebSocketBytesSentTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_websocket_bytes_sent_total",
+			Help: "Total number of WebSocket payload bytes sent, by API key name",
+		},
+		[]string{"api_key"},
+	)
+
+	// Upstream error metrics
+	UpstreamErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_upstream_errors_total",
+			Help: "Total number of upstream errors by source and class",
+		},
+		[]string{"source", "class"},
+	)
+
+	UpstreamDNSChangesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_upstream_dns_changes_total",
+			Help: "Total number of upstream WebSocket connections cycled after a DNS change, by host and result",
+		},
+		[]string{"host", "result"},
+	)
+
 	// Validator metrics
 	ValidatorFetchTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -55,6 +126,83 @@ var (
 		},
 	)
 
+	ValidatorsStaleLocation = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_stale_locations",
+			Help: "Number of validators whose locked location has not been re-confirmed within the lock TTL",
+		},
+	)
+
+	ValidatorPeerProbesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_peer_probes_total",
+			Help: "Total number of validator domain peer port probes by result: reachable, unreachable or skipped (no public IP)",
+		},
+		[]string{"result"},
+	)
+
+	ValidatorSnapshotsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_snapshots_total",
+			Help: "Total number of successful validator fetch cycles, by whether the validator set changed",
+		},
+		[]string{"result"},
+	)
+
+	ValidatorKeyRotationsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_key_rotations_total",
+			Help: "Total number of validator signing key rotations seen in manifests",
+		},
+	)
+
+	ValidatorListPublisherRotationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_list_publisher_rotations_total",
+			Help: "Total number of validator list publisher key rotations accepted, by kind (signing, master)",
+		},
+		[]string{"kind"},
+	)
+
+	ValidatorListPublisherAlerts = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_list_publisher_alerts",
+			Help: "Number of validator list sites presenting a publisher key that is not accepted",
+		},
+	)
+
+	ValidatorListExpiration = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_list_expiration_timestamp_seconds",
+			Help: "Unix time the last validator list fetched from each site expires",
+		},
+		[]string{"site"},
+	)
+
+	ValidatorListExpiryAlerts = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_list_expiry_alerts",
+			Help: "Number of validator list sites whose last list is expired or expires within the warning period",
+		},
+	)
+
+	ValidatorDomainChangesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_domain_changes_total",
+			Help: "Total number of validator domain changes recorded, by source",
+		},
+		[]string{"source"},
+	)
+
+	ValidatorFetchStageDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "xrpl_validator_fetch_stage_duration_seconds",
+			Help:    "Duration of validator fetch cycle stages in seconds",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		},
+		[]string{"stage", "result"},
+	)
+
 	// Transaction metrics
 	TransactionsProcessedTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -71,6 +219,62 @@ var (
 		},
 	)
 
+	TransactionLatencySeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "xrpl_validator_transaction_latency_seconds",
+			Help:    "Transaction processing latency by stage: receive (ledger close to receipt), enrich, broadcast (receipt to fanout) and end_to_end (ledger close to fanout)",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 4, 8, 16, 32},
+		},
+		[]string{"stage"},
+	)
+
+	TransactionProcessorTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_transaction_processor_total",
+			Help: "Total number of custom transaction processor calls by processor and result",
+		},
+		[]string{"processor", "result"},
+	)
+
+	ProvisionalTransactionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_provisional_transactions_total",
+			Help: "Total number of provisional transactions by outcome: previewed, dropped, confirmed, rejected or expired",
+		},
+		[]string{"outcome"},
+	)
+
+	EnrichmentRuleEvaluationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_enrichment_rule_evaluations_total",
+			Help: "Total number of enrichment rule evaluations by rule and result",
+		},
+		[]string{"rule", "result"},
+	)
+
+	FlaggedTransactionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_flagged_transactions_total",
+			Help: "Total number of watchlist matches on transactions by reason",
+		},
+		[]string{"reason"},
+	)
+
+	AccountsCreatedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_accounts_created_total",
+			Help: "Total number of accounts created by streamed transactions, by whether the funding source was located",
+		},
+		[]string{"located"},
+	)
+
+	FeesBurnedDropsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_fees_burned_drops_total",
+			Help: "Total transaction fees destroyed by observed validated ledgers, in drops",
+		},
+	)
+
 	// Geolocation metrics
 	GeolocationEnrichTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -80,7 +284,236 @@ var (
 		[]string{"status"},
 	)
 
+	GeolocationBatchLookupsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_geolocation_batch_lookups_total",
+			Help: "Total number of per-ledger account lookups, by whether the result was looked up or shared within the ledger",
+		},
+		[]string{"result"},
+	)
+
+	GeolocationShardLookupsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_geolocation_shard_lookups_total",
+			Help: "Total number of account lookups by sticky enrichment workers, by result: hit, missing (negative cache hit) or lookup",
+		},
+		[]string{"result"},
+	)
+
+	GeolocationCoordinatesRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_geolocation_coordinates_rejected_total",
+			Help: "Total number of resolved validator coordinates rejected by sanity checks, by reason",
+		},
+		[]string{"reason"},
+	)
+
+	GeolocationAccountDomainChangesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_geolocation_account_domain_changes_total",
+			Help: "Total number of account domain changes seen in the stream that invalidated cached locations, by re-resolution result",
+		},
+		[]string{"result"},
+	)
+
+	GeolocationProviderRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_geolocation_provider_requests_total",
+			Help: "Total number of paid geolocation provider lookups by provider and result: ok, not_found, error, unauthorized or quota_exhausted",
+		},
+		[]string{"provider", "result"},
+	)
+
+	GeolocationProviderQuotaUsed = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_geolocation_provider_quota_used",
+			Help: "Requests sent to each paid geolocation provider in the current UTC calendar month",
+		},
+		[]string{"provider"},
+	)
+
+	GeolocationProviderQuotaLimit = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_geolocation_provider_quota_limit",
+			Help: "Monthly request quota of each paid geolocation provider, 0 when unlimited",
+		},
+		[]string{"provider"},
+	)
+
+	// Report metrics
+	ReportDeliveriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_report_deliveries_total",
+			Help: "Total number of network summary report deliveries by destination and result",
+		},
+		[]string{"destination", "result"},
+	)
+
+	// Census metrics
+	CensusDeliveriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_census_deliveries_total",
+			Help: "Total number of daily validator census artifacts written or uploaded by destination and result",
+		},
+		[]string{"destination", "result"},
+	)
+
+	// Issuer graph metrics
+	IssuerGraphRefreshTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_issuer_graph_refresh_total",
+			Help: "Total number of issuer trust line snapshots by result",
+		},
+		[]string{"result"},
+	)
+
+	// Ingestion metrics
+	IngestionPaused = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_ingestion_paused",
+			Help: "Whether upstream ingestion is paused by an operator (1) or running (0)",
+		},
+	)
+
+	// Load metrics, refreshed on every /metrics scrape and /load request
+	LoadPercent = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_load_percent",
+			Help: "Highest of the normalized load signals, in percent of their targets",
+		},
+	)
+
+	LoadEnrichmentQueueSaturationPercent = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_load_enrichment_queue_saturation_percent",
+			Help: "Geo enrichment queue fill in percent of its capacity",
+		},
+	)
+
+	LoadBroadcastSaturationPercent = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_load_broadcast_saturation_percent",
+			Help: "WebSocket broadcast channel fill in percent of its capacity",
+		},
+	)
+
+	LoadUpstreamLagSeconds = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_load_upstream_lag_seconds",
+			Help: "Seconds between the ledger close and the fanout of the last broadcast transaction",
+		},
+	)
+
+	// Outbound budget metrics
+	OutboundBudgetWaitSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "xrpl_validator_outbound_budget_wait_seconds",
+			Help:    "Time outbound requests waited for their destination's budget, by subsystem",
+			Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"subsystem"},
+	)
+
+	OutboundBudgetAbandonedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_outbound_budget_abandoned_total",
+			Help: "Outbound requests whose context ended while waiting for their destination's budget, by subsystem",
+		},
+		[]string{"subsystem"},
+	)
+
+	// Dev injection metrics
+	DevInjectionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_dev_injections_total",
+			Help: "Synthetic messages injected through POST /dev/inject by kind",
+		},
+		[]string{"kind"},
+	)
+
+	// Watchdog metrics
+	WatchdogStalled = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_watchdog_stalled",
+			Help: "Whether a watchdog check currently sees a stalled pipeline (1) or not (0)",
+		},
+		[]string{"check"},
+	)
+
+	WatchdogAlertsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_watchdog_alerts_total",
+			Help: "Total number of watchdog stall alerts raised, by check",
+		},
+		[]string{"check"},
+	)
+
+	// SLO metrics
+	SLOBreached = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_slo_breached",
+			Help: "Whether a validator set SLO check is currently outside its bound (1) or not (0)",
+		},
+		[]string{"check"},
+	)
+
+	SLOAlertsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_slo_alerts_total",
+			Help: "Total number of validator set SLO breaches raised, by check",
+		},
+		[]string{"check"},
+	)
+
+	ValidatorMappedRatio = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_mapped_ratio",
+			Help: "Share of validators in the current set with known coordinates",
+		},
+	)
+
+	// Anomaly metrics
+	AnomalyZScore = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_anomaly_z_score",
+			Help: "Latest z-score of each network metric against its moving average",
+		},
+		[]string{"metric"},
+	)
+
+	AnomaliesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_anomalies_total",
+			Help: "Total number of network metric anomalies detected, by metric and direction",
+		},
+		[]string{"metric", "direction"},
+	)
+
 	// XRPL upstream client metrics
+	UpstreamMessagesDroppedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_upstream_messages_dropped_total",
+			Help: "Total number of XRPL stream messages dropped before dispatch, by host and reason (buffer_full, decode, duplicate)",
+		},
+		[]string{"host", "reason"},
+	)
+
+	UpstreamMessagesRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_upstream_messages_rejected_total",
+			Help: "Total number of XRPL stream messages rejected for exceeding payload limits, by host and reason (too_large, too_deep)",
+		},
+		[]string{"host", "reason"},
+	)
+
+	UpstreamMessageBufferDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_upstream_message_buffer_depth",
+			Help: "XRPL stream messages waiting for decoding and dispatch, by host",
+		},
+		[]string{"host"},
+	)
+
 	UpstreamCommandTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "xrpl_validator_upstream_command_total",
@@ -88,4 +521,103 @@ var (
 		},
 		[]string{"method", "status"},
 	)
+
+	UpstreamReconnectsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_upstream_reconnects_total",
+			Help: "Total number of stream reconnect attempts, by upstream and result",
+		},
+		[]string{"upstream", "result"},
+	)
+
+	UpstreamCircuitOpen = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_upstream_circuit_open",
+			Help: "Whether reconnects to a stream upstream are paused after repeated failures (1) or not (0)",
+		},
+		[]string{"upstream"},
+	)
+
+	// Debug capture metrics
+	DebugCapturesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_debug_captures_total",
+			Help: "Total number of unparseable upstream payloads offered for debug capture, by source and result",
+		},
+		[]string{"source", "result"},
+	)
+
+	// Upstream server status metrics
+	ServerPeerCount = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_server_peer_count",
+			Help: "Peer count reported by the polled XRPL server",
+		},
+	)
+
+	ServerUptimeSeconds = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_server_uptime_seconds",
+			Help: "Uptime in seconds reported by the polled XRPL server",
+		},
+	)
+
+	ServerCompleteLedgerSpan = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_server_complete_ledger_span",
+			Help: "Number of ledgers in the polled XRPL server's complete_ledgers range",
+		},
+	)
+
+	ServerOldestLedger = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_server_oldest_ledger",
+			Help: "Oldest ledger sequence available on the polled XRPL server",
+		},
+	)
+
+	ServerLedgerGapCount = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_server_ledger_gap_count",
+			Help: "Number of gaps in the polled XRPL server's complete_ledgers range",
+		},
+	)
+
+	ServerLedgerHistoryShrinkTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_server_ledger_history_shrink_total",
+			Help: "Total number of unexpected complete ledger history shrinks",
+		},
+	)
+
+	ServerValidatedLedgerAge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_server_validated_ledger_age_seconds",
+			Help: "Age in seconds of the last validated ledger on the polled XRPL server",
+		},
+	)
+
+	ServerCondition = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xrpl_validator_server_condition",
+			Help: "Whether the polled XRPL server is in an alert condition (1) or not (0)",
+		},
+		[]string{"condition"},
+	)
+
+	ServerStatusPollTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_server_status_poll_total",
+			Help: "Total number of background server status polls",
+		},
+		[]string{"status"},
+	)
+
+	ServerStatusChangesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xrpl_validator_server_status_changes_total",
+			Help: "Total number of material server status changes",
+		},
+		[]string{"reason"},
+	)
 )
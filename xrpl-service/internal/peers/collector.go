@@ -0,0 +1,211 @@
+package peers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultCacheTTL = time.Minute
+
+// IPGeoResolver resolves peer IP addresses to geolocation, returning the
+// locations found by IP.
+type IPGeoResolver interface {
+	ResolveIPsGeo(ips []string) map[string]*models.GeoLocation
+}
+
+// Collector queries the admin `peers` command of a local rippled and
+// aggregates how that node is connected to the rest of the network.
+type Collector struct {
+	client      xrpl.NodeClient
+	geoResolver IPGeoResolver
+	clock       clock.Clock
+	logger      *logrus.Logger
+	cacheTTL    time.Duration
+
+	mu         sync.Mutex
+	cached     *models.PeerSummary
+	cachedAt   time.Time
+	refreshing *peerRefresh // nil unless a refresh is in flight
+}
+
+// peerRefresh is a refresh of the peer summary that is in flight until done
+// is closed.
+type peerRefresh struct {
+	done    chan struct{}
+	summary *models.PeerSummary
+	err     error
+}
+
+// NewCollector creates a new peer collector. The client must point at an
+// admin-enabled JSON-RPC port because `peers` is an admin command.
+func NewCollector(client xrpl.NodeClient, geoResolver IPGeoResolver, cacheTTL time.Duration, clk clock.Clock, logger *logrus.Logger) *Collector {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	if cacheTTL <= 0 {
+		cacheTTL = defaultCacheTTL
+	}
+	return &Collector{
+		client:      client,
+		geoResolver: geoResolver,
+		clock:       clock.OrReal(clk),
+		logger:      logger,
+		cacheTTL:    cacheTTL,
+	}
+}
+
+// GetPeers returns the aggregated peer summary, reusing a cached result while
+// it is younger than the cache TTL. The lock only guards the cached summary,
+// so a slow admin endpoint does not hold up callers that find it fresh.
+// Callers that miss the cache while a refresh is in flight wait for it
+// instead of querying the node again, giving up when ctx is done.
+func (c *Collector) GetPeers(ctx context.Context) (*models.PeerSummary, error) {
+	c.mu.Lock()
+	if c.cached != nil && c.clock.Since(c.cachedAt) < c.cacheTTL {
+		cached := c.cached
+		c.mu.Unlock()
+		return cached, nil
+	}
+	if refresh := c.refreshing; refresh != nil {
+		c.mu.Unlock()
+		select {
+		case <-refresh.done:
+			return refresh.summary, refresh.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	refresh := &peerRefresh{done: make(chan struct{})}
+	c.refreshing = refresh
+	c.mu.Unlock()
+
+	refresh.summary, refresh.err = c.refresh(ctx)
+
+	c.mu.Lock()
+	if refresh.err == nil {
+		c.cached = refresh.summary
+		c.cachedAt = c.clock.Now()
+	}
+	c.refreshing = nil
+	c.mu.Unlock()
+	close(refresh.done)
+	return refresh.summary, refresh.err
+}
+
+// refresh queries the node's peers and locates them.
+func (c *Collector) refresh(ctx context.Context) (*models.PeerSummary, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("XRPL client is nil")
+	}
+	resp, err := c.client.Command(ctx, "peers", map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("peers command failed: %w", err)
+	}
+	summary, err := parsePeersResponse(resp, c.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	c.locatePeers(summary)
+	return summary, nil
+}
+
+// locatePeers resolves the peers' IPs in one batch.
+func (c *Collector) locatePeers(summary *models.PeerSummary) {
+	if c.geoResolver == nil {
+		return
+	}
+	ips := make([]string, 0, len(summary.Peers))
+	for _, peer := range summary.Peers {
+		if peer.IP != "" {
+			ips = append(ips, peer.IP)
+		}
+	}
+	if len(ips) == 0 {
+		return
+	}
+	locations := c.geoResolver.ResolveIPsGeo(ips)
+	for _, peer := range summary.Peers {
+		if geo, ok := locations[peer.IP]; ok {
+			peer.Location = geo
+			summary.Located++
+		}
+	}
+}
+
+func parsePeersResponse(resp interface{}, now time.Time) (*models.PeerSummary, error) {
+	r := jsonutil.NewReader(resp)
+	result := r.Map("result")
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("unexpected peers response: %w", err)
+	}
+	if jsonutil.String(result, "status") == "error" {
+		return nil, fmt.Errorf("peers command error: %v", result["error"])
+	}
+	rawPeers := r.Slice("result.peers")
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("peers response missing peers list: %w", err)
+	}
+
+	summary := &models.PeerSummary{
+		Versions:  make(map[string]int),
+		Peers:     make([]*models.PeerInfo, 0, len(rawPeers)),
+		Timestamp: now.Unix(),
+	}
+	for _, raw := range rawPeers {
+		peerMap := jsonutil.Object(raw)
+		if peerMap == nil {
+			continue
+		}
+		peer := &models.PeerInfo{
+			IP:        peerIP(jsonutil.String(peerMap, "address")),
+			Version:   strings.TrimSpace(jsonutil.String(peerMap, "version")),
+			LatencyMs: jsonutil.Int64(peerMap, "latency"),
+			Uptime:    jsonutil.Int64(peerMap, "uptime"),
+			Inbound:   jsonutil.Bool(peerMap, "inbound"),
+		}
+
+		version := peer.Version
+		if version == "" {
+			version = "unknown"
+		}
+		summary.Versions[version]++
+		if peer.Inbound {
+			summary.Inbound++
+		} else {
+			summary.Outbound++
+		}
+		summary.Peers = append(summary.Peers, peer)
+	}
+	summary.Total = len(summary.Peers)
+	sort.Slice(summary.Peers, func(i, j int) bool { return summary.Peers[i].IP < summary.Peers[j].IP })
+	return summary, nil
+}
+
+// peerIP extracts a public IP from addresses like "1.2.3.4:51235" or
+// "[::ffff:1.2.3.4]:51235".
+func peerIP(address string) string {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return ""
+	}
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	if ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsUnspecified() {
+		return ""
+	}
+	return ip.String()
+}
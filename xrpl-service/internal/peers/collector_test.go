@@ -0,0 +1,168 @@
+package peers
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+type stubIPResolver struct{}
+
+func (stubIPResolver) ResolveIPsGeo(ips []string) map[string]*models.GeoLocation {
+	locations := make(map[string]*models.GeoLocation)
+	for _, ip := range ips {
+		if ip == "203.0.113.10" {
+			locations[ip] = &models.GeoLocation{Latitude: 50.11, Longitude: 8.68, CountryCode: "DE", City: "Frankfurt"}
+		}
+	}
+	return locations
+}
+
+func TestGetPeersAggregatesVersionsAndDirections(t *testing.T) {
+	client := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"result": map[string]interface{}{
+				"status": "success",
+				"peers": []interface{}{
+					map[string]interface{}{"address": "203.0.113.10:51235", "version": "rippled-2.3.0", "latency": float64(42), "uptime": float64(100)},
+					map[string]interface{}{"address": "[::ffff:198.51.100.7]:51235", "version": "rippled-2.3.0", "inbound": true},
+					map[string]interface{}{"address": "10.0.0.5:51235", "inbound": true},
+				},
+			},
+		}, nil
+	})
+	collector := NewCollector(client, stubIPResolver{}, time.Minute, nil, logrus.New())
+
+	summary, err := collector.GetPeers(context.Background())
+	if err != nil {
+		t.Fatalf("GetPeers failed: %v", err)
+	}
+	if summary.Total != 3 || summary.Inbound != 2 || summary.Outbound != 1 {
+		t.Fatalf("unexpected peer counts: %+v", summary)
+	}
+	if summary.Versions["rippled-2.3.0"] != 2 || summary.Versions["unknown"] != 1 {
+		t.Fatalf("unexpected version distribution: %+v", summary.Versions)
+	}
+	if summary.Located != 1 {
+		t.Fatalf("expected 1 located peer, got %d", summary.Located)
+	}
+
+	ips := map[string]bool{}
+	for _, peer := range summary.Peers {
+		ips[peer.IP] = true
+	}
+	if !ips["198.51.100.7"] || !ips[""] {
+		t.Fatalf("expected mapped IPv4 and hidden private IP, got %+v", ips)
+	}
+
+	if _, err := collector.GetPeers(context.Background()); err != nil {
+		t.Fatalf("cached GetPeers failed: %v", err)
+	}
+	if calls := client.CommandCalls("peers"); calls != 1 {
+		t.Fatalf("expected cached summary to avoid repeat peers calls, got %d", calls)
+	}
+}
+
+func TestGetPeersReportsAdminErrors(t *testing.T) {
+	client := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"result": map[string]interface{}{
+				"status": "error",
+				"error":  "forbidden",
+			},
+		}, nil
+	})
+	collector := NewCollector(client, nil, time.Minute, nil, logrus.New())
+
+	if _, err := collector.GetPeers(context.Background()); err == nil {
+		t.Fatal("expected error for non-admin peers response")
+	}
+}
+
+func TestGetPeersSharesAnInFlightRefresh(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	client := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		calls.Add(1)
+		<-release
+		return map[string]interface{}{
+			"result": map[string]interface{}{"status": "success", "peers": []interface{}{}},
+		}, nil
+	})
+	clk := clock.NewFake(time.Unix(1_700_000_000, 0))
+	collector := NewCollector(client, nil, time.Minute, clk, logrus.New())
+
+	const callers = 4
+	done := make(chan *models.PeerSummary, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			summary, err := collector.GetPeers(context.Background())
+			if err != nil {
+				t.Errorf("GetPeers failed: %v", err)
+			}
+			done <- summary
+		}()
+	}
+	// Let every caller miss the cache while the first admin call is slow.
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() < 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	first := <-done
+	for i := 1; i < callers; i++ {
+		if summary := <-done; summary != first {
+			t.Fatal("expected concurrent callers to share one summary")
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected one admin call for concurrent callers, got %d", got)
+	}
+
+	clk.Advance(30 * time.Second)
+	collector.GetPeers(context.Background())
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected the cached summary within the TTL, got %d calls", got)
+	}
+	clk.Advance(31 * time.Second)
+	collector.GetPeers(context.Background())
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected a refresh once the TTL passed on the injected clock, got %d calls", got)
+	}
+}
+
+func TestGetPeersWaitersGiveUpWithTheirContext(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	client := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		<-release
+		return map[string]interface{}{
+			"result": map[string]interface{}{"status": "success", "peers": []interface{}{}},
+		}, nil
+	})
+	collector := NewCollector(client, nil, time.Minute, nil, logrus.New())
+	go collector.GetPeers(context.Background())
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		collector.mu.Lock()
+		inFlight := collector.refreshing != nil
+		collector.mu.Unlock()
+		if inFlight {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := collector.GetPeers(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected the waiter to give up with its context, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+// Package preferences keeps per-API-key stream defaults, so kiosks and
+// embeds that cannot send query parameters or subscribe messages still get
+// a filtered stream. Preferences are keyed by API key name, never by the
+// key itself.
+package preferences
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/cachefile"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxChannels and maxTopics bound the lists one key stores.
+	maxChannels = 32
+	maxTopics   = 32
+
+	// maxChannelLength bounds channel names, which are event types.
+	maxChannelLength = 64
+)
+
+const preferencesFileVersion = 1
+
+// preferencesFileFormat upgrades older preferences files on load. When
+// bumping preferencesFileVersion, register a migration from the previous
+// version here.
+var preferencesFileFormat = cachefile.Format{
+	Name:       "client preferences",
+	Version:    preferencesFileVersion,
+	Migrations: map[int]cachefile.MigrateFunc{},
+}
+
+type preferencesFile struct {
+	Version     int                         `json:"version"`
+	Preferences []*models.ClientPreferences `json:"preferences"`
+}
+
+// ErrInvalidPreferences is returned by Put for preferences that fail
+// validation.
+var ErrInvalidPreferences = errors.New("invalid client preferences")
+
+// Store holds client preferences and persists every change to its file. It
+// is safe for concurrent use.
+type Store struct {
+	path   string
+	clock  clock.Clock
+	logger *logrus.Logger
+
+	mu          sync.RWMutex
+	preferences map[string]*models.ClientPreferences // API key name -> preferences
+}
+
+// Load reads the preferences file at path. A missing file starts an empty
+// store; the file is created by the first Put.
+func Load(path string, clk clock.Clock, logger *logrus.Logger) (*Store, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	s := &Store{
+		path:        path,
+		clock:       clock.OrReal(clk),
+		logger:      logger,
+		preferences: make(map[string]*models.ClientPreferences),
+	}
+	data, _, err := preferencesFileFormat.Load(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	var file preferencesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse client preferences %s: %w", path, err)
+	}
+	for _, prefs := range file.Preferences {
+		if prefs == nil {
+			continue
+		}
+		if err := validate(prefs); err != nil {
+			return nil, fmt.Errorf("client preferences %s: %w", path, err)
+		}
+		s.preferences[prefs.Name] = prefs
+	}
+	return s, nil
+}
+
+// Get returns a copy of the preferences of the API key named name.
+func (s *Store) Get(name string) (*models.ClientPreferences, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	prefs, ok := s.preferences[name]
+	if !ok {
+		return nil, false
+	}
+	stored := *prefs
+	return &stored, true
+}
+
+// Put replaces the preferences of the API key named name and persists the
+// store. It returns the stored preferences.
+func (s *Store) Put(name string, prefs models.ClientPreferences) (*models.ClientPreferences, error) {
+	prefs.Name = name
+	prefs.Units = strings.ToLower(strings.TrimSpace(prefs.Units))
+	prefs.Shape = strings.ToLower(strings.TrimSpace(prefs.Shape))
+	if err := validate(&prefs); err != nil {
+		return nil, err
+	}
+	prefs.UpdatedAt = s.clock.Now().UnixMilli()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, exists := s.preferences[name]
+	s.preferences[name] = &prefs
+	if err := s.persist(); err != nil {
+		if exists {
+			s.preferences[name] = previous
+		} else {
+			delete(s.preferences, name)
+		}
+		return nil, fmt.Errorf("persist client preferences: %w", err)
+	}
+
+	s.logger.WithField("api_key", name).Info("Client preferences saved")
+	stored := prefs
+	return &stored, nil
+}
+
+// persist writes the store to its file. The caller holds s.mu.
+func (s *Store) persist() error {
+	file := preferencesFile{
+		Version:     preferencesFileVersion,
+		Preferences: make([]*models.ClientPreferences, 0, len(s.preferences)),
+	}
+	for _, prefs := range s.preferences {
+		file.Preferences = append(file.Preferences, prefs)
+	}
+	sort.Slice(file.Preferences, func(i, j int) bool { return file.Preferences[i].Name < file.Preferences[j].Name })
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return preferencesFileFormat.Write(s.path, data)
+}
+
+// validate checks the fields the store understands. Topic syntax is left to
+// the server, which parses them.
+func validate(prefs *models.ClientPreferences) error {
+	if prefs.Name == "" {
+		return fmt.Errorf("%w: an API key name is required", ErrInvalidPreferences)
+	}
+	if prefs.MinPaymentDrops < 0 {
+		return fmt.Errorf("%w: min_payment_drops cannot be negative", ErrInvalidPreferences)
+	}
+	switch prefs.Units {
+	case "", models.UnitsDrops, models.UnitsXRP:
+	default:
+		return fmt.Errorf("%w: units must be %s or %s", ErrInvalidPreferences, models.UnitsDrops, models.UnitsXRP)
+	}
+	switch prefs.Shape {
+	case "", models.TransactionShapeLocations, models.TransactionShapeRoles, models.TransactionShapeBoth:
+	default:
+		return fmt.Errorf("%w: shape must be locations, roles or both", ErrInvalidPreferences)
+	}
+	if len(prefs.Channels) > maxChannels {
+		return fmt.Errorf("%w: at most %d channels", ErrInvalidPreferences, maxChannels)
+	}
+	for _, channel := range prefs.Channels {
+		if channel == "" || len(channel) > maxChannelLength {
+			return fmt.Errorf("%w: channels must be 1 to %d characters", ErrInvalidPreferences, maxChannelLength)
+		}
+	}
+	if len(prefs.Topics) > maxTopics {
+		return fmt.Errorf("%w: at most %d topics", ErrInvalidPreferences, maxTopics)
+	}
+	return nil
+}
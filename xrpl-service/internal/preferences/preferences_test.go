@@ -0,0 +1,84 @@
+package preferences
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestStorePersistsPreferences(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "client-preferences.json")
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	store, err := Load(path, fake, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, ok := store.Get("kiosk"); ok {
+		t.Fatal("expected no preferences in a new store")
+	}
+
+	prefs, err := store.Put("kiosk", models.ClientPreferences{
+		Name:            "ignored",
+		MinPaymentDrops: 1_000_000,
+		Channels:        []string{"transactions"},
+		Units:           " XRP ",
+	})
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if prefs.Name != "kiosk" || prefs.Units != models.UnitsXRP || prefs.UpdatedAt != fake.Now().UnixMilli() {
+		t.Fatalf("unexpected stored preferences %+v", prefs)
+	}
+
+	reloaded, err := Load(path, fake, nil)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	got, ok := reloaded.Get("kiosk")
+	if !ok || got.MinPaymentDrops != 1_000_000 || len(got.Channels) != 1 || got.Units != models.UnitsXRP {
+		t.Fatalf("expected the preferences persisted, got %+v", got)
+	}
+}
+
+func TestStoreRejectsInvalidPreferences(t *testing.T) {
+	store, err := Load(filepath.Join(t.TempDir(), "client-preferences.json"), nil, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, prefs := range []models.ClientPreferences{
+		{MinPaymentDrops: -1},
+		{Units: "satoshis"},
+		{Shape: "square"},
+		{Channels: []string{""}},
+		{Topics: make([]string, maxTopics+1)},
+	} {
+		if _, err := store.Put("kiosk", prefs); !errors.Is(err, ErrInvalidPreferences) {
+			t.Errorf("Put(%+v): expected ErrInvalidPreferences, got %v", prefs, err)
+		}
+	}
+	if _, err := store.Put("", models.ClientPreferences{}); !errors.Is(err, ErrInvalidPreferences) {
+		t.Errorf("expected a key name required, got %v", err)
+	}
+}
+
+func TestStoreRollsBackFailedWrites(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing")
+	store, err := Load(filepath.Join(dir, "client-preferences.json"), nil, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := os.WriteFile(dir, nil, 0o600); err != nil {
+		t.Fatalf("block directory: %v", err)
+	}
+	if _, err := store.Put("kiosk", models.ClientPreferences{Units: models.UnitsXRP}); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	if _, ok := store.Get("kiosk"); ok {
+		t.Fatal("expected the failed preferences rolled back")
+	}
+}
@@ -0,0 +1,133 @@
+package replica
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gorilla/websocket"
+)
+
+func TestValidatorsFetchReportsChangesAfterInitialLoad(t *testing.T) {
+	var mu sync.Mutex
+	validators := []*models.Validator{{Address: "nA", Domain: "a.example"}, {Address: "nB"}}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/validators":
+			json.NewEncoder(w).Encode(map[string]interface{}{"validators": validators, "count": len(validators)})
+		case "/network-health":
+			json.NewEncoder(w).Encode(map[string]interface{}{"server": models.ServerStatus{LedgerIndex: 42}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	mirror := NewValidators(upstream.URL, time.Minute, nil)
+	var updates []*models.ValidatorUpdate
+	mirror.AddCallback(func(update *models.ValidatorUpdate) { updates = append(updates, update) })
+
+	if err := mirror.Fetch(context.Background()); err != nil {
+		t.Fatalf("initial fetch failed: %v", err)
+	}
+	if len(mirror.GetValidators()) != 2 || len(updates) != 0 {
+		t.Fatalf("expected 2 validators and no updates on initial load, got %d and %d", len(mirror.GetValidators()), len(updates))
+	}
+
+	mu.Lock()
+	validators = []*models.Validator{{Address: "nA", Domain: "b.example"}}
+	mu.Unlock()
+	if err := mirror.Fetch(context.Background()); err != nil {
+		t.Fatalf("second fetch failed: %v", err)
+	}
+	if len(updates) != 1 || len(updates[0].Upserts) != 1 || len(updates[0].Removals) != 1 {
+		t.Fatalf("expected one upsert and one removal, got %+v", updates)
+	}
+
+	status, err := mirror.GetServerStatus(context.Background())
+	if err != nil || status.LedgerIndex != 42 {
+		t.Fatalf("expected upstream server status, got %+v, %v", status, err)
+	}
+}
+
+func TestStreamRelaysTransactionsAndGeoUpdates(t *testing.T) {
+	upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+	gotOrigin := make(chan string, 1)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			json.NewEncoder(w).Encode(map[string]interface{}{"min_payment_drops": 5000000})
+		case "/transactions":
+			gotOrigin <- r.Header.Get("Origin") + "|" + r.URL.Query().Get("api_key")
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			conn.WriteJSON(&models.StreamEvent{Type: "server_status", Data: map[string]int{"ledger_index": 1}})
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"hash":"ABC","source_info":{"latitude":1,"longitude":2}}`))
+			conn.WriteJSON(&models.StreamEvent{Type: "tx_geo_update", Data: &models.TxGeoUpdate{Hash: "ABC"}})
+			conn.ReadMessage()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	stream, err := NewStream(upstream.URL, "https://edge.example", "edge-key", nil)
+	if err != nil {
+		t.Fatalf("failed to create stream: %v", err)
+	}
+	txs := make(chan *models.Transaction, 4)
+	geoUpdates := make(chan *models.TxGeoUpdate, 4)
+	stream.AddCallback(func(tx *models.Transaction) { txs <- tx })
+	stream.AddGeoUpdateCallback(func(update *models.TxGeoUpdate) { geoUpdates <- update })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream.Start(ctx)
+	defer stream.Stop()
+
+	if origin := <-gotOrigin; origin != "https://edge.example|edge-key" {
+		t.Fatalf("unexpected origin and API key: %s", origin)
+	}
+	select {
+	case tx := <-txs:
+		if tx.Hash != "ABC" || len(tx.Locations) != 1 || tx.Locations[0].Role != models.LocationRoleSource {
+			t.Fatalf("unexpected relayed transaction: %+v", tx)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for relayed transaction")
+	}
+	select {
+	case update := <-geoUpdates:
+		if update.Hash != "ABC" {
+			t.Fatalf("unexpected geo update: %+v", update)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for relayed geo update")
+	}
+	if len(txs) != 0 {
+		t.Fatal("expected server_status event not to be relayed as a transaction")
+	}
+	if !stream.IsSubscribed() || stream.MinPaymentDrops() != 5000000 {
+		t.Fatalf("expected subscribed stream with upstream threshold, got %v and %d", stream.IsSubscribed(), stream.MinPaymentDrops())
+	}
+}
+
+func TestStreamURLFor(t *testing.T) {
+	got, err := streamURLFor("https://primary.example/api/", "")
+	if err != nil || got != "wss://primary.example/api/transactions" {
+		t.Fatalf("unexpected stream URL %q, %v", got, err)
+	}
+	if _, err := streamURLFor("ftp://primary.example", ""); err == nil {
+		t.Fatal("expected error for unsupported scheme")
+	}
+}
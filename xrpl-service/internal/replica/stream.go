@@ -0,0 +1,316 @@
+package replica
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/transaction"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// readTimeout must exceed the upstream's 54s ping interval.
+const readTimeout = 90 * time.Second
+
+// Stream relays the transaction stream of an upstream instance, in place of
+// transaction.Listener. Transactions arrive already filtered and enriched.
+// Server status and validator events are skipped because the replica
+// regenerates them from its own polling.
+type Stream struct {
+	baseURL    string
+	streamURL  string
+	origin     string
+	httpClient *http.Client
+	logger     *logrus.Logger
+
+	mu              sync.RWMutex
+	conn            *websocket.Conn
+	subscribed      bool
+	minPaymentDrops int64
+	callbacks       []transaction.TransactionCallback
+	geoCallbacks    []transaction.GeoUpdateCallback
+
+	// backoff is owned by the reconnect loop in Start.
+	backoff *xrpl.Backoff
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// StreamOptions controls optional relay behavior.
+type StreamOptions struct {
+	// Reconnect paces reconnects after the upstream connection drops or
+	// cannot be established. Zero fields take the xrpl package defaults.
+	Reconnect xrpl.BackoffPolicy
+}
+
+// NewStream creates a relay for the upstream instance at baseURL. origin is
+// sent as the Origin header and must be allowed by the upstream's CORS
+// configuration. apiKey is passed as the api_key query parameter when set.
+func NewStream(baseURL, origin, apiKey string, logger *logrus.Logger, options ...StreamOptions) (*Stream, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	var opts StreamOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	streamURL, err := streamURLFor(baseURL, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		streamURL:  streamURL,
+		origin:     origin,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		logger:     logger,
+		backoff:    xrpl.NewBackoff(opts.Reconnect),
+		stopChan:   make(chan struct{}),
+	}, nil
+}
+
+// streamURLFor maps an upstream base URL to its /transactions WebSocket URL.
+func streamURLFor(baseURL, apiKey string) (string, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid upstream URL: %w", err)
+	}
+	switch parsed.Scheme {
+	case "http":
+		parsed.Scheme = "ws"
+	case "https":
+		parsed.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("upstream URL must use http or https, got %q", parsed.Scheme)
+	}
+	parsed.Path = strings.TrimRight(parsed.Path, "/") + "/transactions"
+	if apiKey != "" {
+		query := parsed.Query()
+		query.Set("api_key", apiKey)
+		parsed.RawQuery = query.Encode()
+	}
+	return parsed.String(), nil
+}
+
+// AddCallback registers a callback for relayed transactions.
+func (s *Stream) AddCallback(callback transaction.TransactionCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks = append(s.callbacks, callback)
+}
+
+// AddGeoUpdateCallback registers a callback for relayed late geolocation
+// results.
+func (s *Stream) AddGeoUpdateCallback(callback transaction.GeoUpdateCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.geoCallbacks = append(s.geoCallbacks, callback)
+}
+
+// IsSubscribed reports whether the upstream stream is connected.
+func (s *Stream) IsSubscribed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.subscribed
+}
+
+// MinPaymentDrops returns the upstream's payment threshold, as last reported
+// by its /health endpoint.
+func (s *Stream) MinPaymentDrops() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.minPaymentDrops
+}
+
+// Start connects to the upstream and keeps reconnecting until ctx is done or
+// Stop is called. Reconnects back off under the reconnect policy; a
+// connection that was established resets it.
+func (s *Stream) Start(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			err := s.run(ctx)
+			wait := s.backoff.Failure()
+			if err != nil {
+				entry := s.logger.WithError(err).WithFields(logrus.Fields{
+					"failures": s.backoff.Failures(),
+					"retry_in": wait.String(),
+				})
+				if s.backoff.State() == xrpl.CircuitOpen {
+					metrics.UpstreamCircuitOpen.WithLabelValues("replica").Set(1)
+					entry.Error("Upstream transaction stream reconnects keep failing, pausing them")
+				} else {
+					entry.Warn("Upstream transaction stream disconnected")
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopChan:
+				return
+			case <-time.After(wait):
+			}
+		}
+	}()
+}
+
+// Stop closes the upstream connection and waits for the relay to exit.
+func (s *Stream) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+		s.mu.RLock()
+		conn := s.conn
+		s.mu.RUnlock()
+		if conn != nil {
+			conn.Close()
+		}
+	})
+	s.wg.Wait()
+}
+
+// run holds one upstream connection until it fails.
+func (s *Stream) run(ctx context.Context) error {
+	s.refreshMinPaymentDrops(ctx)
+
+	header := http.Header{}
+	if s.origin != "" {
+		header.Set("Origin", s.origin)
+	}
+	// The relay parses v1 frames, so it asks for v1 even when the upstream
+	// prefers a newer protocol.
+	dialer := websocket.Dialer{HandshakeTimeout: 15 * time.Second, Subprotocols: []string{models.StreamProtocolV1}}
+	conn, resp, err := dialer.DialContext(ctx, s.streamURL, header)
+	if err != nil {
+		if resp != nil {
+			err = &xrpl.HTTPStatusError{StatusCode: resp.StatusCode}
+		} else {
+			err = xrpl.WrapTransportError(err)
+		}
+		metrics.UpstreamErrorsTotal.WithLabelValues("replica", xrpl.Classify(err)).Inc()
+		metrics.UpstreamReconnectsTotal.WithLabelValues("replica", "failure").Inc()
+		return fmt.Errorf("failed to connect to upstream stream: %w", err)
+	}
+
+	s.mu.Lock()
+	select {
+	case <-s.stopChan:
+		s.mu.Unlock()
+		conn.Close()
+		return nil
+	default:
+	}
+	s.conn = conn
+	s.subscribed = true
+	s.mu.Unlock()
+	s.logger.WithField("url", s.baseURL).Info("Connected to upstream transaction stream")
+	s.backoff.Reset()
+	metrics.UpstreamReconnectsTotal.WithLabelValues("replica", "success").Inc()
+	metrics.UpstreamCircuitOpen.WithLabelValues("replica").Set(0)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	defer func() {
+		s.mu.Lock()
+		s.conn = nil
+		s.subscribed = false
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	conn.SetReadDeadline(time.Now().Add(readTimeout))
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(10*time.Second))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-s.stopChan:
+				return nil
+			default:
+			}
+			return err
+		}
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		s.handleMessage(data)
+	}
+}
+
+// handleMessage dispatches one upstream message. Messages without a type are
+// transactions; typed messages are stream events.
+func (s *Stream) handleMessage(data []byte) {
+	var envelope struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		s.logger.WithError(err).Debug("Skipping undecodable upstream message")
+		return
+	}
+
+	switch envelope.Type {
+	case "":
+		var tx models.Transaction
+		if err := json.Unmarshal(data, &tx); err != nil || tx.Hash == "" {
+			s.logger.WithError(err).Debug("Skipping undecodable upstream transaction")
+			return
+		}
+		s.mu.RLock()
+		callbacks := append([]transaction.TransactionCallback(nil), s.callbacks...)
+		s.mu.RUnlock()
+		for _, callback := range callbacks {
+			callback(&tx)
+		}
+	case "tx_geo_update":
+		var update models.TxGeoUpdate
+		if err := json.Unmarshal(envelope.Data, &update); err != nil || update.Hash == "" {
+			s.logger.WithError(err).Debug("Skipping undecodable upstream geo update")
+			return
+		}
+		s.mu.RLock()
+		callbacks := append([]transaction.GeoUpdateCallback(nil), s.geoCallbacks...)
+		s.mu.RUnlock()
+		for _, callback := range callbacks {
+			callback(&update)
+		}
+	}
+}
+
+// refreshMinPaymentDrops reads min_payment_drops from the upstream's /health
+// endpoint so the replica reports the threshold its stream is filtered by.
+func (s *Stream) refreshMinPaymentDrops(ctx context.Context) {
+	var payload struct {
+		MinPaymentDrops int64 `json:"min_payment_drops"`
+	}
+	if err := getJSON(ctx, s.httpClient, s.baseURL+"/health", &payload); err != nil {
+		metrics.UpstreamErrorsTotal.WithLabelValues("replica", xrpl.Classify(err)).Inc()
+		s.logger.WithError(err).Debug("Failed to read upstream health")
+		return
+	}
+	s.mu.Lock()
+	s.minPaymentDrops = payload.MinPaymentDrops
+	s.mu.Unlock()
+}
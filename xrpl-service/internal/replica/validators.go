@@ -0,0 +1,218 @@
+package replica
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultRefreshInterval = 30 * time.Second
+
+// Validators mirrors the validator list and server status of an upstream
+// instance through its REST API, in place of validator.Fetcher.
+type Validators struct {
+	baseURL         string
+	httpClient      *http.Client
+	refreshInterval time.Duration
+	logger          *logrus.Logger
+	mu              sync.RWMutex
+	validators      []*models.Validator
+	lastUpdate      time.Time
+	callbacks       []validator.UpdateCallback
+	stopChan        chan struct{}
+	stopOnce        sync.Once
+}
+
+// NewValidators creates a mirror of the upstream instance at baseURL
+// (e.g. "http://primary:8080").
+func NewValidators(baseURL string, refreshInterval time.Duration, logger *logrus.Logger) *Validators {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	if refreshInterval <= 0 {
+		refreshInterval = defaultRefreshInterval
+	}
+	return &Validators{
+		baseURL:         strings.TrimRight(baseURL, "/"),
+		httpClient:      &http.Client{Timeout: 15 * time.Second},
+		refreshInterval: refreshInterval,
+		logger:          logger,
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// AddCallback registers a callback for validator changes between polls.
+func (v *Validators) AddCallback(callback validator.UpdateCallback) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.callbacks = append(v.callbacks, callback)
+}
+
+// Start begins periodic polling of the upstream validator list.
+func (v *Validators) Start(ctx context.Context) {
+	go func() {
+		if err := v.Fetch(ctx); err != nil {
+			v.logger.WithError(err).Error("Initial upstream validator fetch failed")
+		}
+
+		ticker := time.NewTicker(v.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-v.stopChan:
+				return
+			case <-ticker.C:
+				if err := v.Fetch(ctx); err != nil {
+					v.logger.WithError(err).Warn("Upstream validator fetch failed")
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops periodic polling.
+func (v *Validators) Stop() {
+	v.stopOnce.Do(func() {
+		close(v.stopChan)
+	})
+}
+
+// Fetch replaces the mirrored list with the upstream /validators response and
+// notifies callbacks of any changes after the initial load.
+func (v *Validators) Fetch(ctx context.Context) error {
+	var payload struct {
+		Validators []*models.Validator `json:"validators"`
+		Timestamp  time.Time           `json:"timestamp"`
+	}
+	if err := v.getJSON(ctx, "/validators", &payload); err != nil {
+		return fmt.Errorf("failed to fetch upstream validators: %w", err)
+	}
+
+	current := make(map[string]*models.Validator, len(payload.Validators))
+	validators := make([]*models.Validator, 0, len(payload.Validators))
+	for _, item := range payload.Validators {
+		if item == nil || item.Address == "" {
+			continue
+		}
+		current[item.Address] = item
+		validators = append(validators, item)
+	}
+	lastUpdate := payload.Timestamp
+	if lastUpdate.IsZero() {
+		lastUpdate = time.Now()
+	}
+
+	v.mu.Lock()
+	previous := make(map[string]*models.Validator, len(v.validators))
+	for _, item := range v.validators {
+		previous[item.Address] = item
+	}
+	initialLoad := v.lastUpdate.IsZero()
+	v.validators = validators
+	v.lastUpdate = lastUpdate
+	callbacks := append([]validator.UpdateCallback(nil), v.callbacks...)
+	v.mu.Unlock()
+
+	if !initialLoad && len(callbacks) > 0 {
+		if update := validator.DiffValidators(previous, current); len(update.Upserts) > 0 || len(update.Removals) > 0 {
+			for _, callback := range callbacks {
+				callback(update)
+			}
+		}
+	}
+
+	v.logger.WithField("count", len(validators)).Debug("Upstream validators updated")
+	return nil
+}
+
+// GetValidators returns the mirrored validators.
+func (v *Validators) GetValidators() []*models.Validator {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return append([]*models.Validator(nil), v.validators...)
+}
+
+// GetLastUpdate returns the upstream's last validator update time.
+func (v *Validators) GetLastUpdate() time.Time {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.lastUpdate
+}
+
+// DataSource reports the validators as mirrored from the upstream instance.
+func (v *Validators) DataSource() string {
+	return "replica"
+}
+
+// GetServerStatus returns the server status reported by the upstream's
+// /network-health endpoint.
+func (v *Validators) GetServerStatus(ctx context.Context) (*models.ServerStatus, error) {
+	var payload struct {
+		Server *models.ServerStatus `json:"server"`
+	}
+	if err := v.getJSON(ctx, "/network-health", &payload); err != nil {
+		return nil, fmt.Errorf("failed to fetch upstream network health: %w", err)
+	}
+	if payload.Server == nil {
+		return nil, fmt.Errorf("%w: upstream network health has no server status", xrpl.ErrDecode)
+	}
+	return payload.Server, nil
+}
+
+// GetDomainHistory returns the validator's domain history from the
+// upstream instance.
+func (v *Validators) GetDomainHistory(ctx context.Context, address string) (*models.DomainHistory, error) {
+	var history models.DomainHistory
+	if err := v.getJSON(ctx, "/validators/"+url.PathEscape(address)+"/domain-history", &history); err != nil {
+		return nil, fmt.Errorf("failed to fetch upstream domain history: %w", err)
+	}
+	return &history, nil
+}
+
+func (v *Validators) getJSON(ctx context.Context, path string, out interface{}) error {
+	err := getJSON(ctx, v.httpClient, v.baseURL+path, out)
+	if err != nil {
+		metrics.UpstreamErrorsTotal.WithLabelValues("replica", xrpl.Classify(err)).Inc()
+	}
+	return err
+}
+
+// getJSON decodes a GET response from the upstream instance, classifying
+// failures with the shared xrpl error classes.
+func getJSON(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return xrpl.WrapTransportError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 120))
+		return &xrpl.HTTPStatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(snippet))}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %s: %w", xrpl.ErrDecode, url, err)
+	}
+	return nil
+}
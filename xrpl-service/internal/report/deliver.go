@@ -0,0 +1,146 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+// Deliver POSTs report to every webhook and writes it to the output
+// directory. Failures are logged and counted; one failing destination does
+// not stop the others.
+func (r *Reporter) Deliver(ctx context.Context, report *models.NetworkReport) {
+	body, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to encode network report")
+		return
+	}
+
+	for _, webhookURL := range r.webhookURLs {
+		if err := r.postWebhook(ctx, webhookURL, body); err != nil {
+			metrics.ReportDeliveriesTotal.WithLabelValues("webhook", "error").Inc()
+			r.logger.WithError(err).WithField("url", webhookURL).Warn("Failed to deliver network report")
+			continue
+		}
+		metrics.ReportDeliveriesTotal.WithLabelValues("webhook", "ok").Inc()
+	}
+
+	if r.outputDir != "" {
+		if err := r.writeFiles(report, body); err != nil {
+			metrics.ReportDeliveriesTotal.WithLabelValues("file", "error").Inc()
+			r.logger.WithError(err).WithField("dir", r.outputDir).Warn("Failed to write network report")
+		} else {
+			metrics.ReportDeliveriesTotal.WithLabelValues("file", "ok").Inc()
+		}
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"period":     report.Period,
+		"validators": report.Validators.End,
+		"payments":   report.Volume.Payments,
+	}).Info("Network report delivered")
+}
+
+func (r *Reporter) postWebhook(ctx context.Context, webhookURL string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return xrpl.WrapTransportError(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &xrpl.HTTPStatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// writeFiles writes network-report-<period>-<date>.{json,md} and refreshes
+// network-report-latest.{json,md} for status pages.
+func (r *Reporter) writeFiles(report *models.NetworkReport, body []byte) error {
+	if err := os.MkdirAll(r.outputDir, 0o755); err != nil {
+		return err
+	}
+	date := time.Unix(report.PeriodStart, 0).UTC().Format("2006-01-02")
+	markdown := []byte(RenderMarkdown(report))
+	for _, name := range []string{"network-report-" + report.Period + "-" + date, "network-report-latest"} {
+		if err := writeFileAtomic(filepath.Join(r.outputDir, name+".json"), body); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filepath.Join(r.outputDir, name+".md"), markdown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// RenderMarkdown formats report as a Markdown document.
+func RenderMarkdown(report *models.NetworkReport) string {
+	var b strings.Builder
+	start := time.Unix(report.PeriodStart, 0).UTC().Format(time.RFC3339)
+	end := time.Unix(report.PeriodEnd, 0).UTC().Format(time.RFC3339)
+	title := strings.ToUpper(report.Period[:1]) + report.Period[1:]
+
+	fmt.Fprintf(&b, "# %s XRPL network report (%s)\n\n", title, report.Network)
+	fmt.Fprintf(&b, "%s to %s\n\n", start, end)
+
+	b.WriteString("## Validators\n\n")
+	fmt.Fprintf(&b, "- Count: %d → %d\n", report.Validators.Start, report.Validators.End)
+	fmt.Fprintf(&b, "- Added: %s\n", listOrNone(report.Validators.Added))
+	fmt.Fprintf(&b, "- Removed: %s\n\n", listOrNone(report.Validators.Removed))
+
+	d := report.Decentralization
+	b.WriteString("## Decentralization\n\n")
+	fmt.Fprintf(&b, "- Mapped validators: %d across %d countries\n", d.MappedValidators, d.Countries)
+	if d.TopCountry != "" {
+		fmt.Fprintf(&b, "- Top country: %s (%.1f%%)\n", d.TopCountry, d.TopCountryShare*100)
+	}
+	fmt.Fprintf(&b, "- Country HHI: %.3f\n", d.CountryHHI)
+	fmt.Fprintf(&b, "- Nakamoto coefficient (countries): %d\n\n", d.NakamotoCoefficient)
+
+	b.WriteString("## Volume\n\n")
+	fmt.Fprintf(&b, "- Transactions: %d\n", report.Volume.Transactions)
+	fmt.Fprintf(&b, "- Payments: %d\n", report.Volume.Payments)
+	fmt.Fprintf(&b, "- XRP: %.6f\n", report.Volume.XRP)
+	fmt.Fprintf(&b, "- Flagged: %d\n\n", report.Volume.Flagged)
+
+	b.WriteString("## Top corridors\n\n")
+	if len(report.TopCorridors) == 0 {
+		b.WriteString("No mapped payments.\n")
+		return b.String()
+	}
+	b.WriteString("| From | To | Payments | XRP |\n|---|---|---:|---:|\n")
+	for _, corridor := range report.TopCorridors {
+		fmt.Fprintf(&b, "| %s | %s | %d | %.6f |\n", corridor.SourceCountry, corridor.DestinationCountry, corridor.Payments, corridor.XRP)
+	}
+	return b.String()
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,299 @@
+// Package report composes periodic network summary reports and delivers
+// them to webhooks or to disk for status pages.
+package report
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Report periods.
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+const (
+	maxTopCorridors = 10
+	dropsPerXRP     = 1_000_000
+
+	// consensusStallShare is the share of validators that can stall XRPL
+	// consensus by withholding validations (quorum is 80%).
+	consensusStallShare = 0.2
+)
+
+// ValidatorSource provides the current validator set.
+type ValidatorSource interface {
+	GetValidators() []*models.Validator
+}
+
+// Reporter accumulates transaction volume and corridors over a period and,
+// at each period boundary, delivers a models.NetworkReport.
+type Reporter struct {
+	validators  ValidatorSource
+	period      string
+	network     string
+	webhookURLs []string
+	outputDir   string
+	httpClient  *http.Client
+	logger      *logrus.Logger
+	now         func() time.Time
+
+	mu           sync.Mutex
+	periodStart  time.Time
+	startSet     map[string]struct{}
+	transactions int64
+	payments     int64
+	flagged      int64
+	drops        int64
+	corridors    map[[2]string]*models.CorridorSummary
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewReporter creates a reporter for period (PeriodDaily or PeriodWeekly).
+// Reports are POSTed to each webhook URL and, when outputDir is set, written
+// there as JSON and Markdown.
+func NewReporter(validators ValidatorSource, period, network string, webhookURLs []string, outputDir string, logger *logrus.Logger) (*Reporter, error) {
+	if period != PeriodDaily && period != PeriodWeekly {
+		return nil, fmt.Errorf("unknown report period %q", period)
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &Reporter{
+		validators:  validators,
+		period:      period,
+		network:     network,
+		webhookURLs: webhookURLs,
+		outputDir:   outputDir,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		logger:      logger,
+		now:         time.Now,
+		corridors:   make(map[[2]string]*models.CorridorSummary),
+		stopChan:    make(chan struct{}),
+	}, nil
+}
+
+// ObserveTransaction adds a broadcast transaction to the current period. It
+// is registered as a transaction callback.
+func (r *Reporter) ObserveTransaction(tx *models.Transaction) {
+	if tx == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transactions++
+	if tx.Flagged {
+		r.flagged++
+	}
+	if tx.TransactionType != "Payment" {
+		return
+	}
+	r.payments++
+	drops, err := strconv.ParseInt(tx.Amount, 10, 64)
+	if err != nil {
+		// Issued currency amounts are counted but carry no XRP volume.
+		drops = 0
+	}
+	r.drops += drops
+
+	source, destination, _ := tx.RoleInfo()
+	if source == nil || destination == nil || source.CountryCode == "" || destination.CountryCode == "" {
+		return
+	}
+	key := [2]string{source.CountryCode, destination.CountryCode}
+	corridor, ok := r.corridors[key]
+	if !ok {
+		corridor = &models.CorridorSummary{SourceCountry: key[0], DestinationCountry: key[1]}
+		r.corridors[key] = corridor
+	}
+	corridor.Payments++
+	corridor.XRP += float64(drops) / dropsPerXRP
+}
+
+// Start begins the first period and delivers a report at every period
+// boundary (UTC midnight, or Monday midnight for weekly reports).
+func (r *Reporter) Start(ctx context.Context) {
+	r.reset(r.now())
+
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(nextBoundary(r.period, r.now())))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-r.stopChan:
+				timer.Stop()
+				return
+			case <-timer.C:
+				report := r.Compose(r.now())
+				r.reset(r.now())
+				r.Deliver(ctx, report)
+			}
+		}
+	}()
+}
+
+// Stop stops scheduled reports. The partial period is not reported.
+func (r *Reporter) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopChan)
+	})
+}
+
+// Compose builds the report for the period ending at end without resetting
+// it.
+func (r *Reporter) Compose(end time.Time) *models.NetworkReport {
+	current := r.validators.GetValidators()
+
+	r.mu.Lock()
+	report := &models.NetworkReport{
+		Period:      r.period,
+		PeriodStart: r.periodStart.Unix(),
+		PeriodEnd:   end.Unix(),
+		Network:     r.network,
+		Validators:  compareValidatorSets(r.startSet, current),
+		Volume: models.VolumeSummary{
+			Transactions: r.transactions,
+			Payments:     r.payments,
+			XRP:          float64(r.drops) / dropsPerXRP,
+			Flagged:      r.flagged,
+		},
+		TopCorridors: topCorridors(r.corridors, maxTopCorridors),
+	}
+	r.mu.Unlock()
+
+	report.Decentralization = Decentralization(current)
+	return report
+}
+
+// reset starts a new period at start.
+func (r *Reporter) reset(start time.Time) {
+	startSet := make(map[string]struct{})
+	for _, v := range r.validators.GetValidators() {
+		if v != nil && v.Address != "" {
+			startSet[v.Address] = struct{}{}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.periodStart = start
+	r.startSet = startSet
+	r.transactions = 0
+	r.payments = 0
+	r.flagged = 0
+	r.drops = 0
+	r.corridors = make(map[[2]string]*models.CorridorSummary)
+}
+
+// nextBoundary returns the first period boundary after now, in UTC.
+func nextBoundary(period string, now time.Time) time.Time {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if period == PeriodWeekly {
+		daysUntilMonday := (int(time.Monday) - int(now.Weekday()) + 7) % 7
+		if daysUntilMonday == 0 {
+			daysUntilMonday = 7
+		}
+		return midnight.AddDate(0, 0, daysUntilMonday)
+	}
+	return midnight.AddDate(0, 0, 1)
+}
+
+func compareValidatorSets(start map[string]struct{}, current []*models.Validator) models.ValidatorCountSummary {
+	summary := models.ValidatorCountSummary{Start: len(start), Added: []string{}, Removed: []string{}}
+	seen := make(map[string]struct{}, len(current))
+	for _, v := range current {
+		if v == nil || v.Address == "" {
+			continue
+		}
+		seen[v.Address] = struct{}{}
+		if _, ok := start[v.Address]; !ok {
+			summary.Added = append(summary.Added, v.Address)
+		}
+	}
+	summary.End = len(seen)
+	for address := range start {
+		if _, ok := seen[address]; !ok {
+			summary.Removed = append(summary.Removed, address)
+		}
+	}
+	sort.Strings(summary.Added)
+	sort.Strings(summary.Removed)
+	return summary
+}
+
+// Decentralization measures the country spread of validators with a known
+// country.
+func Decentralization(validators []*models.Validator) models.DecentralizationSummary {
+	counts := make(map[string]int)
+	mapped := 0
+	for _, v := range validators {
+		if v == nil || v.CountryCode == "" || v.CountryCode == "XX" {
+			continue
+		}
+		counts[v.CountryCode]++
+		mapped++
+	}
+	summary := models.DecentralizationSummary{MappedValidators: mapped, Countries: len(counts)}
+	if mapped == 0 {
+		return summary
+	}
+
+	countries := make([]string, 0, len(counts))
+	for country := range counts {
+		countries = append(countries, country)
+	}
+	sort.Slice(countries, func(i, j int) bool {
+		if counts[countries[i]] != counts[countries[j]] {
+			return counts[countries[i]] > counts[countries[j]]
+		}
+		return countries[i] < countries[j]
+	})
+
+	summary.TopCountry = countries[0]
+	summary.TopCountryShare = float64(counts[countries[0]]) / float64(mapped)
+	covered := 0
+	for _, country := range countries {
+		share := float64(counts[country]) / float64(mapped)
+		summary.CountryHHI += share * share
+		if float64(covered) <= consensusStallShare*float64(mapped) {
+			covered += counts[country]
+			summary.NakamotoCoefficient++
+		}
+	}
+	return summary
+}
+
+func topCorridors(corridors map[[2]string]*models.CorridorSummary, limit int) []*models.CorridorSummary {
+	out := make([]*models.CorridorSummary, 0, len(corridors))
+	for _, corridor := range corridors {
+		copy := *corridor
+		out = append(out, &copy)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].XRP != out[j].XRP {
+			return out[i].XRP > out[j].XRP
+		}
+		if out[i].Payments != out[j].Payments {
+			return out[i].Payments > out[j].Payments
+		}
+		return out[i].SourceCountry+out[i].DestinationCountry < out[j].SourceCountry+out[j].DestinationCountry
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
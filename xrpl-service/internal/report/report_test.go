@@ -0,0 +1,150 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+type stubValidators struct {
+	validators []*models.Validator
+}
+
+func (s *stubValidators) GetValidators() []*models.Validator { return s.validators }
+
+func payment(amount, from, to string) *models.Transaction {
+	return &models.Transaction{
+		TransactionType: "Payment",
+		Amount:          amount,
+		Locations: []*models.GeoLocation{
+			{CountryCode: from, Role: models.LocationRoleSource},
+			{CountryCode: to, Role: models.LocationRoleDestination},
+		},
+	}
+}
+
+func TestComposeSummarizesPeriod(t *testing.T) {
+	source := &stubValidators{validators: []*models.Validator{
+		{Address: "nA1", CountryCode: "US"},
+		{Address: "nA2", CountryCode: "US"},
+		{Address: "nA3", CountryCode: "DE"},
+	}}
+	reporter, err := NewReporter(source, PeriodDaily, "mainnet", nil, "", nil)
+	if err != nil {
+		t.Fatalf("NewReporter failed: %v", err)
+	}
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	reporter.reset(start)
+
+	source.validators = []*models.Validator{
+		{Address: "nA1", CountryCode: "US"},
+		{Address: "nA3", CountryCode: "DE"},
+		{Address: "nA4", CountryCode: "JP"},
+		{Address: "nA5", CountryCode: "XX"},
+	}
+	reporter.ObserveTransaction(payment("5000000", "US", "JP"))
+	reporter.ObserveTransaction(payment("1000000", "US", "JP"))
+	reporter.ObserveTransaction(payment("20000000", "DE", "US"))
+	reporter.ObserveTransaction(&models.Transaction{TransactionType: "OfferCreate", Flagged: true})
+
+	report := reporter.Compose(start.Add(24 * time.Hour))
+	if report.Validators.Start != 3 || report.Validators.End != 4 {
+		t.Fatalf("unexpected validator counts %+v", report.Validators)
+	}
+	if strings.Join(report.Validators.Added, ",") != "nA4,nA5" || strings.Join(report.Validators.Removed, ",") != "nA2" {
+		t.Fatalf("unexpected validator changes %+v", report.Validators)
+	}
+	if report.Volume.Transactions != 4 || report.Volume.Payments != 3 || report.Volume.XRP != 26 || report.Volume.Flagged != 1 {
+		t.Fatalf("unexpected volume %+v", report.Volume)
+	}
+	if len(report.TopCorridors) != 2 || report.TopCorridors[0].SourceCountry != "DE" || report.TopCorridors[1].Payments != 2 {
+		t.Fatalf("unexpected corridors %+v", report.TopCorridors)
+	}
+	d := report.Decentralization
+	if d.MappedValidators != 3 || d.Countries != 3 || d.NakamotoCoefficient != 1 {
+		t.Fatalf("unexpected decentralization %+v", d)
+	}
+}
+
+func TestDecentralizationNakamotoCoefficient(t *testing.T) {
+	var validators []*models.Validator
+	for i, country := range []string{"US", "US", "DE", "DE", "JP", "JP", "FR", "GB", "SG", "CA"} {
+		validators = append(validators, &models.Validator{Address: string(rune('a' + i)), CountryCode: country})
+	}
+	d := Decentralization(validators)
+	// The largest countries hold exactly 20% each, so two are needed to exceed it.
+	if d.NakamotoCoefficient != 2 || d.Countries != 7 || d.TopCountry != "DE" {
+		t.Fatalf("unexpected decentralization %+v", d)
+	}
+}
+
+func TestNextBoundary(t *testing.T) {
+	wednesday := time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC)
+	if got := nextBoundary(PeriodDaily, wednesday); !got.Equal(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected daily boundary %s", got)
+	}
+	if got := nextBoundary(PeriodWeekly, wednesday); !got.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected weekly boundary %s", got)
+	}
+	monday := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	if got := nextBoundary(PeriodWeekly, monday); !got.Equal(time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the following Monday at a boundary, got %s", got)
+	}
+}
+
+func TestDeliverPostsWebhookAndWritesFiles(t *testing.T) {
+	received := make(chan models.NetworkReport, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var report models.NetworkReport
+		json.Unmarshal(body, &report)
+		received <- report
+	}))
+	defer webhook.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	dir := t.TempDir()
+	reporter, err := NewReporter(&stubValidators{}, PeriodWeekly, "mainnet", []string{failing.URL, webhook.URL}, dir, nil)
+	if err != nil {
+		t.Fatalf("NewReporter failed: %v", err)
+	}
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	reporter.Deliver(context.Background(), &models.NetworkReport{
+		Period:       PeriodWeekly,
+		PeriodStart:  start.Unix(),
+		PeriodEnd:    start.AddDate(0, 0, 7).Unix(),
+		Network:      "mainnet",
+		TopCorridors: []*models.CorridorSummary{{SourceCountry: "US", DestinationCountry: "JP", Payments: 2, XRP: 6}},
+	})
+
+	select {
+	case report := <-received:
+		if report.Period != PeriodWeekly || len(report.TopCorridors) != 1 {
+			t.Fatalf("unexpected webhook payload %+v", report)
+		}
+	default:
+		t.Fatal("expected webhook to receive the report after another webhook failed")
+	}
+
+	for _, name := range []string{"network-report-weekly-2026-03-02.json", "network-report-latest.json", "network-report-latest.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("expected %s to be written: %v", name, err)
+		}
+	}
+	markdown, _ := os.ReadFile(filepath.Join(dir, "network-report-latest.md"))
+	if !strings.Contains(string(markdown), "# Weekly XRPL network report (mainnet)") || !strings.Contains(string(markdown), "| US | JP | 2 |") {
+		t.Fatalf("unexpected markdown:\n%s", markdown)
+	}
+}
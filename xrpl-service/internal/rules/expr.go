@@ -0,0 +1,398 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The rule language is a small boolean expression syntax:
+//
+//	tags.source_label != "" && tags.dest_label != ""
+//	transaction_type == "Payment" && amount_drops >= 1e9
+//	startsWith(transaction_result, "tec") || !validated
+//
+// Operands are string, number and boolean literals, fields (see fieldNames
+// and tags.<key>), and calls to contains, startsWith and endsWith. Operators
+// are ||, &&, !, ==, !=, <, <=, >, >= and parentheses.
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != '"'; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: b.String(), pos: i})
+			i = j + 1
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || strings.ContainsRune(".eE", rune(src[j])) ||
+				((src[j] == '+' || src[j] == '-') && (src[j-1] == 'e' || src[j-1] == 'E'))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[i:j], pos: i})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) || src[j] == '_' || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[i:j], pos: i})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!"} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// node is a compiled expression.
+type node interface {
+	eval(env fieldSource) (interface{}, error)
+}
+
+// fieldSource resolves field names during evaluation.
+type fieldSource func(name string) interface{}
+
+type literalNode struct{ value interface{} }
+
+type fieldNode struct{ name string }
+
+type notNode struct{ operand node }
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+type callNode struct {
+	name string
+	args []node
+}
+
+var functions = map[string]func(a, b string) bool{
+	"contains":   strings.Contains,
+	"startsWith": strings.HasPrefix,
+	"endsWith":   strings.HasSuffix,
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// compile parses src into an expression tree, rejecting unknown fields and
+// functions up front.
+func compile(src string) (node, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos)
+	}
+	return expr, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) acceptOp(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.acceptOp("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.acceptOp("!"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString:
+		return &literalNode{value: tok.text}, nil
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at %d", tok.text, tok.pos)
+		}
+		return &literalNode{value: value}, nil
+	case tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ) at %d", closing.pos)
+		}
+		return expr, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		}
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(tok)
+		}
+		if !isKnownField(tok.text) {
+			return nil, fmt.Errorf("unknown field %q at %d", tok.text, tok.pos)
+		}
+		return &fieldNode{name: tok.text}, nil
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos)
+	}
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	if _, ok := functions[name.text]; !ok {
+		return nil, fmt.Errorf("unknown function %q at %d", name.text, name.pos)
+	}
+	p.next() // (
+	var args []node
+	for p.peek().kind != tokenRParen {
+		if len(args) > 0 {
+			if comma := p.next(); comma.kind != tokenComma {
+				return nil, fmt.Errorf("expected , at %d", comma.pos)
+			}
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next() // )
+	if len(args) != 2 {
+		return nil, fmt.Errorf("%s expects 2 arguments, got %d", name.text, len(args))
+	}
+	return &callNode{name: name.text, args: args}, nil
+}
+
+func (n *literalNode) eval(fieldSource) (interface{}, error) { return n.value, nil }
+
+func (n *fieldNode) eval(env fieldSource) (interface{}, error) { return env(n.name), nil }
+
+func (n *notNode) eval(env fieldSource) (interface{}, error) {
+	value, err := evalBool(n.operand, env)
+	if err != nil {
+		return nil, err
+	}
+	return !value, nil
+}
+
+func (n *binaryNode) eval(env fieldSource) (interface{}, error) {
+	switch n.op {
+	case "&&", "||":
+		left, err := evalBool(n.left, env)
+		if err != nil {
+			return nil, err
+		}
+		if (n.op == "&&" && !left) || (n.op == "||" && left) {
+			return left, nil
+		}
+		return evalBool(n.right, env)
+	}
+
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return left == right, nil
+	case "!=":
+		return left != right, nil
+	}
+
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return compareOrdered(n.op, l, r), nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return compareOrdered(n.op, l, r), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot compare %T %s %T", left, n.op, right)
+}
+
+func compareOrdered[T float64 | string](op string, left, right T) bool {
+	switch op {
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	default:
+		return left >= right
+	}
+}
+
+func (n *callNode) eval(env fieldSource) (interface{}, error) {
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s expects string arguments, got %T", n.name, value)
+		}
+		args[i] = s
+	}
+	return functions[n.name](args[0], args[1]), nil
+}
+
+func evalBool(n node, env fieldSource) (bool, error) {
+	value, err := n.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected boolean, got %T", value)
+	}
+	return b, nil
+}
@@ -0,0 +1,187 @@
+// Package rules evaluates operator-defined enrichment rules against
+// transactions. Rules are compiled once at startup and run as a
+// transaction.Processor.
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// fieldNames are the transaction fields available to rule expressions, in
+// addition to tags.<key>.
+var fieldNames = map[string]struct{}{
+	"hash":                {},
+	"account":             {},
+	"destination":         {},
+	"transaction_type":    {},
+	"transaction_result":  {},
+	"amount":              {},
+	"amount_drops":        {},
+	"fee":                 {},
+	"ledger_index":        {},
+	"validated":           {},
+	"location_count":      {},
+	"source.country":      {},
+	"source.city":         {},
+	"destination.country": {},
+	"destination.city":    {},
+}
+
+func isKnownField(name string) bool {
+	if key, ok := strings.CutPrefix(name, "tags."); ok {
+		return key != ""
+	}
+	_, ok := fieldNames[name]
+	return ok
+}
+
+type compiledRule struct {
+	name string
+	when node
+	set  map[string]string
+}
+
+// Engine applies compiled rules in order. Later rules see tags set by
+// earlier ones.
+type Engine struct {
+	rules  []compiledRule
+	logger *logrus.Logger
+}
+
+// NewEngine compiles specs, failing on the first invalid rule.
+func NewEngine(specs []models.EnrichmentRule, logger *logrus.Logger) (*Engine, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	engine := &Engine{logger: logger}
+	seen := make(map[string]struct{}, len(specs))
+	for i, spec := range specs {
+		name := strings.TrimSpace(spec.Name)
+		if name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i)
+		}
+		if _, exists := seen[name]; exists {
+			return nil, fmt.Errorf("duplicate rule name %q", name)
+		}
+		seen[name] = struct{}{}
+		if len(spec.Set) == 0 {
+			return nil, fmt.Errorf("rule %q sets no tags", name)
+		}
+		when, err := compile(spec.When)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", name, err)
+		}
+		engine.rules = append(engine.rules, compiledRule{name: name, when: when, set: spec.Set})
+	}
+	return engine, nil
+}
+
+// Len returns the number of compiled rules.
+func (e *Engine) Len() int {
+	return len(e.rules)
+}
+
+// Name identifies the engine as a transaction processor.
+func (e *Engine) Name() string {
+	return "rules"
+}
+
+// Process evaluates every rule against tx and sets the tags of those that
+// match. A rule that fails to evaluate is counted and skipped.
+func (e *Engine) Process(ctx context.Context, tx *models.Transaction) error {
+	env := transactionFields(tx)
+	for _, rule := range e.rules {
+		matched, err := evalBool(rule.when, env)
+		if err != nil {
+			metrics.EnrichmentRuleEvaluationsTotal.WithLabelValues(rule.name, "error").Inc()
+			e.logger.WithError(err).WithFields(logrus.Fields{
+				"rule": rule.name,
+				"hash": tx.Hash,
+			}).Debug("Enrichment rule failed")
+			continue
+		}
+		if !matched {
+			metrics.EnrichmentRuleEvaluationsTotal.WithLabelValues(rule.name, "no_match").Inc()
+			continue
+		}
+		metrics.EnrichmentRuleEvaluationsTotal.WithLabelValues(rule.name, "match").Inc()
+		if tx.Tags == nil {
+			tx.Tags = make(map[string]string, len(rule.set))
+		}
+		for key, value := range rule.set {
+			tx.Tags[key] = value
+		}
+	}
+	return nil
+}
+
+// transactionFields exposes tx to expressions. Tags are read live so rules
+// can build on each other.
+func transactionFields(tx *models.Transaction) fieldSource {
+	source, destination, _ := tx.RoleInfo()
+	return func(name string) interface{} {
+		if key, ok := strings.CutPrefix(name, "tags."); ok {
+			return tx.Tags[key]
+		}
+		switch name {
+		case "hash":
+			return tx.Hash
+		case "account":
+			return tx.Account
+		case "destination":
+			return tx.Destination
+		case "transaction_type":
+			return tx.TransactionType
+		case "transaction_result":
+			return tx.TransactionResult
+		case "amount":
+			return tx.Amount
+		case "amount_drops":
+			return parseDrops(tx.Amount)
+		case "fee":
+			return parseDrops(tx.Fee)
+		case "ledger_index":
+			return float64(tx.LedgerIndex)
+		case "validated":
+			return tx.Validated
+		case "location_count":
+			return float64(len(tx.Locations))
+		case "source.country":
+			return locationField(source, true)
+		case "source.city":
+			return locationField(source, false)
+		case "destination.country":
+			return locationField(destination, true)
+		case "destination.city":
+			return locationField(destination, false)
+		}
+		return nil
+	}
+}
+
+// parseDrops returns an XRP amount in drops, or 0 for issued currency
+// amounts.
+func parseDrops(amount string) float64 {
+	drops, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0
+	}
+	return drops
+}
+
+func locationField(location *models.GeoLocation, country bool) string {
+	if location == nil {
+		return ""
+	}
+	if country {
+		return location.CountryCode
+	}
+	return location.City
+}
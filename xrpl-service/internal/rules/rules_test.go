@@ -0,0 +1,108 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestCompileAndEvaluate(t *testing.T) {
+	tx := &models.Transaction{
+		Hash:              "ABC",
+		TransactionType:   "Payment",
+		TransactionResult: "tecPATH_DRY",
+		Amount:            "2500000000",
+		Validated:         true,
+		Tags:              map[string]string{"source_label": "Bitstamp"},
+		Locations: []*models.GeoLocation{
+			{CountryCode: "US", City: "New York", Role: models.LocationRoleSource},
+		},
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`transaction_type == "Payment"`, true},
+		{`amount_drops >= 1e9 && amount_drops < 5e9`, true},
+		{`tags.source_label != "" && tags.dest_label != ""`, false},
+		{`tags.source_label != "" || tags.dest_label != ""`, true},
+		{`startsWith(transaction_result, "tec") && !(source.country == "GB")`, true},
+		{`contains(source.city, "York") && location_count == 1`, true},
+		{`validated == false`, false},
+		{`destination.country == ""`, true},
+	}
+	env := transactionFields(tx)
+	for _, tt := range tests {
+		expr, err := compile(tt.expr)
+		if err != nil {
+			t.Fatalf("compile(%q) failed: %v", tt.expr, err)
+		}
+		got, err := evalBool(expr, env)
+		if err != nil {
+			t.Fatalf("eval(%q) failed: %v", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Errorf("eval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`labels.source != ""`,
+		`account == "r`,
+		`(amount_drops > 1`,
+		`lower(account) == "r"`,
+		`contains(account)`,
+		`account == "a" "b"`,
+	} {
+		if _, err := compile(expr); err == nil {
+			t.Errorf("compile(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestEngineSetsTagsInOrder(t *testing.T) {
+	engine, err := NewEngine([]models.EnrichmentRule{
+		{Name: "exchange_flow", When: `tags.source_label != "" && tags.dest_label != ""`, Set: map[string]string{"category": "exchange_flow"}},
+		{Name: "flagged", When: `tags.category == "exchange_flow" && amount_drops >= 1e9`, Set: map[string]string{"flag": "large_exchange_flow"}},
+		{Name: "broken", When: `amount > 5`, Set: map[string]string{"never": "set"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewEngine failed: %v", err)
+	}
+
+	tx := &models.Transaction{
+		Amount: "1000000000",
+		Tags:   map[string]string{"source_label": "Bitstamp", "dest_label": "Kraken"},
+	}
+	if err := engine.Process(context.Background(), tx); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if tx.Tags["category"] != "exchange_flow" || tx.Tags["flag"] != "large_exchange_flow" {
+		t.Fatalf("expected chained rule tags, got %+v", tx.Tags)
+	}
+	if _, ok := tx.Tags["never"]; ok {
+		t.Fatal("expected rule with a type error to be skipped")
+	}
+
+	untagged := &models.Transaction{Amount: "1"}
+	engine.Process(context.Background(), untagged)
+	if untagged.Tags != nil {
+		t.Fatalf("expected no tags when no rule matches, got %+v", untagged.Tags)
+	}
+}
+
+func TestNewEngineValidatesSpecs(t *testing.T) {
+	for _, specs := range [][]models.EnrichmentRule{
+		{{Name: "", When: "validated", Set: map[string]string{"a": "b"}}},
+		{{Name: "a", When: "validated"}},
+		{{Name: "a", When: "validated", Set: map[string]string{"a": "b"}}, {Name: "a", When: "validated", Set: map[string]string{"a": "b"}}},
+	} {
+		if _, err := NewEngine(specs, nil); err == nil {
+			t.Errorf("NewEngine(%+v) succeeded, want error", specs)
+		}
+	}
+}
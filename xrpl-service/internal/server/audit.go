@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// MetadataAuditSource keeps an audit trail of validator metadata changes. It
+// is implemented by validator.Fetcher; replicas do not keep one.
+type MetadataAuditSource interface {
+	MetadataAudit(ctx context.Context, filter models.MetadataAuditFilter) []*models.MetadataAuditRecord
+}
+
+// handleAdminMetadataAudit returns the newest validator metadata changes,
+// optionally filtered, e.g. ?address=nHB...&field=location&source=coverage_lock.
+func (s *Server) handleAdminMetadataAudit(c *gin.Context) {
+	source, ok := s.validatorFetcher.(MetadataAuditSource)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "metadata audit is not available"})
+		return
+	}
+	filter := models.MetadataAuditFilter{
+		Address: c.Query("address"),
+		Field:   c.Query("field"),
+		Source:  c.Query("source"),
+	}
+	if raw := c.Query("since"); raw != "" {
+		since, err := parseCompareTime(raw)
+		if err != nil || since.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be YYYY-MM-DD, RFC 3339 or unix seconds"})
+			return
+		}
+		filter.Since = since.Unix()
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		filter.Limit = limit
+	}
+	records := source.MetadataAudit(c.Request.Context(), filter)
+	c.JSON(http.StatusOK, gin.H{"records": records, "count": len(records)})
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminMetadataAuditWithoutAuditSupport(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/metadata-audit", srv.handleAdminMetadataAudit)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/metadata-audit", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
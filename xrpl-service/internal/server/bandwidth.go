@@ -0,0 +1,171 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Bandwidth budget actions applied to a client that exceeds its budget.
+const (
+	// BandwidthActionThrottle drops messages until the budget refills.
+	BandwidthActionThrottle = "throttle"
+	// BandwidthActionSummary downgrades transactions to summaries and drops
+	// events until the budget refills.
+	BandwidthActionSummary = "summary"
+)
+
+// anonymousAPIKey labels clients that connect without an API key.
+const anonymousAPIKey = "anonymous"
+
+// clientBandwidth tracks bytes and messages written to one WebSocket client.
+type clientBandwidth struct {
+	bytesSent    atomic.Uint64
+	messagesSent atomic.Uint64
+	throttled    atomic.Uint64
+	downgraded   atomic.Uint64
+}
+
+// bandwidthLimiter is a token bucket measured in bytes. It is only used from
+// the client's writePump, so it needs no locking.
+type bandwidthLimiter struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newBandwidthLimiter(bytesPerSecond int) *bandwidthLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &bandwidthLimiter{rate: float64(bytesPerSecond), tokens: float64(bytesPerSecond)}
+}
+
+// allow reports whether n bytes fit in the budget and, if so, spends them.
+// The bucket holds one second of budget. A message larger than that is let
+// through when the bucket is full, and its excess is paid back before the
+// next message fits, so the average rate still holds.
+func (l *bandwidthLimiter) allow(n int, now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.rate {
+			l.tokens = l.rate
+		}
+	}
+	l.last = now
+	if float64(n) > l.tokens && l.tokens < l.rate {
+		return false
+	}
+	l.tokens -= float64(n)
+	return true
+}
+
+// summarizeTransaction keeps only the fields the globe needs to draw a
+// transaction.
+func summarizeTransaction(tx *models.Transaction) *models.TransactionSummary {
+	return &models.TransactionSummary{
+		Hash:            tx.Hash,
+		TransactionType: tx.TransactionType,
+		Amount:          tx.Amount,
+		DisplayWeight:   tx.DisplayWeight,
+		Locations:       tx.Locations,
+		Summary:         true,
+		Flagged:         tx.Flagged,
+	}
+}
+
+// resolveAPIKey maps the key presented by a WebSocket client to its name.
+// Browsers cannot set headers on WebSocket upgrades, so the api_key query
+// parameter is accepted as well. ok is false for an unknown key.
+func (s *Server) resolveAPIKey(c *gin.Context) (name string, ok bool) {
+	key := strings.TrimSpace(c.GetHeader("X-API-Key"))
+	if key == "" {
+		key = strings.TrimSpace(c.Query("api_key"))
+	}
+	if key == "" {
+		return anonymousAPIKey, true
+	}
+	name, ok = s.apiKeys[key]
+	return name, ok
+}
+
+// recordBytesSent adds n bytes written to client to the per-client and
+// per-key totals.
+func (s *Server) recordBytesSent(client *WSClient, n int) {
+	client.bandwidth.bytesSent.Add(uint64(n))
+	client.bandwidth.messagesSent.Add(1)
+	metrics.WebSocketBytesSentTotal.WithLabelValues(client.apiKey).Add(float64(n))
+
+	s.bandwidthMu.Lock()
+	s.apiKeyBytesSent[client.apiKey] += uint64(n)
+	s.bandwidthMu.Unlock()
+}
+
+// requireAdmin rejects requests without the configured bearer token.
+func (s *Server) requireAdmin(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	c.Next()
+}
+
+// handleAdminBandwidth reports bytes sent per connected client and per API key.
+func (s *Server) handleAdminBandwidth(c *gin.Context) {
+	s.wsMu.RLock()
+	clients := make([]*WSClient, 0, len(s.wsClients))
+	for client := range s.wsClients {
+		clients = append(clients, client)
+	}
+	s.wsMu.RUnlock()
+	sort.Slice(clients, func(i, j int) bool { return clients[i].id < clients[j].id })
+
+	clientStats := make([]gin.H, 0, len(clients))
+	for _, client := range clients {
+		stats := gin.H{
+			"id":                  client.id,
+			"origin":              client.origin,
+			"api_key":             client.apiKey,
+			"connected_at":        client.connectedAt.Unix(),
+			"bytes_sent":          client.bandwidth.bytesSent.Load(),
+			"messages_sent":       client.bandwidth.messagesSent.Load(),
+			"messages_throttled":  client.bandwidth.throttled.Load(),
+			"messages_downgraded": client.bandwidth.downgraded.Load(),
+		}
+		if client.protocol != nil {
+			stats["protocol"] = client.protocol.name
+		}
+		if client.conn != nil {
+			stats["remote_addr"] = client.conn.RemoteAddr().String()
+		}
+		clientStats = append(clientStats, stats)
+	}
+
+	s.bandwidthMu.Lock()
+	var total uint64
+	perKey := make(map[string]uint64, len(s.apiKeyBytesSent))
+	for name, sent := range s.apiKeyBytesSent {
+		perKey[name] = sent
+		total += sent
+	}
+	s.bandwidthMu.Unlock()
+
+	c.JSON(http.StatusOK, gin.H{
+		"clients":                 clientStats,
+		"api_keys":                perKey,
+		"total_bytes_sent":        total,
+		"budget_bytes_per_second": s.clientBandwidthLimit,
+		"exceeded_action":         s.bandwidthExceededAction,
+	})
+}
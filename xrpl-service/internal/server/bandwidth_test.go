@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestBandwidthLimiterRefills(t *testing.T) {
+	limiter := newBandwidthLimiter(100)
+	now := time.Unix(1000, 0)
+
+	if !limiter.allow(60, now) {
+		t.Fatal("expected 60 bytes to fit a 100 B/s budget")
+	}
+	if limiter.allow(60, now) {
+		t.Fatal("expected second 60 bytes in the same instant to exceed the budget")
+	}
+	if !limiter.allow(60, now.Add(250*time.Millisecond)) {
+		t.Fatal("expected 25 bytes to refill after 250ms")
+	}
+	if newBandwidthLimiter(0) != nil {
+		t.Fatal("expected no limiter without a budget")
+	}
+}
+
+func TestBandwidthLimiterPassesOversizedMessagesFromAFullBucket(t *testing.T) {
+	limiter := newBandwidthLimiter(100)
+	now := time.Unix(1000, 0)
+
+	if !limiter.allow(250, now) {
+		t.Fatal("expected a message over one second of budget to pass a full bucket")
+	}
+	// The 150 bytes over budget are paid back first.
+	if limiter.allow(1, now.Add(time.Second)) {
+		t.Fatal("expected the excess to be paid back before the next message")
+	}
+	if !limiter.allow(1, now.Add(1600*time.Millisecond)) {
+		t.Fatal("expected the budget back once the excess is paid")
+	}
+	if limiter.allow(250, now.Add(1600*time.Millisecond)) {
+		t.Fatal("expected an oversized message to wait for a full bucket")
+	}
+	if !limiter.allow(250, now.Add(5*time.Second)) {
+		t.Fatal("expected an oversized message to pass once the bucket refills")
+	}
+}
+
+func TestEncodeDowngradesTransactionsOverBudget(t *testing.T) {
+	srv := newTestServer()
+	srv.bandwidthExceededAction = BandwidthActionSummary
+	tx := &models.Transaction{
+		Hash:              "ABC",
+		Account:           "rSource",
+		Destination:       "rDestination",
+		TransactionType:   "Payment",
+		Amount:            "1000000",
+		TransactionResult: "tesSUCCESS",
+	}
+	full, _ := json.Marshal(tx)
+	summary, _ := json.Marshal(summarizeTransaction(tx))
+	client := &WSClient{server: srv, budget: newBandwidthLimiter(len(full) + len(summary))}
+
+	if data, ok := client.encode(tx); !ok || len(data) != len(full) {
+		t.Fatalf("expected full transaction within budget, got %s", data)
+	}
+	data, ok := client.encode(tx)
+	if !ok || len(data) != len(summary) {
+		t.Fatalf("expected summary over budget, got %s", data)
+	}
+	if _, ok := client.encode(&models.StreamEvent{Type: "server_status"}); ok {
+		t.Fatal("expected event to be dropped over budget")
+	}
+	if client.bandwidth.downgraded.Load() != 1 || client.bandwidth.throttled.Load() != 1 {
+		t.Fatalf("expected 1 downgraded and 1 throttled, got %d and %d", client.bandwidth.downgraded.Load(), client.bandwidth.throttled.Load())
+	}
+}
+
+func TestTransactionsWebSocketRejectsUnknownAPIKey(t *testing.T) {
+	srv := newTestServer()
+	srv.apiKeys = map[string]string{"k1": "partner"}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/transactions", srv.handleTransactionsWebSocket)
+	req := httptest.NewRequest(http.MethodGet, "/transactions?api_key=wrong", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for unknown API key, got %d", rec.Code)
+	}
+}
+
+func TestAdminBandwidthRequiresToken(t *testing.T) {
+	srv := newTestServer()
+	srv.adminToken = "secret"
+	srv.apiKeyBytesSent = map[string]uint64{"partner": 1200, anonymousAPIKey: 300}
+	srv.wsClients[&WSClient{id: 7, apiKey: "partner", server: srv}] = true
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/bandwidth", srv.requireAdmin, srv.handleAdminBandwidth)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/bandwidth", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/bandwidth", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with token, got %d", rec.Code)
+	}
+	var body struct {
+		Clients        []map[string]interface{} `json:"clients"`
+		APIKeys        map[string]uint64        `json:"api_keys"`
+		TotalBytesSent uint64                   `json:"total_bytes_sent"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.TotalBytesSent != 1500 || body.APIKeys["partner"] != 1200 || len(body.Clients) != 1 {
+		t.Fatalf("unexpected bandwidth report: %+v", body)
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// BenchmarkBroadcastFanout measures broadcastLoop fanout to in-memory clients
+// that drain their send channels as fast as possible.
+func BenchmarkBroadcastFanout(b *testing.B) {
+	for _, clientCount := range []int{1, 10, 100} {
+		for _, bufferSize := range []int{64, 512} {
+			b.Run(fmt.Sprintf("clients=%d/buffer=%d", clientCount, bufferSize), func(b *testing.B) {
+				logger := logrus.New()
+				logger.SetOutput(io.Discard)
+				srv := &Server{
+					logger:             logger,
+					wsClients:          make(map[*WSClient]bool),
+					broadcast:          make(chan interface{}, 2048),
+					stopBroadcast:      make(chan struct{}),
+					wsClientBufferSize: bufferSize,
+					clock:              clock.Real(),
+				}
+
+				var wg sync.WaitGroup
+				for i := 0; i < clientCount; i++ {
+					client := &WSClient{send: make(chan interface{}, bufferSize), server: srv}
+					srv.wsClients[client] = true
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for i := 0; i < b.N; i++ {
+							if _, ok := <-client.send; !ok {
+								return
+							}
+						}
+					}()
+				}
+				go srv.broadcastLoop()
+
+				tx := &models.Transaction{Hash: "BENCH", Amount: "25000000"}
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					srv.broadcast <- tx
+				}
+				wg.Wait()
+				b.StopTimer()
+
+				b.ReportMetric(float64(srv.websocketClientCount())/float64(clientCount), "clients-kept")
+
+				close(srv.stopBroadcast)
+			})
+		}
+	}
+}
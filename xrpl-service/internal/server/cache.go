@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/gin-gonic/gin"
+)
+
+// maxResponseCacheEntries bounds the cache so arbitrary query strings cannot
+// grow it without limit.
+const maxResponseCacheEntries = 256
+
+// responseCache is an in-memory cache of serialized GET responses, keyed by
+// path and query string.
+type responseCache struct {
+	clock   clock.Clock
+	mu      sync.RWMutex
+	entries map[string]*cachedResponse
+}
+
+type cachedResponse struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// cacheRecorder captures the response body while still writing it through.
+type cacheRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *cacheRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func newResponseCache(clk clock.Clock) *responseCache {
+	return &responseCache{clock: clk, entries: make(map[string]*cachedResponse)}
+}
+
+// middleware serves cached responses for route for up to ttl, keyed by
+// request path and query; route labels the metrics. Clients can bypass the
+// cache with "Cache-Control: no-cache"; every response carries an X-Cache
+// header of HIT, MISS or BYPASS. CORS headers depend on the request's
+// Origin, so they are not stored and the CORS middleware sets them for each
+// hit. A non-positive ttl disables caching.
+func (rc *responseCache) middleware(route string, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ttl <= 0 || c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		if strings.Contains(strings.ToLower(c.GetHeader("Cache-Control")), "no-cache") {
+			metrics.HTTPResponseCacheTotal.WithLabelValues(route, "bypass").Inc()
+			c.Header("X-Cache", "BYPASS")
+			c.Next()
+			return
+		}
+
+		key := c.Request.URL.Path + "?" + c.Request.URL.RawQuery
+		if entry, ok := rc.get(key); ok {
+			metrics.HTTPResponseCacheTotal.WithLabelValues(route, "hit").Inc()
+			for name, values := range entry.header {
+				for _, value := range values {
+					c.Writer.Header().Add(name, value)
+				}
+			}
+			c.Header("X-Cache", "HIT")
+			refreshAge(c.Writer.Header(), rc.clock.Now())
+			if etag := entry.header.Get("ETag"); etag != "" && c.GetHeader("If-None-Match") == etag {
+				c.AbortWithStatus(http.StatusNotModified)
+				return
+			}
+			c.Data(entry.status, entry.header.Get("Content-Type"), entry.body)
+			c.Abort()
+			return
+		}
+
+		metrics.HTTPResponseCacheTotal.WithLabelValues(route, "miss").Inc()
+		c.Header("X-Cache", "MISS")
+		recorder := &cacheRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if recorder.Status() != http.StatusOK || recorder.body.Len() == 0 {
+			return
+		}
+		header := recorder.Header().Clone()
+		header.Del("X-Cache")
+		for name := range header {
+			if isCORSHeader(name) {
+				header.Del(name)
+			}
+		}
+		rc.set(key, &cachedResponse{
+			status:  recorder.Status(),
+			header:  header,
+			body:    recorder.body.Bytes(),
+			expires: rc.clock.Now().Add(ttl),
+		})
+	}
+}
+
+// isCORSHeader reports whether the CORS middleware sets header per request,
+// so cached responses must not replay it.
+func isCORSHeader(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	return name == "Vary" || strings.HasPrefix(name, "Access-Control-")
+}
+
+func (rc *responseCache) get(key string) (*cachedResponse, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	entry, ok := rc.entries[key]
+	if !ok || rc.clock.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (rc *responseCache) set(key string, entry *cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if _, exists := rc.entries[key]; !exists && len(rc.entries) >= maxResponseCacheEntries {
+		now := rc.clock.Now()
+		for existing, cached := range rc.entries {
+			if now.After(cached.expires) {
+				delete(rc.entries, existing)
+			}
+		}
+		if len(rc.entries) >= maxResponseCacheEntries {
+			return
+		}
+	}
+	rc.entries[key] = entry
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/gin-gonic/gin"
+)
+
+func newCacheTestRouter(clk clock.Clock, ttl time.Duration, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	cache := newResponseCache(clk)
+	router.GET("/validators", cache.middleware("/validators", ttl), func(c *gin.Context) {
+		*calls++
+		c.Header("ETag", `W/"v1"`)
+		c.JSON(http.StatusOK, gin.H{"calls": *calls})
+	})
+	return router
+}
+
+func serveCacheRequest(router *gin.Engine, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/validators", nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestResponseCacheServesHitsWithinTTL(t *testing.T) {
+	calls := 0
+	router := newCacheTestRouter(clock.Real(), time.Minute, &calls)
+
+	first := serveCacheRequest(router, nil)
+	second := serveCacheRequest(router, nil)
+
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+	if first.Header().Get("X-Cache") != "MISS" || second.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("unexpected X-Cache headers: %q then %q", first.Header().Get("X-Cache"), second.Header().Get("X-Cache"))
+	}
+	if second.Body.String() != first.Body.String() || second.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Fatalf("cached response differs: %q vs %q", second.Body.String(), first.Body.String())
+	}
+	if second.Header().Get("ETag") != `W/"v1"` {
+		t.Fatalf("expected cached ETag, got %q", second.Header().Get("ETag"))
+	}
+
+	notModified := serveCacheRequest(router, http.Header{"If-None-Match": []string{`W/"v1"`}})
+	if notModified.Code != http.StatusNotModified || calls != 1 {
+		t.Fatalf("expected 304 from cache, got %d after %d calls", notModified.Code, calls)
+	}
+}
+
+func TestResponseCacheBypassAndDisabled(t *testing.T) {
+	calls := 0
+	router := newCacheTestRouter(clock.Real(), time.Minute, &calls)
+	serveCacheRequest(router, nil)
+
+	bypass := serveCacheRequest(router, http.Header{"Cache-Control": []string{"no-cache"}})
+	if bypass.Header().Get("X-Cache") != "BYPASS" || calls != 2 {
+		t.Fatalf("expected bypass to reach handler, X-Cache=%q calls=%d", bypass.Header().Get("X-Cache"), calls)
+	}
+
+	disabledCalls := 0
+	disabled := newCacheTestRouter(clock.Real(), 0, &disabledCalls)
+	serveCacheRequest(disabled, nil)
+	rec := serveCacheRequest(disabled, nil)
+	if disabledCalls != 2 || rec.Header().Get("X-Cache") != "" {
+		t.Fatalf("expected disabled cache to pass through, calls=%d X-Cache=%q", disabledCalls, rec.Header().Get("X-Cache"))
+	}
+}
+
+func TestResponseCacheExpiresAfterTTL(t *testing.T) {
+	calls := 0
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	router := newCacheTestRouter(fake, time.Minute, &calls)
+	serveCacheRequest(router, nil)
+
+	fake.Advance(59 * time.Second)
+	if rec := serveCacheRequest(router, nil); rec.Header().Get("X-Cache") != "HIT" || calls != 1 {
+		t.Fatalf("expected a hit within the TTL, X-Cache=%q calls=%d", rec.Header().Get("X-Cache"), calls)
+	}
+
+	fake.Advance(2 * time.Second)
+	if rec := serveCacheRequest(router, nil); rec.Header().Get("X-Cache") != "MISS" || calls != 2 {
+		t.Fatalf("expected a miss after the TTL, X-Cache=%q calls=%d", rec.Header().Get("X-Cache"), calls)
+	}
+}
+
+func TestCachedResponsesDoNotReplayCORSHeaders(t *testing.T) {
+	srv := newTestServer()
+	srv.corsAllowedOrigins = []string{"https://app.example"}
+	srv.responseCache = newResponseCache(clock.Real())
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(srv.cors)
+	router.GET("/validators", srv.responseCache.middleware("/validators", time.Minute), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"count": 0})
+	})
+
+	get := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/validators", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("https://app.example"); rec.Header().Get("X-Cache") != "MISS" || rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example" {
+		t.Fatalf("expected an allowed cache miss, got %v", rec.Header())
+	}
+	rec := get("https://other.example")
+	if rec.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("expected a cache hit, got %q", rec.Header().Get("X-Cache"))
+	}
+	if got := rec.Header().Values("Access-Control-Allow-Origin"); len(got) != 0 {
+		t.Fatalf("expected no CORS grant for another origin from the cache, got %v", got)
+	}
+	if got := rec.Header().Values("Vary"); len(got) != 1 || got[0] != "Origin" {
+		t.Fatalf("expected a single Vary: Origin, got %v", got)
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/census"
+	"github.com/gin-gonic/gin"
+)
+
+// handleCensusList lists the daily validator census artifacts, newest
+// first, with the key that signs them.
+func (s *Server) handleCensusList(c *gin.Context) {
+	if s.censusDir == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "validator census is not configured"})
+		return
+	}
+	entries, err := census.List(s.censusDir)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to list validator census artifacts")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list validator census"})
+		return
+	}
+	for _, entry := range entries {
+		entry.URL = "/census/" + entry.Date
+	}
+	body := gin.H{"census": entries, "count": len(entries)}
+	if s.exportKey != nil {
+		body["public_key"] = census.PublicKeyHex(s.exportKey)
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, body)
+}
+
+// handleCensus serves the census artifact of one UTC day, YYYY-MM-DD, as
+// written. Artifacts never change once written.
+func (s *Server) handleCensus(c *gin.Context) {
+	if s.censusDir == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "validator census is not configured"})
+		return
+	}
+	date := c.Param("date")
+	if !census.ValidDate(date) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+		return
+	}
+	path := census.Path(s.censusDir, date)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no census for " + date})
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("date", date).Warn("Failed to read validator census")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read validator census"})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=86400, immutable")
+	c.Header("Content-Disposition", `attachment; filename="`+filepath.Base(path)+`"`)
+	c.Data(http.StatusOK, "application/json", data)
+}
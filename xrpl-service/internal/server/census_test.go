@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/census"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCensusEndpoints(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/census", srv.handleCensusList)
+	router.GET("/census/:date", srv.handleCensus)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+	if rec := get("/census"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a census directory, got %d", rec.Code)
+	}
+
+	srv.censusDir = t.TempDir()
+	artifact := []byte(`{"public_key":"ED00","signature":"00","payload":{"date":"2024-03-01"}}`)
+	for _, date := range []string{"2024-03-01", "2024-03-02"} {
+		if err := os.WriteFile(census.Path(srv.censusDir, date), artifact, 0o644); err != nil {
+			t.Fatalf("write census: %v", err)
+		}
+	}
+
+	rec := get("/census")
+	var body struct {
+		Census []struct {
+			Date string `json:"date"`
+			URL  string `json:"url"`
+		} `json:"census"`
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode list: %v", err)
+	}
+	if body.Count != 2 || body.Census[0].Date != "2024-03-02" || body.Census[0].URL != "/census/2024-03-02" {
+		t.Fatalf("unexpected census list %s", rec.Body.String())
+	}
+
+	rec = get("/census/2024-03-01")
+	if rec.Code != http.StatusOK || rec.Body.String() != string(artifact) {
+		t.Fatalf("expected the artifact served as written, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := get("/census/2024-03-09"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing day, got %d", rec.Code)
+	}
+	if rec := get("/census/2024-3-1"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid date, got %d", rec.Code)
+	}
+}
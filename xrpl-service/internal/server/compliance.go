@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleAdminWatchlist reports whether the compliance watchlist is flagging
+// transactions and how many it has flagged.
+func (s *Server) handleAdminWatchlist(c *gin.Context) {
+	if s.watchlist == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "watchlist is not configured"})
+		return
+	}
+	c.JSON(http.StatusOK, s.watchlist.Status())
+}
+
+// handleAdminSetWatchlist turns watchlist flagging on or off, e.g.
+// {"enabled":false}.
+func (s *Server) handleAdminSetWatchlist(c *gin.Context) {
+	if s.watchlist == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "watchlist is not configured"})
+		return
+	}
+	var body struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Enabled == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be {\"enabled\": true|false}"})
+		return
+	}
+	s.watchlist.SetEnabled(*body.Enabled)
+	c.JSON(http.StatusOK, s.watchlist.Status())
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/compliance"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminWatchlistToggle(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/watchlist", srv.handleAdminWatchlist)
+	router.PUT("/admin/watchlist", srv.handleAdminSetWatchlist)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/watchlist", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a watchlist, got %d", rec.Code)
+	}
+
+	watchlist, err := compliance.NewWatchlist(compliance.File{Countries: []string{"KP"}}, nil)
+	if err != nil {
+		t.Fatalf("NewWatchlist: %v", err)
+	}
+	srv.watchlist = watchlist
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/watchlist", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without enabled, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/watchlist", strings.NewReader(`{"enabled":false}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var status models.WatchlistStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if status.Enabled || status.Countries != 1 || watchlist.Enabled() {
+		t.Fatalf("expected the watchlist to be disabled, got %+v", status)
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	corsAllowMethods = "POST, OPTIONS, GET, PUT, DELETE"
+	corsAllowHeaders = "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-API-Key, If-None-Match"
+	// corsExposeHeaders lets cross-origin pollers read validators,
+	// revalidate with If-None-Match and check how fresh the data is.
+	corsExposeHeaders = "ETag, X-Cache, X-Data-Last-Updated, X-Data-Source, Age"
+)
+
+// cors adds CORS headers for allowed origins and answers preflights on
+// every path, including the WebSocket routes, which browsers upgrade with a
+// plain GET but other clients preflight. Responses vary by Origin, and
+// preflights by the requested method and headers too, so caches in front
+// of the service never hand one origin's answer to another.
+func (s *Server) cors(c *gin.Context) {
+	header := c.Writer.Header()
+	preflight := c.Request.Method == http.MethodOptions
+	if preflight {
+		header.Add("Vary", "Origin, Access-Control-Request-Method, Access-Control-Request-Headers")
+	} else {
+		header.Add("Vary", "Origin")
+	}
+
+	if origin := c.GetHeader("Origin"); origin != "" && s.originAllowed(c.Request.URL.Path, origin) {
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Set("Access-Control-Allow-Credentials", "true")
+		if preflight {
+			header.Set("Access-Control-Allow-Methods", corsAllowMethods)
+			header.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			if s.corsMaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(s.corsMaxAge/time.Second)))
+			}
+		} else {
+			header.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+		}
+	}
+
+	if preflight {
+		c.AbortWithStatus(http.StatusNoContent)
+		return
+	}
+	c.Next()
+}
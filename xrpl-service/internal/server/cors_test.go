@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	srv := newTestServer()
+	srv.corsAllowedOrigins = []string{"https://app.example"}
+	srv.corsMaxAge = 10 * time.Minute
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(srv.cors)
+	router.GET("/transactions", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/transactions", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := preflight("https://app.example")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example" {
+		t.Fatalf("expected an allowed preflight, got %d %v", rec.Code, rec.Header())
+	}
+	if rec.Header().Get("Access-Control-Max-Age") != "600" {
+		t.Fatalf("expected Access-Control-Max-Age 600, got %q", rec.Header().Get("Access-Control-Max-Age"))
+	}
+	if rec.Header().Get("Vary") != "Origin, Access-Control-Request-Method, Access-Control-Request-Headers" {
+		t.Fatalf("unexpected Vary %q", rec.Header().Get("Vary"))
+	}
+
+	rec = preflight("https://other.example")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "" || rec.Header().Get("Access-Control-Max-Age") != "" {
+		t.Fatalf("expected a preflight without grants for an unknown origin, got %d %v", rec.Code, rec.Header())
+	}
+}
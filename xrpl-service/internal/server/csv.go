@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// wantsCSV reports whether the request asked for ?format=csv. Other formats
+// than json and csv are rejected with 400 and false is returned.
+func wantsCSV(c *gin.Context) (csvRequested bool, ok bool) {
+	switch strings.ToLower(c.DefaultQuery("format", "json")) {
+	case "json":
+		return false, true
+	case "csv":
+		return true, true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return false, false
+	}
+}
+
+// writeCSV streams a CSV attachment named filename. rows is called with a
+// function that writes one record and should stop on its first error.
+func (s *Server) writeCSV(c *gin.Context, filename string, header []string, rows func(write func([]string) error) error) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	err := w.Write(header)
+	if err == nil {
+		err = rows(w.Write)
+	}
+	if err == nil {
+		w.Flush()
+		err = w.Error()
+	}
+	if err != nil {
+		// Headers are already sent, so the error can only be logged.
+		s.logger.WithError(err).WithField("path", c.Request.URL.Path).Warn("Failed to write CSV response")
+	}
+}
+
+// csvText neutralizes text that spreadsheets would evaluate as a formula.
+// Validator names and domains come from third-party registries.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+var validatorCSVHeader = []string{
+	"address", "public_key", "domain", "name", "network",
+	"latitude", "longitude", "country_code", "city", "last_updated", "is_active",
+}
+
+func validatorCSVRecord(v *models.Validator) []string {
+	return []string{
+		v.Address,
+		v.PublicKey,
+		csvText(v.Domain),
+		csvText(v.Name),
+		v.Network,
+		strconv.FormatFloat(v.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(v.Longitude, 'f', -1, 64),
+		csvText(v.CountryCode),
+		csvText(v.City),
+		strconv.FormatInt(v.LastUpdated, 10),
+		strconv.FormatBool(v.IsActive),
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetValidatorsCSV(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{
+		{Address: "nA1", Domain: "a.example", Name: "=HYPERLINK(\"x\")", Latitude: 40.7128, Longitude: -74.006, City: "New York, NY", IsActive: true},
+	}}
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/validators", srv.handleGetValidators)
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators?format=csv", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected 200 text/csv, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="validators.csv"` {
+		t.Fatalf("unexpected Content-Disposition %q", got)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 2 || records[0][0] != "address" {
+		t.Fatalf("expected header and one row, got %v", records)
+	}
+	row := records[1]
+	if row[0] != "nA1" || row[3] != "'=HYPERLINK(\"x\")" || row[6] != "-74.006" || row[8] != "New York, NY" || row[10] != "true" {
+		t.Fatalf("unexpected row %v", row)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators?format=xml", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown format, got %d", rec.Code)
+	}
+}
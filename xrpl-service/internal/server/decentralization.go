@@ -0,0 +1,72 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/decentralization"
+	"github.com/gin-gonic/gin"
+)
+
+// handleDecentralizationCompare returns how validators moved between
+// countries, AS numbers and operators between two dates, e.g.
+// ?from=2024-01-01&to=now.
+func (s *Server) handleDecentralizationCompare(c *gin.Context) {
+	if s.decentralization == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "decentralization history is not configured"})
+		return
+	}
+	if c.Query("from") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from is required"})
+		return
+	}
+	from, err := parseCompareTime(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseCompareTime(c.DefaultQuery("to", "now"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !to.IsZero() && to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+
+	comparison, err := s.decentralization.Compare(from, to)
+	if errors.Is(err, decentralization.ErrNoSnapshots) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no decentralization snapshots recorded yet"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, comparison)
+}
+
+// parseCompareTime accepts "now", which it returns as the zero time, a
+// date such as 2024-01-01 (UTC midnight), an RFC 3339 timestamp or unix
+// seconds.
+func parseCompareTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "now") {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected now, YYYY-MM-DD, RFC 3339 or unix seconds", value)
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/decentralization"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestDecentralizationCompare(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/decentralization/compare", srv.handleDecentralizationCompare)
+	get := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/decentralization/compare"+query, nil))
+		return rec
+	}
+
+	if rec := get("?from=2024-01-01"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without history, got %d", rec.Code)
+	}
+
+	path := filepath.Join(t.TempDir(), "decentralization-history.json")
+	stored := `{"version":1,"snapshots":[{"timestamp":1704067200,"validators":1,"countries":{"US":1},"asns":{},"operators":{"a.example":1}}]}`
+	if err := os.WriteFile(path, []byte(stored), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source := &staticValidators{validators: []*models.Validator{
+		{Address: "nA1", CountryCode: "US", Operator: "a.example"},
+		{Address: "nA2", CountryCode: "DE", Operator: "b.example"},
+	}}
+	srv.decentralization = decentralization.NewHistory(source, nil, path, 24*time.Hour, 365*24*time.Hour, nil, nil)
+
+	for _, query := range []string{"", "?from=yesterday", "?from=2024-02-01&to=2024-01-01"} {
+		if rec := get(query); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %q, got %d", query, rec.Code)
+		}
+	}
+
+	rec := get("?from=2024-01-01&to=now")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var comparison models.DecentralizationComparison
+	if err := json.Unmarshal(rec.Body.Bytes(), &comparison); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if comparison.From.Timestamp != 1704067200 || !comparison.To.Live || comparison.To.Validators != 2 {
+		t.Fatalf("unexpected points %+v %+v", comparison.From, comparison.To)
+	}
+	if len(comparison.Countries) != 2 || comparison.Countries[0].Key != "DE" || comparison.Countries[0].Change != 1 {
+		t.Fatalf("unexpected country deltas %+v", comparison.Countries)
+	}
+}
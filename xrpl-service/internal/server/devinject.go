@@ -0,0 +1,191 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/geolocation"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Kinds accepted by POST /dev/inject.
+const (
+	devInjectTransaction   = "transaction"
+	devInjectValidatorMove = "validator_move"
+	devInjectUNLChange     = "unl_change"
+	devInjectAlert         = "alert"
+)
+
+// devInjection is the body of POST /dev/inject. Only the fields of its Kind
+// are read.
+type devInjection struct {
+	Kind string `json:"kind"`
+
+	// transaction: any streamed transaction fields. Hash, type, timestamp
+	// and validated are filled in when omitted.
+	Transaction *models.Transaction `json:"transaction"`
+
+	// validator_move
+	Address     string   `json:"address"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	CountryCode string   `json:"country_code"`
+	City        string   `json:"city"`
+
+	// unl_change: validators joining with their fields, and addresses
+	// leaving.
+	Added   []*models.Validator `json:"added"`
+	Removed []string            `json:"removed"`
+
+	// alert: "server_alert", "watchdog_alert", "slo_alert" or "anomaly",
+	// with the event's data.
+	AlertType string          `json:"alert_type"`
+	Alert     json.RawMessage `json:"alert"`
+}
+
+// handleDevInject pushes a synthetic transaction, validator move, UNL change
+// or alert to WebSocket clients through the same fanout as live data, so
+// frontends can exercise rare states on demand. Privacy mode, views and
+// origin policies apply as usual; ingestion-side consumers such as
+// statistics and reports do not see injected data.
+func (s *Server) handleDevInject(c *gin.Context) {
+	var body devInjection
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid body: %v", err)})
+		return
+	}
+
+	var (
+		events int
+		err    error
+	)
+	switch body.Kind {
+	case devInjectTransaction:
+		events, err = s.injectTransaction(body.Transaction)
+	case devInjectValidatorMove:
+		events, err = s.injectValidatorMove(&body)
+	case devInjectUNLChange:
+		events, err = s.injectUNLChange(body.Added, body.Removed)
+	case devInjectAlert:
+		events, err = s.injectAlert(body.AlertType, body.Alert)
+	default:
+		err = fmt.Errorf("kind must be %s, %s, %s or %s", devInjectTransaction, devInjectValidatorMove, devInjectUNLChange, devInjectAlert)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	metrics.DevInjectionsTotal.WithLabelValues(body.Kind).Inc()
+	s.logger.WithField("kind", body.Kind).Info("Injected synthetic data")
+	c.JSON(http.StatusAccepted, gin.H{"kind": body.Kind, "events": events})
+}
+
+func (s *Server) injectTransaction(tx *models.Transaction) (int, error) {
+	if tx == nil {
+		tx = &models.Transaction{Account: "rDevSourceAccount", Destination: "rDevDestinationAccount", Amount: "25000000", Fee: "12"}
+	}
+	injected := *tx
+	if injected.Hash == "" {
+		injected.Hash = fmt.Sprintf("DE%062X", s.devInjections.Add(1))
+	}
+	if injected.TransactionType == "" {
+		injected.TransactionType = "Payment"
+	}
+	if injected.TransactionResult == "" {
+		injected.TransactionResult = "tesSUCCESS"
+	}
+	if injected.Timestamp == 0 {
+		injected.Timestamp = s.clock.Now().Unix()
+	}
+	injected.Validated = true
+	s.onTransaction(&injected)
+	return 1, nil
+}
+
+func (s *Server) injectValidatorMove(body *devInjection) (int, error) {
+	if strings.TrimSpace(body.Address) == "" || body.Latitude == nil || body.Longitude == nil {
+		return 0, fmt.Errorf("validator_move needs address, latitude and longitude")
+	}
+	if !geolocation.ValidCoordinates(*body.Latitude, *body.Longitude) {
+		return 0, fmt.Errorf("latitude and longitude are out of range")
+	}
+	fields := map[string]interface{}{"latitude": *body.Latitude, "longitude": *body.Longitude}
+	if body.CountryCode != "" {
+		fields["country_code"] = body.CountryCode
+	}
+	if body.City != "" {
+		fields["city"] = body.City
+	}
+	s.onValidatorUpdate(&models.ValidatorUpdate{Upserts: []*models.ValidatorDelta{{Address: body.Address, Fields: fields}}})
+	return 1, nil
+}
+
+func (s *Server) injectUNLChange(added []*models.Validator, removed []string) (int, error) {
+	update := &models.ValidatorUpdate{}
+	for _, v := range added {
+		if v == nil || strings.TrimSpace(v.Address) == "" {
+			return 0, fmt.Errorf("added validators need an address")
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return 0, err
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(encoded, &fields); err != nil {
+			return 0, err
+		}
+		delete(fields, "address")
+		update.Upserts = append(update.Upserts, &models.ValidatorDelta{Address: v.Address, Fields: fields})
+	}
+	for _, address := range removed {
+		if strings.TrimSpace(address) == "" {
+			return 0, fmt.Errorf("removed addresses cannot be empty")
+		}
+		update.Removals = append(update.Removals, &models.ValidatorDelta{Address: address})
+	}
+	if len(update.Upserts)+len(update.Removals) == 0 {
+		return 0, fmt.Errorf("unl_change needs added or removed validators")
+	}
+	s.onValidatorUpdate(update)
+	return len(update.Upserts) + len(update.Removals), nil
+}
+
+func (s *Server) injectAlert(alertType string, raw json.RawMessage) (int, error) {
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	switch alertType {
+	case "server_alert":
+		alert := &models.ServerAlert{Condition: "amendment_blocked", Active: true, ServerState: "connected", Message: "Synthetic server alert"}
+		if err := json.Unmarshal(raw, alert); err != nil {
+			return 0, fmt.Errorf("invalid alert: %w", err)
+		}
+		s.broadcastEvent(&models.StreamEvent{Type: "server_alert", Timestamp: s.clock.Now().Unix(), Data: alert})
+	case "watchdog_alert":
+		alert := &models.WatchdogAlert{Check: "transactions_stalled", Active: true, Message: "Synthetic watchdog alert"}
+		if err := json.Unmarshal(raw, alert); err != nil {
+			return 0, fmt.Errorf("invalid alert: %w", err)
+		}
+		s.onWatchdogAlert(alert)
+	case "slo_alert":
+		alert := &models.SLOAlert{Check: "validator_coverage_low", Active: true, Message: "Synthetic SLO alert"}
+		if err := json.Unmarshal(raw, alert); err != nil {
+			return 0, fmt.Errorf("invalid alert: %w", err)
+		}
+		s.onSLOAlert(alert)
+	case "anomaly":
+		anomaly := &models.Anomaly{Metric: "tx_rate", Active: true, Direction: "spike", DetectedAt: s.clock.Now().Unix(), Message: "Synthetic anomaly"}
+		if err := json.Unmarshal(raw, anomaly); err != nil {
+			return 0, fmt.Errorf("invalid alert: %w", err)
+		}
+		s.onAnomaly(anomaly)
+	default:
+		return 0, fmt.Errorf("alert_type must be server_alert, watchdog_alert, slo_alert or anomaly")
+	}
+	return 1, nil
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func devInjectRouter(srv *Server) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/dev/inject", srv.handleDevInject)
+	return router
+}
+
+func postInject(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/dev/inject", strings.NewReader(body)))
+	return rec
+}
+
+func TestDevInjectTransaction(t *testing.T) {
+	srv := newTestServer()
+	router := devInjectRouter(srv)
+
+	rec := postInject(router, `{"kind":"transaction","transaction":{"account":"rA","destination":"rB","amount":"5000000000"}}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	tx, ok := (<-srv.broadcast).(*models.Transaction)
+	if !ok || tx.Account != "rA" || tx.Amount != "5000000000" || len(tx.Hash) != 64 || tx.TransactionType != "Payment" || !tx.Validated {
+		t.Fatalf("unexpected injected transaction %+v", tx)
+	}
+	if recent := srv.recent.snapshot(1); len(recent) != 1 || recent[0].Hash != tx.Hash {
+		t.Fatalf("expected the transaction in the recent buffer, got %+v", recent)
+	}
+}
+
+func TestDevInjectEvents(t *testing.T) {
+	srv := newTestServer()
+	router := devInjectRouter(srv)
+
+	cases := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"validator move", `{"kind":"validator_move","address":"nA1","latitude":35.68,"longitude":139.69,"country_code":"JP"}`, []string{"validator_upsert"}},
+		{"unl change", `{"kind":"unl_change","added":[{"address":"nNew","latitude":1.35,"longitude":103.82}],"removed":["nOld"]}`, []string{"validator_upsert", "validator_remove"}},
+		{"anomaly", `{"kind":"alert","alert_type":"anomaly","alert":{"metric":"validator_count","direction":"drop"}}`, []string{"anomaly"}},
+		{"watchdog alert with defaults", `{"kind":"alert","alert_type":"watchdog_alert"}`, []string{"watchdog_alert"}},
+	}
+	for _, tc := range cases {
+		rec := postInject(router, tc.body)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("%s: expected 202, got %d: %s", tc.name, rec.Code, rec.Body.String())
+		}
+		for _, want := range tc.want {
+			event := (<-srv.broadcast).(*models.StreamEvent)
+			if event.Type != want {
+				t.Fatalf("%s: expected %s, got %s", tc.name, want, event.Type)
+			}
+			if delta, ok := event.Data.(*models.ValidatorDelta); ok && delta.Address == "nNew" && delta.Fields["latitude"] != 1.35 {
+				t.Fatalf("%s: expected the added validator's fields, got %+v", tc.name, delta)
+			}
+		}
+	}
+
+	for _, body := range []string{
+		`{"kind":"meteor"}`,
+		`{"kind":"validator_move","address":"nA1","latitude":95,"longitude":0}`,
+		`{"kind":"unl_change"}`,
+		`{"kind":"alert","alert_type":"pager"}`,
+	} {
+		if rec := postInject(router, body); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/census"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/timeutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Formats of /validators/export.
+const (
+	exportFormatVL   = "vl"   // validator list site payload, as served by vl.ripple.com
+	exportFormatJSON = "json" // plain JSON payload with a detached signature
+)
+
+// exportValidity is how long an export is valid after the fetch it was
+// built from.
+const exportValidity = 24 * time.Hour
+
+// exportListBlob is the signed blob of the vl format. Consumers that only
+// read validator lists ignore the enrichment fields of its entries.
+type exportListBlob struct {
+	Sequence   int64              `json:"sequence"`
+	Expiration int64              `json:"expiration"` // seconds since the XRPL epoch
+	Validators []exportListMember `json:"validators"`
+}
+
+type exportListMember struct {
+	ValidationPublicKey string  `json:"validation_public_key"`
+	Manifest            string  `json:"manifest,omitempty"`
+	Domain              string  `json:"domain,omitempty"`
+	Name                string  `json:"name,omitempty"`
+	Latitude            float64 `json:"latitude"`
+	Longitude           float64 `json:"longitude"`
+	CountryCode         string  `json:"country_code"`
+	City                string  `json:"city"`
+	Operator            string  `json:"operator,omitempty"`
+}
+
+// exportPayload is the signed payload of the json format.
+type exportPayload struct {
+	Version     int                 `json:"version"`
+	Network     string              `json:"network"`
+	Sequence    int64               `json:"sequence"`
+	GeneratedAt int64               `json:"generated_at"` // Unix seconds
+	Expiration  int64               `json:"expiration"`   // Unix seconds
+	Count       int                 `json:"count"`
+	Validators  []*models.Validator `json:"validators"`
+}
+
+// handleValidatorsExport publishes the merged, enriched validator set
+// signed with the export key: by default as a validator list site payload
+// whose blob lists each validator's key and manifest with its enrichment,
+// or with ?format=json as a plain payload and a detached signature over its
+// exact bytes. Sequence and expiration follow the last fetch, so the export
+// only changes when the set does.
+func (s *Server) handleValidatorsExport(c *gin.Context) {
+	if s.exportKey == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "validator export is not configured"})
+		return
+	}
+	format := strings.ToLower(c.DefaultQuery("format", exportFormatVL))
+	if format != exportFormatVL && format != exportFormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be vl or json"})
+		return
+	}
+
+	etag := s.validatorsETag("validators-export-"+format, s.validatorSnapshotHash())
+	c.Header("Cache-Control", "public, max-age=30, stale-while-revalidate=300")
+	c.Header("ETag", etag)
+	if inm := c.GetHeader("If-None-Match"); inm != "" && inm == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	validators := exportableValidators(s.publicValidators())
+	lastUpdate := s.validatorFetcher.GetLastUpdate()
+	sequence := lastUpdate.Unix()
+	expiration := lastUpdate.Add(exportValidity).Unix()
+	publicKey := census.PublicKeyHex(s.exportKey)
+
+	if format == exportFormatJSON {
+		payload, err := json.Marshal(&exportPayload{
+			Version:     1,
+			Network:     exportNetwork(validators),
+			Sequence:    sequence,
+			GeneratedAt: lastUpdate.Unix(),
+			Expiration:  expiration,
+			Count:       len(validators),
+			Validators:  validators,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"public_key": publicKey,
+			"signature":  strings.ToUpper(hex.EncodeToString(ed25519.Sign(s.exportKey, payload))),
+			"payload":    json.RawMessage(payload),
+		})
+		return
+	}
+
+	blob := exportListBlob{
+		Sequence:   sequence,
+		Expiration: expiration - timeutil.RippleEpoch,
+		Validators: make([]exportListMember, 0, len(validators)),
+	}
+	for _, v := range validators {
+		blob.Validators = append(blob.Validators, exportListMember{
+			ValidationPublicKey: v.PublicKey,
+			Manifest:            v.Manifest,
+			Domain:              v.Domain,
+			Name:                v.Name,
+			Latitude:            v.Latitude,
+			Longitude:           v.Longitude,
+			CountryCode:         v.CountryCode,
+			City:                v.City,
+			Operator:            v.Operator,
+		})
+	}
+	raw, err := json.Marshal(&blob)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"public_key": publicKey,
+		"blob":       base64.StdEncoding.EncodeToString(raw),
+		"signature":  strings.ToUpper(hex.EncodeToString(ed25519.Sign(s.exportKey, raw))),
+		"version":    1,
+	})
+}
+
+// exportableValidators returns the validators with a public key, sorted by
+// it so that exports of the same set are identical.
+func exportableValidators(validators []*models.Validator) []*models.Validator {
+	out := make([]*models.Validator, 0, len(validators))
+	for _, v := range validators {
+		if v != nil && v.PublicKey != "" {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PublicKey < out[j].PublicKey })
+	return out
+}
+
+// exportNetwork is the network the validators were fetched from.
+func exportNetwork(validators []*models.Validator) string {
+	for _, v := range validators {
+		if v.Network != "" {
+			return v.Network
+		}
+	}
+	return ""
+}
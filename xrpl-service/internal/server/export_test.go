@@ -0,0 +1,124 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidatorsExport(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newTestServer()
+	srv.exportKey = privateKey
+	srv.validatorFetcher = &hashedValidators{
+		staticValidators: staticValidators{validators: []*models.Validator{
+			{Address: "nB", PublicKey: "nHB", Manifest: "JAAAAA==", Domain: "b.example", CountryCode: "DE", Network: "mainnet"},
+			{Address: "nA", PublicKey: "nHA", Domain: "a.example", City: "Paris", Network: "mainnet"},
+			{Address: "nNoKey", Domain: "nokey.example"},
+		}},
+		hash: "0123456789abcdef0123456789abcdef",
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validators/export", srv.handleValidatorsExport)
+	router.GET("/validators/:address/domain-history", srv.handleValidatorDomainHistory)
+
+	get := func(query, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/validators/export"+query, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	signedBy := func(keyHex, sigHex string, message []byte) bool {
+		if keyHex != "ED"+strings.ToUpper(hex.EncodeToString(publicKey)) {
+			return false
+		}
+		sig, err := hex.DecodeString(sigHex)
+		return err == nil && ed25519.Verify(publicKey, message, sig)
+	}
+
+	rec := get("", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var list struct {
+		PublicKey string `json:"public_key"`
+		Blob      string `json:"blob"`
+		Signature string `json:"signature"`
+		Version   int    `json:"version"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(list.Blob)
+	if err != nil {
+		t.Fatalf("blob is not base64: %v", err)
+	}
+	if list.Version != 1 || !signedBy(list.PublicKey, list.Signature, raw) {
+		t.Fatalf("expected a version 1 list signed by the export key, got %+v", list)
+	}
+	var blob exportListBlob
+	if err := json.Unmarshal(raw, &blob); err != nil {
+		t.Fatalf("invalid blob: %v", err)
+	}
+	if len(blob.Validators) != 2 || blob.Validators[0].ValidationPublicKey != "nHA" || blob.Validators[1].Manifest != "JAAAAA==" {
+		t.Fatalf("expected the keyed validators sorted by key with manifests, got %+v", blob.Validators)
+	}
+	if blob.Validators[0].City != "Paris" || blob.Validators[1].CountryCode != "DE" {
+		t.Fatalf("expected enrichment in the blob, got %+v", blob.Validators)
+	}
+
+	etag := rec.Header().Get("ETag")
+	if rec := get("", etag); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for the same snapshot, got %d", rec.Code)
+	}
+
+	rec = get("?format=json", "")
+	var signed struct {
+		PublicKey string          `json:"public_key"`
+		Signature string          `json:"signature"`
+		Payload   json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &signed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !signedBy(signed.PublicKey, signed.Signature, signed.Payload) {
+		t.Fatalf("expected the payload signed by the export key, got %s", rec.Body.String())
+	}
+	var payload struct {
+		Network    string             `json:"network"`
+		Count      int                `json:"count"`
+		Validators []models.Validator `json:"validators"`
+	}
+	if err := json.Unmarshal(signed.Payload, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.Network != "mainnet" || payload.Count != 2 || len(payload.Validators) != 2 {
+		t.Fatalf("unexpected payload %s", signed.Payload)
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Fatal("expected formats to have distinct ETags")
+	}
+
+	if rec := get("?format=csv", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown format, got %d", rec.Code)
+	}
+	srv.exportKey = nil
+	if rec := get("", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a signing key, got %d", rec.Code)
+	}
+}
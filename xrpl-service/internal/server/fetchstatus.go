@@ -0,0 +1,37 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// FetchProgressSource reports validator fetch cycle progress. It is
+// implemented by validator.Fetcher; replica mode has no fetch cycles.
+type FetchProgressSource interface {
+	FetchStatus() *models.FetchStatus
+	AddCycleCallback(callback validator.CycleCallback)
+}
+
+// handleAdminFetchStatus returns the stage of the running validator fetch
+// cycle with the stages completed so far, and the last completed cycle with
+// per-stage durations and errors.
+func (s *Server) handleAdminFetchStatus(c *gin.Context) {
+	progress, ok := s.validatorFetcher.(FetchProgressSource)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "fetch progress is not available"})
+		return
+	}
+	c.JSON(http.StatusOK, progress.FetchStatus())
+}
+
+// onFetchCycle pushes a fetch_cycle event when a validator fetch cycle
+// completes.
+func (s *Server) onFetchCycle(cycle *models.FetchCycle) {
+	if cycle == nil {
+		return
+	}
+	s.broadcastEvent(&models.StreamEvent{Type: "fetch_cycle", Timestamp: s.clock.Now().Unix(), Data: cycle})
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminFetchStatus(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/fetch-status", srv.handleAdminFetchStatus)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/fetch-status", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a fetcher, got %d", rec.Code)
+	}
+
+	cachePath := filepath.Join(t.TempDir(), "validator-metadata-cache.json")
+	srv.validatorFetcher = validator.NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/fetch-status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var status models.FetchStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if status.Running || status.Cycles != 0 || status.Last != nil {
+		t.Fatalf("expected an idle fetcher, got %+v", status)
+	}
+}
+
+func TestFetchCycleEvent(t *testing.T) {
+	srv := newTestServer()
+	srv.onFetchCycle(&models.FetchCycle{StartedAt: 1, FinishedAt: 2, DurationMS: 1, Validators: 3})
+
+	event := (<-srv.broadcast).(*models.StreamEvent)
+	cycle, ok := event.Data.(*models.FetchCycle)
+	if event.Type != "fetch_cycle" || !ok || cycle.Validators != 3 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldMask selects the top-level JSON fields of the items in a response,
+// from ?fields=address,latitude,longitude. Requested fields are always
+// written, even when omitempty would drop them, so clients can rely on the
+// shape they asked for. A nil mask keeps every field.
+type fieldMask struct {
+	// key is the selected names in declaration order, joined by commas. It
+	// is the same for any spelling of the same selection.
+	key    string
+	fields []maskedField
+}
+
+type maskedField struct {
+	prefix []byte // `"name":`
+	index  int
+}
+
+// jsonFieldIndexes caches the JSON field names of struct types, keyed by
+// reflect.Type.
+var jsonFieldIndexes sync.Map
+
+type jsonFieldIndex struct {
+	names []string
+	index map[string]int
+}
+
+// jsonFieldsOf returns the JSON names of a struct type's exported fields in
+// declaration order.
+func jsonFieldsOf(t reflect.Type) *jsonFieldIndex {
+	if cached, ok := jsonFieldIndexes.Load(t); ok {
+		return cached.(*jsonFieldIndex)
+	}
+	fields := &jsonFieldIndex{index: make(map[string]int)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields.names = append(fields.names, name)
+		fields.index[name] = i
+	}
+	jsonFieldIndexes.Store(t, fields)
+	return fields
+}
+
+// parseFieldMask parses a comma-separated fields parameter for items of
+// type T. An empty parameter returns a nil mask; unknown names are an error
+// listing the valid ones.
+func parseFieldMask[T any](raw string) (*fieldMask, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	available := jsonFieldsOf(reflect.TypeFor[T]())
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := available.index[name]; !ok {
+			return nil, fmt.Errorf("unknown field %q; fields must be from %s", name, strings.Join(available.names, ", "))
+		}
+		selected[name] = true
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	mask := &fieldMask{}
+	keys := make([]string, 0, len(selected))
+	for _, name := range available.names {
+		if !selected[name] {
+			continue
+		}
+		keys = append(keys, name)
+		mask.fields = append(mask.fields, maskedField{prefix: []byte(`"` + name + `":`), index: available.index[name]})
+	}
+	mask.key = strings.Join(keys, ",")
+	return mask, nil
+}
+
+// marshalMasked encodes items as a JSON array, keeping only the fields in
+// mask. With a nil mask it is json.Marshal.
+func marshalMasked[T any](items []*T, mask *fieldMask) ([]byte, error) {
+	if mask == nil {
+		return json.Marshal(items)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if item == nil {
+			buf.WriteString("null")
+			continue
+		}
+		value := reflect.ValueOf(item).Elem()
+		buf.WriteByte('{')
+		for j, field := range mask.fields {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			encoded, err := json.Marshal(value.Field(field.index).Interface())
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(field.prefix)
+			buf.Write(encoded)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseFieldMask(t *testing.T) {
+	mask, err := parseFieldMask[models.Validator](" longitude,address,,latitude,address ")
+	if err != nil {
+		t.Fatalf("parseFieldMask failed: %v", err)
+	}
+	if mask.key != "address,latitude,longitude" {
+		t.Fatalf("expected declaration order, got %q", mask.key)
+	}
+	if mask, err := parseFieldMask[models.Validator](""); mask != nil || err != nil {
+		t.Fatalf("expected no mask, got %+v (%v)", mask, err)
+	}
+	if _, err := parseFieldMask[models.Validator]("address,secret"); err == nil {
+		t.Fatal("expected an unknown field to fail")
+	}
+	if _, err := parseFieldMask[models.Transaction]("geo_candidates"); err == nil {
+		t.Fatal("expected a field hidden from JSON to fail")
+	}
+}
+
+func TestValidatorsFieldMask(t *testing.T) {
+	var validators []*models.Validator
+	for i := 0; i < 50; i++ {
+		validators = append(validators, &models.Validator{
+			Address:     fmt.Sprintf("nHValidator%02d", i),
+			PublicKey:   "ED0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF",
+			Domain:      fmt.Sprintf("validator%02d.example.com", i),
+			Name:        "Example validator",
+			Network:     "mainnet",
+			Latitude:    48.85,
+			Longitude:   2.35,
+			CountryCode: "FR",
+			City:        "Paris",
+			Description: "Operated by Example since 2019.",
+			Operator:    "example.com",
+			IsActive:    true,
+		})
+	}
+	source := &hashedValidators{
+		staticValidators: staticValidators{validators: validators},
+		hash:             "0123456789abcdef0123456789abcdef",
+	}
+	srv := newTestServer()
+	srv.validatorFetcher = source
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validators", srv.handleGetValidators)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	full := get("/validators")
+	masked := get("/validators?fields=address,latitude,longitude,description")
+	if masked.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", masked.Code, masked.Body.String())
+	}
+	if masked.Header().Get("ETag") == full.Header().Get("ETag") {
+		t.Fatal("expected masked responses to have their own ETag")
+	}
+	if masked.Body.Len()*2 > full.Body.Len() {
+		t.Fatalf("expected the masked response to be much smaller, got %d of %d bytes", masked.Body.Len(), full.Body.Len())
+	}
+
+	var payload struct {
+		Validators []map[string]interface{} `json:"validators"`
+		Count      int                      `json:"count"`
+	}
+	if err := json.Unmarshal(masked.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if payload.Count != 50 || len(payload.Validators) != 50 {
+		t.Fatalf("expected 50 validators, got %d", payload.Count)
+	}
+	if first := payload.Validators[0]; len(first) != 4 || first["address"] != "nHValidator00" || first["latitude"] != 48.85 {
+		t.Fatalf("unexpected masked validator %v", first)
+	}
+
+	if rec := get("/validators?fields=address,nope"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown field, got %d", rec.Code)
+	}
+	if rec := get("/validators?format=csv&fields=address"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for fields with CSV, got %d", rec.Code)
+	}
+}
+
+func TestRecentTransactionsFieldMask(t *testing.T) {
+	srv := newTestServer()
+	srv.recent.add(&models.Transaction{Hash: "A", Account: "rA", Amount: "100", Validated: true})
+	srv.recent.add(&models.Transaction{Hash: "B", Account: "rB", Amount: "200", Validated: true})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/transactions/recent", srv.handleRecentTransactions)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/transactions/recent?fields=hash,locations&limit=1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	want := `{"count":1,"transactions":[{"hash":"B","locations":null}]}`
+	if rec.Body.String() != want {
+		t.Fatalf("expected %s, got %s", want, rec.Body.String())
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/transaction"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// FilterPreviewSource replays recent stream traffic through candidate
+// filters. It is implemented by transaction.Listener; replicas only receive
+// traffic that already passed the filters.
+type FilterPreviewSource interface {
+	PreviewFilter(candidate models.TxFilter, window time.Duration) (*models.FilterPreview, error)
+}
+
+// handleAdminFilterPreview projects the event rates of a candidate filter
+// over the last minutes of traffic, next to those of the live filter, e.g.
+// {"min_payment_drops":5000000,"allowed_results":["tes*","tec*"],"minutes":10}.
+// Omitted fields keep their live values; the live stream is not changed.
+func (s *Server) handleAdminFilterPreview(c *gin.Context) {
+	previews, ok := s.transactionListener.(FilterPreviewSource)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "filter preview is not available"})
+		return
+	}
+	var body struct {
+		models.TxFilter
+		Minutes int `json:"minutes"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Minutes < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be {\"min_payment_drops\": 5000000, \"transaction_types\": [\"Payment\"], \"allowed_results\": [\"tesSUCCESS\"], \"minutes\": 10}"})
+		return
+	}
+
+	preview, err := previews.PreviewFilter(body.TxFilter, time.Duration(body.Minutes)*time.Minute)
+	switch {
+	case errors.Is(err, transaction.ErrFilterPreviewDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": "filter preview is not available"})
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, preview)
+	}
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/transaction"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminFilterPreview(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/filters/preview", srv.handleAdminFilterPreview)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/filters/preview", strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := post(`{}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a listener, got %d", rec.Code)
+	}
+	srv.transactionListener = transaction.NewListener(nil, 1000000, nil, nil)
+	if rec := post(`{}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 with previews disabled, got %d", rec.Code)
+	}
+
+	srv.transactionListener = transaction.NewListener(nil, 1000000, nil, nil, transaction.ListenerOptions{FilterPreviewWindow: time.Minute})
+	for _, body := range []string{`nope`, `{"minutes":-1}`, `{"allowed_results":["success"]}`} {
+		if rec := post(body); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", body, rec.Code)
+		}
+	}
+
+	rec := post(`{"min_payment_drops":5000000,"transaction_types":["Payment","OfferCreate"],"minutes":10}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var preview models.FilterPreview
+	if err := json.Unmarshal(rec.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if preview.Candidate.MinPaymentDrops != 5000000 || len(preview.Candidate.TransactionTypes) != 2 || preview.Live.MinPaymentDrops != 1000000 {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+}
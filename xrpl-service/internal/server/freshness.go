@@ -0,0 +1,89 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Freshness headers. X-Data-Last-Updated is an HTTP date; Age is the
+// seconds since then.
+const (
+	headerDataLastUpdated = "X-Data-Last-Updated"
+	headerDataSource      = "X-Data-Source"
+)
+
+// Data sources reported in X-Data-Source and data_source.
+const (
+	dataSourceFetch       = "fetch"       // fetched by this instance
+	dataSourceProvisional = "provisional" // restored from the metadata cache at startup
+	dataSourcePoller      = "poller"      // the status poller's latest poll
+	dataSourceStaleCache  = "stale_cache" // the last status fetched before upstream failed
+	dataSourceStream      = "stream"      // aggregated from the transaction stream
+)
+
+// DataSourceReporter names where a validator source gets its data. It is
+// implemented by replica.Validators; other sources fetch their own.
+type DataSourceReporter interface {
+	DataSource() string
+}
+
+// setFreshness sets the freshness headers for data last updated at
+// updatedAt from source and returns the matching JSON fields. A zero
+// updatedAt, before any data arrived, only reports the source. Public mode
+// never reports the source, which describes the deployment.
+func (s *Server) setFreshness(c *gin.Context, updatedAt time.Time, source string) models.Freshness {
+	if s.publicMode {
+		source = ""
+	}
+	freshness := models.Freshness{DataSource: source}
+	if source != "" {
+		c.Header(headerDataSource, source)
+	}
+	if updatedAt.IsZero() {
+		return freshness
+	}
+	c.Header(headerDataLastUpdated, updatedAt.UTC().Format(http.TimeFormat))
+	setAge(c.Writer.Header(), s.clock.Now(), updatedAt)
+	freshness.DataLastUpdated = updatedAt.Unix()
+	return freshness
+}
+
+// setAge sets Age to the whole seconds from updatedAt to now.
+func setAge(header http.Header, now, updatedAt time.Time) {
+	header.Set("Age", strconv.FormatInt(int64(max(now.Sub(updatedAt), 0)/time.Second), 10))
+}
+
+// refreshAge recomputes the Age of a cached response from its
+// X-Data-Last-Updated header.
+func refreshAge(header http.Header, now time.Time) {
+	if updatedAt, err := http.ParseTime(header.Get(headerDataLastUpdated)); err == nil {
+		setAge(header, now, updatedAt)
+	}
+}
+
+// validatorFreshness returns when the served validator set was last updated
+// and where it came from.
+func (s *Server) validatorFreshness() (time.Time, string) {
+	if since := s.validatorProvisionalSince(); !since.IsZero() {
+		return since, dataSourceProvisional
+	}
+	source := dataSourceFetch
+	if reporter, ok := s.validatorFetcher.(DataSourceReporter); ok {
+		source = reporter.DataSource()
+	}
+	return s.validatorFetcher.GetLastUpdate(), source
+}
+
+// setFreshnessFields adds freshness to a JSON response.
+func setFreshnessFields(response gin.H, freshness models.Freshness) {
+	if freshness.DataLastUpdated != 0 {
+		response["data_last_updated"] = freshness.DataLastUpdated
+	}
+	if freshness.DataSource != "" {
+		response["data_source"] = freshness.DataSource
+	}
+}
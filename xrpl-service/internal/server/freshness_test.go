@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/stats"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+type updatedValidators struct {
+	staticValidators
+	updatedAt time.Time
+}
+
+func (u *updatedValidators) GetLastUpdate() time.Time { return u.updatedAt }
+
+func TestValidatorsFreshnessHeadersAgeInCache(t *testing.T) {
+	fetchedAt := time.Unix(1_700_000_000, 0)
+	fake := clock.NewFake(fetchedAt.Add(10 * time.Second))
+	srv := newTestServer()
+	srv.clock = fake
+	srv.validatorFetcher = &updatedValidators{
+		staticValidators: staticValidators{validators: []*models.Validator{{Address: "nA1"}}},
+		updatedAt:        fetchedAt,
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validators", newResponseCache(fake).middleware("/validators", time.Minute), srv.handleGetValidators)
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators", nil))
+		return rec
+	}
+
+	first := get()
+	if got := first.Header().Get(headerDataLastUpdated); got != "Tue, 14 Nov 2023 22:13:20 GMT" {
+		t.Fatalf("unexpected %s %q", headerDataLastUpdated, got)
+	}
+	if first.Header().Get(headerDataSource) != dataSourceFetch || first.Header().Get("Age") != "10" {
+		t.Fatalf("unexpected freshness headers %v", first.Header())
+	}
+	var body struct {
+		DataLastUpdated int64  `json:"data_last_updated"`
+		DataSource      string `json:"data_source"`
+	}
+	if err := json.Unmarshal(first.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.DataLastUpdated != fetchedAt.Unix() || body.DataSource != dataSourceFetch {
+		t.Fatalf("unexpected freshness fields %+v", body)
+	}
+
+	// A cached response reports its age at the time it is served.
+	fake.Advance(30 * time.Second)
+	cached := get()
+	if cached.Header().Get("X-Cache") != "HIT" || cached.Header().Get("Age") != "40" {
+		t.Fatalf("expected a cache hit aged 40s, got %s %q", cached.Header().Get("X-Cache"), cached.Header().Get("Age"))
+	}
+}
+
+func TestStatsFreshnessBeforeAndAfterData(t *testing.T) {
+	srv := newTestServer()
+	srv.burn = stats.NewBurnTracker()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/burn", srv.handleBurn)
+
+	get := func() (*httptest.ResponseRecorder, models.BurnTotals) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/burn", nil))
+		var totals models.BurnTotals
+		if err := json.Unmarshal(rec.Body.Bytes(), &totals); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return rec, totals
+	}
+
+	rec, totals := get()
+	if rec.Header().Get(headerDataLastUpdated) != "" || rec.Header().Get("Age") != "" || totals.DataLastUpdated != 0 {
+		t.Fatalf("expected no update time before the first ledger, got %v %+v", rec.Header(), totals.Freshness)
+	}
+	if rec.Header().Get(headerDataSource) != dataSourceStream || totals.DataSource != dataSourceStream {
+		t.Fatalf("expected the stream source, got %v %+v", rec.Header(), totals.Freshness)
+	}
+
+	srv.burn.ObserveLedger(&models.LedgerFees{LedgerIndex: 100, Transactions: 1, FeeDrops: 12})
+	rec, totals = get()
+	if rec.Header().Get(headerDataLastUpdated) == "" || totals.DataLastUpdated == 0 {
+		t.Fatalf("expected an update time after a ledger, got %v %+v", rec.Header(), totals.Freshness)
+	}
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// geoJSONFeatureCollection is an RFC 7946 FeatureCollection of validator
+// points.
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Geometry   geoJSONPoint           `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"` // longitude, latitude
+}
+
+// validatorFeatureCollection converts validators with mapped coordinates to
+// a FeatureCollection. Validators at 0,0 have no known location and are
+// left out.
+func validatorFeatureCollection(validators []*models.Validator) geoJSONFeatureCollection {
+	collection := geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONFeature, 0, len(validators)),
+	}
+	for _, v := range validators {
+		if v == nil || (v.Latitude == 0 && v.Longitude == 0) {
+			continue
+		}
+		feature := geoJSONFeature{
+			Type: "Feature",
+			ID:   v.Address,
+			Geometry: geoJSONPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{v.Longitude, v.Latitude},
+			},
+			Properties: map[string]interface{}{
+				"address":        v.Address,
+				"public_key":     v.PublicKey,
+				"domain":         v.Domain,
+				"name":           v.Name,
+				"network":        v.Network,
+				"country_code":   v.CountryCode,
+				"city":           v.City,
+				"approximate":    v.Approximate,
+				"stale_location": v.StaleLocation,
+				"last_updated":   v.LastUpdated,
+				"is_active":      v.IsActive,
+			},
+		}
+		if v.PeerReachable != nil {
+			feature.Properties["peer_reachable"] = *v.PeerReachable
+		}
+		collection.Features = append(collection.Features, feature)
+	}
+	return collection
+}
+
+// handleGetValidatorsGeoJSON returns mapped validators as a GeoJSON
+// FeatureCollection for GIS and web map tools.
+func (s *Server) handleGetValidatorsGeoJSON(c *gin.Context) {
+	preset, ok := s.requestValidatorPreset(c)
+	if !ok {
+		return
+	}
+	v := requestView(c)
+	hash := s.validatorSnapshotHash()
+	etag := s.validatorsETag(preset.kind(v.kind("validators-geojson")), hash)
+
+	c.Header("Cache-Control", "public, max-age=30, stale-while-revalidate=300")
+	c.Header("ETag", etag)
+	c.Header("Content-Disposition", `inline; filename="validators.geojson"`)
+	updatedAt, source := s.validatorFreshness()
+	s.setFreshness(c, updatedAt, source)
+
+	if inm := c.GetHeader("If-None-Match"); inm != "" && inm == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	body, _, err := s.validatorBody(v, preset, "geojson", hash, func(validators []*models.Validator) ([]byte, error) {
+		return json.Marshal(validatorFeatureCollection(validators))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/geo+json", body)
+}
+
+const (
+	defaultRecentLimit = 100
+	dropsPerXRP        = 1_000_000
+)
+
+type geoJSONLineString struct {
+	Type        string       `json:"type"`
+	Coordinates [][2]float64 `json:"coordinates"` // source then destination, each longitude, latitude
+}
+
+type geoJSONLineFeature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Geometry   geoJSONLineString      `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONLineCollection struct {
+	Type     string               `json:"type"`
+	Features []geoJSONLineFeature `json:"features"`
+}
+
+// amountTier buckets an XRP amount the way the globe colors flows.
+func amountTier(xrp float64) string {
+	switch {
+	case xrp >= 100_000:
+		return "whale"
+	case xrp >= 10_000:
+		return "large"
+	default:
+		return "standard"
+	}
+}
+
+// transactionFeatureCollection converts payments with both a source and a
+// destination location to LineString arcs.
+func transactionFeatureCollection(transactions []*models.Transaction) geoJSONLineCollection {
+	collection := geoJSONLineCollection{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONLineFeature, 0, len(transactions)),
+	}
+	for _, tx := range transactions {
+		if tx == nil || tx.TransactionType != "Payment" {
+			continue
+		}
+		source, destination, _ := tx.RoleInfo()
+		if source == nil || destination == nil {
+			continue
+		}
+		properties := map[string]interface{}{
+			"hash":                tx.Hash,
+			"ledger_index":        tx.LedgerIndex,
+			"account":             tx.Account,
+			"destination":         tx.Destination,
+			"amount_drops":        tx.Amount,
+			"timestamp":           tx.Timestamp,
+			"source_country":      source.CountryCode,
+			"source_city":         source.City,
+			"destination_country": destination.CountryCode,
+			"destination_city":    destination.City,
+		}
+		if drops, err := strconv.ParseInt(tx.Amount, 10, 64); err == nil {
+			xrp := float64(drops) / dropsPerXRP
+			properties["amount_xrp"] = xrp
+			properties["tier"] = amountTier(xrp)
+		}
+		if tx.DisplayWeight > 0 {
+			properties["display_weight"] = tx.DisplayWeight
+		}
+		if len(tx.Tags) > 0 {
+			properties["tags"] = tx.Tags
+		}
+		if tx.Flagged {
+			properties["flagged"] = true
+			properties["flag_reasons"] = tx.FlagReasons
+		}
+		collection.Features = append(collection.Features, geoJSONLineFeature{
+			Type: "Feature",
+			ID:   tx.Hash,
+			Geometry: geoJSONLineString{
+				Type: "LineString",
+				Coordinates: [][2]float64{
+					{source.Longitude, source.Latitude},
+					{destination.Longitude, destination.Latitude},
+				},
+			},
+			Properties: properties,
+		})
+	}
+	return collection
+}
+
+// handleRecentTransactionsGeoJSON returns recently broadcast payments as
+// LineString arcs, newest first. ?limit= caps how many recent transactions
+// are considered.
+func (s *Server) handleRecentTransactionsGeoJSON(c *gin.Context) {
+	limit, ok := recentLimit(c)
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(transactionFeatureCollection(s.recentFor(requestView(c), limit)))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.Data(http.StatusOK, "application/geo+json", body)
+}
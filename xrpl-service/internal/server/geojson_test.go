@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidatorsGeoJSON(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{
+		{Address: "nA1", Domain: "a.example", Latitude: 48.85, Longitude: 2.35, City: "Paris", IsActive: true},
+		{Address: "nA2", Domain: "unmapped.example"},
+	}}
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/validators.geojson", srv.handleGetValidatorsGeoJSON)
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators.geojson", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/geo+json" {
+		t.Fatalf("expected 200 application/geo+json, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Type     string `json:"type"`
+			ID       string `json:"id"`
+			Geometry struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &collection); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 1 {
+		t.Fatalf("expected a FeatureCollection with the mapped validator only, got %s", rec.Body.String())
+	}
+	feature := collection.Features[0]
+	if feature.Type != "Feature" || feature.ID != "nA1" || feature.Geometry.Type != "Point" {
+		t.Fatalf("unexpected feature %+v", feature)
+	}
+	if coords := feature.Geometry.Coordinates; len(coords) != 2 || coords[0] != 2.35 || coords[1] != 48.85 {
+		t.Fatalf("expected [longitude, latitude], got %v", coords)
+	}
+	if feature.Properties["domain"] != "a.example" || feature.Properties["city"] != "Paris" {
+		t.Fatalf("unexpected properties %v", feature.Properties)
+	}
+
+	etag := rec.Header().Get("ETag")
+	req := httptest.NewRequest(http.MethodGet, "/validators.geojson", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for matching ETag, got %d", rec.Code)
+	}
+}
+
+func TestRecentTransactionsGeoJSON(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/transactions/recent.geojson", srv.handleRecentTransactionsGeoJSON)
+
+	source := &models.GeoLocation{Latitude: 40.71, Longitude: -74.0, CountryCode: "US", Role: models.LocationRoleSource}
+	destination := &models.GeoLocation{Latitude: 35.68, Longitude: 139.69, CountryCode: "JP", Role: models.LocationRoleDestination}
+	srv.onTransaction(&models.Transaction{Hash: "A", TransactionType: "Payment", Amount: "250000000000", Locations: []*models.GeoLocation{source, destination}})
+	srv.onTransaction(&models.Transaction{Hash: "B", TransactionType: "Payment", Amount: "5000000", Locations: []*models.GeoLocation{source}})
+	srv.onTransaction(&models.Transaction{Hash: "C", TransactionType: "OfferCreate", Locations: []*models.GeoLocation{source, destination}})
+	srv.onTxGeoUpdate(&models.TxGeoUpdate{Hash: "B", Locations: []*models.GeoLocation{source, destination}})
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/transactions/recent.geojson", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/geo+json" {
+		t.Fatalf("expected 200 application/geo+json, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			ID       string `json:"id"`
+			Geometry struct {
+				Type        string       `json:"type"`
+				Coordinates [][2]float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &collection); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 2 {
+		t.Fatalf("expected two payment arcs, got %s", rec.Body.String())
+	}
+	newest, oldest := collection.Features[0], collection.Features[1]
+	if newest.ID != "B" || oldest.ID != "A" {
+		t.Fatalf("expected newest first, got %s then %s", newest.ID, oldest.ID)
+	}
+	if oldest.Geometry.Type != "LineString" || oldest.Geometry.Coordinates[0] != [2]float64{-74.0, 40.71} || oldest.Geometry.Coordinates[1] != [2]float64{139.69, 35.68} {
+		t.Fatalf("unexpected geometry %+v", oldest.Geometry)
+	}
+	if oldest.Properties["tier"] != "whale" || oldest.Properties["amount_xrp"] != 250000.0 || newest.Properties["tier"] != "standard" {
+		t.Fatalf("unexpected tier properties %v / %v", oldest.Properties, newest.Properties)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/transactions/recent.geojson?limit=0", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid limit, got %d", rec.Code)
+	}
+}
+
+func TestRecentTransactionsRingKeepsNewest(t *testing.T) {
+	recent := newRecentTransactions(3)
+	for _, hash := range []string{"1", "2", "3", "4"} {
+		recent.add(&models.Transaction{Hash: hash})
+	}
+	got := recent.snapshot(0)
+	if len(got) != 3 || got[0].Hash != "4" || got[2].Hash != "2" {
+		t.Fatalf("unexpected snapshot %+v", got)
+	}
+	if got := recent.snapshot(1); len(got) != 1 || got[0].Hash != "4" {
+		t.Fatalf("expected limit to apply, got %+v", got)
+	}
+}
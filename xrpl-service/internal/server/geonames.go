@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/geolocation"
+	"github.com/gin-gonic/gin"
+)
+
+// handleGeoNames returns localized country and city names in the language
+// chosen by ?lang= or Accept-Language. ?countries=US,DE limits the countries,
+// which default to the whole bundled table; each ?city= is translated from
+// its English name.
+func (s *Server) handleGeoNames(c *gin.Context) {
+	lang := geolocation.MatchLanguage(c.Query("lang"), c.GetHeader("Accept-Language"))
+
+	codes := geolocation.CountryCodes()
+	if raw := c.Query("countries"); raw != "" {
+		codes = nil
+		for _, code := range strings.Split(raw, ",") {
+			if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+				codes = append(codes, code)
+			}
+		}
+	}
+	countries := make(map[string]string, len(codes))
+	for _, code := range codes {
+		if name := geolocation.CountryName(code, lang); name != "" {
+			countries[code] = name
+		}
+	}
+	cities := make(map[string]string)
+	for _, city := range c.QueryArray("city") {
+		if city = strings.TrimSpace(city); city != "" {
+			cities[city] = geolocation.CityName(city, lang)
+		}
+	}
+
+	c.Header("Content-Language", lang)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.JSON(http.StatusOK, gin.H{
+		"lang":      lang,
+		"languages": geolocation.Languages,
+		"countries": countries,
+		"cities":    cities,
+	})
+}
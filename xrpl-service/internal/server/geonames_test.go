@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGeoNamesNegotiatesLanguage(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/geo/names", srv.handleGeoNames)
+
+	req := httptest.NewRequest(http.MethodGet, "/geo/names?countries=us,de,XX&city=New%20York&city=Smallville", nil)
+	req.Header.Set("Accept-Language", "es-MX,es;q=0.9,en;q=0.5")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Language"); got != "es" {
+		t.Fatalf("expected Content-Language es, got %q", got)
+	}
+	var body struct {
+		Lang      string            `json:"lang"`
+		Countries map[string]string `json:"countries"`
+		Cities    map[string]string `json:"cities"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Lang != "es" || len(body.Countries) != 2 || body.Countries["US"] != "Estados Unidos" || body.Countries["DE"] != "Alemania" {
+		t.Fatalf("unexpected countries %+v", body)
+	}
+	if body.Cities["New York"] != "Nueva York" || body.Cities["Smallville"] != "Smallville" {
+		t.Fatalf("unexpected cities %+v", body.Cities)
+	}
+
+	// ?lang= wins over the header.
+	req = httptest.NewRequest(http.MethodGet, "/geo/names?lang=ja", nil)
+	req.Header.Set("Accept-Language", "es")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Lang != "ja" || body.Countries["JP"] != "日本" || len(body.Countries) < 50 {
+		t.Fatalf("expected the full Japanese table, got lang %q with %d countries", body.Lang, len(body.Countries))
+	}
+}
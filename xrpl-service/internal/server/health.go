@@ -0,0 +1,101 @@
+package server
+
+import (
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// UpstreamStatusSource reports the upstream connection and enrichment
+// queues. It is implemented by transaction.Listener; replica streams do not
+// report one.
+type UpstreamStatusSource interface {
+	UpstreamStatus() *models.UpstreamStatus
+}
+
+// GeoDBInfo reports the GeoLite DB in use. It is implemented by
+// geolocation.Resolver.
+type GeoDBInfo interface {
+	GeoLiteBuildTime() time.Time
+}
+
+// GeoLiteInitSource reports a GeoLite DB that failed to open at startup
+// and is being retried. It is implemented by geolocation.Resolver.
+type GeoLiteInitSource interface {
+	GeoLiteInitError() error
+}
+
+const (
+	// geoDBStaleAfter is the GeoLite DB age /health reports as stale.
+	// GeoLite is rebuilt twice a week, so this leaves room for a missed
+	// GEOLITE_REFRESH_INTERVAL download or two.
+	geoDBStaleAfter = 60 * 24 * time.Hour
+
+	// enrichmentSaturatedPercent is the enrichment queue fill /health
+	// reports as saturated; beyond it transactions are forwarded without
+	// locations.
+	enrichmentSaturatedPercent = 90
+)
+
+// Degraded flags reported by /health, next to the watchdog's stalled checks.
+const (
+	degradedUpstreamDisconnected  = "upstream_disconnected"
+	degradedStreamDown            = "transaction_stream_down"
+	degradedEnrichmentSaturated   = "enrichment_queue_saturated"
+	degradedGeoDBStale            = "geo_db_stale"
+	degradedGeoLiteUnavailable    = "geolite_unavailable"
+	degradedValidatorCacheEmpty   = "validator_cache_empty"
+	degradedValidatorsProvisional = "validators_provisional"
+	degradedSLOBreached           = "slo_breached"
+	degradedPublisherKeyAlert     = "validator_list_publisher_alert"
+	degradedListExpiry            = "validator_list_expiring"
+)
+
+// upstreamHealth adds the upstream connection, enrichment queue and GeoLite
+// DB state to status and returns their degraded flags. A paused ingestion
+// is not degraded.
+func (s *Server) upstreamHealth(status gin.H) []string {
+	degraded := []string{}
+	if source, ok := s.transactionListener.(UpstreamStatusSource); ok {
+		upstream := source.UpstreamStatus()
+		status["upstream"] = upstream
+		if !s.ingestionPaused() {
+			if !upstream.Connected {
+				degraded = append(degraded, degradedUpstreamDisconnected)
+			}
+			if !upstream.Subscribed {
+				degraded = append(degraded, degradedStreamDown)
+			}
+		}
+		if upstream.EnrichmentQueueCapacity > 0 &&
+			upstream.EnrichmentQueueDepth*100 >= upstream.EnrichmentQueueCapacity*enrichmentSaturatedPercent {
+			degraded = append(degraded, degradedEnrichmentSaturated)
+		}
+	}
+	if err := s.geoLiteInitError(); err != nil {
+		// The error names local paths, which public mode keeps private
+		if !s.publicMode {
+			status["geolite_error"] = err.Error()
+		}
+		degraded = append(degraded, degradedGeoLiteUnavailable)
+	}
+	if s.geoDB != nil {
+		if built := s.geoDB.GeoLiteBuildTime(); !built.IsZero() {
+			status["geo_db_built_at"] = built.UTC()
+			if s.clock.Now().Sub(built) > geoDBStaleAfter {
+				degraded = append(degraded, degradedGeoDBStale)
+			}
+		}
+	}
+	return degraded
+}
+
+// geoLiteInitError returns why the GeoLite DB has not opened yet while it
+// is being retried, or nil.
+func (s *Server) geoLiteInitError() error {
+	if source, ok := s.geoDB.(GeoLiteInitSource); ok {
+		return source.GeoLiteInitError()
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/transaction"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+type upstreamListener struct {
+	status models.UpstreamStatus
+}
+
+func (l *upstreamListener) AddCallback(transaction.TransactionCallback)        {}
+func (l *upstreamListener) AddGeoUpdateCallback(transaction.GeoUpdateCallback) {}
+func (l *upstreamListener) IsSubscribed() bool                                 { return l.status.Subscribed }
+func (l *upstreamListener) MinPaymentDrops() int64                             { return 0 }
+func (l *upstreamListener) UpstreamStatus() *models.UpstreamStatus {
+	status := l.status
+	return &status
+}
+
+type builtGeoDB time.Time
+
+func (b builtGeoDB) GeoLiteBuildTime() time.Time { return time.Time(b) }
+
+type pendingGeoDB struct{ err error }
+
+func (p pendingGeoDB) GeoLiteBuildTime() time.Time { return time.Time{} }
+func (p pendingGeoDB) GeoLiteInitError() error     { return p.err }
+
+func TestHealthReportsUpstreamDegradations(t *testing.T) {
+	listener := &upstreamListener{status: models.UpstreamStatus{
+		Connected:               true,
+		Subscribed:              true,
+		LastTransactionAt:       time.Now().UnixMilli(),
+		EnrichmentQueueCapacity: 100,
+	}}
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{{Address: "nHB1"}}}
+	srv.transactionListener = listener
+	srv.geoDB = builtGeoDB(time.Now().Add(-7 * 24 * time.Hour))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health", srv.handleHealth)
+
+	get := func() (string, []string, *models.UpstreamStatus) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		var body struct {
+			Status   string                 `json:"status"`
+			Degraded []string               `json:"degraded"`
+			Upstream *models.UpstreamStatus `json:"upstream"`
+			GeoDB    time.Time              `json:"geo_db_built_at"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode health: %v", err)
+		}
+		if body.GeoDB.IsZero() {
+			t.Fatalf("expected the GeoLite DB build time, got %s", rec.Body.String())
+		}
+		return body.Status, body.Degraded, body.Upstream
+	}
+
+	status, degraded, upstream := get()
+	if status != "ok" || len(degraded) != 0 || upstream == nil || upstream.LastTransactionAt != listener.status.LastTransactionAt {
+		t.Fatalf("expected a healthy upstream, got %s %v %+v", status, degraded, upstream)
+	}
+
+	listener.status.Connected = false
+	listener.status.Subscribed = false
+	listener.status.EnrichmentQueueDepth = 95
+	srv.geoDB = builtGeoDB(time.Now().Add(-90 * 24 * time.Hour))
+	status, degraded, _ = get()
+	want := []string{degradedUpstreamDisconnected, degradedStreamDown, degradedEnrichmentSaturated, degradedGeoDBStale}
+	if status != "degraded" || !reflect.DeepEqual(degraded, want) {
+		t.Fatalf("expected degraded %v, got %s %v", want, status, degraded)
+	}
+}
+
+func TestHealthAndReadyzReportUnavailableGeoLite(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{{Address: "nHB1"}}}
+	srv.transactionListener = &upstreamListener{status: models.UpstreamStatus{Connected: true, Subscribed: true}}
+	srv.geoDB = pendingGeoDB{err: errors.New("GeoLite DB not found")}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health", srv.handleHealth)
+	router.GET("/readyz", srv.handleReadyz)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health struct {
+		Status   string   `json:"status"`
+		Degraded []string `json:"degraded"`
+		Error    string   `json:"geolite_error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("failed to decode health: %v", err)
+	}
+	if health.Status != "degraded" || !reflect.DeepEqual(health.Degraded, []string{degradedGeoLiteUnavailable}) || health.Error != "GeoLite DB not found" {
+		t.Fatalf("expected the unavailable GeoLite DB to degrade health, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var ready struct {
+		Degraded []string `json:"degraded"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &ready); err != nil {
+		t.Fatalf("failed to decode readyz: %v", err)
+	}
+	if !reflect.DeepEqual(ready.Degraded, []string{degradedGeoLiteUnavailable}) {
+		t.Fatalf("expected readyz to report degraded geolocation, got %s", rec.Body.String())
+	}
+
+	srv.geoDB = pendingGeoDB{}
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	ready.Degraded = nil
+	if err := json.Unmarshal(rec.Body.Bytes(), &ready); err != nil || ready.Degraded != nil {
+		t.Fatalf("expected no degraded flag once the DB opened, got %s", rec.Body.String())
+	}
+}
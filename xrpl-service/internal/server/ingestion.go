@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleAdminIngestion reports whether upstream ingestion is paused.
+func (s *Server) handleAdminIngestion(c *gin.Context) {
+	if s.ingestion == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "ingestion control is not available"})
+		return
+	}
+	c.JSON(http.StatusOK, s.ingestion.Status())
+}
+
+// handleAdminSetIngestion pauses or resumes upstream ingestion, e.g.
+// {"paused":true,"reason":"provider maintenance"}. Cached data keeps being
+// served while paused.
+func (s *Server) handleAdminSetIngestion(c *gin.Context) {
+	if s.ingestion == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "ingestion control is not available"})
+		return
+	}
+	var body struct {
+		Paused *bool  `json:"paused"`
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Paused == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be {\"paused\": true|false, \"reason\": \"...\"}"})
+		return
+	}
+
+	if *body.Paused {
+		if err := s.ingestion.Pause(c.Request.Context(), body.Reason); err != nil {
+			s.logger.WithError(err).Error("Failed to pause ingestion")
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to pause ingestion: " + err.Error()})
+			return
+		}
+	} else if err := s.ingestion.Resume(c.Request.Context()); err != nil {
+		// Ingestion counts as resumed; failed components recover on their
+		// own reconnect schedule.
+		s.logger.WithError(err).Warn("Ingestion resumed with errors")
+	}
+	c.JSON(http.StatusOK, s.ingestion.Status())
+}
+
+// ingestionPaused reports whether upstream ingestion was paused on purpose.
+func (s *Server) ingestionPaused() bool {
+	return s.ingestion != nil && s.ingestion.Paused()
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/ingestion"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+type fakePausable struct{ paused bool }
+
+func (f *fakePausable) Pause(ctx context.Context) error {
+	f.paused = true
+	return nil
+}
+
+func (f *fakePausable) Resume(ctx context.Context) error {
+	f.paused = false
+	return nil
+}
+
+func TestAdminIngestionPauseAndResume(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/ingestion", srv.handleAdminIngestion)
+	router.PUT("/admin/ingestion", srv.handleAdminSetIngestion)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/ingestion", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without ingestion control, got %d", rec.Code)
+	}
+
+	component := &fakePausable{}
+	srv.ingestion = ingestion.NewController(nil)
+	srv.ingestion.Add(component)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/ingestion", strings.NewReader(`{"reason":"x"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without paused, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/ingestion", strings.NewReader(`{"paused":true,"reason":"provider maintenance"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var status models.IngestionStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !status.Paused || status.Reason != "provider maintenance" || status.Since == 0 || !component.paused {
+		t.Fatalf("expected ingestion to be paused, got %+v", status)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/ingestion", strings.NewReader(`{"paused":false}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if srv.ingestion.Paused() || component.paused {
+		t.Fatal("expected ingestion to be resumed")
+	}
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/transaction"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/xrpltest"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// domainGeoResolver resolves accounts through account_info on the fake node
+// and maps the decoded domain to a fixed location.
+type domainGeoResolver struct {
+	locations map[string]*models.GeoLocation
+}
+
+func (r *domainGeoResolver) ResolveAccountGeo(ctx context.Context, client xrpl.NodeClient, account string) (*models.GeoLocation, error) {
+	resp, err := client.Command(ctx, "account_info", map[string]interface{}{"account": account})
+	if err != nil {
+		return nil, err
+	}
+	result, _ := resp.(map[string]interface{})["result"].(map[string]interface{})
+	accountData, _ := result["account_data"].(map[string]interface{})
+	domainHex, _ := accountData["Domain"].(string)
+	domain, err := hex.DecodeString(domainHex)
+	if err != nil {
+		return nil, err
+	}
+	geo, ok := r.locations[string(domain)]
+	if !ok {
+		return nil, nil
+	}
+	copy := *geo
+	copy.ValidatorAddress = account
+	return &copy, nil
+}
+
+func newIntegrationServer(t *testing.T, fake *xrpltest.Server) (*Server, *transaction.Listener, *httptest.Server) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	client := xrpl.NewClient(fake.URL(), fake.WSURL(), logger)
+	t.Cleanup(func() { client.Close() })
+
+	resolver := &domainGeoResolver{
+		locations: map[string]*models.GeoLocation{
+			"example.com": {Latitude: 40.7128, Longitude: -74.0060, CountryCode: "US", City: "New York"},
+		},
+	}
+	listener := transaction.NewListener(client, 1_000_000, resolver, logger)
+	fetcher := validator.NewFetcher(
+		client,
+		time.Minute,
+		nil,
+		[]string{fake.URL()},
+		fake.URL(),
+		filepath.Join(t.TempDir(), "validator-metadata-cache.json"),
+		[]string{fake.URL()},
+		1,
+		"mainnet",
+		logger,
+	)
+	srv := NewServer(fetcher, listener, "127.0.0.1", 0, []string{"http://localhost:3000"}, 16, 16, logger)
+	httpSrv := httptest.NewServer(srv.router)
+	t.Cleanup(func() {
+		httpSrv.Close()
+		srv.Stop(context.Background())
+		listener.Stop(context.Background())
+	})
+	return srv, listener, httpSrv
+}
+
+func TestTransactionPipelineEndToEnd(t *testing.T) {
+	fake := xrpltest.NewServer()
+	defer fake.Close()
+
+	source := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	destination := "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY"
+	fake.SetAccountDomain(source, "example.com")
+
+	srv, listener, httpSrv := newIntegrationServer(t, fake)
+	if err := listener.Start(context.Background()); err != nil {
+		t.Fatalf("listener start failed: %v", err)
+	}
+	if !fake.WaitForSubscribers(1, 2*time.Second) {
+		t.Fatal("listener did not subscribe to fake transaction stream")
+	}
+
+	wsURL := "ws" + strings.TrimPrefix(httpSrv.URL, "http") + "/transactions"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Origin": []string{"http://localhost:3000"}})
+	if err != nil {
+		t.Fatalf("websocket dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.websocketClientCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	fake.Emit("transactions", xrpltest.PaymentMessage("E2EHASH", source, destination, 25_000_000, 90000001))
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var tx models.Transaction
+	if err := conn.ReadJSON(&tx); err != nil {
+		t.Fatalf("failed to read broadcast transaction: %v", err)
+	}
+	if tx.Hash != "E2EHASH" || tx.Amount != "25000000" || tx.LedgerIndex != 90000001 {
+		t.Fatalf("unexpected broadcast transaction: %+v", tx)
+	}
+	if len(tx.Locations) != 1 || tx.Locations[0].City != "New York" || tx.Locations[0].ValidatorAddress != source {
+		t.Fatalf("expected enriched source location, got %+v", tx.Locations)
+	}
+	if fake.Calls("account_info") < 2 {
+		t.Fatalf("expected account_info lookups for source and destination, got %d", fake.Calls("account_info"))
+	}
+}
+
+func TestNetworkHealthFromFakeNode(t *testing.T) {
+	fake := xrpltest.NewServer()
+	defer fake.Close()
+
+	_, _, httpSrv := newIntegrationServer(t, fake)
+
+	resp, err := http.Get(httpSrv.URL + "/network-health")
+	if err != nil {
+		t.Fatalf("network-health request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Status string              `json:"status"`
+		Server models.ServerStatus `json:"server"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode network-health: %v", err)
+	}
+	if payload.Status != "ok" || payload.Server.ServerState != "full" || payload.Server.PeerCount != 21 {
+		t.Fatalf("unexpected network-health payload: %+v", payload)
+	}
+}
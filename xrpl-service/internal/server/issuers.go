@@ -0,0 +1,68 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// handleIssuerGraph returns the latest trust line snapshot of a configured
+// issuer.
+func (s *Server) handleIssuerGraph(c *gin.Context) {
+	account := c.Param("account")
+	if s.issuerGraphs == nil || !s.issuerGraphs.Tracks(account) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "issuer is not tracked"})
+		return
+	}
+	graph := s.issuerGraphs.Graph(account)
+	if graph == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "issuer graph is not available yet"})
+		return
+	}
+	if s.privacyMode {
+		graph = anonymizeIssuerGraph(graph)
+	}
+	c.JSON(http.StatusOK, roundIssuerGraph(graph, s.coordinatePrecision))
+}
+
+// anonymizeIssuerGraph returns a copy of graph with truncated holder
+// accounts and snapped locations. The issuer is configured and requested by
+// address, so it is kept.
+func anonymizeIssuerGraph(graph *models.IssuerGraph) *models.IssuerGraph {
+	copy := *graph
+	if graph.Location != nil {
+		copy.Location = anonymizeLocations([]*models.GeoLocation{graph.Location})[0]
+	}
+	copy.Holders = make([]*models.IssuerHolder, 0, len(graph.Holders))
+	for _, holder := range graph.Holders {
+		holderCopy := *holder
+		holderCopy.Account = truncateAddress(holder.Account)
+		if holder.Location != nil {
+			holderCopy.Location = anonymizeLocations([]*models.GeoLocation{holder.Location})[0]
+		}
+		copy.Holders = append(copy.Holders, &holderCopy)
+	}
+	return &copy
+}
+
+// roundIssuerGraph returns a copy of graph with locations rounded to places
+// decimal places.
+func roundIssuerGraph(graph *models.IssuerGraph, places int) *models.IssuerGraph {
+	if places <= 0 {
+		return graph
+	}
+	copy := *graph
+	if graph.Location != nil {
+		copy.Location = roundLocations([]*models.GeoLocation{graph.Location}, places)[0]
+	}
+	copy.Holders = make([]*models.IssuerHolder, 0, len(graph.Holders))
+	for _, holder := range graph.Holders {
+		holderCopy := *holder
+		if holder.Location != nil {
+			holderCopy.Location = roundLocations([]*models.GeoLocation{holder.Location}, places)[0]
+		}
+		copy.Holders = append(copy.Holders, &holderCopy)
+	}
+	return &copy
+}
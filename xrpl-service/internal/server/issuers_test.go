@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/issuers"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/gin-gonic/gin"
+)
+
+func TestIssuerGraphEndpoint(t *testing.T) {
+	const issuer = "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"
+	client := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		return map[string]interface{}{"result": map[string]interface{}{
+			"status":      "success",
+			"obligations": map[string]interface{}{"USD": "100"},
+			"lines": []interface{}{
+				map[string]interface{}{"account": "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY", "currency": "USD", "balance": "-40"},
+			},
+		}}, nil
+	})
+	srv := newTestServer()
+	srv.issuerGraphs = issuers.NewCollector(client, nil, []string{issuer}, time.Hour, 10, nil)
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/issuers/:account/graph", srv.handleIssuerGraph)
+
+	get := func(account string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/issuers/"+account+"/graph", nil))
+		return rec
+	}
+	if rec := get("rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an untracked issuer, got %d", rec.Code)
+	}
+	if rec := get(issuer); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before the first refresh, got %d", rec.Code)
+	}
+
+	srv.issuerGraphs.Refresh(context.Background())
+	srv.privacyMode = true
+	rec := get(issuer)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var graph models.IssuerGraph
+	if err := json.Unmarshal(rec.Body.Bytes(), &graph); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if graph.Issuer != issuer || len(graph.Holders) != 1 || graph.Holders[0].Account != "rLHzPs..." || graph.Holders[0].Share != 0.4 {
+		t.Fatalf("unexpected graph %s", rec.Body.String())
+	}
+	if srv.issuerGraphs.Graph(issuer).Holders[0].Account != "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY" {
+		t.Fatal("expected privacy mode to leave the stored graph untouched")
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/labels"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// handleListLabels returns every account label, including pending and
+// rejected ones awaiting review.
+func (s *Server) handleListLabels(c *gin.Context) {
+	if s.labels == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account labels are not configured"})
+		return
+	}
+	labels := s.labels.List()
+	c.JSON(http.StatusOK, gin.H{"labels": labels, "count": len(labels)})
+}
+
+// handlePostLabel submits or corrects an account label, e.g.
+// {"account":"r...","label":"Exchange","submitted_by":"ops","status":"pending"}.
+func (s *Server) handlePostLabel(c *gin.Context) {
+	if s.labels == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account labels are not configured"})
+		return
+	}
+	var body struct {
+		Account     string `json:"account"`
+		Label       string `json:"label"`
+		SubmittedBy string `json:"submitted_by"`
+		Source      string `json:"source"`
+		Status      string `json:"status"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be {\"account\", \"label\", \"submitted_by\"} with optional \"source\" and \"status\""})
+		return
+	}
+	label, created, err := s.labels.Put(models.AccountLabel{
+		Account:     body.Account,
+		Label:       body.Label,
+		SubmittedBy: body.SubmittedBy,
+		Source:      body.Source,
+		Status:      body.Status,
+	})
+	if errors.Is(err, labels.ErrInvalidLabel) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to save account label")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save account label"})
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, label)
+}
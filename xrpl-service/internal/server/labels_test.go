@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/labels"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestPostLabelCreatesAndCorrects(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/labels", srv.handleListLabels)
+	router.POST("/labels", srv.handlePostLabel)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/labels", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a labels store, got %d", rec.Code)
+	}
+
+	store, err := labels.Load(filepath.Join(t.TempDir(), "account-labels.json"), nil, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	srv.labels = store
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/labels", strings.NewReader(body)))
+		return rec
+	}
+	if rec := post(`{"account":"rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY","label":"Exchange"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without submitted_by, got %d", rec.Code)
+	}
+	if rec := post(`{"account":"rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY","label":"Exchange","submitted_by":"ops","status":"pending"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for a new label, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = post(`{"account":"rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY","label":"Exchange Hot Wallet","submitted_by":"reviewer","status":"approved"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a correction, got %d", rec.Code)
+	}
+	var label models.AccountLabel
+	if err := json.Unmarshal(rec.Body.Bytes(), &label); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if label.Label != "Exchange Hot Wallet" || label.SubmittedBy != "reviewer" || label.Status != models.LabelStatusApproved {
+		t.Fatalf("unexpected label %+v", label)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/labels", nil))
+	var body struct {
+		Labels []*models.AccountLabel `json:"labels"`
+		Count  int                    `json:"count"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Count != 1 || body.Labels[0].Label != "Exchange Hot Wallet" {
+		t.Fatalf("unexpected labels %+v", body)
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/timeutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// stampBroadcast returns a copy of a transaction or event carrying the time
+// it is handed to clients, and records the transaction's latency. The
+// original may be shared with the recent buffer and is not modified.
+func stampBroadcast(msg interface{}, now time.Time) interface{} {
+	switch m := msg.(type) {
+	case *models.Transaction:
+		stamped := *m
+		stamped.BroadcastAt = now.UnixMilli()
+		if !stamped.Provisional {
+			observeTransactionLatency(&stamped)
+		}
+		return &stamped
+	case *models.StreamEvent:
+		stamped := *m
+		stamped.BroadcastAt = now.UnixMilli()
+		return &stamped
+	}
+	return msg
+}
+
+// observeTransactionLatency records the stages of a broadcast transaction
+// whose times are known. Ledger close times have one-second resolution.
+func observeTransactionLatency(tx *models.Transaction) {
+	if tx.ReceivedAt == 0 {
+		return // synthetic, or from an upstream that does not stamp
+	}
+	var closedAt int64
+	if tx.CloseTime != 0 {
+		closedAt = timeutil.RippleToUnixMilli(tx.CloseTime)
+	}
+	observe := func(stage string, from, to int64) {
+		if from == 0 || to < from {
+			return
+		}
+		metrics.TransactionLatencySeconds.WithLabelValues(stage).Observe(float64(to-from) / 1000)
+	}
+	observe("receive", closedAt, tx.ReceivedAt)
+	if tx.EnrichedAt != 0 {
+		observe("enrich", tx.ReceivedAt, tx.EnrichedAt)
+	}
+	observe("broadcast", tx.ReceivedAt, tx.BroadcastAt)
+	observe("end_to_end", closedAt, tx.BroadcastAt)
+}
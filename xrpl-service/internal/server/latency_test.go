@@ -0,0 +1,27 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/timeutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestStampBroadcastCopiesMessages(t *testing.T) {
+	now := time.UnixMilli(1_700_000_001_500)
+	tx := &models.Transaction{Hash: "ABC", CloseTime: uint32(1_700_000_000 - timeutil.RippleEpoch), ReceivedAt: 1_700_000_000_900}
+
+	stamped, ok := stampBroadcast(tx, now).(*models.Transaction)
+	if !ok || stamped == tx || stamped.Hash != "ABC" || stamped.BroadcastAt != now.UnixMilli() {
+		t.Fatalf("expected a stamped copy of the transaction, got %+v", stamped)
+	}
+	if tx.BroadcastAt != 0 {
+		t.Fatal("expected the shared transaction to be left unchanged")
+	}
+
+	event := &models.StreamEvent{Type: "fee_burn"}
+	if stamped, ok := stampBroadcast(event, now).(*models.StreamEvent); !ok || stamped.BroadcastAt != now.UnixMilli() || event.BroadcastAt != 0 {
+		t.Fatalf("expected a stamped copy of the event, got %+v", stamped)
+	}
+}
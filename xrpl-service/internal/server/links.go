@@ -0,0 +1,57 @@
+package server
+
+import (
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// linkTransaction returns a copy of tx carrying its explorer links. In
+// privacy mode accounts are truncated, so only the transaction and ledger
+// are linked.
+func (s *Server) linkTransaction(tx *models.Transaction) *models.Transaction {
+	if s.explorerLinks == nil {
+		return tx
+	}
+	copy := *tx
+	copy.Links = s.explorerLinks.Transaction(tx, !s.privacyMode)
+	return &copy
+}
+
+// linkValidators returns copies of validators carrying their explorer
+// links.
+func (s *Server) linkValidators(validators []*models.Validator) []*models.Validator {
+	if s.explorerLinks == nil {
+		return validators
+	}
+	out := make([]*models.Validator, 0, len(validators))
+	for _, v := range validators {
+		if v == nil {
+			continue
+		}
+		copy := *v
+		copy.Links = s.explorerLinks.Validator(v.Address)
+		out = append(out, &copy)
+	}
+	return out
+}
+
+// linkValidatorDelta adds explorer links to the upsert of a validator seen
+// for the first time, the only upsert carrying its public key; links never
+// change afterwards.
+func (s *Server) linkValidatorDelta(delta *models.ValidatorDelta) *models.ValidatorDelta {
+	if s.explorerLinks == nil || delta == nil {
+		return delta
+	}
+	if _, ok := delta.Fields["public_key"]; !ok {
+		return delta
+	}
+	links := s.explorerLinks.Validator(delta.Address)
+	if len(links) == 0 {
+		return delta
+	}
+	fields := make(map[string]interface{}, len(delta.Fields)+1)
+	for key, value := range delta.Fields {
+		fields[key] = value
+	}
+	fields["links"] = links
+	return &models.ValidatorDelta{Address: delta.Address, Fields: fields}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/explorer"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestTransactionsCarryExplorerLinks(t *testing.T) {
+	srv := newTestServer()
+	srv.explorerLinks = explorer.NewLinker([]models.Explorer{explorer.Presets["xrpscan"]})
+
+	srv.onTransaction(&models.Transaction{Hash: "ABC", LedgerIndex: 7, Account: "rSource", Destination: "rDest"})
+	recent := srv.recent.snapshot(1)
+	if len(recent) != 1 || len(recent[0].Links) != 1 {
+		t.Fatalf("expected the recent transaction linked, got %+v", recent)
+	}
+	if link := recent[0].Links[0]; link.Transaction != "https://xrpscan.com/tx/ABC" || link.Destination != "https://xrpscan.com/account/rDest" {
+		t.Fatalf("unexpected links %+v", link)
+	}
+
+	srv.privacyMode = true
+	srv.onTransaction(&models.Transaction{Hash: "DEF", Account: "rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY"})
+	if link := srv.recent.snapshot(1)[0].Links[0]; link.Account != "" || link.Transaction != "https://xrpscan.com/tx/DEF" {
+		t.Fatalf("expected no account links in privacy mode, got %+v", link)
+	}
+}
+
+func TestValidatorsCarryExplorerLinks(t *testing.T) {
+	srv := newTestServer()
+	shared := &models.Validator{Address: "nHUvalidator", PublicKey: "ED01"}
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{shared}}
+
+	if validators := srv.publicValidators(); validators[0].Links != nil {
+		t.Fatalf("expected no links without explorers, got %+v", validators[0].Links)
+	}
+
+	srv.explorerLinks = explorer.NewLinker([]models.Explorer{explorer.Presets["livenet"]})
+	validators := srv.publicValidators()
+	if len(validators[0].Links) != 1 || validators[0].Links[0].Validator != "https://livenet.xrpl.org/network/validators/nHUvalidator" {
+		t.Fatalf("unexpected validator links %+v", validators[0].Links)
+	}
+	if shared.Links != nil {
+		t.Fatal("expected the fetcher's validator left unchanged")
+	}
+
+	added := srv.linkValidatorDelta(&models.ValidatorDelta{Address: "nHUvalidator", Fields: map[string]interface{}{"public_key": "ED01"}})
+	if _, ok := added.Fields["links"]; !ok {
+		t.Fatalf("expected a new validator's upsert linked, got %+v", added.Fields)
+	}
+	changed := srv.linkValidatorDelta(&models.ValidatorDelta{Address: "nHUvalidator", Fields: map[string]interface{}{"domain": "example.com"}})
+	if _, ok := changed.Fields["links"]; ok {
+		t.Fatalf("expected changed fields only, got %+v", changed.Fields)
+	}
+}
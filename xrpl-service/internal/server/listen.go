@@ -0,0 +1,93 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseListenSpec maps a listen spec to a network and address for
+// net.Listen. Specs are "unix:/path/to/socket", "host:port", or "host:port"
+// prefixed with "tcp:", "tcp4:" or "tcp6:" to force the network. Unprefixed
+// IPv4 and IPv6 literal hosts listen on tcp4 and tcp6 respectively, so
+// "0.0.0.0:8080" and "[::]:8080" can be bound side by side; other hosts use
+// tcp. Ports are numbers, 0 picking a free one.
+func ParseListenSpec(spec string) (string, string, error) {
+	spec = strings.TrimSpace(spec)
+	for _, network := range []string{"tcp4", "tcp6", "tcp"} {
+		if address, ok := strings.CutPrefix(spec, network+":"); ok {
+			_, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid listen spec %q: %w", spec, err)
+			}
+			if err := checkListenPort(spec, port); err != nil {
+				return "", "", err
+			}
+			return network, address, nil
+		}
+	}
+	if path, ok := strings.CutPrefix(spec, "unix:"); ok {
+		path = strings.TrimPrefix(path, "//")
+		if path == "" {
+			return "", "", fmt.Errorf("listen spec %q has an empty socket path", spec)
+		}
+		return "unix", path, nil
+	}
+
+	host, port, err := net.SplitHostPort(spec)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid listen spec %q: %w", spec, err)
+	}
+	if err := checkListenPort(spec, port); err != nil {
+		return "", "", err
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.To4() != nil {
+			return "tcp4", spec, nil
+		}
+		return "tcp6", spec, nil
+	}
+	return "tcp", spec, nil
+}
+
+func checkListenPort(spec, port string) error {
+	if port == "" {
+		return fmt.Errorf("listen spec %q has no port", spec)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("invalid listen spec %q: bad port", spec)
+	}
+	return nil
+}
+
+// listen opens a listener for spec. A stale unix socket left by an unclean
+// shutdown is removed first; a socket something still answers on, or any
+// other existing file, is left alone.
+func listen(spec string) (net.Listener, error) {
+	network, address, err := ParseListenSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	if network == "unix" {
+		if info, err := os.Lstat(address); err == nil {
+			if info.Mode()&fs.ModeSocket == 0 {
+				return nil, fmt.Errorf("listen spec %q: %s exists and is not a socket", spec, address)
+			}
+			if conn, err := net.DialTimeout("unix", address, time.Second); err == nil {
+				conn.Close()
+				return nil, fmt.Errorf("listen spec %q: %s is in use", spec, address)
+			}
+			if err := os.Remove(address); err != nil {
+				return nil, err
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return net.Listen(network, address)
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseListenSpec(t *testing.T) {
+	tests := []struct {
+		spec        string
+		wantNetwork string
+		wantAddress string
+		wantErr     bool
+	}{
+		{spec: "0.0.0.0:8080", wantNetwork: "tcp4", wantAddress: "0.0.0.0:8080"},
+		{spec: "[::]:8080", wantNetwork: "tcp6", wantAddress: "[::]:8080"},
+		{spec: "localhost:8080", wantNetwork: "tcp", wantAddress: "localhost:8080"},
+		{spec: "tcp:0.0.0.0:8080", wantNetwork: "tcp", wantAddress: "0.0.0.0:8080"},
+		{spec: "unix:/run/xrpl.sock", wantNetwork: "unix", wantAddress: "/run/xrpl.sock"},
+		{spec: "unix:///run/xrpl.sock", wantNetwork: "unix", wantAddress: "/run/xrpl.sock"},
+		{spec: "unix:", wantErr: true},
+		{spec: "8080", wantErr: true},
+		{spec: "tcp6:[::]", wantErr: true},
+		{spec: "tcp:127.0.0.1:", wantErr: true},
+		{spec: "127.0.0.1:99999", wantErr: true},
+		{spec: "localhost:http", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		network, address, err := ParseListenSpec(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseListenSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+		}
+		if !tt.wantErr && (network != tt.wantNetwork || address != tt.wantAddress) {
+			t.Fatalf("ParseListenSpec(%q) = %q, %q, want %q, %q", tt.spec, network, address, tt.wantNetwork, tt.wantAddress)
+		}
+	}
+}
+
+func TestStartServesOnTCPAndUnixListeners(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "xrpl.sock")
+	srv := newTestServer()
+	srv.listenSpecs = []string{"127.0.0.1:0", "unix:" + socketPath}
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Start(context.Background()) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(srv.ListenAddrs()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	addrs := srv.ListenAddrs()
+	if len(addrs) != 2 {
+		t.Fatalf("expected 2 listeners, got %v", addrs)
+	}
+
+	tcpResp, err := http.Get("http://" + addrs[0].String() + "/ping")
+	if err != nil {
+		t.Fatalf("tcp request failed: %v", err)
+	}
+	tcpResp.Body.Close()
+
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+		},
+	}}
+	unixResp, err := unixClient.Get("http://unix/ping")
+	if err != nil {
+		t.Fatalf("unix socket request failed: %v", err)
+	}
+	unixResp.Body.Close()
+	if tcpResp.StatusCode != http.StatusOK || unixResp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected statuses tcp=%d unix=%d", tcpResp.StatusCode, unixResp.StatusCode)
+	}
+
+	if err := srv.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if err := <-done; err != http.ErrServerClosed {
+		t.Fatalf("expected ErrServerClosed from Start, got %v", err)
+	}
+}
+
+func TestListenRemovesOnlyStaleSockets(t *testing.T) {
+	dir := t.TempDir()
+
+	live := filepath.Join(dir, "live.sock")
+	existing, err := net.Listen("unix", live)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer existing.Close()
+	if _, err := listen("unix:" + live); err == nil {
+		t.Fatal("expected a socket in use to be left alone")
+	}
+	go func() {
+		if conn, err := existing.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+	conn, err := net.Dial("unix", live)
+	if err != nil {
+		t.Fatalf("expected the running listener to keep its socket: %v", err)
+	}
+	conn.Close()
+
+	// A socket left by an unclean shutdown is replaced.
+	stale := filepath.Join(dir, "stale.sock")
+	old, err := net.Listen("unix", stale)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	old.(*net.UnixListener).SetUnlinkOnClose(false)
+	old.Close()
+	replaced, err := listen("unix:" + stale)
+	if err != nil {
+		t.Fatalf("expected the stale socket to be replaced: %v", err)
+	}
+	replaced.Close()
+
+	regular := filepath.Join(dir, "regular")
+	if err := os.WriteFile(regular, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := listen("unix:" + regular); err == nil {
+		t.Fatal("expected a regular file to be left alone")
+	}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/timeutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// defaultUpstreamLagTarget is the upstream lag /load reports as 100%.
+const defaultUpstreamLagTarget = 10 * time.Second
+
+var metricsHandler = promhttp.Handler()
+
+// recordUpstreamLag remembers how long after its ledger closed a
+// transaction was handed to clients. msg has been stamped by stampBroadcast.
+func (s *Server) recordUpstreamLag(msg interface{}) {
+	tx, ok := msg.(*models.Transaction)
+	if !ok || tx.CloseTime == 0 || tx.BroadcastAt == 0 {
+		return
+	}
+	lag := tx.BroadcastAt - timeutil.RippleToUnixMilli(tx.CloseTime)
+	if lag < 0 {
+		lag = 0
+	}
+	s.upstreamLagMillis.Store(lag)
+}
+
+// loadSignals computes the load indicators and updates their gauges.
+// Sources without an enrichment queue, such as replica streams, report it
+// empty.
+func (s *Server) loadSignals() *models.LoadSignals {
+	signals := &models.LoadSignals{
+		Timestamp:                  s.clock.Now().Unix(),
+		BroadcastSaturationPercent: percentOf(float64(len(s.broadcast)), float64(cap(s.broadcast))),
+		UpstreamLagSeconds:         float64(s.upstreamLagMillis.Load()) / 1000,
+		UpstreamLagTargetSeconds:   s.upstreamLagTarget.Seconds(),
+		WebSocketClients:           s.websocketClientCount(),
+	}
+	if source, ok := s.transactionListener.(UpstreamStatusSource); ok {
+		upstream := source.UpstreamStatus()
+		signals.EnrichmentQueueSaturationPercent = percentOf(float64(upstream.EnrichmentQueueDepth), float64(upstream.EnrichmentQueueCapacity))
+	}
+	signals.UpstreamLagPercent = percentOf(signals.UpstreamLagSeconds, signals.UpstreamLagTargetSeconds)
+	signals.LoadPercent = math.Max(signals.EnrichmentQueueSaturationPercent, math.Max(signals.BroadcastSaturationPercent, signals.UpstreamLagPercent))
+
+	metrics.LoadPercent.Set(signals.LoadPercent)
+	metrics.LoadEnrichmentQueueSaturationPercent.Set(signals.EnrichmentQueueSaturationPercent)
+	metrics.LoadBroadcastSaturationPercent.Set(signals.BroadcastSaturationPercent)
+	metrics.LoadUpstreamLagSeconds.Set(signals.UpstreamLagSeconds)
+	return signals
+}
+
+// percentOf returns value in percent of total, rounded to one decimal, or 0
+// without a total.
+func percentOf(value, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(value/total*1000) / 10
+}
+
+// handleLoad returns the load indicators, for HPA and KEDA external
+// scalers.
+func (s *Server) handleLoad(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, s.loadSignals())
+}
+
+// handleMetrics serves the Prometheus metrics, refreshing the load gauges
+// first.
+func (s *Server) handleMetrics(c *gin.Context) {
+	s.loadSignals()
+	metricsHandler.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/timeutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoadSignals(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1_700_000_000, 0))
+	srv := newTestServer()
+	srv.clock = clk
+	srv.upstreamLagTarget = defaultUpstreamLagTarget
+	srv.transactionListener = &upstreamListener{status: models.UpstreamStatus{EnrichmentQueueDepth: 512, EnrichmentQueueCapacity: 2048}}
+	srv.broadcast <- &models.Transaction{Hash: "QUEUED"}
+
+	closeTime := uint32(clk.Now().Unix()-timeutil.RippleEpoch) - 15
+	srv.recordUpstreamLag(stampBroadcast(&models.Transaction{Hash: "A", CloseTime: closeTime}, clk.Now()))
+	srv.recordUpstreamLag(stampBroadcast(&models.StreamEvent{Type: "server_status"}, clk.Now()))
+
+	signals := srv.loadSignals()
+	if signals.EnrichmentQueueSaturationPercent != 25 || signals.BroadcastSaturationPercent != 25 {
+		t.Fatalf("expected 25%% saturations, got %+v", signals)
+	}
+	if signals.UpstreamLagSeconds != 15 || signals.UpstreamLagPercent != 150 || signals.LoadPercent != 150 {
+		t.Fatalf("expected a 15s lag at 150%% load, got %+v", signals)
+	}
+
+	srv.transactionListener = nil
+	if signals := srv.loadSignals(); signals.EnrichmentQueueSaturationPercent != 0 {
+		t.Fatalf("expected no enrichment saturation without an upstream status, got %+v", signals)
+	}
+}
+
+func TestHandleLoadAndMetrics(t *testing.T) {
+	srv := newTestServer()
+	srv.upstreamLagTarget = defaultUpstreamLagTarget
+	srv.transactionListener = &upstreamListener{}
+	router := gin.New()
+	router.GET("/load", srv.handleLoad)
+	router.GET("/metrics", srv.handleMetrics)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/load", nil))
+	var signals models.LoadSignals
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &signals) != nil || signals.UpstreamLagTargetSeconds != 10 {
+		t.Fatalf("unexpected /load response %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "xrpl_validator_load_percent") {
+		t.Fatalf("expected /metrics to export the load gauges, got %d", rec.Code)
+	}
+}
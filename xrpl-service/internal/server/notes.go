@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/gin-gonic/gin"
+)
+
+// ValidatorNotesSource keeps operator notes on validators. It is
+// implemented by validator.Fetcher; replicas do not keep notes.
+type ValidatorNotesSource interface {
+	GetNotes(ctx context.Context, key string) (*models.ValidatorNotes, error)
+	AddNote(ctx context.Context, key string, note models.ValidatorNote) (*models.ValidatorNote, error)
+}
+
+// handleAdminValidatorNotes returns the operator notes of one validator,
+// looked up by its master key or any signing key it has used.
+func (s *Server) handleAdminValidatorNotes(c *gin.Context) {
+	source, ok := s.validatorFetcher.(ValidatorNotesSource)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "validator notes are not available"})
+		return
+	}
+	notes, err := source.GetNotes(c.Request.Context(), c.Param("address"))
+	if errors.Is(err, xrpl.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "validator not found"})
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to fetch validator notes")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, notes)
+}
+
+// handleAdminAddValidatorNote attaches a note to a validator, e.g.
+// {"text":"contacted about domain mismatch","tags":["outreach"],"author":"ops"}.
+func (s *Server) handleAdminAddValidatorNote(c *gin.Context) {
+	source, ok := s.validatorFetcher.(ValidatorNotesSource)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "validator notes are not available"})
+		return
+	}
+	var body struct {
+		Text   string   `json:"text"`
+		Tags   []string `json:"tags"`
+		Author string   `json:"author"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be {\"text\"} with optional \"tags\" and \"author\""})
+		return
+	}
+	note, err := source.AddNote(c.Request.Context(), c.Param("address"), models.ValidatorNote{
+		Text:   body.Text,
+		Tags:   body.Tags,
+		Author: body.Author,
+	})
+	if errors.Is(err, validator.ErrInvalidNote) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, xrpl.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "validator not found"})
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to add validator note")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, note)
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminValidatorNotesWithoutNotesSupport(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/validators/:address/notes", srv.handleAdminValidatorNotes)
+	router.POST("/admin/validators/:address/notes", srv.handleAdminAddValidatorNote)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/validators/nA1/notes", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/validators/nA1/notes", strings.NewReader(`{"text":"hello"}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,14 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// handleOperators returns validators aggregated by operator, largest first,
+// for per-operator decentralization views.
+func (s *Server) handleOperators(c *gin.Context) {
+	c.JSON(http.StatusOK, validator.SummarizeOperators(requestView(c).filterValidators(s.publicValidators())))
+}
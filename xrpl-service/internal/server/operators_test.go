@@ -0,0 +1,39 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestOperatorsAggregatesValidators(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{
+		{Address: "nA1", Domain: "a.example", CountryCode: "DE", Operator: "example"},
+		{Address: "nA2", Domain: "b.example", CountryCode: "US", Operator: "example"},
+		{Address: "nB1", Domain: "other.example", CountryCode: "FR", Operator: "other.example"},
+	}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/operators", srv.handleOperators)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/operators", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var summary models.OperatorSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if summary.OperatorCount != 2 || summary.ValidatorCount != 3 || summary.NakamotoCoefficient != 1 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if top := summary.Operators[0]; top.ID != "example" || top.ValidatorCount != 2 || len(top.Validators) != 2 {
+		t.Fatalf("unexpected top operator %+v", top)
+	}
+}
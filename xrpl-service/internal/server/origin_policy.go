@@ -0,0 +1,129 @@
+package server
+
+import (
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// defaultOriginPolicyKey selects the policy applied to allowed origins that
+// have no policy of their own.
+const defaultOriginPolicyKey = "*"
+
+// originPolicy is the resolved form of models.OriginPolicy for one origin.
+type originPolicy struct {
+	maxConnections       int
+	channels             map[string]struct{}
+	maxMessagesPerSecond float64
+}
+
+func newOriginPolicy(policy models.OriginPolicy) *originPolicy {
+	resolved := &originPolicy{
+		maxConnections:       policy.MaxConnections,
+		maxMessagesPerSecond: policy.MaxMessagesPerSecond,
+	}
+	if len(policy.Channels) > 0 {
+		resolved.channels = make(map[string]struct{}, len(policy.Channels))
+		for _, channel := range policy.Channels {
+			resolved.channels[channel] = struct{}{}
+		}
+	}
+	return resolved
+}
+
+// originPolicyFor returns the policy for origin, falling back to the "*"
+// policy. It returns nil when no policy applies.
+func (s *Server) originPolicyFor(origin string) *originPolicy {
+	if policy, ok := s.originPolicies[origin]; ok {
+		return policy
+	}
+	return s.originPolicies[defaultOriginPolicyKey]
+}
+
+// allows reports whether a client under this policy may receive channel.
+func (p *originPolicy) allows(channel string) bool {
+	if p == nil || p.channels == nil {
+		return true
+	}
+	_, ok := p.channels[channel]
+	return ok
+}
+
+func (p *originPolicy) newLimiter() *messageRateLimiter {
+	if p == nil || p.maxMessagesPerSecond <= 0 {
+		return nil
+	}
+	burst := p.maxMessagesPerSecond
+	if burst < 1 {
+		burst = 1
+	}
+	return &messageRateLimiter{rate: p.maxMessagesPerSecond, burst: burst, tokens: burst}
+}
+
+// streamChannels lists the channels messageChannel returns for broadcast
+// messages: transactions and the type of each broadcast event.
+var streamChannels = map[string]bool{
+	"transactions":      true,
+	"tx_geo_update":     true,
+	"tx_settlement":     true,
+	"server_status":     true,
+	"server_alert":      true,
+	"watchdog_alert":    true,
+	"slo_alert":         true,
+	"anomaly":           true,
+	"fee_burn":          true,
+	"validator_upsert":  true,
+	"validator_remove":  true,
+	"validator_rotated": true,
+	"fetch_cycle":       true,
+}
+
+// IsStreamChannel reports whether name is a channel of the /transactions
+// stream, as origin policies, views and client preferences list them.
+func IsStreamChannel(name string) bool {
+	return streamChannels[name]
+}
+
+// messageChannel names the stream channel a broadcast message belongs to.
+func messageChannel(msg interface{}) string {
+	switch typed := msg.(type) {
+	case *models.Transaction:
+		return "transactions"
+	case *models.StreamEvent:
+		return typed.Type
+	}
+	return ""
+}
+
+// messageRateLimiter is a token bucket. It does no locking: per-client
+// limiters are only used from broadcastLoop, and ipRateLimiter guards its
+// own.
+type messageRateLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (l *messageRateLimiter) allow(now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// full reports whether the bucket will have refilled by now.
+func (l *messageRateLimiter) full(now time.Time) bool {
+	return l.tokens+now.Sub(l.last).Seconds()*l.rate >= l.burst
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMessageRateLimiterRefills(t *testing.T) {
+	limiter := newOriginPolicy(models.OriginPolicy{MaxMessagesPerSecond: 2}).newLimiter()
+	now := time.Unix(1000, 0)
+
+	if !limiter.allow(now) || !limiter.allow(now) {
+		t.Fatal("expected burst of 2 to be allowed")
+	}
+	if limiter.allow(now) {
+		t.Fatal("expected third message in the same instant to be dropped")
+	}
+	if !limiter.allow(now.Add(500 * time.Millisecond)) {
+		t.Fatal("expected a token to refill after 500ms at 2 msg/s")
+	}
+	if newOriginPolicy(models.OriginPolicy{}).newLimiter() != nil {
+		t.Fatal("expected no limiter without a rate")
+	}
+}
+
+func TestBroadcastLoopFiltersChannelsByOriginPolicy(t *testing.T) {
+	srv := newTestServer()
+	embed := &WSClient{send: make(chan interface{}, 4), server: srv, policy: newOriginPolicy(models.OriginPolicy{Channels: []string{"transactions"}})}
+	primary := &WSClient{send: make(chan interface{}, 4), server: srv}
+	srv.wsClients[embed] = true
+	srv.wsClients[primary] = true
+
+	go srv.broadcastLoop()
+	defer close(srv.stopBroadcast)
+
+	srv.broadcast <- &models.StreamEvent{Type: "server_status"}
+	srv.broadcast <- &models.Transaction{Hash: "ABC"}
+
+	deadline := time.After(time.Second)
+	for len(primary.send) < 2 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected primary client to receive both messages, got %d", len(primary.send))
+		case <-time.After(time.Millisecond):
+		}
+	}
+	if len(embed.send) != 1 {
+		t.Fatalf("expected embed client to receive only the transaction, got %d messages", len(embed.send))
+	}
+	if tx, ok := (<-embed.send).(*models.Transaction); !ok || tx.Hash != "ABC" {
+		t.Fatalf("expected transaction for embed client, got %+v", tx)
+	}
+}
+
+func TestTransactionsWebSocketEnforcesOriginConnectionCap(t *testing.T) {
+	srv := newTestServer()
+	srv.originConns = make(map[string]int)
+	srv.originPolicies = map[string]*originPolicy{
+		"https://embed.example": newOriginPolicy(models.OriginPolicy{MaxConnections: 1}),
+	}
+	if !srv.reserveOriginConnection("https://embed.example", srv.originPolicyFor("https://embed.example")) {
+		t.Fatal("expected first connection to be admitted")
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/transactions", srv.handleTransactionsWebSocket)
+	req := httptest.NewRequest(http.MethodGet, "/transactions", nil)
+	req.Header.Set("Origin", "https://embed.example")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 over origin cap, got %d", rec.Code)
+	}
+
+	srv.releaseOriginConnection("https://embed.example")
+	if !srv.reserveOriginConnection("https://embed.example", srv.originPolicyFor("https://embed.example")) {
+		t.Fatal("expected connection to be admitted after release")
+	}
+	if srv.originPolicyFor("https://other.example") != nil {
+		t.Fatal("expected no policy for unconfigured origin without a * policy")
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"math"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// roundToPrecision rounds a latitude or longitude to places decimal places.
+// Zero places leaves it unrounded. Values rounding to zero are returned as
+// positive zero, which encodes as 0 rather than -0.
+func roundToPrecision(value float64, places int) float64 {
+	if places <= 0 {
+		return value
+	}
+	scale := math.Pow(10, float64(places))
+	rounded := math.Round(value*scale) / scale
+	if rounded == 0 {
+		return 0
+	}
+	return rounded
+}
+
+// roundLocations returns copies of locations with coordinates rounded to
+// places decimal places.
+func roundLocations(locations []*models.GeoLocation, places int) []*models.GeoLocation {
+	if locations == nil || places <= 0 {
+		return locations
+	}
+	out := make([]*models.GeoLocation, 0, len(locations))
+	for _, loc := range locations {
+		if loc == nil {
+			continue
+		}
+		copy := *loc
+		copy.Latitude = roundToPrecision(loc.Latitude, places)
+		copy.Longitude = roundToPrecision(loc.Longitude, places)
+		out = append(out, &copy)
+	}
+	return out
+}
+
+// roundValidators returns copies of validators with coordinates rounded to
+// places decimal places.
+func roundValidators(validators []*models.Validator, places int) []*models.Validator {
+	if places <= 0 {
+		return validators
+	}
+	out := make([]*models.Validator, 0, len(validators))
+	for _, v := range validators {
+		if v == nil {
+			continue
+		}
+		copy := *v
+		copy.Latitude = roundToPrecision(v.Latitude, places)
+		copy.Longitude = roundToPrecision(v.Longitude, places)
+		out = append(out, &copy)
+	}
+	return out
+}
+
+// roundValidatorDelta rounds changed coordinates in an upsert delta.
+func roundValidatorDelta(delta *models.ValidatorDelta, places int) *models.ValidatorDelta {
+	if delta == nil || delta.Fields == nil || places <= 0 {
+		return delta
+	}
+	fields := make(map[string]interface{}, len(delta.Fields))
+	for key, value := range delta.Fields {
+		if coordinate, ok := value.(float64); ok && (key == "latitude" || key == "longitude") {
+			value = roundToPrecision(coordinate, places)
+		}
+		fields[key] = value
+	}
+	return &models.ValidatorDelta{Address: delta.Address, Fields: fields}
+}
+
+// roundTransaction returns a copy of tx with its locations rounded. tx
+// itself is shared with other callbacks and is not modified.
+func roundTransaction(tx *models.Transaction, places int) *models.Transaction {
+	if places <= 0 || len(tx.Locations) == 0 {
+		return tx
+	}
+	copy := *tx
+	copy.Locations = roundLocations(tx.Locations, places)
+	return &copy
+}
+
+// roundPeers returns a copy of summary with peer locations rounded.
+func roundPeers(summary *models.PeerSummary, places int) *models.PeerSummary {
+	if places <= 0 {
+		return summary
+	}
+	copy := *summary
+	copy.Peers = make([]*models.PeerInfo, 0, len(summary.Peers))
+	for _, peer := range summary.Peers {
+		if peer == nil {
+			continue
+		}
+		peerCopy := *peer
+		if peer.Location != nil {
+			peerCopy.Location = roundLocations([]*models.GeoLocation{peer.Location}, places)[0]
+		}
+		copy.Peers = append(copy.Peers, &peerCopy)
+	}
+	return &copy
+}
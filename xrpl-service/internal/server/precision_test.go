@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCoordinatePrecisionRoundsPublishedCoordinates(t *testing.T) {
+	srv := newTestServer()
+	srv.coordinatePrecision = 2
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{
+		{Address: "nA1", Domain: "a.example", Latitude: 40.712776, Longitude: -74.005974},
+	}}
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/validators", srv.handleGetValidators)
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators", nil))
+	var body struct {
+		Validators []*models.Validator `json:"validators"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(body.Validators) != 1 || body.Validators[0].Latitude != 40.71 || body.Validators[0].Longitude != -74.01 {
+		t.Fatalf("expected coordinates rounded to 2 places, got %s", rec.Body.String())
+	}
+
+	tx := &models.Transaction{
+		Hash:      "ABC",
+		Locations: []*models.GeoLocation{{Latitude: 48.856613, Longitude: 2.352222, CountryCode: "FR"}},
+	}
+	srv.onTransaction(tx)
+	got := (<-srv.broadcast).(*models.Transaction)
+	if got.Locations[0].Latitude != 48.86 || got.Locations[0].Longitude != 2.35 {
+		t.Fatalf("expected transaction locations rounded, got %+v", got.Locations[0])
+	}
+	if tx.Locations[0].Latitude != 48.856613 {
+		t.Fatal("expected the shared transaction to be left untouched")
+	}
+
+	srv.onValidatorUpdate(&models.ValidatorUpdate{Upserts: []*models.ValidatorDelta{
+		{Address: "nA1", Fields: map[string]interface{}{"longitude": 139.691706, "city": "Tokyo"}},
+	}})
+	delta := (<-srv.broadcast).(*models.StreamEvent).Data.(*models.ValidatorDelta)
+	if delta.Fields["longitude"] != 139.69 || delta.Fields["city"] != "Tokyo" {
+		t.Fatalf("expected delta coordinates rounded, got %v", delta.Fields)
+	}
+}
+
+func TestCoordinatePrecisionAppliesAfterPrivacyGrid(t *testing.T) {
+	srv := newTestServer()
+	srv.privacyMode = true
+	srv.coordinatePrecision = 1
+	srv.onTxGeoUpdate(&models.TxGeoUpdate{Hash: "ABC", Locations: []*models.GeoLocation{{Latitude: -33.87, Longitude: 151.21}}})
+	update := (<-srv.broadcast).(*models.StreamEvent).Data.(*models.TxGeoUpdate)
+	if update.Locations[0].Latitude != -34 || update.Locations[0].Longitude != 151 {
+		t.Fatalf("expected grid-snapped coordinates, got %+v", update.Locations[0])
+	}
+}
+
+func TestRoundToPrecisionZeroLeavesValue(t *testing.T) {
+	if got := roundToPrecision(40.712776, 0); got != 40.712776 {
+		t.Fatalf("expected zero places to leave the value, got %v", got)
+	}
+	if got := roundToPrecision(-0.0049, 2); got != 0 || math.Signbit(got) {
+		t.Fatalf("expected -0.0049 to round to positive zero, got %v", got)
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/preferences"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// preferencesKey resolves the API key a /preferences request is made with.
+// Preferences belong to a named key, so anonymous requests are refused.
+func (s *Server) preferencesKey(c *gin.Context) (string, bool) {
+	if s.preferences == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "client preferences are not configured"})
+		return "", false
+	}
+	name, ok := s.resolveAPIKey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
+		return "", false
+	}
+	if name == anonymousAPIKey {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "an API key is required"})
+		return "", false
+	}
+	return name, true
+}
+
+// handleGetPreferences returns the stream defaults of the request's API key.
+func (s *Server) handleGetPreferences(c *gin.Context) {
+	name, ok := s.preferencesKey(c)
+	if !ok {
+		return
+	}
+	prefs, ok := s.preferences.Get(name)
+	if !ok {
+		prefs = &models.ClientPreferences{Name: name}
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+// handlePutPreferences replaces the stream defaults of the request's API
+// key, e.g. {"min_payment_drops":1000000,"channels":["transactions"],"units":"xrp"}.
+// They apply to WebSocket connections made after the change.
+func (s *Server) handlePutPreferences(c *gin.Context) {
+	name, ok := s.preferencesKey(c)
+	if !ok {
+		return
+	}
+	var body models.ClientPreferences
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be a JSON object of client preferences"})
+		return
+	}
+	for _, channel := range body.Channels {
+		if !IsStreamChannel(channel) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown channel %q", channel)})
+			return
+		}
+	}
+	topics, err := parseTopics(body.Topics, s.privacyMode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(topics) > maxClientTopics {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d topics per client", maxClientTopics)})
+		return
+	}
+	body.Topics = nil
+	for _, t := range topics {
+		body.Topics = append(body.Topics, t.name)
+	}
+
+	prefs, err := s.preferences.Put(name, body)
+	if errors.Is(err, preferences.ErrInvalidPreferences) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to save client preferences")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save client preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+// clientPreferences returns the stored stream defaults of apiKey, or empty
+// preferences.
+func (s *Server) clientPreferences(apiKey string) *models.ClientPreferences {
+	if s.preferences != nil && apiKey != anonymousAPIKey {
+		if prefs, ok := s.preferences.Get(apiKey); ok {
+			return prefs
+		}
+	}
+	return &models.ClientPreferences{}
+}
+
+// preferencesFilter returns the view filtering a client by its stored
+// minimum payment and channels, or nil when it sets neither.
+func preferencesFilter(prefs *models.ClientPreferences) *view {
+	if prefs.MinPaymentDrops <= 0 && len(prefs.Channels) == 0 {
+		return nil
+	}
+	return newView("", models.View{MinPaymentDrops: prefs.MinPaymentDrops, Channels: prefs.Channels})
+}
+
+// inXRP returns a copy of tx with its drop amount and fee in XRP. Issued
+// currency amounts are left as they are. Only transactions, and the
+// summaries made from them, are converted; event payloads keep their
+// _drops fields.
+func inXRP(tx *models.Transaction) *models.Transaction {
+	converted := *tx
+	if xrp, ok := dropsToXRP(tx.Amount); ok {
+		converted.Amount = xrp
+	}
+	if xrp, ok := dropsToXRP(tx.Fee); ok {
+		converted.Fee = xrp
+	}
+	return &converted
+}
+
+// dropsToXRP formats a drops amount as an exact decimal XRP amount, e.g.
+// "1500000" as "1.5". ok is false for anything but a drops integer.
+func dropsToXRP(drops string) (string, bool) {
+	value, err := strconv.ParseUint(drops, 10, 64)
+	if err != nil {
+		return "", false
+	}
+	whole := strconv.FormatUint(value/dropsPerXRP, 10)
+	fraction := strings.TrimRight(strconv.FormatUint(value%dropsPerXRP+dropsPerXRP, 10)[1:], "0")
+	if fraction == "" {
+		return whole, true
+	}
+	return whole + "." + fraction, true
+}
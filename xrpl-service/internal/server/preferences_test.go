@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/preferences"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestPreferencesRoundTrip(t *testing.T) {
+	srv := newTestServer()
+	srv.apiKeys = map[string]string{"k1": "kiosk"}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/preferences", srv.handleGetPreferences)
+	router.PUT("/preferences", srv.handlePutPreferences)
+
+	request := func(method, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/preferences", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := request(http.MethodGet, "k1", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a preferences store, got %d", rec.Code)
+	}
+
+	store, err := preferences.Load(filepath.Join(t.TempDir(), "client-preferences.json"), nil, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	srv.preferences = store
+
+	if rec := request(http.MethodGet, "", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without an API key, got %d", rec.Code)
+	}
+	if rec := request(http.MethodGet, "nope", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an unknown API key, got %d", rec.Code)
+	}
+	if rec := request(http.MethodPut, "k1", `{"units":"sats"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown units, got %d", rec.Code)
+	}
+	if rec := request(http.MethodPut, "k1", `{"channels":["transactions","trasactions"]}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown channel, got %d", rec.Code)
+	}
+	if rec := request(http.MethodPut, "k1", `{"topics":["bogus"]}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid topic, got %d", rec.Code)
+	}
+	if rec := request(http.MethodPut, "k1", `{"min_payment_drops":1000000,"channels":["transactions"],"units":"xrp"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec := request(http.MethodGet, "k1", "")
+	var prefs models.ClientPreferences
+	if err := json.Unmarshal(rec.Body.Bytes(), &prefs); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if prefs.Name != "kiosk" || prefs.MinPaymentDrops != 1_000_000 || prefs.Units != models.UnitsXRP || len(prefs.Channels) != 1 {
+		t.Fatalf("unexpected preferences %+v", prefs)
+	}
+}
+
+func TestPreferencesFilterAndUnits(t *testing.T) {
+	filter := preferencesFilter(&models.ClientPreferences{MinPaymentDrops: 1_000_000, Channels: []string{"transactions"}})
+	if filter.allows(&models.Transaction{Amount: "999999"}) || !filter.allows(&models.Transaction{Amount: "1000000"}) {
+		t.Fatal("expected payments filtered by the stored minimum")
+	}
+	if filter.allows(&models.StreamEvent{Type: "server_status"}) {
+		t.Fatal("expected channels outside the stored list filtered")
+	}
+	if preferencesFilter(&models.ClientPreferences{Units: models.UnitsXRP}) != nil {
+		t.Fatal("expected no filter without a minimum or channels")
+	}
+
+	for drops, want := range map[string]string{"1500000": "1.5", "12": "0.000012", "2000000": "2", "0": "0"} {
+		if got, ok := dropsToXRP(drops); !ok || got != want {
+			t.Errorf("dropsToXRP(%s) = %q, want %q", drops, got, want)
+		}
+	}
+	if _, ok := dropsToXRP(`{"currency":"USD","value":"1"}`); ok {
+		t.Fatal("expected issued currency amounts left alone")
+	}
+
+	client := &WSClient{server: newTestServer(), units: models.UnitsXRP}
+	tx := &models.Transaction{Hash: "ABC", Amount: "2500000", Fee: "12"}
+	data, ok := client.encode(tx)
+	if !ok {
+		t.Fatal("expected the transaction encoded")
+	}
+	var decoded models.Transaction
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if decoded.Amount != "2.5" || decoded.Fee != "0.000012" || tx.Amount != "2500000" {
+		t.Fatalf("expected an XRP copy of the transaction, got %s", data)
+	}
+
+	// A transaction downgraded to a summary over the bandwidth budget keeps
+	// the client's units.
+	client.server.bandwidthExceededAction = BandwidthActionSummary
+	summaryData, _ := json.Marshal(summarizeTransaction(inXRP(tx)))
+	client.budget = newBandwidthLimiter(len(data) + len(summaryData))
+	client.encode(tx)
+	data, ok = client.encode(tx)
+	if !ok {
+		t.Fatal("expected the summary encoded")
+	}
+	var summary models.TransactionSummary
+	if err := json.Unmarshal(data, &summary); err != nil || !summary.Summary || summary.Amount != "2.5" {
+		t.Fatalf("expected an XRP summary, got %s", data)
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Validator set presets accepted by ?set=.
+const (
+	validatorSetAll    = "all"
+	validatorSetActive = "active"
+)
+
+// validatorPreset is the validator selection of the ?unl= and ?set= query
+// parameters. A nil preset selects every validator.
+type validatorPreset struct {
+	unl        string // preset name, empty for any publisher
+	site       string // validator list site of unl
+	activeOnly bool
+}
+
+// requestValidatorPreset resolves the ?unl= and ?set= parameters of c. It
+// writes a 400 and returns false for an unknown preset.
+func (s *Server) requestValidatorPreset(c *gin.Context) (*validatorPreset, bool) {
+	unl := strings.ToLower(strings.TrimSpace(c.Query("unl")))
+	set := strings.ToLower(strings.TrimSpace(c.Query("set")))
+	preset := &validatorPreset{}
+	if unl != "" {
+		site, ok := s.unlPresets[unl]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown unl preset", "unl_presets": s.unlPresetNames()})
+			return nil, false
+		}
+		preset.unl, preset.site = unl, site
+	}
+	switch set {
+	case "", validatorSetAll:
+	case validatorSetActive:
+		preset.activeOnly = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "set must be all or active"})
+		return nil, false
+	}
+	if preset.unl == "" && !preset.activeOnly {
+		return nil, true
+	}
+	return preset, true
+}
+
+// unlPresetNames returns the configured preset names, sorted.
+func (s *Server) unlPresetNames() []string {
+	names := make([]string, 0, len(s.unlPresets))
+	for name := range s.unlPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// kind namespaces a cached representation or ETag kind by preset.
+func (p *validatorPreset) kind(kind string) string {
+	if p == nil {
+		return kind
+	}
+	if p.unl != "" {
+		kind += "-unl-" + p.unl
+	}
+	if p.activeOnly {
+		kind += "-active"
+	}
+	return kind
+}
+
+// filterValidators returns the validators the preset selects: those whose
+// Publishers include the preset's site and, for set=active, that are active.
+func (p *validatorPreset) filterValidators(validators []*models.Validator) []*models.Validator {
+	if p == nil {
+		return validators
+	}
+	filtered := make([]*models.Validator, 0, len(validators))
+	for _, validator := range validators {
+		if validator == nil {
+			continue
+		}
+		if p.activeOnly && !validator.IsActive {
+			continue
+		}
+		if p.site != "" && !containsPublisher(validator.Publishers, p.site) {
+			continue
+		}
+		filtered = append(filtered, validator)
+	}
+	return filtered
+}
+
+func containsPublisher(publishers []string, site string) bool {
+	for _, publisher := range publishers {
+		if publisher == site {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/xrpltest"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidatorPresetsFilterValidators(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{
+		{Address: "nA1", IsActive: true, Publishers: []string{"https://vl.ripple.com", "https://unl.xrplf.org"}},
+		{Address: "nA2", IsActive: false, Publishers: []string{"https://vl.ripple.com"}},
+		{Address: "nA3", IsActive: true},
+	}}
+	srv.unlPresets = map[string]string{"dunl": "https://vl.ripple.com", "xrplf": "https://unl.xrplf.org"}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validators", srv.handleGetValidators)
+	router.GET("/validators.geojson", srv.handleGetValidatorsGeoJSON)
+
+	get := func(target string) (*httptest.ResponseRecorder, []string) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		var payload struct {
+			Validators []models.Validator `json:"validators"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &payload)
+		var addresses []string
+		for _, v := range payload.Validators {
+			addresses = append(addresses, v.Address)
+		}
+		return rec, addresses
+	}
+
+	all, addresses := get("/validators?set=all")
+	if len(addresses) != 3 {
+		t.Fatalf("expected every validator for set=all, got %v", addresses)
+	}
+	dunl, addresses := get("/validators?unl=dunl")
+	if dunl.Code != http.StatusOK || len(addresses) != 2 || addresses[0] != "nA1" || addresses[1] != "nA2" {
+		t.Fatalf("expected the dUNL members, got %d %v", dunl.Code, addresses)
+	}
+	if dunl.Header().Get("ETag") == all.Header().Get("ETag") {
+		t.Fatal("expected the preset to have its own ETag")
+	}
+	if _, addresses := get("/validators?unl=dunl&set=active"); len(addresses) != 1 || addresses[0] != "nA1" {
+		t.Fatalf("expected the active dUNL members, got %v", addresses)
+	}
+	if _, addresses := get("/validators?unl=XRPLF"); len(addresses) != 1 || addresses[0] != "nA1" {
+		t.Fatalf("expected the XRPLF members, got %v", addresses)
+	}
+	if rec, _ := get("/validators?unl=other"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown preset, got %d", rec.Code)
+	}
+	if rec, _ := get("/validators?set=some"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown set, got %d", rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators.geojson?set=active", nil))
+	var collection struct {
+		Features []json.RawMessage `json:"features"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &collection); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("expected a feature collection, got %d %v", rec.Code, err)
+	}
+}
+
+func TestValidatorPresetFollowsPublisherChanges(t *testing.T) {
+	node := xrpltest.NewServer()
+	defer node.Close()
+	site := httptest.NewServer(http.NotFoundHandler())
+	defer site.Close()
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer registry.Close()
+	members := []string{"nHBidG3pZK11zQD6kpNDoAhDxH6WLGui6ZxSbUx7LSqLHsgzMPec"}
+	unl := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entries := make([]map[string]interface{}, 0, len(members))
+		for _, key := range members {
+			entries = append(entries, map[string]interface{}{"validation_public_key": key})
+		}
+		blob, _ := json.Marshal(map[string]interface{}{"sequence": 1, "validators": entries})
+		json.NewEncoder(w).Encode(map[string]interface{}{"blob": base64.StdEncoding.EncodeToString(blob)})
+	}))
+	defer unl.Close()
+
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	cachePath := filepath.Join(t.TempDir(), "validator-metadata-cache.json")
+	fetcher := validator.NewFetcher(xrpl.NewClient(node.URL(), "", nil), time.Minute, nil, []string{site.URL}, registry.URL, cachePath, nil, 1, "mainnet", nil, validator.FetcherOptions{
+		Clock:            fake,
+		AttributionSites: []string{unl.URL},
+	})
+	srv := newTestServer()
+	srv.validatorFetcher = fetcher
+	srv.unlPresets = map[string]string{"dunl": unl.URL}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validators", srv.handleGetValidators)
+
+	count := func() int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators?unl=dunl", nil))
+		var payload struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("expected the preset, got %d %v", rec.Code, err)
+		}
+		return payload.Count
+	}
+
+	if err := fetcher.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if got := count(); got != 1 {
+		t.Fatalf("expected 1 dUNL member, got %d", got)
+	}
+
+	// Only the list membership changes between the fetches.
+	members = append(members, "nHUon2tpyJEHHYGmxqeGu37cvPYHzrMtUNQFVdCgGNvEkjmCpTqK")
+	fake.Advance(24 * time.Hour)
+	if err := fetcher.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if got := count(); got != 2 {
+		t.Fatalf("expected the preset to follow the new list, got %d members", got)
+	}
+}
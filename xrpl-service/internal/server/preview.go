@@ -0,0 +1,95 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/transaction"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// PreviewSource previews payments before they are validated. It is
+// implemented by transaction.Listener; replicas relay validated
+// transactions only.
+type PreviewSource interface {
+	AddProvisionalCallback(callback transaction.ProvisionalCallback)
+	AddSettlementCallback(callback transaction.SettlementCallback)
+}
+
+// onProvisionalTransaction pushes a previewed payment to the clients that
+// asked for provisional transactions. It is not kept in the recent buffer,
+// which only holds validated transactions.
+func (s *Server) onProvisionalTransaction(tx *models.Transaction) {
+	if s.stopped.Load() || tx == nil {
+		return
+	}
+	tx = s.weighTransaction(tx)
+	if s.privacyMode {
+		tx = anonymizeTransaction(tx)
+	}
+	tx = roundTransaction(tx, s.coordinatePrecision)
+	tx = s.linkTransaction(tx)
+	s.previews.add(tx)
+	select {
+	case s.broadcast <- tx:
+	default:
+		s.logger.Warn("Broadcast channel full, dropping provisional transaction")
+	}
+}
+
+// onTxSettlement pushes the outcome of a previewed payment, scoped to the
+// previewed transaction so views filter it the same way.
+func (s *Server) onTxSettlement(settlement *models.TxSettlement) {
+	if settlement == nil {
+		return
+	}
+	s.broadcastEvent(&models.StreamEvent{
+		Type:        "tx_settlement",
+		Timestamp:   s.clock.Now().Unix(),
+		Data:        settlement,
+		Transaction: s.previews.settle(settlement.Hash),
+	})
+}
+
+// pendingPreviews remembers the provisional transactions pushed to clients
+// until they settle. The listener settles or expires every payment it
+// previews, so at most maxPendingPreviews are held.
+type pendingPreviews struct {
+	mu           sync.Mutex
+	transactions map[string]*models.Transaction
+}
+
+// maxPendingPreviews matches the listener's bound on unsettled previews.
+const maxPendingPreviews = 4096
+
+func (p *pendingPreviews) add(tx *models.Transaction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.transactions == nil {
+		p.transactions = make(map[string]*models.Transaction)
+	}
+	if len(p.transactions) < maxPendingPreviews {
+		p.transactions[tx.Hash] = tx
+	}
+}
+
+// settle forgets the transaction previewed as hash and returns it, or nil.
+func (p *pendingPreviews) settle(hash string) *models.Transaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	tx := p.transactions[hash]
+	delete(p.transactions, hash)
+	return tx
+}
+
+// isProvisionalMessage reports whether msg is a provisional transaction or
+// its settlement, which only reach clients connected with ?provisional=true.
+func isProvisionalMessage(msg interface{}) bool {
+	switch typed := msg.(type) {
+	case *models.Transaction:
+		return typed.Provisional
+	case *models.StreamEvent:
+		_, ok := typed.Data.(*models.TxSettlement)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestBroadcastLoopSendsProvisionalMessagesOnlyOnRequest(t *testing.T) {
+	srv := newTestServer()
+	preview := &WSClient{send: make(chan interface{}, 4), server: srv, provisional: true}
+	plain := &WSClient{send: make(chan interface{}, 4), server: srv}
+	srv.wsClients[preview] = true
+	srv.wsClients[plain] = true
+
+	go srv.broadcastLoop()
+	defer close(srv.stopBroadcast)
+
+	srv.onProvisionalTransaction(&models.Transaction{Hash: "P1", Provisional: true})
+	srv.onTxSettlement(&models.TxSettlement{Hash: "P1", Status: models.SettlementConfirmed})
+	srv.onTransaction(&models.Transaction{Hash: "P1", Validated: true})
+
+	deadline := time.After(time.Second)
+	for len(preview.send) < 3 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected the preview client to receive 3 messages, got %d", len(preview.send))
+		case <-time.After(time.Millisecond):
+		}
+	}
+	if len(plain.send) != 1 {
+		t.Fatalf("expected the plain client to receive only the validated transaction, got %d messages", len(plain.send))
+	}
+	if tx, ok := (<-plain.send).(*models.Transaction); !ok || tx.Provisional {
+		t.Fatalf("expected a validated transaction, got %+v", tx)
+	}
+	if event, ok := (<-preview.send).(*models.Transaction); !ok || !event.Provisional {
+		t.Fatalf("expected the provisional transaction first, got %+v", event)
+	}
+	if event, ok := (<-preview.send).(*models.StreamEvent); !ok || event.Type != "tx_settlement" {
+		t.Fatalf("expected a tx_settlement event, got %+v", event)
+	}
+	if got := srv.recent.snapshot(8); len(got) != 1 || got[0].Provisional {
+		t.Fatalf("expected only the validated transaction in the recent buffer, got %+v", got)
+	}
+}
+
+func TestTransactionsWebSocketRejectsInvalidProvisional(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/transactions", srv.handleTransactionsWebSocket)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/transactions?provisional=maybe", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for provisional=maybe, got %d", rec.Code)
+	}
+}
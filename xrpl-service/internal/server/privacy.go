@@ -0,0 +1,121 @@
+package server
+
+import (
+	"math"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+const (
+	// privacyAddressPrefix is how many leading characters of an account
+	// address survive truncation.
+	privacyAddressPrefix = 6
+
+	// privacyGridDegrees is the coordinate grid of privacy mode. Half a
+	// degree of latitude is about 55km.
+	privacyGridDegrees = 0.5
+)
+
+// truncateAddress keeps the first characters of an account address, enough
+// to tell accounts apart on screen but not to look them up.
+func truncateAddress(address string) string {
+	if len(address) <= privacyAddressPrefix {
+		return address
+	}
+	return address[:privacyAddressPrefix] + "..."
+}
+
+// roundCoordinate snaps a latitude or longitude to the privacy grid.
+func roundCoordinate(value float64) float64 {
+	return math.Round(value/privacyGridDegrees) * privacyGridDegrees
+}
+
+// anonymizeLocations returns copies of locations snapped to the privacy grid.
+// Account locations carry the resolved account, which is truncated.
+func anonymizeLocations(locations []*models.GeoLocation) []*models.GeoLocation {
+	if locations == nil {
+		return nil
+	}
+	out := make([]*models.GeoLocation, 0, len(locations))
+	for _, loc := range locations {
+		if loc == nil {
+			continue
+		}
+		copy := *loc
+		copy.Latitude = roundCoordinate(loc.Latitude)
+		copy.Longitude = roundCoordinate(loc.Longitude)
+		copy.ValidatorAddress = truncateAddress(loc.ValidatorAddress)
+		out = append(out, &copy)
+	}
+	return out
+}
+
+// anonymizeTransaction returns a copy of tx with truncated accounts, snapped
+// locations and no processor tags. tx itself is shared with other callbacks
+// and is not modified.
+func anonymizeTransaction(tx *models.Transaction) *models.Transaction {
+	copy := *tx
+	copy.Account = truncateAddress(tx.Account)
+	copy.Destination = truncateAddress(tx.Destination)
+	if tx.CreatedAccounts != nil {
+		copy.CreatedAccounts = make([]string, len(tx.CreatedAccounts))
+		for i, account := range tx.CreatedAccounts {
+			copy.CreatedAccounts[i] = truncateAddress(account)
+		}
+	}
+	copy.Locations = anonymizeLocations(tx.Locations)
+	copy.Tags = nil
+	copy.GeoCandidates = nil
+	return &copy
+}
+
+// anonymizeValidators returns copies of validators snapped to the privacy
+// grid. Validator addresses are public keys published in validator lists
+// and are kept.
+func anonymizeValidators(validators []*models.Validator) []*models.Validator {
+	out := make([]*models.Validator, 0, len(validators))
+	for _, v := range validators {
+		if v == nil {
+			continue
+		}
+		copy := *v
+		copy.Latitude = roundCoordinate(v.Latitude)
+		copy.Longitude = roundCoordinate(v.Longitude)
+		out = append(out, &copy)
+	}
+	return out
+}
+
+// anonymizeValidatorDelta snaps changed coordinates in an upsert delta.
+func anonymizeValidatorDelta(delta *models.ValidatorDelta) *models.ValidatorDelta {
+	if delta == nil || delta.Fields == nil {
+		return delta
+	}
+	fields := make(map[string]interface{}, len(delta.Fields))
+	for key, value := range delta.Fields {
+		if coordinate, ok := value.(float64); ok && (key == "latitude" || key == "longitude") {
+			value = roundCoordinate(coordinate)
+		}
+		fields[key] = value
+	}
+	return &models.ValidatorDelta{Address: delta.Address, Fields: fields}
+}
+
+// anonymizePeers returns a copy of summary without peer IPs and with
+// locations snapped to the privacy grid.
+func anonymizePeers(summary *models.PeerSummary) *models.PeerSummary {
+	copy := *summary
+	copy.Peers = make([]*models.PeerInfo, 0, len(summary.Peers))
+	for _, peer := range summary.Peers {
+		if peer == nil {
+			continue
+		}
+		peerCopy := *peer
+		peerCopy.IP = ""
+		if peer.Location != nil {
+			peerCopy.Location = anonymizeLocations([]*models.GeoLocation{peer.Location})[0]
+		}
+		copy.Peers = append(copy.Peers, &peerCopy)
+	}
+	return &copy
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestPrivacyModeAnonymizesTransactions(t *testing.T) {
+	srv := newTestServer()
+	srv.privacyMode = true
+
+	tx := &models.Transaction{
+		Hash:            "ABC",
+		Account:         "rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY",
+		Destination:     "rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn",
+		TransactionType: "Payment",
+		Locations:       []*models.GeoLocation{{Latitude: 48.85, Longitude: 2.35, CountryCode: "FR", ValidatorAddress: "rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY", Role: models.LocationRoleSource}},
+		Tags:            map[string]string{"source_label": "exchange"},
+	}
+	srv.onTransaction(tx)
+
+	got := (<-srv.broadcast).(*models.Transaction)
+	if got.Account != "rPEPPE..." || got.Destination != "rf1BiG..." {
+		t.Fatalf("expected truncated accounts, got %q %q", got.Account, got.Destination)
+	}
+	if got.Locations[0].Latitude != 49 || got.Locations[0].Longitude != 2.5 || got.Locations[0].CountryCode != "FR" || got.Locations[0].ValidatorAddress != "rPEPPE..." {
+		t.Fatalf("expected coordinates snapped to the grid, got %+v", got.Locations[0])
+	}
+	if got.Tags != nil {
+		t.Fatalf("expected tags to be dropped, got %v", got.Tags)
+	}
+	if tx.Account != "rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY" || tx.Locations[0].Latitude != 48.85 || tx.Tags == nil {
+		t.Fatal("expected the shared transaction to be left untouched")
+	}
+	if recent := srv.recent.snapshot(1); recent[0].Account != "rPEPPE..." {
+		t.Fatalf("expected the recent ring to hold the anonymized copy, got %q", recent[0].Account)
+	}
+
+	srv.onTxGeoUpdate(&models.TxGeoUpdate{Hash: "ABC", Locations: []*models.GeoLocation{{Latitude: -33.87, Longitude: 151.21}}})
+	event := (<-srv.broadcast).(*models.StreamEvent)
+	update := event.Data.(*models.TxGeoUpdate)
+	if update.Locations[0].Latitude != -34 || update.Locations[0].Longitude != 151 {
+		t.Fatalf("expected late locations snapped to the grid, got %+v", update.Locations[0])
+	}
+}
+
+func TestPrivacyModeSnapsValidatorCoordinates(t *testing.T) {
+	srv := newTestServer()
+	srv.privacyMode = true
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{
+		{Address: "nA1", Domain: "a.example", Latitude: 40.71, Longitude: -74.01},
+	}}
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/validators", srv.handleGetValidators)
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators", nil))
+	var body struct {
+		Validators []*models.Validator `json:"validators"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(body.Validators) != 1 || body.Validators[0].Latitude != 40.5 || body.Validators[0].Longitude != -74 {
+		t.Fatalf("expected snapped coordinates, got %s", rec.Body.String())
+	}
+	if body.Validators[0].Address != "nA1" {
+		t.Fatalf("expected validator address to be kept, got %q", body.Validators[0].Address)
+	}
+
+	srv.onValidatorUpdate(&models.ValidatorUpdate{Upserts: []*models.ValidatorDelta{
+		{Address: "nA1", Fields: map[string]interface{}{"latitude": 40.71, "domain": "a.example"}},
+	}})
+	delta := (<-srv.broadcast).(*models.StreamEvent).Data.(*models.ValidatorDelta)
+	if delta.Fields["latitude"] != 40.5 || delta.Fields["domain"] != "a.example" {
+		t.Fatalf("expected snapped delta coordinates, got %v", delta.Fields)
+	}
+}
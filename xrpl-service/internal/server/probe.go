@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// ProbeStartup requests /startupz from the listener described by spec and
+// returns an error unless it answers 200. Wildcard hosts such as 0.0.0.0
+// and [::] are probed on loopback. It backs the healthcheck subcommand.
+func ProbeStartup(ctx context.Context, spec string) error {
+	network, address, err := ParseListenSpec(spec)
+	if err != nil {
+		return err
+	}
+	if network != "unix" {
+		address, err = loopbackAddress(address)
+		if err != nil {
+			return err
+		}
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		},
+		DisableKeepAlives: true,
+	}}
+	host := address
+	if network == "unix" {
+		host = "unix"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+host+"/startupz", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("startup probe returned %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// loopbackAddress rewrites an unspecified host in address to the matching
+// loopback address.
+func loopbackAddress(address string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case host == "" || (ip != nil && ip.To4() != nil && ip.IsUnspecified()):
+		host = "127.0.0.1"
+	case ip != nil && ip.IsUnspecified():
+		host = "::1"
+	}
+	return net.JoinHostPort(host, port), nil
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/gin-gonic/gin"
+)
+
+type staticValidators struct {
+	validators []*models.Validator
+	histories  map[string]*models.DomainHistory
+	historyErr error // returned for validators without a history, instead of xrpl.ErrNotFound
+}
+
+func (s *staticValidators) GetValidators() []*models.Validator { return s.validators }
+
+func (s *staticValidators) GetLastUpdate() time.Time { return time.Time{} }
+
+func (s *staticValidators) GetServerStatus(ctx context.Context) (*models.ServerStatus, error) {
+	return nil, nil
+}
+
+func (s *staticValidators) GetDomainHistory(ctx context.Context, address string) (*models.DomainHistory, error) {
+	if history, ok := s.histories[address]; ok {
+		return history, nil
+	}
+	if s.historyErr != nil {
+		return nil, s.historyErr
+	}
+	return nil, xrpl.ErrNotFound
+}
+
+func (s *staticValidators) AddCallback(callback validator.UpdateCallback) {}
+
+func TestStartupzRequiresValidators(t *testing.T) {
+	source := &staticValidators{}
+	srv := newTestServer()
+	srv.validatorFetcher = source
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/startupz", srv.handleStartupz)
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/startupz", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "validator_cache_empty") {
+		t.Fatalf("expected 503 with validator_cache_empty, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	source.validators = []*models.Validator{{Address: "nHB1"}}
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/startupz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 once validators are loaded, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestProbeStartup(t *testing.T) {
+	source := &staticValidators{}
+	socketPath := filepath.Join(t.TempDir(), "xrpl.sock")
+	srv := newTestServer()
+	srv.validatorFetcher = source
+	srv.listenSpecs = []string{"0.0.0.0:0", "unix:" + socketPath}
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/startupz", srv.handleStartupz)
+
+	go srv.Start(context.Background())
+	defer srv.Stop(context.Background())
+	deadline := time.Now().Add(2 * time.Second)
+	for len(srv.ListenAddrs()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	addrs := srv.ListenAddrs()
+	if len(addrs) != 2 {
+		t.Fatalf("expected 2 listeners, got %v", addrs)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	specs := []string{addrs[0].String(), "unix:" + socketPath}
+	for _, spec := range specs {
+		if err := ProbeStartup(ctx, spec); err == nil || !strings.Contains(err.Error(), "503") {
+			t.Fatalf("ProbeStartup(%q) = %v, want 503 error", spec, err)
+		}
+	}
+
+	source.validators = []*models.Validator{{Address: "nHB1"}}
+	for _, spec := range specs {
+		if err := ProbeStartup(ctx, spec); err != nil {
+			t.Fatalf("ProbeStartup(%q) failed: %v", spec, err)
+		}
+	}
+
+	if err := ProbeStartup(ctx, "127.0.0.1:1"); err == nil {
+		t.Fatal("expected probe of a closed port to fail")
+	}
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Public mode defaults, used when ServerOptions leaves them zero.
+const (
+	defaultAdminListenSpec  = "127.0.0.1:8081"
+	defaultPublicRateLimit  = 5.0
+	defaultPublicRateBurst  = 20
+	defaultPublicConnsPerIP = 4
+)
+
+// maxRateLimitedClients bounds the per-IP bucket table.
+const maxRateLimitedClients = 65536
+
+// probePaths are exempt from the public rate limit: load balancer and
+// orchestrator probes share a handful of addresses and poll steadily.
+var probePaths = map[string]bool{
+	"/health":   true,
+	"/readyz":   true,
+	"/startupz": true,
+}
+
+// ipRateLimiter keeps a token bucket per client IP.
+type ipRateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*messageRateLimiter
+}
+
+func newIPRateLimiter(rate float64, burst int) *ipRateLimiter {
+	return &ipRateLimiter{rate: rate, burst: float64(burst), buckets: make(map[string]*messageRateLimiter)}
+}
+
+// allow takes a token from ip's bucket. When the table is full, buckets
+// that have refilled are dropped first, since a fresh bucket behaves the
+// same; if none have, the table starts over rather than grow without bound.
+func (l *ipRateLimiter) allow(ip string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bucket := l.buckets[ip]
+	if bucket == nil {
+		if len(l.buckets) >= maxRateLimitedClients {
+			for key, b := range l.buckets {
+				if b.full(now) {
+					delete(l.buckets, key)
+				}
+			}
+			if len(l.buckets) >= maxRateLimitedClients {
+				clear(l.buckets)
+			}
+		}
+		bucket = &messageRateLimiter{rate: l.rate, burst: l.burst, tokens: l.burst}
+		l.buckets[ip] = bucket
+	}
+	return bucket.allow(now)
+}
+
+// rateLimitPublic rejects clients over the per-IP request rate with 429.
+// Client IPs honor TrustedProxies, so put the proxies in front of a public
+// instance there or every request counts against the proxy.
+func (s *Server) rateLimitPublic(c *gin.Context) {
+	if probePaths[c.Request.URL.Path] || c.Request.Method == http.MethodOptions {
+		c.Next()
+		return
+	}
+	if !s.publicLimiter.allow(c.ClientIP(), s.clock.Now()) {
+		metrics.HTTPRateLimitedTotal.Inc()
+		c.Header("Retry-After", "1")
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+		return
+	}
+	c.Next()
+}
+
+// reserveIPConnection counts a WebSocket connection against its client IP,
+// refusing it over the public mode cap.
+func (s *Server) reserveIPConnection(ip string) bool {
+	if s.maxConnectionsPerIP <= 0 {
+		return true
+	}
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	if s.ipConns[ip] >= s.maxConnectionsPerIP {
+		return false
+	}
+	s.ipConns[ip]++
+	return true
+}
+
+func (s *Server) releaseIPConnection(ip string) {
+	if s.maxConnectionsPerIP <= 0 {
+		return
+	}
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	if s.ipConns[ip] <= 1 {
+		delete(s.ipConns, ip)
+		return
+	}
+	s.ipConns[ip]--
+}
+
+// registerAdminRoutes sets up the operator endpoints on router: the main
+// router normally, the admin listener's in public mode.
+func (s *Server) registerAdminRoutes(router *gin.Engine) {
+	if s.adminToken == "" {
+		return
+	}
+	admin := router.Group("/admin", s.requireAdmin)
+	admin.GET("/bandwidth", s.handleAdminBandwidth)
+	admin.GET("/fetch-status", s.handleAdminFetchStatus)
+	admin.GET("/watchlist", s.handleAdminWatchlist)
+	admin.PUT("/watchlist", s.handleAdminSetWatchlist)
+	admin.GET("/ingestion", s.handleAdminIngestion)
+	admin.PUT("/ingestion", s.handleAdminSetIngestion)
+	admin.POST("/filters/preview", s.handleAdminFilterPreview)
+	admin.GET("/validators/:address/notes", s.handleAdminValidatorNotes)
+	admin.POST("/validators/:address/notes", s.handleAdminAddValidatorNote)
+	admin.GET("/metadata-audit", s.handleAdminMetadataAudit)
+	admin.GET("/validator-list-publishers", s.handleAdminValidatorListPublishers)
+
+	// Account labels are submitted and reviewed by operators
+	router.GET("/labels", s.requireAdmin, s.handleListLabels)
+	router.POST("/labels", s.requireAdmin, s.handlePostLabel)
+}
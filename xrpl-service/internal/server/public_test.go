@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func newPublicTestServer(opts ServerOptions) *Server {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	opts.PublicMode = true
+	if opts.AdminListenSpec == "" {
+		opts.AdminListenSpec = "127.0.0.1:0"
+	}
+	return NewServer(&staticValidators{}, &upstreamListener{}, "127.0.0.1", 0, []string{"http://localhost:3000"}, 16, 16, logger, opts)
+}
+
+func TestPublicModeMovesOperatorEndpoints(t *testing.T) {
+	srv := newPublicTestServer(ServerOptions{AdminToken: "secret", DevMode: true, InstanceID: "edge-1"})
+	defer srv.Stop(context.Background())
+
+	get := func(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	for _, route := range [][2]string{
+		{http.MethodGet, "/metrics"},
+		{http.MethodGet, "/admin/bandwidth"},
+		{http.MethodGet, "/labels"},
+		{http.MethodPost, "/dev/inject"},
+	} {
+		if rec := get(srv.router, route[0], route[1]); rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s: expected 404 on the public router, got %d", route[0], route[1], rec.Code)
+		}
+	}
+	for _, path := range []string{"/metrics", "/admin/bandwidth"} {
+		if rec := get(srv.adminRouter, http.MethodGet, path); rec.Code != http.StatusOK {
+			t.Errorf("%s: expected 200 on the admin router, got %d", path, rec.Code)
+		}
+	}
+
+	for _, path := range []string{"/health", "/version"} {
+		rec := get(srv.router, http.MethodGet, path)
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", path, err)
+		}
+		if _, ok := body["instance_id"]; ok {
+			t.Errorf("%s: expected no instance_id in public mode, got %v", path, body)
+		}
+	}
+
+	rec := get(srv.router, http.MethodGet, "/validators")
+	if rec.Header().Get(headerDataSource) != "" {
+		t.Fatalf("expected no %s header in public mode", headerDataSource)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode validators: %v", err)
+	}
+	if _, ok := body["data_source"]; ok {
+		t.Fatalf("expected no data_source in public mode, got %v", body)
+	}
+}
+
+func TestPublicModeRateLimitsPerIP(t *testing.T) {
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	srv := newPublicTestServer(ServerOptions{Clock: fake, PublicRateLimit: 1, PublicRateBurst: 2})
+	defer srv.Stop(context.Background())
+
+	get := func(path, remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	for i := 0; i < 2; i++ {
+		if code := get("/version", "203.0.113.7:4000"); code != http.StatusOK {
+			t.Fatalf("request %d: expected 200 within the burst, got %d", i, code)
+		}
+	}
+	if code := get("/version", "203.0.113.7:4001"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 over the burst, got %d", code)
+	}
+	if code := get("/health", "203.0.113.7:4002"); code != http.StatusOK {
+		t.Fatalf("expected probes exempt from the limit, got %d", code)
+	}
+	if code := get("/version", "198.51.100.9:4000"); code != http.StatusOK {
+		t.Fatalf("expected another IP to have its own bucket, got %d", code)
+	}
+	fake.Advance(time.Second)
+	if code := get("/version", "203.0.113.7:4003"); code != http.StatusOK {
+		t.Fatalf("expected a token back after a second, got %d", code)
+	}
+}
+
+func TestIPRateLimiterBoundsBuckets(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := newIPRateLimiter(1, 1)
+	for i := 0; i < maxRateLimitedClients; i++ {
+		l.buckets[strconv.Itoa(i)] = &messageRateLimiter{rate: 1, burst: 1, last: now}
+	}
+	l.buckets["refilled"] = &messageRateLimiter{rate: 1, burst: 1, last: now.Add(-time.Minute)}
+	delete(l.buckets, "0")
+
+	if !l.allow("new", now) {
+		t.Fatal("expected a new client to be allowed")
+	}
+	if _, ok := l.buckets["refilled"]; ok {
+		t.Fatal("expected the refilled bucket pruned")
+	}
+	if len(l.buckets) != maxRateLimitedClients {
+		t.Fatalf("expected %d buckets, got %d", maxRateLimitedClients, len(l.buckets))
+	}
+}
+
+func TestPublicModeCapsWebSocketConnectionsPerIP(t *testing.T) {
+	srv := newTestServer()
+	srv.ipConns = make(map[string]int)
+	srv.maxConnectionsPerIP = 2
+
+	if !srv.reserveIPConnection("203.0.113.7") || !srv.reserveIPConnection("203.0.113.7") {
+		t.Fatal("expected connections under the cap reserved")
+	}
+	if srv.reserveIPConnection("203.0.113.7") {
+		t.Fatal("expected a connection over the cap refused")
+	}
+	if !srv.reserveIPConnection("198.51.100.9") {
+		t.Fatal("expected another IP counted separately")
+	}
+	srv.releaseIPConnection("203.0.113.7")
+	if !srv.reserveIPConnection("203.0.113.7") {
+		t.Fatal("expected a released slot reused")
+	}
+}
+
+func TestPublicModeServesAdminListener(t *testing.T) {
+	srv := newPublicTestServer(ServerOptions{})
+	srv.listenSpecs = []string{"127.0.0.1:0"}
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Start(context.Background()) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.AdminListenAddr() == nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	admin := srv.AdminListenAddr()
+	if admin == nil {
+		t.Fatal("expected the admin listener to open")
+	}
+	resp, err := http.Get("http://" + admin.String() + "/metrics")
+	if err != nil {
+		t.Fatalf("admin request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected /metrics on the admin listener, got %d", resp.StatusCode)
+	}
+
+	if err := srv.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if err := <-done; err != http.ErrServerClosed {
+		t.Fatalf("expected ErrServerClosed from Start, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// PublisherSource tracks the keys validator list sites publish under. It is
+// implemented by validator.Fetcher; replicas do not fetch lists.
+type PublisherSource interface {
+	ValidatorListPublishers(ctx context.Context) []*models.ValidatorListPublisher
+}
+
+// handleAdminValidatorListPublishers returns the accepted publisher keys of
+// every validator list site, their rotations, and any site presenting a key
+// that is not accepted.
+func (s *Server) handleAdminValidatorListPublishers(c *gin.Context) {
+	source, ok := s.validatorFetcher.(PublisherSource)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "validator list publishers are not available"})
+		return
+	}
+	publishers := source.ValidatorListPublishers(c.Request.Context())
+	alerts, expiryAlerts := 0, 0
+	for _, publisher := range publishers {
+		if publisher.Alert != "" {
+			alerts++
+		}
+		if publisher.ListExpiry != "" {
+			expiryAlerts++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"publishers": publishers, "alerts": alerts, "expiry_alerts": expiryAlerts})
+}
+
+// publisherAlerted reports whether any validator list site presents a
+// publisher key that is not accepted, and whether any last published a
+// list that has expired or expires soon.
+func (s *Server) publisherAlerted(ctx context.Context) (keyAlert, expiryAlert bool) {
+	source, ok := s.validatorFetcher.(PublisherSource)
+	if !ok {
+		return false, false
+	}
+	for _, publisher := range source.ValidatorListPublishers(ctx) {
+		keyAlert = keyAlert || publisher.Alert != ""
+		expiryAlert = expiryAlert || publisher.ListExpiry != ""
+	}
+	return keyAlert, expiryAlert
+}
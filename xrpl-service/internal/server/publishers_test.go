@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+type publisherValidators struct {
+	staticValidators
+	publishers []*models.ValidatorListPublisher
+}
+
+func (p *publisherValidators) ValidatorListPublishers(context.Context) []*models.ValidatorListPublisher {
+	return p.publishers
+}
+
+func TestAdminValidatorListPublishers(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &publisherValidators{
+		staticValidators: staticValidators{validators: []*models.Validator{{Address: "nHB1"}}},
+		publishers: []*models.ValidatorListPublisher{
+			{Site: "https://unl.example", MasterKey: "ED01"},
+			{Site: "https://vl.example", MasterKey: "ED02", Alert: models.PublisherAlertUnknownKey, PresentedKey: "ED03"},
+		},
+	}
+	srv.transactionListener = &upstreamListener{status: models.UpstreamStatus{Connected: true, Subscribed: true}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/validator-list-publishers", srv.handleAdminValidatorListPublishers)
+	router.GET("/health", srv.handleHealth)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/validator-list-publishers", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Publishers []*models.ValidatorListPublisher `json:"publishers"`
+		Alerts     int                              `json:"alerts"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if len(body.Publishers) != 2 || body.Alerts != 1 || body.Publishers[1].PresentedKey != "ED03" {
+		t.Fatalf("unexpected publishers %+v", body)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health struct {
+		Status   string   `json:"status"`
+		Degraded []string `json:"degraded"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("failed to decode health: %v", err)
+	}
+	if health.Status != "degraded" || len(health.Degraded) != 1 || health.Degraded[0] != degradedPublisherKeyAlert {
+		t.Fatalf("expected a publisher alert in /health, got %+v", health)
+	}
+
+	srv.validatorFetcher = &staticValidators{}
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/validator-list-publishers", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without publisher tracking, got %d", rec.Code)
+	}
+}
+
+func TestHealthReportsValidatorListExpiry(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &publisherValidators{
+		staticValidators: staticValidators{validators: []*models.Validator{{Address: "nHB1"}}},
+		publishers: []*models.ValidatorListPublisher{
+			{Site: "https://vl.example", MasterKey: "ED02", ListSequence: 80, ListExpiration: 1710000000, ListExpiry: models.ListExpiryExpiring},
+		},
+	}
+	srv.transactionListener = &upstreamListener{status: models.UpstreamStatus{Connected: true, Subscribed: true}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/validator-list-publishers", srv.handleAdminValidatorListPublishers)
+	router.GET("/health", srv.handleHealth)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/validator-list-publishers", nil))
+	var body struct {
+		Alerts       int `json:"alerts"`
+		ExpiryAlerts int `json:"expiry_alerts"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.Alerts != 0 || body.ExpiryAlerts != 1 {
+		t.Fatalf("expected one expiry alert and no key alert, got %+v", body)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health struct {
+		Degraded []string `json:"degraded"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("failed to decode health: %v", err)
+	}
+	if len(health.Degraded) != 1 || health.Degraded[0] != degradedListExpiry {
+		t.Fatalf("expected a list expiry in /health, got %+v", health)
+	}
+}
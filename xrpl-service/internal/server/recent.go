@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// recentTransactionsSize is how many broadcast transactions are kept for
+// /transactions/recent and /transactions/recent.geojson.
+const recentTransactionsSize = 500
+
+// recentTransactions is a fixed-size ring of the latest broadcast
+// transactions.
+type recentTransactions struct {
+	mu    sync.Mutex
+	items []*models.Transaction
+	next  int
+	full  bool
+}
+
+func newRecentTransactions(size int) *recentTransactions {
+	return &recentTransactions{items: make([]*models.Transaction, size)}
+}
+
+// add keeps a shallow copy of tx, since late geolocation enrichment sets
+// Locations on the original after broadcast.
+func (r *recentTransactions) add(tx *models.Transaction) {
+	kept := *tx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[r.next] = &kept
+	r.next = (r.next + 1) % len(r.items)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// updateLocations replaces the locations of a kept transaction once they
+// are resolved after broadcast. Entries handed out by snapshot may still be
+// in use, so the entry is replaced rather than modified.
+func (r *recentTransactions) updateLocations(hash string, locations []*models.GeoLocation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, tx := range r.items {
+		if tx != nil && tx.Hash == hash {
+			updated := *tx
+			updated.Locations = locations
+			r.items[i] = &updated
+			return
+		}
+	}
+}
+
+// snapshot returns up to limit transactions, newest first.
+func (r *recentTransactions) snapshot(limit int) []*models.Transaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := r.next
+	if r.full {
+		count = len(r.items)
+	}
+	if limit <= 0 || limit > count {
+		limit = count
+	}
+	out := make([]*models.Transaction, 0, limit)
+	for i := 1; i <= limit; i++ {
+		out = append(out, r.items[(r.next-i+len(r.items))%len(r.items)])
+	}
+	return out
+}
+
+// recentLimit parses ?limit= for the recent transaction endpoints, writing
+// a 400 response when it is out of range.
+func recentLimit(c *gin.Context) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return defaultRecentLimit, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 || limit > recentTransactionsSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", recentTransactionsSize)})
+		return 0, false
+	}
+	return limit, true
+}
+
+// handleRecentTransactions returns recently broadcast transactions, newest
+// first. ?limit= caps how many are returned and ?fields= keeps only the
+// listed fields of each.
+func (s *Server) handleRecentTransactions(c *gin.Context) {
+	limit, ok := recentLimit(c)
+	if !ok {
+		return
+	}
+	mask, err := parseFieldMask[models.Transaction](c.Query("fields"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transactions := s.recentFor(requestView(c), limit)
+	body, err := marshalMasked(transactions, mask)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.JSON(http.StatusOK, gin.H{
+		"transactions": json.RawMessage(body),
+		"count":        len(transactions),
+	})
+}
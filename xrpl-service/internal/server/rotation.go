@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/gin-gonic/gin"
+)
+
+// KeyRotationSource tracks validator signing key rotations. It is
+// implemented by validator.Fetcher; replicas do not track rotations.
+type KeyRotationSource interface {
+	GetKeyHistory(ctx context.Context, key string) (*models.KeyHistory, error)
+	AddRotationCallback(callback validator.RotationCallback)
+}
+
+// handleValidatorKeyHistory returns the recorded signing key rotations of
+// one validator, looked up by its master key or any signing key it has used.
+func (s *Server) handleValidatorKeyHistory(c *gin.Context) {
+	source, ok := s.validatorFetcher.(KeyRotationSource)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "key rotation tracking is not available"})
+		return
+	}
+	history, err := source.GetKeyHistory(c.Request.Context(), c.Param("address"))
+	if errors.Is(err, xrpl.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "validator not found"})
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to fetch validator key history")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// onKeyRotation pushes a validator_rotated event, so clients keep the
+// validator's marker and relabel it instead of removing and adding it.
+func (s *Server) onKeyRotation(rotation *models.KeyRotation) {
+	if rotation == nil {
+		return
+	}
+	s.broadcastEvent(&models.StreamEvent{
+		Type:      "validator_rotated",
+		Timestamp: s.clock.Now().Unix(),
+		Data:      rotation,
+		Countries: s.validatorCountry(rotation.Address),
+	})
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidatorKeyHistoryWithoutRotationTracking(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validators/:address/key-history", srv.handleValidatorKeyHistory)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators/nA1/key-history", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestKeyRotationEvent(t *testing.T) {
+	srv := newTestServer()
+	srv.onKeyRotation(&models.KeyRotation{Address: "nA1", OldSigningKey: "n9Old", NewSigningKey: "n9New", OldSequence: 1, NewSequence: 2})
+
+	event := (<-srv.broadcast).(*models.StreamEvent)
+	rotation, ok := event.Data.(*models.KeyRotation)
+	if event.Type != "validator_rotated" || !ok || rotation.NewSigningKey != "n9New" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+}
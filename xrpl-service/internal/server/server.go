@@ -2,15 +2,33 @@ package server
 
 import (
 	"context"
-	"fmt"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/brandon/xrpl-validator-service/internal/models"
-	"github.com/brandon/xrpl-validator-service/internal/transaction"
-	"github.com/brandon/xrpl-validator-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/buildinfo"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/compliance"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/decentralization"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/explorer"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/health"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/ingestion"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/issuers"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/labels"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/peers"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/preferences"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/stats"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/transaction"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
@@ -20,91 +38,490 @@ const networkHealthStaleTTL = 15 * time.Minute
 
 // Server manages HTTP and WebSocket connections
 type Server struct {
-	router              *gin.Engine
-	logger              *logrus.Logger
-	validatorFetcher    *validator.Fetcher
-	transactionListener *transaction.Listener
-	listenAddr          string
-	listenPort          int
-	corsAllowedOrigins  []string
-	httpServer          *http.Server
-	wsUpgrader          websocket.Upgrader
-	wsClients           map[*WSClient]bool
-	wsMu                sync.RWMutex
-	broadcast           chan *models.Transaction
-	wsClientBufferSize  int
-	networkHealthMu     sync.RWMutex
-	lastNetworkHealth   *models.ServerStatus
-	lastNetworkHealthAt time.Time
-	stopBroadcast       chan struct{}
-	stopOnce            sync.Once
-	stopped             atomic.Bool
+	router                  *gin.Engine
+	logger                  *logrus.Logger
+	validatorFetcher        ValidatorSource
+	transactionListener     TransactionSource
+	listenAddr              string
+	listenPort              int
+	listenSpecs             []string
+	listenersMu             sync.Mutex
+	listeners               []net.Listener
+	corsAllowedOrigins      []string
+	corsMaxAge              time.Duration
+	httpServer              *http.Server
+	wsUpgrader              websocket.Upgrader
+	wsClients               map[*WSClient]bool
+	wsMu                    sync.RWMutex
+	broadcast               chan interface{}
+	wsClientBufferSize      int
+	statusPoller            *health.Poller
+	watchdog                *health.Watchdog
+	anomalyDetector         *health.AnomalyDetector
+	sloMonitor              *health.SLOMonitor
+	peerCollector           *peers.Collector
+	issuerGraphs            *issuers.Collector
+	watchlist               *compliance.Watchlist
+	labels                  *labels.Store
+	preferences             *preferences.Store
+	decentralization        *decentralization.History
+	newAccounts             *stats.NewAccountTracker
+	activity                *stats.Activity
+	burn                    *stats.BurnTracker
+	distributions           *stats.Distributions
+	ingestion               *ingestion.Controller
+	geoDB                   GeoDBInfo
+	responseCache           *responseCache
+	snapshotBodies          snapshotBodies
+	previews                pendingPreviews
+	clock                   clock.Clock
+	build                   buildinfo.Build
+	instanceID              string
+	startedAt               time.Time
+	responseCacheTTL        time.Duration
+	recent                  *recentTransactions
+	originPolicies          map[string]*originPolicy
+	views                   map[string]*view
+	unlPresets              map[string]string
+	originConns             map[string]int
+	apiKeys                 map[string]string
+	adminToken              string
+	clientBandwidthLimit    int
+	bandwidthExceededAction string
+	privacyMode             bool
+	coordinatePrecision     int
+	displayWeight           displayWeight
+	explorerLinks           *explorer.Linker
+	devMode                 bool
+	ingestOnly              bool
+	publicMode              bool
+	adminRouter             *gin.Engine
+	adminListenSpec         string
+	adminHTTPServer         *http.Server
+	adminListener           net.Listener
+	publicLimiter           *ipRateLimiter
+	maxConnectionsPerIP     int
+	ipConns                 map[string]int
+	exportKey               ed25519.PrivateKey
+	censusDir               string
+	devInjections           atomic.Uint64
+	bandwidthMu             sync.Mutex
+	apiKeyBytesSent         map[string]uint64
+	nextClientID            atomic.Uint64
+	networkHealthMu         sync.RWMutex
+	lastNetworkHealth       *models.ServerStatus
+	lastNetworkHealthAt     time.Time
+	upstreamLagTarget       time.Duration
+	upstreamLagMillis       atomic.Int64
+	stopBroadcast           chan struct{}
+	stopOnce                sync.Once
+	stopped                 atomic.Bool
+}
+
+// ValidatorSource provides validators and on-demand XRPL server status. It is
+// implemented by validator.Fetcher and, in replica mode, replica.Validators.
+type ValidatorSource interface {
+	GetValidators() []*models.Validator
+	GetLastUpdate() time.Time
+	GetServerStatus(ctx context.Context) (*models.ServerStatus, error)
+	GetDomainHistory(ctx context.Context, address string) (*models.DomainHistory, error)
+	AddCallback(callback validator.UpdateCallback)
+}
+
+// TransactionSource delivers streamed transactions. It is implemented by
+// transaction.Listener and, in replica mode, replica.Stream.
+type TransactionSource interface {
+	AddCallback(callback transaction.TransactionCallback)
+	AddGeoUpdateCallback(callback transaction.GeoUpdateCallback)
+	IsSubscribed() bool
+	MinPaymentDrops() int64
+}
+
+// ServerOptions controls optional server integrations.
+type ServerOptions struct {
+	// StatusPoller, when set, backs /network-health with the polled status
+	// and pushes server status change events to WebSocket clients.
+	StatusPoller *health.Poller
+
+	// Watchdog, when set, pushes stalled-pipeline alerts to WebSocket
+	// clients and reports stalled checks from /readyz.
+	Watchdog *health.Watchdog
+
+	// AnomalyDetector, when set, pushes anomaly events to WebSocket clients
+	// and enables /anomalies.
+	AnomalyDetector *health.AnomalyDetector
+
+	// SLOMonitor, when set, pushes validator set SLO alerts to WebSocket
+	// clients and reports breached checks from /health.
+	SLOMonitor *health.SLOMonitor
+
+	// PeerCollector, when set, enables /network/peers.
+	PeerCollector *peers.Collector
+
+	// IssuerGraphs, when set, enables /issuers/:account/graph.
+	IssuerGraphs *issuers.Collector
+
+	// Watchlist, when set, can be inspected and toggled at
+	// /admin/watchlist. It flags transactions as a listener processor.
+	Watchlist *compliance.Watchlist
+
+	// Labels, when set, accepts account labels at POST /labels and lists
+	// them at GET /labels. It tags transactions as a listener processor.
+	Labels *labels.Store
+
+	// Preferences, when set, keeps per-API-key stream defaults at
+	// GET/PUT /preferences and applies them to WebSocket clients.
+	Preferences *preferences.Store
+
+	// Decentralization, when set, enables /decentralization/compare.
+	Decentralization *decentralization.History
+
+	// CensusDir, when set, lists the daily validator census artifacts
+	// written there at /census and serves them at /census/:date.
+	CensusDir string
+
+	// NewAccounts, when set, enables /stats/new-accounts.
+	NewAccounts *stats.NewAccountTracker
+
+	// Activity, when set, enables /stats, /stats/timeline and /hotspots.
+	Activity *stats.Activity
+
+	// Burn, when set, pushes fee_burn events to WebSocket clients and
+	// enables /burn.
+	Burn *stats.BurnTracker
+
+	// Distributions, when set, enables /stats/distributions.
+	Distributions *stats.Distributions
+
+	// Ingestion, when set, can be paused and resumed at /admin/ingestion
+	// and is reported by /health.
+	Ingestion *ingestion.Controller
+
+	// GeoDB, when set, reports the age of the GeoLite DB in /health.
+	GeoDB GeoDBInfo
+
+	// Clock stamps events and drives cache expiry, rate limits and
+	// staleness checks. Nil uses the system clock.
+	Clock clock.Clock
+
+	// CORSMaxAge lets browsers cache preflight responses for this long.
+	// Zero omits Access-Control-Max-Age.
+	CORSMaxAge time.Duration
+
+	// TrustedProxies lists the proxy IPs and CIDRs whose X-Forwarded-For
+	// header is believed for client IPs. Requests from elsewhere are
+	// attributed to their peer address.
+	TrustedProxies []string
+
+	// ResponseCacheTTL caches serialized responses of hot REST endpoints
+	// for this long. Zero disables the cache.
+	ResponseCacheTTL time.Duration
+
+	// OriginPolicies restricts WebSocket clients per Origin header. The "*"
+	// key applies to allowed origins without a policy of their own.
+	OriginPolicies map[string]models.OriginPolicy
+
+	// ListenSpecs, when set, replaces the listen address/port pair with one
+	// listener per spec (see ParseListenSpec).
+	ListenSpecs []string
+
+	// APIKeys maps API keys accepted from WebSocket clients to the names
+	// used in bandwidth accounting. Unknown keys are rejected.
+	APIKeys map[string]string
+
+	// AdminToken, when set, enables the /admin endpoints behind
+	// "Authorization: Bearer <token>".
+	AdminToken string
+
+	// ClientBandwidthLimit is the per-client WebSocket budget in bytes per
+	// second. Zero disables the budget.
+	ClientBandwidthLimit int
+
+	// BandwidthExceededAction is BandwidthActionThrottle (default) or
+	// BandwidthActionSummary.
+	BandwidthExceededAction string
+
+	// PrivacyMode truncates account addresses, snaps coordinates to a
+	// ~50km grid and drops transaction tags and peer IPs in every REST and
+	// WebSocket response.
+	PrivacyMode bool
+
+	// CoordinatePrecision rounds published latitudes and longitudes to
+	// this many decimal places, after privacy mode snapping. Zero
+	// publishes them unrounded.
+	CoordinatePrecision int
+
+	// DisplayWeightMode is how display_weight is computed: WeightModeXRP
+	// (default), WeightModeLog, WeightModeFiat or WeightModeFlat.
+	DisplayWeightMode string
+
+	// DisplayWeightFiatRate is the fiat price of one XRP used by
+	// WeightModeFiat.
+	DisplayWeightFiatRate float64
+
+	// Explorers, in order, are the block explorers transactions and
+	// validators link to. Empty adds no links.
+	Explorers []models.Explorer
+
+	// DevMode enables POST /dev/inject, which pushes synthetic transactions
+	// and events to clients. Never enable it in production.
+	DevMode bool
+
+	// PublicMode hardens the service for direct internet exposure: no dev
+	// endpoints, the admin endpoints and /metrics on a separate listener at
+	// AdminListenSpec, per-IP request and WebSocket connection limits, and
+	// no instance IDs, data sources or peer IPs in responses.
+	PublicMode bool
+
+	// AdminListenSpec is where public mode serves the admin endpoints and
+	// /metrics (see ParseListenSpec). Empty uses 127.0.0.1:8081.
+	AdminListenSpec string
+
+	// PublicRateLimit and PublicRateBurst are the requests per second and
+	// burst each client IP gets in public mode. Zero uses 5 and 20.
+	PublicRateLimit float64
+	PublicRateBurst int
+
+	// MaxConnectionsPerIP caps concurrent WebSocket connections per
+	// client IP in public mode. Zero uses 4.
+	MaxConnectionsPerIP int
+
+	// IngestOnly limits the public surface to what serve-role processes
+	// mirror (/validators, /network-health, /transactions and validator
+	// domain and key histories), plus the health, metrics and admin
+	// endpoints. Views are not served.
+	IngestOnly bool
+
+	// Views are tenant namespaces served under /t/{name}/, keyed by name.
+	Views map[string]models.View
+
+	// UNLPresets maps the preset names accepted by ?unl= on the validator
+	// endpoints to the validator list site whose members they select.
+	UNLPresets map[string]string
+
+	// ExportSigningKey, when set, enables /validators/export, signed with
+	// it.
+	ExportSigningKey ed25519.PrivateKey
+
+	// UpstreamLagTarget is the upstream lag /load reports as 100%. Zero
+	// uses 10 seconds.
+	UpstreamLagTarget time.Duration
+
+	// Build and InstanceID identify the serving build and instance in
+	// /health and /version.
+	Build      buildinfo.Build
+	InstanceID string
 }
 
 // WSClient represents a WebSocket client connection
 type WSClient struct {
 	conn      *websocket.Conn
-	send      chan *models.Transaction
+	send      chan interface{}
 	server    *Server
 	closeOnce sync.Once
+	sendMu    sync.Mutex
+	closed    bool
+	origin    string
+	ip        string
+	policy    *originPolicy
+	limiter   *messageRateLimiter
+	view      *view
+
+	id          uint64
+	apiKey      string
+	shape       string
+	provisional bool   // receives provisional transactions and tx_settlement events
+	units       string // "xrp" converts drop amounts and fees, otherwise drops
+	filter      *view  // the API key's stored minimum payment and channels, or nil
+	protocol    *wsProtocol
+	connectedAt time.Time
+	bandwidth   clientBandwidth
+	budget      *bandwidthLimiter
+
+	// Corridor topics, set at connect or by subscribe messages.
+	topics      atomic.Pointer[[]topic]
+	topicFilter topicFilter
 }
 
 // NewServer creates a new HTTP server
 func NewServer(
-	validatorFetcher *validator.Fetcher,
-	transactionListener *transaction.Listener,
+	validatorFetcher ValidatorSource,
+	transactionListener TransactionSource,
 	listenAddr string,
 	listenPort int,
 	corsAllowedOrigins []string,
 	broadcastBufferSize int,
 	wsClientBufferSize int,
 	logger *logrus.Logger,
+	options ...ServerOptions,
 ) *Server {
 	if logger == nil {
 		logger = logrus.New()
 	}
+	opts := ServerOptions{}
+	if len(options) > 0 {
+		opts = options[0]
+	}
 	if broadcastBufferSize <= 0 {
 		broadcastBufferSize = 256
 	}
 	if wsClientBufferSize <= 0 {
 		wsClientBufferSize = 256
 	}
+	clk := clock.OrReal(opts.Clock)
+	if opts.UpstreamLagTarget <= 0 {
+		opts.UpstreamLagTarget = defaultUpstreamLagTarget
+	}
+	if opts.DisplayWeightMode == "" {
+		opts.DisplayWeightMode = WeightModeXRP
+	}
+	if opts.BandwidthExceededAction == "" {
+		opts.BandwidthExceededAction = BandwidthActionThrottle
+	}
+	if opts.PublicMode {
+		opts.DevMode = false
+		if opts.AdminListenSpec == "" {
+			opts.AdminListenSpec = defaultAdminListenSpec
+		}
+		if opts.PublicRateLimit <= 0 {
+			opts.PublicRateLimit = defaultPublicRateLimit
+		}
+		if opts.PublicRateBurst <= 0 {
+			opts.PublicRateBurst = defaultPublicRateBurst
+		}
+		if opts.MaxConnectionsPerIP <= 0 {
+			opts.MaxConnectionsPerIP = defaultPublicConnsPerIP
+		}
+	}
 
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
+	if err := router.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		logger.WithError(err).Warn("Invalid trusted proxies; trusting none")
+		_ = router.SetTrustedProxies(nil)
+	}
 
 	srv := &Server{
-		router:              router,
-		logger:              logger,
-		validatorFetcher:    validatorFetcher,
-		transactionListener: transactionListener,
-		listenAddr:          listenAddr,
-		listenPort:          listenPort,
-		corsAllowedOrigins:  corsAllowedOrigins,
-		wsClients:           make(map[*WSClient]bool),
-		broadcast:           make(chan *models.Transaction, broadcastBufferSize),
-		wsClientBufferSize:  wsClientBufferSize,
-		stopBroadcast:       make(chan struct{}),
+		router:                  router,
+		logger:                  logger,
+		validatorFetcher:        validatorFetcher,
+		transactionListener:     transactionListener,
+		listenAddr:              listenAddr,
+		listenPort:              listenPort,
+		listenSpecs:             opts.ListenSpecs,
+		corsAllowedOrigins:      corsAllowedOrigins,
+		corsMaxAge:              opts.CORSMaxAge,
+		wsClients:               make(map[*WSClient]bool),
+		originConns:             make(map[string]int),
+		apiKeys:                 opts.APIKeys,
+		adminToken:              opts.AdminToken,
+		clientBandwidthLimit:    opts.ClientBandwidthLimit,
+		bandwidthExceededAction: opts.BandwidthExceededAction,
+		privacyMode:             opts.PrivacyMode,
+		coordinatePrecision:     opts.CoordinatePrecision,
+		displayWeight:           displayWeight{mode: opts.DisplayWeightMode, fiatRate: opts.DisplayWeightFiatRate},
+		explorerLinks:           explorer.NewLinker(opts.Explorers),
+		devMode:                 opts.DevMode,
+		ingestOnly:              opts.IngestOnly,
+		publicMode:              opts.PublicMode,
+		ipConns:                 make(map[string]int),
+		exportKey:               opts.ExportSigningKey,
+		censusDir:               opts.CensusDir,
+		apiKeyBytesSent:         make(map[string]uint64),
+		broadcast:               make(chan interface{}, broadcastBufferSize),
+		wsClientBufferSize:      wsClientBufferSize,
+		statusPoller:            opts.StatusPoller,
+		watchdog:                opts.Watchdog,
+		anomalyDetector:         opts.AnomalyDetector,
+		sloMonitor:              opts.SLOMonitor,
+		peerCollector:           opts.PeerCollector,
+		issuerGraphs:            opts.IssuerGraphs,
+		watchlist:               opts.Watchlist,
+		labels:                  opts.Labels,
+		preferences:             opts.Preferences,
+		decentralization:        opts.Decentralization,
+		newAccounts:             opts.NewAccounts,
+		activity:                opts.Activity,
+		burn:                    opts.Burn,
+		distributions:           opts.Distributions,
+		ingestion:               opts.Ingestion,
+		geoDB:                   opts.GeoDB,
+		responseCache:           newResponseCache(clk),
+		clock:                   clk,
+		build:                   opts.Build,
+		instanceID:              opts.InstanceID,
+		startedAt:               clk.Now(),
+		responseCacheTTL:        opts.ResponseCacheTTL,
+		upstreamLagTarget:       opts.UpstreamLagTarget,
+		recent:                  newRecentTransactions(recentTransactionsSize),
+		stopBroadcast:           make(chan struct{}),
 		wsUpgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
-			CheckOrigin: func(r *http.Request) bool {
-				origin := r.Header.Get("Origin")
-				for _, allowed := range corsAllowedOrigins {
-					if origin == allowed {
-						return true
-					}
-				}
-				return false
-			},
+			Subprotocols:    supportedSubprotocols,
 		},
 	}
+	srv.wsUpgrader.CheckOrigin = func(r *http.Request) bool {
+		return srv.originAllowed(r.URL.Path, r.Header.Get("Origin"))
+	}
+
+	if len(opts.Views) > 0 {
+		srv.views = make(map[string]*view, len(opts.Views))
+		for name, config := range opts.Views {
+			srv.views[name] = newView(name, config)
+		}
+	}
+
+	srv.unlPresets = opts.UNLPresets
+
+	if opts.PublicMode {
+		srv.adminRouter = gin.Default()
+		srv.adminListenSpec = opts.AdminListenSpec
+		srv.publicLimiter = newIPRateLimiter(opts.PublicRateLimit, opts.PublicRateBurst)
+		srv.maxConnectionsPerIP = opts.MaxConnectionsPerIP
+	}
+
+	if len(opts.OriginPolicies) > 0 {
+		srv.originPolicies = make(map[string]*originPolicy, len(opts.OriginPolicies))
+		for origin, policy := range opts.OriginPolicies {
+			srv.originPolicies[origin] = newOriginPolicy(policy)
+		}
+	}
 
 	// Register routes
 	srv.registerRoutes()
 
 	// Register transaction callback
 	transactionListener.AddCallback(srv.onTransaction)
+	transactionListener.AddGeoUpdateCallback(srv.onTxGeoUpdate)
+	if srv.statusPoller != nil {
+		srv.statusPoller.AddCallback(srv.onServerStatusChange)
+	}
+	if srv.watchdog != nil {
+		srv.watchdog.AddCallback(srv.onWatchdogAlert)
+	}
+	if srv.anomalyDetector != nil {
+		srv.anomalyDetector.AddCallback(srv.onAnomaly)
+	}
+	if srv.sloMonitor != nil {
+		srv.sloMonitor.AddCallback(srv.onSLOAlert)
+	}
+	if srv.burn != nil {
+		srv.burn.AddCallback(srv.onFeeBurn)
+	}
+	if srv.validatorFetcher != nil {
+		srv.validatorFetcher.AddCallback(srv.onValidatorUpdate)
+	}
+	if progress, ok := srv.validatorFetcher.(FetchProgressSource); ok {
+		progress.AddCycleCallback(srv.onFetchCycle)
+	}
+	if rotations, ok := srv.validatorFetcher.(KeyRotationSource); ok {
+		rotations.AddRotationCallback(srv.onKeyRotation)
+	}
+	if preview, ok := transactionListener.(PreviewSource); ok {
+		preview.AddProvisionalCallback(srv.onProvisionalTransaction)
+		preview.AddSettlementCallback(srv.onTxSettlement)
+	}
 
 	// Start broadcast loop
 	go srv.broadcastLoop()
@@ -115,79 +532,319 @@ func NewServer(
 // registerRoutes sets up all HTTP endpoints
 func (s *Server) registerRoutes() {
 	// CORS middleware (must be registered before routes)
-	s.router.Use(func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-		allowed := false
-		for _, allowedOrigin := range s.corsAllowedOrigins {
-			if origin == allowedOrigin {
-				allowed = true
-				break
-			}
-		}
-		if allowed {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-		}
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-
-		c.Next()
-	})
+	s.router.Use(s.cors)
+	if s.publicMode {
+		s.router.Use(s.rateLimitPublic)
+	}
 
 	// Health check
 	s.router.GET("/health", s.handleHealth)
+	s.router.GET("/version", s.handleVersion)
+	s.router.GET("/readyz", s.handleReadyz)
+	s.router.GET("/startupz", s.handleStartupz)
+	s.router.GET("/load", s.handleLoad)
+
+	// Endpoints mirrored by serve-role processes
+	s.router.GET("/validators", s.responseCache.middleware("/validators", s.responseCacheTTL), s.handleGetValidators)
+	s.router.GET("/validators/:address/domain-history", s.handleValidatorDomainHistory)
+	s.router.GET("/validators/:address/key-history", s.handleValidatorKeyHistory)
+	s.router.GET("/network-health", s.responseCache.middleware("/network-health", s.responseCacheTTL), s.handleNetworkHealth)
+	s.router.GET("/transactions", s.handleTransactionsWebSocket)
+	if !s.ingestOnly {
+		s.registerPublicRoutes()
+	}
 
+	// Synthetic data for frontend development, only in dev mode
+	if s.devMode {
+		s.router.POST("/dev/inject", s.handleDevInject)
+	}
+
+	// Metrics and admin endpoints, the latter only when a token is
+	// configured. Public mode moves them to the admin listener.
+	operator := s.router
+	if s.adminRouter != nil {
+		operator = s.adminRouter
+	}
+	operator.GET("/metrics", s.handleMetrics)
+	s.registerAdminRoutes(operator)
+}
+
+// registerPublicRoutes sets up the endpoints frontends use directly, which
+// an ingest-only process leaves to its serve processes
+func (s *Server) registerPublicRoutes() {
 	// Validators endpoint
-	s.router.GET("/validators", s.handleGetValidators)
+	s.router.GET("/validators.geojson", s.responseCache.middleware("/validators.geojson", s.responseCacheTTL), s.handleGetValidatorsGeoJSON)
+	s.router.GET("/validators/export", s.handleValidatorsExport)
+	s.router.GET("/operators", s.handleOperators)
+	s.router.GET("/decentralization/compare", s.handleDecentralizationCompare)
+	s.router.GET("/census", s.handleCensusList)
+	s.router.GET("/census/:date", s.handleCensus)
 
-	// Network health endpoint
-	s.router.GET("/network-health", s.handleNetworkHealth)
+	// Local node peer connectivity endpoint
+	s.router.GET("/network/peers", s.handleNetworkPeers)
 
-	// Transactions WebSocket
-	s.router.GET("/transactions", s.handleTransactionsWebSocket)
+	// Localized country and city names
+	s.router.GET("/geo/names", s.handleGeoNames)
+
+	// Issuer trust line graphs
+	s.router.GET("/issuers/:account/graph", s.handleIssuerGraph)
+
+	// Network metric anomalies currently active
+	s.router.GET("/anomalies", s.handleAnomalies)
+
+	// Ledger statistics
+	s.router.GET("/stats", s.handleActivityStats)
+	s.router.GET("/stats/timeline", s.handleActivityTimeline)
+	s.router.GET("/hotspots", s.handleHotspots)
+	s.router.GET("/stats/new-accounts", s.handleNewAccountStats)
+	s.router.GET("/stats/distributions", s.handleDistributions)
+	s.router.GET("/burn", s.handleBurn)
+
+	// Recent transactions
+	s.router.GET("/transactions/recent", s.handleRecentTransactions)
+	s.router.GET("/transactions/recent.geojson", s.handleRecentTransactionsGeoJSON)
+
+	// Per-API-key stream defaults
+	s.router.GET("/preferences", s.handleGetPreferences)
+	s.router.PUT("/preferences", s.handlePutPreferences)
+
+	// Tenant views, filtered copies of the public endpoints
+	if len(s.views) > 0 {
+		views := s.router.Group("/t/:view", s.resolveView)
+		views.GET("/validators", s.responseCache.middleware("/t/:view/validators", s.responseCacheTTL), s.handleGetValidators)
+		views.GET("/validators.geojson", s.responseCache.middleware("/t/:view/validators.geojson", s.responseCacheTTL), s.handleGetValidatorsGeoJSON)
+		views.GET("/operators", s.handleOperators)
+		views.GET("/transactions", s.handleTransactionsWebSocket)
+		views.GET("/transactions/recent", s.handleRecentTransactions)
+		views.GET("/transactions/recent.geojson", s.handleRecentTransactionsGeoJSON)
+	}
 }
 
-// handleHealth returns service health status
+// handleHealth returns service health status. It always answers 200;
+// status is "degraded" when any degraded flag is set.
 func (s *Server) handleHealth(c *gin.Context) {
+	validatorsCount := len(s.validatorFetcher.GetValidators())
+	provisional := !s.validatorProvisionalSince().IsZero()
 	status := gin.H{
 		"status":                      "ok",
-		"validators_count":            len(s.validatorFetcher.GetValidators()),
+		"validators_count":            validatorsCount,
 		"last_validator_update":       s.validatorFetcher.GetLastUpdate(),
+		"validators_provisional":      provisional,
 		"transaction_listener_active": s.transactionListener.IsSubscribed(),
 		"min_payment_drops":           s.transactionListener.MinPaymentDrops(),
 		"websocket_clients":           s.websocketClientCount(),
+		"version":                     s.build.Version,
+		"commit":                      s.build.Commit,
+		"uptime_seconds":              s.uptimeSeconds(),
+	}
+	if !s.publicMode {
+		status["instance_id"] = s.instanceID
+	}
+	if s.ingestion != nil {
+		status["ingestion"] = s.ingestion.Status()
+	}
+
+	degraded := s.upstreamHealth(status)
+	if validatorsCount == 0 {
+		degraded = append(degraded, degradedValidatorCacheEmpty)
+	}
+	if provisional {
+		degraded = append(degraded, degradedValidatorsProvisional)
+	}
+	keyAlert, expiryAlert := s.publisherAlerted(c.Request.Context())
+	if keyAlert {
+		degraded = append(degraded, degradedPublisherKeyAlert)
+	}
+	if expiryAlert {
+		degraded = append(degraded, degradedListExpiry)
+	}
+	if s.sloMonitor != nil {
+		breached := s.sloMonitor.Breached()
+		status["slo_breached"] = breached
+		if len(breached) > 0 {
+			degraded = append(degraded, degradedSLOBreached)
+		}
+	}
+	if s.watchdog != nil {
+		degraded = append(degraded, s.watchdog.Stalled()...)
+	}
+	status["degraded"] = degraded
+	if len(degraded) > 0 {
+		status["status"] = "degraded"
 	}
 	c.JSON(http.StatusOK, status)
 }
 
-// handleGetValidators returns the list of validators
+// handleReadyz reports whether the service can serve live data: the polled
+// XRPL server must be ready and the transaction stream subscribed, unless
+// ingestion was paused on purpose. Not-ready responses are 503 with the
+// reasons.
+func (s *Server) handleReadyz(c *gin.Context) {
+	var reasons []string
+	if status, _, ok := s.polledNetworkHealth(); ok {
+		reasons = health.NotReadyReasons(status)
+	} else {
+		reasons = append(reasons, "no_server_status")
+	}
+	if !s.transactionListener.IsSubscribed() && !s.ingestionPaused() {
+		reasons = append(reasons, "transaction_stream_down")
+	}
+	if s.watchdog != nil {
+		reasons = append(reasons, s.watchdog.Stalled()...)
+	}
+
+	// Geolocation is degraded rather than down: the instance serves
+	// validators and transactions without locations until the DB opens.
+	body := gin.H{"ready": len(reasons) == 0}
+	if s.geoLiteInitError() != nil {
+		body["degraded"] = []string{degradedGeoLiteUnavailable}
+	}
+	if len(reasons) > 0 {
+		body["reasons"] = reasons
+		c.JSON(http.StatusServiceUnavailable, body)
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// handleStartupz reports whether startup has finished: the server is
+// serving and the validator cache holds at least one validator. Unlike
+// /readyz it does not depend on upstream health, so orchestrators can use
+// it to decide when to start liveness checks.
+func (s *Server) handleStartupz(c *gin.Context) {
+	count := len(s.validatorFetcher.GetValidators())
+	if count == 0 {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"started": false, "reasons": []string{"validator_cache_empty"}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"started": true, "validators_count": count})
+}
+
+// handleGetValidators returns the list of validators, as JSON or, with
+// ?format=csv, as a CSV attachment.
 func (s *Server) handleGetValidators(c *gin.Context) {
-	validators := s.validatorFetcher.GetValidators()
-	lastUpdate := s.validatorFetcher.GetLastUpdate()
-	etag := fmt.Sprintf("W/\"validators-%d-%d\"", lastUpdate.UnixNano(), len(validators))
+	csvRequested, ok := wantsCSV(c)
+	if !ok {
+		return
+	}
+	mask, err := parseFieldMask[models.Validator](c.Query("fields"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if mask != nil && csvRequested {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "fields is not supported for CSV"})
+		return
+	}
+	preset, ok := s.requestValidatorPreset(c)
+	if !ok {
+		return
+	}
+	kind := "json"
+	if mask != nil {
+		kind = "json-fields-" + mask.key
+	}
+	v := requestView(c)
+	hash := s.validatorSnapshotHash()
+	etag := s.validatorsETag(preset.kind(v.kind("validators")), hash)
+	if csvRequested {
+		etag = s.validatorsETag(preset.kind(v.kind("validators-csv")), hash)
+	} else if mask != nil {
+		etag = s.validatorsETag(preset.kind(v.kind("validators-fields-"+mask.key)), hash)
+	}
 
 	c.Header("Cache-Control", "public, max-age=30, stale-while-revalidate=300")
 	c.Header("ETag", etag)
+	updatedAt, source := s.validatorFreshness()
+	freshness := s.setFreshness(c, updatedAt, source)
 
 	if inm := c.GetHeader("If-None-Match"); inm != "" && inm == etag {
 		c.Status(http.StatusNotModified)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"validators": validators,
-		"count":      len(validators),
-		"timestamp":  lastUpdate,
+	if csvRequested {
+		validators := preset.filterValidators(v.filterValidators(s.publicValidators()))
+		s.writeCSV(c, "validators.csv", validatorCSVHeader, func(write func([]string) error) error {
+			for _, v := range validators {
+				if err := write(validatorCSVRecord(v)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return
+	}
+
+	body, count, err := s.validatorBody(v, preset, kind, hash, func(validators []*models.Validator) ([]byte, error) {
+		return marshalMasked(validators, mask)
 	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := gin.H{
+		"validators": json.RawMessage(body),
+		"count":      count,
+		"timestamp":  s.validatorFetcher.GetLastUpdate(),
+	}
+	if since := s.validatorProvisionalSince(); !since.IsZero() {
+		response["provisional"] = true
+		response["timestamp"] = since
+	}
+	if source, ok := s.validatorFetcher.(SourceStatusSource); ok {
+		if status := source.SourceStatus(); status != nil {
+			response["sources_ok"] = status.OK
+			response["sources_failed"] = status.Failed
+		}
+	}
+	setFreshnessFields(response, freshness)
+	c.JSON(http.StatusOK, response)
+}
+
+// publicValidators returns the current validators as served to clients.
+func (s *Server) publicValidators() []*models.Validator {
+	return s.PublishValidators(s.validatorFetcher.GetValidators())
+}
+
+// PublishValidators prepares validators the way the API serves them:
+// snapped to the privacy grid in privacy mode, rounded to the coordinate
+// precision and linked to the configured explorers. The validators given are
+// not modified.
+func (s *Server) PublishValidators(validators []*models.Validator) []*models.Validator {
+	if s.privacyMode {
+		validators = anonymizeValidators(validators)
+	}
+	return s.linkValidators(roundValidators(validators, s.coordinatePrecision))
+}
+
+// handleValidatorDomainHistory returns the recorded domain changes of one
+// validator.
+func (s *Server) handleValidatorDomainHistory(c *gin.Context) {
+	address := c.Param("address")
+	history, err := s.validatorFetcher.GetDomainHistory(c.Request.Context(), address)
+	if errors.Is(err, xrpl.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "validator not found"})
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("address", address).Warn("Failed to fetch validator domain history")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "validator domain history is unavailable"})
+		return
+	}
+	c.JSON(http.StatusOK, history)
 }
 
 // handleNetworkHealth returns XRPL consensus health data for visualization mode.
 func (s *Server) handleNetworkHealth(c *gin.Context) {
+	if serverStatus, polledAt, ok := s.polledNetworkHealth(); ok {
+		s.cacheNetworkHealth(serverStatus)
+		payload := s.networkHealthPayload(serverStatus)
+		setFreshnessFields(payload, s.setFreshness(c, polledAt, dataSourcePoller))
+		c.JSON(http.StatusOK, payload)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
@@ -195,18 +852,20 @@ func (s *Server) handleNetworkHealth(c *gin.Context) {
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to fetch network health")
 		if staleStatus, staleAt, ok := s.getCachedNetworkHealth(); ok {
-			c.JSON(http.StatusOK, gin.H{
+			payload := gin.H{
 				"status":                      "degraded",
 				"server":                      staleStatus,
 				"stale":                       true,
-				"stale_age_seconds":           int(time.Since(staleAt).Seconds()),
+				"stale_age_seconds":           int(s.clock.Since(staleAt).Seconds()),
 				"stale_ttl_seconds":           int(networkHealthStaleTTL.Seconds()),
 				"error":                       err.Error(),
 				"validators_count":            len(s.validatorFetcher.GetValidators()),
 				"transaction_listener_active": s.transactionListener.IsSubscribed(),
 				"websocket_clients":           s.websocketClientCount(),
-				"timestamp":                   time.Now().Unix(),
-			})
+				"timestamp":                   s.clock.Now().Unix(),
+			}
+			setFreshnessFields(payload, s.setFreshness(c, staleAt, dataSourceStaleCache))
+			c.JSON(http.StatusOK, payload)
 			return
 		}
 
@@ -219,37 +878,199 @@ func (s *Server) handleNetworkHealth(c *gin.Context) {
 	}
 	s.cacheNetworkHealth(serverStatus)
 
-	c.JSON(http.StatusOK, gin.H{
+	payload := s.networkHealthPayload(serverStatus)
+	source := dataSourceFetch
+	if reporter, ok := s.validatorFetcher.(DataSourceReporter); ok {
+		source = reporter.DataSource()
+	}
+	setFreshnessFields(payload, s.setFreshness(c, s.clock.Now(), source))
+	c.JSON(http.StatusOK, payload)
+}
+
+func (s *Server) networkHealthPayload(serverStatus *models.ServerStatus) gin.H {
+	return gin.H{
 		"status":                      "ok",
 		"server":                      serverStatus,
 		"stale":                       false,
 		"validators_count":            len(s.validatorFetcher.GetValidators()),
 		"transaction_listener_active": s.transactionListener.IsSubscribed(),
 		"websocket_clients":           s.websocketClientCount(),
-		"timestamp":                   time.Now().Unix(),
+		"timestamp":                   s.clock.Now().Unix(),
+	}
+}
+
+// polledNetworkHealth returns the poller's cached status and when it was
+// polled while it is younger than two polling intervals.
+func (s *Server) polledNetworkHealth() (*models.ServerStatus, time.Time, bool) {
+	if s.statusPoller == nil {
+		return nil, time.Time{}, false
+	}
+	status, polledAt, ok := s.statusPoller.Latest()
+	if !ok || s.clock.Since(polledAt) > 2*s.statusPoller.Interval() {
+		return nil, time.Time{}, false
+	}
+	return status, polledAt, true
+}
+
+// handleNetworkPeers returns the local node's peer connections and version mix.
+func (s *Server) handleNetworkPeers(c *gin.Context) {
+	if s.peerCollector == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "peer collection is not configured"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	summary, err := s.peerCollector.GetPeers(ctx)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to fetch peers")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if s.privacyMode || s.publicMode {
+		summary = anonymizePeers(summary)
+	}
+	c.JSON(http.StatusOK, roundPeers(summary, s.coordinatePrecision))
+}
+
+// handleAnomalies returns the network metric anomalies that have not
+// cleared, so clients can show banners before the next anomaly event.
+func (s *Server) handleAnomalies(c *gin.Context) {
+	if s.anomalyDetector == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "anomaly detection is not configured"})
+		return
+	}
+	anomalies := s.anomalyDetector.Active()
+	c.JSON(http.StatusOK, gin.H{
+		"anomalies": anomalies,
+		"count":     len(anomalies),
 	})
 }
 
 // handleTransactionsWebSocket upgrades HTTP connection to WebSocket
 func (s *Server) handleTransactionsWebSocket(c *gin.Context) {
+	apiKey, ok := s.resolveAPIKey(c)
+	if !ok {
+		metrics.WebSocketConnectionsRejectedTotal.WithLabelValues("invalid_api_key").Inc()
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
+		return
+	}
+
+	// Stored preferences are defaults; query parameters override them
+	prefs := s.clientPreferences(apiKey)
+
+	shape := c.Query("shape")
+	if shape == "" {
+		shape = prefs.Shape
+	}
+	if shape == "" {
+		shape = models.TransactionShapeLocations
+	}
+	switch shape {
+	case models.TransactionShapeLocations, models.TransactionShapeRoles, models.TransactionShapeBoth:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "shape must be locations, roles or both"})
+		return
+	}
+
+	topics, err := s.queryTopics(c.Query("topics"))
+	if c.Query("topics") == "" && len(prefs.Topics) > 0 {
+		topics, err = parseTopics(prefs.Topics, s.privacyMode)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	units := c.DefaultQuery("units", prefs.Units)
+	switch units {
+	case "", models.UnitsDrops, models.UnitsXRP:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "units must be drops or xrp"})
+		return
+	}
+
+	provisional := prefs.Provisional
+	if raw := c.Query("provisional"); raw != "" {
+		provisional, err = strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "provisional must be true or false"})
+			return
+		}
+	}
+
+	if !offersSupportedSubprotocol(c.Request) {
+		metrics.WebSocketConnectionsRejectedTotal.WithLabelValues("unsupported_subprotocol").Inc()
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported subprotocol", "supported": supportedSubprotocols})
+		return
+	}
+
+	origin := c.GetHeader("Origin")
+	policy := s.originPolicyFor(origin)
+	if !s.reserveOriginConnection(origin, policy) {
+		metrics.WebSocketConnectionsRejectedTotal.WithLabelValues("origin_connection_cap").Inc()
+		s.logger.WithField("origin", origin).Warn("Rejecting WebSocket client over origin connection cap")
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many connections for origin"})
+		return
+	}
+
+	ip := c.ClientIP()
+	if !s.reserveIPConnection(ip) {
+		s.releaseOriginConnection(origin)
+		metrics.WebSocketConnectionsRejectedTotal.WithLabelValues("ip_connection_cap").Inc()
+		s.logger.WithField("client_ip", ip).Warn("Rejecting WebSocket client over per-IP connection cap")
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many connections from this address"})
+		return
+	}
+
 	conn, err := s.wsUpgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
+		s.releaseOriginConnection(origin)
+		s.releaseIPConnection(ip)
 		s.logger.WithError(err).Error("WebSocket upgrade failed")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "WebSocket upgrade failed"})
 		return
 	}
 
 	client := &WSClient{
-		conn:   conn,
-		send:   make(chan *models.Transaction, s.wsClientBufferSize),
-		server: s,
+		conn:    conn,
+		send:    make(chan interface{}, s.wsClientBufferSize),
+		server:  s,
+		origin:  origin,
+		ip:      ip,
+		policy:  policy,
+		limiter: policy.newLimiter(),
+		view:    requestView(c),
+
+		id:          s.nextClientID.Add(1),
+		apiKey:      apiKey,
+		shape:       shape,
+		provisional: provisional,
+		units:       units,
+		filter:      preferencesFilter(prefs),
+		protocol:    protocolFor(conn.Subprotocol()),
+		connectedAt: s.clock.Now(),
+		budget:      newBandwidthLimiter(s.clientBandwidthLimit),
+	}
+	if len(topics) > 0 {
+		client.topics.Store(&topics)
+	}
+	if hello := client.helloEvent(); hello != nil {
+		client.trySend(hello)
 	}
 
 	s.wsMu.Lock()
 	s.wsClients[client] = true
 	s.wsMu.Unlock()
+	metrics.WebSocketConnectionsTotal.Inc()
+	metrics.WebSocketSubprotocolConnectionsTotal.WithLabelValues(client.protocol.name).Inc()
+	metrics.WebSocketConnectionsActive.Inc()
 
-	s.logger.WithField("client_addr", conn.RemoteAddr()).Info("WebSocket client connected")
+	s.logger.WithFields(logrus.Fields{
+		"client_addr": conn.RemoteAddr(),
+		"protocol":    client.protocol.name,
+	}).Info("WebSocket client connected")
 
 	// Start client goroutines
 	go client.readPump()
@@ -258,9 +1079,16 @@ func (s *Server) handleTransactionsWebSocket(c *gin.Context) {
 
 // onTransaction is called when a new transaction is received
 func (s *Server) onTransaction(tx *models.Transaction) {
-	if s.stopped.Load() {
+	if s.stopped.Load() || tx == nil {
 		return
 	}
+	tx = s.weighTransaction(tx)
+	if s.privacyMode {
+		tx = anonymizeTransaction(tx)
+	}
+	tx = roundTransaction(tx, s.coordinatePrecision)
+	tx = s.linkTransaction(tx)
+	s.recent.add(tx)
 	select {
 	case s.broadcast <- tx:
 	default:
@@ -268,18 +1096,154 @@ func (s *Server) onTransaction(tx *models.Transaction) {
 	}
 }
 
-// broadcastLoop distributes transactions to all connected clients
+// onTxGeoUpdate pushes locations resolved after a transaction was broadcast
+// without them.
+func (s *Server) onTxGeoUpdate(update *models.TxGeoUpdate) {
+	if update == nil {
+		return
+	}
+	if s.privacyMode {
+		update = &models.TxGeoUpdate{Hash: update.Hash, LedgerIndex: update.LedgerIndex, Locations: anonymizeLocations(update.Locations), EnrichedAt: update.EnrichedAt}
+	}
+	if s.coordinatePrecision > 0 {
+		update = &models.TxGeoUpdate{Hash: update.Hash, LedgerIndex: update.LedgerIndex, Locations: roundLocations(update.Locations, s.coordinatePrecision), EnrichedAt: update.EnrichedAt}
+	}
+	s.recent.updateLocations(update.Hash, update.Locations)
+	s.broadcastEvent(&models.StreamEvent{
+		Type:      "tx_geo_update",
+		Timestamp: s.clock.Now().Unix(),
+		Data:      update,
+	})
+}
+
+// onServerStatusChange pushes material server status changes to clients.
+func (s *Server) onServerStatusChange(change *models.ServerStatusChange) {
+	if change == nil {
+		return
+	}
+	now := s.clock.Now().Unix()
+	s.broadcastEvent(&models.StreamEvent{
+		Type:      "server_status",
+		Timestamp: now,
+		Data:      change,
+	})
+	for _, alert := range change.Alerts {
+		s.broadcastEvent(&models.StreamEvent{Type: "server_alert", Timestamp: now, Data: alert})
+	}
+}
+
+// onWatchdogAlert pushes stalled-pipeline alerts to clients.
+func (s *Server) onWatchdogAlert(alert *models.WatchdogAlert) {
+	if alert == nil {
+		return
+	}
+	s.broadcastEvent(&models.StreamEvent{Type: "watchdog_alert", Timestamp: s.clock.Now().Unix(), Data: alert})
+}
+
+// onSLOAlert pushes validator set SLO alerts to clients.
+func (s *Server) onSLOAlert(alert *models.SLOAlert) {
+	if alert == nil {
+		return
+	}
+	s.broadcastEvent(&models.StreamEvent{Type: "slo_alert", Timestamp: s.clock.Now().Unix(), Data: alert})
+}
+
+// onAnomaly pushes network metric anomalies to clients.
+func (s *Server) onAnomaly(anomaly *models.Anomaly) {
+	if anomaly == nil {
+		return
+	}
+	s.broadcastEvent(&models.StreamEvent{Type: "anomaly", Timestamp: s.clock.Now().Unix(), Data: anomaly})
+}
+
+// onFeeBurn pushes each validated ledger's fee burn to clients.
+func (s *Server) onFeeBurn(burn *models.FeeBurn) {
+	if burn == nil {
+		return
+	}
+	s.broadcastEvent(&models.StreamEvent{Type: "fee_burn", Timestamp: s.clock.Now().Unix(), Data: burn})
+}
+
+// onValidatorUpdate pushes one validator_upsert or validator_remove event per
+// changed validator so clients can patch markers without refetching.
+func (s *Server) onValidatorUpdate(update *models.ValidatorUpdate) {
+	if update == nil {
+		return
+	}
+	now := s.clock.Now().Unix()
+	for _, delta := range update.Upserts {
+		countries := s.deltaCountries(delta)
+		if s.privacyMode {
+			delta = anonymizeValidatorDelta(delta)
+		}
+		delta = roundValidatorDelta(delta, s.coordinatePrecision)
+		delta = s.linkValidatorDelta(delta)
+		s.broadcastEvent(&models.StreamEvent{Type: "validator_upsert", Timestamp: now, Data: delta, Countries: countries})
+	}
+	for _, delta := range update.Removals {
+		s.broadcastEvent(&models.StreamEvent{Type: "validator_remove", Timestamp: now, Data: delta, Countries: s.deltaCountries(delta)})
+	}
+}
+
+// deltaCountries returns the countries views filter a validator delta by:
+// those recorded when it was diffed, otherwise the validator's current
+// country.
+func (s *Server) deltaCountries(delta *models.ValidatorDelta) []string {
+	if delta == nil {
+		return nil
+	}
+	if len(delta.Countries) > 0 {
+		return delta.Countries
+	}
+	return s.validatorCountry(delta.Address)
+}
+
+// validatorCountry returns the current country of the validator at address,
+// or nil if it is unknown.
+func (s *Server) validatorCountry(address string) []string {
+	if s.validatorFetcher == nil {
+		return nil
+	}
+	for _, v := range s.validatorFetcher.GetValidators() {
+		if v != nil && v.Address == address {
+			return []string{v.CountryCode}
+		}
+	}
+	return nil
+}
+
+// broadcastEvent enqueues a non-transaction event for WebSocket fanout.
+func (s *Server) broadcastEvent(event *models.StreamEvent) {
+	if s.stopped.Load() || event == nil {
+		return
+	}
+	select {
+	case s.broadcast <- event:
+	default:
+		s.logger.WithField("type", event.Type).Warn("Broadcast channel full, dropping event")
+	}
+}
+
+// broadcastLoop distributes transactions and events to all connected clients
 func (s *Server) broadcastLoop() {
 	for {
-		var tx *models.Transaction
+		var msg interface{}
 		select {
 		case <-s.stopBroadcast:
 			return
-		case tx = <-s.broadcast:
+		case msg = <-s.broadcast:
 		}
-		if tx == nil {
+		if msg == nil {
 			continue
 		}
+		now := s.clock.Now()
+		msg = stampBroadcast(msg, now)
+		s.recordUpstreamLag(msg)
+		if tx, ok := msg.(*models.Transaction); ok && !tx.Provisional && s.watchdog != nil {
+			// Liveness counts transactions that reached the loop, with or
+			// without clients connected.
+			s.watchdog.ObserveTransaction(tx)
+		}
 
 		s.wsMu.RLock()
 		clients := make([]*WSClient, 0, len(s.wsClients))
@@ -288,10 +1252,20 @@ func (s *Server) broadcastLoop() {
 		}
 		s.wsMu.RUnlock()
 
+		channel := messageChannel(msg)
 		for _, client := range clients {
-			select {
-			case client.send <- tx:
-			default:
+			if !client.provisional && isProvisionalMessage(msg) {
+				continue
+			}
+			if !client.policy.allows(channel) || !client.view.allows(msg) || !client.filter.allows(msg) || !client.topicFilter.allows(client.currentTopics(), msg) {
+				continue
+			}
+			if !client.limiter.allow(now) {
+				metrics.WebSocketMessagesDroppedTotal.WithLabelValues("origin_rate_limit").Inc()
+				continue
+			}
+			if !client.trySend(msg) {
+				metrics.WebSocketMessagesDroppedTotal.WithLabelValues("client_buffer_full").Inc()
 				go s.closeClient(client)
 			}
 		}
@@ -305,9 +1279,19 @@ func (s *Server) closeClient(client *WSClient) {
 	}
 	client.closeOnce.Do(func() {
 		s.wsMu.Lock()
-		delete(s.wsClients, client)
+		if _, ok := s.wsClients[client]; ok {
+			delete(s.wsClients, client)
+			if client.conn != nil {
+				metrics.WebSocketConnectionsActive.Dec()
+			}
+		}
 		s.wsMu.Unlock()
+		s.releaseOriginConnection(client.origin)
+		s.releaseIPConnection(client.ip)
+		client.sendMu.Lock()
+		client.closed = true
 		close(client.send)
+		client.sendMu.Unlock()
 		if client.conn != nil {
 			client.conn.Close()
 			s.logger.WithField("client_addr", client.conn.RemoteAddr()).Info("WebSocket client disconnected")
@@ -322,7 +1306,7 @@ func (s *Server) cacheNetworkHealth(status *models.ServerStatus) {
 	copy := *status
 	s.networkHealthMu.Lock()
 	s.lastNetworkHealth = &copy
-	s.lastNetworkHealthAt = time.Now()
+	s.lastNetworkHealthAt = s.clock.Now()
 	s.networkHealthMu.Unlock()
 }
 
@@ -333,7 +1317,7 @@ func (s *Server) getCachedNetworkHealth() (*models.ServerStatus, time.Time, bool
 	if s.lastNetworkHealth == nil || s.lastNetworkHealthAt.IsZero() {
 		return nil, time.Time{}, false
 	}
-	if time.Since(s.lastNetworkHealthAt) > networkHealthStaleTTL {
+	if s.clock.Since(s.lastNetworkHealthAt) > networkHealthStaleTTL {
 		return nil, time.Time{}, false
 	}
 
@@ -341,6 +1325,28 @@ func (s *Server) getCachedNetworkHealth() (*models.ServerStatus, time.Time, bool
 	return &copy, s.lastNetworkHealthAt, true
 }
 
+// reserveOriginConnection counts a new connection against its origin's cap.
+// It returns false when the origin is already at its cap.
+func (s *Server) reserveOriginConnection(origin string, policy *originPolicy) bool {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	if policy != nil && policy.maxConnections > 0 && s.originConns[origin] >= policy.maxConnections {
+		return false
+	}
+	s.originConns[origin]++
+	return true
+}
+
+func (s *Server) releaseOriginConnection(origin string) {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	if s.originConns[origin] <= 1 {
+		delete(s.originConns, origin)
+		return
+	}
+	s.originConns[origin]--
+}
+
 func (s *Server) websocketClientCount() int {
 	s.wsMu.RLock()
 	defer s.wsMu.RUnlock()
@@ -360,12 +1366,29 @@ func (s *Server) closeAllClients() {
 	}
 }
 
+// trySend enqueues msg without blocking. It returns false when the client's
+// buffer is full; sends to an already-closed client are silently ignored.
+func (c *WSClient) trySend(msg interface{}) bool {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	if c.closed {
+		return true
+	}
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
 // readPump reads messages from the WebSocket client
 func (c *WSClient) readPump() {
 	defer func() {
 		c.server.closeClient(c)
 	}()
 
+	c.conn.SetReadLimit(maxClientMessageBytes)
 	c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
@@ -373,13 +1396,19 @@ func (c *WSClient) readPump() {
 	})
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		messageType, message, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				c.server.logger.WithError(err).Warn("WebSocket error")
 			}
 			break
 		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+		// Replies are dropped rather than closing the client when its
+		// buffer is full; it can resend the request.
+		c.trySend(c.handleTopicRequest(message))
 	}
 }
 
@@ -393,16 +1422,21 @@ func (c *WSClient) writePump() {
 
 	for {
 		select {
-		case tx, ok := <-c.send:
+		case msg, ok := <-c.send:
 			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			if !ok {
 				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
 
-			if err := c.conn.WriteJSON(tx); err != nil {
+			data, ok := c.encode(msg)
+			if !ok {
+				continue
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
 				return
 			}
+			c.server.recordBytesSent(c, len(data))
 
 		case <-ticker.C:
 			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
@@ -413,16 +1447,132 @@ func (c *WSClient) writePump() {
 	}
 }
 
-// Start starts the HTTP server
+// encode serializes msg for the wire and applies the client's bandwidth
+// budget. It returns false when the message should be skipped.
+func (c *WSClient) encode(msg interface{}) ([]byte, bool) {
+	var data []byte
+	var err error
+	now := c.server.clock.Now()
+	if tx, isTx := msg.(*models.Transaction); isTx && c.units == models.UnitsXRP {
+		msg = inXRP(tx)
+	}
+	if tx, isTx := msg.(*models.Transaction); isTx {
+		data, err = models.MarshalTransaction(tx, c.shape)
+		if err == nil {
+			data, err = c.protocol.frameTransaction(data, now)
+		}
+	} else {
+		data, err = json.Marshal(msg)
+	}
+	if err != nil {
+		c.server.logger.WithError(err).Warn("Failed to encode WebSocket message")
+		return nil, false
+	}
+	if c.budget.allow(len(data), now) {
+		return data, true
+	}
+	if tx, isTx := msg.(*models.Transaction); isTx && c.server.bandwidthExceededAction == BandwidthActionSummary {
+		summary, err := json.Marshal(summarizeTransaction(tx))
+		if err == nil {
+			summary, err = c.protocol.frameTransaction(summary, now)
+		}
+		if err == nil && c.budget.allow(len(summary), now) {
+			c.bandwidth.downgraded.Add(1)
+			metrics.WebSocketMessagesDowngradedTotal.Inc()
+			return summary, true
+		}
+	}
+	c.bandwidth.throttled.Add(1)
+	metrics.WebSocketMessagesDroppedTotal.WithLabelValues("bandwidth_budget").Inc()
+	return nil, false
+}
+
+// Start opens a listener per listen spec and serves HTTP on all of them. It
+// blocks until serving stops and returns the first serve error, which is
+// http.ErrServerClosed after Stop.
 func (s *Server) Start(ctx context.Context) error {
-	addr := fmt.Sprintf("%s:%d", s.listenAddr, s.listenPort)
-	s.httpServer = &http.Server{
-		Addr:    addr,
-		Handler: s.router,
+	specs := s.listenSpecs
+	if len(specs) == 0 {
+		specs = []string{"tcp:" + net.JoinHostPort(s.listenAddr, strconv.Itoa(s.listenPort))}
 	}
 
-	s.logger.WithField("address", addr).Info("Starting HTTP server")
-	return s.httpServer.ListenAndServe()
+	listeners := make([]net.Listener, 0, len(specs))
+	for _, spec := range specs {
+		listener, err := listen(spec)
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	var adminListener net.Listener
+	var adminServer *http.Server
+	if s.adminRouter != nil {
+		listener, err := listen(s.adminListenSpec)
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return err
+		}
+		adminListener = listener
+		adminServer = &http.Server{Handler: s.adminRouter}
+	}
+
+	httpServer := &http.Server{Handler: s.router}
+	s.listenersMu.Lock()
+	s.httpServer = httpServer
+	s.listeners = listeners
+	s.adminHTTPServer = adminServer
+	s.adminListener = adminListener
+	s.listenersMu.Unlock()
+
+	errCh := make(chan error, len(listeners)+1)
+	for _, listener := range listeners {
+		s.logger.WithFields(logrus.Fields{
+			"network": listener.Addr().Network(),
+			"address": listener.Addr().String(),
+		}).Info("Starting HTTP server")
+		go func(listener net.Listener) {
+			errCh <- httpServer.Serve(listener)
+		}(listener)
+	}
+	if adminServer != nil {
+		s.logger.WithFields(logrus.Fields{
+			"network": adminListener.Addr().Network(),
+			"address": adminListener.Addr().String(),
+		}).Info("Starting admin HTTP server")
+		go func() {
+			errCh <- adminServer.Serve(adminListener)
+		}()
+	}
+	return <-errCh
+}
+
+// ListenAddrs returns the addresses the server is listening on once Start
+// has opened its listeners.
+func (s *Server) ListenAddrs() []net.Addr {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	addrs := make([]net.Addr, 0, len(s.listeners))
+	for _, listener := range s.listeners {
+		addrs = append(addrs, listener.Addr())
+	}
+	return addrs
+}
+
+// AdminListenAddr returns the address of the public mode admin listener
+// once Start has opened it, or nil.
+func (s *Server) AdminListenAddr() net.Addr {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	if s.adminListener == nil {
+		return nil
+	}
+	return s.adminListener.Addr()
 }
 
 // Stop gracefully stops the HTTP server and closes client connections.
@@ -432,8 +1582,17 @@ func (s *Server) Stop(ctx context.Context) error {
 		s.stopped.Store(true)
 		close(s.stopBroadcast)
 		s.closeAllClients()
-		if s.httpServer != nil {
-			stopErr = s.httpServer.Shutdown(ctx)
+		s.listenersMu.Lock()
+		httpServer := s.httpServer
+		adminServer := s.adminHTTPServer
+		s.listenersMu.Unlock()
+		if adminServer != nil {
+			stopErr = adminServer.Shutdown(ctx)
+		}
+		if httpServer != nil {
+			if err := httpServer.Shutdown(ctx); err != nil {
+				stopErr = err
+			}
 		}
 	})
 	return stopErr
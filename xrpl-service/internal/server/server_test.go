@@ -2,10 +2,20 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/brandon/xrpl-validator-service/internal/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/health"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/stats"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
@@ -13,16 +23,18 @@ func newTestServer() *Server {
 	return &Server{
 		logger:             logrus.New(),
 		wsClients:          make(map[*WSClient]bool),
-		broadcast:          make(chan *models.Transaction, 4),
+		broadcast:          make(chan interface{}, 4),
 		stopBroadcast:      make(chan struct{}),
 		wsClientBufferSize: 4,
+		recent:             newRecentTransactions(8),
+		clock:              clock.Real(),
 	}
 }
 
 func TestCloseClientIsIdempotent(t *testing.T) {
 	srv := newTestServer()
 	client := &WSClient{
-		send:   make(chan *models.Transaction),
+		send:   make(chan interface{}),
 		server: srv,
 	}
 	srv.wsClients[client] = true
@@ -87,3 +99,381 @@ func TestBroadcastLoopStopsWhenSignaled(t *testing.T) {
 		t.Fatal("broadcastLoop did not stop after stop signal")
 	}
 }
+
+func TestOnServerStatusChangeEnqueuesEvent(t *testing.T) {
+	srv := newTestServer()
+
+	srv.onServerStatusChange(&models.ServerStatusChange{
+		Current: &models.ServerStatus{ServerState: "full"},
+		Reasons: []string{"initial"},
+	})
+
+	select {
+	case msg := <-srv.broadcast:
+		event, ok := msg.(*models.StreamEvent)
+		if !ok {
+			t.Fatalf("expected stream event, got %T", msg)
+		}
+		if event.Type != "server_status" {
+			t.Fatalf("expected server_status event, got %s", event.Type)
+		}
+	default:
+		t.Fatal("expected server status event to be enqueued")
+	}
+}
+
+func TestOnServerStatusChangeEnqueuesAlerts(t *testing.T) {
+	srv := newTestServer()
+
+	srv.onServerStatusChange(&models.ServerStatusChange{
+		Current: &models.ServerStatus{ServerState: "full", AmendmentBlocked: true},
+		Reasons: []string{"amendment_blocked"},
+		Alerts:  []*models.ServerAlert{{Condition: "amendment_blocked", Active: true}},
+	})
+
+	for _, want := range []string{"server_status", "server_alert"} {
+		select {
+		case msg := <-srv.broadcast:
+			if event := msg.(*models.StreamEvent); event.Type != want {
+				t.Fatalf("expected %s event, got %s", want, event.Type)
+			}
+		default:
+			t.Fatalf("expected %s event to be enqueued", want)
+		}
+	}
+}
+
+func TestOnWatchdogAlertEnqueuesEvent(t *testing.T) {
+	srv := newTestServer()
+	srv.onWatchdogAlert(&models.WatchdogAlert{Check: "transactions_stalled", Active: true})
+
+	select {
+	case msg := <-srv.broadcast:
+		event, ok := msg.(*models.StreamEvent)
+		if !ok || event.Type != "watchdog_alert" {
+			t.Fatalf("expected watchdog_alert event, got %#v", msg)
+		}
+	default:
+		t.Fatal("expected watchdog alert to be enqueued")
+	}
+}
+
+func TestBroadcastLoopRecordsWatchdogLiveness(t *testing.T) {
+	const stallAfter = 200 * time.Millisecond
+	srv := newTestServer()
+	srv.watchdog = health.NewWatchdog(nil, &upstreamListener{status: models.UpstreamStatus{Subscribed: true}}, stallAfter, 0, nil)
+	srv.watchdog.Check()
+	time.Sleep(stallAfter + 50*time.Millisecond)
+
+	srv.watchdog.Check()
+	if stalled := srv.watchdog.Stalled(); len(stalled) != 1 {
+		t.Fatalf("expected the stalled transactions check, got %v", stalled)
+	}
+
+	go srv.broadcastLoop()
+	defer close(srv.stopBroadcast)
+	srv.broadcast <- &models.Transaction{Hash: "P1", Provisional: true}
+	srv.broadcast <- &models.Transaction{Hash: "V1", Validated: true}
+
+	deadline := time.After(time.Second)
+	for {
+		srv.watchdog.Check()
+		if len(srv.watchdog.Stalled()) == 0 {
+			return
+		}
+		select {
+		case <-deadline:
+			t.Fatal("expected a broadcast transaction to clear the stall without any client connected")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestAnomaliesEndpointAndEvent(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/anomalies", srv.handleAnomalies)
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/anomalies", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a detector, got %d", rec.Code)
+	}
+
+	srv.anomalyDetector = health.NewAnomalyDetector(nil, time.Minute, 3, nil)
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/anomalies", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"count":0`) {
+		t.Fatalf("expected an empty anomaly list, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	srv.onAnomaly(&models.Anomaly{Metric: health.MetricTxRate, Active: true, Direction: "spike"})
+	select {
+	case msg := <-srv.broadcast:
+		event, ok := msg.(*models.StreamEvent)
+		if !ok || event.Type != "anomaly" {
+			t.Fatalf("expected anomaly event, got %#v", msg)
+		}
+	default:
+		t.Fatal("expected anomaly to be enqueued")
+	}
+}
+
+func TestNewAccountStatsEndpoint(t *testing.T) {
+	srv := newTestServer()
+	srv.newAccounts = stats.NewNewAccountTracker()
+	srv.newAccounts.ObserveTransaction(&models.Transaction{
+		CreatedAccounts: []string{"rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY"},
+		Locations:       []*models.GeoLocation{{CountryCode: "BR", City: "Sao Paulo", Latitude: -23.55, Longitude: -46.63, Role: models.LocationRoleSource}},
+	})
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/stats/new-accounts", srv.handleNewAccountStats)
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats/new-accounts?window=7d", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body models.NewAccountStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Window != "7d" || body.Total != 1 || body.Countries["BR"] != 1 || len(body.Regions) != 1 || body.Regions[0].City != "Sao Paulo" {
+		t.Fatalf("unexpected stats %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats/new-accounts?window=30d", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown window, got %d", rec.Code)
+	}
+}
+
+func TestActivityEndpoints(t *testing.T) {
+	srv := newTestServer()
+	srv.privacyMode = true
+	srv.activity = stats.NewActivity()
+	srv.activity.ObserveValidators([]*models.Validator{{Address: "nA1", IsActive: true}})
+	srv.activity.ObserveTransaction(&models.Transaction{
+		TransactionType: "Payment",
+		Amount:          "5000000",
+		Locations:       []*models.GeoLocation{{CountryCode: "BR", City: "Sao Paulo", Latitude: -23.55, Longitude: -46.63}},
+	})
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/stats", srv.handleActivityStats)
+	srv.router.GET("/stats/timeline", srv.handleActivityTimeline)
+	srv.router.GET("/hotspots", srv.handleHotspots)
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats?window=1h", nil))
+	var summary models.ActivityStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &summary); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with stats, got %d %s", rec.Code, rec.Body.String())
+	}
+	if summary.Window != "1h" || summary.Payments != 1 || summary.XRP != 5 || summary.Validators == nil || summary.Validators.Latest != 1 {
+		t.Fatalf("unexpected stats %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hotspots?limit=10", nil))
+	var hotspots models.Hotspots
+	if err := json.Unmarshal(rec.Body.Bytes(), &hotspots); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with hotspots, got %d %s", rec.Code, rec.Body.String())
+	}
+	if len(hotspots.Hotspots) != 1 || hotspots.Hotspots[0].Latitude != roundCoordinate(-23.55) {
+		t.Fatalf("expected one privacy-snapped hotspot, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats/timeline", nil))
+	var timeline models.ActivityTimeline
+	if err := json.Unmarshal(rec.Body.Bytes(), &timeline); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with a timeline, got %d %s", rec.Code, rec.Body.String())
+	}
+	if len(timeline.Points) != 1 || timeline.Points[0].Transactions != 1 || len(timeline.Points[0].Hotspots) != 1 {
+		t.Fatalf("unexpected timeline %s", rec.Body.String())
+	}
+
+	for _, path := range []string{"/stats?window=7d", "/hotspots?limit=0"} {
+		rec = httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestBurnEndpointAndEvent(t *testing.T) {
+	srv := newTestServer()
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/burn", srv.handleBurn)
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/burn", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a burn tracker, got %d", rec.Code)
+	}
+
+	srv.burn = stats.NewBurnTracker()
+	srv.burn.AddCallback(srv.onFeeBurn)
+	srv.burn.ObserveLedger(&models.LedgerFees{LedgerIndex: 100, Transactions: 3, FeeDrops: 45})
+
+	select {
+	case msg := <-srv.broadcast:
+		event, ok := msg.(*models.StreamEvent)
+		if !ok || event.Type != "fee_burn" {
+			t.Fatalf("expected fee_burn event, got %#v", msg)
+		}
+		burn, ok := event.Data.(*models.FeeBurn)
+		if !ok || burn.LedgerIndex != 100 || burn.CumulativeDrops != 45 {
+			t.Fatalf("unexpected fee_burn data %#v", event.Data)
+		}
+	default:
+		t.Fatal("expected fee_burn event to be enqueued")
+	}
+
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/burn", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body models.BurnTotals
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Drops != 45 || body.LastLedgerIndex != 100 || body.Windows["1h"] == nil || body.Windows["1h"].Ledgers != 1 {
+		t.Fatalf("unexpected totals %s", rec.Body.String())
+	}
+}
+
+func TestOnValidatorUpdateEnqueuesEventPerValidator(t *testing.T) {
+	srv := newTestServer()
+
+	srv.onValidatorUpdate(&models.ValidatorUpdate{
+		Upserts:  []*models.ValidatorDelta{{Address: "nA1", Fields: map[string]interface{}{"city": "Paris"}}},
+		Removals: []*models.ValidatorDelta{{Address: "nA2"}},
+	})
+
+	want := []struct {
+		eventType string
+		address   string
+	}{
+		{"validator_upsert", "nA1"},
+		{"validator_remove", "nA2"},
+	}
+	for _, expected := range want {
+		select {
+		case msg := <-srv.broadcast:
+			event, ok := msg.(*models.StreamEvent)
+			if !ok {
+				t.Fatalf("expected stream event, got %T", msg)
+			}
+			delta, ok := event.Data.(*models.ValidatorDelta)
+			if event.Type != expected.eventType || !ok || delta.Address != expected.address {
+				t.Fatalf("expected %s for %s, got %s with %#v", expected.eventType, expected.address, event.Type, event.Data)
+			}
+		default:
+			t.Fatalf("expected %s event to be enqueued", expected.eventType)
+		}
+	}
+}
+
+func TestOnTxGeoUpdateEnqueuesEvent(t *testing.T) {
+	srv := newTestServer()
+
+	srv.onTxGeoUpdate(&models.TxGeoUpdate{Hash: "ABC", Locations: []*models.GeoLocation{{Latitude: 1, Longitude: 2}}})
+
+	select {
+	case msg := <-srv.broadcast:
+		event, ok := msg.(*models.StreamEvent)
+		if !ok || event.Type != "tx_geo_update" {
+			t.Fatalf("expected tx_geo_update event, got %#v", msg)
+		}
+	default:
+		t.Fatal("expected tx geo update event to be enqueued")
+	}
+}
+
+func TestTrySendAfterCloseDoesNotPanic(t *testing.T) {
+	srv := newTestServer()
+	client := &WSClient{
+		send:   make(chan interface{}, 1),
+		server: srv,
+	}
+	srv.wsClients[client] = true
+
+	srv.closeClient(client)
+
+	if !client.trySend(&models.Transaction{Hash: "ABC"}) {
+		t.Fatal("expected send to closed client to be ignored, not reported as full")
+	}
+}
+
+func TestValidatorDomainHistoryEndpoint(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{histories: map[string]*models.DomainHistory{
+		"nA1": {
+			Address:       "nA1",
+			CurrentDomain: "b.example",
+			Changes: []*models.DomainChange{
+				{NewDomain: "a.example", Source: "validator_list", ChangedAt: 100},
+				{OldDomain: "a.example", NewDomain: "b.example", Source: "secondary_registry", ChangedAt: 200},
+			},
+		},
+	}}
+	gin.SetMode(gin.TestMode)
+	srv.router = gin.New()
+	srv.router.GET("/validators/:address/domain-history", srv.handleValidatorDomainHistory)
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators/nA1/domain-history", nil))
+	var history models.DomainHistory
+	if err := json.Unmarshal(rec.Body.Bytes(), &history); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with history, got %d %s", rec.Code, rec.Body.String())
+	}
+	if len(history.Changes) != 2 || history.Changes[1].OldDomain != "a.example" {
+		t.Fatalf("unexpected history %+v", history)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators/nUnknown/domain-history", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown validator, got %d", rec.Code)
+	}
+
+	// Upstream errors are logged, not returned.
+	srv.validatorFetcher.(*staticValidators).historyErr = errors.New("dial tcp 10.0.0.5:51234: connection refused")
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators/nUnknown/domain-history", nil))
+	if rec.Code != http.StatusServiceUnavailable || strings.Contains(rec.Body.String(), "10.0.0.5") {
+		t.Fatalf("expected 503 without the upstream error, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestIngestOnlyServesMirroredEndpoints(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	srv := NewServer(&staticValidators{}, &upstreamListener{}, "127.0.0.1", 0, []string{"http://localhost:3000"}, 16, 16, logger, ServerOptions{IngestOnly: true})
+	defer srv.Stop(context.Background())
+
+	cases := map[string]int{
+		"/health":                   http.StatusOK,
+		"/validators":               http.StatusOK,
+		"/transactions/recent":      http.StatusNotFound,
+		"/validators.geojson":       http.StatusNotFound,
+		"/stats/new-accounts":       http.StatusNotFound,
+		"/operators":                http.StatusNotFound,
+		"/decentralization/compare": http.StatusNotFound,
+	}
+	for path, want := range cases {
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+}
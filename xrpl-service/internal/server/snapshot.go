@@ -0,0 +1,109 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// SnapshotSource hashes the validator set. It is implemented by
+// validator.Fetcher; with it, validator ETags follow the content rather than
+// the fetch time and serialized responses are reused until it changes.
+type SnapshotSource interface {
+	SnapshotHash() string
+}
+
+// SourceStatusSource reports which validator sources the current snapshot
+// was built from. It is implemented by validator.Fetcher.
+type SourceStatusSource interface {
+	SourceStatus() *models.SourceStatus
+}
+
+// ProvisionalSource reports whether the validator set is a provisional one
+// restored from the metadata cache at startup, by returning when that set
+// was fetched, or the zero time once a fetch has completed. It is
+// implemented by validator.Fetcher.
+type ProvisionalSource interface {
+	ProvisionalSince() time.Time
+}
+
+// snapshotBodies holds serialized validator responses for one snapshot hash.
+type snapshotBodies struct {
+	mu     sync.Mutex
+	hash   string
+	bodies map[string]snapshotBody
+}
+
+type snapshotBody struct {
+	data  []byte
+	count int
+}
+
+// validatorSnapshotHash returns the validator source's snapshot hash, or ""
+// if it does not hash its snapshots.
+func (s *Server) validatorSnapshotHash() string {
+	if source, ok := s.validatorFetcher.(SnapshotSource); ok {
+		return source.SnapshotHash()
+	}
+	return ""
+}
+
+// validatorProvisionalSince returns when the provisional validator set was
+// fetched, or the zero time if the set is not provisional.
+func (s *Server) validatorProvisionalSince() time.Time {
+	if source, ok := s.validatorFetcher.(ProvisionalSource); ok {
+		return source.ProvisionalSince()
+	}
+	return time.Time{}
+}
+
+// validatorsETag returns the ETag of a validator representation: the
+// snapshot hash when available, otherwise the last update time and count.
+// A provisional set has its own ETags, so clients revalidating once the
+// first fetch has completed get the response without the provisional flag
+// even if the validators are unchanged.
+func (s *Server) validatorsETag(kind, hash string) string {
+	if !s.validatorProvisionalSince().IsZero() {
+		kind += "-provisional"
+	}
+	if hash != "" {
+		return fmt.Sprintf("W/\"%s-%.16s\"", kind, hash)
+	}
+	lastUpdate := s.validatorFetcher.GetLastUpdate()
+	return fmt.Sprintf("W/\"%s-%d-%d\"", kind, lastUpdate.UnixNano(), len(s.validatorFetcher.GetValidators()))
+}
+
+// validatorBody returns the public validators in v and preset serialized by
+// encode, and their count. With a snapshot hash the result is kept per view,
+// preset and kind until the hash changes, so quiet refresh cycles do not re-serialize
+// the set.
+func (s *Server) validatorBody(v *view, preset *validatorPreset, kind, hash string, encode func([]*models.Validator) ([]byte, error)) ([]byte, int, error) {
+	kind = preset.kind(v.kind(kind))
+	if hash != "" {
+		s.snapshotBodies.mu.Lock()
+		body, ok := s.snapshotBodies.bodies[kind]
+		current := s.snapshotBodies.hash == hash
+		s.snapshotBodies.mu.Unlock()
+		if ok && current {
+			return body.data, body.count, nil
+		}
+	}
+
+	validators := preset.filterValidators(v.filterValidators(s.publicValidators()))
+	data, err := encode(validators)
+	if err != nil {
+		return nil, 0, err
+	}
+	if hash != "" {
+		s.snapshotBodies.mu.Lock()
+		if s.snapshotBodies.hash != hash {
+			s.snapshotBodies.hash = hash
+			s.snapshotBodies.bodies = make(map[string]snapshotBody)
+		}
+		s.snapshotBodies.bodies[kind] = snapshotBody{data: data, count: len(validators)}
+		s.snapshotBodies.mu.Unlock()
+	}
+	return data, len(validators), nil
+}
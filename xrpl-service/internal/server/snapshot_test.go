@@ -0,0 +1,166 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+type hashedValidators struct {
+	staticValidators
+	hash string
+}
+
+func (h *hashedValidators) SnapshotHash() string { return h.hash }
+
+func TestValidatorsReusedUntilSnapshotChanges(t *testing.T) {
+	source := &hashedValidators{
+		staticValidators: staticValidators{validators: []*models.Validator{{Address: "nA1", Domain: "a.example"}}},
+		hash:             "0123456789abcdef0123456789abcdef",
+	}
+	srv := newTestServer()
+	srv.validatorFetcher = source
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validators", srv.handleGetValidators)
+
+	get := func(etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/validators", nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	domains := func(rec *httptest.ResponseRecorder) []string {
+		var payload struct {
+			Validators []models.Validator `json:"validators"`
+			Count      int                `json:"count"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if payload.Count != len(payload.Validators) {
+			t.Fatalf("count %d does not match %d validators", payload.Count, len(payload.Validators))
+		}
+		var out []string
+		for _, v := range payload.Validators {
+			out = append(out, v.Domain)
+		}
+		return out
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if etag != `W/"validators-0123456789abcdef"` {
+		t.Fatalf("expected an ETag from the snapshot hash, got %q", etag)
+	}
+	if rec := get(etag); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for the same snapshot, got %d", rec.Code)
+	}
+
+	// While the hash holds, the serialized body is reused.
+	source.validators = []*models.Validator{{Address: "nA1", Domain: "changed.example"}}
+	if got := domains(get("")); len(got) != 1 || got[0] != "a.example" {
+		t.Fatalf("expected the cached body, got %v", got)
+	}
+
+	source.hash = "fedcba9876543210fedcba9876543210"
+	rec := get(etag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("expected a new snapshot to change the ETag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+	if got := domains(rec); len(got) != 1 || got[0] != "changed.example" {
+		t.Fatalf("expected the new snapshot, got %v", got)
+	}
+}
+
+type statusedValidators struct {
+	staticValidators
+	status *models.SourceStatus
+}
+
+func (s *statusedValidators) SourceStatus() *models.SourceStatus { return s.status }
+
+func TestValidatorsReportSourceStatus(t *testing.T) {
+	source := &statusedValidators{
+		staticValidators: staticValidators{validators: []*models.Validator{{Address: "nA1"}}},
+	}
+	srv := newTestServer()
+	srv.validatorFetcher = source
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validators", srv.handleGetValidators)
+
+	get := func() map[string]json.RawMessage {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators", nil))
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return payload
+	}
+
+	if payload := get(); payload["sources_ok"] != nil || payload["sources_failed"] != nil {
+		t.Fatalf("expected no source status before the first cycle, got %v", payload)
+	}
+	source.status = &models.SourceStatus{OK: []string{"trusted_validators"}, Failed: []string{"validator_list"}}
+	payload := get()
+	if string(payload["sources_ok"]) != `["trusted_validators"]` || string(payload["sources_failed"]) != `["validator_list"]` {
+		t.Fatalf("expected the source status, got ok=%s failed=%s", payload["sources_ok"], payload["sources_failed"])
+	}
+}
+
+type provisionalValidators struct {
+	hashedValidators
+	since time.Time
+}
+
+func (p *provisionalValidators) ProvisionalSince() time.Time { return p.since }
+
+func TestValidatorsFlagProvisionalSnapshot(t *testing.T) {
+	source := &provisionalValidators{
+		hashedValidators: hashedValidators{
+			staticValidators: staticValidators{validators: []*models.Validator{{Address: "nA1"}}},
+			hash:             "0123456789abcdef0123456789abcdef",
+		},
+		since: time.Unix(1_700_000_000, 0),
+	}
+	srv := newTestServer()
+	srv.validatorFetcher = source
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validators", srv.handleGetValidators)
+
+	get := func() (*httptest.ResponseRecorder, map[string]json.RawMessage) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validators", nil))
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return rec, payload
+	}
+
+	rec, payload := get()
+	if string(payload["provisional"]) != "true" || string(payload["timestamp"]) != `"`+source.since.Format(time.RFC3339Nano)+`"` {
+		t.Fatalf("expected a provisional response stamped with the cached fetch, got %s", rec.Body.String())
+	}
+	provisionalETag := rec.Header().Get("ETag")
+
+	source.since = time.Time{}
+	rec, payload = get()
+	if payload["provisional"] != nil {
+		t.Fatalf("expected no provisional flag after the first fetch, got %s", rec.Body.String())
+	}
+	if rec.Header().Get("ETag") == provisionalETag {
+		t.Fatal("expected the provisional response to have its own ETag")
+	}
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newAccountWindows are the windows /stats/new-accounts accepts.
+var newAccountWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+}
+
+// handleNewAccountStats returns accounts created per funding region over
+// ?window= (1h, 24h or 7d, default 24h).
+func (s *Server) handleNewAccountStats(c *gin.Context) {
+	if s.newAccounts == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "new account tracking is not configured"})
+		return
+	}
+	label := c.DefaultQuery("window", "24h")
+	window, ok := newAccountWindows[label]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "window must be 1h, 24h or 7d"})
+		return
+	}
+
+	stats := s.newAccounts.NewAccounts(window, label)
+	stats.Freshness = s.setFreshness(c, s.newAccounts.LastUpdate(), dataSourceStream)
+	for _, region := range stats.Regions {
+		if s.privacyMode {
+			region.Latitude = roundCoordinate(region.Latitude)
+			region.Longitude = roundCoordinate(region.Longitude)
+		}
+		region.Latitude = roundToPrecision(region.Latitude, s.coordinatePrecision)
+		region.Longitude = roundToPrecision(region.Longitude, s.coordinatePrecision)
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// handleDistributions returns the type, size, memo and ledger object
+// composition of every streamed transaction since the service started.
+func (s *Server) handleDistributions(c *gin.Context) {
+	if s.distributions == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction distributions are not configured"})
+		return
+	}
+	distributions := s.distributions.Snapshot()
+	distributions.Freshness = s.setFreshness(c, s.distributions.LastUpdate(), dataSourceStream)
+	c.JSON(http.StatusOK, distributions)
+}
+
+// handleBurn returns the transaction fees destroyed since the service
+// started and the rolling burn rates.
+func (s *Server) handleBurn(c *gin.Context) {
+	if s.burn == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "fee burn tracking is not configured"})
+		return
+	}
+	totals := s.burn.Totals()
+	totals.Freshness = s.setFreshness(c, s.burn.LastUpdate(), dataSourceStream)
+	c.JSON(http.StatusOK, totals)
+}
+
+// activityWindows are the windows /stats, /stats/timeline and /hotspots
+// accept.
+var activityWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"6h":  6 * time.Hour,
+	"24h": 24 * time.Hour,
+}
+
+const (
+	defaultHotspotLimit = 50
+	maxHotspotLimit     = 500
+)
+
+// activityWindow resolves ?window= (1h, 6h or 24h, default 24h), answering
+// 404 when activity tracking is off and 400 for other windows.
+func (s *Server) activityWindow(c *gin.Context) (time.Duration, string, bool) {
+	if s.activity == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "activity tracking is not configured"})
+		return 0, "", false
+	}
+	label := c.DefaultQuery("window", "24h")
+	window, ok := activityWindows[label]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "window must be 1h, 6h or 24h"})
+		return 0, "", false
+	}
+	return window, label, true
+}
+
+// handleActivityStats returns transaction totals and the validator set
+// range over ?window=.
+func (s *Server) handleActivityStats(c *gin.Context) {
+	window, label, ok := s.activityWindow(c)
+	if !ok {
+		return
+	}
+	stats := s.activity.Stats(window, label)
+	stats.Freshness = s.setFreshness(c, s.activity.LastUpdate(), dataSourceStream)
+	c.JSON(http.StatusOK, stats)
+}
+
+// handleActivityTimeline returns the per-minute activity of ?window=,
+// oldest first, for replay.
+func (s *Server) handleActivityTimeline(c *gin.Context) {
+	window, label, ok := s.activityWindow(c)
+	if !ok {
+		return
+	}
+	timeline := s.activity.Timeline(window, label)
+	timeline.Freshness = s.setFreshness(c, s.activity.LastUpdate(), dataSourceStream)
+	for _, point := range timeline.Points {
+		s.publishHotspots(point.Hotspots)
+	}
+	c.JSON(http.StatusOK, timeline)
+}
+
+// handleHotspots returns the busiest cities over ?window=, at most ?limit=
+// (default 50, at most 500).
+func (s *Server) handleHotspots(c *gin.Context) {
+	window, label, ok := s.activityWindow(c)
+	if !ok {
+		return
+	}
+	limit := defaultHotspotLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = min(parsed, maxHotspotLimit)
+	}
+	hotspots := s.activity.Hotspots(window, label, limit)
+	hotspots.Freshness = s.setFreshness(c, s.activity.LastUpdate(), dataSourceStream)
+	s.publishHotspots(hotspots.Hotspots)
+	c.JSON(http.StatusOK, hotspots)
+}
+
+// publishHotspots applies privacy mode and the coordinate precision to
+// hotspot coordinates.
+func (s *Server) publishHotspots(hotspots []*models.Hotspot) {
+	for _, hotspot := range hotspots {
+		if s.privacyMode {
+			hotspot.Latitude = roundCoordinate(hotspot.Latitude)
+			hotspot.Longitude = roundCoordinate(hotspot.Longitude)
+		}
+		hotspot.Latitude = roundToPrecision(hotspot.Latitude, s.coordinatePrecision)
+		hotspot.Longitude = roundToPrecision(hotspot.Longitude, s.coordinatePrecision)
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gorilla/websocket"
+)
+
+// supportedSubprotocols are the /transactions subprotocols, newest first.
+// The upgrader picks the first of them the client offers, so clients list
+// every version they speak and get the newest.
+var supportedSubprotocols = []string{models.StreamProtocolV2, models.StreamProtocolV1}
+
+// wsProtocol is the encoding and feature set of a stream subprotocol.
+type wsProtocol struct {
+	name string
+
+	// envelope sends transactions as "transaction" stream events, so that
+	// every frame carries a type.
+	envelope bool
+
+	// hello opens the stream with a "hello" event describing it.
+	hello bool
+}
+
+var wsProtocols = map[string]*wsProtocol{
+	models.StreamProtocolV1: {name: models.StreamProtocolV1},
+	models.StreamProtocolV2: {name: models.StreamProtocolV2, envelope: true, hello: true},
+}
+
+// protocolFor returns the protocol negotiated during the upgrade. Clients
+// that offered none get v1.
+func protocolFor(negotiated string) *wsProtocol {
+	if protocol, ok := wsProtocols[negotiated]; ok {
+		return protocol
+	}
+	return wsProtocols[models.StreamProtocolV1]
+}
+
+// offersSupportedSubprotocol reports whether a client that offers
+// subprotocols offers one the server speaks. Such clients would fail the
+// connection after an upgrade that selects none, so they are turned away
+// with a readable error instead.
+func offersSupportedSubprotocol(r *http.Request) bool {
+	offered := websocket.Subprotocols(r)
+	if len(offered) == 0 {
+		return true
+	}
+	for _, name := range offered {
+		if _, ok := wsProtocols[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// frameTransaction wraps an encoded transaction for the wire.
+func (p *wsProtocol) frameTransaction(data []byte, now time.Time) ([]byte, error) {
+	if p == nil || !p.envelope {
+		return data, nil
+	}
+	return json.Marshal(&models.StreamEvent{Type: "transaction", Timestamp: now.Unix(), Data: json.RawMessage(data)})
+}
+
+// helloEvent describes the stream to a client whose protocol opens with
+// one, or returns nil.
+func (c *WSClient) helloEvent() *models.StreamEvent {
+	if c.protocol == nil || !c.protocol.hello {
+		return nil
+	}
+	return &models.StreamEvent{
+		Type:      "hello",
+		Timestamp: c.server.clock.Now().Unix(),
+		Data: &models.StreamHello{
+			Protocol:      c.protocol.name,
+			Protocols:     supportedSubprotocols,
+			ServerVersion: c.server.build.Version,
+			Shape:         c.shape,
+			Topics:        topicNames(c.currentTopics()),
+		},
+	}
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+func newSubprotocolServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	srv := NewServer(&staticValidators{}, &upstreamListener{}, "127.0.0.1", 0, []string{"http://localhost:3000"}, 16, 16, logger)
+	httpSrv := httptest.NewServer(srv.router)
+	t.Cleanup(func() {
+		httpSrv.Close()
+		srv.Stop(context.Background())
+	})
+	return srv, "ws" + strings.TrimPrefix(httpSrv.URL, "http") + "/transactions"
+}
+
+func dialSubprotocols(t *testing.T, url string, protocols ...string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	dialer := websocket.Dialer{HandshakeTimeout: 2 * time.Second, Subprotocols: protocols}
+	conn, resp, err := dialer.Dial(url, http.Header{"Origin": []string{"http://localhost:3000"}})
+	if conn != nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+	return conn, resp, err
+}
+
+func waitForClients(t *testing.T, srv *Server, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.websocketClientCount() < n && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if srv.websocketClientCount() < n {
+		t.Fatalf("expected %d WebSocket clients, got %d", n, srv.websocketClientCount())
+	}
+}
+
+func TestWebSocketSubprotocolNegotiation(t *testing.T) {
+	_, url := newSubprotocolServer(t)
+	cases := []struct {
+		offered []string
+		want    string
+	}{
+		{offered: []string{models.StreamProtocolV1, models.StreamProtocolV2}, want: models.StreamProtocolV2},
+		{offered: []string{"xrplviz.v9", models.StreamProtocolV1}, want: models.StreamProtocolV1},
+		{offered: nil, want: ""},
+	}
+	for _, tc := range cases {
+		conn, _, err := dialSubprotocols(t, url, tc.offered...)
+		if err != nil {
+			t.Fatalf("%v: dial failed: %v", tc.offered, err)
+		}
+		if got := conn.Subprotocol(); got != tc.want {
+			t.Errorf("%v: expected %q, got %q", tc.offered, tc.want, got)
+		}
+	}
+
+	_, resp, err := dialSubprotocols(t, url, "xrplviz.v9")
+	if err == nil || resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected an unsupported subprotocol to be rejected with 400, got %v (%v)", resp, err)
+	}
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body["error"] != "unsupported subprotocol" {
+		t.Fatalf("expected an unsupported subprotocol error, got %v (%v)", body, err)
+	}
+}
+
+func TestWebSocketSubprotocolFraming(t *testing.T) {
+	srv, url := newSubprotocolServer(t)
+	v1, _, err := dialSubprotocols(t, url, models.StreamProtocolV1)
+	if err != nil {
+		t.Fatalf("v1 dial failed: %v", err)
+	}
+	v2, _, err := dialSubprotocols(t, url+"?topics=country:US->*", models.StreamProtocolV2)
+	if err != nil {
+		t.Fatalf("v2 dial failed: %v", err)
+	}
+	waitForClients(t, srv, 2)
+
+	v2.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var hello struct {
+		Type string             `json:"type"`
+		Data models.StreamHello `json:"data"`
+	}
+	if err := v2.ReadJSON(&hello); err != nil {
+		t.Fatalf("failed to read hello: %v", err)
+	}
+	if hello.Type != "hello" || hello.Data.Protocol != models.StreamProtocolV2 || len(hello.Data.Protocols) != 2 {
+		t.Fatalf("unexpected hello: %+v", hello)
+	}
+	if len(hello.Data.Topics) != 1 || hello.Data.Topics[0] != "country:US->*" {
+		t.Fatalf("expected the hello to list the client's topics, got %v", hello.Data.Topics)
+	}
+
+	srv.onTransaction(&models.Transaction{Hash: "FRAMED", Locations: []*models.GeoLocation{{CountryCode: "US"}}})
+
+	v1.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var bare models.Transaction
+	if err := v1.ReadJSON(&bare); err != nil || bare.Hash != "FRAMED" {
+		t.Fatalf("expected a bare transaction on v1, got %+v (%v)", bare, err)
+	}
+
+	v2.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var framed struct {
+		Type string             `json:"type"`
+		Data models.Transaction `json:"data"`
+	}
+	if err := v2.ReadJSON(&framed); err != nil {
+		t.Fatalf("failed to read v2 transaction: %v", err)
+	}
+	if framed.Type != "transaction" || framed.Data.Hash != "FRAMED" {
+		t.Fatalf("expected a transaction event on v2, got %+v", framed)
+	}
+}
@@ -0,0 +1,284 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// Corridor topic kinds. A topic names a flow as "<kind>:<source>-><destination>",
+// where either side may be "*", e.g. country:US->JP or account:rExchange...->*.
+const (
+	topicKindCountry = "country"
+	topicKindAccount = "account"
+	topicWildcard    = "*"
+)
+
+const (
+	// maxClientTopics bounds the topics one WebSocket client subscribes to.
+	maxClientTopics = 32
+
+	// maxClientMessageBytes bounds messages read from WebSocket clients,
+	// which only send topic requests.
+	maxClientMessageBytes = 8192
+
+	// topicDeliveredHashes is how many transactions sent to a client are
+	// remembered, so that their tx_geo_update events reach it too.
+	topicDeliveredHashes = 256
+)
+
+// topic is a parsed corridor topic.
+type topic struct {
+	name        string
+	kind        string
+	source      string
+	destination string
+}
+
+// parseTopic parses a corridor topic. Country codes are uppercased; account
+// topics are rejected in privacy mode, where addresses are truncated before
+// matching.
+func parseTopic(name string, privacyMode bool) (topic, error) {
+	name = strings.TrimSpace(name)
+	kind, corridor, ok := strings.Cut(name, ":")
+	if !ok {
+		return topic{}, fmt.Errorf("topic %q must be country:<from>-><to> or account:<from>-><to>", name)
+	}
+	source, destination, ok := strings.Cut(corridor, "->")
+	if !ok {
+		return topic{}, fmt.Errorf("topic %q must separate its ends with ->", name)
+	}
+	t := topic{kind: kind, source: strings.TrimSpace(source), destination: strings.TrimSpace(destination)}
+	if t.source == topicWildcard && t.destination == topicWildcard {
+		return topic{}, fmt.Errorf("topic %q must name at least one end", name)
+	}
+	switch kind {
+	case topicKindCountry:
+		t.source, t.destination = strings.ToUpper(t.source), strings.ToUpper(t.destination)
+		for _, end := range []string{t.source, t.destination} {
+			if end != topicWildcard && len(end) != 2 {
+				return topic{}, fmt.Errorf("topic %q: %q is not an ISO 3166-1 alpha-2 code or *", name, end)
+			}
+		}
+	case topicKindAccount:
+		if privacyMode {
+			return topic{}, fmt.Errorf("topic %q: account topics are not available in privacy mode", name)
+		}
+		for _, end := range []string{t.source, t.destination} {
+			if end != topicWildcard && (!strings.HasPrefix(end, "r") || len(end) < 25 || len(end) > 35) {
+				return topic{}, fmt.Errorf("topic %q: %q is not a classic address or *", name, end)
+			}
+		}
+	default:
+		return topic{}, fmt.Errorf("topic %q: kind must be %s or %s", name, topicKindCountry, topicKindAccount)
+	}
+	t.name = t.kind + ":" + t.source + "->" + t.destination
+	return t, nil
+}
+
+// parseTopics parses a list of topics, dropping duplicates.
+func parseTopics(names []string, privacyMode bool) ([]topic, error) {
+	topics := make([]topic, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		t, err := parseTopic(name, privacyMode)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[t.name]; ok {
+			continue
+		}
+		seen[t.name] = struct{}{}
+		topics = append(topics, t)
+	}
+	return topics, nil
+}
+
+// queryTopics parses the comma-separated topics query parameter of a
+// WebSocket connection.
+func (s *Server) queryTopics(raw string) ([]topic, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	topics, err := parseTopics(strings.Split(raw, ","), s.privacyMode)
+	if err != nil {
+		return nil, err
+	}
+	if len(topics) > maxClientTopics {
+		return nil, fmt.Errorf("at most %d topics per client", maxClientTopics)
+	}
+	return topics, nil
+}
+
+func endMatches(want, got string) bool {
+	return want == topicWildcard || want == got
+}
+
+// matches reports whether tx flows along the topic's corridor. Country
+// topics use the locations known when tx is broadcast.
+func (t topic) matches(tx *models.Transaction) bool {
+	if t.kind == topicKindAccount {
+		return endMatches(t.source, tx.Account) && endMatches(t.destination, tx.Destination)
+	}
+	return t.matchesLocations(tx)
+}
+
+func (t topic) matchesLocations(tx *models.Transaction) bool {
+	if t.kind != topicKindCountry {
+		return false
+	}
+	source, destination, _ := tx.RoleInfo()
+	sourceCountry, destinationCountry := "", ""
+	if source != nil {
+		sourceCountry = source.CountryCode
+	}
+	if destination != nil {
+		destinationCountry = destination.CountryCode
+	}
+	return endMatches(t.source, sourceCountry) && endMatches(t.destination, destinationCountry)
+}
+
+// topicFilter is a client's subscribed topics. Without topics it passes
+// everything. Transactions pass when they match a topic, tx_geo_update
+// events when their transaction was sent to the client or their locations
+// match a country topic, and other events always. It is only used from
+// broadcastLoop apart from the topics, which the client swaps atomically.
+type topicFilter struct {
+	delivered map[string]struct{}
+	order     []string // delivered hashes, oldest first
+}
+
+func (f *topicFilter) allows(topics []topic, msg interface{}) bool {
+	if len(topics) == 0 {
+		return true
+	}
+	switch typed := msg.(type) {
+	case *models.Transaction:
+		for _, t := range topics {
+			if t.matches(typed) {
+				f.remember(typed.Hash)
+				return true
+			}
+		}
+		return false
+	case *models.StreamEvent:
+		update, ok := typed.Data.(*models.TxGeoUpdate)
+		if !ok {
+			return true
+		}
+		if _, ok := f.delivered[update.Hash]; ok {
+			return true
+		}
+		located := &models.Transaction{Locations: update.Locations}
+		for _, t := range topics {
+			if t.matchesLocations(located) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (f *topicFilter) remember(hash string) {
+	if hash == "" {
+		return
+	}
+	if f.delivered == nil {
+		f.delivered = make(map[string]struct{}, topicDeliveredHashes)
+	}
+	if _, ok := f.delivered[hash]; ok {
+		return
+	}
+	f.delivered[hash] = struct{}{}
+	f.order = append(f.order, hash)
+	if len(f.order) > topicDeliveredHashes {
+		delete(f.delivered, f.order[0])
+		f.order = f.order[1:]
+	}
+}
+
+// topicNames returns the names of topics, sorted.
+func topicNames(topics []topic) []string {
+	names := make([]string, 0, len(topics))
+	for _, t := range topics {
+		names = append(names, t.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// topicRequest is a client message changing its topics, e.g.
+// {"action":"subscribe","topics":["country:US->JP"]}.
+type topicRequest struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics"`
+}
+
+// Topic request actions.
+const (
+	topicActionSubscribe   = "subscribe"
+	topicActionUnsubscribe = "unsubscribe"
+)
+
+// handleTopicRequest applies a client message to the client's topics and
+// returns the reply: a "topics" event listing the current topics, or a
+// "topics_error" event.
+func (c *WSClient) handleTopicRequest(message []byte) *models.StreamEvent {
+	now := c.server.clock.Now().Unix()
+	var request topicRequest
+	if err := json.Unmarshal(message, &request); err != nil {
+		return &models.StreamEvent{Type: "topics_error", Timestamp: now, Data: map[string]string{"error": "message must be {\"action\": \"subscribe\"|\"unsubscribe\", \"topics\": [...]}"}}
+	}
+	parsed, err := parseTopics(request.Topics, c.server.privacyMode)
+	if err != nil {
+		return &models.StreamEvent{Type: "topics_error", Timestamp: now, Data: map[string]string{"error": err.Error()}}
+	}
+
+	current := c.currentTopics()
+	var next []topic
+	switch request.Action {
+	case topicActionSubscribe:
+		next = append(next, current...)
+		have := make(map[string]struct{}, len(current))
+		for _, t := range current {
+			have[t.name] = struct{}{}
+		}
+		for _, t := range parsed {
+			if _, ok := have[t.name]; !ok {
+				next = append(next, t)
+			}
+		}
+	case topicActionUnsubscribe:
+		drop := make(map[string]struct{}, len(parsed))
+		for _, t := range parsed {
+			drop[t.name] = struct{}{}
+		}
+		for _, t := range current {
+			if _, ok := drop[t.name]; !ok {
+				next = append(next, t)
+			}
+		}
+	default:
+		return &models.StreamEvent{Type: "topics_error", Timestamp: now, Data: map[string]string{"error": "action must be subscribe or unsubscribe"}}
+	}
+	if len(next) > maxClientTopics {
+		return &models.StreamEvent{Type: "topics_error", Timestamp: now, Data: map[string]string{"error": fmt.Sprintf("at most %d topics per client", maxClientTopics)}}
+	}
+	c.topics.Store(&next)
+	return &models.StreamEvent{Type: "topics", Timestamp: now, Data: map[string][]string{"topics": topicNames(next)}}
+}
+
+// currentTopics returns the client's topics.
+func (c *WSClient) currentTopics() []topic {
+	if topics := c.topics.Load(); topics != nil {
+		return *topics
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestParseTopic(t *testing.T) {
+	exchange := "rExchange1111111111111111111111"
+	cases := []struct {
+		name    string
+		privacy bool
+		want    string
+		wantErr bool
+	}{
+		{name: "country:us->jp", want: "country:US->JP"},
+		{name: " country:US->* ", want: "country:US->*"},
+		{name: "account:" + exchange + "->*", want: "account:" + exchange + "->*"},
+		{name: "account:" + exchange + "->*", privacy: true, wantErr: true},
+		{name: "country:*->*", wantErr: true},
+		{name: "country:USA->JP", wantErr: true},
+		{name: "account:xyz->*", wantErr: true},
+		{name: "ledger:US->JP", wantErr: true},
+		{name: "country:US", wantErr: true},
+		{name: "US->JP", wantErr: true},
+	}
+	for _, tc := range cases {
+		got, err := parseTopic(tc.name, tc.privacy)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %q", tc.name, got.name)
+			}
+			continue
+		}
+		if err != nil || got.name != tc.want {
+			t.Errorf("%q: expected %q, got %q (%v)", tc.name, tc.want, got.name, err)
+		}
+	}
+}
+
+func TestTopicFilterAllows(t *testing.T) {
+	exchange := "rExchange1111111111111111111111"
+	topics, err := parseTopics([]string{"country:US->JP", "account:" + exchange + "->*"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usToJP := []*models.GeoLocation{{CountryCode: "US"}, {CountryCode: "JP"}}
+	jpToUS := []*models.GeoLocation{{CountryCode: "JP"}, {CountryCode: "US"}}
+
+	var filter topicFilter
+	cases := []struct {
+		name string
+		msg  interface{}
+		want bool
+	}{
+		{"US to JP payment", &models.Transaction{Hash: "A", Locations: usToJP}, true},
+		{"JP to US payment", &models.Transaction{Hash: "B", Locations: jpToUS}, false},
+		{"payment from the exchange", &models.Transaction{Hash: "C", Account: exchange, Destination: "rOther"}, true},
+		{"payment to the exchange", &models.Transaction{Hash: "D", Account: "rOther", Destination: exchange}, false},
+		{"geo update of a delivered payment", &models.StreamEvent{Type: "tx_geo_update", Data: &models.TxGeoUpdate{Hash: "C", Locations: jpToUS}}, true},
+		{"geo update into the corridor", &models.StreamEvent{Type: "tx_geo_update", Data: &models.TxGeoUpdate{Hash: "E", Locations: usToJP}}, true},
+		{"geo update outside the corridor", &models.StreamEvent{Type: "tx_geo_update", Data: &models.TxGeoUpdate{Hash: "B", Locations: jpToUS}}, false},
+		{"other event", &models.StreamEvent{Type: "server_status"}, true},
+	}
+	for _, tc := range cases {
+		if got := filter.allows(topics, tc.msg); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+	if !filter.allows(nil, &models.Transaction{Hash: "F"}) {
+		t.Fatal("expected a client without topics to receive everything")
+	}
+}
+
+func TestTopicFilterForgetsOldestDeliveredHashes(t *testing.T) {
+	var filter topicFilter
+	for i := 0; i <= topicDeliveredHashes; i++ {
+		filter.remember(strconv.Itoa(i))
+	}
+	if len(filter.delivered) != topicDeliveredHashes || len(filter.order) != topicDeliveredHashes {
+		t.Fatalf("expected %d remembered hashes, got %d/%d", topicDeliveredHashes, len(filter.delivered), len(filter.order))
+	}
+}
+
+func TestHandleTopicRequest(t *testing.T) {
+	client := &WSClient{server: newTestServer()}
+	reply := func(message string) (string, map[string]interface{}) {
+		event := client.handleTopicRequest([]byte(message))
+		data, _ := json.Marshal(event.Data)
+		var payload map[string]interface{}
+		json.Unmarshal(data, &payload)
+		return event.Type, payload
+	}
+
+	kind, payload := reply(`{"action":"subscribe","topics":["country:us->jp","country:US->DE"]}`)
+	if kind != "topics" || len(payload["topics"].([]interface{})) != 2 {
+		t.Fatalf("expected two topics, got %s %v", kind, payload)
+	}
+	kind, payload = reply(`{"action":"subscribe","topics":["country:US->JP"]}`)
+	if kind != "topics" || len(payload["topics"].([]interface{})) != 2 {
+		t.Fatalf("expected resubscribing to be a no-op, got %s %v", kind, payload)
+	}
+	kind, payload = reply(`{"action":"unsubscribe","topics":["country:US->DE"]}`)
+	if kind != "topics" || len(payload["topics"].([]interface{})) != 1 || payload["topics"].([]interface{})[0] != "country:US->JP" {
+		t.Fatalf("expected country:US->JP to remain, got %s %v", kind, payload)
+	}
+
+	for _, message := range []string{
+		`not json`,
+		`{"action":"replace","topics":["country:US->JP"]}`,
+		`{"action":"subscribe","topics":["country:*->*"]}`,
+	} {
+		if kind, payload := reply(message); kind != "topics_error" || payload["error"] == "" {
+			t.Errorf("%s: expected topics_error, got %s %v", message, kind, payload)
+		}
+	}
+	if topics := client.currentTopics(); len(topics) != 1 {
+		t.Fatalf("expected rejected requests to keep the topics, got %v", topicNames(topics))
+	}
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleVersion identifies the serving build and instance, for bug reports
+// and for telling instances apart behind a load balancer. Public mode
+// leaves out the instance.
+func (s *Server) handleVersion(c *gin.Context) {
+	response := gin.H{
+		"version":        s.build.Version,
+		"commit":         s.build.Commit,
+		"build_time":     s.build.BuildTime,
+		"go_version":     s.build.GoVersion,
+		"started_at":     s.startedAt.Unix(),
+		"uptime_seconds": s.uptimeSeconds(),
+	}
+	if !s.publicMode {
+		response["instance_id"] = s.instanceID
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// uptimeSeconds is how long the server has been running.
+func (s *Server) uptimeSeconds() int64 {
+	return int64(s.clock.Since(s.startedAt).Seconds())
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/buildinfo"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestVersionReportsBuildAndUptime(t *testing.T) {
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	srv := newTestServer()
+	srv.clock = fake
+	srv.build = buildinfo.Build{Version: "v1.4.0", Commit: "abc123", BuildTime: "2026-01-02T03:04:05Z", GoVersion: "go1.25.0"}
+	srv.instanceID = "edge-1"
+	srv.startedAt = fake.Now()
+	fake.Advance(90 * time.Second)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/version", srv.handleVersion)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body["version"] != "v1.4.0" || body["commit"] != "abc123" || body["instance_id"] != "edge-1" {
+		t.Fatalf("unexpected build fields %v", body)
+	}
+	if body["uptime_seconds"] != float64(90) || body["started_at"] != float64(1_700_000_000) {
+		t.Fatalf("unexpected uptime fields %v", body)
+	}
+}
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// viewRoutePrefix is where views are served, as /t/{name}/...
+const viewRoutePrefix = "/t/"
+
+// viewContextKey holds the request's *view in the gin context.
+const viewContextKey = "view"
+
+// view is the resolved form of models.View. A nil view is the unfiltered
+// top-level API.
+type view struct {
+	name            string
+	allowedOrigins  []string
+	minPaymentDrops int64
+	countries       map[string]struct{}
+	channels        map[string]struct{}
+}
+
+func newView(name string, config models.View) *view {
+	resolved := &view{
+		name:            name,
+		allowedOrigins:  config.AllowedOrigins,
+		minPaymentDrops: config.MinPaymentDrops,
+	}
+	if len(config.Countries) > 0 {
+		resolved.countries = make(map[string]struct{}, len(config.Countries))
+		for _, country := range config.Countries {
+			resolved.countries[country] = struct{}{}
+		}
+	}
+	if len(config.Channels) > 0 {
+		resolved.channels = make(map[string]struct{}, len(config.Channels))
+		for _, channel := range config.Channels {
+			resolved.channels[channel] = struct{}{}
+		}
+	}
+	return resolved
+}
+
+// resolveView is the middleware of the /t/:view routes. Unknown views
+// return 404.
+func (s *Server) resolveView(c *gin.Context) {
+	v, ok := s.views[c.Param("view")]
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "view not found"})
+		return
+	}
+	c.Set(viewContextKey, v)
+	c.Next()
+}
+
+// requestView returns the view a request is served under, or nil.
+func requestView(c *gin.Context) *view {
+	v, _ := c.Get(viewContextKey)
+	resolved, _ := v.(*view)
+	return resolved
+}
+
+// originAllowed reports whether origin may call path: against the view's
+// allowed origins for its /t/{name}/ routes when it has any, otherwise
+// against CORS_ALLOWED_ORIGINS.
+func (s *Server) originAllowed(path, origin string) bool {
+	allowed := s.corsAllowedOrigins
+	if rest, ok := strings.CutPrefix(path, viewRoutePrefix); ok {
+		name, _, _ := strings.Cut(rest, "/")
+		if v, ok := s.views[name]; ok && len(v.allowedOrigins) > 0 {
+			allowed = v.allowedOrigins
+		}
+	}
+	for _, candidate := range allowed {
+		if origin == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// kind namespaces a cached representation or ETag kind by view.
+func (v *view) kind(kind string) string {
+	if v == nil {
+		return kind
+	}
+	return "t-" + v.name + "-" + kind
+}
+
+// filterValidators returns the validators in the view's countries.
+func (v *view) filterValidators(validators []*models.Validator) []*models.Validator {
+	if v == nil || v.countries == nil {
+		return validators
+	}
+	filtered := make([]*models.Validator, 0, len(validators))
+	for _, validator := range validators {
+		if validator == nil {
+			continue
+		}
+		if _, ok := v.countries[validator.CountryCode]; ok {
+			filtered = append(filtered, validator)
+		}
+	}
+	return filtered
+}
+
+// allowsTransaction reports whether tx meets the view's payment threshold
+// and has an endpoint in one of its countries.
+func (v *view) allowsTransaction(tx *models.Transaction) bool {
+	if v == nil {
+		return true
+	}
+	if v.minPaymentDrops > 0 {
+		drops, err := strconv.ParseInt(tx.Amount, 10, 64)
+		if err != nil || drops < v.minPaymentDrops {
+			return false
+		}
+	}
+	return v.inCountries(tx.Locations)
+}
+
+// inCountries reports whether any of locations is in the view's countries.
+func (v *view) inCountries(locations []*models.GeoLocation) bool {
+	if v.countries == nil {
+		return true
+	}
+	for _, location := range locations {
+		if location == nil {
+			continue
+		}
+		if _, ok := v.countries[location.CountryCode]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// allows reports whether a client of the view may receive msg. Transactions
+// are matched on the locations known when they are broadcast, tx_geo_update
+// events on the locations they add, tx_settlement events on the transaction
+// they settle and validator events on the validator's country; other events
+// are filtered by channel only.
+func (v *view) allows(msg interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if v.channels != nil {
+		if _, ok := v.channels[messageChannel(msg)]; !ok {
+			return false
+		}
+	}
+	switch typed := msg.(type) {
+	case *models.Transaction:
+		return v.allowsTransaction(typed)
+	case *models.StreamEvent:
+		switch typed.Type {
+		case "tx_geo_update":
+			if update, ok := typed.Data.(*models.TxGeoUpdate); ok {
+				return v.inCountries(update.Locations)
+			}
+		case "tx_settlement":
+			if typed.Transaction == nil {
+				return v.minPaymentDrops <= 0 && v.countries == nil
+			}
+			return v.allowsTransaction(typed.Transaction)
+		case "validator_upsert", "validator_remove", "validator_rotated":
+			return v.inCountryCodes(typed.Countries)
+		}
+	}
+	return true
+}
+
+// inCountryCodes reports whether any of codes is one of the view's
+// countries.
+func (v *view) inCountryCodes(codes []string) bool {
+	if v.countries == nil {
+		return true
+	}
+	for _, code := range codes {
+		if _, ok := v.countries[code]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// recentFor returns up to limit recent transactions allowed by v, newest
+// first.
+func (s *Server) recentFor(v *view, limit int) []*models.Transaction {
+	if v == nil {
+		return s.recent.snapshot(limit)
+	}
+	filtered := make([]*models.Transaction, 0, limit)
+	for _, tx := range s.recent.snapshot(0) {
+		if !v.allowsTransaction(tx) {
+			continue
+		}
+		filtered = append(filtered, tx)
+		if len(filtered) == limit {
+			break
+		}
+	}
+	return filtered
+}
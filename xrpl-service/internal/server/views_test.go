@@ -0,0 +1,209 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/validator"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestViewFiltersValidators(t *testing.T) {
+	srv := newTestServer()
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{
+		{Address: "nA1", CountryCode: "US"},
+		{Address: "nA2", CountryCode: "DE"},
+		{Address: "nA3", CountryCode: "CA"},
+	}}
+	srv.views = map[string]*view{"acme": newView("acme", models.View{Countries: []string{"US", "CA"}})}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validators", srv.handleGetValidators)
+	router.GET("/t/:view/validators", srv.resolveView, srv.handleGetValidators)
+
+	get := func(target string) (*httptest.ResponseRecorder, []string) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		var payload struct {
+			Validators []models.Validator `json:"validators"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &payload)
+		var addresses []string
+		for _, v := range payload.Validators {
+			addresses = append(addresses, v.Address)
+		}
+		return rec, addresses
+	}
+
+	all, addresses := get("/validators")
+	if len(addresses) != 3 {
+		t.Fatalf("expected every validator at the top level, got %v", addresses)
+	}
+	scoped, addresses := get("/t/acme/validators")
+	if scoped.Code != http.StatusOK || len(addresses) != 2 || addresses[0] != "nA1" || addresses[1] != "nA3" {
+		t.Fatalf("expected the view's countries only, got %d %v", scoped.Code, addresses)
+	}
+	if scoped.Header().Get("ETag") == all.Header().Get("ETag") {
+		t.Fatal("expected the view to have its own ETag")
+	}
+	if rec, _ := get("/t/unknown/validators"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown view, got %d", rec.Code)
+	}
+}
+
+func TestViewAllowsMessages(t *testing.T) {
+	v := newView("acme", models.View{MinPaymentDrops: 1_000_000, Countries: []string{"US"}, Channels: []string{"transactions", "tx_geo_update"}})
+	us := []*models.GeoLocation{{CountryCode: "US"}}
+	jp := []*models.GeoLocation{{CountryCode: "JP"}}
+
+	cases := []struct {
+		name string
+		msg  interface{}
+		want bool
+	}{
+		{"large US payment", &models.Transaction{Amount: "5000000", Locations: us}, true},
+		{"small US payment", &models.Transaction{Amount: "10", Locations: us}, false},
+		{"large JP payment", &models.Transaction{Amount: "5000000", Locations: jp}, false},
+		{"payment without locations", &models.Transaction{Amount: "5000000"}, false},
+		{"US geo update", &models.StreamEvent{Type: "tx_geo_update", Data: &models.TxGeoUpdate{Locations: us}}, true},
+		{"JP geo update", &models.StreamEvent{Type: "tx_geo_update", Data: &models.TxGeoUpdate{Locations: jp}}, false},
+		{"channel not in view", &models.StreamEvent{Type: "server_status"}, false},
+	}
+	for _, tc := range cases {
+		if got := v.allows(tc.msg); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+	var unfiltered *view
+	if !unfiltered.allows(&models.Transaction{Amount: "1"}) {
+		t.Error("expected the top level to allow every message")
+	}
+}
+
+func TestViewOrigins(t *testing.T) {
+	srv := newTestServer()
+	srv.corsAllowedOrigins = []string{"https://main.example"}
+	srv.views = map[string]*view{
+		"acme":  newView("acme", models.View{AllowedOrigins: []string{"https://acme.example"}}),
+		"plain": newView("plain", models.View{}),
+	}
+
+	cases := []struct {
+		path, origin string
+		want         bool
+	}{
+		{"/validators", "https://main.example", true},
+		{"/validators", "https://acme.example", false},
+		{"/t/acme/validators", "https://acme.example", true},
+		{"/t/acme/transactions", "https://main.example", false},
+		{"/t/plain/validators", "https://main.example", true},
+	}
+	for _, tc := range cases {
+		if got := srv.originAllowed(tc.path, tc.origin); got != tc.want {
+			t.Errorf("%s from %s: expected %v, got %v", tc.path, tc.origin, tc.want, got)
+		}
+	}
+}
+
+func TestRecentForView(t *testing.T) {
+	srv := newTestServer()
+	for _, tx := range []*models.Transaction{
+		{Hash: "A", Amount: "100", Locations: []*models.GeoLocation{{CountryCode: "US"}}},
+		{Hash: "B", Amount: "100", Locations: []*models.GeoLocation{{CountryCode: "JP"}}},
+		{Hash: "C", Amount: "100", Locations: []*models.GeoLocation{{CountryCode: "US"}}},
+		{Hash: "D", Amount: "100", Locations: []*models.GeoLocation{{CountryCode: "US"}}},
+	} {
+		srv.recent.add(tx)
+	}
+	v := newView("acme", models.View{Countries: []string{"US"}})
+	recent := srv.recentFor(v, 2)
+	if len(recent) != 2 || recent[0].Hash != "D" || recent[1].Hash != "C" {
+		t.Fatalf("expected the two newest US transactions, got %+v", recent)
+	}
+}
+
+func TestViewFiltersValidatorEventsByCountry(t *testing.T) {
+	srv := newTestServer()
+	srv.broadcast = make(chan interface{}, 16)
+	srv.validatorFetcher = &staticValidators{validators: []*models.Validator{
+		{Address: "nUS", CountryCode: "US"},
+		{Address: "nDE", CountryCode: "DE"},
+		{Address: "nMoved", CountryCode: "DE"},
+	}}
+	v := newView("acme", models.View{Countries: []string{"US"}})
+
+	update := validator.DiffValidators(map[string]*models.Validator{
+		"nUS":     {Address: "nUS", CountryCode: "US", Domain: "old.example"},
+		"nDE":     {Address: "nDE", CountryCode: "DE"},
+		"nMoved":  {Address: "nMoved", CountryCode: "US"},
+		"nGoneUS": {Address: "nGoneUS", CountryCode: "US"},
+		"nGoneDE": {Address: "nGoneDE", CountryCode: "DE"},
+	}, map[string]*models.Validator{
+		"nUS":    {Address: "nUS", CountryCode: "US"},
+		"nDE":    {Address: "nDE", CountryCode: "DE"},
+		"nMoved": {Address: "nMoved", CountryCode: "DE"},
+	})
+	srv.onValidatorUpdate(update)
+	// Deltas made elsewhere, e.g. by the dev injector, fall back to the
+	// validator's current country.
+	srv.onValidatorUpdate(&models.ValidatorUpdate{Upserts: []*models.ValidatorDelta{{Address: "nDE", Fields: map[string]interface{}{"domain": "de.example"}}}})
+	srv.onKeyRotation(&models.KeyRotation{Address: "nUS"})
+	srv.onKeyRotation(&models.KeyRotation{Address: "nDE"})
+
+	want := map[string]bool{
+		"validator_upsert nDE":     false,
+		"validator_upsert nMoved":  true,
+		"validator_upsert nUS":     true,
+		"validator_remove nGoneDE": false,
+		"validator_remove nGoneUS": true,
+		"validator_rotated nUS":    true,
+		"validator_rotated nDE":    false,
+	}
+	if len(srv.broadcast) != len(want) {
+		t.Fatalf("expected %d validator events, got %d", len(want), len(srv.broadcast))
+	}
+	for len(srv.broadcast) > 0 {
+		event := (<-srv.broadcast).(*models.StreamEvent)
+		var address string
+		switch data := event.Data.(type) {
+		case *models.ValidatorDelta:
+			address = data.Address
+		case *models.KeyRotation:
+			address = data.Address
+		}
+		key := event.Type + " " + address
+		if got := v.allows(event); got != want[key] {
+			t.Errorf("%s: expected %v, got %v", key, want[key], got)
+		}
+	}
+}
+
+func TestViewFiltersSettlementsByTransaction(t *testing.T) {
+	srv := newTestServer()
+	srv.broadcast = make(chan interface{}, 16)
+	v := newView("acme", models.View{MinPaymentDrops: 1_000_000, Countries: []string{"US"}})
+
+	srv.onProvisionalTransaction(&models.Transaction{Hash: "US", Amount: "5000000", Provisional: true, Locations: []*models.GeoLocation{{CountryCode: "US"}}})
+	srv.onProvisionalTransaction(&models.Transaction{Hash: "JP", Amount: "5000000", Provisional: true, Locations: []*models.GeoLocation{{CountryCode: "JP"}}})
+	srv.onProvisionalTransaction(&models.Transaction{Hash: "SMALL", Amount: "10", Provisional: true, Locations: []*models.GeoLocation{{CountryCode: "US"}}})
+	for len(srv.broadcast) > 0 {
+		<-srv.broadcast
+	}
+	want := map[string]bool{"US": true, "JP": false, "SMALL": false, "UNKNOWN": false}
+	for _, hash := range []string{"US", "JP", "SMALL", "UNKNOWN"} {
+		srv.onTxSettlement(&models.TxSettlement{Hash: hash, Status: models.SettlementConfirmed})
+		event := (<-srv.broadcast).(*models.StreamEvent)
+		if got := v.allows(event); got != want[hash] {
+			t.Errorf("settlement of %s: expected %v, got %v", hash, want[hash], got)
+		}
+		if !newView("all", models.View{}).allows(event) {
+			t.Errorf("settlement of %s: expected an unfiltered view to allow it", hash)
+		}
+	}
+	if pending := srv.previews.settle("US"); pending != nil {
+		t.Fatalf("expected settled previews to be forgotten, got %+v", pending)
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// Display weight modes: how display_weight, which clients use to size
+// transaction arcs, is derived from the amount.
+const (
+	WeightModeXRP  = "xrp"  // the amount in XRP
+	WeightModeLog  = "log"  // log10(1 + XRP), so large payments do not drown out the rest
+	WeightModeFiat = "fiat" // the amount at a configured XRP price
+	WeightModeFlat = "flat" // 1 per transaction
+)
+
+// displayWeight computes display weights in one mode.
+type displayWeight struct {
+	mode     string
+	fiatRate float64 // fiat per XRP, for WeightModeFiat
+}
+
+// of returns the display weight of tx, or 0 when its amount is not in
+// drops and the mode depends on it.
+func (w displayWeight) of(tx *models.Transaction) float64 {
+	if w.mode == WeightModeFlat {
+		return 1
+	}
+	drops, err := strconv.ParseInt(tx.Amount, 10, 64)
+	if err != nil || drops <= 0 {
+		return 0
+	}
+	xrp := float64(drops) / dropsPerXRP
+	switch w.mode {
+	case WeightModeLog:
+		return math.Round(math.Log10(1+xrp)*10_000) / 10_000
+	case WeightModeFiat:
+		return math.Round(xrp*w.fiatRate*100) / 100
+	}
+	return xrp
+}
+
+// weighTransaction returns a copy of tx carrying its display weight. It runs
+// before tx reaches the recent buffer, so that live and replayed
+// transactions agree.
+func (s *Server) weighTransaction(tx *models.Transaction) *models.Transaction {
+	copy := *tx
+	copy.DisplayWeight = s.displayWeight.of(tx)
+	return &copy
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestDisplayWeight(t *testing.T) {
+	cases := []struct {
+		mode   string
+		amount string
+		want   float64
+	}{
+		{mode: WeightModeXRP, amount: "25000000", want: 25},
+		{mode: WeightModeLog, amount: "999000000", want: 3},
+		{mode: WeightModeLog, amount: "1500000", want: 0.3979},
+		{mode: WeightModeFiat, amount: "25000000", want: 13},
+		{mode: WeightModeFlat, amount: "25000000", want: 1},
+		{mode: WeightModeFlat, amount: `{"currency":"USD"}`, want: 1},
+		{mode: WeightModeXRP, amount: `{"currency":"USD"}`, want: 0},
+		{mode: WeightModeLog, amount: "", want: 0},
+	}
+	for _, tc := range cases {
+		weight := displayWeight{mode: tc.mode, fiatRate: 0.52}
+		if got := weight.of(&models.Transaction{Amount: tc.amount}); got != tc.want {
+			t.Errorf("%s of %q: expected %g, got %g", tc.mode, tc.amount, tc.want, got)
+		}
+	}
+}
+
+func TestLiveAndRecentTransactionsShareDisplayWeight(t *testing.T) {
+	srv := newTestServer()
+	srv.displayWeight = displayWeight{mode: WeightModeLog}
+	original := &models.Transaction{Hash: "WEIGHED", Amount: "9000000"}
+	srv.onTransaction(original)
+
+	live := (<-srv.broadcast).(*models.Transaction)
+	recent := srv.recent.snapshot(1)
+	if live.DisplayWeight != 1 || len(recent) != 1 || recent[0].DisplayWeight != live.DisplayWeight {
+		t.Fatalf("expected live and recent weights of 1, got %g and %+v", live.DisplayWeight, recent)
+	}
+	if original.DisplayWeight != 0 {
+		t.Fatal("expected the listener's transaction to be left unmodified")
+	}
+}
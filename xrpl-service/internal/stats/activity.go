@@ -0,0 +1,342 @@
+package stats
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// ActivityRetention is the longest window Activity can report.
+const ActivityRetention = 24 * time.Hour
+
+const (
+	activityBucketWidth = time.Minute
+
+	// pointHotspots is the number of cities kept on each timeline point.
+	pointHotspots = 3
+)
+
+// validatorSample is the validator set as seen at one point in time.
+type validatorSample struct {
+	total  int
+	active int
+}
+
+// activityBucket holds one minute of transactions and validator samples.
+type activityBucket struct {
+	minute       int64 // unix minute; 0 marks an unused bucket
+	transactions int64
+	payments     int64
+	drops        int64
+	flagged      int64
+	types        map[string]int64
+	hotspots     map[regionKey]*regionCount
+
+	// The latest validator sample by the end of the minute, and the
+	// smallest and largest set sizes sampled during it
+	known         bool
+	validators    validatorSample
+	sampled       bool
+	minValidators int
+	maxValidators int
+}
+
+// Activity aggregates the transaction stream and validator set into
+// minute buckets covering ActivityRetention, so summaries, hotspots and a
+// replayable timeline of the last day can be served without keeping the
+// transactions themselves.
+type Activity struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets []activityBucket
+
+	// Latest validator sample, carried into minutes without one
+	lastSample   validatorSample
+	lastSampleAt int64 // unix minute; 0 before the first sample
+
+	lastObserved atomic.Int64 // unix milliseconds
+}
+
+// NewActivity creates an empty activity store.
+func NewActivity() *Activity {
+	return &Activity{
+		now:     time.Now,
+		buckets: make([]activityBucket, int(ActivityRetention/activityBucketWidth)),
+	}
+}
+
+// ObserveTransaction adds tx to the current minute. Each located end of tx
+// counts once towards its city's hotspot. It is registered as a
+// transaction callback.
+func (a *Activity) ObserveTransaction(tx *models.Transaction) {
+	if tx == nil {
+		return
+	}
+	now := a.now()
+	a.lastObserved.Store(now.UnixMilli())
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	bucket := a.bucket(now)
+	bucket.transactions++
+	bucket.types[tx.TransactionType]++
+	if tx.Flagged {
+		bucket.flagged++
+	}
+	if tx.TransactionType == "Payment" {
+		bucket.payments++
+		if drops, err := strconv.ParseInt(tx.Amount, 10, 64); err == nil && drops > 0 {
+			bucket.drops += drops
+		}
+	}
+	seen := make(map[regionKey]bool, len(tx.Locations))
+	for _, location := range tx.Locations {
+		if location == nil || location.CountryCode == "" {
+			continue
+		}
+		key := regionKey{country: location.CountryCode, city: location.City}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		hotspot, ok := bucket.hotspots[key]
+		if !ok {
+			hotspot = &regionCount{latitude: location.Latitude, longitude: location.Longitude}
+			bucket.hotspots[key] = hotspot
+		}
+		hotspot.count++
+	}
+}
+
+// ObserveValidators samples the size of the validator set. It is called
+// with the full set after every validator update.
+func (a *Activity) ObserveValidators(validators []*models.Validator) {
+	sample := validatorSample{total: len(validators)}
+	for _, v := range validators {
+		if v != nil && v.IsActive {
+			sample.active++
+		}
+	}
+	now := a.now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	bucket := a.bucket(now)
+	if !bucket.sampled || sample.total < bucket.minValidators {
+		bucket.minValidators = sample.total
+	}
+	if !bucket.sampled || sample.total > bucket.maxValidators {
+		bucket.maxValidators = sample.total
+	}
+	bucket.sampled = true
+	bucket.known = true
+	bucket.validators = sample
+	a.lastSample = sample
+	a.lastSampleAt = bucket.minute
+}
+
+// LastUpdate returns when the last transaction was observed, or the zero
+// time before the first.
+func (a *Activity) LastUpdate() time.Time {
+	if ms := a.lastObserved.Load(); ms != 0 {
+		return time.UnixMilli(ms)
+	}
+	return time.Time{}
+}
+
+// Stats totals the buckets inside window, which is rounded up to whole
+// minutes and capped at ActivityRetention.
+func (a *Activity) Stats(window time.Duration, label string) *models.ActivityStats {
+	oldest, current := a.span(window)
+	stats := &models.ActivityStats{
+		Window: label,
+		Since:  oldest * int64(activityBucketWidth.Seconds()),
+		Types:  make(map[string]int64),
+	}
+	var drops int64
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, bucket := range a.inWindow(oldest, current) {
+		stats.Transactions += bucket.transactions
+		stats.Payments += bucket.payments
+		stats.Flagged += bucket.flagged
+		drops += bucket.drops
+		for txType, count := range bucket.types {
+			stats.Types[txType] += count
+		}
+		if !bucket.sampled {
+			continue
+		}
+		if stats.Validators == nil {
+			stats.Validators = &models.ValidatorRange{Min: bucket.minValidators, Max: bucket.maxValidators}
+		}
+		stats.Validators.Min = min(stats.Validators.Min, bucket.minValidators)
+		stats.Validators.Max = max(stats.Validators.Max, bucket.maxValidators)
+	}
+	stats.XRP = float64(drops) / dropsPerXRP
+
+	// A set unchanged since before the window still describes it
+	if a.lastSampleAt != 0 {
+		if stats.Validators == nil {
+			stats.Validators = &models.ValidatorRange{Min: a.lastSample.total, Max: a.lastSample.total}
+		}
+		stats.Validators.Latest = a.lastSample.total
+		stats.Validators.LatestActive = a.lastSample.active
+		stats.Validators.Min = min(stats.Validators.Min, a.lastSample.total)
+		stats.Validators.Max = max(stats.Validators.Max, a.lastSample.total)
+	}
+	return stats
+}
+
+// Hotspots ranks the cities of the buckets inside window by transaction
+// count, busiest first, keeping at most limit. A limit of zero or less
+// keeps all.
+func (a *Activity) Hotspots(window time.Duration, label string, limit int) *models.Hotspots {
+	oldest, current := a.span(window)
+	out := &models.Hotspots{
+		Window: label,
+		Since:  oldest * int64(activityBucketWidth.Seconds()),
+	}
+
+	a.mu.Lock()
+	merged := make(map[regionKey]*models.Hotspot)
+	for _, bucket := range a.inWindow(oldest, current) {
+		for key, count := range bucket.hotspots {
+			hotspot, ok := merged[key]
+			if !ok {
+				hotspot = &models.Hotspot{
+					CountryCode: key.country,
+					City:        key.city,
+					Latitude:    count.latitude,
+					Longitude:   count.longitude,
+				}
+				merged[key] = hotspot
+			}
+			hotspot.Transactions += int64(count.count)
+		}
+	}
+	a.mu.Unlock()
+
+	out.Hotspots = rankHotspots(merged, limit)
+	return out
+}
+
+// Timeline returns a point for every minute inside window that saw
+// transactions or a validator sample, oldest first.
+func (a *Activity) Timeline(window time.Duration, label string) *models.ActivityTimeline {
+	oldest, current := a.span(window)
+	timeline := &models.ActivityTimeline{
+		Window: label,
+		Since:  oldest * int64(activityBucketWidth.Seconds()),
+		Points: []*models.ActivityPoint{},
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	buckets := a.inWindow(oldest, current)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].minute < buckets[j].minute })
+
+	for _, bucket := range buckets {
+		var validators *int
+		if bucket.known {
+			total := bucket.validators.total
+			validators = &total
+		}
+		point := &models.ActivityPoint{
+			Minute:       bucket.minute * int64(activityBucketWidth.Seconds()),
+			Transactions: bucket.transactions,
+			Payments:     bucket.payments,
+			XRP:          float64(bucket.drops) / dropsPerXRP,
+			Validators:   validators,
+		}
+		if len(bucket.hotspots) > 0 {
+			hotspots := make(map[regionKey]*models.Hotspot, len(bucket.hotspots))
+			for key, count := range bucket.hotspots {
+				hotspots[key] = &models.Hotspot{
+					CountryCode:  key.country,
+					City:         key.city,
+					Latitude:     count.latitude,
+					Longitude:    count.longitude,
+					Transactions: int64(count.count),
+				}
+			}
+			point.Hotspots = rankHotspots(hotspots, pointHotspots)
+		}
+		timeline.Points = append(timeline.Points, point)
+	}
+	return timeline
+}
+
+// span returns the unix minutes window covers, ending with the current
+// one.
+func (a *Activity) span(window time.Duration) (oldest, current int64) {
+	if window > ActivityRetention {
+		window = ActivityRetention
+	}
+	minutes := int64((window + activityBucketWidth - 1) / activityBucketWidth)
+	current = a.now().Unix() / int64(activityBucketWidth.Seconds())
+	return current - minutes + 1, current
+}
+
+// inWindow returns the buckets holding minutes oldest through current, in
+// ring order. The caller holds a.mu.
+func (a *Activity) inWindow(oldest, current int64) []*activityBucket {
+	var buckets []*activityBucket
+	for i := range a.buckets {
+		bucket := &a.buckets[i]
+		if bucket.minute < oldest || bucket.minute > current {
+			continue
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// bucket returns the bucket for now, recycling it if it holds an older
+// minute. The caller holds a.mu.
+func (a *Activity) bucket(now time.Time) *activityBucket {
+	minute := now.Unix() / int64(activityBucketWidth.Seconds())
+	bucket := &a.buckets[minute%int64(len(a.buckets))]
+	if bucket.minute != minute {
+		*bucket = activityBucket{
+			minute:   minute,
+			types:    make(map[string]int64),
+			hotspots: make(map[regionKey]*regionCount),
+		}
+		// Minutes without a sample of their own keep the latest one
+		if a.lastSampleAt != 0 {
+			bucket.known = true
+			bucket.validators = a.lastSample
+		}
+	}
+	return bucket
+}
+
+// rankHotspots orders hotspots by transaction count, busiest first, and
+// keeps at most limit of them when limit is positive.
+func rankHotspots(hotspots map[regionKey]*models.Hotspot, limit int) []*models.Hotspot {
+	ranked := make([]*models.Hotspot, 0, len(hotspots))
+	for _, hotspot := range hotspots {
+		ranked = append(ranked, hotspot)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Transactions != b.Transactions {
+			return a.Transactions > b.Transactions
+		}
+		if a.CountryCode != b.CountryCode {
+			return a.CountryCode < b.CountryCode
+		}
+		return a.City < b.City
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
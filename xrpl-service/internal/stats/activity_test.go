@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func payment(drops string, locations ...*models.GeoLocation) *models.Transaction {
+	return &models.Transaction{TransactionType: "Payment", Amount: drops, Locations: locations}
+}
+
+func validatorSet(total, active int) []*models.Validator {
+	validators := make([]*models.Validator, total)
+	for i := range validators {
+		validators[i] = &models.Validator{IsActive: i < active}
+	}
+	return validators
+}
+
+func TestActivityStatsAndHotspots(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	activity := NewActivity()
+	activity.now = func() time.Time { return clock }
+
+	tokyo := &models.GeoLocation{CountryCode: "JP", City: "Tokyo", Latitude: 35.68, Longitude: 139.69}
+	paris := &models.GeoLocation{CountryCode: "FR", City: "Paris", Latitude: 48.85, Longitude: 2.35}
+
+	activity.ObserveValidators(validatorSet(30, 28))
+	activity.ObserveTransaction(payment("2000000", tokyo, paris))
+	activity.ObserveTransaction(payment("1000000", tokyo, tokyo))
+	activity.ObserveTransaction(&models.Transaction{TransactionType: "TrustSet", Flagged: true})
+	clock = clock.Add(2 * time.Hour)
+	activity.ObserveValidators(validatorSet(35, 33))
+	activity.ObserveTransaction(payment("4000000", paris))
+
+	stats := activity.Stats(24*time.Hour, "24h")
+	if stats.Transactions != 4 || stats.Payments != 3 || stats.XRP != 7 || stats.Flagged != 1 || stats.Types["TrustSet"] != 1 {
+		t.Fatalf("unexpected totals %+v", stats)
+	}
+	if v := stats.Validators; v == nil || v.Latest != 35 || v.LatestActive != 33 || v.Min != 30 || v.Max != 35 {
+		t.Fatalf("unexpected validator range %+v", stats.Validators)
+	}
+	if recent := activity.Stats(time.Hour, "1h"); recent.Transactions != 1 || recent.Validators.Min != 35 {
+		t.Fatalf("expected only the latest hour, got %+v", recent)
+	}
+
+	hotspots := activity.Hotspots(24*time.Hour, "24h", 0).Hotspots
+	if len(hotspots) != 2 || hotspots[0].City != "Paris" || hotspots[0].Transactions != 2 || hotspots[1].Transactions != 2 {
+		t.Fatalf("unexpected hotspots %+v", hotspots)
+	}
+	if limited := activity.Hotspots(24*time.Hour, "24h", 1).Hotspots; len(limited) != 1 {
+		t.Fatalf("expected the limit to apply, got %d hotspots", len(limited))
+	}
+}
+
+func TestActivityTimelineCarriesValidatorCounts(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	activity := NewActivity()
+	activity.now = func() time.Time { return clock }
+
+	activity.ObserveTransaction(payment("1000000"))
+	clock = clock.Add(time.Minute)
+	activity.ObserveValidators(validatorSet(30, 30))
+	clock = clock.Add(5 * time.Minute)
+	activity.ObserveTransaction(payment("3000000", &models.GeoLocation{CountryCode: "US", City: "Denver"}))
+
+	points := activity.Timeline(time.Hour, "1h").Points
+	if len(points) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(points))
+	}
+	if points[0].Validators != nil || points[0].Transactions != 1 {
+		t.Fatalf("expected no validator count before the first sample, got %+v", points[0])
+	}
+	if points[1].Validators == nil || *points[1].Validators != 30 || points[1].Transactions != 0 {
+		t.Fatalf("unexpected sampled point %+v", points[1])
+	}
+	last := points[2]
+	if last.Validators == nil || *last.Validators != 30 || last.XRP != 3 || len(last.Hotspots) != 1 || last.Hotspots[0].City != "Denver" {
+		t.Fatalf("unexpected last point %+v", last)
+	}
+	if points[0].Minute >= points[1].Minute || points[1].Minute >= points[2].Minute {
+		t.Fatalf("expected points oldest first, got %d %d %d", points[0].Minute, points[1].Minute, points[2].Minute)
+	}
+}
+
+func TestActivityDropsMinutesOlderThanRetention(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	activity := NewActivity()
+	activity.now = func() time.Time { return clock }
+
+	activity.ObserveTransaction(payment("1000000"))
+	clock = clock.Add(ActivityRetention)
+	activity.ObserveTransaction(payment("1000000"))
+
+	if stats := activity.Stats(ActivityRetention, "24h"); stats.Transactions != 1 {
+		t.Fatalf("expected the expired minute to be dropped, got %d transactions", stats.Transactions)
+	}
+}
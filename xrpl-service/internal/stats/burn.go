@@ -0,0 +1,177 @@
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// BurnRetention is the longest rolling window of the fee burn rates.
+const BurnRetention = 24 * time.Hour
+
+const (
+	burnBucketWidth = time.Minute
+	dropsPerXRP     = 1_000_000
+)
+
+// burnWindows are the rolling windows reported by Totals.
+var burnWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": BurnRetention,
+}
+
+// FeeBurnCallback receives each observed ledger's burn.
+type FeeBurnCallback func(*models.FeeBurn)
+
+// burnBucket holds one minute of ledger fees.
+type burnBucket struct {
+	minute       int64 // unix minute; 0 marks an unused bucket
+	ledgers      int64
+	transactions int64
+	drops        int64
+}
+
+// BurnTracker accumulates the transaction fees destroyed per validated
+// ledger, in total since it was created and in minute buckets for rolling
+// burn rates.
+type BurnTracker struct {
+	now func() time.Time
+
+	mu           sync.Mutex
+	since        time.Time
+	lastObserved time.Time
+	lastLedger   uint32
+	ledgers      int64
+	transactions int64
+	drops        int64
+	buckets      []burnBucket
+	callbacks    []FeeBurnCallback
+}
+
+// NewBurnTracker creates a tracker whose totals start now.
+func NewBurnTracker() *BurnTracker {
+	return &BurnTracker{
+		now:     time.Now,
+		since:   time.Now(),
+		buckets: make([]burnBucket, int(BurnRetention/burnBucketWidth)),
+	}
+}
+
+// AddCallback registers a callback for fee burn events.
+func (t *BurnTracker) AddCallback(callback FeeBurnCallback) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.callbacks = append(t.callbacks, callback)
+}
+
+// ObserveLedger adds a validated ledger's fees and notifies callbacks. It is
+// registered as a listener ledger fee callback.
+func (t *BurnTracker) ObserveLedger(fees *models.LedgerFees) {
+	if fees == nil {
+		return
+	}
+	now := t.now()
+
+	t.mu.Lock()
+	if fees.LedgerIndex <= t.lastLedger {
+		t.mu.Unlock()
+		return
+	}
+	t.lastLedger = fees.LedgerIndex
+	t.lastObserved = now
+	t.ledgers++
+	t.transactions += int64(fees.Transactions)
+	t.drops += fees.FeeDrops
+	bucket := t.bucket(now)
+	bucket.ledgers++
+	bucket.transactions += int64(fees.Transactions)
+	bucket.drops += fees.FeeDrops
+
+	burn := &models.FeeBurn{
+		LedgerFees:      *fees,
+		CumulativeDrops: t.drops,
+		XRPPerHour1h:    t.window(now, burnWindows["1h"]).XRPPerHour,
+		XRPPerHour24h:   t.window(now, burnWindows["24h"]).XRPPerHour,
+	}
+	callbacks := make([]FeeBurnCallback, len(t.callbacks))
+	copy(callbacks, t.callbacks)
+	t.mu.Unlock()
+
+	metrics.FeesBurnedDropsTotal.Add(float64(fees.FeeDrops))
+	for _, callback := range callbacks {
+		callback(burn)
+	}
+}
+
+// LastUpdate returns when the last ledger was added, or the zero time before
+// the first.
+func (t *BurnTracker) LastUpdate() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastObserved
+}
+
+// Totals returns the burn since the tracker was created and over each
+// rolling window.
+func (t *BurnTracker) Totals() *models.BurnTotals {
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	totals := &models.BurnTotals{
+		Since:           t.since.Unix(),
+		LastLedgerIndex: t.lastLedger,
+		Ledgers:         t.ledgers,
+		Transactions:    t.transactions,
+		Drops:           t.drops,
+		XRP:             float64(t.drops) / dropsPerXRP,
+		Windows:         make(map[string]*models.BurnWindow, len(burnWindows)),
+	}
+	for label, window := range burnWindows {
+		totals.Windows[label] = t.window(now, window)
+	}
+	return totals
+}
+
+// window sums the buckets inside window. The hourly rate is taken over the
+// part of the window the tracker has been running, so it is meaningful
+// before a full window has passed. The caller holds t.mu.
+func (t *BurnTracker) window(now time.Time, window time.Duration) *models.BurnWindow {
+	minutes := int64(window / burnBucketWidth)
+	currentMinute := now.Unix() / int64(burnBucketWidth.Seconds())
+	oldestMinute := currentMinute - minutes + 1
+
+	out := &models.BurnWindow{}
+	for i := range t.buckets {
+		bucket := &t.buckets[i]
+		if bucket.minute < oldestMinute || bucket.minute > currentMinute {
+			continue
+		}
+		out.Ledgers += bucket.ledgers
+		out.Transactions += bucket.transactions
+		out.Drops += bucket.drops
+	}
+
+	covered := now.Sub(t.since)
+	if covered > window {
+		covered = window
+	}
+	if covered < burnBucketWidth {
+		covered = burnBucketWidth
+	}
+	out.XRPPerHour = float64(out.Drops) / dropsPerXRP / covered.Hours()
+	return out
+}
+
+// bucket returns the bucket for now, recycling it if it holds an older
+// minute. The caller holds t.mu.
+func (t *BurnTracker) bucket(now time.Time) *burnBucket {
+	minute := now.Unix() / int64(burnBucketWidth.Seconds())
+	bucket := &t.buckets[minute%int64(len(t.buckets))]
+	if bucket.minute != minute {
+		*bucket = burnBucket{minute: minute}
+	}
+	return bucket
+}
@@ -0,0 +1,41 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestBurnTrackerTotalsAndRates(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	tracker := NewBurnTracker()
+	tracker.now = func() time.Time { return clock }
+	tracker.since = clock
+
+	var events []*models.FeeBurn
+	tracker.AddCallback(func(burn *models.FeeBurn) { events = append(events, burn) })
+
+	tracker.ObserveLedger(&models.LedgerFees{LedgerIndex: 100, Transactions: 10, FeeDrops: 1_000_000})
+	tracker.ObserveLedger(&models.LedgerFees{LedgerIndex: 100, Transactions: 10, FeeDrops: 1_000_000})
+	clock = clock.Add(2 * time.Hour)
+	tracker.ObserveLedger(&models.LedgerFees{LedgerIndex: 101, Transactions: 5, FeeDrops: 3_000_000})
+
+	if len(events) != 2 || events[1].CumulativeDrops != 4_000_000 {
+		t.Fatalf("expected two events ignoring the repeated ledger, got %+v", events)
+	}
+
+	totals := tracker.Totals()
+	if totals.Ledgers != 2 || totals.Transactions != 15 || totals.XRP != 4 || totals.LastLedgerIndex != 101 {
+		t.Fatalf("unexpected totals %+v", totals)
+	}
+	hour := totals.Windows["1h"]
+	if hour.Ledgers != 1 || hour.Drops != 3_000_000 || hour.XRPPerHour != 3 {
+		t.Fatalf("unexpected 1h window %+v", hour)
+	}
+	// The 24h rate is spread over the two hours the tracker has run.
+	if day := totals.Windows["24h"]; day.Drops != 4_000_000 || math.Abs(day.XRPPerHour-2) > 1e-9 {
+		t.Fatalf("unexpected 24h window %+v", day)
+	}
+}
@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// sizeBucketBounds are the upper bounds of the transaction size histogram;
+// a last bucket counts larger transactions.
+var sizeBucketBounds = []int{256, 512, 1024, 2048, 4096, 8192}
+
+// Distributions counts the types, sizes, memo use and affected ledger
+// object types of streamed transactions since it was created. It keeps
+// counters only, never the transactions.
+type Distributions struct {
+	mu           sync.Mutex
+	since        time.Time
+	lastObserved time.Time
+	transactions int64
+	withMemos    int64
+	totalBytes   int64
+	sizes        []int64 // per sizeBucketBounds, plus the unbounded bucket
+	types        map[string]int64
+	objectTypes  map[string]int64
+}
+
+// NewDistributions creates counters that start now.
+func NewDistributions() *Distributions {
+	return &Distributions{
+		since:       time.Now(),
+		sizes:       make([]int64, len(sizeBucketBounds)+1),
+		types:       make(map[string]int64),
+		objectTypes: make(map[string]int64),
+	}
+}
+
+// Observe counts a transaction. It is registered as a listener shape
+// callback.
+func (d *Distributions) Observe(shape *models.TransactionShape) {
+	if shape == nil {
+		return
+	}
+	bucket := len(sizeBucketBounds)
+	for i, bound := range sizeBucketBounds {
+		if shape.SizeBytes <= bound {
+			bucket = i
+			break
+		}
+	}
+	txType := shape.TransactionType
+	if txType == "" {
+		txType = "unknown"
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastObserved = time.Now()
+	d.transactions++
+	d.totalBytes += int64(shape.SizeBytes)
+	d.sizes[bucket]++
+	d.types[txType]++
+	if shape.HasMemos {
+		d.withMemos++
+	}
+	for _, entryType := range shape.LedgerEntryTypes {
+		d.objectTypes[entryType]++
+	}
+}
+
+// LastUpdate returns when the last transaction was counted, or the zero time
+// before the first.
+func (d *Distributions) LastUpdate() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastObserved
+}
+
+// Snapshot returns the counts so far.
+func (d *Distributions) Snapshot() *models.TxDistributions {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := &models.TxDistributions{
+		Since:        d.since.Unix(),
+		Transactions: d.transactions,
+		WithMemos:    d.withMemos,
+		Sizes:        make([]*models.SizeBucket, 0, len(d.sizes)),
+		Types:        make(map[string]int64, len(d.types)),
+		ObjectTypes:  make(map[string]int64, len(d.objectTypes)),
+	}
+	if d.transactions > 0 {
+		out.MeanSizeBytes = float64(d.totalBytes) / float64(d.transactions)
+	}
+	for i, count := range d.sizes {
+		bucket := &models.SizeBucket{Count: count}
+		if i < len(sizeBucketBounds) {
+			bucket.MaxBytes = sizeBucketBounds[i]
+		}
+		out.Sizes = append(out.Sizes, bucket)
+	}
+	for txType, count := range d.types {
+		out.Types[txType] = count
+	}
+	for entryType, count := range d.objectTypes {
+		out.ObjectTypes[entryType] = count
+	}
+	return out
+}
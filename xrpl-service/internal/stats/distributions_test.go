@@ -0,0 +1,39 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestDistributionsCountsShapes(t *testing.T) {
+	d := NewDistributions()
+	d.Observe(&models.TransactionShape{TransactionType: "Payment", SizeBytes: 200, LedgerEntryTypes: []string{"AccountRoot", "AccountRoot"}})
+	d.Observe(&models.TransactionShape{TransactionType: "Payment", SizeBytes: 600, HasMemos: true})
+	d.Observe(&models.TransactionShape{TransactionType: "OfferCreate", SizeBytes: 10000, LedgerEntryTypes: []string{"Offer"}})
+	d.Observe(nil)
+
+	snapshot := d.Snapshot()
+	if snapshot.Transactions != 3 || snapshot.WithMemos != 1 || snapshot.MeanSizeBytes != 3600 {
+		t.Fatalf("unexpected totals %+v", snapshot)
+	}
+	if snapshot.Types["Payment"] != 2 || snapshot.Types["OfferCreate"] != 1 {
+		t.Fatalf("unexpected types %v", snapshot.Types)
+	}
+	if snapshot.ObjectTypes["AccountRoot"] != 2 || snapshot.ObjectTypes["Offer"] != 1 {
+		t.Fatalf("unexpected object types %v", snapshot.ObjectTypes)
+	}
+	if len(snapshot.Sizes) != len(sizeBucketBounds)+1 {
+		t.Fatalf("expected %d size buckets, got %d", len(sizeBucketBounds)+1, len(snapshot.Sizes))
+	}
+	first, second, last := snapshot.Sizes[0], snapshot.Sizes[2], snapshot.Sizes[len(snapshot.Sizes)-1]
+	if first.MaxBytes != 256 || first.Count != 1 || second.MaxBytes != 1024 || second.Count != 1 || last.MaxBytes != 0 || last.Count != 1 {
+		t.Fatalf("unexpected size buckets %+v %+v %+v", first, second, last)
+	}
+
+	// Snapshots are copies.
+	snapshot.Types["Payment"] = 100
+	if d.Snapshot().Types["Payment"] != 2 {
+		t.Fatal("expected the snapshot to be independent of the counters")
+	}
+}
@@ -0,0 +1,170 @@
+// Package stats keeps rolling ledger statistics derived from the
+// transaction stream.
+package stats
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// NewAccountRetention is the longest window NewAccounts can report.
+const NewAccountRetention = 7 * 24 * time.Hour
+
+const bucketWidth = time.Hour
+
+type regionKey struct {
+	country string
+	city    string
+}
+
+type regionCount struct {
+	latitude  float64
+	longitude float64
+	count     int
+}
+
+// newAccountBucket holds one hour of account creations.
+type newAccountBucket struct {
+	hour      int64 // unix hour; 0 marks an unused bucket
+	regions   map[regionKey]*regionCount
+	unlocated int
+}
+
+// NewAccountTracker counts accounts created by streamed transactions in
+// hourly buckets, keyed by the city of the funding account.
+type NewAccountTracker struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets []newAccountBucket
+
+	lastObserved atomic.Int64 // unix milliseconds
+}
+
+// NewNewAccountTracker creates a tracker that retains NewAccountRetention
+// of history.
+func NewNewAccountTracker() *NewAccountTracker {
+	return &NewAccountTracker{
+		now:     time.Now,
+		buckets: make([]newAccountBucket, int(NewAccountRetention/bucketWidth)),
+	}
+}
+
+// ObserveTransaction records the accounts tx created. The funding source is
+// tx's source location; creations whose source was not located when the
+// transaction was broadcast are counted as unlocated. It is registered as a
+// transaction callback.
+func (t *NewAccountTracker) ObserveTransaction(tx *models.Transaction) {
+	if tx == nil {
+		return
+	}
+	t.lastObserved.Store(t.now().UnixMilli())
+	if len(tx.CreatedAccounts) == 0 {
+		return
+	}
+	source, _, _ := tx.RoleInfo()
+	located := source != nil && source.CountryCode != ""
+
+	t.mu.Lock()
+	bucket := t.bucket(t.now())
+	if located {
+		key := regionKey{country: source.CountryCode, city: source.City}
+		region, ok := bucket.regions[key]
+		if !ok {
+			region = &regionCount{latitude: source.Latitude, longitude: source.Longitude}
+			bucket.regions[key] = region
+		}
+		region.count += len(tx.CreatedAccounts)
+	} else {
+		bucket.unlocated += len(tx.CreatedAccounts)
+	}
+	t.mu.Unlock()
+
+	metrics.AccountsCreatedTotal.WithLabelValues(strconv.FormatBool(located)).Add(float64(len(tx.CreatedAccounts)))
+}
+
+// LastUpdate returns when the last transaction was observed, or the zero
+// time before the first.
+func (t *NewAccountTracker) LastUpdate() time.Time {
+	if ms := t.lastObserved.Load(); ms != 0 {
+		return time.UnixMilli(ms)
+	}
+	return time.Time{}
+}
+
+// NewAccounts aggregates the buckets inside window, which is rounded up to
+// whole hours and capped at NewAccountRetention. Regions are ordered by
+// count, largest first.
+func (t *NewAccountTracker) NewAccounts(window time.Duration, label string) *models.NewAccountStats {
+	if window > NewAccountRetention {
+		window = NewAccountRetention
+	}
+	hours := int64((window + bucketWidth - 1) / bucketWidth)
+	now := t.now()
+	currentHour := now.Unix() / int64(bucketWidth.Seconds())
+	oldestHour := currentHour - hours + 1
+
+	stats := &models.NewAccountStats{
+		Window:    label,
+		Since:     oldestHour * int64(bucketWidth.Seconds()),
+		Countries: make(map[string]int),
+		Regions:   []*models.NewAccountRegion{},
+	}
+	regions := make(map[regionKey]*models.NewAccountRegion)
+
+	t.mu.Lock()
+	for i := range t.buckets {
+		bucket := &t.buckets[i]
+		if bucket.hour < oldestHour || bucket.hour > currentHour {
+			continue
+		}
+		stats.Unlocated += bucket.unlocated
+		stats.Total += bucket.unlocated
+		for key, count := range bucket.regions {
+			region, ok := regions[key]
+			if !ok {
+				region = &models.NewAccountRegion{
+					CountryCode: key.country,
+					City:        key.city,
+					Latitude:    count.latitude,
+					Longitude:   count.longitude,
+				}
+				regions[key] = region
+				stats.Regions = append(stats.Regions, region)
+			}
+			region.Count += count.count
+			stats.Countries[key.country] += count.count
+			stats.Total += count.count
+		}
+	}
+	t.mu.Unlock()
+
+	sort.Slice(stats.Regions, func(i, j int) bool {
+		a, b := stats.Regions[i], stats.Regions[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.CountryCode != b.CountryCode {
+			return a.CountryCode < b.CountryCode
+		}
+		return a.City < b.City
+	})
+	return stats
+}
+
+// bucket returns the bucket for now, recycling it if it holds an older
+// hour. The caller holds t.mu.
+func (t *NewAccountTracker) bucket(now time.Time) *newAccountBucket {
+	hour := now.Unix() / int64(bucketWidth.Seconds())
+	bucket := &t.buckets[hour%int64(len(t.buckets))]
+	if bucket.hour != hour {
+		*bucket = newAccountBucket{hour: hour, regions: make(map[regionKey]*regionCount)}
+	}
+	return bucket
+}
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func creation(accounts int, country, city string) *models.Transaction {
+	tx := &models.Transaction{TransactionType: "Payment", CreatedAccounts: make([]string, accounts)}
+	if country != "" {
+		tx.Locations = []*models.GeoLocation{{CountryCode: country, City: city, Latitude: 1, Longitude: 2, Role: models.LocationRoleSource}}
+	}
+	return tx
+}
+
+func TestNewAccountTrackerAggregatesRegions(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	tracker := NewNewAccountTracker()
+	tracker.now = func() time.Time { return clock }
+
+	tracker.ObserveTransaction(creation(1, "US", "New York"))
+	tracker.ObserveTransaction(creation(2, "JP", "Tokyo"))
+	tracker.ObserveTransaction(creation(1, "", ""))
+	tracker.ObserveTransaction(&models.Transaction{TransactionType: "Payment"})
+	clock = clock.Add(2 * time.Hour)
+	tracker.ObserveTransaction(creation(1, "US", "New York"))
+
+	stats := tracker.NewAccounts(24*time.Hour, "24h")
+	if stats.Total != 5 || stats.Unlocated != 1 || stats.Countries["US"] != 2 || stats.Countries["JP"] != 2 {
+		t.Fatalf("unexpected totals %+v", stats)
+	}
+	if len(stats.Regions) != 2 || stats.Regions[0].CountryCode != "JP" || stats.Regions[1].City != "New York" || stats.Regions[1].Count != 2 {
+		t.Fatalf("unexpected regions %+v %+v", stats.Regions[0], stats.Regions[1])
+	}
+
+	if recent := tracker.NewAccounts(time.Hour, "1h"); recent.Total != 1 || recent.Countries["US"] != 1 {
+		t.Fatalf("expected only the latest hour, got %+v", recent)
+	}
+}
+
+func TestNewAccountTrackerExpiresOldBuckets(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	tracker := NewNewAccountTracker()
+	tracker.now = func() time.Time { return clock }
+
+	tracker.ObserveTransaction(creation(3, "DE", "Berlin"))
+	clock = clock.Add(NewAccountRetention)
+	tracker.ObserveTransaction(creation(1, "FR", "Paris"))
+
+	stats := tracker.NewAccounts(30*24*time.Hour, "7d")
+	if stats.Total != 1 || stats.Countries["DE"] != 0 {
+		t.Fatalf("expected the week-old bucket to be recycled, got %+v", stats)
+	}
+}
@@ -0,0 +1,70 @@
+// Package timeutil converts between Unix time and the XRPL's Ripple epoch,
+// which ledger close times, transaction dates and validator list
+// expirations count seconds from.
+package timeutil
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RippleEpoch is the Unix time of the Ripple epoch, 2000-01-01T00:00:00Z.
+const RippleEpoch int64 = 946684800
+
+// RippleToUnix converts seconds since the Ripple epoch to Unix seconds.
+func RippleToUnix(seconds uint32) int64 {
+	return int64(seconds) + RippleEpoch
+}
+
+// UnixToRipple converts Unix seconds to seconds since the Ripple epoch. ok
+// is false for times before the epoch or too late for the XRPL's 32-bit
+// fields.
+func UnixToRipple(unix int64) (seconds uint32, ok bool) {
+	rel := unix - RippleEpoch
+	if rel < 0 || rel > math.MaxUint32 {
+		return 0, false
+	}
+	return uint32(rel), true
+}
+
+// FromRipple returns seconds since the Ripple epoch as a UTC time.
+func FromRipple(seconds uint32) time.Time {
+	return time.Unix(RippleToUnix(seconds), 0).UTC()
+}
+
+// ToRipple returns t in seconds since the Ripple epoch, truncated to the
+// second; see UnixToRipple.
+func ToRipple(t time.Time) (uint32, bool) {
+	return UnixToRipple(t.Unix())
+}
+
+// RippleToUnixMilli converts seconds since the Ripple epoch to Unix
+// milliseconds, for comparing close times with millisecond stamps.
+func RippleToUnixMilli(seconds uint32) int64 {
+	return RippleToUnix(seconds) * 1000
+}
+
+// ParseRipple reads a Ripple-epoch time from a decoded JSON value: a
+// non-negative number, as in stream messages and validator list blobs, or
+// a decimal string.
+func ParseRipple(v interface{}) (uint32, bool) {
+	var n float64
+	switch value := v.(type) {
+	case float64:
+		n = value
+	case string:
+		parsed, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+		if err != nil {
+			return 0, false
+		}
+		return uint32(parsed), true
+	default:
+		return 0, false
+	}
+	if math.IsNaN(n) || n < 0 || n > math.MaxUint32 {
+		return 0, false
+	}
+	return uint32(n), true
+}
@@ -0,0 +1,52 @@
+package timeutil
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRippleEpochConversions(t *testing.T) {
+	if got := FromRipple(0); !got.Equal(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)) || got.Location() != time.UTC {
+		t.Fatalf("expected the epoch at 2000-01-01 UTC, got %v", got)
+	}
+	if got := RippleToUnix(760000000); got != 1706684800 {
+		t.Fatalf("expected 1706684800, got %d", got)
+	}
+	if got := RippleToUnixMilli(1); got != (RippleEpoch+1)*1000 {
+		t.Fatalf("unexpected milliseconds %d", got)
+	}
+
+	at := time.Date(2024, 1, 31, 7, 6, 40, 500, time.FixedZone("CET", 3600))
+	seconds, ok := ToRipple(at)
+	if !ok || !FromRipple(seconds).Equal(at.Truncate(time.Second)) {
+		t.Fatalf("expected a round trip through the Ripple epoch, got %d %v", seconds, ok)
+	}
+	if _, ok := UnixToRipple(RippleEpoch - 1); ok {
+		t.Fatal("expected times before the epoch to be rejected")
+	}
+	if _, ok := UnixToRipple(RippleEpoch + math.MaxUint32 + 1); ok {
+		t.Fatal("expected times past 32 bits to be rejected")
+	}
+}
+
+func TestParseRipple(t *testing.T) {
+	for _, tc := range []struct {
+		value interface{}
+		want  uint32
+		ok    bool
+	}{
+		{value: float64(760000000), want: 760000000, ok: true},
+		{value: " 760000000 ", want: 760000000, ok: true},
+		{value: float64(-1)},
+		{value: float64(math.MaxUint32) + 1},
+		{value: math.NaN()},
+		{value: "soon"},
+		{value: nil},
+	} {
+		got, ok := ParseRipple(tc.value)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ParseRipple(%v) = %d, %v; want %d, %v", tc.value, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package transaction
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/xrpltest"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// TestCorpus runs the parse and enrichment pipeline over every recorded
+// stream message and compares the results with the expectations stored
+// alongside it.
+func TestCorpus(t *testing.T) {
+	entries, err := xrpltest.LoadCorpus()
+	if err != nil {
+		t.Fatalf("LoadCorpus: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("corpus is empty")
+	}
+
+	for _, entry := range entries {
+		entry := entry
+		t.Run(entry.Name, func(t *testing.T) {
+			expect := entry.Expect
+			listener := NewListener(nil, 1, nil, nil)
+
+			tx, err := listener.parseTransaction(entry.Message)
+			if (err != nil) != expect.Error {
+				t.Fatalf("parseTransaction error = %v, want error %v", err, expect.Error)
+			}
+			if (tx != nil) != expect.Forwarded {
+				t.Fatalf("forwarded = %v, want %v", tx != nil, expect.Forwarded)
+			}
+			if tx != nil {
+				if tx.Amount != expect.Amount {
+					t.Errorf("amount = %q, want %q", tx.Amount, expect.Amount)
+				}
+				if tx.TransactionResult != expect.TransactionResult {
+					t.Errorf("transaction result = %q, want %q", tx.TransactionResult, expect.TransactionResult)
+				}
+				if tx.CloseTime != expect.CloseTime {
+					t.Errorf("close time = %d, want %d", tx.CloseTime, expect.CloseTime)
+				}
+				if !reflect.DeepEqual(tx.CreatedAccounts, expect.CreatedAccounts) {
+					t.Errorf("created accounts = %v, want %v", tx.CreatedAccounts, expect.CreatedAccounts)
+				}
+				checkCorpusEnrichment(t, tx, expect.Candidates)
+			}
+
+			var shape *xrpltest.CorpusShape
+			if got := transactionShape(entry.Message); got != nil {
+				shape = &xrpltest.CorpusShape{
+					TransactionType:  got.TransactionType,
+					HasMemos:         got.HasMemos,
+					LedgerEntryTypes: got.LedgerEntryTypes,
+				}
+			}
+			if !reflect.DeepEqual(shape, expect.Shape) {
+				t.Errorf("shape = %+v, want %+v", shape, expect.Shape)
+			}
+
+			var change *xrpltest.CorpusDomainChange
+			if account, oldDomain, newDomain, ok := accountDomainChange(entry.Message); ok {
+				change = &xrpltest.CorpusDomainChange{Account: account, OldDomain: oldDomain, NewDomain: newDomain}
+			}
+			if !reflect.DeepEqual(change, expect.DomainChange) {
+				t.Errorf("domain change = %+v, want %+v", change, expect.DomainChange)
+			}
+
+			// The full message path must accept every entry, whatever it
+			// does with it.
+			listener.handleMessage(entry.Message)
+		})
+	}
+}
+
+// checkCorpusEnrichment resolves every candidate of tx to a distinct location
+// and checks they are looked up in the expected order with the right roles.
+func checkCorpusEnrichment(t *testing.T, tx *models.Transaction, candidates []string) {
+	t.Helper()
+	resolver := &mockGeoResolver{locations: make(map[string]*models.GeoLocation)}
+	for i, account := range candidates {
+		resolver.locations[account] = &models.GeoLocation{Latitude: float64(i), Longitude: float64(i), City: account}
+	}
+	listener := NewListener(nil, 1, resolver, nil)
+	listener.enrichTransaction(context.Background(), tx)
+
+	got := make([]string, 0, len(tx.Locations))
+	for _, location := range tx.Locations {
+		got = append(got, location.City)
+		want := models.LocationRoleExtra
+		switch location.City {
+		case tx.Account:
+			want = models.LocationRoleSource
+		case tx.Destination:
+			want = models.LocationRoleDestination
+		}
+		if location.Role != want {
+			t.Errorf("role of %s = %q, want %q", location.City, location.Role, want)
+		}
+	}
+	if len(got) == 0 {
+		got = nil
+	}
+	if !reflect.DeepEqual(got, candidates) {
+		t.Errorf("enriched candidates = %v, want %v", got, candidates)
+	}
+}
@@ -0,0 +1,78 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+)
+
+// AccountDomainObserver is implemented by geo resolvers that cache account
+// locations by domain and need to hear when an account's Domain changes.
+type AccountDomainObserver interface {
+	AccountDomainChanged(account, oldDomain, newDomain string) string
+}
+
+// observeDomainChange passes a validated, successful AccountSet that sets
+// or clears Domain to the geo resolver, if it observes domain changes, and
+// drops the account from the enrichment shards' caches. The previous domain
+// comes from the AccountRoot's PreviousFields and is empty when the account
+// had none. Re-resolution does DNS lookups, so it runs off the stream
+// goroutine.
+func (l *Listener) observeDomainChange(msg map[string]interface{}) {
+	observer, observing := l.geoResolver.(AccountDomainObserver)
+	if !observing && l.shards == nil {
+		return
+	}
+	account, oldDomain, newDomain, ok := accountDomainChange(msg)
+	if !ok {
+		return
+	}
+	l.invalidateShards(account)
+	if observing {
+		go observer.AccountDomainChanged(account, oldDomain, newDomain)
+	}
+}
+
+// accountDomainChange extracts the account and its old and new domains from
+// a validated, successful AccountSet transaction message with a Domain
+// field.
+func accountDomainChange(msg map[string]interface{}) (account, oldDomain, newDomain string, ok bool) {
+	if jsonutil.String(msg, "type") != "transaction" || !jsonutil.Bool(msg, "validated") {
+		return "", "", "", false
+	}
+	txnRaw := jsonutil.Map(msg, "transaction")
+	if jsonutil.String(txnRaw, "TransactionType") != "AccountSet" {
+		return "", "", "", false
+	}
+	domainHex, hasDomain := txnRaw["Domain"].(string)
+	account = jsonutil.String(txnRaw, "Account")
+	if !hasDomain || account == "" {
+		return "", "", "", false
+	}
+	meta := jsonutil.Map(msg, "meta")
+	if jsonutil.String(meta, "TransactionResult") != defaultAllowedResult {
+		return "", "", "", false
+	}
+
+	for _, node := range jsonutil.Slice(meta, "AffectedNodes") {
+		modified := jsonutil.Map(jsonutil.Object(node), "ModifiedNode")
+		if jsonutil.String(modified, "LedgerEntryType") != "AccountRoot" ||
+			jsonutil.GetString(modified, "FinalFields.Account") != account {
+			continue
+		}
+		if previousHex, ok := jsonutil.Map(modified, "PreviousFields")["Domain"].(string); ok {
+			oldDomain = decodeDomain(previousHex)
+		}
+	}
+	return account, oldDomain, decodeDomain(domainHex), true
+}
+
+// decodeDomain decodes a hex Domain field, returning "" for invalid hex.
+func decodeDomain(domainHex string) string {
+	raw, err := hex.DecodeString(domainHex)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.Trim(string(raw), "\x00"))
+}
@@ -0,0 +1,78 @@
+package transaction
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+type domainChange struct{ account, oldDomain, newDomain string }
+
+type domainObservingResolver struct {
+	changes chan domainChange
+}
+
+func (r *domainObservingResolver) ResolveAccountGeo(ctx context.Context, client xrpl.NodeClient, account string) (*models.GeoLocation, error) {
+	return nil, nil
+}
+
+func (r *domainObservingResolver) AccountDomainChanged(account, oldDomain, newDomain string) string {
+	r.changes <- domainChange{account, oldDomain, newDomain}
+	return ""
+}
+
+func accountSetMessage(account, newDomain, previousDomain string, result string) map[string]interface{} {
+	modified := map[string]interface{}{
+		"LedgerEntryType": "AccountRoot",
+		"FinalFields":     map[string]interface{}{"Account": account, "Domain": hex.EncodeToString([]byte(newDomain))},
+	}
+	if previousDomain != "" {
+		modified["PreviousFields"] = map[string]interface{}{"Domain": hex.EncodeToString([]byte(previousDomain))}
+	}
+	return map[string]interface{}{
+		"type":      "transaction",
+		"validated": true,
+		"transaction": map[string]interface{}{
+			"TransactionType": "AccountSet",
+			"Account":         account,
+			"Domain":          hex.EncodeToString([]byte(newDomain)),
+		},
+		"meta": map[string]interface{}{
+			"TransactionResult": result,
+			"AffectedNodes":     []interface{}{map[string]interface{}{"ModifiedNode": modified}},
+		},
+	}
+}
+
+func TestAccountSetDomainChangeNotifiesResolver(t *testing.T) {
+	resolver := &domainObservingResolver{changes: make(chan domainChange, 1)}
+	listener := NewListener(nil, 1, resolver, nil)
+
+	listener.handleMessage(accountSetMessage("rAcct", "new.example", "old.example", "tesSUCCESS"))
+	select {
+	case change := <-resolver.changes:
+		if change != (domainChange{"rAcct", "old.example", "new.example"}) {
+			t.Fatalf("unexpected domain change %+v", change)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the resolver to be notified of the domain change")
+	}
+
+	listener.handleMessage(accountSetMessage("rAcct", "other.example", "new.example", "tecNO_PERMISSION"))
+	select {
+	case change := <-resolver.changes:
+		t.Fatalf("expected a failed AccountSet to be ignored, got %+v", change)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestAccountDomainChangeWithoutPreviousDomain(t *testing.T) {
+	account, oldDomain, newDomain, ok := accountDomainChange(accountSetMessage("rAcct", "first.example", "", "tesSUCCESS"))
+	if !ok || account != "rAcct" || oldDomain != "" || newDomain != "first.example" {
+		t.Fatalf("unexpected change %q %q %q %v", account, oldDomain, newDomain, ok)
+	}
+}
@@ -0,0 +1,213 @@
+package transaction
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultExecProcessorTimeout = 200 * time.Millisecond
+	execProcessorRestartDelay   = 5 * time.Second
+	execProcessorMaxLine        = 1 << 20
+)
+
+var errExecProcessorUnavailable = errors.New("processor process unavailable")
+
+// ExecProcessor runs an external process as a Processor. Each transaction is
+// written to the process's stdin as one JSON line, {"transaction": {...}},
+// and the process answers with one JSON line on stdout: {"transaction": {...}}
+// to replace the transaction, or {} to leave it unchanged. A process that
+// exits, writes invalid output, or misses the timeout is killed and restarted
+// after a short delay; transactions pass through unchanged meanwhile.
+type ExecProcessor struct {
+	command []string
+	timeout time.Duration
+	logger  *logrus.Logger
+
+	mu           sync.Mutex
+	cmd          *exec.Cmd
+	stdin        *os.File // a pipe, so that writes can time out
+	stderr       io.WriteCloser
+	lines        chan []byte
+	restartAfter time.Time
+}
+
+type execRequest struct {
+	Transaction *models.Transaction `json:"transaction"`
+}
+
+type execResponse struct {
+	Transaction *models.Transaction `json:"transaction"`
+}
+
+// NewExecProcessor creates a processor backed by command, e.g.
+// []string{"/usr/local/bin/tagger", "--mode", "strict"}. The process is
+// started on first use.
+func NewExecProcessor(command []string, timeout time.Duration, logger *logrus.Logger) *ExecProcessor {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	if timeout <= 0 {
+		timeout = defaultExecProcessorTimeout
+	}
+	return &ExecProcessor{
+		command: command,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// Name identifies the processor by its executable.
+func (p *ExecProcessor) Name() string {
+	if len(p.command) == 0 {
+		return "exec"
+	}
+	return "exec:" + filepath.Base(p.command[0])
+}
+
+// Process sends tx to the external process and applies its answer.
+func (p *ExecProcessor) Process(ctx context.Context, tx *models.Transaction) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ensureStartedLocked(); err != nil {
+		return err
+	}
+
+	request, err := json.Marshal(execRequest{Transaction: tx})
+	if err != nil {
+		return err
+	}
+	// The timeout covers the write too: a process that stops reading would
+	// otherwise block the listener once the pipe buffer is full.
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	p.stdin.SetWriteDeadline(deadline)
+	if _, err := p.stdin.Write(append(request, '\n')); err != nil {
+		p.stopLocked()
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return fmt.Errorf("failed to write to processor: %w", context.DeadlineExceeded)
+		}
+		return fmt.Errorf("failed to write to processor: %w", err)
+	}
+
+	select {
+	case line, ok := <-p.lines:
+		if !ok {
+			p.stopLocked()
+			return fmt.Errorf("processor exited")
+		}
+		var response execResponse
+		if err := json.Unmarshal(line, &response); err != nil {
+			p.stopLocked()
+			return fmt.Errorf("invalid processor response: %w", err)
+		}
+		if response.Transaction != nil {
+			candidates := tx.GeoCandidates
+			*tx = *response.Transaction
+			tx.GeoCandidates = candidates
+		}
+		return nil
+	case <-ctx.Done():
+		// The late answer would desynchronize the line protocol.
+		p.stopLocked()
+		return ctx.Err()
+	}
+}
+
+// Close stops the external process.
+func (p *ExecProcessor) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopLocked()
+	return nil
+}
+
+func (p *ExecProcessor) ensureStartedLocked() error {
+	if p.cmd != nil {
+		return nil
+	}
+	if len(p.command) == 0 || time.Now().Before(p.restartAfter) {
+		return errExecProcessorUnavailable
+	}
+
+	cmd := exec.Command(p.command[0], p.command[1:]...)
+	stdinReader, stdin, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = stdinReader
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		stdinReader.Close()
+		stdin.Close()
+		return err
+	}
+	stderr := p.logger.WithField("processor", p.Name()).WriterLevel(logrus.WarnLevel)
+	cmd.Stderr = stderr
+	err = cmd.Start()
+	stdinReader.Close()
+	if err != nil {
+		stdin.Close()
+		stderr.Close()
+		p.restartAfter = time.Now().Add(execProcessorRestartDelay)
+		return fmt.Errorf("failed to start processor: %w", err)
+	}
+
+	lines := make(chan []byte, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), execProcessorMaxLine)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			lines <- line
+		}
+	}()
+
+	p.cmd = cmd
+	p.stdin = stdin
+	p.stderr = stderr
+	p.lines = lines
+	p.logger.WithField("processor", p.Name()).Info("Started transaction processor")
+	return nil
+}
+
+func (p *ExecProcessor) stopLocked() {
+	if p.cmd == nil {
+		return
+	}
+	p.stdin.Close()
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+	cmd, lines, stderr := p.cmd, p.lines, p.stderr
+	go func() {
+		// Drain so the reader goroutine can exit, then reap the process.
+		// Wait returns once stderr is copied, after which the log pipe and
+		// its goroutine can go.
+		for range lines {
+		}
+		cmd.Wait()
+		stderr.Close()
+	}()
+	p.cmd = nil
+	p.stdin = nil
+	p.stderr = nil
+	p.lines = nil
+	p.restartAfter = time.Now().Add(execProcessorRestartDelay)
+	p.logger.WithField("processor", p.Name()).Warn("Stopped transaction processor")
+}
@@ -0,0 +1,248 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/intern"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+const (
+	// maxTrafficSamples bounds the traffic kept for filter previews. At
+	// busy times it covers less than the configured window.
+	maxTrafficSamples = 1 << 17
+
+	// maxPreviewTypes bounds the transaction types of a candidate filter.
+	maxPreviewTypes = 64
+)
+
+// ErrFilterPreviewDisabled is returned by PreviewFilter when the listener
+// keeps no traffic to replay.
+var ErrFilterPreviewDisabled = errors.New("filter preview is disabled")
+
+// ErrInvalidFilter is wrapped by PreviewFilter when a candidate filter is
+// rejected.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// trafficSample is what the filters look at in one validated transaction.
+type trafficSample struct {
+	at     int64 // unix milliseconds received
+	txType string
+	result string
+	drops  int64 // delivered XRP drops of a payment, -1 otherwise
+}
+
+// trafficBuffer is a ring of the validated transactions received, before
+// any filter, for replaying through candidate filters.
+type trafficBuffer struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	samples []trafficSample
+	next    int   // slot overwritten next once the ring is full
+	since   int64 // unix milliseconds the first sample was received
+}
+
+func newTrafficBuffer(window time.Duration) *trafficBuffer {
+	return &trafficBuffer{window: window}
+}
+
+func (b *trafficBuffer) record(sample trafficSample) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.since == 0 {
+		b.since = sample.at
+	}
+	if len(b.samples) < maxTrafficSamples {
+		b.samples = append(b.samples, sample)
+		return
+	}
+	b.samples[b.next] = sample
+	b.next = (b.next + 1) % maxTrafficSamples
+}
+
+// replay counts the samples received at or after start that each filter
+// matches, and returns when the replayed span begins: start, or the oldest
+// sample kept if the buffer does not reach back that far.
+func (b *trafficBuffer) replay(start int64, filters []sampleFilter, projections []*models.FilterProjection) (from int64, sampled int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from = max(start, b.since)
+	if len(b.samples) == maxTrafficSamples {
+		from = max(from, b.samples[b.next].at)
+	}
+	for _, sample := range b.samples {
+		if sample.at < from {
+			continue
+		}
+		sampled++
+		for i, filter := range filters {
+			if filter.matches(sample) {
+				projections[i].Matched++
+				projections[i].ByType[sample.txType]++
+			}
+		}
+	}
+	return from, sampled
+}
+
+// recordTraffic keeps a validated transaction message for filter previews.
+func (l *Listener) recordTraffic(msg map[string]interface{}) {
+	if l.traffic == nil || !jsonutil.Bool(msg, "validated") {
+		return
+	}
+	txnRaw := jsonutil.Map(msg, "transaction")
+	if txnRaw == nil {
+		return
+	}
+	sample := trafficSample{
+		at:     l.clock.Now().UnixMilli(),
+		txType: intern.String(jsonutil.String(txnRaw, "TransactionType")),
+		result: intern.String(transactionResult(msg)),
+		drops:  -1,
+	}
+	if sample.txType == "Payment" {
+		if drops, ok := parsePaymentAmountDrops(msg, txnRaw); ok {
+			sample.drops = drops
+		}
+	}
+	l.traffic.record(sample)
+}
+
+// sampleFilter is a TxFilter prepared for matching samples.
+type sampleFilter struct {
+	minDrops int64
+	types    map[string]struct{}
+	results  resultFilter
+}
+
+func newSampleFilter(filter models.TxFilter) sampleFilter {
+	types := make(map[string]struct{}, len(filter.TransactionTypes))
+	for _, txType := range filter.TransactionTypes {
+		types[txType] = struct{}{}
+	}
+	return sampleFilter{minDrops: filter.MinPaymentDrops, types: types, results: newResultFilter(filter.AllowedResults)}
+}
+
+func (f sampleFilter) matches(sample trafficSample) bool {
+	if _, ok := f.types[sample.txType]; !ok {
+		return false
+	}
+	if !f.results.allows(sample.result) {
+		return false
+	}
+	if sample.txType == "Payment" {
+		return sample.drops >= 0 && sample.drops >= f.minDrops
+	}
+	return true
+}
+
+// Filter returns the filter the listener forwards transactions with.
+func (l *Listener) Filter() models.TxFilter {
+	return models.TxFilter{
+		MinPaymentDrops:  l.minPaymentDrops,
+		TransactionTypes: []string{"Payment"},
+		AllowedResults:   l.allowedResults.patterns,
+	}
+}
+
+// PreviewFilter replays the traffic received within window, or within the
+// whole preview window when zero, through the live filter and candidate,
+// and projects how many events each would have forwarded. Fields the
+// candidate leaves at zero are taken from the live filter. The live stream
+// only forwards payments, so other types show what adding them would cost.
+func (l *Listener) PreviewFilter(candidate models.TxFilter, window time.Duration) (*models.FilterPreview, error) {
+	if l.traffic == nil {
+		return nil, ErrFilterPreviewDisabled
+	}
+	live := l.Filter()
+	if candidate.MinPaymentDrops == 0 {
+		candidate.MinPaymentDrops = live.MinPaymentDrops
+	}
+	if len(candidate.TransactionTypes) == 0 {
+		candidate.TransactionTypes = live.TransactionTypes
+	}
+	if len(candidate.AllowedResults) == 0 {
+		candidate.AllowedResults = live.AllowedResults
+	}
+	if err := validateFilter(candidate); err != nil {
+		return nil, err
+	}
+	if window <= 0 || window > l.traffic.window {
+		window = l.traffic.window
+	}
+
+	preview := &models.FilterPreview{
+		Live:      live,
+		Candidate: candidate,
+		Current:   models.FilterProjection{ByType: make(map[string]int)},
+		Projected: models.FilterProjection{ByType: make(map[string]int)},
+	}
+	now := l.clock.Now().UnixMilli()
+	from, sampled := l.traffic.replay(now-window.Milliseconds(),
+		[]sampleFilter{newSampleFilter(live), newSampleFilter(candidate)},
+		[]*models.FilterProjection{&preview.Current, &preview.Projected})
+	preview.Sampled = sampled
+	if from < now {
+		preview.WindowSeconds = float64(now-from) / 1000
+		for _, projection := range []*models.FilterProjection{&preview.Current, &preview.Projected} {
+			projection.PerSecond = float64(projection.Matched) / preview.WindowSeconds
+			projection.PerMinute = projection.PerSecond * 60
+		}
+	}
+	return preview, nil
+}
+
+// validateFilter checks a candidate filter the way the configuration
+// checks MIN_PAYMENT_DROPS and ALLOWED_TX_RESULTS.
+func validateFilter(filter models.TxFilter) error {
+	if filter.MinPaymentDrops < 0 {
+		return fmt.Errorf("%w: min_payment_drops must not be negative", ErrInvalidFilter)
+	}
+	if len(filter.TransactionTypes) > maxPreviewTypes {
+		return fmt.Errorf("%w: at most %d transaction_types", ErrInvalidFilter, maxPreviewTypes)
+	}
+	for _, txType := range filter.TransactionTypes {
+		if !isTransactionType(txType) {
+			return fmt.Errorf("%w: transaction type %q", ErrInvalidFilter, txType)
+		}
+	}
+	for _, pattern := range filter.AllowedResults {
+		if !isResultPattern(pattern) {
+			return fmt.Errorf("%w: result pattern %q", ErrInvalidFilter, pattern)
+		}
+	}
+	return nil
+}
+
+// isTransactionType reports whether s looks like a TransactionType such as
+// "Payment" or "NFTokenMint".
+func isTransactionType(s string) bool {
+	if s == "" || len(s) > 64 || s[0] < 'A' || s[0] > 'Z' {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// isResultPattern reports whether pattern is an engine result code, a
+// result class prefix followed by "*", or "*".
+func isResultPattern(pattern string) bool {
+	for _, class := range []string{"tes", "tec", "tef", "tel", "tem", "ter"} {
+		if strings.HasPrefix(pattern, class) {
+			return !strings.Contains(strings.TrimSuffix(pattern, "*"), "*")
+		}
+	}
+	return pattern == "*"
+}
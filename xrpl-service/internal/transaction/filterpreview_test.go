@@ -0,0 +1,140 @@
+package transaction
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func offerMessage(hash, result string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":          "transaction",
+		"validated":     true,
+		"engine_result": result,
+		"ledger_index":  float64(100),
+		"transaction": map[string]interface{}{
+			"TransactionType": "OfferCreate",
+			"hash":            hash,
+			"Account":         "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		},
+	}
+}
+
+func TestPreviewFilterReplaysUnfilteredTraffic(t *testing.T) {
+	fake := clock.NewFake(time.Unix(1700000000, 0))
+	l := NewListener(nil, 10000000, nil, nil, ListenerOptions{FilterPreviewWindow: 10 * time.Minute, Clock: fake})
+
+	// Twenty minutes ago, outside the window.
+	l.handleMessage(previewPaymentMessage("OLD", true, "tesSUCCESS", 0))
+	fake.Advance(10 * time.Minute)
+
+	small := previewPaymentMessage("SMALL", true, "tesSUCCESS", 0)
+	small["transaction"].(map[string]interface{})["Amount"] = "2000000"
+	for _, msg := range []map[string]interface{}{
+		previewPaymentMessage("P1", true, "tesSUCCESS", 0),
+		previewPaymentMessage("P2", true, "tecPATH_DRY", 0),
+		previewPaymentMessage("PROPOSED", false, "tesSUCCESS", 0),
+		small,
+		offerMessage("O1", "tesSUCCESS"),
+		offerMessage("O2", "tecKILLED"),
+	} {
+		l.handleMessage(msg)
+	}
+	fake.Advance(10 * time.Minute)
+	<-l.transactionBuffer
+	<-l.transactionBuffer
+
+	preview, err := l.PreviewFilter(models.TxFilter{
+		MinPaymentDrops:  1000000,
+		TransactionTypes: []string{"Payment", "OfferCreate"},
+		AllowedResults:   []string{"tes*", "tec*"},
+	}, 0)
+	if err != nil {
+		t.Fatalf("PreviewFilter failed: %v", err)
+	}
+	if preview.WindowSeconds != 600 || preview.Sampled != 5 {
+		t.Fatalf("expected 5 validated transactions over 600s, got %d over %gs", preview.Sampled, preview.WindowSeconds)
+	}
+	if preview.Current.Matched != 1 || preview.Current.ByType["Payment"] != 1 {
+		t.Fatalf("expected the live filter to match P1 only, got %+v", preview.Current)
+	}
+	if preview.Projected.Matched != 5 || preview.Projected.ByType["OfferCreate"] != 2 {
+		t.Fatalf("expected the candidate to match every transaction, got %+v", preview.Projected)
+	}
+	if got := preview.Projected.PerMinute; got != 0.5 {
+		t.Fatalf("expected 0.5 events per minute, got %g", got)
+	}
+	if live := l.Filter(); live.MinPaymentDrops != 10000000 || len(live.AllowedResults) != 1 {
+		t.Fatalf("expected the live filter to be unchanged, got %+v", live)
+	}
+}
+
+func TestPreviewFilterDefaultsAndWindow(t *testing.T) {
+	fake := clock.NewFake(time.Unix(1700000000, 0))
+	l := NewListener(nil, 1000000, nil, nil, ListenerOptions{FilterPreviewWindow: 15 * time.Minute, Clock: fake})
+	l.handleMessage(previewPaymentMessage("P1", true, "tesSUCCESS", 0))
+	fake.Advance(5 * time.Minute)
+	l.handleMessage(previewPaymentMessage("P2", true, "tesSUCCESS", 0))
+	fake.Advance(time.Minute)
+
+	preview, err := l.PreviewFilter(models.TxFilter{MinPaymentDrops: 50000000}, 2*time.Minute)
+	if err != nil {
+		t.Fatalf("PreviewFilter failed: %v", err)
+	}
+	if preview.WindowSeconds != 120 || preview.Sampled != 1 {
+		t.Fatalf("expected the last 2 minutes replayed, got %d over %gs", preview.Sampled, preview.WindowSeconds)
+	}
+	if preview.Candidate.TransactionTypes[0] != "Payment" || preview.Candidate.AllowedResults[0] != "tesSUCCESS" {
+		t.Fatalf("expected omitted fields taken from the live filter, got %+v", preview.Candidate)
+	}
+	if preview.Current.Matched != 1 || preview.Projected.Matched != 0 {
+		t.Fatalf("expected the higher threshold to drop the payment, got %+v / %+v", preview.Current, preview.Projected)
+	}
+
+	// A window longer than recorded traffic starts at the first sample.
+	preview, _ = l.PreviewFilter(models.TxFilter{}, 0)
+	if preview.WindowSeconds != 360 || preview.Sampled != 2 {
+		t.Fatalf("expected the 6 minutes recorded, got %d over %gs", preview.Sampled, preview.WindowSeconds)
+	}
+}
+
+func TestPreviewFilterRejectsInvalidFilters(t *testing.T) {
+	l := NewListener(nil, 1000000, nil, nil, ListenerOptions{FilterPreviewWindow: time.Minute})
+	for _, filter := range []models.TxFilter{
+		{MinPaymentDrops: -1},
+		{TransactionTypes: []string{"payment"}},
+		{AllowedResults: []string{"tes*SUCCESS"}},
+		{AllowedResults: []string{"SUCCESS"}},
+	} {
+		if _, err := l.PreviewFilter(filter, 0); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("expected %+v rejected, got %v", filter, err)
+		}
+	}
+
+	disabled := NewListener(nil, 1000000, nil, nil)
+	disabled.handleMessage(previewPaymentMessage("P1", true, "tesSUCCESS", 0))
+	if disabled.traffic != nil {
+		t.Fatal("expected no traffic kept without a preview window")
+	}
+	if _, err := disabled.PreviewFilter(models.TxFilter{}, 0); !errors.Is(err, ErrFilterPreviewDisabled) {
+		t.Fatalf("expected previews disabled, got %v", err)
+	}
+}
+
+func TestTrafficBufferWrapsAtCapacity(t *testing.T) {
+	b := newTrafficBuffer(time.Hour)
+	for i := 0; i < maxTrafficSamples+10; i++ {
+		b.record(trafficSample{at: int64(i), txType: "Payment", drops: 1})
+	}
+	projection := models.FilterProjection{ByType: map[string]int{}}
+	from, sampled := b.replay(0, []sampleFilter{newSampleFilter(models.TxFilter{TransactionTypes: []string{"Payment"}, AllowedResults: []string{"*"}})}, []*models.FilterProjection{&projection})
+	if from != 10 || sampled != maxTrafficSamples {
+		t.Fatalf("expected the replay to start at the oldest kept sample, got %d with %d samples", from, sampled)
+	}
+	if projection.Matched != 0 {
+		t.Fatalf("expected samples without a result to be filtered, got %d", projection.Matched)
+	}
+}
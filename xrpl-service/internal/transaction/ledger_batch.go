@@ -0,0 +1,108 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// ledgerBatchRetention is how many ledgers behind the newest one a batch is
+// kept, so workers still draining an older ledger keep sharing lookups.
+const ledgerBatchRetention = 2
+
+// ledgerGeoBatches groups geolocation lookups by ledger. Transactions of the
+// same ledger resolve each account of their combined candidate set once and
+// share the result, so an account that appears in many transactions of one
+// ledger costs a single account_info call.
+type ledgerGeoBatches struct {
+	mu      sync.Mutex
+	batches map[uint32]*ledgerGeoBatch
+	newest  uint32
+}
+
+// ledgerGeoBatch holds the lookups of one ledger, keyed by account.
+type ledgerGeoBatch struct {
+	mu      sync.Mutex
+	lookups map[string]*accountLookup
+}
+
+// accountLookup is a lookup that is in flight until done is closed.
+type accountLookup struct {
+	done chan struct{}
+	geo  *models.GeoLocation
+	err  error
+}
+
+func newLedgerGeoBatches() *ledgerGeoBatches {
+	return &ledgerGeoBatches{batches: make(map[uint32]*ledgerGeoBatch)}
+}
+
+// forLedger returns the batch for ledgerIndex, creating it if needed, and
+// drops batches that fell out of the retention window. Transactions without a
+// ledger index get no batch.
+func (b *ledgerGeoBatches) forLedger(ledgerIndex uint32) *ledgerGeoBatch {
+	if b == nil || ledgerIndex == 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ledgerIndex+ledgerBatchRetention < b.newest {
+		return nil
+	}
+	batch, ok := b.batches[ledgerIndex]
+	if !ok {
+		batch = &ledgerGeoBatch{lookups: make(map[string]*accountLookup)}
+		b.batches[ledgerIndex] = batch
+	}
+	if ledgerIndex > b.newest {
+		b.newest = ledgerIndex
+		for index := range b.batches {
+			if index+ledgerBatchRetention < b.newest {
+				delete(b.batches, index)
+			}
+		}
+	}
+	return batch
+}
+
+// resolve returns the batch's result for account, running lookup only if no
+// other transaction of the ledger has started it. Callers waiting on another
+// transaction's lookup give up when ctx is done.
+func (b *ledgerGeoBatch) resolve(
+	ctx context.Context,
+	account string,
+	lookup func() (*models.GeoLocation, error),
+) (*models.GeoLocation, error) {
+	if b == nil {
+		return lookup()
+	}
+
+	b.mu.Lock()
+	existing, ok := b.lookups[account]
+	if !ok {
+		existing = &accountLookup{done: make(chan struct{})}
+		b.lookups[account] = existing
+	}
+	b.mu.Unlock()
+
+	if ok {
+		metrics.GeolocationBatchLookupsTotal.WithLabelValues("shared").Inc()
+		select {
+		case <-existing.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	} else {
+		metrics.GeolocationBatchLookupsTotal.WithLabelValues("lookup").Inc()
+		existing.geo, existing.err = lookup()
+		close(existing.done)
+	}
+
+	if existing.geo == nil {
+		return nil, existing.err
+	}
+	geo := *existing.geo
+	return &geo, existing.err
+}
@@ -0,0 +1,75 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+type countingGeoResolver struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (r *countingGeoResolver) ResolveAccountGeo(ctx context.Context, client xrpl.NodeClient, account string) (*models.GeoLocation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[account]++
+	return &models.GeoLocation{Latitude: 1, Longitude: 2, City: account}, nil
+}
+
+func TestEnrichTransaction_SharesLookupsWithinLedger(t *testing.T) {
+	whale := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	destinations := []string{
+		"rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY",
+		"rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe",
+		"rDsbeomae4FXwgQTJp9Rs64Qg9vDiTCdBv",
+	}
+	resolver := &countingGeoResolver{calls: make(map[string]int)}
+	listener := NewListener(nil, 1, resolver, nil)
+
+	var wg sync.WaitGroup
+	for _, destination := range destinations {
+		wg.Add(1)
+		go func(destination string) {
+			defer wg.Done()
+			tx := &models.Transaction{LedgerIndex: 100, Account: whale, Destination: destination}
+			listener.enrichTransaction(context.Background(), tx)
+			if len(tx.Locations) != 2 || tx.Locations[0].City != whale {
+				t.Errorf("expected whale and destination locations, got %+v", tx.Locations)
+			}
+		}(destination)
+	}
+	wg.Wait()
+
+	if resolver.calls[whale] != 1 {
+		t.Fatalf("expected one lookup for the shared account, got %d", resolver.calls[whale])
+	}
+
+	listener.enrichTransaction(context.Background(), &models.Transaction{LedgerIndex: 101, Account: whale})
+	if resolver.calls[whale] != 2 {
+		t.Fatalf("expected a new lookup in the next ledger, got %d", resolver.calls[whale])
+	}
+}
+
+func TestLedgerGeoBatchesEvictsOldLedgers(t *testing.T) {
+	batches := newLedgerGeoBatches()
+	first := batches.forLedger(10)
+	if batches.forLedger(10) != first {
+		t.Fatal("expected the same batch for the same ledger")
+	}
+
+	batches.forLedger(10 + ledgerBatchRetention + 1)
+	if _, ok := batches.batches[10]; ok {
+		t.Fatal("expected ledger 10 batch to be evicted")
+	}
+	if batches.forLedger(10) != nil {
+		t.Fatal("expected no batch for a ledger outside the retention window")
+	}
+	if batches.forLedger(0) != nil {
+		t.Fatal("expected no batch without a ledger index")
+	}
+}
@@ -0,0 +1,77 @@
+package transaction
+
+import (
+	"sync"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/timeutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// LedgerFeeCallback receives the fees a validated ledger destroyed once the
+// stream has moved on to a later ledger.
+type LedgerFeeCallback func(*models.LedgerFees)
+
+// ledgerFeeTally sums the fees of every validated transaction, before the
+// payment filters, for the ledger the stream is currently delivering. The
+// transactions stream sends a ledger's transactions together, so the first
+// transaction of a later ledger completes the current one.
+type ledgerFeeTally struct {
+	mu      sync.Mutex
+	current *models.LedgerFees
+}
+
+// observe adds msg's fee to the tally and returns the completed ledger when
+// msg belongs to a later one. Messages of ledgers that already completed,
+// e.g. replayed after a reconnect, are ignored.
+func (t *ledgerFeeTally) observe(msg map[string]interface{}) *models.LedgerFees {
+	if jsonutil.String(msg, "type") != "transaction" || !jsonutil.Bool(msg, "validated") {
+		return nil
+	}
+	txnRaw := jsonutil.Map(msg, "transaction")
+	if txnRaw == nil {
+		return nil
+	}
+	ledgerIndex, ok := toUint32(msg["ledger_index"])
+	if !ok || ledgerIndex == 0 {
+		return nil
+	}
+	fee, ok := parseDrops(txnRaw["Fee"])
+	if !ok {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var completed *models.LedgerFees
+	if t.current != nil {
+		if ledgerIndex < t.current.LedgerIndex {
+			return nil
+		}
+		if ledgerIndex > t.current.LedgerIndex {
+			completed = t.current
+			t.current = nil
+		}
+	}
+	if t.current == nil {
+		t.current = &models.LedgerFees{LedgerIndex: ledgerIndex}
+		if closeTime, ok := ledgerCloseTime(msg, txnRaw); ok {
+			t.current.CloseTime = timeutil.RippleToUnix(closeTime)
+		}
+	}
+	t.current.Transactions++
+	t.current.FeeDrops += fee
+	return completed
+}
+
+// notifyLedgerFees passes a completed ledger to the fee callbacks.
+func (l *Listener) notifyLedgerFees(fees *models.LedgerFees) {
+	l.mu.RLock()
+	callbacks := make([]LedgerFeeCallback, len(l.ledgerFeeCallbacks))
+	copy(callbacks, l.ledgerFeeCallbacks)
+	l.mu.RUnlock()
+
+	for _, callback := range callbacks {
+		callback(fees)
+	}
+}
@@ -3,23 +3,30 @@ package transaction
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/brandon/xrpl-validator-service/internal/models"
-	"github.com/brandon/xrpl-validator-service/internal/xrpl"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/debugcapture"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/timeutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
 	"github.com/sirupsen/logrus"
 )
 
-const rippleEpochOffset = 946684800
 const tfPartialPayment = 0x00020000
-const reconnectInterval = 5 * time.Second
+const connectionCheckInterval = time.Second
 const defaultTransactionBufferSize = 2048
 const defaultGeoEnrichmentQueueSize = 2048
-const defaultGeoWorkerCount = 8
+const defaultGeoWorkerCount = 16
 const defaultMaxGeoCandidates = 6
+const defaultAllowedResult = "tesSUCCESS"
 const xrplBase58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
 
 // AccountGeoResolver resolves XRPL accounts to geolocation.
@@ -29,17 +36,39 @@ type AccountGeoResolver interface {
 
 // Listener handles transaction stream subscriptions and callbacks
 type Listener struct {
-	client            xrpl.NodeClient
-	logger            *logrus.Logger
-	mu                sync.RWMutex
-	callbacks         []TransactionCallback
-	isSubscribed      bool
-	stopChan          chan struct{}
-	transactionBuffer chan *models.Transaction
-	geoEnrichmentQ    chan *models.Transaction
-	minPaymentDrops   int64
-	geoWorkerCount    int
-	maxGeoCandidates  int
+	client               xrpl.NodeClient
+	logger               *logrus.Logger
+	mu                   sync.RWMutex
+	callbacks            []TransactionCallback
+	processors           []Processor
+	isSubscribed         bool
+	paused               bool
+	stopChan             chan struct{}
+	transactionBuffer    chan *models.Transaction
+	geoEnrichmentQ       chan *models.Transaction
+	lateEnrichmentQ      chan *models.Transaction
+	shards               []*enrichShard // sticky workers, replacing the shared queues
+	geoUpdateCallbacks   []GeoUpdateCallback
+	ledgerFeeCallbacks   []LedgerFeeCallback
+	ledgerFees           ledgerFeeTally
+	shapeCallbacks       []ShapeCallback
+	preview              bool
+	previewQ             chan previewEvent
+	provisional          provisionalTracker
+	provisionalCallbacks []ProvisionalCallback
+	settlementCallbacks  []SettlementCallback
+	minPaymentDrops      int64
+	geoWorkerCount       int
+	maxGeoCandidates     int
+	allowedResults       resultFilter
+	ledgerBatches        *ledgerGeoBatches
+	streams              []string
+	dispatcher           *xrpl.Dispatcher
+	clock                clock.Clock
+	debugCapture         *debugcapture.Capturer
+	reconnectPolicy      xrpl.BackoffPolicy
+	lastTransactionAt    atomic.Int64   // unix milliseconds
+	traffic              *trafficBuffer // nil when filter previews are disabled
 
 	geoResolver AccountGeoResolver
 }
@@ -50,11 +79,44 @@ type ListenerOptions struct {
 	GeoEnrichmentQSize    int
 	GeoWorkerCount        int
 	MaxGeoCandidates      int
+	// AllowedResults lists the engine results that pass the listener, e.g.
+	// "tesSUCCESS" or "tecPATH_DRY". A trailing "*" matches a prefix, so
+	// "tec*" admits every tec code. Defaults to tesSUCCESS only.
+	AllowedResults []string
+	// Streams lists the upstream streams to subscribe to, e.g. "ledger" or
+	// "validations" next to "transactions". Handlers for them are
+	// registered with HandleStream. Defaults to transactions only.
+	Streams []string
+	// ShardByAccount gives each enrichment worker its own queue, fed the
+	// transactions whose source account hashes to it, and its own
+	// lock-free account caches instead of the shared per-ledger batches.
+	ShardByAccount bool
+	// Preview forwards unvalidated payments from transactions_proposed to
+	// provisional callbacks, followed by a settlement when their validated
+	// result arrives or they expire. Streams must include
+	// transactions_proposed.
+	Preview bool
+	// FilterPreviewWindow is how much validated traffic, before filtering,
+	// is kept to replay through candidate filters. Zero disables previews.
+	FilterPreviewWindow time.Duration
+	// Clock schedules reconnect checks and stamps received transactions.
+	// Nil uses the system clock.
+	Clock clock.Clock
+	// DebugCapture, when set, keeps redacted samples of stream messages
+	// that fail to parse.
+	DebugCapture *debugcapture.Capturer
+	// Reconnect paces reconnects after the WebSocket drops. Zero fields
+	// take the xrpl package defaults.
+	Reconnect xrpl.BackoffPolicy
 }
 
 // TransactionCallback is a function that processes transactions
 type TransactionCallback func(*models.Transaction)
 
+// GeoUpdateCallback receives locations resolved for a transaction that was
+// forwarded before enrichment.
+type GeoUpdateCallback func(*models.TxGeoUpdate)
+
 // NewListener creates a new transaction listener
 func NewListener(
 	client xrpl.NodeClient,
@@ -89,19 +151,51 @@ func NewListener(
 	if maxGeoCandidates <= 0 {
 		maxGeoCandidates = defaultMaxGeoCandidates
 	}
+	allowedResults := opts.AllowedResults
+	if len(allowedResults) == 0 {
+		allowedResults = []string{defaultAllowedResult}
+	}
+	streams := opts.Streams
+	if len(streams) == 0 {
+		streams = []string{xrpl.StreamTransactions}
+	}
 
-	return &Listener{
+	l := &Listener{
 		client:            client,
 		logger:            logger,
 		callbacks:         make([]TransactionCallback, 0),
 		stopChan:          make(chan struct{}),
 		transactionBuffer: make(chan *models.Transaction, transactionBufferSize),
-		geoEnrichmentQ:    make(chan *models.Transaction, geoQueueSize),
 		minPaymentDrops:   minPaymentDrops,
 		geoWorkerCount:    geoWorkerCount,
 		maxGeoCandidates:  maxGeoCandidates,
+		allowedResults:    newResultFilter(allowedResults),
+		ledgerBatches:     newLedgerGeoBatches(),
+		streams:           streams,
+		dispatcher:        xrpl.NewDispatcher(),
+		clock:             clock.OrReal(opts.Clock),
+		debugCapture:      opts.DebugCapture,
+		reconnectPolicy:   opts.Reconnect,
 		geoResolver:       geoResolver,
+		preview:           opts.Preview,
+	}
+	if opts.FilterPreviewWindow > 0 {
+		l.traffic = newTrafficBuffer(opts.FilterPreviewWindow)
+	}
+	if opts.ShardByAccount {
+		l.shards = newEnrichShards(geoWorkerCount, geoQueueSize)
+	} else {
+		l.geoEnrichmentQ = make(chan *models.Transaction, geoQueueSize)
+		l.lateEnrichmentQ = make(chan *models.Transaction, geoQueueSize)
+	}
+	l.dispatcher.Handle(xrpl.StreamTransactions, func(msg map[string]interface{}) {
+		l.handleMessage(msg)
+	})
+	if l.preview {
+		l.previewQ = make(chan previewEvent, geoQueueSize)
+		l.dispatcher.Handle(xrpl.StreamTransactionsProposed, l.handleProposed)
 	}
+	return l
 }
 
 // AddCallback registers a callback function for transaction processing
@@ -111,6 +205,36 @@ func (l *Listener) AddCallback(callback TransactionCallback) {
 	l.callbacks = append(l.callbacks, callback)
 }
 
+// AddGeoUpdateCallback registers a callback for late enrichment results.
+func (l *Listener) AddGeoUpdateCallback(callback GeoUpdateCallback) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.geoUpdateCallbacks = append(l.geoUpdateCallbacks, callback)
+}
+
+// AddLedgerFeeCallback registers a callback for the fees of each validated
+// ledger. Fees are counted for every streamed transaction, not only those
+// that pass the listener's filters.
+func (l *Listener) AddLedgerFeeCallback(callback LedgerFeeCallback) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ledgerFeeCallbacks = append(l.ledgerFeeCallbacks, callback)
+}
+
+// HandleStream registers a handler for the messages of an upstream stream.
+// Handlers only run for streams listed in ListenerOptions.Streams and must
+// be registered before Start.
+func (l *Listener) HandleStream(stream string, handler xrpl.StreamHandler) {
+	l.dispatcher.Handle(stream, handler)
+}
+
+// Streams returns the upstream streams the listener subscribes to.
+func (l *Listener) Streams() []string {
+	out := make([]string, len(l.streams))
+	copy(out, l.streams)
+	return out
+}
+
 // Start begins listening for transactions
 func (l *Listener) Start(ctx context.Context) error {
 	l.mu.Lock()
@@ -129,23 +253,33 @@ func (l *Listener) Start(ctx context.Context) error {
 		}
 	}
 
-	err := l.client.Subscribe(ctx, []string{"transactions"}, l.handleMessage)
+	err := l.client.Subscribe(ctx, l.streams, l.dispatcher.Dispatch)
 	if err != nil {
-		return fmt.Errorf("failed to subscribe to transactions: %w", err)
+		return fmt.Errorf("failed to subscribe to %s: %w", strings.Join(l.streams, ", "), err)
 	}
 
 	l.mu.Lock()
 	l.isSubscribed = true
 	l.mu.Unlock()
 
-	l.logger.WithField("min_payment_drops", l.minPaymentDrops).Info("Transaction listener started")
+	l.logger.WithFields(logrus.Fields{
+		"min_payment_drops": l.minPaymentDrops,
+		"streams":           l.streams,
+		"preview":           l.preview,
+	}).Info("Transaction listener started")
 
 	go l.processTransactions()
 	if l.geoResolver != nil {
-		for i := 0; i < l.geoWorkerCount; i++ {
+		for _, shard := range l.shards {
+			go l.processShard(shard)
+		}
+		for i := 0; i < l.geoWorkerCount && l.shards == nil; i++ {
 			go l.processGeoEnrichment()
 		}
 	}
+	if l.preview {
+		go l.processPreview()
+	}
 	go l.maintainSubscription(ctx)
 
 	return nil
@@ -163,9 +297,9 @@ func (l *Listener) Stop(ctx context.Context) error {
 	close(l.stopChan)
 
 	if l.client != nil && l.client.IsConnected() {
-		err := l.client.Unsubscribe(ctx, []string{"transactions"})
+		err := l.client.Unsubscribe(ctx, l.streams)
 		if err != nil {
-			l.logger.WithError(err).Error("Failed to unsubscribe from transactions")
+			l.logger.WithError(err).Error("Failed to unsubscribe from upstream streams")
 			return err
 		}
 	}
@@ -181,10 +315,23 @@ func (l *Listener) handleMessage(msg interface{}) {
 	if !ok {
 		return
 	}
+	if completed := l.ledgerFees.observe(msgMap); completed != nil {
+		l.notifyLedgerFees(completed)
+	}
+	l.notifyShape(msgMap)
+	if msgType, _ := msgMap["type"].(string); msgType == "transaction" {
+		l.lastTransactionAt.Store(l.clock.Now().UnixMilli())
+		l.observeDomainChange(msgMap)
+		l.recordTraffic(msgMap)
+	}
 
 	tx, err := l.parseTransaction(msgMap)
+	if jsonutil.Bool(msgMap, "validated") {
+		l.settleProvisional(msgMap, tx)
+	}
 	if err != nil {
 		l.logger.WithError(err).Debug("Skipping transaction")
+		l.debugCapture.Capture(debugcapture.SourceTransaction, err, msgMap)
 		return
 	}
 	if tx == nil {
@@ -196,14 +343,58 @@ func (l *Listener) handleMessage(msg interface{}) {
 		return
 	}
 
+	queue := l.geoEnrichmentQ
+	if l.shards != nil {
+		queue = l.shardFor(tx.Account).queue
+	}
 	select {
-	case l.geoEnrichmentQ <- tx:
+	case queue <- tx:
 	case <-l.stopChan:
 		return
 	default:
 		l.logger.Warn("Geo enrichment queue full, forwarding transaction without enrichment")
 		l.enqueueTransaction(tx)
+		l.enqueueLateEnrichment(tx)
+	}
+}
+
+// lateTransactionPool holds the copies queued for late enrichment, which
+// are discarded once their geo update is sent. They pile up exactly when
+// the enrichment queue is saturated, so they are reused.
+var lateTransactionPool = sync.Pool{
+	New: func() interface{} { return new(models.Transaction) },
+}
+
+// enqueueLateEnrichment schedules a copy of an already forwarded transaction
+// for enrichment when the workers are idle.
+func (l *Listener) enqueueLateEnrichment(tx *models.Transaction) {
+	if len(tx.GeoCandidates) == 0 {
+		return
 	}
+	late := lateTransactionPool.Get().(*models.Transaction)
+	late.Hash = tx.Hash
+	late.LedgerIndex = tx.LedgerIndex
+	late.Account = tx.Account
+	late.Destination = tx.Destination
+	late.GeoCandidates = tx.GeoCandidates
+	queue := l.lateEnrichmentQ
+	if l.shards != nil {
+		queue = l.shardFor(tx.Account).late
+	}
+	select {
+	case queue <- late:
+		metrics.GeolocationEnrichTotal.WithLabelValues("late_queued").Inc()
+	default:
+		metrics.GeolocationEnrichTotal.WithLabelValues("late_dropped").Inc()
+		releaseLateTransaction(late)
+	}
+}
+
+// releaseLateTransaction returns a late enrichment copy to the pool. The
+// geo update keeps its locations slice, not the copy itself.
+func releaseLateTransaction(tx *models.Transaction) {
+	*tx = models.Transaction{}
+	lateTransactionPool.Put(tx)
 }
 
 // processTransactions processes buffered transactions
@@ -211,6 +402,8 @@ func (l *Listener) processTransactions() {
 	for {
 		select {
 		case tx := <-l.transactionBuffer:
+			l.runProcessors(tx)
+
 			l.mu.RLock()
 			callbacks := make([]TransactionCallback, len(l.callbacks))
 			copy(callbacks, l.callbacks)
@@ -228,16 +421,51 @@ func (l *Listener) processTransactions() {
 
 func (l *Listener) processGeoEnrichment() {
 	for {
+		// Live transactions take priority over late enrichment.
+		select {
+		case tx := <-l.geoEnrichmentQ:
+			l.enrichTransaction(context.Background(), tx)
+			l.enqueueTransaction(tx)
+			continue
+		case <-l.stopChan:
+			return
+		default:
+		}
+
 		select {
 		case tx := <-l.geoEnrichmentQ:
 			l.enrichTransaction(context.Background(), tx)
 			l.enqueueTransaction(tx)
+		case tx := <-l.lateEnrichmentQ:
+			l.enrichLate(tx, nil)
 		case <-l.stopChan:
 			return
 		}
 	}
 }
 
+// enrichLate enriches a transaction that was forwarded without locations,
+// in shard when sharded, and notifies geo update callbacks if any were
+// resolved.
+func (l *Listener) enrichLate(tx *models.Transaction, shard *enrichShard) {
+	defer releaseLateTransaction(tx)
+	l.enrichTransactionIn(context.Background(), tx, shard)
+	if len(tx.Locations) == 0 {
+		return
+	}
+	metrics.GeolocationEnrichTotal.WithLabelValues("late_resolved").Inc()
+
+	l.mu.RLock()
+	callbacks := make([]GeoUpdateCallback, len(l.geoUpdateCallbacks))
+	copy(callbacks, l.geoUpdateCallbacks)
+	l.mu.RUnlock()
+
+	update := &models.TxGeoUpdate{Hash: tx.Hash, LedgerIndex: tx.LedgerIndex, Locations: tx.Locations, EnrichedAt: tx.EnrichedAt}
+	for _, callback := range callbacks {
+		callback(update)
+	}
+}
+
 func (l *Listener) enqueueTransaction(tx *models.Transaction) {
 	if tx == nil {
 		return
@@ -251,10 +479,54 @@ func (l *Listener) enqueueTransaction(tx *models.Transaction) {
 	}
 }
 
+// Pause unsubscribes from the upstream streams until Resume. Transactions
+// already buffered are still delivered, and the subscription is not
+// restored if the connection drops meanwhile.
+func (l *Listener) Pause(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.paused {
+		return nil
+	}
+	if l.isSubscribed && l.client != nil && l.client.IsConnected() {
+		if err := l.client.Unsubscribe(ctx, l.streams); err != nil {
+			return fmt.Errorf("failed to unsubscribe from upstream streams: %w", err)
+		}
+	}
+	l.paused = true
+	return nil
+}
+
+// Resume subscribes to the upstream streams again after Pause, reconnecting
+// if needed.
+func (l *Listener) Resume(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.paused {
+		return nil
+	}
+	l.paused = false
+	if !l.isSubscribed || l.client == nil {
+		return nil
+	}
+	if !l.client.IsConnected() {
+		// maintainSubscription reconnects and resubscribes.
+		return nil
+	}
+	if err := l.client.Subscribe(ctx, l.streams, nil); err != nil {
+		return fmt.Errorf("failed to resubscribe to upstream streams: %w", err)
+	}
+	return nil
+}
+
 // maintainSubscription reconnects and resubscribes if the WebSocket drops.
+// The first attempt follows the drop; failed attempts back off under the
+// reconnect policy, which pauses them while its circuit is open.
 func (l *Listener) maintainSubscription(parentCtx context.Context) {
-	ticker := time.NewTicker(reconnectInterval)
+	ticker := l.clock.NewTicker(connectionCheckInterval)
 	defer ticker.Stop()
+	backoff := xrpl.NewBackoff(l.reconnectPolicy)
+	var retryAt time.Time
 
 	for {
 		select {
@@ -262,46 +534,78 @@ func (l *Listener) maintainSubscription(parentCtx context.Context) {
 			return
 		case <-l.stopChan:
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			l.mu.RLock()
-			subscribed := l.isSubscribed
+			subscribed := l.isSubscribed && !l.paused
 			l.mu.RUnlock()
 			if !subscribed || l.client == nil || l.client.IsConnected() {
 				continue
 			}
-
-			reconnectCtx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-			if err := l.client.Connect(reconnectCtx); err != nil {
-				l.logger.WithError(err).Warn("Failed to reconnect transaction stream")
-				cancel()
+			now := l.clock.Now()
+			if now.Before(retryAt) {
 				continue
 			}
-			if err := l.client.Subscribe(reconnectCtx, []string{"transactions"}, nil); err != nil {
-				l.logger.WithError(err).Warn("Failed to resubscribe transaction stream")
+
+			if err := l.reconnect(); err != nil {
+				wait := backoff.Failure()
+				retryAt = now.Add(wait)
+				metrics.UpstreamReconnectsTotal.WithLabelValues("xrpl", "failure").Inc()
+				entry := l.logger.WithError(err).WithFields(logrus.Fields{
+					"failures": backoff.Failures(),
+					"retry_in": wait.String(),
+				})
+				if backoff.State() == xrpl.CircuitOpen {
+					metrics.UpstreamCircuitOpen.WithLabelValues("xrpl").Set(1)
+					entry.Error("Transaction stream reconnects keep failing, pausing them")
+				} else {
+					entry.Warn("Failed to reconnect transaction stream")
+				}
+				continue
 			}
-			cancel()
+			backoff.Reset()
+			retryAt = time.Time{}
+			metrics.UpstreamReconnectsTotal.WithLabelValues("xrpl", "success").Inc()
+			metrics.UpstreamCircuitOpen.WithLabelValues("xrpl").Set(0)
 		}
 	}
 }
 
+// reconnect connects the client again and resubscribes the streams. A
+// failed resubscription is logged only; the connection stays up and the
+// next drop retries both.
+func (l *Listener) reconnect() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+	if err := l.client.Connect(ctx); err != nil {
+		return err
+	}
+	if err := l.client.Subscribe(ctx, l.streams, nil); err != nil {
+		l.logger.WithError(err).Warn("Failed to resubscribe upstream streams")
+	}
+	return nil
+}
+
 // parseTransaction converts a raw stream message to a Transaction model.
 func (l *Listener) parseTransaction(msg map[string]interface{}) (*models.Transaction, error) {
-	msgType, _ := msg["type"].(string)
-	if msgType != "transaction" {
+	if jsonutil.String(msg, "type") != "transaction" {
 		return nil, nil
 	}
 
-	validated, _ := msg["validated"].(bool)
-	if !validated {
+	if !jsonutil.Bool(msg, "validated") {
 		return nil, nil
 	}
+	return l.parsePayment(msg)
+}
 
-	txnRaw, ok := msg["transaction"].(map[string]interface{})
-	if !ok {
+// parsePayment converts a transaction message, validated or not, to a
+// Transaction if it is a payment that passes the listener's filters.
+func (l *Listener) parsePayment(msg map[string]interface{}) (*models.Transaction, error) {
+	txnRaw := jsonutil.Map(msg, "transaction")
+	if txnRaw == nil {
 		return nil, fmt.Errorf("missing transaction payload")
 	}
 
-	txType, _ := txnRaw["TransactionType"].(string)
+	txType := jsonutil.String(txnRaw, "TransactionType")
 	if txType != "Payment" {
 		return nil, nil
 	}
@@ -311,6 +615,7 @@ func (l *Listener) parseTransaction(msg map[string]interface{}) (*models.Transac
 		return nil, nil
 	}
 
+	now := l.clock.Now()
 	tx := &models.Transaction{
 		Hash:            stringify(txnRaw["hash"]),
 		Account:         stringify(txnRaw["Account"]),
@@ -318,8 +623,14 @@ func (l *Listener) parseTransaction(msg map[string]interface{}) (*models.Transac
 		TransactionType: txType,
 		Amount:          strconv.FormatInt(amountDrops, 10),
 		Fee:             stringify(txnRaw["Fee"]),
-		Validated:       validated,
-		Timestamp:       toUnixTimestamp(msg["date"]),
+		Validated:       jsonutil.Bool(msg, "validated"),
+		Timestamp:       now.Unix(),
+		ReceivedAt:      now.UnixMilli(),
+	}
+	if closeTime, ok := ledgerCloseTime(msg, txnRaw); ok {
+		tx.CloseTime = closeTime
+		tx.CloseTimeISO = timeutil.FromRipple(closeTime).Format(time.RFC3339)
+		tx.Timestamp = timeutil.RippleToUnix(closeTime)
 	}
 
 	if tx.Hash == "" || tx.Account == "" || tx.Destination == "" {
@@ -330,23 +641,42 @@ func (l *Listener) parseTransaction(msg map[string]interface{}) (*models.Transac
 		tx.LedgerIndex = li
 	}
 
-	if result, ok := msg["engine_result"].(string); ok {
-		tx.TransactionResult = result
-	}
-	if tx.TransactionResult == "" {
-		if meta, ok := msg["meta"].(map[string]interface{}); ok {
-			tx.TransactionResult = stringify(meta["TransactionResult"])
-		}
-	}
-	if tx.TransactionResult != "tesSUCCESS" {
+	tx.TransactionResult = transactionResult(msg)
+	if !l.allowedResults.allows(tx.TransactionResult) {
 		return nil, nil
 	}
 
 	tx.GeoCandidates = gatherGeoCandidates(txnRaw, msg["meta"], tx.Account, tx.Destination, l.maxGeoCandidates)
+	tx.CreatedAccounts = createdAccounts(msg["meta"])
 
 	return tx, nil
 }
 
+// transactionResult returns the engine result of a transaction message,
+// which validated messages may carry only in their metadata.
+func transactionResult(msg map[string]interface{}) string {
+	if result := jsonutil.String(msg, "engine_result"); result != "" {
+		return result
+	}
+	return jsonutil.GetString(msg, "meta.TransactionResult")
+}
+
+// createdAccounts returns the accounts whose AccountRoot the transaction
+// created, i.e. accounts funded into existence by it.
+func createdAccounts(meta interface{}) []string {
+	var accounts []string
+	for _, node := range jsonutil.Slice(jsonutil.Object(meta), "AffectedNodes") {
+		created := jsonutil.Map(jsonutil.Object(node), "CreatedNode")
+		if jsonutil.String(created, "LedgerEntryType") != "AccountRoot" {
+			continue
+		}
+		if account := jsonutil.GetString(created, "NewFields.Account"); isLikelyXRPLAccount(account) {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts
+}
+
 func parseDrops(amount interface{}) (int64, bool) {
 	asString, ok := amount.(string)
 	if !ok {
@@ -418,32 +748,67 @@ func toUint32(v interface{}) (uint32, bool) {
 	return uint32(n), true
 }
 
-func toUnixTimestamp(v interface{}) int64 {
-	rippleTime, ok := v.(float64)
-	if !ok {
-		return time.Now().Unix()
+// ledgerCloseTime returns the close time of the transaction's ledger in
+// seconds since the Ripple epoch. Streams report it as ledger_close_time,
+// date (top level or on the transaction) or, in API v2, close_time_iso.
+func ledgerCloseTime(msg map[string]interface{}, txnRaw map[string]interface{}) (uint32, bool) {
+	for _, value := range []interface{}{msg["ledger_close_time"], msg["date"], txnRaw["date"]} {
+		if closeTime, ok := timeutil.ParseRipple(value); ok && closeTime > 0 {
+			return closeTime, true
+		}
 	}
-	return int64(rippleTime) + rippleEpochOffset
+	if iso, ok := msg["close_time_iso"].(string); ok {
+		parsed, err := time.Parse(time.RFC3339, iso)
+		if err != nil {
+			return 0, false
+		}
+		if closeTime, ok := timeutil.ToRipple(parsed); ok && closeTime > 0 {
+			return closeTime, true
+		}
+	}
+	return 0, false
 }
 
 // enrichTransaction adds geolocation points to transaction.
 func (l *Listener) enrichTransaction(ctx context.Context, tx *models.Transaction) {
+	l.enrichTransactionIn(ctx, tx, nil)
+}
+
+// enrichTransactionIn adds geolocation points to transaction, resolving
+// accounts through shard's caches, or through the ledger's shared batch
+// when shard is nil.
+func (l *Listener) enrichTransactionIn(ctx context.Context, tx *models.Transaction, shard *enrichShard) {
 	if l.geoResolver == nil || tx == nil {
 		return
 	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	defer func() { tx.EnrichedAt = l.clock.Now().UnixMilli() }()
 
 	candidates := prioritizeCandidates(tx.GeoCandidates, tx.Account, tx.Destination, l.maxGeoCandidates)
 	if len(candidates) == 0 {
 		return
 	}
 
+	// Share lookups with other transactions of the same ledger.
+	var batch *ledgerGeoBatch
+	if shard == nil {
+		batch = l.ledgerBatches.forLedger(tx.LedgerIndex)
+	}
 	locations := make([]*models.GeoLocation, 0, len(candidates))
 	for _, account := range candidates {
 		lookupCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
-		geo, err := l.geoResolver.ResolveAccountGeo(lookupCtx, l.client, account)
+		lookup := func() (*models.GeoLocation, error) {
+			return l.geoResolver.ResolveAccountGeo(lookupCtx, l.client, account)
+		}
+		var geo *models.GeoLocation
+		var err error
+		if shard != nil {
+			geo, err = shard.resolve(l.clock.Now(), account, lookup)
+		} else {
+			geo, err = batch.resolve(lookupCtx, account, lookup)
+		}
 		cancel()
 		if err != nil {
 			l.logger.WithError(err).WithField("account", account).Debug("Failed to resolve account geolocation")
@@ -453,6 +818,14 @@ func (l *Listener) enrichTransaction(ctx context.Context, tx *models.Transaction
 			continue
 		}
 
+		switch account {
+		case tx.Account:
+			geo.Role = models.LocationRoleSource
+		case tx.Destination:
+			geo.Role = models.LocationRoleDestination
+		default:
+			geo.Role = models.LocationRoleExtra
+		}
 		locations = append(locations, geo)
 	}
 	if len(locations) > 0 {
@@ -460,6 +833,45 @@ func (l *Listener) enrichTransaction(ctx context.Context, tx *models.Transaction
 	}
 }
 
+// Geo candidate role scores; higher scores are enriched first. An account
+// keeps the best role it appears in and gains up to maxCandidateActivityBonus
+// for each metadata field that references it.
+const (
+	candidateScoreSource      = 300
+	candidateScoreDestination = 200
+	candidateScoreIssuer      = 50 // issuer of an amount field
+	candidateScoreTxnField    = 20 // any other account field on the transaction
+	candidateScoreMetadata    = 10 // referenced only by affected ledger entries
+	maxCandidateActivityBonus = 9
+)
+
+// amountFields are transaction fields holding amounts whose issuer is worth
+// locating.
+var amountFields = []string{"Amount", "SendMax", "DeliverMax", "DeliverMin", "TakerGets", "TakerPays", "LimitAmount"}
+
+type geoCandidate struct {
+	account  string
+	score    int
+	activity int
+}
+
+// candidateScratch is the working set of gatherGeoCandidates, pooled since
+// it is needed once per transaction and discarded right after.
+type candidateScratch struct {
+	index      map[string]int // account to position in candidates
+	candidates []geoCandidate
+}
+
+var candidateScratchPool = sync.Pool{
+	New: func() interface{} {
+		return &candidateScratch{index: make(map[string]int)}
+	},
+}
+
+// gatherGeoCandidates scores every account referenced by a transaction by its
+// role (source/destination > amount issuer > other transaction fields >
+// metadata-only) and activity in the metadata, and returns the best
+// maxCandidates accounts, highest score first.
 func gatherGeoCandidates(
 	txnRaw map[string]interface{},
 	meta interface{},
@@ -467,27 +879,69 @@ func gatherGeoCandidates(
 	destination string,
 	maxCandidates int,
 ) []string {
-	candidates := make([]string, 0, maxCandidates)
-	seen := make(map[string]struct{})
-	add := func(candidate string) {
+	scratch := candidateScratchPool.Get().(*candidateScratch)
+	defer func() {
+		clear(scratch.index)
+		scratch.candidates = scratch.candidates[:0]
+		candidateScratchPool.Put(scratch)
+	}()
+	// note returns a pointer that is valid until the next call.
+	note := func(candidate string, score int) *geoCandidate {
 		trimmed := strings.TrimSpace(candidate)
 		if !isLikelyXRPLAccount(trimmed) {
-			return
+			return nil
 		}
-		if _, exists := seen[trimmed]; exists {
-			return
+		i, ok := scratch.index[trimmed]
+		if !ok {
+			i = len(scratch.candidates)
+			scratch.index[trimmed] = i
+			scratch.candidates = append(scratch.candidates, geoCandidate{account: trimmed, score: score})
+		} else if score > scratch.candidates[i].score {
+			scratch.candidates[i].score = score
 		}
-		seen[trimmed] = struct{}{}
-		candidates = append(candidates, trimmed)
+		return &scratch.candidates[i]
 	}
 
-	add(account)
-	add(destination)
-	collectCandidateAccounts(txnRaw, add)
-	collectCandidateAccounts(meta, add)
+	note(account, candidateScoreSource)
+	note(destination, candidateScoreDestination)
+	for _, field := range amountFields {
+		if amount, ok := txnRaw[field].(map[string]interface{}); ok {
+			note(stringify(amount["issuer"]), candidateScoreIssuer)
+		}
+	}
+	collectCandidateAccounts(txnRaw, func(candidate string) {
+		note(candidate, candidateScoreTxnField)
+	})
+	if metaMap, ok := meta.(map[string]interface{}); ok {
+		for _, field := range []string{"delivered_amount", "DeliveredAmount"} {
+			if amount, ok := metaMap[field].(map[string]interface{}); ok {
+				note(stringify(amount["issuer"]), candidateScoreIssuer)
+			}
+		}
+	}
+	collectCandidateAccounts(meta, func(candidate string) {
+		if c := note(candidate, candidateScoreMetadata); c != nil {
+			c.activity++
+		}
+	})
 
-	if maxCandidates > 0 && len(candidates) > maxCandidates {
-		return candidates[:maxCandidates]
+	scored := scratch.candidates
+	for i := range scored {
+		scored[i].score += min(scored[i].activity, maxCandidateActivityBonus)
+	}
+	slices.SortFunc(scored, func(a, b geoCandidate) int {
+		if a.score != b.score {
+			return b.score - a.score
+		}
+		return strings.Compare(a.account, b.account)
+	})
+	if maxCandidates > 0 && len(scored) > maxCandidates {
+		scored = scored[:maxCandidates]
+	}
+
+	candidates := make([]string, 0, len(scored))
+	for _, candidate := range scored {
+		candidates = append(candidates, candidate.account)
 	}
 	return candidates
 }
@@ -569,11 +1023,71 @@ func isLikelyXRPLAccount(account string) bool {
 	return true
 }
 
-// IsSubscribed returns subscription status
+// resultFilter matches engine results against exact codes and "prefix*"
+// patterns.
+type resultFilter struct {
+	patterns []string
+	exact    map[string]struct{}
+	prefixes []string
+}
+
+func newResultFilter(patterns []string) resultFilter {
+	filter := resultFilter{patterns: patterns, exact: make(map[string]struct{}, len(patterns))}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			filter.prefixes = append(filter.prefixes, prefix)
+			continue
+		}
+		if pattern != "" {
+			filter.exact[pattern] = struct{}{}
+		}
+	}
+	return filter
+}
+
+func (f resultFilter) allows(result string) bool {
+	if result == "" {
+		return false
+	}
+	if _, ok := f.exact[result]; ok {
+		return true
+	}
+	for _, prefix := range f.prefixes {
+		if strings.HasPrefix(result, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSubscribed returns subscription status. A paused listener is not
+// subscribed.
 func (l *Listener) IsSubscribed() bool {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
-	return l.isSubscribed
+	return l.isSubscribed && !l.paused
+}
+
+// UpstreamStatus returns the state of the upstream connection and of the
+// enrichment queues.
+func (l *Listener) UpstreamStatus() *models.UpstreamStatus {
+	status := &models.UpstreamStatus{
+		Subscribed:               l.IsSubscribed(),
+		LastTransactionAt:        l.lastTransactionAt.Load(),
+		EnrichmentQueueDepth:     len(l.geoEnrichmentQ),
+		EnrichmentQueueCapacity:  cap(l.geoEnrichmentQ),
+		LateEnrichmentQueueDepth: len(l.lateEnrichmentQ),
+	}
+	for _, shard := range l.shards {
+		status.EnrichmentQueueDepth += len(shard.queue)
+		status.EnrichmentQueueCapacity += cap(shard.queue)
+		status.LateEnrichmentQueueDepth += len(shard.late)
+	}
+	if l.client != nil {
+		status.Connected = l.client.IsConnected()
+	}
+	return status
 }
 
 // MinPaymentDrops returns the currently configured minimum payment amount filter.
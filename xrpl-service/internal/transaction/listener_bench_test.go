@@ -0,0 +1,269 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/xrpltest"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+// latencyGeoResolver simulates account_info + DNS round trips.
+type latencyGeoResolver struct {
+	latency time.Duration
+}
+
+func (r *latencyGeoResolver) ResolveAccountGeo(ctx context.Context, client xrpl.NodeClient, account string) (*models.GeoLocation, error) {
+	if r.latency > 0 {
+		time.Sleep(r.latency)
+	}
+	return &models.GeoLocation{Latitude: 1, Longitude: 1, CountryCode: "US", City: "Benchmark"}, nil
+}
+
+// sharedCacheGeoResolver mimics the geolocation resolver: a cache and a
+// missing-account set behind shared locks, with latency only on a miss.
+type sharedCacheGeoResolver struct {
+	latency time.Duration
+
+	mu      sync.RWMutex
+	cache   map[string]*models.GeoLocation
+	missMu  sync.Mutex
+	missing map[string]time.Time
+}
+
+func newSharedCacheGeoResolver(latency time.Duration) *sharedCacheGeoResolver {
+	return &sharedCacheGeoResolver{
+		latency: latency,
+		cache:   make(map[string]*models.GeoLocation),
+		missing: make(map[string]time.Time),
+	}
+}
+
+func (r *sharedCacheGeoResolver) ResolveAccountGeo(ctx context.Context, client xrpl.NodeClient, account string) (*models.GeoLocation, error) {
+	r.mu.RLock()
+	cached, ok := r.cache[account]
+	r.mu.RUnlock()
+	if ok {
+		geo := *cached
+		return &geo, nil
+	}
+	r.missMu.Lock()
+	_, missing := r.missing[account]
+	r.missMu.Unlock()
+	if missing {
+		return nil, nil
+	}
+	if r.latency > 0 {
+		time.Sleep(r.latency)
+	}
+	geo := &models.GeoLocation{Latitude: 1, Longitude: 1, CountryCode: "US", City: "Benchmark"}
+	r.mu.Lock()
+	r.cache[account] = geo
+	r.mu.Unlock()
+	copied := *geo
+	return &copied, nil
+}
+
+// benchmarkAccount returns a distinct, plausible account address for i.
+func benchmarkAccount(i int) string {
+	suffix := fmt.Sprintf("%08d", i)
+	suffix = strings.NewReplacer("0", "a", "1", "b", "2", "c", "3", "d", "4", "e", "5", "f", "6", "g", "7", "h", "8", "i", "9", "j").Replace(suffix)
+	return "rHb9CJAWyB4rj91VRWn96Dk" + suffix
+}
+
+func benchmarkMessage(i int) map[string]interface{} {
+	msg := xrpltest.PaymentMessage(
+		fmt.Sprintf("BENCH%08d", i),
+		"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		"rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY",
+		25_000_000,
+		90000000,
+	)
+	msg["meta"].(map[string]interface{})["AffectedNodes"] = []interface{}{
+		map[string]interface{}{"ModifiedNode": map[string]interface{}{"FinalFields": map[string]interface{}{
+			"Issuer": "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe",
+			"Owner":  "rDsbeomae4FXwgQTJp9Rs64Qg9vDiTCdBv",
+		}}},
+	}
+	return msg
+}
+
+func newBenchmarkListener(resolver AccountGeoResolver, opts ListenerOptions) (*Listener, *atomic.Int64) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	l := NewListener(nil, 1, resolver, logger, opts)
+
+	delivered := &atomic.Int64{}
+	l.AddCallback(func(*models.Transaction) { delivered.Add(1) })
+	go l.processTransactions()
+	for _, shard := range l.shards {
+		go l.processShard(shard)
+	}
+	for i := 0; i < l.geoWorkerCount && l.shards == nil; i++ {
+		go l.processGeoEnrichment()
+	}
+	return l, delivered
+}
+
+func waitForDelivered(delivered *atomic.Int64, want int64, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	last := delivered.Load()
+	lastChange := time.Now()
+	for delivered.Load() < want && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		if current := delivered.Load(); current != last {
+			last = current
+			lastChange = time.Now()
+		} else if time.Since(lastChange) > 250*time.Millisecond {
+			return
+		}
+	}
+}
+
+func BenchmarkParseTransaction(b *testing.B) {
+	l := NewListener(nil, 1, nil, nil)
+	msg := benchmarkMessage(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := l.parseTransaction(msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGatherGeoCandidates(b *testing.B) {
+	msg := benchmarkMessage(0)
+	txnRaw := msg["transaction"].(map[string]interface{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gatherGeoCandidates(txnRaw, msg["meta"], "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY", defaultMaxGeoCandidates)
+	}
+}
+
+// BenchmarkPipelineThroughput measures sustained parse→enrich→dispatch
+// throughput. Input is paced so queues never overflow; tx/s is reported.
+func BenchmarkPipelineThroughput(b *testing.B) {
+	for _, latency := range []time.Duration{0, time.Millisecond, 5 * time.Millisecond} {
+		for _, workers := range []int{4, 8, 16, 32} {
+			b.Run(fmt.Sprintf("latency=%s/workers=%d", latency, workers), func(b *testing.B) {
+				l, delivered := newBenchmarkListener(&latencyGeoResolver{latency: latency}, ListenerOptions{
+					GeoWorkerCount:   workers,
+					MaxGeoCandidates: defaultMaxGeoCandidates,
+				})
+				defer close(l.stopChan)
+
+				msg := benchmarkMessage(0)
+				b.ResetTimer()
+				start := time.Now()
+				for i := 0; i < b.N; i++ {
+					for len(l.geoEnrichmentQ) >= cap(l.geoEnrichmentQ)-1 {
+						runtime.Gosched()
+					}
+					l.handleMessage(msg)
+				}
+				waitForDelivered(delivered, int64(b.N), time.Minute)
+				elapsed := time.Since(start)
+				b.StopTimer()
+
+				b.ReportMetric(float64(delivered.Load())/elapsed.Seconds(), "tx/s")
+			})
+		}
+	}
+}
+
+// BenchmarkPipelineBurst feeds b.N messages at once and reports the fraction
+// that was dropped or forwarded unenriched at each queue size.
+func BenchmarkPipelineBurst(b *testing.B) {
+	for _, queueSize := range []int{256, 2048, 8192} {
+		b.Run(fmt.Sprintf("queue=%d", queueSize), func(b *testing.B) {
+			l, delivered := newBenchmarkListener(&latencyGeoResolver{latency: time.Millisecond}, ListenerOptions{
+				TransactionBufferSize: queueSize,
+				GeoEnrichmentQSize:    queueSize,
+				GeoWorkerCount:        defaultGeoWorkerCount,
+				MaxGeoCandidates:      defaultMaxGeoCandidates,
+			})
+			defer close(l.stopChan)
+
+			msg := benchmarkMessage(0)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.handleMessage(msg)
+			}
+			waitForDelivered(delivered, int64(b.N), time.Minute)
+			b.StopTimer()
+
+			b.ReportMetric(1-float64(delivered.Load())/float64(b.N), "drop-ratio")
+		})
+	}
+}
+
+// enrichmentQueueFor returns the queue handleMessage puts msg on.
+func enrichmentQueueFor(l *Listener, msg map[string]interface{}) chan *models.Transaction {
+	if l.shards == nil {
+		return l.geoEnrichmentQ
+	}
+	return l.shardFor(msg["transaction"].(map[string]interface{})["Account"].(string)).queue
+}
+
+// BenchmarkEnrichmentSharding compares the shared per-ledger batches with
+// sticky workers keyed by source account, on a stream of many accounts
+// whose locations are mostly cached. Every transaction of a ledger shares
+// one batch in the shared design, so workers contend on its lock and on the
+// resolver's; sticky workers answer repeats from worker-local caches.
+func BenchmarkEnrichmentSharding(b *testing.B) {
+	const accounts = 512
+	const perLedger = 200
+	messages := make([]map[string]interface{}, accounts)
+	for i := range messages {
+		messages[i] = xrpltest.PaymentMessage(
+			fmt.Sprintf("SHARD%08d", i),
+			benchmarkAccount(i),
+			benchmarkAccount((i*7+1)%accounts),
+			25_000_000,
+			0,
+		)
+	}
+	for _, sharded := range []bool{false, true} {
+		design := "shared"
+		if sharded {
+			design = "sticky"
+		}
+		for _, workers := range []int{4, 16, 32} {
+			b.Run(fmt.Sprintf("design=%s/workers=%d", design, workers), func(b *testing.B) {
+				l, delivered := newBenchmarkListener(newSharedCacheGeoResolver(time.Millisecond), ListenerOptions{
+					GeoWorkerCount:   workers,
+					MaxGeoCandidates: defaultMaxGeoCandidates,
+					ShardByAccount:   sharded,
+				})
+				defer close(l.stopChan)
+
+				b.ResetTimer()
+				start := time.Now()
+				for i := 0; i < b.N; i++ {
+					msg := messages[i%accounts]
+					msg["ledger_index"] = float64(90000000 + i/perLedger)
+					for queue := enrichmentQueueFor(l, msg); len(queue) >= cap(queue)-1; {
+						runtime.Gosched()
+					}
+					l.handleMessage(msg)
+				}
+				waitForDelivered(delivered, int64(b.N), time.Minute)
+				elapsed := time.Since(start)
+				b.StopTimer()
+
+				b.ReportMetric(float64(delivered.Load())/elapsed.Seconds(), "tx/s")
+			})
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package transaction
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/xrpltest"
+	"github.com/sirupsen/logrus"
+)
+
+const fuzzPaymentSeed = `{"type":"transaction","validated":true,"engine_result":"tesSUCCESS","ledger_index":90000000,"date":760000000,` +
+	`"transaction":{"TransactionType":"Payment","hash":"ABC","Account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","Destination":"rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY","Amount":"25000000","Fee":"12","Flags":131072},` +
+	`"meta":{"TransactionResult":"tesSUCCESS","delivered_amount":"25000000","AffectedNodes":[{"ModifiedNode":{"FinalFields":{"Issuer":"rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"}}}]}}`
+
+func addTransactionSeeds(f *testing.F) {
+	f.Add([]byte(fuzzPaymentSeed))
+	f.Add([]byte(`{"type":"transaction","validated":true,"transaction":{"TransactionType":"Payment","Amount":{"currency":"USD","value":"1"}},"meta":{"delivered_amount":"unavailable"}}`))
+	f.Add([]byte(`{"type":"transaction","validated":true,"transaction":"not-a-map"}`))
+	f.Add([]byte(`{"type":"transaction","validated":true,"ledger_index":-1,"date":1e300,"transaction":{"TransactionType":"Payment","Amount":"-5","Flags":-1}}`))
+	f.Add([]byte(`{"type":"ledgerClosed"}`))
+
+	entries, err := xrpltest.LoadCorpus()
+	if err != nil {
+		f.Fatalf("LoadCorpus: %v", err)
+	}
+	for _, entry := range entries {
+		data, err := json.Marshal(entry.Message)
+		if err != nil {
+			f.Fatalf("encode corpus entry %s: %v", entry.Name, err)
+		}
+		f.Add(data)
+	}
+}
+
+func FuzzParseTransaction(f *testing.F) {
+	addTransactionSeeds(f)
+
+	listener := NewListener(nil, 0, nil, nil)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var msg map[string]interface{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return
+		}
+		tx, err := listener.parseTransaction(msg)
+		if err != nil || tx == nil {
+			return
+		}
+		if tx.Hash == "" || tx.Account == "" || tx.Destination == "" {
+			t.Fatalf("parsed transaction missing required fields: %+v", tx)
+		}
+		if len(tx.GeoCandidates) > listener.maxGeoCandidates {
+			t.Fatalf("got %d geo candidates, limit %d", len(tx.GeoCandidates), listener.maxGeoCandidates)
+		}
+	})
+}
+
+func FuzzGatherGeoCandidates(f *testing.F) {
+	addTransactionSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var msg map[string]interface{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return
+		}
+		txnRaw, _ := msg["transaction"].(map[string]interface{})
+		account, _ := txnRaw["Account"].(string)
+		destination, _ := txnRaw["Destination"].(string)
+
+		candidates := gatherGeoCandidates(txnRaw, msg["meta"], account, destination, defaultMaxGeoCandidates)
+		if len(candidates) > defaultMaxGeoCandidates {
+			t.Fatalf("got %d candidates, limit %d", len(candidates), defaultMaxGeoCandidates)
+		}
+		seen := make(map[string]struct{}, len(candidates))
+		for _, candidate := range candidates {
+			if !isLikelyXRPLAccount(candidate) {
+				t.Fatalf("candidate %q is not an XRPL account", candidate)
+			}
+			if _, dup := seen[candidate]; dup {
+				t.Fatalf("duplicate candidate %q", candidate)
+			}
+			seen[candidate] = struct{}{}
+		}
+	})
+}
+
+func FuzzHandleMessage(f *testing.F) {
+	addTransactionSeeds(f)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	listener := NewListener(nil, 0, nil, logger, ListenerOptions{TransactionBufferSize: 1})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var msg interface{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return
+		}
+		listener.handleMessage(msg)
+		select {
+		case <-listener.transactionBuffer:
+		default:
+		}
+	})
+}
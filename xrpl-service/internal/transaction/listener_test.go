@@ -2,10 +2,14 @@ package transaction
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
-	"github.com/brandon/xrpl-validator-service/internal/models"
-	"github.com/brandon/xrpl-validator-service/internal/xrpl"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/timeutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
 )
 
 type mockGeoResolver struct {
@@ -126,6 +130,92 @@ func TestParseTransaction_FallsBackToAmountForNonPartialPayment(t *testing.T) {
 	}
 }
 
+func TestParseTransaction_PopulatesCloseTime(t *testing.T) {
+	listener := NewListener(nil, 1, nil, nil)
+	payment := func() map[string]interface{} {
+		return map[string]interface{}{
+			"type":          "transaction",
+			"validated":     true,
+			"engine_result": "tesSUCCESS",
+			"transaction": map[string]interface{}{
+				"TransactionType": "Payment",
+				"hash":            "PQR789",
+				"Account":         "rSource",
+				"Destination":     "rDest",
+				"Amount":          "5000000",
+			},
+		}
+	}
+
+	fromDate := payment()
+	fromDate["transaction"].(map[string]interface{})["date"] = float64(760000000)
+	fromISO := payment()
+	fromISO["close_time_iso"] = "2024-01-31T07:06:40Z"
+
+	for name, msg := range map[string]map[string]interface{}{"date": fromDate, "close_time_iso": fromISO} {
+		tx, err := listener.parseTransaction(msg)
+		if err != nil || tx == nil {
+			t.Fatalf("%s: expected transaction, got %v %v", name, tx, err)
+		}
+		if tx.CloseTime != 760000000 {
+			t.Errorf("%s: expected CloseTime 760000000, got %d", name, tx.CloseTime)
+		}
+		if tx.CloseTimeISO != "2024-01-31T07:06:40Z" {
+			t.Errorf("%s: expected CloseTimeISO 2024-01-31T07:06:40Z, got %s", name, tx.CloseTimeISO)
+		}
+		if tx.Timestamp != timeutil.RippleToUnix(760000000) {
+			t.Errorf("%s: expected Timestamp from close time, got %d", name, tx.Timestamp)
+		}
+	}
+
+	tx, _ := listener.parseTransaction(payment())
+	if tx == nil || tx.CloseTime != 0 || tx.CloseTimeISO != "" || tx.Timestamp == 0 {
+		t.Fatalf("expected receipt timestamp without close time, got %+v", tx)
+	}
+}
+
+func TestParseTransaction_FiltersByAllowedResults(t *testing.T) {
+	failedPayment := func(result string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":          "transaction",
+			"validated":     true,
+			"engine_result": result,
+			"transaction": map[string]interface{}{
+				"TransactionType": "Payment",
+				"hash":            "MNO456",
+				"Account":         "rSource",
+				"Destination":     "rDest",
+				"Amount":          "5000000",
+			},
+		}
+	}
+
+	defaultListener := NewListener(nil, 1, nil, nil)
+	if tx, _ := defaultListener.parseTransaction(failedPayment("tecPATH_DRY")); tx != nil {
+		t.Fatal("expected tecPATH_DRY to be filtered by default")
+	}
+
+	listener := NewListener(nil, 1, nil, nil, ListenerOptions{AllowedResults: []string{"tesSUCCESS", "tecUNFUNDED*"}})
+	tests := []struct {
+		result string
+		want   bool
+	}{
+		{result: "tesSUCCESS", want: true},
+		{result: "tecUNFUNDED_PAYMENT", want: true},
+		{result: "tecPATH_DRY", want: false},
+		{result: "", want: false},
+	}
+	for _, tt := range tests {
+		tx, err := listener.parseTransaction(failedPayment(tt.result))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.result, err)
+		}
+		if got := tx != nil; got != tt.want {
+			t.Errorf("%q: passed = %t, want %t", tt.result, got, tt.want)
+		}
+	}
+}
+
 func TestParseTransaction_CollectsGeoCandidatesFromIssuerAndMetadata(t *testing.T) {
 	listener := NewListener(nil, 1, nil, nil)
 	source := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
@@ -185,6 +275,54 @@ func TestParseTransaction_CollectsGeoCandidatesFromIssuerAndMetadata(t *testing.
 	}
 }
 
+func TestParseTransaction_CollectsCreatedAccounts(t *testing.T) {
+	listener := NewListener(nil, 1, nil, nil)
+	created := "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY"
+
+	msg := map[string]interface{}{
+		"type":      "transaction",
+		"validated": true,
+		"transaction": map[string]interface{}{
+			"TransactionType": "Payment",
+			"hash":            "NEW123",
+			"Account":         "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+			"Destination":     created,
+			"Amount":          "20000000",
+		},
+		"meta": map[string]interface{}{
+			"TransactionResult": "tesSUCCESS",
+			"AffectedNodes": []interface{}{
+				map[string]interface{}{
+					"ModifiedNode": map[string]interface{}{
+						"LedgerEntryType": "AccountRoot",
+						"FinalFields":     map[string]interface{}{"Account": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"},
+					},
+				},
+				map[string]interface{}{
+					"CreatedNode": map[string]interface{}{
+						"LedgerEntryType": "AccountRoot",
+						"NewFields":       map[string]interface{}{"Account": created, "Balance": "20000000"},
+					},
+				},
+				map[string]interface{}{
+					"CreatedNode": map[string]interface{}{
+						"LedgerEntryType": "RippleState",
+						"NewFields":       map[string]interface{}{"Account": "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"},
+					},
+				},
+			},
+		},
+	}
+
+	tx, err := listener.parseTransaction(msg)
+	if err != nil || tx == nil {
+		t.Fatalf("expected transaction, got %v %v", tx, err)
+	}
+	if len(tx.CreatedAccounts) != 1 || tx.CreatedAccounts[0] != created {
+		t.Fatalf("expected only the created AccountRoot, got %v", tx.CreatedAccounts)
+	}
+}
+
 func TestGatherGeoCandidates_LimitPreservesSourceAndDestination(t *testing.T) {
 	source := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
 	destination := "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY"
@@ -222,6 +360,243 @@ func TestGatherGeoCandidates_LimitPreservesSourceAndDestination(t *testing.T) {
 	}
 }
 
+func TestGatherGeoCandidates_ScoresIssuerAndActivityAboveMetadataNoise(t *testing.T) {
+	source := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	destination := "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY"
+	issuer := "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"
+	busyOwner := "rDsbeomae4FXwgQTJp9Rs64Qg9vDiTCdBv"
+	quietOwner := "rN7n7otQDd6FczFgLdSqtcsAUxDkw6fzRH"
+
+	txnRaw := map[string]interface{}{
+		"Account":     source,
+		"Destination": destination,
+		"SendMax": map[string]interface{}{
+			"currency": "USD",
+			"issuer":   issuer,
+			"value":    "100",
+		},
+	}
+	meta := map[string]interface{}{
+		"AffectedNodes": []interface{}{
+			map[string]interface{}{"ModifiedNode": map[string]interface{}{"FinalFields": map[string]interface{}{"Owner": quietOwner}}},
+			map[string]interface{}{"ModifiedNode": map[string]interface{}{"FinalFields": map[string]interface{}{"Owner": busyOwner}}},
+			map[string]interface{}{"ModifiedNode": map[string]interface{}{"FinalFields": map[string]interface{}{"Owner": busyOwner}}},
+		},
+	}
+
+	candidates := gatherGeoCandidates(txnRaw, meta, source, destination, 4)
+	want := []string{source, destination, issuer, busyOwner}
+	if len(candidates) != len(want) {
+		t.Fatalf("expected %d candidates, got %+v", len(want), candidates)
+	}
+	for i, account := range want {
+		if candidates[i] != account {
+			t.Fatalf("expected %+v, got %+v", want, candidates)
+		}
+	}
+}
+
+func TestHandleMessage_OverflowSchedulesLateGeoUpdate(t *testing.T) {
+	source := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	destination := "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY"
+	resolver := &mockGeoResolver{
+		locations: map[string]*models.GeoLocation{
+			source: {Latitude: 37.7749, Longitude: -122.4194, City: "San Francisco"},
+		},
+	}
+	listener := NewListener(nil, 1, resolver, nil, ListenerOptions{GeoEnrichmentQSize: 1})
+	var updates []*models.TxGeoUpdate
+	listener.AddGeoUpdateCallback(func(update *models.TxGeoUpdate) {
+		updates = append(updates, update)
+	})
+	listener.geoEnrichmentQ <- &models.Transaction{}
+
+	listener.handleMessage(map[string]interface{}{
+		"type":          "transaction",
+		"validated":     true,
+		"engine_result": "tesSUCCESS",
+		"ledger_index":  float64(100),
+		"transaction": map[string]interface{}{
+			"TransactionType": "Payment",
+			"hash":            "LATE1",
+			"Account":         source,
+			"Destination":     destination,
+			"Amount":          "5000000",
+		},
+	})
+
+	forwarded := <-listener.transactionBuffer
+	if forwarded.Hash != "LATE1" || len(forwarded.Locations) != 0 {
+		t.Fatalf("expected unenriched transaction to be forwarded, got %+v", forwarded)
+	}
+	if status := listener.UpstreamStatus(); status.LastTransactionAt == 0 || status.EnrichmentQueueDepth != 1 ||
+		status.EnrichmentQueueCapacity != 1 || status.LateEnrichmentQueueDepth != 1 {
+		t.Fatalf("unexpected upstream status %+v", status)
+	}
+	select {
+	case late := <-listener.lateEnrichmentQ:
+		listener.enrichLate(late, nil)
+	default:
+		t.Fatal("expected transaction to be queued for late enrichment")
+	}
+
+	if len(updates) != 1 || updates[0].Hash != "LATE1" || updates[0].LedgerIndex != 100 {
+		t.Fatalf("expected one geo update for LATE1, got %+v", updates)
+	}
+	if len(updates[0].Locations) != 1 || updates[0].Locations[0].City != "San Francisco" {
+		t.Fatalf("unexpected late locations: %+v", updates[0].Locations)
+	}
+	if len(forwarded.Locations) != 0 {
+		t.Fatal("late enrichment must not modify the forwarded transaction")
+	}
+}
+
+func TestHandleMessage_TalliesFeesPerLedger(t *testing.T) {
+	listener := NewListener(nil, 1000000, nil, nil)
+	var ledgers []*models.LedgerFees
+	listener.AddLedgerFeeCallback(func(fees *models.LedgerFees) {
+		ledgers = append(ledgers, fees)
+	})
+
+	message := func(ledgerIndex float64, txType, fee string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":          "transaction",
+			"validated":     true,
+			"engine_result": "tecPATH_DRY",
+			"ledger_index":  ledgerIndex,
+			"transaction": map[string]interface{}{
+				"TransactionType": txType,
+				"Fee":             fee,
+			},
+		}
+	}
+	listener.handleMessage(message(100, "OfferCreate", "12"))
+	listener.handleMessage(message(100, "Payment", "10"))
+	listener.handleMessage(message(99, "OfferCreate", "1000"))
+	if len(ledgers) != 0 {
+		t.Fatalf("expected ledger 100 to stay open, got %+v", ledgers)
+	}
+
+	listener.handleMessage(message(101, "TrustSet", "15"))
+	if len(ledgers) != 1 || ledgers[0].LedgerIndex != 100 || ledgers[0].Transactions != 2 || ledgers[0].FeeDrops != 22 {
+		t.Fatalf("expected ledger 100 with 22 drops over 2 transactions, got %+v", ledgers)
+	}
+}
+
+func TestHandleMessage_ReportsShapesBeforeFilters(t *testing.T) {
+	listener := NewListener(nil, 1000000, nil, nil)
+	var shapes []*models.TransactionShape
+	listener.AddShapeCallback(func(shape *models.TransactionShape) {
+		shapes = append(shapes, shape)
+	})
+
+	listener.handleMessage(map[string]interface{}{
+		"type":          "transaction",
+		"validated":     true,
+		"engine_result": "tecPATH_DRY",
+		"ledger_index":  float64(100),
+		"transaction": map[string]interface{}{
+			"TransactionType": "Payment",
+			"Fee":             "12",
+			"Memos":           []interface{}{map[string]interface{}{"Memo": map[string]interface{}{"MemoData": "00"}}},
+		},
+		"meta": map[string]interface{}{
+			"AffectedNodes": []interface{}{
+				map[string]interface{}{"ModifiedNode": map[string]interface{}{"LedgerEntryType": "AccountRoot"}},
+				map[string]interface{}{"CreatedNode": map[string]interface{}{"LedgerEntryType": "RippleState"}},
+			},
+		},
+	})
+	listener.handleMessage(map[string]interface{}{"type": "transaction", "validated": false})
+
+	if len(shapes) != 1 {
+		t.Fatalf("expected one shape for the validated transaction, got %d", len(shapes))
+	}
+	shape := shapes[0]
+	if shape.TransactionType != "Payment" || !shape.HasMemos || shape.SizeBytes == 0 {
+		t.Fatalf("unexpected shape %+v", shape)
+	}
+	if len(shape.LedgerEntryTypes) != 2 || shape.LedgerEntryTypes[0] != "AccountRoot" || shape.LedgerEntryTypes[1] != "RippleState" {
+		t.Fatalf("unexpected ledger entry types %v", shape.LedgerEntryTypes)
+	}
+}
+
+func TestStart_SubscribesConfiguredStreams(t *testing.T) {
+	client := xrpl.NewMockClient(nil)
+	listener := NewListener(client, 1, nil, nil, ListenerOptions{Streams: []string{"transactions", "ledger"}})
+	var ledgers []float64
+	listener.HandleStream(xrpl.StreamLedger, func(msg map[string]interface{}) {
+		index, _ := msg["ledger_index"].(float64)
+		ledgers = append(ledgers, index)
+	})
+	received := make(chan *models.Transaction, 1)
+	listener.AddCallback(func(tx *models.Transaction) { received <- tx })
+	if err := listener.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer listener.Stop(context.Background())
+
+	if streams := client.Streams(); len(streams) != 2 || streams[0] != "transactions" || streams[1] != "ledger" {
+		t.Fatalf("expected transactions and ledger subscriptions, got %v", streams)
+	}
+
+	client.Emit(map[string]interface{}{"type": "ledgerClosed", "ledger_index": float64(100)})
+	client.Emit(map[string]interface{}{
+		"type":          "transaction",
+		"validated":     true,
+		"engine_result": "tesSUCCESS",
+		"transaction": map[string]interface{}{
+			"TransactionType": "Payment",
+			"hash":            "STREAM1",
+			"Account":         "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+			"Destination":     "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY",
+			"Amount":          "5000000",
+		},
+	})
+
+	if len(ledgers) != 1 || ledgers[0] != 100 {
+		t.Fatalf("expected the ledger handler to see ledger 100, got %v", ledgers)
+	}
+	select {
+	case tx := <-received:
+		if tx.Hash != "STREAM1" {
+			t.Fatalf("unexpected transaction %+v", tx)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the transaction to reach callbacks")
+	}
+}
+
+func TestParseAndEnrich_StampProcessingTimes(t *testing.T) {
+	fake := clock.NewFake(time.UnixMilli(1_700_000_000_250))
+	listener := NewListener(nil, 1, &mockGeoResolver{}, nil, ListenerOptions{Clock: fake})
+
+	tx, err := listener.parseTransaction(map[string]interface{}{
+		"type":      "transaction",
+		"validated": true,
+		"transaction": map[string]interface{}{
+			"TransactionType": "Payment",
+			"hash":            "ABC123",
+			"Account":         "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+			"Destination":     "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY",
+			"Amount":          "5000000",
+		},
+		"meta": map[string]interface{}{"TransactionResult": "tesSUCCESS"},
+	})
+	if err != nil || tx == nil {
+		t.Fatalf("expected a transaction, got %v", err)
+	}
+	if tx.ReceivedAt != 1_700_000_000_250 || tx.EnrichedAt != 0 {
+		t.Fatalf("expected only the receipt time, got received_at=%d enriched_at=%d", tx.ReceivedAt, tx.EnrichedAt)
+	}
+
+	fake.Advance(40 * time.Millisecond)
+	listener.enrichTransaction(context.Background(), tx)
+	if tx.EnrichedAt != 1_700_000_000_290 {
+		t.Fatalf("expected the enrichment time, got %d", tx.EnrichedAt)
+	}
+}
+
 func TestEnrichTransaction_PopulatesLocations(t *testing.T) {
 	source := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
 	destination := "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY"
@@ -269,3 +644,118 @@ func TestEnrichTransaction_PopulatesLocations(t *testing.T) {
 		t.Fatalf("expected extra candidate location third, got %+v", tx.Locations[2])
 	}
 }
+
+func TestPauseUnsubscribesUntilResumed(t *testing.T) {
+	client := xrpl.NewMockClient(nil)
+	listener := NewListener(client, 1, nil, nil)
+	if err := listener.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer listener.Stop(context.Background())
+
+	if err := listener.Pause(context.Background()); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if streams := client.Streams(); len(streams) != 0 {
+		t.Fatalf("expected no subscriptions while paused, got %v", streams)
+	}
+	if listener.IsSubscribed() {
+		t.Fatal("expected a paused listener to report itself unsubscribed")
+	}
+
+	if err := listener.Resume(context.Background()); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if streams := client.Streams(); len(streams) != 1 || streams[0] != "transactions" {
+		t.Fatalf("expected the transactions stream to be resubscribed, got %v", streams)
+	}
+	if !listener.IsSubscribed() {
+		t.Fatal("expected the listener to be subscribed after resuming")
+	}
+}
+
+func TestMaintainSubscriptionReconnectsOnTick(t *testing.T) {
+	client := xrpl.NewMockClient(nil)
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	listener := NewListener(client, 1, nil, nil, ListenerOptions{Clock: fake})
+	if err := listener.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer listener.Stop(context.Background())
+
+	fake.BlockUntil(1)
+	client.Close()
+	fake.Advance(connectionCheckInterval / 2)
+	if client.IsConnected() {
+		t.Fatal("expected no reconnect before the next connection check")
+	}
+
+	fake.Advance(connectionCheckInterval / 2)
+	deadline := time.Now().Add(time.Second)
+	for !client.IsConnected() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the listener to reconnect on the next tick")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMaintainSubscriptionBacksOffFailedReconnects(t *testing.T) {
+	client := xrpl.NewMockClient(nil)
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	listener := NewListener(client, 1, nil, nil, ListenerOptions{
+		Clock:     fake,
+		Reconnect: xrpl.BackoffPolicy{Initial: 4 * time.Second, Max: time.Minute, Jitter: -1},
+	})
+	if err := listener.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer listener.Stop(context.Background())
+
+	fake.BlockUntil(1)
+	client.SetConnectErr(errors.New("connection refused"))
+	client.Close()
+
+	waitForConnectCalls := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for client.ConnectCalls() < want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d connect attempts, got %d", want, client.ConnectCalls())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	advance := func(d time.Duration) {
+		for step := time.Duration(0); step < d; step += connectionCheckInterval {
+			fake.Advance(connectionCheckInterval)
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	advance(connectionCheckInterval)
+	waitForConnectCalls(1)
+
+	// The first failure waits the initial 4s.
+	advance(3 * time.Second)
+	if calls := client.ConnectCalls(); calls != 1 {
+		t.Fatalf("expected no retry before the backoff elapsed, got %d attempts", calls)
+	}
+	advance(connectionCheckInterval)
+	waitForConnectCalls(2)
+
+	// The second failure doubles the wait to 8s.
+	advance(7 * time.Second)
+	if calls := client.ConnectCalls(); calls != 2 {
+		t.Fatalf("expected the backoff to double, got %d attempts", calls)
+	}
+	client.SetConnectErr(nil)
+	advance(connectionCheckInterval)
+	deadline := time.Now().Add(time.Second)
+	for !client.IsConnected() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the listener to reconnect once the backoff elapsed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,94 @@
+//go:build live
+
+package transaction
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+// Contract test against a public transaction stream. Run with:
+//
+//	go test -tags live ./internal/transaction/
+//
+// The endpoint can be overridden with LIVE_XRPL_WEBSOCKET_URL.
+func TestLiveTransactionStreamShape(t *testing.T) {
+	wsURL := os.Getenv("LIVE_XRPL_WEBSOCKET_URL")
+	if wsURL == "" {
+		wsURL = "wss://xrplcluster.com"
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	client := xrpl.NewClient("", wsURL, logger)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer client.Close()
+
+	var mu sync.Mutex
+	var messages []map[string]interface{}
+	done := make(chan struct{})
+	var doneOnce sync.Once
+	err := client.Subscribe(ctx, []string{"transactions"}, func(msg interface{}) {
+		msgMap, ok := msg.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if msgType, _ := msgMap["type"].(string); msgType != "transaction" {
+			return
+		}
+		mu.Lock()
+		messages = append(messages, msgMap)
+		if len(messages) >= 25 {
+			doneOnce.Do(func() { close(done) })
+		}
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(messages) == 0 {
+		t.Fatal("no transaction stream messages received")
+	}
+
+	listener := NewListener(nil, 1, nil, logger)
+	for _, msg := range messages {
+		txnRaw, ok := msg["transaction"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("stream message missing transaction object: %+v", msg)
+		}
+		if _, ok := txnRaw["TransactionType"].(string); !ok {
+			t.Fatalf("transaction missing TransactionType: %+v", txnRaw)
+		}
+		if _, ok := txnRaw["hash"].(string); !ok {
+			t.Fatalf("transaction missing hash: %+v", txnRaw)
+		}
+		if _, ok := toUint32(msg["ledger_index"]); !ok {
+			t.Fatalf("stream message missing numeric ledger_index: %+v", msg)
+		}
+		if _, ok := msg["meta"].(map[string]interface{}); !ok {
+			t.Fatalf("stream message missing meta object: %+v", msg)
+		}
+		if _, err := listener.parseTransaction(msg); err != nil {
+			t.Fatalf("parseTransaction rejected live message: %v", err)
+		}
+	}
+}
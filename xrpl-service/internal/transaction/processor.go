@@ -0,0 +1,58 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// processorTimeout bounds a single processor call.
+const processorTimeout = time.Second
+
+// Processor observes or mutates transactions after enrichment and before they
+// reach callbacks, e.g. to add proprietary Tags. Processors run in
+// registration order on the listener's dispatch goroutine, so they must be
+// fast. An error is logged and the remaining processors still run.
+type Processor interface {
+	Name() string
+	Process(ctx context.Context, tx *models.Transaction) error
+}
+
+// AddProcessor registers a processor run on every transaction before
+// callbacks.
+func (l *Listener) AddProcessor(processor Processor) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.processors = append(l.processors, processor)
+}
+
+// runProcessors applies the registered processors to tx in order.
+func (l *Listener) runProcessors(tx *models.Transaction) {
+	l.mu.RLock()
+	processors := make([]Processor, len(l.processors))
+	copy(processors, l.processors)
+	l.mu.RUnlock()
+
+	for _, processor := range processors {
+		ctx, cancel := context.WithTimeout(context.Background(), processorTimeout)
+		err := processor.Process(ctx, tx)
+		cancel()
+		if err != nil {
+			result := "error"
+			if errors.Is(err, context.DeadlineExceeded) {
+				result = "timeout"
+			}
+			metrics.TransactionProcessorTotal.WithLabelValues(processor.Name(), result).Inc()
+			l.logger.WithError(err).WithFields(logrus.Fields{
+				"processor": processor.Name(),
+				"hash":      tx.Hash,
+			}).Debug("Transaction processor failed")
+			continue
+		}
+		metrics.TransactionProcessorTotal.WithLabelValues(processor.Name(), "ok").Inc()
+	}
+}
@@ -0,0 +1,154 @@
+package transaction
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+type tagProcessor struct{}
+
+func (tagProcessor) Name() string { return "tag" }
+
+func (tagProcessor) Process(ctx context.Context, tx *models.Transaction) error {
+	if tx.Tags == nil {
+		tx.Tags = make(map[string]string)
+	}
+	tx.Tags["desk"] = "treasury"
+	return nil
+}
+
+type failingProcessor struct{}
+
+func (failingProcessor) Name() string { return "failing" }
+
+func (failingProcessor) Process(ctx context.Context, tx *models.Transaction) error {
+	return fmt.Errorf("boom")
+}
+
+func TestProcessorsRunBeforeCallbacks(t *testing.T) {
+	listener := NewListener(nil, 1, nil, nil)
+	listener.AddProcessor(failingProcessor{})
+	listener.AddProcessor(tagProcessor{})
+
+	received := make(chan *models.Transaction, 1)
+	listener.AddCallback(func(tx *models.Transaction) { received <- tx })
+	go listener.processTransactions()
+	defer close(listener.stopChan)
+
+	listener.enqueueTransaction(&models.Transaction{Hash: "ABC", Account: "rSource"})
+	select {
+	case tx := <-received:
+		if tx.Tags["desk"] != "treasury" {
+			t.Fatalf("expected processor tag, got %+v", tx.Tags)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for processed transaction")
+	}
+}
+
+// TestExecProcessorHelper is run as the external processor by the tests
+// below. It tags transactions and answers {} for hash "SKIP". With
+// EXEC_PROCESSOR_HELPER=stall it never reads its input.
+func TestExecProcessorHelper(t *testing.T) {
+	switch os.Getenv("EXEC_PROCESSOR_HELPER") {
+	case "1":
+	case "stall":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	default:
+		return
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request execRequest
+		json.Unmarshal(scanner.Bytes(), &request)
+		switch request.Transaction.Hash {
+		case "SKIP":
+			fmt.Println("{}")
+		case "SLOW":
+			time.Sleep(time.Second)
+		default:
+			request.Transaction.Tags = map[string]string{"source": "helper"}
+			out, _ := json.Marshal(execResponse{Transaction: request.Transaction})
+			fmt.Println(string(out))
+		}
+	}
+	os.Exit(0)
+}
+
+func newHelperProcessor(t *testing.T) *ExecProcessor {
+	return newHelperProcessorMode(t, "1")
+}
+
+func newHelperProcessorMode(t *testing.T, mode string) *ExecProcessor {
+	t.Setenv("EXEC_PROCESSOR_HELPER", mode)
+	processor := NewExecProcessor([]string{os.Args[0], "-test.run=^TestExecProcessorHelper$"}, time.Second, nil)
+	t.Cleanup(func() { processor.Close() })
+	return processor
+}
+
+func TestExecProcessorAppliesResponses(t *testing.T) {
+	processor := newHelperProcessor(t)
+
+	tx := &models.Transaction{Hash: "ABC", Account: "rSource", GeoCandidates: []string{"rSource"}}
+	if err := processor.Process(context.Background(), tx); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if tx.Tags["source"] != "helper" || tx.Account != "rSource" || len(tx.GeoCandidates) != 1 {
+		t.Fatalf("expected tagged transaction with candidates kept, got %+v", tx)
+	}
+
+	unchanged := &models.Transaction{Hash: "SKIP"}
+	if err := processor.Process(context.Background(), unchanged); err != nil || unchanged.Tags != nil {
+		t.Fatalf("expected unchanged transaction, got %+v, %v", unchanged, err)
+	}
+}
+
+func TestExecProcessorTimeoutRestartsProcess(t *testing.T) {
+	processor := newHelperProcessor(t)
+	processor.timeout = 100 * time.Millisecond
+
+	if err := processor.Process(context.Background(), &models.Transaction{Hash: "SLOW"}); err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if err := processor.Process(context.Background(), &models.Transaction{Hash: "ABC"}); err != errExecProcessorUnavailable {
+		t.Fatalf("expected processor to be unavailable until restart, got %v", err)
+	}
+
+	processor.mu.Lock()
+	processor.restartAfter = time.Time{}
+	processor.mu.Unlock()
+	tx := &models.Transaction{Hash: "ABC"}
+	if err := processor.Process(context.Background(), tx); err != nil || tx.Tags["source"] != "helper" {
+		t.Fatalf("expected restarted processor to answer, got %+v, %v", tx, err)
+	}
+}
+
+func TestExecProcessorWriteTimesOut(t *testing.T) {
+	processor := newHelperProcessorMode(t, "stall")
+	processor.timeout = 100 * time.Millisecond
+
+	// More than a pipe buffer, so the write blocks on a process that does
+	// not read.
+	tx := &models.Transaction{Hash: "ABC", Tags: map[string]string{"padding": strings.Repeat("x", 1<<20)}}
+	start := time.Now()
+	err := processor.Process(context.Background(), tx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the write to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the write bounded by the timeout, took %v", elapsed)
+	}
+	if err := processor.Process(context.Background(), &models.Transaction{Hash: "ABC"}); err != errExecProcessorUnavailable {
+		t.Fatalf("expected the stalled process to be stopped, got %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// provisionalTTL is how long a provisional payment without a
+// LastLedgerSequence waits for its validated result before it is cancelled.
+const provisionalTTL = 2 * time.Minute
+
+// maxPendingProvisional bounds the provisional payments awaiting settlement.
+// Further proposals are not previewed until some settle.
+const maxPendingProvisional = 4096
+
+// ProvisionalCallback receives payments seen on transactions_proposed before
+// they are validated.
+type ProvisionalCallback func(*models.Transaction)
+
+// SettlementCallback receives the outcome of a payment that was previewed.
+type SettlementCallback func(*models.TxSettlement)
+
+// previewEvent is a provisional transaction or a settlement, queued in order
+// for the preview worker.
+type previewEvent struct {
+	tx         *models.Transaction
+	settlement *models.TxSettlement
+}
+
+// pendingPayment is a previewed payment awaiting its validated result.
+type pendingPayment struct {
+	lastLedger uint32    // LastLedgerSequence, 0 when unset
+	expiresAt  time.Time // used when lastLedger is unset
+}
+
+// provisionalTracker remembers previewed payments until they settle.
+type provisionalTracker struct {
+	mu          sync.Mutex
+	pending     map[string]pendingPayment
+	sweptLedger uint32
+}
+
+// add starts tracking hash. It returns false when hash is already tracked or
+// the tracker is full.
+func (t *provisionalTracker) add(hash string, payment pendingPayment) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == nil {
+		t.pending = make(map[string]pendingPayment)
+	}
+	if _, ok := t.pending[hash]; ok || len(t.pending) >= maxPendingProvisional {
+		return false
+	}
+	t.pending[hash] = payment
+	return true
+}
+
+// remove stops tracking hash and reports whether it was tracked.
+func (t *provisionalTracker) remove(hash string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.pending[hash]; !ok {
+		return false
+	}
+	delete(t.pending, hash)
+	return true
+}
+
+// expire removes and returns the payments that can no longer be validated
+// once ledgerIndex is validated. It only sweeps once per ledger.
+func (t *provisionalTracker) expire(ledgerIndex uint32, now time.Time) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if ledgerIndex <= t.sweptLedger {
+		return nil
+	}
+	t.sweptLedger = ledgerIndex
+	var expired []string
+	for hash, payment := range t.pending {
+		if (payment.lastLedger != 0 && ledgerIndex > payment.lastLedger) ||
+			(payment.lastLedger == 0 && now.After(payment.expiresAt)) {
+			delete(t.pending, hash)
+			expired = append(expired, hash)
+		}
+	}
+	return expired
+}
+
+// AddProvisionalCallback registers a callback for previewed payments. They
+// only arrive when the listener runs with ListenerOptions.Preview.
+func (l *Listener) AddProvisionalCallback(callback ProvisionalCallback) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.provisionalCallbacks = append(l.provisionalCallbacks, callback)
+}
+
+// AddSettlementCallback registers a callback for the outcome of previewed
+// payments.
+func (l *Listener) AddSettlementCallback(callback SettlementCallback) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.settlementCallbacks = append(l.settlementCallbacks, callback)
+}
+
+// handleProposed previews an unvalidated payment from transactions_proposed.
+func (l *Listener) handleProposed(msg map[string]interface{}) {
+	tx, err := l.parseProvisional(msg)
+	if err != nil {
+		l.logger.WithError(err).Debug("Skipping provisional transaction")
+		return
+	}
+	if tx == nil {
+		return
+	}
+
+	payment := pendingPayment{expiresAt: l.clock.Now().Add(provisionalTTL)}
+	if lastLedger, ok := toUint32(jsonutil.Map(msg, "transaction")["LastLedgerSequence"]); ok {
+		payment.lastLedger = lastLedger
+	}
+	if !l.provisional.add(tx.Hash, payment) {
+		return
+	}
+	if !l.enqueuePreview(previewEvent{tx: tx}) {
+		l.provisional.remove(tx.Hash)
+		metrics.ProvisionalTransactionsTotal.WithLabelValues("dropped").Inc()
+		return
+	}
+	metrics.ProvisionalTransactionsTotal.WithLabelValues("previewed").Inc()
+}
+
+// parseProvisional converts an unvalidated payment message to a provisional
+// Transaction. Its engine result is the tentative one of the proposing node.
+func (l *Listener) parseProvisional(msg map[string]interface{}) (*models.Transaction, error) {
+	if jsonutil.String(msg, "type") != "transaction" || jsonutil.Bool(msg, "validated") {
+		return nil, nil
+	}
+	tx, err := l.parsePayment(msg)
+	if tx != nil {
+		tx.Provisional = true
+	}
+	return tx, err
+}
+
+// settleProvisional settles the previewed payment of a validated message, if
+// any: confirmed when it became tx, rejected when the listener filtered it
+// out. It then expires previews that missed their last ledger.
+func (l *Listener) settleProvisional(msg map[string]interface{}, tx *models.Transaction) {
+	if !l.preview {
+		return
+	}
+	hash := jsonutil.GetString(msg, "transaction.hash")
+	ledgerIndex, _ := toUint32(msg["ledger_index"])
+	now := l.clock.Now()
+
+	if hash != "" && l.provisional.remove(hash) {
+		settlement := &models.TxSettlement{
+			Hash:              hash,
+			Status:            models.SettlementConfirmed,
+			LedgerIndex:       ledgerIndex,
+			TransactionResult: jsonutil.String(msg, "engine_result"),
+			SettledAt:         now.UnixMilli(),
+		}
+		if settlement.TransactionResult == "" {
+			settlement.TransactionResult = jsonutil.GetString(msg, "meta.TransactionResult")
+		}
+		outcome := "confirmed"
+		if tx == nil {
+			settlement.Status = models.SettlementCancelled
+			settlement.Reason = models.SettlementReasonRejected
+			outcome = "rejected"
+		}
+		metrics.ProvisionalTransactionsTotal.WithLabelValues(outcome).Inc()
+		l.enqueuePreview(previewEvent{settlement: settlement})
+	}
+
+	for _, expired := range l.provisional.expire(ledgerIndex, now) {
+		metrics.ProvisionalTransactionsTotal.WithLabelValues("expired").Inc()
+		l.enqueuePreview(previewEvent{settlement: &models.TxSettlement{
+			Hash:      expired,
+			Status:    models.SettlementCancelled,
+			Reason:    models.SettlementReasonExpired,
+			SettledAt: now.UnixMilli(),
+		}})
+	}
+}
+
+// enqueuePreview queues event for the preview worker without blocking the
+// stream. It returns false when the queue is full.
+func (l *Listener) enqueuePreview(event previewEvent) bool {
+	select {
+	case l.previewQ <- event:
+		return true
+	default:
+		l.logger.Warn("Preview queue full, dropping provisional event")
+		return false
+	}
+}
+
+// processPreview enriches provisional transactions and hands them and
+// settlements to their callbacks, in the order they were queued so that a
+// settlement never overtakes its preview.
+func (l *Listener) processPreview() {
+	for {
+		select {
+		case event := <-l.previewQ:
+			if event.tx != nil {
+				l.enrichTransaction(context.Background(), event.tx)
+				l.notifyProvisional(event.tx)
+			}
+			if event.settlement != nil {
+				l.notifySettlement(event.settlement)
+			}
+		case <-l.stopChan:
+			return
+		}
+	}
+}
+
+func (l *Listener) notifyProvisional(tx *models.Transaction) {
+	l.mu.RLock()
+	callbacks := make([]ProvisionalCallback, len(l.provisionalCallbacks))
+	copy(callbacks, l.provisionalCallbacks)
+	l.mu.RUnlock()
+
+	for _, callback := range callbacks {
+		callback(tx)
+	}
+}
+
+func (l *Listener) notifySettlement(settlement *models.TxSettlement) {
+	l.mu.RLock()
+	callbacks := make([]SettlementCallback, len(l.settlementCallbacks))
+	copy(callbacks, l.settlementCallbacks)
+	l.mu.RUnlock()
+
+	l.logger.WithFields(logrus.Fields{
+		"hash":   settlement.Hash,
+		"status": settlement.Status,
+		"reason": settlement.Reason,
+	}).Debug("Settled provisional transaction")
+	for _, callback := range callbacks {
+		callback(settlement)
+	}
+}
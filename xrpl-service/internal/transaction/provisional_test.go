@@ -0,0 +1,142 @@
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+func previewPaymentMessage(hash string, validated bool, result string, lastLedger float64) map[string]interface{} {
+	txn := map[string]interface{}{
+		"TransactionType": "Payment",
+		"hash":            hash,
+		"Account":         "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		"Destination":     "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY",
+		"Amount":          "25000000",
+	}
+	if lastLedger > 0 {
+		txn["LastLedgerSequence"] = lastLedger
+	}
+	msg := map[string]interface{}{
+		"type":          "transaction",
+		"validated":     validated,
+		"engine_result": result,
+		"transaction":   txn,
+	}
+	if validated {
+		msg["ledger_index"] = float64(100)
+	} else {
+		msg["ledger_current_index"] = float64(100)
+	}
+	return msg
+}
+
+func newPreviewListener(t *testing.T, fake *clock.Fake) *Listener {
+	t.Helper()
+	return NewListener(nil, 1, nil, nil, ListenerOptions{
+		Streams: []string{xrpl.StreamTransactionsProposed},
+		Preview: true,
+		Clock:   fake,
+	})
+}
+
+// nextPreview returns the next queued preview event, failing if there is none.
+func nextPreview(t *testing.T, l *Listener) previewEvent {
+	t.Helper()
+	select {
+	case event := <-l.previewQ:
+		return event
+	default:
+		t.Fatal("expected a queued preview event")
+		return previewEvent{}
+	}
+}
+
+func TestPreviewConfirmsValidatedPayment(t *testing.T) {
+	l := newPreviewListener(t, clock.NewFake(time.Unix(1700000000, 0)))
+
+	l.dispatcher.Dispatch(previewPaymentMessage("P1", false, "tesSUCCESS", 0))
+	l.dispatcher.Dispatch(previewPaymentMessage("P1", false, "tesSUCCESS", 0))
+	event := nextPreview(t, l)
+	if event.tx == nil || !event.tx.Provisional || event.tx.Validated || event.tx.Hash != "P1" {
+		t.Fatalf("expected provisional P1, got %+v", event.tx)
+	}
+	if len(l.previewQ) != 0 {
+		t.Fatal("expected a repeated proposal to be previewed once")
+	}
+
+	l.dispatcher.Dispatch(previewPaymentMessage("P1", true, "tesSUCCESS", 0))
+	settlement := nextPreview(t, l).settlement
+	if settlement == nil || settlement.Status != models.SettlementConfirmed || settlement.LedgerIndex != 100 || settlement.TransactionResult != "tesSUCCESS" {
+		t.Fatalf("expected P1 confirmed in ledger 100, got %+v", settlement)
+	}
+	select {
+	case tx := <-l.transactionBuffer:
+		if tx.Provisional || !tx.Validated {
+			t.Fatalf("expected the validated transaction to be forwarded as final, got %+v", tx)
+		}
+	default:
+		t.Fatal("expected the validated transaction to be forwarded")
+	}
+}
+
+func TestPreviewCancelsRejectedAndExpiredPayments(t *testing.T) {
+	fake := clock.NewFake(time.Unix(1700000000, 0))
+	l := newPreviewListener(t, fake)
+
+	l.dispatcher.Dispatch(previewPaymentMessage("FAILED", false, "tesSUCCESS", 0))
+	l.dispatcher.Dispatch(previewPaymentMessage("LATE", false, "tesSUCCESS", 99))
+	l.dispatcher.Dispatch(previewPaymentMessage("QUEUED", false, "terQUEUED", 0))
+	nextPreview(t, l)
+	nextPreview(t, l)
+	if len(l.previewQ) != 0 {
+		t.Fatal("expected a proposal outside the allowed results not to be previewed")
+	}
+
+	l.dispatcher.Dispatch(previewPaymentMessage("FAILED", true, "tecPATH_DRY", 0))
+	rejected := nextPreview(t, l).settlement
+	if rejected == nil || rejected.Hash != "FAILED" || rejected.Status != models.SettlementCancelled ||
+		rejected.Reason != models.SettlementReasonRejected || rejected.TransactionResult != "tecPATH_DRY" {
+		t.Fatalf("expected FAILED rejected with tecPATH_DRY, got %+v", rejected)
+	}
+	expired := nextPreview(t, l).settlement
+	if expired == nil || expired.Hash != "LATE" || expired.Reason != models.SettlementReasonExpired {
+		t.Fatalf("expected LATE to expire past its last ledger, got %+v", expired)
+	}
+}
+
+func TestPreviewExpiresPaymentsWithoutLastLedgerAfterTTL(t *testing.T) {
+	fake := clock.NewFake(time.Unix(1700000000, 0))
+	l := newPreviewListener(t, fake)
+
+	l.dispatcher.Dispatch(previewPaymentMessage("OPEN", false, "tesSUCCESS", 0))
+	nextPreview(t, l)
+
+	validated := previewPaymentMessage("OTHER", true, "tesSUCCESS", 0)
+	l.dispatcher.Dispatch(validated)
+	if len(l.previewQ) != 0 {
+		t.Fatal("expected OPEN to wait within the TTL")
+	}
+
+	fake.Advance(provisionalTTL + time.Second)
+	validated["ledger_index"] = float64(101)
+	l.dispatcher.Dispatch(validated)
+	if expired := nextPreview(t, l).settlement; expired == nil || expired.Hash != "OPEN" || expired.Reason != models.SettlementReasonExpired {
+		t.Fatalf("expected OPEN to expire after the TTL, got %+v", expired)
+	}
+}
+
+func TestProposedTransactionsIgnoredWithoutPreview(t *testing.T) {
+	l := NewListener(nil, 1, nil, nil, ListenerOptions{Streams: []string{xrpl.StreamTransactionsProposed}})
+	l.dispatcher.Dispatch(previewPaymentMessage("P1", false, "tesSUCCESS", 0))
+	l.dispatcher.Dispatch(previewPaymentMessage("P1", true, "tesSUCCESS", 0))
+	if l.previewQ != nil {
+		t.Fatal("expected no preview queue without Preview")
+	}
+	if len(l.transactionBuffer) != 1 {
+		t.Fatalf("expected only the validated transaction, got %d", len(l.transactionBuffer))
+	}
+}
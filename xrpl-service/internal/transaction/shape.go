@@ -0,0 +1,72 @@
+package transaction
+
+import (
+	"encoding/json"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// ShapeCallback receives the shape of each validated streamed transaction.
+type ShapeCallback func(*models.TransactionShape)
+
+// affectedNodeKinds are the keys of meta.AffectedNodes entries.
+var affectedNodeKinds = []string{"CreatedNode", "ModifiedNode", "DeletedNode"}
+
+// AddShapeCallback registers a callback for the shape of every validated
+// streamed transaction. Like fees, shapes are taken before the listener's
+// filters.
+func (l *Listener) AddShapeCallback(callback ShapeCallback) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.shapeCallbacks = append(l.shapeCallbacks, callback)
+}
+
+// notifyShape passes msg's shape to the shape callbacks, if any are
+// registered and msg is a validated transaction.
+func (l *Listener) notifyShape(msg map[string]interface{}) {
+	l.mu.RLock()
+	callbacks := make([]ShapeCallback, len(l.shapeCallbacks))
+	copy(callbacks, l.shapeCallbacks)
+	l.mu.RUnlock()
+	if len(callbacks) == 0 {
+		return
+	}
+
+	shape := transactionShape(msg)
+	if shape == nil {
+		return
+	}
+	for _, callback := range callbacks {
+		callback(shape)
+	}
+}
+
+// transactionShape returns the shape of a validated transaction message, or
+// nil for other messages.
+func transactionShape(msg map[string]interface{}) *models.TransactionShape {
+	if jsonutil.String(msg, "type") != "transaction" || !jsonutil.Bool(msg, "validated") {
+		return nil
+	}
+	txnRaw := jsonutil.Map(msg, "transaction")
+	if txnRaw == nil {
+		return nil
+	}
+
+	shape := &models.TransactionShape{TransactionType: stringify(txnRaw["TransactionType"])}
+	if encoded, err := json.Marshal(txnRaw); err == nil {
+		shape.SizeBytes = len(encoded)
+	}
+	if len(jsonutil.Slice(txnRaw, "Memos")) > 0 {
+		shape.HasMemos = true
+	}
+	for _, node := range jsonutil.GetSlice(msg, "meta.AffectedNodes") {
+		nodeMap := jsonutil.Object(node)
+		for _, kind := range affectedNodeKinds {
+			if entryType := jsonutil.GetString(nodeMap, kind+".LedgerEntryType"); entryType != "" {
+				shape.LedgerEntryTypes = append(shape.LedgerEntryTypes, entryType)
+			}
+		}
+	}
+	return shape
+}
@@ -0,0 +1,187 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+const (
+	// shardCacheTTL is how long a worker reuses an account location it
+	// resolved before asking the resolver again.
+	shardCacheTTL = time.Minute
+
+	// shardMissingTTL is how long a worker remembers that an account has no
+	// location.
+	shardMissingTTL = 30 * time.Second
+
+	// maxShardCacheEntries bounds each worker's caches; past it expired
+	// entries are dropped, then arbitrary ones.
+	maxShardCacheEntries = 4096
+
+	// shardInvalidationQueueSize bounds the domain changes waiting for a
+	// busy worker. Changes beyond it are dropped and the entry expires with
+	// shardCacheTTL.
+	shardInvalidationQueueSize = 64
+)
+
+// enrichShard is the queue and state of one sticky enrichment worker.
+// Transactions are routed to a shard by their source account, so the
+// worker sees every transaction of that account in order. Its caches are
+// only touched by its own worker and need no locks; lookups are serial
+// within a worker, so a repeated account waits for nothing but the cache.
+type enrichShard struct {
+	queue      chan *models.Transaction
+	late       chan *models.Transaction
+	invalidate chan string // accounts whose domain changed
+
+	cache   map[string]shardCacheEntry
+	missing map[string]time.Time // account -> until
+}
+
+type shardCacheEntry struct {
+	geo     *models.GeoLocation
+	expires time.Time
+}
+
+// newEnrichShards splits the enrichment queues of queueSize between count
+// shards.
+func newEnrichShards(count, queueSize int) []*enrichShard {
+	perShard := (queueSize + count - 1) / count
+	shards := make([]*enrichShard, count)
+	for i := range shards {
+		shards[i] = &enrichShard{
+			queue:      make(chan *models.Transaction, perShard),
+			late:       make(chan *models.Transaction, perShard),
+			invalidate: make(chan string, shardInvalidationQueueSize),
+			cache:      make(map[string]shardCacheEntry),
+			missing:    make(map[string]time.Time),
+		}
+	}
+	return shards
+}
+
+// shardFor returns the shard owning account.
+func (l *Listener) shardFor(account string) *enrichShard {
+	h := fnv.New32a()
+	h.Write([]byte(account))
+	return l.shards[h.Sum32()%uint32(len(l.shards))]
+}
+
+// processShard is the worker of s. Domain changes are applied first, then
+// live transactions, then late enrichment.
+func (l *Listener) processShard(s *enrichShard) {
+	for {
+		select {
+		case account := <-s.invalidate:
+			s.forget(account)
+			continue
+		case <-l.stopChan:
+			return
+		default:
+		}
+
+		select {
+		case tx := <-s.queue:
+			l.enrichTransactionIn(context.Background(), tx, s)
+			l.enqueueTransaction(tx)
+			continue
+		default:
+		}
+
+		select {
+		case account := <-s.invalidate:
+			s.forget(account)
+		case tx := <-s.queue:
+			l.enrichTransactionIn(context.Background(), tx, s)
+			l.enqueueTransaction(tx)
+		case tx := <-s.late:
+			l.enrichLate(tx, s)
+		case <-l.stopChan:
+			return
+		}
+	}
+}
+
+// invalidateShards tells every shard to drop account, which may be cached
+// by any worker that saw it as a counterparty.
+func (l *Listener) invalidateShards(account string) {
+	for _, s := range l.shards {
+		select {
+		case s.invalidate <- account:
+		default:
+		}
+	}
+}
+
+// resolve returns account's location from the shard's caches, or from
+// lookup, caching the result. Only the shard's worker may call it.
+func (s *enrichShard) resolve(now time.Time, account string, lookup func() (*models.GeoLocation, error)) (*models.GeoLocation, error) {
+	if entry, ok := s.cache[account]; ok && now.Before(entry.expires) {
+		metrics.GeolocationShardLookupsTotal.WithLabelValues("hit").Inc()
+		geo := *entry.geo
+		return &geo, nil
+	}
+	if until, ok := s.missing[account]; ok && now.Before(until) {
+		metrics.GeolocationShardLookupsTotal.WithLabelValues("missing").Inc()
+		return nil, nil
+	}
+
+	metrics.GeolocationShardLookupsTotal.WithLabelValues("lookup").Inc()
+	geo, err := lookup()
+	if geo == nil {
+		// Only answers that the account has no location are remembered;
+		// timeouts and upstream errors are retried by the next transaction.
+		if err == nil || errors.Is(err, xrpl.ErrNotFound) {
+			s.makeRoom(now)
+			s.missing[account] = now.Add(shardMissingTTL)
+		}
+		delete(s.cache, account)
+		return nil, err
+	}
+	s.makeRoom(now)
+	cached := *geo
+	s.cache[account] = shardCacheEntry{geo: &cached, expires: now.Add(shardCacheTTL)}
+	delete(s.missing, account)
+	return geo, err
+}
+
+// forget drops account from the shard's caches.
+func (s *enrichShard) forget(account string) {
+	delete(s.cache, account)
+	delete(s.missing, account)
+}
+
+// makeRoom keeps the caches below maxShardCacheEntries.
+func (s *enrichShard) makeRoom(now time.Time) {
+	if len(s.cache)+len(s.missing) < maxShardCacheEntries {
+		return
+	}
+	for account, entry := range s.cache {
+		if !now.Before(entry.expires) {
+			delete(s.cache, account)
+		}
+	}
+	for account, until := range s.missing {
+		if !now.Before(until) {
+			delete(s.missing, account)
+		}
+	}
+	for account := range s.cache {
+		if len(s.cache)+len(s.missing) < maxShardCacheEntries*3/4 {
+			return
+		}
+		delete(s.cache, account)
+	}
+	for account := range s.missing {
+		if len(s.cache)+len(s.missing) < maxShardCacheEntries*3/4 {
+			return
+		}
+		delete(s.missing, account)
+	}
+}
@@ -0,0 +1,142 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+func TestShardForIsStickyAndSpreadsAccounts(t *testing.T) {
+	l := NewListener(nil, 1, &countingGeoResolver{calls: map[string]int{}}, nil, ListenerOptions{
+		GeoWorkerCount:     4,
+		GeoEnrichmentQSize: 10,
+		ShardByAccount:     true,
+	})
+	if l.geoEnrichmentQ != nil || len(l.shards) != 4 || cap(l.shards[0].queue) != 3 {
+		t.Fatalf("expected 4 shards of 3 slots instead of a shared queue, got %d", len(l.shards))
+	}
+
+	accounts := []string{
+		"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		"rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY",
+		"rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe",
+		"rDsbeomae4FXwgQTJp9Rs64Qg9vDiTCdBv",
+		"rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh",
+		"rMQ98K56yXJbDGv49ZSmW51sLn94Xe1mu1",
+	}
+	used := make(map[*enrichShard]bool)
+	for _, account := range accounts {
+		shard := l.shardFor(account)
+		if l.shardFor(account) != shard {
+			t.Fatalf("expected %s to stay on one shard", account)
+		}
+		used[shard] = true
+	}
+	if len(used) < 2 {
+		t.Fatal("expected accounts to spread over several shards")
+	}
+
+	l.handleMessage(previewPaymentMessage("S1", true, "tesSUCCESS", 0))
+	source := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	if got := len(l.shardFor(source).queue); got != 1 {
+		t.Fatalf("expected the transaction on its source account's shard, got %d queued", got)
+	}
+	if status := l.UpstreamStatus(); status.EnrichmentQueueDepth != 1 || status.EnrichmentQueueCapacity != 12 {
+		t.Fatalf("expected the shard queues summed in the status, got %+v", status)
+	}
+}
+
+type flakyGeoResolver struct {
+	calls map[string]int
+	err   map[string]error
+}
+
+func (r *flakyGeoResolver) ResolveAccountGeo(ctx context.Context, client xrpl.NodeClient, account string) (*models.GeoLocation, error) {
+	r.calls[account]++
+	if err := r.err[account]; err != nil {
+		return nil, err
+	}
+	if account == "rMQ98K56yXJbDGv49ZSmW51sLn94Xe1mu1" {
+		return nil, nil
+	}
+	return &models.GeoLocation{Latitude: 1, Longitude: 2, City: account}, nil
+}
+
+func TestEnrichShardCachesLocationsAndMisses(t *testing.T) {
+	source := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	unlocated := "rMQ98K56yXJbDGv49ZSmW51sLn94Xe1mu1"
+	failing := "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"
+	resolver := &flakyGeoResolver{calls: map[string]int{}, err: map[string]error{failing: errors.New("timeout")}}
+	fake := clock.NewFake(time.Unix(1700000000, 0))
+	l := NewListener(nil, 1, resolver, nil, ListenerOptions{GeoWorkerCount: 2, ShardByAccount: true, Clock: fake})
+	shard := l.shardFor(source)
+
+	enrich := func(ledger uint32) *models.Transaction {
+		tx := &models.Transaction{LedgerIndex: ledger, Account: source, Destination: unlocated, GeoCandidates: []string{failing}}
+		l.enrichTransactionIn(context.Background(), tx, shard)
+		return tx
+	}
+	first := enrich(100)
+	if len(first.Locations) != 1 || first.Locations[0].Role != models.LocationRoleSource {
+		t.Fatalf("expected the source location, got %+v", first.Locations)
+	}
+	first.Locations[0].City = "mutated"
+
+	// Later ledgers reuse the shard's caches, unlike the per-ledger batches.
+	second := enrich(101)
+	if second.Locations[0].City != source {
+		t.Fatalf("expected the cached location to be unaffected by callers, got %+v", second.Locations)
+	}
+	if resolver.calls[source] != 1 || resolver.calls[unlocated] != 1 {
+		t.Fatalf("expected one lookup each for the located and unlocated accounts, got %+v", resolver.calls)
+	}
+	if resolver.calls[failing] != 2 {
+		t.Fatalf("expected failed lookups to be retried, got %d", resolver.calls[failing])
+	}
+
+	fake.Advance(shardMissingTTL)
+	enrich(102)
+	if resolver.calls[source] != 1 || resolver.calls[unlocated] != 2 {
+		t.Fatalf("expected only the negative entry to expire, got %+v", resolver.calls)
+	}
+
+	shard.forget(source)
+	enrich(103)
+	if resolver.calls[source] != 2 {
+		t.Fatalf("expected a forgotten account to be looked up again, got %d", resolver.calls[source])
+	}
+}
+
+func TestDomainChangeInvalidatesEveryShard(t *testing.T) {
+	account := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	l := NewListener(nil, 1, &countingGeoResolver{calls: map[string]int{}}, nil, ListenerOptions{GeoWorkerCount: 3, ShardByAccount: true})
+
+	l.observeDomainChange(accountSetMessage(account, "new.example", "old.example", "tesSUCCESS"))
+	for i, shard := range l.shards {
+		select {
+		case got := <-shard.invalidate:
+			if got != account {
+				t.Fatalf("shard %d: expected %s invalidated, got %s", i, account, got)
+			}
+		default:
+			t.Fatalf("shard %d: expected an invalidation", i)
+		}
+	}
+}
+
+func TestEnrichShardMakeRoomBoundsCaches(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	shard := newEnrichShards(1, 1)[0]
+	for i := 0; i < maxShardCacheEntries; i++ {
+		shard.cache[string(rune(0x10000+i))] = shardCacheEntry{geo: &models.GeoLocation{}, expires: now.Add(time.Minute)}
+	}
+	shard.makeRoom(now)
+	if n := len(shard.cache); n >= maxShardCacheEntries*3/4 {
+		t.Fatalf("expected the cache trimmed below %d, got %d", maxShardCacheEntries*3/4, n)
+	}
+}
@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// attributePublishers sets each validator's Publishers to the attribution
+// sites whose list includes it. A site that cannot be fetched keeps the
+// members of its last list; a site never fetched attributes nothing.
+func (f *Fetcher) attributePublishers(ctx context.Context, validators []*models.Validator) {
+	if len(f.attributionSites) == 0 {
+		return
+	}
+	members := make(map[string]map[string]struct{}, len(f.attributionSites))
+	for _, site := range f.attributionSites {
+		list, err := f.fetchValidatorListSite(ctx, site)
+		if err != nil {
+			f.logger.WithError(err).WithField("url", site).Warn("Validator list attribution unavailable, keeping last members")
+			f.sourceStateMu.Lock()
+			members[site] = f.siteMembers[site]
+			f.sourceStateMu.Unlock()
+			continue
+		}
+		keys := listMemberKeys(list)
+		f.sourceStateMu.Lock()
+		f.siteMembers[site] = keys
+		f.sourceStateMu.Unlock()
+		members[site] = keys
+	}
+
+	for _, v := range validators {
+		v.Publishers = nil
+		for _, site := range f.attributionSites {
+			keys := members[site]
+			if _, ok := keys[strings.ToUpper(v.PublicKey)]; ok && v.PublicKey != "" {
+				v.Publishers = append(v.Publishers, site)
+				continue
+			}
+			if _, ok := keys[strings.ToUpper(v.Address)]; ok && v.Address != "" {
+				v.Publishers = append(v.Publishers, site)
+			}
+		}
+	}
+}
+
+// listMemberKeys returns the upper-cased validation public keys of a decoded
+// validator list blob.
+func listMemberKeys(list map[string]interface{}) map[string]struct{} {
+	entries := jsonutil.Slice(list, "validators")
+	keys := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		if key := jsonutil.String(jsonutil.Object(entry), "validation_public_key"); key != "" {
+			keys[strings.ToUpper(key)] = struct{}{}
+		}
+	}
+	return keys
+}
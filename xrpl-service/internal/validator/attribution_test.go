@@ -0,0 +1,132 @@
+package validator
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/xrpltest"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+// validatorListSite serves a validator list whose blob lists keys, or a 404
+// once *down is set.
+func validatorListSite(t *testing.T, down *bool, keys ...string) *httptest.Server {
+	t.Helper()
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down != nil && *down {
+			http.NotFound(w, r)
+			return
+		}
+		writeValidatorList(w, keys)
+	}))
+	t.Cleanup(site.Close)
+	return site
+}
+
+// writeValidatorList writes a validator list response whose blob lists keys.
+func writeValidatorList(w http.ResponseWriter, keys []string) {
+	entries := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, map[string]interface{}{"validation_public_key": key})
+	}
+	blob, _ := json.Marshal(map[string]interface{}{"sequence": 1, "validators": entries})
+	json.NewEncoder(w).Encode(map[string]interface{}{"blob": base64.StdEncoding.EncodeToString(blob)})
+}
+
+func TestAttributePublishersKeepsLastMembersOfFailingSites(t *testing.T) {
+	down := false
+	ripple := validatorListSite(t, nil, "ED01", "ED02")
+	xrplf := validatorListSite(t, &down, "ed02", "ED03")
+
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, []string{ripple.URL}, "", cachePath, nil, 1, "mainnet", nil, FetcherOptions{
+		Clock:            fake,
+		AttributionSites: []string{ripple.URL, xrplf.URL},
+	})
+	validators := []*models.Validator{{PublicKey: "ED01"}, {PublicKey: "ED02"}, {PublicKey: "ED03"}, {PublicKey: "ED04"}}
+
+	fetcher.attributePublishers(context.Background(), validators)
+	want := [][]string{{ripple.URL}, {ripple.URL, xrplf.URL}, {xrplf.URL}, nil}
+	for i, v := range validators {
+		if len(v.Publishers) != len(want[i]) {
+			t.Fatalf("validator %s: expected publishers %v, got %v", v.PublicKey, want[i], v.Publishers)
+		}
+		for j := range want[i] {
+			if v.Publishers[j] != want[i][j] {
+				t.Fatalf("validator %s: expected publishers %v, got %v", v.PublicKey, want[i], v.Publishers)
+			}
+		}
+	}
+
+	// Once the cached list expires and the site fails, its last members
+	// are still attributed.
+	down = true
+	fake.Advance(24 * time.Hour)
+	validators = []*models.Validator{{PublicKey: "ED03"}}
+	fetcher.attributePublishers(context.Background(), validators)
+	if len(validators[0].Publishers) != 1 || validators[0].Publishers[0] != xrplf.URL {
+		t.Fatalf("expected the last xrplf members to be kept, got %v", validators[0].Publishers)
+	}
+}
+
+func TestPublisherChangeUpdatesSnapshot(t *testing.T) {
+	node := xrpltest.NewServer()
+	defer node.Close()
+	site := httptest.NewServer(http.NotFoundHandler())
+	defer site.Close()
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer registry.Close()
+	members := []string{trustedKeyA}
+	attribution := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeValidatorList(w, members)
+	}))
+	defer attribution.Close()
+
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fetcher := NewFetcher(xrpl.NewClient(node.URL(), "", nil), time.Minute, nil, []string{site.URL}, registry.URL, cachePath, nil, 1, "mainnet", nil, FetcherOptions{
+		Clock:            fake,
+		AttributionSites: []string{attribution.URL},
+	})
+	if err := fetcher.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	hash := fetcher.SnapshotHash()
+	if v := fetcher.GetValidator(trustedKeyB); v == nil || len(v.Publishers) != 0 {
+		t.Fatalf("expected %s to have no publisher, got %+v", trustedKeyB, v)
+	}
+
+	// Only the attribution changes: the list now includes both validators.
+	var updates []*models.ValidatorUpdate
+	fetcher.AddCallback(func(update *models.ValidatorUpdate) { updates = append(updates, update) })
+	members = []string{trustedKeyA, trustedKeyB}
+	fake.Advance(24 * time.Hour)
+	if err := fetcher.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if fetcher.SnapshotHash() == hash {
+		t.Fatal("expected the publisher change to change the snapshot hash")
+	}
+	if v := fetcher.GetValidator(trustedKeyB); v == nil || len(v.Publishers) != 1 || v.Publishers[0] != attribution.URL {
+		t.Fatalf("expected %s to be attributed to the list, got %+v", trustedKeyB, v)
+	}
+	if len(updates) != 1 || len(updates[0].Upserts) != 1 {
+		t.Fatalf("expected one upsert, got %+v", updates)
+	}
+	delta := updates[0].Upserts[0]
+	publishers, ok := delta.Fields["publishers"].([]string)
+	if delta.Address != trustedKeyB || len(delta.Fields) != 1 || !ok || len(publishers) != 1 || publishers[0] != attribution.URL {
+		t.Fatalf("expected only the publishers of %s to change, got %+v", trustedKeyB, delta)
+	}
+}
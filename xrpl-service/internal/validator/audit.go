@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// maxMetadataAuditRecords bounds the metadata audit trail kept with the
+// metadata cache; the oldest records are dropped first.
+const maxMetadataAuditRecords = 5000
+
+// Metadata audit fields.
+const (
+	AuditFieldEntry       = "entry" // a validator first seen
+	AuditFieldDomain      = "domain"
+	AuditFieldName        = "name"
+	AuditFieldLocation    = "location"
+	AuditFieldSigningKey  = "signing_key"
+	AuditFieldIcon        = "icon"
+	AuditFieldTwitter     = "twitter"
+	AuditFieldDescription = "description"
+	AuditFieldOwner       = "owner"
+	AuditFieldNote        = "note"
+)
+
+// Metadata audit sources besides the domain sources.
+const (
+	AuditSourceFetch        = "fetch"         // the validator list or trusted set of a fetch cycle
+	AuditSourceGeolocation  = "geolocation"   // resolved in the fetch cycle
+	AuditSourceCoverageLock = "coverage_lock" // kept from a prior cycle by the coverage lock
+	AuditSourceManifest     = "manifest"
+	AuditSourceProfile      = "profile" // the domain's xrp-ledger.toml
+	AuditSourceAdmin        = "admin"
+)
+
+// Metadata audit query limits.
+const (
+	defaultMetadataAuditLimit = 100
+	maxMetadataAuditLimit     = 1000
+)
+
+// appendMetadataAudit appends a record to records, dropping the oldest beyond
+// maxMetadataAuditRecords.
+func appendMetadataAudit(records []*models.MetadataAuditRecord, record *models.MetadataAuditRecord) []*models.MetadataAuditRecord {
+	records = append(records, record)
+	if len(records) > maxMetadataAuditRecords {
+		records = records[len(records)-maxMetadataAuditRecords:]
+	}
+	return records
+}
+
+// auditMetadata records a change to a validator's metadata. The caller holds
+// f.sourceStateMu.
+func (f *Fetcher) auditMetadata(address, field, oldValue, newValue, source string, at int64) {
+	f.metadataAudit = appendMetadataAudit(f.metadataAudit, &models.MetadataAuditRecord{
+		Timestamp: at,
+		Address:   address,
+		Field:     field,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		Source:    source,
+	})
+}
+
+// MetadataAudit returns the newest metadata audit records matching filter,
+// newest first. An address may be a master key or any signing key the
+// validator has used.
+func (f *Fetcher) MetadataAudit(ctx context.Context, filter models.MetadataAuditFilter) []*models.MetadataAuditRecord {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultMetadataAuditLimit
+	}
+	limit = min(limit, maxMetadataAuditLimit)
+
+	f.sourceStateMu.Lock()
+	defer f.sourceStateMu.Unlock()
+	address := filter.Address
+	if address != "" {
+		if entry := f.metadataEntryForKey(address); entry != nil {
+			address = entry.Address
+		}
+	}
+	records := make([]*models.MetadataAuditRecord, 0)
+	for i := len(f.metadataAudit) - 1; i >= 0 && len(records) < limit; i-- {
+		record := f.metadataAudit[i]
+		if (address != "" && record.Address != address) ||
+			(filter.Field != "" && record.Field != filter.Field) ||
+			(filter.Source != "" && record.Source != filter.Source) ||
+			record.Timestamp < filter.Since {
+			continue
+		}
+		copy := *record
+		records = append(records, &copy)
+	}
+	return records
+}
+
+// formatAuditLocation renders a location for the audit trail.
+func formatAuditLocation(latitude, longitude float64, countryCode, city string, approximate bool) string {
+	if latitude == 0 && longitude == 0 {
+		return ""
+	}
+	location := fmt.Sprintf("%.4f,%.4f %s/%s", latitude, longitude, countryCode, city)
+	if approximate {
+		location += " (approximate)"
+	}
+	return location
+}
@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestMetadataAuditAttributesCoverageLock(t *testing.T) {
+	fetcher := newCoordinateTestFetcher(t, nil)
+	fetcher.updatePersistedMetadata([]*models.Validator{
+		{Address: "nA1", Domain: "a.example", SigningKey: "n9Key", ManifestSequence: 1, Latitude: 50.11, Longitude: 8.68, CountryCode: "DE", City: "Frankfurt"},
+	}, map[string]string{"nA1": DomainSourceValidatorList}, nil)
+
+	// Geolocation only places the validator at the country centroid; the
+	// coverage lock keeps the known city.
+	centroid := &models.Validator{Address: "nA1", Domain: "a.example", SigningKey: "n9Key", ManifestSequence: 1, Latitude: 51.17, Longitude: 10.45, CountryCode: "DE", City: "Unknown", Approximate: true}
+	sources := fetcher.preserveMappedCoverage([]*models.Validator{centroid}, nil)
+	if sources["nA1"] != AuditSourceCoverageLock {
+		t.Fatalf("expected the coverage lock to be reported, got %v", sources)
+	}
+	fetcher.updatePersistedMetadata([]*models.Validator{centroid}, nil, sources)
+
+	moved := &models.Validator{Address: "nA1", Domain: "a.example", SigningKey: "n9Key", ManifestSequence: 1, Latitude: 48.86, Longitude: 2.35, CountryCode: "FR", City: "Paris"}
+	fetcher.updatePersistedMetadata([]*models.Validator{moved}, nil, nil)
+
+	records := fetcher.MetadataAudit(context.Background(), models.MetadataAuditFilter{Address: "n9Key", Field: AuditFieldLocation})
+	if len(records) != 2 {
+		t.Fatalf("expected the initial location and the move, got %+v", records)
+	}
+	if records[0].Source != AuditSourceGeolocation || records[0].OldValue != "50.1100,8.6800 DE/Frankfurt" || records[0].NewValue != "48.8600,2.3500 FR/Paris" {
+		t.Fatalf("unexpected newest record %+v", records[0])
+	}
+	if records[1].OldValue != "" || records[1].NewValue != "50.1100,8.6800 DE/Frankfurt" {
+		t.Fatalf("unexpected initial record %+v", records[1])
+	}
+
+	all := fetcher.MetadataAudit(context.Background(), models.MetadataAuditFilter{Address: "nA1"})
+	fields := make(map[string]string)
+	for _, record := range all {
+		fields[record.Field] = record.Source
+	}
+	if fields[AuditFieldEntry] != AuditSourceFetch || fields[AuditFieldDomain] != DomainSourceValidatorList || fields[AuditFieldSigningKey] != AuditSourceManifest {
+		t.Fatalf("unexpected audit sources %v", fields)
+	}
+}
+
+func TestMetadataAuditIsBoundedAndSurvivesRestart(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	fetcher.updatePersistedMetadata([]*models.Validator{{Address: "nA1", Name: "name-0"}}, nil, nil)
+	fetcher.sourceStateMu.Lock()
+	for i := 1; i < maxMetadataAuditRecords; i++ {
+		fetcher.auditMetadata("nA1", AuditFieldName, fmt.Sprintf("name-%d", i-1), fmt.Sprintf("name-%d", i), AuditSourceFetch, int64(i))
+	}
+	fetcher.sourceStateMu.Unlock()
+	if err := fetcher.persistMetadataCache(); err != nil {
+		t.Fatalf("persistMetadataCache failed: %v", err)
+	}
+
+	reloaded := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	if len(reloaded.metadataAudit) != maxMetadataAuditRecords {
+		t.Fatalf("expected %d records, got %d", maxMetadataAuditRecords, len(reloaded.metadataAudit))
+	}
+	records := reloaded.MetadataAudit(context.Background(), models.MetadataAuditFilter{Field: AuditFieldName, Limit: 1})
+	want := fmt.Sprintf("name-%d", maxMetadataAuditRecords-1)
+	if len(records) != 1 || records[0].NewValue != want {
+		t.Fatalf("expected the newest name change %q, got %+v", want, records)
+	}
+	if got := reloaded.MetadataAudit(context.Background(), models.MetadataAuditFilter{Field: AuditFieldEntry}); len(got) != 0 {
+		t.Fatalf("expected the oldest record to be dropped, got %+v", got)
+	}
+	if got := reloaded.MetadataAudit(context.Background(), models.MetadataAuditFilter{Limit: 5000}); len(got) != maxMetadataAuditLimit {
+		t.Fatalf("expected the limit to be capped at %d, got %d", maxMetadataAuditLimit, len(got))
+	}
+}
@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// applyBadges sets the trust badges of validators from the claims recorded
+// for their current domain by applyDomainProfiles. An owner is verified
+// only when the validator list attested the domain, so that the domain and
+// the validator each vouch for the other; votes are consistent when a list
+// the stanza claims was observed to include the validator.
+func (f *Fetcher) applyBadges(validators []*models.Validator, domainSources map[string]string) {
+	f.sourceStateMu.Lock()
+	defer f.sourceStateMu.Unlock()
+
+	for _, v := range validators {
+		if v == nil {
+			continue
+		}
+		v.Owner, v.VerifiedOwner, v.ConsistentVotes = "", false, false
+		entry := f.metadataCache[v.Address]
+		if entry == nil || entry.ProfileDomain != v.Domain {
+			continue
+		}
+		claims := entry.claims()
+		if !claims.Listed {
+			continue
+		}
+		v.Owner = claims.Owner
+		v.VerifiedOwner = claims.Owner != "" && domainSources[v.Address] == DomainSourceValidatorList
+		v.ConsistentVotes = claims.UNL != "" && includesSite(v.Publishers, claims.UNL)
+	}
+}
+
+// includesSite reports whether sites has a list site on the host of claim.
+func includesSite(sites []string, claim string) bool {
+	host := siteHost(claim)
+	if host == "" {
+		return false
+	}
+	for _, site := range sites {
+		if siteHost(site) == host {
+			return true
+		}
+	}
+	return false
+}
+
+// siteHost returns the lower-cased host of a list site URL.
+func siteHost(site string) string {
+	parsed, err := url.Parse(strings.TrimSpace(site))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
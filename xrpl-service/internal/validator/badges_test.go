@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestApplyBadgesChecksTOMLClaims(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testValidatorTOML))
+	}))
+	defer upstream.Close()
+
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", filepath.Join(t.TempDir(), "validator-metadata-cache.json"), nil, 1, "mainnet", nil)
+	fetcher.profileURLTemplate = upstream.URL + "/%s/.well-known/xrp-ledger.toml"
+
+	validators := []*models.Validator{
+		{Address: "nA1", Domain: "a.example", Publishers: []string{"https://VL.ripple.com/"}},
+		{Address: "nA2", Domain: "a.example", Publishers: []string{"https://vl.ripple.com"}},
+		{Address: "nA3", Domain: "a.example"},
+	}
+	fetcher.applyDomainProfiles(context.Background(), validators)
+	fetcher.applyBadges(validators, map[string]string{
+		"nA1": DomainSourceValidatorList,
+		"nA2": DomainSourceSecondaryRegistry,
+		"nA3": DomainSourceValidatorList,
+	})
+
+	if first := validators[0]; first.Owner != "A Validators" || !first.VerifiedOwner || !first.ConsistentVotes {
+		t.Fatalf("expected nA1 verified with consistent votes, got %+v", first)
+	}
+	if second := validators[1]; second.Owner != "A Validators" || second.VerifiedOwner || second.ConsistentVotes {
+		t.Fatalf("expected a registry domain not to verify nA2 and no UNL claim, got %+v", second)
+	}
+	if third := validators[2]; third.Owner != "" || third.VerifiedOwner {
+		t.Fatalf("expected a validator the TOML does not list to get no badges, got %+v", third)
+	}
+
+	// A claimed UNL that was not observed to include the validator.
+	validators[0].Publishers = []string{"https://unl.xrplf.org"}
+	fetcher.applyBadges(validators, map[string]string{"nA1": DomainSourceValidatorList})
+	if validators[0].ConsistentVotes {
+		t.Fatal("expected votes to be inconsistent with the observed lists")
+	}
+
+	// Badges belong to the domain that made the claims.
+	validators[0].Domain = "b.example"
+	fetcher.applyBadges(validators, map[string]string{"nA1": DomainSourceValidatorList})
+	if validators[0].Owner != "" || validators[0].VerifiedOwner {
+		t.Fatalf("expected badges cleared after a domain change, got %+v", validators[0])
+	}
+}
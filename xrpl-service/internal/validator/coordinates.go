@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/geolocation"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxUnconfirmedMoveKm is the largest move from a validator's known
+	// location accepted from the geolocation provider alone.
+	maxUnconfirmedMoveKm = 5000
+
+	// confirmAgreementKm is how close the confirming provider must place a
+	// validator for a larger move to be accepted. Providers disagree on
+	// cities, so this only checks that they agree on the region.
+	confirmAgreementKm = 1000
+)
+
+// Reasons coordinates are rejected, used as metric labels.
+const (
+	rejectOutOfRange      = "out_of_range"
+	rejectOcean           = "ocean"
+	rejectUnconfirmedMove = "unconfirmed_move"
+)
+
+// checkCoordinates rejects resolved coordinates that are out of range, in
+// open ocean, or more than maxUnconfirmedMoveKm from the validator's known
+// location without the GeoConfirmer agreeing. Rejected validators are reset
+// to unmapped, so the coverage lock keeps their known location instead.
+func (f *Fetcher) checkCoordinates(validators []*models.Validator) {
+	previous := make(map[string][2]float64)
+	f.mu.RLock()
+	for address, v := range f.validators {
+		if v != nil && (v.Latitude != 0 || v.Longitude != 0) {
+			previous[address] = [2]float64{v.Latitude, v.Longitude}
+		}
+	}
+	f.mu.RUnlock()
+	f.sourceStateMu.Lock()
+	for address, entry := range f.metadataCache {
+		if _, ok := previous[address]; ok || entry == nil {
+			continue
+		}
+		if entry.Latitude != 0 || entry.Longitude != 0 {
+			previous[address] = [2]float64{entry.Latitude, entry.Longitude}
+		}
+	}
+	f.sourceStateMu.Unlock()
+
+	for _, v := range validators {
+		if v == nil || (v.Latitude == 0 && v.Longitude == 0) {
+			continue
+		}
+		reason := ""
+		known, hasKnown := previous[v.Address]
+		switch {
+		case !geolocation.ValidCoordinates(v.Latitude, v.Longitude):
+			reason = rejectOutOfRange
+		case !geolocation.OnLand(v.Latitude, v.Longitude):
+			reason = rejectOcean
+		case hasKnown && geolocation.DistanceKm(known[0], known[1], v.Latitude, v.Longitude) > maxUnconfirmedMoveKm:
+			if !f.confirmMove(v) {
+				reason = rejectUnconfirmedMove
+			}
+		}
+		if reason == "" {
+			continue
+		}
+
+		metrics.GeolocationCoordinatesRejectedTotal.WithLabelValues(reason).Inc()
+		f.logger.WithFields(logrus.Fields{
+			"address":   v.Address,
+			"domain":    v.Domain,
+			"latitude":  v.Latitude,
+			"longitude": v.Longitude,
+			"reason":    reason,
+		}).Warn("Rejected resolved validator coordinates")
+		v.Latitude = 0
+		v.Longitude = 0
+		v.CountryCode = "XX"
+		v.City = "Unknown"
+		v.Approximate = false
+	}
+}
+
+// confirmMove reports whether the GeoConfirmer places v within
+// confirmAgreementKm of its newly resolved location.
+func (f *Fetcher) confirmMove(v *models.Validator) bool {
+	if f.geoConfirmer == nil {
+		return false
+	}
+	confirmed := *v
+	confirmed.Latitude = 0
+	confirmed.Longitude = 0
+	if err := f.geoConfirmer.EnrichValidator(&confirmed); err != nil {
+		f.logger.WithError(err).WithField("address", v.Address).Debug("Confirming geolocation provider failed")
+		return false
+	}
+	if confirmed.Latitude == 0 && confirmed.Longitude == 0 {
+		return false
+	}
+	return geolocation.DistanceKm(confirmed.Latitude, confirmed.Longitude, v.Latitude, v.Longitude) <= confirmAgreementKm
+}
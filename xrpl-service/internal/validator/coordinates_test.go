@@ -0,0 +1,133 @@
+package validator
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// fixedGeo places every validator at one location.
+type fixedGeo struct {
+	lat, lon float64
+	country  string
+}
+
+func (g fixedGeo) EnrichValidator(v *models.Validator) error {
+	v.Latitude = g.lat
+	v.Longitude = g.lon
+	v.CountryCode = g.country
+	v.City = "Somewhere"
+	return nil
+}
+
+func newCoordinateTestFetcher(t *testing.T, confirmer GeoLocationProvider) *Fetcher {
+	t.Helper()
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil, FetcherOptions{GeoConfirmer: confirmer})
+	// Known location: Frankfurt.
+	fetcher.validators["nA1"] = &models.Validator{Address: "nA1", Latitude: 50.11, Longitude: 8.68, CountryCode: "DE", City: "Frankfurt"}
+	return fetcher
+}
+
+func TestCheckCoordinatesRejectsOutOfRangeAndOcean(t *testing.T) {
+	fetcher := newCoordinateTestFetcher(t, nil)
+	outOfRange := &models.Validator{Address: "nA1", Latitude: 95, Longitude: 8.68, CountryCode: "DE"}
+	ocean := &models.Validator{Address: "nB2", Latitude: -45, Longitude: -120, CountryCode: "US"}
+	fetcher.checkCoordinates([]*models.Validator{outOfRange, ocean})
+
+	for _, v := range []*models.Validator{outOfRange, ocean} {
+		if v.Latitude != 0 || v.Longitude != 0 || v.CountryCode != "XX" {
+			t.Fatalf("expected %s to be reset to unmapped, got %+v", v.Address, v)
+		}
+	}
+
+	// The coverage lock then restores the known location.
+	fetcher.preserveMappedCoverage([]*models.Validator{outOfRange}, nil)
+	if outOfRange.City != "Frankfurt" {
+		t.Fatalf("expected the known location to be kept, got %+v", outOfRange)
+	}
+}
+
+func TestCheckCoordinatesRequiresConfirmationForLargeMoves(t *testing.T) {
+	// Frankfurt to Sydney is over 16000 km; to Paris under 500 km.
+	moved := func() *models.Validator {
+		return &models.Validator{Address: "nA1", Latitude: -33.87, Longitude: 151.21, CountryCode: "AU"}
+	}
+
+	unconfirmed := moved()
+	newCoordinateTestFetcher(t, nil).checkCoordinates([]*models.Validator{unconfirmed})
+	if unconfirmed.Latitude != 0 {
+		t.Fatalf("expected a move without a confirmer to be rejected, got %+v", unconfirmed)
+	}
+
+	disagreeing := moved()
+	newCoordinateTestFetcher(t, fixedGeo{lat: 40.71, lon: -74.0, country: "US"}).checkCoordinates([]*models.Validator{disagreeing})
+	if disagreeing.Latitude != 0 {
+		t.Fatalf("expected a move the confirmer disagrees with to be rejected, got %+v", disagreeing)
+	}
+
+	confirmed := moved()
+	newCoordinateTestFetcher(t, fixedGeo{lat: -37.81, lon: 144.96, country: "AU"}).checkCoordinates([]*models.Validator{confirmed})
+	if confirmed.Latitude != -33.87 || confirmed.CountryCode != "AU" {
+		t.Fatalf("expected a confirmed move to be kept, got %+v", confirmed)
+	}
+
+	nearby := &models.Validator{Address: "nA1", Latitude: 48.86, Longitude: 2.35, CountryCode: "FR"}
+	newCoordinateTestFetcher(t, nil).checkCoordinates([]*models.Validator{nearby})
+	if nearby.Latitude != 48.86 {
+		t.Fatalf("expected a small move to need no confirmation, got %+v", nearby)
+	}
+}
+
+func TestPreserveMappedCoverageKeepsCityOverCountryCentroid(t *testing.T) {
+	fetcher := newCoordinateTestFetcher(t, nil)
+	centroid := &models.Validator{Address: "nA1", Latitude: 51.17, Longitude: 10.45, CountryCode: "DE", City: "Unknown", Approximate: true}
+	fetcher.preserveMappedCoverage([]*models.Validator{centroid}, nil)
+	if centroid.City != "Frankfurt" || centroid.Approximate {
+		t.Fatalf("expected the known city to replace the country centroid, got %+v", centroid)
+	}
+
+	moved := &models.Validator{Address: "nA1", Latitude: 46.23, Longitude: 2.21, CountryCode: "FR", City: "Unknown", Approximate: true}
+	fetcher.preserveMappedCoverage([]*models.Validator{moved}, nil)
+	if moved.CountryCode != "FR" || !moved.Approximate {
+		t.Fatalf("expected a centroid in another country to be kept, got %+v", moved)
+	}
+}
+
+func TestPreserveMappedCoverageExpiresUnconfirmedLocks(t *testing.T) {
+	fetcher := newCoordinateTestFetcher(t, nil)
+	fetcher.locationLockTTL = time.Hour
+	fetcher.metadataCache["nA1"] = &validatorMetadataEntry{
+		Address: "nA1", Latitude: 50.11, Longitude: 8.68, CountryCode: "DE", City: "Frankfurt",
+		LocationConfirmedAt: time.Now().Add(-2 * time.Hour).Unix(),
+	}
+
+	unmapped := &models.Validator{Address: "nA1", CountryCode: "XX", City: "Unknown"}
+	restored := &models.Validator{Address: "nA1", Latitude: 50.11, Longitude: 8.68, CountryCode: "DE", City: "Frankfurt"}
+	sources := fetcher.preserveMappedCoverage([]*models.Validator{unmapped}, nil)
+	if unmapped.City != "Frankfurt" || !unmapped.StaleLocation || sources["nA1"] != AuditSourceCoverageLock {
+		t.Fatalf("expected the expired location to be kept and flagged stale, got %+v", unmapped)
+	}
+	fetcher.preserveMappedCoverage([]*models.Validator{restored}, map[string]bool{"nA1": true})
+	if !restored.StaleLocation {
+		t.Fatalf("expected a location restored from metadata to be flagged stale, got %+v", restored)
+	}
+
+	centroid := &models.Validator{Address: "nA1", Latitude: 51.17, Longitude: 10.45, CountryCode: "DE", City: "Unknown", Approximate: true}
+	if sources := fetcher.preserveMappedCoverage([]*models.Validator{centroid}, nil); len(sources) != 0 {
+		t.Fatalf("expected an expired lock not to override a fresh centroid, got %v", sources)
+	}
+	if centroid.City != "Unknown" || !centroid.Approximate || centroid.StaleLocation {
+		t.Fatalf("expected the fresh centroid to be kept, got %+v", centroid)
+	}
+
+	// A confirmed resolution restarts the TTL.
+	fetcher.updatePersistedMetadata([]*models.Validator{{Address: "nA1", Latitude: 50.11, Longitude: 8.68, CountryCode: "DE", City: "Frankfurt"}}, nil, nil)
+	unmapped = &models.Validator{Address: "nA1", CountryCode: "XX", City: "Unknown"}
+	fetcher.preserveMappedCoverage([]*models.Validator{unmapped}, nil)
+	if unmapped.City != "Frankfurt" || unmapped.StaleLocation {
+		t.Fatalf("expected a re-confirmed location not to be stale, got %+v", unmapped)
+	}
+}
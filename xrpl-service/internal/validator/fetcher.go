@@ -1,23 +1,33 @@
 package validator
 
 import (
-	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/brandon/xrpl-validator-service/internal/models"
-	"github.com/brandon/xrpl-validator-service/internal/xrpl"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/cachefile"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/debugcapture"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/health"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/intern"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/budget"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
 	"github.com/sirupsen/logrus"
 )
 
@@ -38,6 +48,10 @@ type secondaryRegistryEntry struct {
 	Chain        string `json:"chain"`
 	Domain       string `json:"domain"`
 	DomainLegacy string `json:"domain_legacy"`
+	Icon         string `json:"icon"`
+	Twitter      string `json:"twitter"`
+	Description  string `json:"description"`
+	Owner        string `json:"owner"`
 }
 
 type secondaryRegistryCacheEntry struct {
@@ -53,16 +67,79 @@ type validatorMetadataEntry struct {
 	Longitude   float64 `json:"longitude"`
 	CountryCode string  `json:"country_code"`
 	City        string  `json:"city"`
+	Approximate bool    `json:"approximate,omitempty"`
 	LastSeenAt  int64   `json:"last_seen_at"`
+
+	// LocationConfirmedAt is when geolocation last resolved the location
+	// rather than the coverage lock keeping it (unix seconds).
+	LocationConfirmedAt int64 `json:"location_confirmed_at,omitempty"`
+
+	// Profile from the xrp-ledger.toml of ProfileDomain, checked at
+	// ProfileCheckedAt (unix seconds).
+	Icon             string `json:"icon,omitempty"`
+	Twitter          string `json:"twitter,omitempty"`
+	Description      string `json:"description,omitempty"`
+	ProfileDomain    string `json:"profile_domain,omitempty"`
+	ProfileCheckedAt int64  `json:"profile_checked_at,omitempty"`
+
+	// Claims of the same xrp-ledger.toml: whether it lists the validator,
+	// the owner it names and the UNL the validator's stanza claims.
+	ProfileListed bool   `json:"profile_listed,omitempty"`
+	Owner         string `json:"owner,omitempty"`
+	UNLClaim      string `json:"unl_claim,omitempty"`
+
+	// Peer port reachability of PeerDomain, probed at PeerCheckedAt (unix
+	// seconds). PeerReachable is nil when the domain had no public IP.
+	PeerDomain    string `json:"peer_domain,omitempty"`
+	PeerReachable *bool  `json:"peer_reachable,omitempty"`
+	PeerPort      int    `json:"peer_port,omitempty"`
+	PeerCheckedAt int64  `json:"peer_checked_at,omitempty"`
+
+	DomainHistory []*models.DomainChange `json:"domain_history,omitempty"`
+
+	// Signing key from the highest manifest sequence seen, and the
+	// rotations between them.
+	SigningKey       string                `json:"signing_key,omitempty"`
+	ManifestSequence uint32                `json:"manifest_sequence,omitempty"`
+	KeyRotations     []*models.KeyRotation `json:"key_rotations,omitempty"`
+
+	Notes []*models.ValidatorNote `json:"notes,omitempty"`
 }
 
 type validatorMetadataCacheFile struct {
 	Version int                                `json:"version"`
 	Entries map[string]*validatorMetadataEntry `json:"entries"`
+	Audit   []*models.MetadataAuditRecord      `json:"audit,omitempty"`
+
+	// Publishers is keyed by validator list site URL.
+	Publishers map[string]*models.ValidatorListPublisher `json:"publishers,omitempty"`
 }
 
 const validatorMetadataCacheVersion = 1
 
+// validatorMetadataCacheFormat upgrades older metadata caches on load. When
+// bumping validatorMetadataCacheVersion, register a migration from the
+// previous version here.
+var validatorMetadataCacheFormat = cachefile.Format{
+	Name:       "validator metadata cache",
+	Version:    validatorMetadataCacheVersion,
+	Migrations: map[int]cachefile.MigrateFunc{},
+}
+
+// Domain sources recorded in the domain audit trail.
+const (
+	DomainSourceValidatorList     = "validator_list"
+	DomainSourceSecondaryRegistry = "secondary_registry"
+	DomainSourceImport            = "import" // seeded by ImportMetadata
+)
+
+// maxDomainHistory bounds the recorded domain changes per validator; the
+// oldest are dropped first.
+const maxDomainHistory = 50
+
+// UpdateCallback receives the validator changes produced by a fetch cycle.
+type UpdateCallback func(*models.ValidatorUpdate)
+
 // Fetcher handles validator data retrieval and caching
 type Fetcher struct {
 	client               xrpl.NodeClient
@@ -70,15 +147,22 @@ type Fetcher struct {
 	httpClient           *http.Client
 	mu                   sync.RWMutex
 	validators           map[string]*models.Validator // Address -> Validator
+	snapshotHash         string
+	sources              *models.SourceStatus // of the cycle that last updated validators
 	lastUpdate           time.Time
+	provisionalSince     time.Time // set while serving the set restored at startup
 	refreshInterval      time.Duration
 	stopChan             chan struct{}
 	geolocationProvider  GeoLocationProvider
+	geoConfirmer         GeoLocationProvider
+	locationLockTTL      time.Duration
+	asnProvider          ASNProvider
 	maxValidators        int
 	validatorListSites   []string
 	secondaryRegistryURL string
 	metadataCachePath    string
 	networkHealthRPCURLs []string
+	networkHealthClients map[string]xrpl.NodeClient
 	networkHealthRetries int
 	network              string
 	sourceStateMu        sync.Mutex
@@ -86,6 +170,84 @@ type Fetcher struct {
 	secondaryCache       *secondaryRegistryCacheEntry
 	sourceCooldownUntil  map[string]time.Time
 	metadataCache        map[string]*validatorMetadataEntry
+	metadataAudit        []*models.MetadataAuditRecord             // oldest first, guarded by sourceStateMu
+	metadataPersistMu    sync.Mutex                                // serializes metadata cache writes
+	publishers           map[string]*models.ValidatorListPublisher // by list site, guarded by sourceStateMu
+	publisherKeyChains   [][]string
+	listExpiryWarning    time.Duration
+	attributionSites     []string
+	siteMembers          map[string]map[string]struct{} // last list members by attribution site, guarded by sourceStateMu
+	callbacks            []UpdateCallback
+	rotationCallbacks    []RotationCallback
+	paused               bool
+	profileURLTemplate   string // xrp-ledger.toml URL with %s for the domain; tests override it
+	peerProbeInterval    time.Duration
+	lookupHost           func(ctx context.Context, host string) ([]string, error)
+	peerDial             func(ctx context.Context, network, address string) (net.Conn, error)
+	progress             *fetchProgress
+	clock                clock.Clock
+	schedule             clock.Schedule
+	debugCapture         *debugcapture.Capturer
+}
+
+// FetcherOptions controls optional fetcher behavior.
+type FetcherOptions struct {
+	// Clock drives refresh ticks, source cooldowns and cache TTLs. Nil
+	// uses the system clock.
+	Clock clock.Clock
+
+	// RefreshJitter varies each refresh interval by up to this fraction,
+	// e.g. 0.1 for ±10%, so that instances drift apart.
+	RefreshJitter float64
+
+	// RefreshSplay delays the first periodic refresh, giving each
+	// instance its own phase; see clock.SplayFor.
+	RefreshSplay time.Duration
+
+	// ASNProvider looks up the AS hosting validator domains for operator
+	// grouping. Without it validators are grouped by domain and owner only.
+	ASNProvider ASNProvider
+
+	// GeoConfirmer is a second geolocation provider that must agree before
+	// a validator is moved more than maxUnconfirmedMoveKm. Without it such
+	// moves are rejected.
+	GeoConfirmer GeoLocationProvider
+
+	// LocationLockTTL is how long the coverage lock keeps a location that
+	// geolocation no longer confirms before flagging it stale_location,
+	// after which a fresh country centroid also replaces it. Zero keeps
+	// locked locations indefinitely.
+	LocationLockTTL time.Duration
+
+	// PeerProbeInterval, when positive, probes the peer ports of validator
+	// domains that resolve to a public IP, re-probing each after this long.
+	// Zero disables probing.
+	PeerProbeInterval time.Duration
+
+	// PublisherKeyChains lists the accepted validator list publisher master
+	// keys as rotation chains, oldest key first. A site may move from a key
+	// to a later one in its chain; any other key raises a publisher alert.
+	// Without chains the first key each site presents is trusted.
+	PublisherKeyChains [][]string
+
+	// ListExpiryWarning raises an expiry alert on a validator list site
+	// whose last list expires within this long. Expired lists raise one
+	// regardless; zero only alerts on those.
+	ListExpiryWarning time.Duration
+
+	// AttributionSites are validator list sites whose members are recorded
+	// in each validator's Publishers, fetched in addition to the list the
+	// validators come from. Empty disables attribution.
+	AttributionSites []string
+
+	// Budget paces the fetcher's HTTP requests and network health checks
+	// against per-host limits shared with other subsystems. Nil leaves
+	// them unpaced.
+	Budget *budget.Manager
+
+	// DebugCapture, when set, keeps redacted samples of validator lists
+	// and entries that fail to parse.
+	DebugCapture *debugcapture.Capturer
 }
 
 // GeoLocationProvider defines the interface for geolocation enrichment
@@ -106,10 +268,16 @@ func NewFetcher(
 	networkHealthRetries int,
 	network string,
 	logger *logrus.Logger,
+	options ...FetcherOptions,
 ) *Fetcher {
 	if logger == nil {
 		logger = logrus.New()
 	}
+	var opts FetcherOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	clk := clock.OrReal(opts.Clock)
 	sites := make([]string, 0, len(validatorListSites))
 	for _, site := range validatorListSites {
 		trimmed := strings.TrimSpace(site)
@@ -148,29 +316,62 @@ func NewFetcher(
 	if networkHealthRetries <= 0 {
 		networkHealthRetries = 2
 	}
+	healthClients := make(map[string]xrpl.NodeClient, len(endpoints))
+	for _, endpoint := range endpoints {
+		healthClients[endpoint] = xrpl.NewClient(endpoint, "", logger, xrpl.ClientOptions{Budget: opts.Budget, Subsystem: budget.SubsystemHealth})
+	}
 	fetcher := &Fetcher{
 		client:               client,
 		logger:               logger,
-		httpClient:           &http.Client{Timeout: 30 * time.Second},
+		httpClient:           &http.Client{Timeout: 30 * time.Second, Transport: budget.Transport(opts.Budget, budget.SubsystemFetcher, nil)},
 		validators:           make(map[string]*models.Validator),
 		refreshInterval:      refreshInterval,
 		stopChan:             make(chan struct{}),
 		geolocationProvider:  geoProvider,
+		geoConfirmer:         opts.GeoConfirmer,
+		locationLockTTL:      opts.LocationLockTTL,
+		asnProvider:          opts.ASNProvider,
 		maxValidators:        1000, // Limit to prevent memory exhaustion
 		validatorListSites:   sites,
 		secondaryRegistryURL: secondaryRegistryURL,
 		metadataCachePath:    metadataCachePath,
 		networkHealthRPCURLs: endpoints,
+		networkHealthClients: healthClients,
 		networkHealthRetries: networkHealthRetries,
 		network:              strings.ToLower(network),
 		validatorListCache:   make(map[string]*validatorListCacheEntry),
 		sourceCooldownUntil:  make(map[string]time.Time),
 		metadataCache:        make(map[string]*validatorMetadataEntry),
+		publishers:           make(map[string]*models.ValidatorListPublisher),
+		publisherKeyChains:   opts.PublisherKeyChains,
+		listExpiryWarning:    opts.ListExpiryWarning,
+		attributionSites:     opts.AttributionSites,
+		siteMembers:          make(map[string]map[string]struct{}),
+		peerProbeInterval:    opts.PeerProbeInterval,
+		lookupHost:           net.DefaultResolver.LookupHost,
+		peerDial:             (&net.Dialer{}).DialContext,
+		progress:             newFetchProgress(clk),
+		clock:                clk,
+		schedule: clock.Schedule{
+			Interval: refreshInterval,
+			Jitter:   opts.RefreshJitter,
+			Splay:    opts.RefreshSplay,
+		},
+		debugCapture: opts.DebugCapture,
 	}
 	fetcher.loadMetadataCache()
+	fetcher.loadProvisionalSnapshot()
 	return fetcher
 }
 
+// AddCallback registers a callback for validator changes. Callbacks run after
+// every fetch cycle that follows the initial load and changes the set.
+func (f *Fetcher) AddCallback(callback UpdateCallback) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.callbacks = append(f.callbacks, callback)
+}
+
 // Start begins the periodic validator fetching
 func (f *Fetcher) Start(ctx context.Context) {
 	go func() {
@@ -179,8 +380,8 @@ func (f *Fetcher) Start(ctx context.Context) {
 			f.logger.WithError(err).Error("Initial validator fetch failed")
 		}
 
-		// Set up periodic fetching
-		ticker := time.NewTicker(f.refreshInterval)
+		// Set up periodic fetching; each tick picks the next jittered wait.
+		ticker := f.clock.NewTicker(f.schedule.First())
 		defer ticker.Stop()
 
 		for {
@@ -188,7 +389,14 @@ func (f *Fetcher) Start(ctx context.Context) {
 			case <-f.stopChan:
 				f.logger.Info("Validator fetcher stopped")
 				return
-			case <-ticker.C:
+			case <-ticker.C():
+				ticker.Reset(f.schedule.Next())
+				f.mu.RLock()
+				paused := f.paused
+				f.mu.RUnlock()
+				if paused {
+					continue
+				}
 				if err := f.Fetch(ctx); err != nil {
 					f.logger.WithError(err).Error("Periodic validator fetch failed")
 				}
@@ -197,40 +405,103 @@ func (f *Fetcher) Start(ctx context.Context) {
 	}()
 }
 
+// Pause skips periodic fetches until Resume; the cached validators keep
+// being served.
+func (f *Fetcher) Pause(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paused = true
+	return nil
+}
+
+// Resume lets periodic fetches run again from the next refresh tick.
+func (f *Fetcher) Resume(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paused = false
+	return nil
+}
+
 // Stop stops the periodic fetching
 func (f *Fetcher) Stop() {
 	close(f.stopChan)
 }
 
-// Fetch retrieves current validators from XRPL
-func (f *Fetcher) Fetch(ctx context.Context) error {
+// Fetch retrieves current validators from XRPL. Each stage of the cycle is
+// tracked for FetchStatus, and cycle callbacks run when it completes.
+//
+// The validator list and the node's trusted keys are alternative sources:
+// a cycle proceeds with whichever succeeded and only fails, keeping the
+// previous snapshot, when both did. The sources used are reported by
+// SourceStatus.
+func (f *Fetcher) Fetch(ctx context.Context) (err error) {
 	f.logger.Debug("Fetching validators from XRPL")
 
-	// Query XRPL for validator information
-	// Using ledger_closed subscription to get updated validator set
-	result, err := f.fetchValidatorList(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch validator list: %w", err)
-	}
+	var validators []*models.Validator
+	f.progress.begin()
+	defer func() { f.progress.end(len(validators), err) }()
+	sources := &models.SourceStatus{OK: []string{}, Failed: []string{}}
 
-	validators, err := f.parseValidators(result)
-	if err != nil {
-		return fmt.Errorf("failed to parse validators: %w", err)
+	f.progress.beginStage(StageValidatorList)
+	result, listErr := f.fetchValidatorList(ctx)
+	if listErr != nil {
+		listErr = fmt.Errorf("failed to fetch validator list: %w", listErr)
+	} else if validators, listErr = f.parseValidators(result); listErr != nil {
+		f.debugCapture.Capture(debugcapture.SourceValidatorList, listErr, result)
+		validators = nil
+		listErr = fmt.Errorf("failed to parse validators: %w", listErr)
 	}
+	if listErr != nil {
+		f.logger.WithError(listErr).Warn("Validator list unavailable, continuing with trusted validators")
+	}
+	recordSource(sources, StageValidatorList, listErr)
+	f.progress.endStage(len(validators), listErr)
 
-	trustedValidators, trustedSet, err := f.fetchTrustedValidatorsFromXRPL(ctx)
-	if err != nil {
-		f.logger.WithError(err).Warn("Failed to fetch trusted validators from XRPL")
+	f.progress.beginStage(StageTrustedValidators)
+	trustedValidators, trustedSet, trustedErr := f.fetchTrustedValidatorsFromXRPL(ctx)
+	if trustedErr != nil {
+		f.logger.WithError(trustedErr).Warn("Failed to fetch trusted validators from XRPL")
 	}
+	recordSource(sources, StageTrustedValidators, trustedErr)
 	validators = mergeValidators(validators, trustedValidators)
+	f.progress.endStage(len(validators), trustedErr)
+	if listErr != nil && trustedErr != nil {
+		err = fmt.Errorf("no validator source succeeded: %w", errors.Join(listErr, trustedErr))
+		return err
+	}
+	domainSources := make(map[string]string, len(validators))
+	for _, v := range validators {
+		if v.Domain != "" {
+			domainSources[v.Address] = DomainSourceValidatorList
+		}
+	}
 
-	validators, err = f.applySecondaryRegistryDomains(ctx, validators, trustedSet)
-	if err != nil {
-		f.logger.WithError(err).Warn("Failed to enrich validators from secondary registry")
+	f.progress.beginStage(StageSecondaryRegistry)
+	validators, registryErr := f.applySecondaryRegistryDomains(ctx, validators, trustedSet)
+	if registryErr != nil {
+		f.logger.WithError(registryErr).Warn("Failed to enrich validators from secondary registry")
+	}
+	recordSource(sources, StageSecondaryRegistry, registryErr)
+	f.progress.endStage(len(validators), registryErr)
+	for _, v := range validators {
+		if _, ok := domainSources[v.Address]; !ok && v.Domain != "" {
+			domainSources[v.Address] = DomainSourceSecondaryRegistry
+		}
 	}
 
+	f.attributePublishers(ctx, validators)
+
 	// Apply previously persisted metadata before live enrichment to maximize coverage.
 	f.applyPersistedMetadata(validators)
+	f.progress.beginStage(StageProfiles)
+	f.applyDomainProfiles(ctx, validators)
+	f.applyBadges(validators, domainSources)
+	f.progress.endStage(len(validators), nil)
+	if f.peerProbeInterval > 0 {
+		f.progress.beginStage(StagePeerProbes)
+		f.applyPeerReachability(ctx, validators)
+		f.progress.endStage(len(validators), nil)
+	}
 
 	// Limit the number of validators to prevent memory exhaustion
 	if len(validators) > f.maxValidators {
@@ -242,34 +513,255 @@ func (f *Fetcher) Fetch(ctx context.Context) error {
 	}
 
 	// Enrich validators with geolocation data
+	f.progress.beginStage(StageGeolocation)
+	unresolved := make(map[string]bool)
 	for _, v := range validators {
+		resolved := false
 		if f.geolocationProvider != nil {
 			if err := f.geolocationProvider.EnrichValidator(v); err != nil {
 				f.logger.WithError(err).WithField("address", v.Address).Warn("Failed to enrich validator geolocation")
+			} else {
+				resolved = true
 			}
 		}
+		if !resolved && (v.Latitude != 0 || v.Longitude != 0) {
+			unresolved[v.Address] = true // restored from persisted metadata
+		}
 	}
 
+	// Reject implausible coordinates before the coverage lock can persist them.
+	f.checkCoordinates(validators)
+
 	// Coverage lock: never regress from known mapped coordinates to zeroed coordinates.
-	f.preserveMappedCoverage(validators)
+	locationSources := f.preserveMappedCoverage(validators, unresolved)
+	stale := 0
+	for _, v := range validators {
+		if v.StaleLocation {
+			stale++
+		}
+	}
+	metrics.ValidatorsStaleLocation.Set(float64(stale))
+	f.assignOperators(validators)
+	f.progress.endStage(len(validators), nil)
 
-	// Update cache
-	f.mu.Lock()
-	f.validators = make(map[string]*models.Validator)
+	// Update cache. An unchanged snapshot keeps the previous one, so that
+	// validators keep their last_updated and ETags and serialized responses
+	// stay valid; the fetch still counts as a successful cycle.
+	current := make(map[string]*models.Validator, len(validators))
 	for _, v := range validators {
-		f.validators[v.Address] = v
+		current[v.Address] = v
 	}
-	f.lastUpdate = time.Now()
+	hash := SnapshotHash(current)
+	f.mu.Lock()
+	previous := f.validators
+	// Clients may already hold a provisional set restored at startup; they
+	// get the first fetch's changes to it like any later cycle's.
+	initialLoad := f.lastUpdate.IsZero() && f.provisionalSince.IsZero()
+	changed := initialLoad || hash != f.snapshotHash
+	if changed {
+		f.validators = current
+		f.snapshotHash = hash
+	}
+	f.sources = sources
+	f.lastUpdate = f.clock.Now()
+	f.provisionalSince = time.Time{}
+	callbacks := append([]UpdateCallback(nil), f.callbacks...)
+	rotationCallbacks := append([]RotationCallback(nil), f.rotationCallbacks...)
 	f.mu.Unlock()
+	if changed {
+		metrics.ValidatorSnapshotsTotal.WithLabelValues("changed").Inc()
+		metrics.ValidatorsCount.Set(float64(len(current)))
+	} else {
+		metrics.ValidatorSnapshotsTotal.WithLabelValues("unchanged").Inc()
+	}
 
-	f.updatePersistedMetadata(validators)
+	f.progress.beginStage(StagePersist)
+	rotations := f.updatePersistedMetadata(validators, domainSources, locationSources)
+	f.progress.endStage(len(validators), nil)
 
-	f.logger.WithField("count", len(validators)).Info("Validators updated")
+	// The initial load is served by /validators; only push later deltas.
+	if changed && !initialLoad && len(callbacks) > 0 {
+		if update := DiffValidators(previous, current); len(update.Upserts) > 0 || len(update.Removals) > 0 {
+			for _, callback := range callbacks {
+				callback(update)
+			}
+		}
+	}
+	for _, rotation := range rotations {
+		for _, callback := range rotationCallbacks {
+			callback(rotation)
+		}
+	}
+
+	f.logger.WithFields(logrus.Fields{
+		"count":          len(validators),
+		"changed":        changed,
+		"sources_failed": sources.Failed,
+	}).Info("Validators updated")
 	return nil
 }
 
-func (f *Fetcher) preserveMappedCoverage(validators []*models.Validator) {
+// recordSource adds a source to the cycle's status as succeeded or failed.
+func recordSource(status *models.SourceStatus, source string, err error) {
+	if err != nil {
+		status.Failed = append(status.Failed, source)
+		return
+	}
+	status.OK = append(status.OK, source)
+}
+
+// SnapshotHash returns a content hash of a validator set keyed by address.
+// Like DiffValidators it ignores last_updated, so two cycles that fetched the
+// same validators hash equally.
+func SnapshotHash(validators map[string]*models.Validator) string {
+	addresses := make([]string, 0, len(validators))
+	for address, v := range validators {
+		if v != nil {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+
+	h := sha256.New()
+	for _, address := range addresses {
+		// Map keys are encoded sorted, so the encoding is stable.
+		fields, _ := json.Marshal(validatorFields(validators[address]))
+		fmt.Fprintf(h, "%s\x00%s\x00", address, fields)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SourceStatus returns which sources the current validator set was built
+// from and which failed in that cycle, or nil before the first successful
+// cycle.
+func (f *Fetcher) SourceStatus() *models.SourceStatus {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.sources == nil {
+		return nil
+	}
+	return &models.SourceStatus{
+		OK:     append([]string{}, f.sources.OK...),
+		Failed: append([]string{}, f.sources.Failed...),
+	}
+}
+
+// SnapshotHash returns the content hash of the current validator set; it
+// changes only when a fetch cycle changes the validators.
+func (f *Fetcher) SnapshotHash() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.snapshotHash
+}
+
+// DiffValidators compares two validator sets keyed by address. Upserts carry
+// only the JSON fields that changed; last_updated is ignored because it is
+// refreshed on every cycle. Each delta records the validator's countries
+// before and after the change. Results are sorted by address.
+func DiffValidators(previous, current map[string]*models.Validator) *models.ValidatorUpdate {
+	update := &models.ValidatorUpdate{
+		Upserts:  make([]*models.ValidatorDelta, 0),
+		Removals: make([]*models.ValidatorDelta, 0),
+	}
+	for address, cur := range current {
+		if cur == nil {
+			continue
+		}
+		curFields := validatorFields(cur)
+		prev, ok := previous[address]
+		if !ok || prev == nil {
+			curFields["publishers"] = cur.Publishers
+			update.Upserts = append(update.Upserts, &models.ValidatorDelta{Address: address, Fields: curFields, Countries: []string{cur.CountryCode}})
+			continue
+		}
+		prevFields := validatorFields(prev)
+		changed := make(map[string]interface{})
+		for name, value := range curFields {
+			if prevValue, exists := prevFields[name]; !exists || prevValue != value {
+				changed[name] = value
+			}
+		}
+		if _, ok := changed["publishers"]; ok {
+			changed["publishers"] = cur.Publishers
+		}
+		if len(changed) > 0 {
+			countries := []string{cur.CountryCode}
+			if prev.CountryCode != cur.CountryCode {
+				countries = append(countries, prev.CountryCode)
+			}
+			update.Upserts = append(update.Upserts, &models.ValidatorDelta{Address: address, Fields: changed, Countries: countries})
+		}
+	}
+	for address, prev := range previous {
+		if _, ok := current[address]; !ok {
+			delta := &models.ValidatorDelta{Address: address}
+			if prev != nil {
+				delta.Countries = []string{prev.CountryCode}
+			}
+			update.Removals = append(update.Removals, delta)
+		}
+	}
+	sort.Slice(update.Upserts, func(i, j int) bool { return update.Upserts[i].Address < update.Upserts[j].Address })
+	sort.Slice(update.Removals, func(i, j int) bool { return update.Removals[i].Address < update.Removals[j].Address })
+	return update
+}
+
+// validatorFields returns the validator's JSON fields, excluding the address
+// and last_updated. Values are comparable with ==, so pointers are
+// dereferenced and publishers are keyed by publishersKey; DiffValidators
+// puts the list back in the fields it sends.
+func validatorFields(v *models.Validator) map[string]interface{} {
+	var peerReachable interface{}
+	if v.PeerReachable != nil {
+		peerReachable = *v.PeerReachable
+	}
+	return map[string]interface{}{
+		"public_key":        v.PublicKey,
+		"domain":            v.Domain,
+		"name":              v.Name,
+		"network":           v.Network,
+		"latitude":          v.Latitude,
+		"longitude":         v.Longitude,
+		"country_code":      v.CountryCode,
+		"city":              v.City,
+		"approximate":       v.Approximate,
+		"stale_location":    v.StaleLocation,
+		"icon":              v.Icon,
+		"twitter":           v.Twitter,
+		"description":       v.Description,
+		"owner":             v.Owner,
+		"verified_owner":    v.VerifiedOwner,
+		"consistent_votes":  v.ConsistentVotes,
+		"operator":          v.Operator,
+		"peer_reachable":    peerReachable,
+		"peer_port":         v.PeerPort,
+		"peer_checked_at":   v.PeerCheckedAt,
+		"signing_key":       v.SigningKey,
+		"manifest_sequence": v.ManifestSequence,
+		"is_active":         v.IsActive,
+		"publishers":        publishersKey(v.Publishers),
+	}
+}
+
+// publishersKey encodes publishers as a comparable string that does not
+// depend on their order.
+func publishersKey(publishers []string) string {
+	sorted := append([]string(nil), publishers...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// preserveMappedCoverage keeps the known locations of validators left
+// unmapped by this cycle, and of validators only placed at a country
+// centroid where a city was known. unresolved names validators whose
+// location was restored from persisted metadata rather than resolved. Kept
+// locations not confirmed within the lock TTL are flagged StaleLocation and
+// no longer override a country centroid. It returns AuditSourceCoverageLock
+// for each validator whose location it kept.
+func (f *Fetcher) preserveMappedCoverage(validators []*models.Validator, unresolved map[string]bool) map[string]string {
+	sources := make(map[string]string)
 	previous := make(map[string]*models.Validator)
+	now := f.clock.Now()
 
 	f.mu.RLock()
 	for k, v := range f.validators {
@@ -283,21 +775,41 @@ func (f *Fetcher) preserveMappedCoverage(validators []*models.Validator) {
 		if v == nil || v.Address == "" {
 			continue
 		}
-		// Already mapped; keep fresh value.
+		prev, hasPrev := previous[v.Address]
+		hasPrev = hasPrev && (prev.Latitude != 0 || prev.Longitude != 0)
+		// Already mapped; keep fresh value, unless it is only a country
+		// centroid and the prior value placed the validator in a city of
+		// the same country.
 		if v.Latitude != 0 || v.Longitude != 0 {
+			if unresolved[v.Address] {
+				v.StaleLocation = f.lockedLocationExpired(v.Address, now)
+				sources[v.Address] = AuditSourceCoverageLock
+				continue
+			}
+			if v.Approximate && hasPrev && !prev.Approximate && prev.CountryCode == v.CountryCode &&
+				!f.lockedLocationExpired(v.Address, now) {
+				v.Latitude = prev.Latitude
+				v.Longitude = prev.Longitude
+				v.City = prev.City
+				v.Approximate = false
+				sources[v.Address] = AuditSourceCoverageLock
+			}
 			continue
 		}
 
 		// Prefer prior in-memory mapped value if present.
-		if prev, ok := previous[v.Address]; ok && (prev.Latitude != 0 || prev.Longitude != 0) {
+		if hasPrev {
+			v.StaleLocation = f.lockedLocationExpired(v.Address, now)
 			v.Latitude = prev.Latitude
 			v.Longitude = prev.Longitude
+			v.Approximate = prev.Approximate
 			if v.CountryCode == "" || v.CountryCode == "XX" {
 				v.CountryCode = prev.CountryCode
 			}
 			if v.City == "" || v.City == "Unknown" {
 				v.City = prev.City
 			}
+			sources[v.Address] = AuditSourceCoverageLock
 			continue
 		}
 
@@ -308,14 +820,35 @@ func (f *Fetcher) preserveMappedCoverage(validators []*models.Validator) {
 		if entry != nil && (entry.Latitude != 0 || entry.Longitude != 0) {
 			v.Latitude = entry.Latitude
 			v.Longitude = entry.Longitude
+			v.Approximate = entry.Approximate
 			if v.CountryCode == "" || v.CountryCode == "XX" {
 				v.CountryCode = entry.CountryCode
 			}
 			if v.City == "" || v.City == "Unknown" {
 				v.City = entry.City
 			}
+			v.StaleLocation = f.lockedLocationExpired(v.Address, now)
+			sources[v.Address] = AuditSourceCoverageLock
 		}
 	}
+	return sources
+}
+
+// lockedLocationExpired reports whether the validator's location was last
+// confirmed by geolocation longer than the lock TTL ago.
+func (f *Fetcher) lockedLocationExpired(address string, now time.Time) bool {
+	f.sourceStateMu.Lock()
+	defer f.sourceStateMu.Unlock()
+	return f.locationExpired(f.metadataCache[address], now)
+}
+
+// locationExpired reports whether entry's location was last confirmed
+// longer than the lock TTL ago. The caller holds f.sourceStateMu.
+func (f *Fetcher) locationExpired(entry *validatorMetadataEntry, now time.Time) bool {
+	if f.locationLockTTL <= 0 || entry == nil || entry.LocationConfirmedAt == 0 {
+		return false
+	}
+	return now.Sub(time.Unix(entry.LocationConfirmedAt, 0)) > f.locationLockTTL
 }
 
 // GetValidators returns the cached list of validators
@@ -369,7 +902,7 @@ func (f *Fetcher) GetServerStatus(ctx context.Context) (*models.ServerStatus, er
 func (f *Fetcher) getServerStatusFromEndpoint(ctx context.Context, endpoint string) (*models.ServerStatus, error) {
 	var lastErr error
 	for attempt := 1; attempt <= f.networkHealthRetries; attempt++ {
-		result, err := f.fetchServerInfoFromJSONRPC(ctx, endpoint)
+		result, err := f.networkHealthClients[endpoint].GetServerInfo(ctx)
 		if err == nil {
 			status, parseErr := parseServerStatusResult(result)
 			if parseErr == nil {
@@ -377,278 +910,249 @@ func (f *Fetcher) getServerStatusFromEndpoint(ctx context.Context, endpoint stri
 			}
 			err = parseErr
 		}
+		f.logger.WithError(err).WithFields(logrus.Fields{
+			"endpoint": endpoint,
+			"attempt":  attempt,
+		}).Debug("Network health probe failed")
 		lastErr = err
-		if attempt == f.networkHealthRetries {
+		if attempt == f.networkHealthRetries || !xrpl.IsRetryable(err) {
 			break
 		}
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
-		case <-time.After(time.Duration(attempt) * 150 * time.Millisecond):
+		case <-f.clock.After(time.Duration(attempt) * 150 * time.Millisecond):
 		}
 	}
 	return nil, lastErr
 }
 
-func (f *Fetcher) fetchServerInfoFromJSONRPC(ctx context.Context, endpoint string) (map[string]interface{}, error) {
-	requestPayload := map[string]interface{}{
-		"method":  "server_info",
-		"params":  []interface{}{map[string]interface{}{}},
-		"id":      1,
-		"jsonrpc": "2.0",
-	}
-	body, err := json.Marshal(requestPayload)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
+func parseServerStatusResult(result interface{}) (*models.ServerStatus, error) {
+	r := jsonutil.NewReader(result)
+	info := r.Map("result.info")
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("%w: server_info: %w", xrpl.ErrDecode, err)
+	}
+
+	validatedLedger := jsonutil.Map(info, "validated_ledger")
+	completeLedgers := jsonutil.String(info, "complete_ledgers")
+	history := health.ParseCompleteLedgers(completeLedgers)
+	serverState := jsonutil.String(info, "server_state")
+	amendmentBlocked := jsonutil.Bool(info, "amendment_blocked")
+	_, reporting := info["reporting"]
+	return &models.ServerStatus{
+		Connected:          true,
+		ServerState:        serverState,
+		LedgerIndex:        uint32(jsonutil.Int64(validatedLedger, "seq")),
+		NetworkID:          uint16(jsonutil.Int64(info, "network_id")),
+		PeerCount:          int(jsonutil.Int64(info, "peers")),
+		CompleteLedgers:    completeLedgers,
+		Uptime:             jsonutil.Int64(info, "uptime"),
+		LastSync:           time.Now().Unix(),
+		ValidatedLedgerAge: jsonutil.Int64(validatedLedger, "age"),
+		AmendmentBlocked:   amendmentBlocked,
+		ReportingMode:      reporting || serverState == "reporting",
+		CompleteLedgerSpan: history.Span,
+		OldestLedger:       history.OldestLedger,
+		LedgerGaps:         history.Gaps,
+	}, nil
+}
 
-	resp, err := f.httpClient.Do(req)
-	if err != nil {
-		return nil, err
+// fetchValidatorList queries XRPL for validator data
+func (f *Fetcher) fetchValidatorList(ctx context.Context) (interface{}, error) {
+	var lastErr error
+	for _, validatorListURL := range f.validatorListSites {
+		result, err := f.fetchValidatorListSite(ctx, validatorListURL)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, errSourceCooldown) {
+			lastErr = err
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 120))
-		return nil, fmt.Errorf("http %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	for _, validatorListURL := range f.validatorListSites {
+		if cached, ok := f.getValidatorListCache(validatorListURL, true); ok {
+			f.logger.WithField("url", validatorListURL).Warn("Using stale validator list cache after source failures")
+			return cached, nil
+		}
 	}
 
-	var parsed map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-		return nil, err
-	}
-	if errorResult, ok := parsed["error"]; ok {
-		return nil, fmt.Errorf("JSON-RPC error: %v", errorResult)
-	}
-	return parsed, nil
+	return nil, fmt.Errorf("failed after %d attempts: %w", validatorListMaxRetries, lastErr)
 }
 
-func parseServerStatusResult(result interface{}) (*models.ServerStatus, error) {
-	resultMap, ok := result.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected server_info response format")
-	}
-	payload, ok := resultMap["result"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("missing server_info result payload")
-	}
-	info, ok := payload["info"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("missing server_info info payload")
-	}
+// errSourceCooldown is returned for a source skipped while in cooldown
+// without a cached response.
+var errSourceCooldown = errors.New("source is in cooldown")
 
-	return &models.ServerStatus{
-		Connected:       true,
-		ServerState:     getString(info, "server_state"),
-		LedgerIndex:     uint32(getInt64(getMap(info, "validated_ledger"), "seq")),
-		NetworkID:       uint16(getInt64(info, "network_id")),
-		PeerCount:       int(getInt64(info, "peers")),
-		CompleteLedgers: getString(info, "complete_ledgers"),
-		Uptime:          getInt64(info, "uptime"),
-		LastSync:        time.Now().Unix(),
-	}, nil
-}
+// validatorListMaxRetries is the number of attempts made per site.
+const validatorListMaxRetries = 3
 
-func getMap(parent map[string]interface{}, key string) map[string]interface{} {
-	value, ok := parent[key].(map[string]interface{})
-	if !ok {
-		return map[string]interface{}{}
+// fetchValidatorListSite returns the decoded list of one site, from the
+// cache while it is fresh, or while the site is in cooldown.
+func (f *Fetcher) fetchValidatorListSite(ctx context.Context, validatorListURL string) (map[string]interface{}, error) {
+	if until, ok := f.getSourceCooldown("validator-list:" + validatorListURL); ok && f.clock.Now().Before(until) {
+		f.logger.WithFields(logrus.Fields{
+			"url":      validatorListURL,
+			"cooldown": until.Format(time.RFC3339),
+		}).Warn("Skipping validator list source while in cooldown")
+		if cached, ok := f.getValidatorListCache(validatorListURL, true); ok {
+			return cached, nil
+		}
+		return nil, errSourceCooldown
 	}
-	return value
-}
-
-func getString(parent map[string]interface{}, key string) string {
-	value, _ := parent[key].(string)
-	return value
-}
-
-func getInt64(parent map[string]interface{}, key string) int64 {
-	switch value := parent[key].(type) {
-	case float64:
-		return int64(value)
-	case int64:
-		return value
-	case int:
-		return int64(value)
-	default:
-		return 0
+	if cached, ok := f.getValidatorListCache(validatorListURL, false); ok {
+		return cached, nil
 	}
-}
 
-// fetchValidatorList queries XRPL for validator data
-func (f *Fetcher) fetchValidatorList(ctx context.Context) (interface{}, error) {
 	var lastErr error
-	maxRetries := 3
-	for _, validatorListURL := range f.validatorListSites {
-		if until, ok := f.getSourceCooldown("validator-list:" + validatorListURL); ok && time.Now().Before(until) {
+	for attempt := 0; attempt < validatorListMaxRetries; attempt++ {
+		if attempt > 0 {
+			// Exponential backoff
+			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
 			f.logger.WithFields(logrus.Fields{
-				"url":      validatorListURL,
-				"cooldown": until.Format(time.RFC3339),
-			}).Warn("Skipping validator list source while in cooldown")
-			if cached, ok := f.getValidatorListCache(validatorListURL, true); ok {
-				return cached, nil
+				"attempt": attempt,
+				"backoff": backoff,
+				"url":     validatorListURL,
+			}).Debug("Retrying validator list fetch")
+			select {
+			case <-f.clock.After(backoff):
+			case <-ctx.Done():
+				return nil, ctx.Err()
 			}
-			continue
-		}
-		if cached, ok := f.getValidatorListCache(validatorListURL, false); ok {
-			return cached, nil
 		}
 
-		for attempt := 0; attempt < maxRetries; attempt++ {
-			if attempt > 0 {
-				// Exponential backoff
-				backoff := time.Duration(1<<uint(attempt-1)) * time.Second
-				f.logger.WithFields(logrus.Fields{
-					"attempt": attempt,
-					"backoff": backoff,
-					"url":     validatorListURL,
-				}).Debug("Retrying validator list fetch")
-				select {
-				case <-time.After(backoff):
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				}
-			}
+		// Create HTTP request
+		req, err := http.NewRequestWithContext(ctx, "GET", validatorListURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
 
-			// Create HTTP request
-			req, err := http.NewRequestWithContext(ctx, "GET", validatorListURL, nil)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create request: %w", err)
-			}
-			req.Header.Set("Accept", "application/json")
-
-			// Send request
-			resp, err := f.httpClient.Do(req)
-			if err != nil {
-				lastErr = fmt.Errorf("failed to fetch validator list: %w", err)
-				f.logger.WithError(err).WithFields(logrus.Fields{
-					"attempt": attempt + 1,
-					"url":     validatorListURL,
-				}).Warn("Validator list fetch failed")
-				continue
+		// Send request
+		resp, err := f.httpClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to fetch validator list: %w", xrpl.WrapTransportError(err))
+			metrics.UpstreamErrorsTotal.WithLabelValues("validator_list", xrpl.Classify(lastErr)).Inc()
+			f.logger.WithError(err).WithFields(logrus.Fields{
+				"attempt": attempt + 1,
+				"url":     validatorListURL,
+			}).Warn("Validator list fetch failed")
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+				f.setSourceCooldown(
+					"validator-list:"+validatorListURL,
+					cooldownFromResponse(resp, f.clock.Now(), defaultRateLimitCooldown),
+				)
 			}
-			if resp.StatusCode != http.StatusOK {
-				if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
-					f.setSourceCooldown(
-						"validator-list:"+validatorListURL,
-						cooldownFromResponse(resp, defaultRateLimitCooldown),
-					)
-				}
-				resp.Body.Close()
-				lastErr = fmt.Errorf("validator list site returned status %d", resp.StatusCode)
-				f.logger.WithFields(logrus.Fields{
-					"status":  resp.StatusCode,
-					"attempt": attempt + 1,
-					"url":     validatorListURL,
-				}).Warn("Validator list fetch failed with bad status")
-				continue
+			resp.Body.Close()
+			lastErr = fmt.Errorf("validator list site returned status: %w", &xrpl.HTTPStatusError{StatusCode: resp.StatusCode})
+			metrics.UpstreamErrorsTotal.WithLabelValues("validator_list", xrpl.Classify(lastErr)).Inc()
+			f.logger.WithFields(logrus.Fields{
+				"status":  resp.StatusCode,
+				"attempt": attempt + 1,
+				"url":     validatorListURL,
+			}).Warn("Validator list fetch failed with bad status")
+			if !xrpl.IsRetryable(lastErr) {
+				break
 			}
+			continue
+		}
 
-			// Parse response
-			var result map[string]interface{}
-			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-				resp.Body.Close()
-				lastErr = fmt.Errorf("failed to parse validator list: %w", err)
-				f.logger.WithError(err).WithFields(logrus.Fields{
-					"attempt": attempt + 1,
-					"url":     validatorListURL,
-				}).Warn("Validator list parse failed")
-				continue
-			}
+		// Parse response
+		var result map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 			resp.Body.Close()
+			lastErr = fmt.Errorf("%w: validator list: %w", xrpl.ErrDecode, err)
+			metrics.UpstreamErrorsTotal.WithLabelValues("validator_list", xrpl.Classify(lastErr)).Inc()
+			f.logger.WithError(err).WithFields(logrus.Fields{
+				"attempt": attempt + 1,
+				"url":     validatorListURL,
+			}).Warn("Validator list parse failed")
+			continue
+		}
+		resp.Body.Close()
 
-			// Decode the base64 blob containing the validator list
-			blobStr, ok := result["blob"].(string)
-			if !ok {
-				lastErr = fmt.Errorf("no blob field in validator list response")
-				f.logger.WithFields(logrus.Fields{
-					"attempt": attempt + 1,
-					"url":     validatorListURL,
-				}).Warn("No blob field in validator list response")
-				continue
-			}
-
-			blobData, err := base64.StdEncoding.DecodeString(blobStr)
-			if err != nil {
-				lastErr = fmt.Errorf("failed to decode base64 blob: %w", err)
-				f.logger.WithError(err).WithFields(logrus.Fields{
-					"attempt": attempt + 1,
-					"url":     validatorListURL,
-				}).Warn("Base64 decode failed")
-				continue
-			}
+		blobResult, err := decodeValidatorListBlob(result)
+		if err != nil {
+			lastErr = err
+			metrics.UpstreamErrorsTotal.WithLabelValues("validator_list", xrpl.Classify(lastErr)).Inc()
+			f.logger.WithError(err).WithFields(logrus.Fields{
+				"attempt": attempt + 1,
+				"url":     validatorListURL,
+			}).Warn("Validator list blob decode failed")
+			continue
+		}
 
-			// Parse the decoded blob as JSON
-			var blobResult map[string]interface{}
-			if err := json.Unmarshal(blobData, &blobResult); err != nil {
-				lastErr = fmt.Errorf("failed to parse decoded blob: %w", err)
-				f.logger.WithError(err).WithFields(logrus.Fields{
-					"attempt": attempt + 1,
-					"url":     validatorListURL,
-				}).Warn("Blob parse failed")
-				continue
+		publisherChanged := f.recordPublisher(validatorListURL, result)
+		if f.recordListExpiration(validatorListURL, blobResult) || publisherChanged {
+			if err := f.persistMetadataCache(); err != nil {
+				f.logger.WithError(err).Warn("Failed to persist validator metadata cache")
 			}
-
-			f.setValidatorListCache(validatorListURL, blobResult)
-			return blobResult, nil
 		}
+		f.setValidatorListCache(validatorListURL, blobResult)
+		return blobResult, nil
 	}
+	return nil, lastErr
+}
 
-	for _, validatorListURL := range f.validatorListSites {
-		if cached, ok := f.getValidatorListCache(validatorListURL, true); ok {
-			f.logger.WithField("url", validatorListURL).Warn("Using stale validator list cache after source failures")
-			return cached, nil
-		}
+// decodeValidatorListBlob extracts and decodes the base64 JSON blob carried
+// by a validator list site response.
+func decodeValidatorListBlob(result map[string]interface{}) (map[string]interface{}, error) {
+	blobStr, ok := result["blob"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: no blob field in validator list response", xrpl.ErrDecode)
 	}
 
-	return nil, fmt.Errorf("failed after %d attempts: %w", maxRetries, lastErr)
+	blobData, err := base64.StdEncoding.DecodeString(blobStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: base64 blob: %w", xrpl.ErrDecode, err)
+	}
+
+	var blobResult map[string]interface{}
+	if err := json.Unmarshal(blobData, &blobResult); err != nil {
+		return nil, fmt.Errorf("%w: decoded blob: %w", xrpl.ErrDecode, err)
+	}
+	if blobResult == nil {
+		return nil, fmt.Errorf("%w: decoded blob is empty", xrpl.ErrDecode)
+	}
+	return blobResult, nil
 }
 
 func (f *Fetcher) fetchTrustedValidatorsFromXRPL(ctx context.Context) ([]*models.Validator, map[string]struct{}, error) {
+	if f.client == nil {
+		return nil, nil, fmt.Errorf("no XRPL client")
+	}
 	resp, err := f.client.Command(ctx, "validators", map[string]interface{}{})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	respMap, ok := resp.(map[string]interface{})
-	if !ok {
-		return nil, nil, fmt.Errorf("unexpected validators response format")
-	}
-	resultMap, ok := respMap["result"].(map[string]interface{})
-	if !ok {
-		return nil, nil, fmt.Errorf("validators response missing result")
+	r := jsonutil.NewReader(resp)
+	resultMap := r.Map("result")
+	if err := r.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: validators: %w", xrpl.ErrDecode, err)
 	}
-	keysRaw, _ := resultMap["trusted_validator_keys"].([]interface{})
+	keysRaw := jsonutil.Slice(resultMap, "trusted_validator_keys")
 	rawKeys := make([]interface{}, 0, len(keysRaw))
 	rawKeys = append(rawKeys, keysRaw...)
 
 	// During startup/bootstrap, trusted_validator_keys may be empty.
 	// Fall back to publisher list keys so we can still map validator metadata.
 	if len(rawKeys) == 0 {
-		if publisherLists, ok := resultMap["publisher_lists"].([]interface{}); ok {
-			for _, listRaw := range publisherLists {
-				listMap, ok := listRaw.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				members, ok := listMap["list"].([]interface{})
-				if !ok {
-					continue
-				}
-				rawKeys = append(rawKeys, members...)
-			}
+		for _, listRaw := range jsonutil.Slice(resultMap, "publisher_lists") {
+			rawKeys = append(rawKeys, jsonutil.Slice(jsonutil.Object(listRaw), "list")...)
 		}
 	}
 
 	out := make([]*models.Validator, 0, len(rawKeys))
 	keySet := make(map[string]struct{}, len(rawKeys))
-	now := time.Now().Unix()
+	now := f.clock.Now().Unix()
 
 	for _, raw := range rawKeys {
 		key, ok := raw.(string)
@@ -684,7 +1188,7 @@ func (f *Fetcher) applySecondaryRegistryDomains(ctx context.Context, validators
 		return validators, fmt.Errorf("invalid secondary registry URL: %w", err)
 	}
 
-	if until, ok := f.getSourceCooldown("registry:" + registryURL); ok && time.Now().Before(until) {
+	if until, ok := f.getSourceCooldown("registry:" + registryURL); ok && f.clock.Now().Before(until) {
 		if cached, ok := f.getSecondaryRegistryCache(true); ok {
 			return f.mergeSecondaryRegistry(validators, trustedSet, cached), nil
 		}
@@ -697,6 +1201,8 @@ func (f *Fetcher) applySecondaryRegistryDomains(ctx context.Context, validators
 	}
 	resp, err := f.httpClient.Do(req)
 	if err != nil {
+		err = xrpl.WrapTransportError(err)
+		metrics.UpstreamErrorsTotal.WithLabelValues("validator_registry", xrpl.Classify(err)).Inc()
 		if cached, ok := f.getSecondaryRegistryCache(true); ok {
 			f.logger.WithError(err).Warn("Using stale secondary registry cache after fetch error")
 			return f.mergeSecondaryRegistry(validators, trustedSet, cached), nil
@@ -705,20 +1211,24 @@ func (f *Fetcher) applySecondaryRegistryDomains(ctx context.Context, validators
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
+		statusErr := &xrpl.HTTPStatusError{StatusCode: resp.StatusCode}
+		metrics.UpstreamErrorsTotal.WithLabelValues("validator_registry", xrpl.Classify(statusErr)).Inc()
 		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
-			f.setSourceCooldown("registry:"+registryURL, cooldownFromResponse(resp, defaultRateLimitCooldown))
+			f.setSourceCooldown("registry:"+registryURL, cooldownFromResponse(resp, f.clock.Now(), defaultRateLimitCooldown))
 		} else {
-			f.setSourceCooldown("registry:"+registryURL, time.Now().Add(defaultSourceCooldown))
+			f.setSourceCooldown("registry:"+registryURL, f.clock.Now().Add(defaultSourceCooldown))
 		}
 		if cached, ok := f.getSecondaryRegistryCache(true); ok {
 			f.logger.WithField("status", resp.StatusCode).Warn("Using stale secondary registry cache after non-OK status")
 			return f.mergeSecondaryRegistry(validators, trustedSet, cached), nil
 		}
-		return validators, fmt.Errorf("secondary registry returned status %d", resp.StatusCode)
+		return validators, fmt.Errorf("secondary registry returned status: %w", statusErr)
 	}
 
 	var entries []secondaryRegistryEntry
 	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		err = fmt.Errorf("%w: secondary registry: %w", xrpl.ErrDecode, err)
+		metrics.UpstreamErrorsTotal.WithLabelValues("validator_registry", xrpl.Classify(err)).Inc()
 		if cached, ok := f.getSecondaryRegistryCache(true); ok {
 			f.logger.WithError(err).Warn("Using stale secondary registry cache after parse error")
 			return f.mergeSecondaryRegistry(validators, trustedSet, cached), nil
@@ -738,7 +1248,7 @@ func (f *Fetcher) mergeSecondaryRegistry(validators []*models.Validator, trusted
 		}
 	}
 
-	now := time.Now().Unix()
+	now := f.clock.Now().Unix()
 	for _, entry := range entries {
 		if entry.Chain != "" && entry.Chain != "main" {
 			continue
@@ -757,7 +1267,9 @@ func (f *Fetcher) mergeSecondaryRegistry(validators []*models.Validator, trusted
 			continue
 		}
 
+		profile := sanitizeProfile(entry.Icon, entry.Twitter, entry.Description)
 		if existing, ok := byAddress[entry.MasterKey]; ok {
+			profile.apply(existing, false)
 			if existing.Domain == "" {
 				existing.Domain = domain
 				if existing.Name == "" || existing.Name == existing.Address {
@@ -778,6 +1290,7 @@ func (f *Fetcher) mergeSecondaryRegistry(validators []*models.Validator, trusted
 			CountryCode: "XX",
 			City:        "Unknown",
 		}
+		profile.apply(v, false)
 		validators = append(validators, v)
 		byAddress[v.Address] = v
 	}
@@ -805,7 +1318,7 @@ func (f *Fetcher) getValidatorListCache(source string, allowStale bool) (map[str
 	if !ok || entry == nil {
 		return nil, false
 	}
-	if !allowStale && time.Now().After(entry.expiresAt) {
+	if !allowStale && f.clock.Now().After(entry.expiresAt) {
 		return nil, false
 	}
 	return entry.payload, true
@@ -815,7 +1328,7 @@ func (f *Fetcher) setValidatorListCache(source string, payload map[string]interf
 	f.sourceStateMu.Lock()
 	f.validatorListCache[source] = &validatorListCacheEntry{
 		payload:   payload,
-		expiresAt: time.Now().Add(validatorListCacheTTL),
+		expiresAt: f.clock.Now().Add(validatorListCacheTTL),
 	}
 	f.sourceStateMu.Unlock()
 }
@@ -827,7 +1340,7 @@ func (f *Fetcher) getSecondaryRegistryCache(allowStale bool) ([]secondaryRegistr
 	if entry == nil {
 		return nil, false
 	}
-	if !allowStale && time.Now().After(entry.expiresAt) {
+	if !allowStale && f.clock.Now().After(entry.expiresAt) {
 		return nil, false
 	}
 	out := make([]secondaryRegistryEntry, 0, len(entry.entries))
@@ -841,18 +1354,18 @@ func (f *Fetcher) setSecondaryRegistryCache(entries []secondaryRegistryEntry) {
 	f.sourceStateMu.Lock()
 	f.secondaryCache = &secondaryRegistryCacheEntry{
 		entries:   out,
-		expiresAt: time.Now().Add(secondaryRegistryCacheTTL),
+		expiresAt: f.clock.Now().Add(secondaryRegistryCacheTTL),
 	}
 	f.sourceStateMu.Unlock()
 }
 
-func cooldownFromResponse(resp *http.Response, fallback time.Duration) time.Time {
+func cooldownFromResponse(resp *http.Response, now time.Time, fallback time.Duration) time.Time {
 	retryAfter := strings.TrimSpace(resp.Header.Get("Retry-After"))
 	if retryAfter == "" {
-		return time.Now().Add(fallback)
+		return now.Add(fallback)
 	}
 	if secs, err := strconv.Atoi(retryAfter); err == nil && secs > 0 {
-		return time.Now().Add(time.Duration(secs) * time.Second)
+		return now.Add(time.Duration(secs) * time.Second)
 	}
 	if t, err := time.Parse(time.RFC1123, retryAfter); err == nil {
 		return t
@@ -860,7 +1373,7 @@ func cooldownFromResponse(resp *http.Response, fallback time.Duration) time.Time
 	if t, err := time.Parse(time.RFC1123Z, retryAfter); err == nil {
 		return t
 	}
-	return time.Now().Add(fallback)
+	return now.Add(fallback)
 }
 
 func (f *Fetcher) applyPersistedMetadata(validators []*models.Validator) {
@@ -886,13 +1399,20 @@ func (f *Fetcher) applyPersistedMetadata(validators []*models.Validator) {
 			v.Longitude = entry.Longitude
 			v.CountryCode = entry.CountryCode
 			v.City = entry.City
+			v.Approximate = entry.Approximate
 		}
 	}
 }
 
-func (f *Fetcher) updatePersistedMetadata(validators []*models.Validator) {
+// updatePersistedMetadata records the latest metadata of validators and
+// audits the changes. Domain changes are appended to the validator's domain
+// history, attributed to domainSources; location changes are attributed to
+// locationSources, or to geolocation.
+func (f *Fetcher) updatePersistedMetadata(validators []*models.Validator, domainSources, locationSources map[string]string) []*models.KeyRotation {
 	changed := false
-	now := time.Now().Unix()
+	now := f.clock.Now().Unix()
+	var domainChanges []logrus.Fields
+	var rotations []*models.KeyRotation
 
 	f.sourceStateMu.Lock()
 	for _, v := range validators {
@@ -903,23 +1423,75 @@ func (f *Fetcher) updatePersistedMetadata(validators []*models.Validator) {
 		if !ok || entry == nil {
 			entry = &validatorMetadataEntry{Address: v.Address}
 			f.metadataCache[v.Address] = entry
+			f.auditMetadata(v.Address, AuditFieldEntry, "", v.Address, AuditSourceFetch, now)
 			changed = true
 		}
 
 		if v.Domain != "" && entry.Domain != v.Domain {
+			change := &models.DomainChange{
+				OldDomain: entry.Domain,
+				NewDomain: v.Domain,
+				Source:    domainSources[v.Address],
+				ChangedAt: now,
+			}
+			entry.DomainHistory = append(entry.DomainHistory, change)
+			if len(entry.DomainHistory) > maxDomainHistory {
+				entry.DomainHistory = entry.DomainHistory[len(entry.DomainHistory)-maxDomainHistory:]
+			}
+			metrics.ValidatorDomainChangesTotal.WithLabelValues(change.Source).Inc()
+			if change.OldDomain != "" {
+				domainChanges = append(domainChanges, logrus.Fields{
+					"address":    v.Address,
+					"old_domain": change.OldDomain,
+					"new_domain": change.NewDomain,
+					"source":     change.Source,
+				})
+			}
+			source := change.Source
+			if source == "" {
+				source = AuditSourceFetch
+			}
+			f.auditMetadata(v.Address, AuditFieldDomain, entry.Domain, v.Domain, source, now)
 			entry.Domain = v.Domain
 			changed = true
 		}
 		if v.Name != "" && entry.Name != v.Name {
+			f.auditMetadata(v.Address, AuditFieldName, entry.Name, v.Name, AuditSourceFetch, now)
 			entry.Name = v.Name
 			changed = true
 		}
+
 		if (v.Latitude != 0 || v.Longitude != 0) &&
-			(entry.Latitude != v.Latitude || entry.Longitude != v.Longitude || entry.City != v.City || entry.CountryCode != v.CountryCode) {
+			(entry.Latitude != v.Latitude || entry.Longitude != v.Longitude || entry.City != v.City ||
+				entry.CountryCode != v.CountryCode || entry.Approximate != v.Approximate) {
+			source := locationSources[v.Address]
+			if source == "" {
+				source = AuditSourceGeolocation
+			}
+			f.auditMetadata(v.Address, AuditFieldLocation,
+				formatAuditLocation(entry.Latitude, entry.Longitude, entry.CountryCode, entry.City, entry.Approximate),
+				formatAuditLocation(v.Latitude, v.Longitude, v.CountryCode, v.City, v.Approximate),
+				source, now)
 			entry.Latitude = v.Latitude
 			entry.Longitude = v.Longitude
-			entry.CountryCode = v.CountryCode
-			entry.City = v.City
+			entry.CountryCode = intern.String(v.CountryCode)
+			entry.City = intern.String(v.City)
+			entry.Approximate = v.Approximate
+			changed = true
+		}
+		if (v.Latitude != 0 || v.Longitude != 0) && locationSources[v.Address] != AuditSourceCoverageLock &&
+			entry.LocationConfirmedAt != now {
+			entry.LocationConfirmedAt = now
+			changed = true
+		}
+		previousKey := entry.SigningKey
+		if rotation, updated := recordSigningKey(entry, v, now); updated {
+			if entry.SigningKey != previousKey {
+				f.auditMetadata(v.Address, AuditFieldSigningKey, previousKey, entry.SigningKey, AuditSourceManifest, now)
+			}
+			if rotation != nil {
+				rotations = append(rotations, rotation)
+			}
 			changed = true
 		}
 		if entry.LastSeenAt != now {
@@ -929,33 +1501,106 @@ func (f *Fetcher) updatePersistedMetadata(validators []*models.Validator) {
 	}
 	f.sourceStateMu.Unlock()
 
+	for _, fields := range domainChanges {
+		f.logger.WithFields(fields).Warn("Validator domain changed")
+	}
+	for _, rotation := range rotations {
+		metrics.ValidatorKeyRotationsTotal.Inc()
+		f.logger.WithFields(logrus.Fields{
+			"address":         rotation.Address,
+			"old_signing_key": rotation.OldSigningKey,
+			"new_signing_key": rotation.NewSigningKey,
+			"sequence":        rotation.NewSequence,
+		}).Info("Validator rotated signing key")
+	}
 	if changed {
 		if err := f.persistMetadataCache(); err != nil {
 			f.logger.WithError(err).Warn("Failed to persist validator metadata cache")
 		}
 	}
+	return rotations
+}
+
+// GetDomainHistory returns the recorded domain changes of a validator, or
+// xrpl.ErrNotFound if it has never been seen.
+func (f *Fetcher) GetDomainHistory(ctx context.Context, address string) (*models.DomainHistory, error) {
+	f.sourceStateMu.Lock()
+	defer f.sourceStateMu.Unlock()
+
+	entry := f.metadataEntryForKey(address)
+	if entry == nil {
+		return nil, fmt.Errorf("validator %s: %w", address, xrpl.ErrNotFound)
+	}
+	address = entry.Address
+	history := &models.DomainHistory{
+		Address:       address,
+		CurrentDomain: entry.Domain,
+		Changes:       make([]*models.DomainChange, 0, len(entry.DomainHistory)),
+	}
+	for _, change := range entry.DomainHistory {
+		copy := *change
+		history.Changes = append(history.Changes, &copy)
+	}
+	return history, nil
 }
 
 func (f *Fetcher) loadMetadataCache() {
-	data, err := os.ReadFile(f.metadataCachePath)
+	data, migratedFrom, err := validatorMetadataCacheFormat.Load(f.metadataCachePath)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			f.logger.WithError(err).WithField("path", f.metadataCachePath).Warn("Failed to read validator metadata cache")
+			f.logger.WithError(err).WithField("path", f.metadataCachePath).Warn("Failed to load validator metadata cache")
 		}
 		return
 	}
+	if migratedFrom != 0 {
+		f.logger.WithFields(logrus.Fields{
+			"path":   f.metadataCachePath,
+			"from":   migratedFrom,
+			"to":     validatorMetadataCacheVersion,
+			"backup": cachefile.BackupPath(f.metadataCachePath, migratedFrom),
+		}).Info("Migrated validator metadata cache")
+	}
 
 	var payload validatorMetadataCacheFile
 	if err := json.Unmarshal(data, &payload); err != nil {
 		f.logger.WithError(err).WithField("path", f.metadataCachePath).Warn("Failed to parse validator metadata cache")
 		return
 	}
-	if payload.Version != validatorMetadataCacheVersion || payload.Entries == nil {
-		return
+	if payload.Entries == nil {
+		payload.Entries = make(map[string]*validatorMetadataEntry)
+	}
+	for _, entry := range payload.Entries {
+		if entry != nil {
+			entry.CountryCode = intern.String(entry.CountryCode)
+			entry.City = intern.String(entry.City)
+			// Caches written before confirmations were recorded start
+			// the lock TTL from when the validator was last seen.
+			if entry.LocationConfirmedAt == 0 && (entry.Latitude != 0 || entry.Longitude != 0) {
+				entry.LocationConfirmedAt = entry.LastSeenAt
+			}
+		}
+	}
+
+	audit := make([]*models.MetadataAuditRecord, 0, len(payload.Audit))
+	for _, record := range payload.Audit {
+		if record != nil {
+			audit = appendMetadataAudit(audit, record)
+		}
+	}
+
+	publishers := make(map[string]*models.ValidatorListPublisher, len(payload.Publishers))
+	for site, entry := range payload.Publishers {
+		if entry != nil {
+			publishers[site] = entry
+		}
 	}
 
 	f.sourceStateMu.Lock()
 	f.metadataCache = payload.Entries
+	f.metadataAudit = audit
+	f.publishers = publishers
+	f.updatePublisherAlertsMetric()
+	f.updateListExpiryMetrics()
 	f.sourceStateMu.Unlock()
 
 	f.logger.WithFields(logrus.Fields{
@@ -965,6 +1610,9 @@ func (f *Fetcher) loadMetadataCache() {
 }
 
 func (f *Fetcher) persistMetadataCache() error {
+	f.metadataPersistMu.Lock()
+	defer f.metadataPersistMu.Unlock()
+
 	f.sourceStateMu.Lock()
 	payload := validatorMetadataCacheFile{
 		Version: validatorMetadataCacheVersion,
@@ -977,6 +1625,16 @@ func (f *Fetcher) persistMetadataCache() error {
 		copy := *entry
 		payload.Entries[key] = &copy
 	}
+	// Records are never modified once appended, so sharing them is safe.
+	payload.Audit = append([]*models.MetadataAuditRecord(nil), f.metadataAudit...)
+	if len(f.publishers) > 0 {
+		payload.Publishers = make(map[string]*models.ValidatorListPublisher, len(f.publishers))
+		for site, entry := range f.publishers {
+			copy := *entry
+			copy.Rotations = append([]*models.PublisherKeyRotation(nil), entry.Rotations...)
+			payload.Publishers[site] = &copy
+		}
+	}
 	f.sourceStateMu.Unlock()
 
 	data, err := json.MarshalIndent(payload, "", "  ")
@@ -984,14 +1642,7 @@ func (f *Fetcher) persistMetadataCache() error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(f.metadataCachePath), 0o755); err != nil {
-		return err
-	}
-	tmpPath := f.metadataCachePath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
-		return err
-	}
-	return os.Rename(tmpPath, f.metadataCachePath)
+	return validatorMetadataCacheFormat.Write(f.metadataCachePath, data)
 }
 
 func mergeValidators(primary []*models.Validator, secondary []*models.Validator) []*models.Validator {
@@ -1016,8 +1667,8 @@ func mergeValidators(primary []*models.Validator, secondary []*models.Validator)
 func (f *Fetcher) parseValidators(data interface{}) ([]*models.Validator, error) {
 	validators := make([]*models.Validator, 0)
 
-	dataMap, ok := data.(map[string]interface{})
-	if !ok {
+	dataMap := jsonutil.Object(data)
+	if dataMap == nil {
 		return validators, fmt.Errorf("unexpected response format")
 	}
 
@@ -1043,6 +1694,7 @@ func (f *Fetcher) parseValidators(data interface{}) ([]*models.Validator, error)
 		validator, err := f.parseValidator(v)
 		if err != nil {
 			f.logger.WithError(err).Warn("Failed to parse individual validator")
+			f.debugCapture.Capture(debugcapture.SourceValidatorList, err, v)
 			continue
 		}
 		validators = append(validators, validator)
@@ -1053,21 +1705,19 @@ func (f *Fetcher) parseValidators(data interface{}) ([]*models.Validator, error)
 
 // parseValidator converts a raw validator entry to a Validator model
 func (f *Fetcher) parseValidator(raw interface{}) (*models.Validator, error) {
-	rawMap, ok := raw.(map[string]interface{})
-	if !ok {
+	rawMap := jsonutil.Object(raw)
+	if rawMap == nil {
 		return nil, fmt.Errorf("validator entry is not a map")
 	}
 
 	v := &models.Validator{
 		Network:     f.network,
-		LastUpdated: time.Now().Unix(),
+		LastUpdated: f.clock.Now().Unix(),
 		IsActive:    true,
 	}
 
 	// Extract public key (hex string)
-	if pubKey, ok := rawMap["validation_public_key"].(string); ok {
-		v.PublicKey = pubKey
-	}
+	v.PublicKey = jsonutil.String(rawMap, "validation_public_key")
 
 	// Extract domain
 	if domain, ok := rawMap["domain"].(string); ok {
@@ -1080,6 +1730,24 @@ func (f *Fetcher) parseValidator(raw interface{}) (*models.Validator, error) {
 		v.Name = name
 	}
 
+	// The manifest names the current signing key. Lists publish it signed,
+	// so a higher sequence is a key rotation.
+	if encoded := jsonutil.String(rawMap, "manifest"); encoded != "" {
+		if m, err := parseManifest(encoded); err != nil {
+			f.logger.WithError(err).WithField("public_key", v.PublicKey).Debug("Failed to parse validator manifest")
+		} else {
+			if v.PublicKey == "" {
+				v.PublicKey = m.MasterKey
+			}
+			v.SigningKey = m.SigningKey
+			v.ManifestSequence = m.Sequence
+			v.Manifest = encoded
+			if m.Revoked() {
+				v.IsActive = false
+			}
+		}
+	}
+
 	// Extract validator address if available (some lists provide it)
 	if address, ok := rawMap["address"].(string); ok {
 		v.Address = address
@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+func newFuzzFetcher() *Fetcher {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return &Fetcher{network: "mainnet", logger: logger, clock: clock.Real()}
+}
+
+func FuzzParseValidators(f *testing.F) {
+	f.Add([]byte(`{"validators":[{"validation_public_key":"nHBidG3pZK11zQD6kpNDoAhDxH6WLGui6ZxSbUx7LSqLHsgzMPec","domain":"example.com"}]}`))
+	f.Add([]byte(`{"data":[{"validation_public_key":"nHUon2tpyJEHHYGmxqeGu37cvPYHzrMtUNQFVdCgGNvEkjmCpTqK","name":"x","address":"rX"}]}`))
+	f.Add([]byte(`{"validators":[null,1,"x",{"domain":7}]}`))
+	f.Add([]byte(`{"validators":{}}`))
+	f.Add([]byte(`[]`))
+
+	fetcher := newFuzzFetcher()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var payload interface{}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return
+		}
+		validators, err := fetcher.parseValidators(payload)
+		if err == nil && validators == nil {
+			t.Fatal("parseValidators returned nil slice without error")
+		}
+		for _, v := range validators {
+			if v == nil {
+				t.Fatal("parseValidators returned nil validator")
+			}
+		}
+	})
+}
+
+func FuzzDecodeValidatorListBlob(f *testing.F) {
+	blob := base64.StdEncoding.EncodeToString([]byte(`{"sequence":1,"expiration":800000000,"validators":[{"validation_public_key":"ED00","manifest":"JAAAAAFxIe0="}]}`))
+	f.Add(`{"blob":"` + blob + `","manifest":"JAAAAAFxIe0=","signature":"00","version":1}`)
+	f.Add(`{"blob":"bnVsbA=="}`)
+	f.Add(`{"blob":"!!not-base64"}`)
+	f.Add(`{"blob":12}`)
+	f.Add(`{}`)
+
+	fetcher := newFuzzFetcher()
+	f.Fuzz(func(t *testing.T, data string) {
+		var result map[string]interface{}
+		if err := json.Unmarshal([]byte(data), &result); err != nil {
+			return
+		}
+		decoded, err := decodeValidatorListBlob(result)
+		if err != nil {
+			return
+		}
+		if decoded == nil {
+			t.Fatal("decodeValidatorListBlob returned nil map without error")
+		}
+		fetcher.parseValidators(decoded)
+	})
+}
+
+func FuzzParseServerStatusResult(f *testing.F) {
+	f.Add([]byte(`{"result":{"info":{"server_state":"full","peers":21,"complete_ledgers":"32570-90000000","validated_ledger":{"seq":90000000,"age":2}}}}`))
+	f.Add([]byte(`{"result":{"info":{"complete_ledgers":"5-1,x-y,,-"}}}`))
+	f.Add([]byte(`{"result":{"status":"error"}}`))
+	f.Add([]byte(`{"result":null}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var payload interface{}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return
+		}
+		parseServerStatusResult(payload)
+	})
+}
+
+func FuzzParseManifest(f *testing.F) {
+	f.Add(encodeManifest(7, testKey(0xAA), testKey(0xBB), "example.com"))
+	f.Add(encodeManifest(revokedManifestSequence, testKey(0xAA), nil, ""))
+	full, _ := base64.StdEncoding.DecodeString(encodeManifest(7, testKey(0xAA), testKey(0xBB), ""))
+	f.Add(base64.StdEncoding.EncodeToString(full[:30]))
+	f.Add("JAAAAAFxIe0=")
+	f.Add("not base64!")
+
+	f.Fuzz(func(t *testing.T, encoded string) {
+		m, err := parseManifest(encoded)
+		if err != nil {
+			return
+		}
+		if m == nil || m.MasterKey == "" {
+			t.Fatalf("parseManifest returned %+v without a master key", m)
+		}
+		if m.Revoked() && m.SigningKey != "" {
+			t.Fatalf("revoked manifest kept signing key %s", m.SigningKey)
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/xrpltest"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+func TestDiffValidatorsReportsChangedFieldsOnly(t *testing.T) {
+	previous := map[string]*models.Validator{
+		"nA1": {Address: "nA1", Domain: "a.example", City: "Paris", LastUpdated: 100, IsActive: true},
+		"nA2": {Address: "nA2", Domain: "b.example", LastUpdated: 100},
+		"nA3": {Address: "nA3", Domain: "c.example", LastUpdated: 100},
+	}
+	current := map[string]*models.Validator{
+		"nA1": {Address: "nA1", Domain: "a.example", City: "Berlin", LastUpdated: 200, IsActive: true},
+		"nA2": {Address: "nA2", Domain: "b.example", LastUpdated: 200},
+		"nA4": {Address: "nA4", Domain: "d.example", LastUpdated: 200},
+	}
+
+	update := DiffValidators(previous, current)
+
+	if len(update.Upserts) != 2 {
+		t.Fatalf("expected 2 upserts, got %d", len(update.Upserts))
+	}
+	changed := update.Upserts[0]
+	if changed.Address != "nA1" || len(changed.Fields) != 1 || changed.Fields["city"] != "Berlin" {
+		t.Fatalf("expected only city change for nA1, got %#v", changed)
+	}
+	added := update.Upserts[1]
+	if added.Address != "nA4" || added.Fields["domain"] != "d.example" {
+		t.Fatalf("expected full field set for new validator nA4, got %#v", added)
+	}
+	if _, ok := added.Fields["last_updated"]; ok {
+		t.Fatal("did not expect last_updated in upsert fields")
+	}
+	if len(update.Removals) != 1 || update.Removals[0].Address != "nA3" {
+		t.Fatalf("expected nA3 removal, got %#v", update.Removals)
+	}
+}
+
+func TestDiffValidatorsNoChanges(t *testing.T) {
+	validators := map[string]*models.Validator{
+		"nA1": {Address: "nA1", Domain: "a.example", LastUpdated: 100},
+	}
+	refreshed := map[string]*models.Validator{
+		"nA1": {Address: "nA1", Domain: "a.example", LastUpdated: 200},
+	}
+
+	update := DiffValidators(validators, refreshed)
+	if len(update.Upserts) != 0 || len(update.Removals) != 0 {
+		t.Fatalf("expected no changes, got %#v", update)
+	}
+}
+
+func TestDiffValidatorsComparesPeerReachabilityByValue(t *testing.T) {
+	reachable, stillReachable, unreachable := true, true, false
+	previous := map[string]*models.Validator{
+		"nA1": {Address: "nA1", PeerReachable: &reachable},
+		"nA2": {Address: "nA2", PeerReachable: &reachable},
+		"nA3": {Address: "nA3"},
+	}
+	current := map[string]*models.Validator{
+		"nA1": {Address: "nA1", PeerReachable: &stillReachable},
+		"nA2": {Address: "nA2", PeerReachable: &unreachable},
+		"nA3": {Address: "nA3"},
+	}
+
+	update := DiffValidators(previous, current)
+	if len(update.Upserts) != 1 || update.Upserts[0].Address != "nA2" || update.Upserts[0].Fields["peer_reachable"] != false {
+		t.Fatalf("expected only nA2's reachability change, got %#v", update.Upserts)
+	}
+}
+
+func TestUpdatePersistedMetadataRecordsDomainHistory(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "validator-metadata-cache.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+
+	fetcher.updatePersistedMetadata(
+		[]*models.Validator{{Address: "nA1", Domain: "a.example"}},
+		map[string]string{"nA1": DomainSourceValidatorList},
+		nil,
+	)
+	fetcher.updatePersistedMetadata(
+		[]*models.Validator{{Address: "nA1", Domain: "a.example"}},
+		map[string]string{"nA1": DomainSourceValidatorList},
+		nil,
+	)
+	fetcher.updatePersistedMetadata(
+		[]*models.Validator{{Address: "nA1", Domain: "evil.example"}},
+		map[string]string{"nA1": DomainSourceSecondaryRegistry},
+		nil,
+	)
+
+	// The history survives a restart through the metadata cache file.
+	reloaded := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	history, err := reloaded.GetDomainHistory(context.Background(), "nA1")
+	if err != nil {
+		t.Fatalf("GetDomainHistory failed: %v", err)
+	}
+	if history.CurrentDomain != "evil.example" || len(history.Changes) != 2 {
+		t.Fatalf("expected two recorded changes, got %+v", history)
+	}
+	last := history.Changes[1]
+	if last.OldDomain != "a.example" || last.NewDomain != "evil.example" || last.Source != DomainSourceSecondaryRegistry || last.ChangedAt == 0 {
+		t.Fatalf("unexpected change record %+v", last)
+	}
+
+	if _, err := reloaded.GetDomainHistory(context.Background(), "nUnknown"); !errors.Is(err, xrpl.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown validator, got %v", err)
+	}
+}
+
+func TestSecondaryRegistryCooldownFollowsRetryAfter(t *testing.T) {
+	requests := 0
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer registry.Close()
+
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, registry.URL, cachePath, nil, 1, "mainnet", nil, FetcherOptions{Clock: fake})
+	validators := []*models.Validator{{Address: "nA1"}}
+
+	if _, err := fetcher.applySecondaryRegistryDomains(context.Background(), validators, nil); err == nil {
+		t.Fatal("expected the rate-limited registry to fail without a cache")
+	}
+	fake.Advance(119 * time.Second)
+	if _, err := fetcher.applySecondaryRegistryDomains(context.Background(), validators, nil); err == nil {
+		t.Fatal("expected the registry to stay in cooldown")
+	}
+	if requests != 1 {
+		t.Fatalf("expected no request during the cooldown, got %d", requests)
+	}
+
+	fake.Advance(time.Second)
+	fetcher.applySecondaryRegistryDomains(context.Background(), validators, nil)
+	if requests != 2 {
+		t.Fatalf("expected the registry to be requested again after Retry-After, got %d", requests)
+	}
+}
+
+func TestFetchProceedsWithoutValidatorList(t *testing.T) {
+	node := xrpltest.NewServer()
+	defer node.Close()
+	site := httptest.NewServer(http.NotFoundHandler())
+	defer site.Close()
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer registry.Close()
+
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fetcher := NewFetcher(xrpl.NewClient(node.URL(), "", nil), time.Minute, nil, []string{site.URL}, registry.URL, cachePath, nil, 1, "mainnet", nil, FetcherOptions{Clock: fake})
+
+	if fetcher.SourceStatus() != nil {
+		t.Fatal("expected no source status before the first cycle")
+	}
+	if err := fetcher.Fetch(context.Background()); err != nil {
+		t.Fatalf("expected the trusted keys to carry the cycle, got %v", err)
+	}
+	if got := len(fetcher.GetValidators()); got != 2 {
+		t.Fatalf("expected the 2 trusted validators, got %d", got)
+	}
+	status := fetcher.SourceStatus()
+	if len(status.OK) != 2 || status.OK[0] != StageTrustedValidators || status.OK[1] != StageSecondaryRegistry || len(status.Failed) != 1 || status.Failed[0] != StageValidatorList {
+		t.Fatalf("unexpected source status %+v", status)
+	}
+
+	// With no source left the cycle fails and the snapshot is kept.
+	node.SetResult("validators", map[string]interface{}{"error": "noNetwork", "status": "error"})
+	if err := fetcher.Fetch(context.Background()); err == nil {
+		t.Fatal("expected the cycle to fail without any validator source")
+	}
+	if got := len(fetcher.GetValidators()); got != 2 {
+		t.Fatalf("expected the previous snapshot to be kept, got %d validators", got)
+	}
+	if status := fetcher.SourceStatus(); len(status.Failed) != 1 || status.Failed[0] != StageValidatorList {
+		t.Fatalf("expected the status of the kept snapshot, got %+v", status)
+	}
+}
+
+func TestStartDelaysFirstRefreshBySplay(t *testing.T) {
+	// Failing cycles are enough to count refreshes.
+	site := httptest.NewServer(http.NotFoundHandler())
+	defer site.Close()
+
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, []string{site.URL}, "", cachePath, nil, 1, "mainnet", nil, FetcherOptions{
+		Clock:        fake,
+		RefreshSplay: 30 * time.Second,
+	})
+	fetcher.Start(context.Background())
+	defer fetcher.Stop()
+
+	waitForCycles := func(want int64) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for fetcher.FetchStatus().Cycles < want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d fetch cycles, got %d", want, fetcher.FetchStatus().Cycles)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitForCycles(1)
+	fake.BlockUntil(1)
+
+	fake.Advance(time.Minute)
+	if cycles := fetcher.FetchStatus().Cycles; cycles != 1 {
+		t.Fatalf("expected the splay to delay the first refresh, got %d cycles", cycles)
+	}
+	fake.Advance(30 * time.Second)
+	waitForCycles(2)
+
+	// Later refreshes run one interval apart, without the splay.
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+	waitForCycles(3)
+}
+
+func TestSnapshotHashIgnoresLastUpdated(t *testing.T) {
+	snapshot := func(lastUpdated int64, city string) map[string]*models.Validator {
+		return map[string]*models.Validator{
+			"nA1": {Address: "nA1", Domain: "a.example", City: city, LastUpdated: lastUpdated},
+			"nA2": {Address: "nA2", Domain: "b.example", LastUpdated: lastUpdated},
+		}
+	}
+
+	base := SnapshotHash(snapshot(100, "Paris"))
+	if again := SnapshotHash(snapshot(200, "Paris")); again != base {
+		t.Fatal("expected a refreshed last_updated to keep the hash")
+	}
+	if moved := SnapshotHash(snapshot(100, "Lyon")); moved == base {
+		t.Fatal("expected a changed field to change the hash")
+	}
+	fewer := snapshot(100, "Paris")
+	delete(fewer, "nA2")
+	if SnapshotHash(fewer) == base {
+		t.Fatal("expected a removed validator to change the hash")
+	}
+}
@@ -0,0 +1,270 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/geolocation"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// Historical dataset formats accepted by ImportMetadata.
+const (
+	ImportFormatXRPScan = "xrpscan" // xrpscan validator registry export
+	ImportFormatVHS     = "vhs"     // validator history service dump
+)
+
+// importKeys lists, per format, the keys a field may appear under in an
+// exported validator object, in order of preference.
+type importKeys struct {
+	address, domain, name, latitude, longitude, country, city []string
+}
+
+var importFormats = map[string]importKeys{
+	ImportFormatXRPScan: {
+		address:   []string{"master_key", "validation_public_key"},
+		domain:    []string{"domain", "domain_legacy"},
+		name:      []string{"account_name", "name"},
+		latitude:  []string{"latitude", "lat"},
+		longitude: []string{"longitude", "lng", "lon"},
+		country:   []string{"country_code", "country"},
+		city:      []string{"city"},
+	},
+	ImportFormatVHS: {
+		address:   []string{"master_key", "validation_public_key"},
+		domain:    []string{"domain"},
+		name:      []string{"name"},
+		latitude:  []string{"lat", "latitude"},
+		longitude: []string{"long", "longitude"},
+		country:   []string{"country_code", "country"},
+		city:      []string{"city"},
+	},
+}
+
+// ImportSummary counts what ImportMetadata did with a dataset's validators.
+type ImportSummary struct {
+	Read    int `json:"read"`
+	Added   int `json:"added"`   // validators new to the cache
+	Updated int `json:"updated"` // cached validators given a missing domain, name or location
+	Skipped int `json:"skipped"` // no address, nothing usable, or nothing missing
+}
+
+// ImportMetadata seeds the validator metadata cache at cachePath with the
+// domains, names and locations in a historical dataset read from r. It only
+// fills what the cache lacks, so live enrichment always wins; locations
+// that are out of range or in open ocean are ignored. The running service
+// rewrites its cache from memory, so import while it is stopped.
+func ImportMetadata(cachePath, format string, r io.Reader, now time.Time) (*ImportSummary, error) {
+	keys, ok := importFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown import format %q, expected %s or %s", format, ImportFormatXRPScan, ImportFormatVHS)
+	}
+	records, err := decodeImportRecords(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s dataset: %w", format, err)
+	}
+
+	entries := make(map[string]*validatorMetadataEntry)
+	var audit []*models.MetadataAuditRecord
+	var publishers map[string]*models.ValidatorListPublisher
+	data, _, err := validatorMetadataCacheFormat.Load(cachePath)
+	switch {
+	case err == nil:
+		var payload validatorMetadataCacheFile
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, fmt.Errorf("failed to parse validator metadata cache: %w", err)
+		}
+		if payload.Entries != nil {
+			entries = payload.Entries
+		}
+		audit = payload.Audit
+		publishers = payload.Publishers
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	summary := &ImportSummary{}
+	for _, record := range records {
+		summary.Read++
+		imported := keys.entry(record)
+		if imported == nil {
+			summary.Skipped++
+			continue
+		}
+		entry, exists := entries[imported.Address]
+		if !exists || entry == nil {
+			entry = &validatorMetadataEntry{Address: imported.Address}
+		}
+		before := *entry
+		if !fillImportedMetadata(entry, imported, now.Unix()) {
+			summary.Skipped++
+			continue
+		}
+		for _, record := range importAuditRecords(&before, entry, !exists, now.Unix()) {
+			audit = appendMetadataAudit(audit, record)
+		}
+		entries[imported.Address] = entry
+		if exists {
+			summary.Updated++
+		} else {
+			summary.Added++
+		}
+	}
+
+	if summary.Added+summary.Updated == 0 {
+		return summary, nil
+	}
+	data, err = json.MarshalIndent(validatorMetadataCacheFile{Version: validatorMetadataCacheVersion, Entries: entries, Audit: audit, Publishers: publishers}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := validatorMetadataCacheFormat.Write(cachePath, data); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// decodeImportRecords accepts a JSON array of validator objects or an
+// object holding one under "validators" or "data".
+func decodeImportRecords(r io.Reader) ([]map[string]interface{}, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal(raw, &records); err == nil {
+		return records, nil
+	}
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, fmt.Errorf("expected an array of validators or an object with a validators array")
+	}
+	for _, key := range []string{"validators", "data"} {
+		if list, ok := envelope[key]; ok {
+			if err := json.Unmarshal(list, &records); err != nil {
+				return nil, fmt.Errorf("invalid %s array: %w", key, err)
+			}
+			return records, nil
+		}
+	}
+	return nil, fmt.Errorf("expected an array of validators or an object with a validators array")
+}
+
+// entry extracts a metadata entry from one exported validator object, or
+// nil if it has no address or nothing usable.
+func (k importKeys) entry(record map[string]interface{}) *validatorMetadataEntry {
+	entry := &validatorMetadataEntry{
+		Address: importString(record, k.address),
+		Domain:  strings.ToLower(strings.TrimSuffix(importString(record, k.domain), ".")),
+		Name:    importString(record, k.name),
+		City:    importString(record, k.city),
+	}
+	if entry.Address == "" {
+		return nil
+	}
+	if country := strings.ToUpper(importString(record, k.country)); len(country) == 2 {
+		entry.CountryCode = country
+	}
+	lat, latOK := importFloat(record, k.latitude)
+	lon, lonOK := importFloat(record, k.longitude)
+	if latOK && lonOK && (lat != 0 || lon != 0) && geolocation.ValidCoordinates(lat, lon) && geolocation.OnLand(lat, lon) {
+		entry.Latitude = lat
+		entry.Longitude = lon
+	}
+	if entry.Domain == "" && entry.Name == "" && entry.Latitude == 0 && entry.Longitude == 0 {
+		return nil
+	}
+	return entry
+}
+
+// fillImportedMetadata copies the fields entry lacks from imported and
+// reports whether it changed anything.
+func fillImportedMetadata(entry, imported *validatorMetadataEntry, now int64) bool {
+	changed := false
+	if entry.Domain == "" && imported.Domain != "" {
+		entry.Domain = imported.Domain
+		entry.DomainHistory = append(entry.DomainHistory, &models.DomainChange{
+			NewDomain: imported.Domain,
+			Source:    DomainSourceImport,
+			ChangedAt: now,
+		})
+		changed = true
+	}
+	if entry.Name == "" && imported.Name != "" {
+		entry.Name = imported.Name
+		changed = true
+	}
+	if entry.Latitude == 0 && entry.Longitude == 0 && (imported.Latitude != 0 || imported.Longitude != 0) {
+		entry.Latitude = imported.Latitude
+		entry.Longitude = imported.Longitude
+		entry.CountryCode = imported.CountryCode
+		entry.City = imported.City
+		if entry.CountryCode == "" {
+			entry.CountryCode = "XX"
+		}
+		if entry.City == "" {
+			entry.City = "Unknown"
+		}
+		changed = true
+	}
+	return changed
+}
+
+// importAuditRecords returns the audit records of an import that turned
+// before into after.
+func importAuditRecords(before, after *validatorMetadataEntry, created bool, now int64) []*models.MetadataAuditRecord {
+	var records []*models.MetadataAuditRecord
+	add := func(field, oldValue, newValue string) {
+		records = append(records, &models.MetadataAuditRecord{
+			Timestamp: now,
+			Address:   after.Address,
+			Field:     field,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			Source:    DomainSourceImport,
+		})
+	}
+	if created {
+		add(AuditFieldEntry, "", after.Address)
+	}
+	if before.Domain != after.Domain {
+		add(AuditFieldDomain, before.Domain, after.Domain)
+	}
+	if before.Name != after.Name {
+		add(AuditFieldName, before.Name, after.Name)
+	}
+	oldLocation := formatAuditLocation(before.Latitude, before.Longitude, before.CountryCode, before.City, before.Approximate)
+	newLocation := formatAuditLocation(after.Latitude, after.Longitude, after.CountryCode, after.City, after.Approximate)
+	if oldLocation != newLocation {
+		add(AuditFieldLocation, oldLocation, newLocation)
+	}
+	return records
+}
+
+func importString(record map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		if value, ok := record[key].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func importFloat(record map[string]interface{}, keys []string) (float64, bool) {
+	for _, key := range keys {
+		switch value := record[key].(type) {
+		case float64:
+			return value, true
+		case string:
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				return parsed, true
+			}
+		}
+	}
+	return 0, false
+}
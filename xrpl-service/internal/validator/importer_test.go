@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readMetadataCache(t *testing.T, path string) map[string]*validatorMetadataEntry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	var payload validatorMetadataCacheFile
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("failed to parse cache: %v", err)
+	}
+	return payload.Entries
+}
+
+func TestImportMetadataFillsOnlyMissingFields(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	existing := validatorMetadataCacheFile{Version: validatorMetadataCacheVersion, Entries: map[string]*validatorMetadataEntry{
+		"nLive":     {Address: "nLive", Domain: "live.example", Latitude: 52.52, Longitude: 13.40, CountryCode: "DE", City: "Berlin", LastSeenAt: 100},
+		"nUnmapped": {Address: "nUnmapped", Domain: "unmapped.example", CountryCode: "XX", City: "Unknown", LastSeenAt: 100},
+	}}
+	data, _ := json.Marshal(existing)
+	if err := os.WriteFile(cachePath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dataset := `[
+		{"master_key": "nLive", "domain": "other.example", "latitude": 40.71, "longitude": -74.0},
+		{"master_key": "nUnmapped", "domain": "other.example", "lat": "35.68", "lng": "139.69", "country": "jp", "city": "Tokyo"},
+		{"master_key": "nNew", "domain": "New.Example.", "account_name": "New Validator"},
+		{"master_key": "nOcean", "latitude": -40, "longitude": -130},
+		{"domain": "no-address.example"}
+	]`
+	summary, err := ImportMetadata(cachePath, ImportFormatXRPScan, strings.NewReader(dataset), time.Unix(1_700_000_000, 0))
+	if err != nil {
+		t.Fatalf("ImportMetadata failed: %v", err)
+	}
+	if summary.Read != 5 || summary.Added != 1 || summary.Updated != 1 || summary.Skipped != 3 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+
+	entries := readMetadataCache(t, cachePath)
+	if live := entries["nLive"]; live.Domain != "live.example" || live.City != "Berlin" {
+		t.Fatalf("expected live metadata to be kept, got %+v", live)
+	}
+	if unmapped := entries["nUnmapped"]; unmapped.Domain != "unmapped.example" || unmapped.Latitude != 35.68 || unmapped.CountryCode != "JP" || unmapped.City != "Tokyo" {
+		t.Fatalf("expected the missing location to be filled, got %+v", unmapped)
+	}
+	added := entries["nNew"]
+	if added == nil || added.Domain != "new.example" || added.Name != "New Validator" || added.LastSeenAt != 0 {
+		t.Fatalf("unexpected imported validator %+v", added)
+	}
+	if len(added.DomainHistory) != 1 || added.DomainHistory[0].Source != DomainSourceImport {
+		t.Fatalf("expected the imported domain in the audit trail, got %+v", added.DomainHistory)
+	}
+	if _, ok := entries["nOcean"]; ok {
+		t.Fatal("expected a validator with only an ocean location to be skipped")
+	}
+}
+
+func TestImportMetadataVHSDump(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	dataset := `{"result": "success", "count": 1, "validators": [{"validation_public_key": "nVHS", "domain": "vhs.example", "lat": 51.5, "long": -0.12, "country": "GB"}]}`
+	summary, err := ImportMetadata(cachePath, ImportFormatVHS, strings.NewReader(dataset), time.Now())
+	if err != nil {
+		t.Fatalf("ImportMetadata failed: %v", err)
+	}
+	if summary.Added != 1 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if entry := readMetadataCache(t, cachePath)["nVHS"]; entry.Longitude != -0.12 || entry.CountryCode != "GB" || entry.City != "Unknown" {
+		t.Fatalf("unexpected imported validator %+v", entry)
+	}
+
+	// The service picks the seeded metadata up when it starts.
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	if history, err := fetcher.GetDomainHistory(t.Context(), "nVHS"); err != nil || history.CurrentDomain != "vhs.example" {
+		t.Fatalf("expected the fetcher to load the import, got %+v (%v)", history, err)
+	}
+
+	if _, err := ImportMetadata(cachePath, "csv", strings.NewReader("[]"), time.Now()); err == nil {
+		t.Fatal("expected an unknown format to fail")
+	}
+	if _, err := ImportMetadata(cachePath, ImportFormatVHS, strings.NewReader(`{"result":"error"}`), time.Now()); err == nil {
+		t.Fatal("expected a dump without validators to fail")
+	}
+}
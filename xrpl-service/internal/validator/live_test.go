@@ -0,0 +1,104 @@
+//go:build live
+
+package validator
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+// Contract tests against public infrastructure. Run with:
+//
+//	go test -tags live ./internal/validator/
+//
+// Endpoints can be overridden with LIVE_XRPL_JSON_RPC_URL and LIVE_VALIDATOR_LIST_SITE.
+
+func liveEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func newLiveFetcher(t *testing.T) *Fetcher {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	rpcURL := liveEnv("LIVE_XRPL_JSON_RPC_URL", "https://xrplcluster.com")
+	return NewFetcher(
+		xrpl.NewClient(rpcURL, "", logger),
+		time.Minute,
+		nil,
+		[]string{liveEnv("LIVE_VALIDATOR_LIST_SITE", "https://vl.ripple.com")},
+		"https://api.xrpscan.com/api/v1/validatorregistry",
+		filepath.Join(t.TempDir(), "validator-metadata-cache.json"),
+		[]string{rpcURL},
+		2,
+		"mainnet",
+		logger,
+	)
+}
+
+func TestLiveValidatorListShape(t *testing.T) {
+	fetcher := newLiveFetcher(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := fetcher.fetchValidatorList(ctx)
+	if err != nil {
+		t.Fatalf("UNL fetch failed: %v", err)
+	}
+	validators, err := fetcher.parseValidators(result)
+	if err != nil {
+		t.Fatalf("UNL parse failed; response shape may have changed: %v", err)
+	}
+	if len(validators) < 10 {
+		t.Fatalf("expected a populated UNL, got %d validators", len(validators))
+	}
+	for _, v := range validators {
+		if v.PublicKey == "" || v.Address == "" {
+			t.Fatalf("UNL entry missing validation_public_key: %+v", v)
+		}
+	}
+}
+
+func TestLiveValidatorsCommandShape(t *testing.T) {
+	fetcher := newLiveFetcher(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	validators, trusted, err := fetcher.fetchTrustedValidatorsFromXRPL(ctx)
+	if err != nil {
+		t.Fatalf("validators command parse failed; response shape may have changed: %v", err)
+	}
+	if len(validators) == 0 || len(trusted) == 0 {
+		t.Fatal("expected trusted or publisher list keys from validators command")
+	}
+}
+
+func TestLiveServerInfoShape(t *testing.T) {
+	fetcher := newLiveFetcher(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	status, err := fetcher.GetServerStatus(ctx)
+	if err != nil {
+		t.Fatalf("server_info fetch failed: %v", err)
+	}
+	if status.ServerState == "" {
+		t.Fatal("server_info missing server_state")
+	}
+	if status.LedgerIndex == 0 {
+		t.Fatal("server_info missing validated_ledger.seq")
+	}
+	if status.CompleteLedgerSpan == 0 {
+		t.Fatalf("server_info complete_ledgers did not parse: %q", status.CompleteLedgers)
+	}
+}
@@ -0,0 +1,167 @@
+package validator
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// revokedManifestSequence is the sequence of a manifest that permanently
+// revokes its master key.
+const revokedManifestSequence = 0xFFFFFFFF
+
+// manifest is the part of a validator manifest the fetcher uses: which
+// ephemeral signing key a master key delegates to, as of which sequence.
+// Neither the manifest's signatures nor those of the validator lists that
+// carry it are checked, so manifests are unauthenticated.
+type manifest struct {
+	MasterKey  string // hex, upper case
+	SigningKey string // hex, upper case; empty when revoked
+	Sequence   uint32
+	Domain     string
+}
+
+// Revoked reports whether the manifest revokes its master key.
+func (m *manifest) Revoked() bool {
+	return m.Sequence == revokedManifestSequence
+}
+
+// STObject field codes used by manifests.
+const (
+	stTypeUInt16  = 1
+	stTypeUInt32  = 2
+	stTypeUInt64  = 3
+	stTypeHash128 = 4
+	stTypeHash256 = 5
+	stTypeBlob    = 7
+
+	fieldSequence      = 4 // UInt32
+	fieldPublicKey     = 1 // Blob
+	fieldSigningPubKey = 3 // Blob
+	fieldDomain        = 7 // Blob
+)
+
+// stFixedSizes are the sizes of fixed-width STObject types that manifests
+// may carry but the fetcher skips.
+var stFixedSizes = map[int]int{stTypeUInt16: 2, stTypeUInt64: 8, stTypeHash128: 16, stTypeHash256: 32}
+
+var errTruncatedManifest = errors.New("truncated manifest")
+
+// parseManifest decodes a base64 serialized manifest.
+func parseManifest(encoded string) (*manifest, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest encoding: %w", err)
+	}
+
+	m := &manifest{}
+	hasSequence := false
+	for len(data) > 0 {
+		typeCode, fieldCode, rest, err := readFieldID(data)
+		if err != nil {
+			return nil, err
+		}
+		data = rest
+
+		switch typeCode {
+		case stTypeBlob:
+			value, rest, err := readVL(data)
+			if err != nil {
+				return nil, err
+			}
+			data = rest
+			switch fieldCode {
+			case fieldPublicKey:
+				m.MasterKey = strings.ToUpper(hex.EncodeToString(value))
+			case fieldSigningPubKey:
+				m.SigningKey = strings.ToUpper(hex.EncodeToString(value))
+			case fieldDomain:
+				m.Domain = string(value)
+			}
+		case stTypeUInt32:
+			if len(data) < 4 {
+				return nil, errTruncatedManifest
+			}
+			if fieldCode == fieldSequence {
+				m.Sequence = binary.BigEndian.Uint32(data)
+				hasSequence = true
+			}
+			data = data[4:]
+		default:
+			size, ok := stFixedSizes[typeCode]
+			if !ok {
+				return nil, fmt.Errorf("unsupported manifest field type %d", typeCode)
+			}
+			if len(data) < size {
+				return nil, errTruncatedManifest
+			}
+			data = data[size:]
+		}
+	}
+
+	if m.MasterKey == "" || !hasSequence {
+		return nil, errors.New("manifest is missing its master key or sequence")
+	}
+	if m.Revoked() {
+		m.SigningKey = ""
+	}
+	return m, nil
+}
+
+// readFieldID decodes an STObject field header of one to three bytes.
+func readFieldID(data []byte) (typeCode, fieldCode int, rest []byte, err error) {
+	if len(data) < 1 {
+		return 0, 0, nil, errTruncatedManifest
+	}
+	typeCode = int(data[0] >> 4)
+	fieldCode = int(data[0] & 0x0F)
+	data = data[1:]
+	if typeCode == 0 {
+		if len(data) < 1 {
+			return 0, 0, nil, errTruncatedManifest
+		}
+		typeCode = int(data[0])
+		data = data[1:]
+	}
+	if fieldCode == 0 {
+		if len(data) < 1 {
+			return 0, 0, nil, errTruncatedManifest
+		}
+		fieldCode = int(data[0])
+		data = data[1:]
+	}
+	return typeCode, fieldCode, data, nil
+}
+
+// readVL decodes a variable length prefixed value.
+func readVL(data []byte) (value, rest []byte, err error) {
+	if len(data) < 1 {
+		return nil, nil, errTruncatedManifest
+	}
+	length := int(data[0])
+	data = data[1:]
+	switch {
+	case length <= 192:
+	case length <= 240:
+		if len(data) < 1 {
+			return nil, nil, errTruncatedManifest
+		}
+		length = 193 + (length-193)*256 + int(data[0])
+		data = data[1:]
+	case length <= 254:
+		if len(data) < 2 {
+			return nil, nil, errTruncatedManifest
+		}
+		length = 12481 + (length-241)*65536 + int(data[0])*256 + int(data[1])
+		data = data[2:]
+	default:
+		return nil, nil, errors.New("invalid manifest length prefix")
+	}
+	if len(data) < length {
+		return nil, nil, errTruncatedManifest
+	}
+	return data[:length], data[length:], nil
+}
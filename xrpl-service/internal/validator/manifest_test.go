@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+)
+
+// encodeManifest serializes a manifest the way validators publish it, with
+// placeholder signatures.
+func encodeManifest(sequence uint32, master, signing []byte, domain string) string {
+	var buf bytes.Buffer
+	buf.WriteByte(0x24) // sfSequence
+	binary.Write(&buf, binary.BigEndian, sequence)
+	buf.WriteByte(0x71) // sfPublicKey
+	buf.WriteByte(byte(len(master)))
+	buf.Write(master)
+	if signing != nil {
+		buf.WriteByte(0x73) // sfSigningPubKey
+		buf.WriteByte(byte(len(signing)))
+		buf.Write(signing)
+	}
+	buf.WriteByte(0x76) // sfSignature
+	buf.WriteByte(64)
+	buf.Write(make([]byte, 64))
+	if domain != "" {
+		buf.WriteByte(0x77) // sfDomain
+		buf.WriteByte(byte(len(domain)))
+		buf.WriteString(domain)
+	}
+	buf.Write([]byte{0x70, 0x12}) // sfMasterSignature
+	buf.WriteByte(64)
+	buf.Write(make([]byte, 64))
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func testKey(b byte) []byte {
+	return append([]byte{0xED}, bytes.Repeat([]byte{b}, 32)...)
+}
+
+func TestParseManifest(t *testing.T) {
+	m, err := parseManifest(encodeManifest(7, testKey(0xAA), testKey(0xBB), "example.com"))
+	if err != nil {
+		t.Fatalf("parseManifest failed: %v", err)
+	}
+	if m.Sequence != 7 || m.Domain != "example.com" || m.Revoked() {
+		t.Fatalf("unexpected manifest %+v", m)
+	}
+	if m.MasterKey != "ED"+string(bytes.Repeat([]byte("AA"), 32)) || m.SigningKey != "ED"+string(bytes.Repeat([]byte("BB"), 32)) {
+		t.Fatalf("unexpected keys %s %s", m.MasterKey, m.SigningKey)
+	}
+
+	revoked, err := parseManifest(encodeManifest(revokedManifestSequence, testKey(0xAA), nil, ""))
+	if err != nil || !revoked.Revoked() || revoked.SigningKey != "" {
+		t.Fatalf("expected a revocation, got %+v (%v)", revoked, err)
+	}
+
+	encoded := encodeManifest(7, testKey(0xAA), testKey(0xBB), "")
+	raw, _ := base64.StdEncoding.DecodeString(encoded)
+	if _, err := parseManifest(base64.StdEncoding.EncodeToString(raw[:30])); err == nil {
+		t.Fatal("expected a truncated manifest to fail")
+	}
+	if _, err := parseManifest("not base64!"); err == nil {
+		t.Fatal("expected invalid base64 to fail")
+	}
+}
@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+// Operator note limits. The oldest notes beyond maxValidatorNotes are
+// dropped first.
+const (
+	maxValidatorNotes   = 100
+	maxNoteTextLength   = 2000
+	maxNoteTags         = 10
+	maxNoteTagLength    = 32
+	maxNoteAuthorLength = 64
+)
+
+// ErrInvalidNote is returned by AddNote for a note that fails validation.
+var ErrInvalidNote = errors.New("invalid validator note")
+
+// AddNote attaches an operator note to a validator, looked up by its master
+// key or any signing key it has used, and persists it with the validator
+// metadata. It returns the stored note, or xrpl.ErrNotFound if the validator
+// has never been seen.
+func (f *Fetcher) AddNote(ctx context.Context, key string, note models.ValidatorNote) (*models.ValidatorNote, error) {
+	note.Text = strings.TrimSpace(note.Text)
+	note.Author = strings.TrimSpace(note.Author)
+	if note.Text == "" || len(note.Text) > maxNoteTextLength {
+		return nil, fmt.Errorf("%w: text must be 1 to %d characters", ErrInvalidNote, maxNoteTextLength)
+	}
+	if len(note.Author) > maxNoteAuthorLength {
+		return nil, fmt.Errorf("%w: author must be at most %d characters", ErrInvalidNote, maxNoteAuthorLength)
+	}
+	tags := make([]string, 0, len(note.Tags))
+	for _, tag := range note.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if len(tag) > maxNoteTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", ErrInvalidNote, tag, maxNoteTagLength)
+		}
+		tags = append(tags, tag)
+	}
+	if len(tags) > maxNoteTags {
+		return nil, fmt.Errorf("%w: at most %d tags", ErrInvalidNote, maxNoteTags)
+	}
+	note.Tags = nil
+	if len(tags) > 0 {
+		note.Tags = tags
+	}
+	note.CreatedAt = f.clock.Now().UnixMilli()
+
+	f.sourceStateMu.Lock()
+	entry := f.metadataEntryForKey(key)
+	if entry == nil {
+		f.sourceStateMu.Unlock()
+		return nil, fmt.Errorf("validator %s: %w", key, xrpl.ErrNotFound)
+	}
+	stored := note
+	entry.Notes = append(entry.Notes, &stored)
+	f.auditMetadata(entry.Address, AuditFieldNote, "", note.Text, AuditSourceAdmin, note.CreatedAt/1000)
+	if len(entry.Notes) > maxValidatorNotes {
+		entry.Notes = entry.Notes[len(entry.Notes)-maxValidatorNotes:]
+	}
+	address := entry.Address
+	f.sourceStateMu.Unlock()
+
+	if err := f.persistMetadataCache(); err != nil {
+		f.logger.WithError(err).Warn("Failed to persist validator metadata cache")
+	}
+	f.logger.WithFields(logrus.Fields{
+		"address": address,
+		"author":  note.Author,
+		"tags":    note.Tags,
+	}).Info("Validator note added")
+	return &note, nil
+}
+
+// GetNotes returns the operator notes of a validator, looked up by its
+// master key or any signing key it has used, or xrpl.ErrNotFound if it has
+// never been seen.
+func (f *Fetcher) GetNotes(ctx context.Context, key string) (*models.ValidatorNotes, error) {
+	f.sourceStateMu.Lock()
+	defer f.sourceStateMu.Unlock()
+
+	entry := f.metadataEntryForKey(key)
+	if entry == nil {
+		return nil, fmt.Errorf("validator %s: %w", key, xrpl.ErrNotFound)
+	}
+	notes := &models.ValidatorNotes{
+		Address: entry.Address,
+		Notes:   make([]*models.ValidatorNote, 0, len(entry.Notes)),
+	}
+	for _, note := range entry.Notes {
+		copy := *note
+		copy.Tags = append([]string(nil), note.Tags...)
+		notes.Notes = append(notes.Notes, &copy)
+	}
+	return notes, nil
+}
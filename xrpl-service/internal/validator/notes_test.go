@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+func TestAddNotePersistsWithMetadata(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	if _, err := fetcher.AddNote(context.Background(), "nA1", models.ValidatorNote{Text: "unseen"}); !errors.Is(err, xrpl.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unseen validator, got %v", err)
+	}
+
+	fetcher.updatePersistedMetadata([]*models.Validator{{Address: "nA1", Domain: "a.example", SigningKey: "n9Key", ManifestSequence: 1}}, nil, nil)
+	note, err := fetcher.AddNote(context.Background(), "n9Key", models.ValidatorNote{
+		Text:   " contacted about domain mismatch ",
+		Tags:   []string{"Outreach", " "},
+		Author: "ops",
+	})
+	if err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+	if note.Text != "contacted about domain mismatch" || !reflect.DeepEqual(note.Tags, []string{"outreach"}) || note.CreatedAt == 0 {
+		t.Fatalf("unexpected note %+v", note)
+	}
+	if _, err := fetcher.AddNote(context.Background(), "nA1", models.ValidatorNote{Text: "  "}); !errors.Is(err, ErrInvalidNote) {
+		t.Fatalf("expected ErrInvalidNote for an empty note, got %v", err)
+	}
+
+	// Notes survive later fetch cycles and a restart.
+	fetcher.updatePersistedMetadata([]*models.Validator{{Address: "nA1", Domain: "b.example", SigningKey: "n9Key", ManifestSequence: 1}}, nil, nil)
+	reloaded := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	notes, err := reloaded.GetNotes(context.Background(), "nA1")
+	if err != nil {
+		t.Fatalf("GetNotes failed: %v", err)
+	}
+	if notes.Address != "nA1" || len(notes.Notes) != 1 || !reflect.DeepEqual(notes.Notes[0], note) {
+		t.Fatalf("unexpected notes %+v", notes)
+	}
+}
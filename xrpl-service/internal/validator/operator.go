@@ -0,0 +1,228 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"golang.org/x/net/publicsuffix"
+)
+
+// ASNProvider looks up the autonomous system number hosting a domain, 0 if
+// unknown. It is implemented by geolocation.Resolver.
+type ASNProvider interface {
+	ResolveDomainASN(domain string) (uint, error)
+}
+
+// sharedHostingASNs are cloud, CDN and hosting networks used by many
+// unrelated operators. Sharing one of them does not group validators.
+var sharedHostingASNs = map[uint]struct{}{
+	13335:  {}, // Cloudflare
+	14061:  {}, // DigitalOcean
+	14618:  {}, // Amazon
+	15169:  {}, // Google
+	16276:  {}, // OVH
+	16509:  {}, // Amazon
+	20473:  {}, // Vultr
+	20940:  {}, // Akamai
+	24940:  {}, // Hetzner
+	31898:  {}, // Oracle Cloud
+	36459:  {}, // GitHub
+	45102:  {}, // Alibaba Cloud
+	51167:  {}, // Contabo
+	54113:  {}, // Fastly
+	63949:  {}, // Linode
+	8075:   {}, // Microsoft
+	396982: {}, // Google Cloud
+}
+
+// operatorMinShare is the share of validators an operator coalition must
+// exceed to count toward the Nakamoto coefficient.
+const operatorMinShare = 0.2
+
+// domainApex returns the registrable domain of domain, e.g. example.co.uk
+// for validator.example.co.uk.
+func domainApex(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if domain == "" {
+		return ""
+	}
+	if apex, err := publicsuffix.EffectiveTLDPlusOne(domain); err == nil {
+		return apex
+	}
+	return domain
+}
+
+// assignOperators groups validators that share a domain apex, secondary
+// registry owner or non-hosting AS number, and sets each validator's
+// Operator to its group's ID.
+func (f *Fetcher) assignOperators(validators []*models.Validator) {
+	owners := make(map[string]string)
+	if entries, ok := f.getSecondaryRegistryCache(true); ok {
+		for _, entry := range entries {
+			if owner := strings.ToLower(strings.TrimSpace(entry.Owner)); owner != "" {
+				owners[entry.MasterKey] = owner
+			}
+		}
+	}
+
+	parent := make([]int, len(validators))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	firstByKey := make(map[string]int)
+	link := func(i int, key string) {
+		if first, ok := firstByKey[key]; ok {
+			parent[find(i)] = find(first)
+			return
+		}
+		firstByKey[key] = i
+	}
+
+	apexes := make([]string, len(validators))
+	asns := make([]uint, len(validators))
+	for i, v := range validators {
+		if v == nil {
+			continue
+		}
+		if apex := domainApex(v.Domain); apex != "" {
+			apexes[i] = apex
+			link(i, "domain:"+apex)
+		}
+		if owner := owners[v.Address]; owner != "" {
+			link(i, "owner:"+owner)
+		}
+		if f.asnProvider != nil && v.Domain != "" {
+			asn, err := f.asnProvider.ResolveDomainASN(v.Domain)
+			if err != nil {
+				f.logger.WithError(err).WithField("domain", v.Domain).Debug("Failed to resolve validator domain ASN")
+			}
+			if _, shared := sharedHostingASNs[asn]; asn != 0 && !shared {
+				asns[i] = asn
+				link(i, fmt.Sprintf("asn:%d", asn))
+			}
+		}
+	}
+
+	// Pick each group's ID by preference: domain apex, owner, AS number.
+	ids := make(map[int]string)
+	ranks := make(map[int]int)
+	for i, v := range validators {
+		if v == nil {
+			continue
+		}
+		root := find(i)
+		candidates := []string{apexes[i], owners[v.Address], ""}
+		if asns[i] != 0 {
+			candidates[2] = fmt.Sprintf("AS%d", asns[i])
+		}
+		for rank, candidate := range candidates {
+			if candidate == "" {
+				continue
+			}
+			current, ok := ids[root]
+			if !ok || rank < ranks[root] || (rank == ranks[root] && candidate < current) {
+				ids[root] = candidate
+				ranks[root] = rank
+			}
+			break
+		}
+	}
+	for i, v := range validators {
+		if v == nil {
+			continue
+		}
+		if id, ok := ids[find(i)]; ok {
+			v.Operator = id
+		} else {
+			v.Operator = v.Address
+		}
+	}
+}
+
+// SummarizeOperators aggregates validators by their Operator, largest
+// operators first.
+func SummarizeOperators(validators []*models.Validator) *models.OperatorSummary {
+	byID := make(map[string]*models.Operator)
+	domains := make(map[string]map[string]struct{})
+	countries := make(map[string]map[string]struct{})
+	total := 0
+	for _, v := range validators {
+		if v == nil {
+			continue
+		}
+		total++
+		id := v.Operator
+		if id == "" {
+			id = v.Address
+		}
+		operator, ok := byID[id]
+		if !ok {
+			operator = &models.Operator{ID: id}
+			byID[id] = operator
+			domains[id] = make(map[string]struct{})
+			countries[id] = make(map[string]struct{})
+		}
+		operator.ValidatorCount++
+		operator.Validators = append(operator.Validators, v.Address)
+		if v.Domain != "" {
+			domains[id][v.Domain] = struct{}{}
+		}
+		if v.CountryCode != "" && v.CountryCode != "XX" {
+			countries[id][v.CountryCode] = struct{}{}
+		}
+	}
+
+	summary := &models.OperatorSummary{
+		ValidatorCount: total,
+		OperatorCount:  len(byID),
+		Operators:      make([]*models.Operator, 0, len(byID)),
+	}
+	for id, operator := range byID {
+		operator.Share = float64(operator.ValidatorCount) / float64(total)
+		sort.Strings(operator.Validators)
+		operator.Domains = sortedKeys(domains[id])
+		operator.Countries = sortedKeys(countries[id])
+		summary.Operators = append(summary.Operators, operator)
+	}
+	sort.Slice(summary.Operators, func(i, j int) bool {
+		a, b := summary.Operators[i], summary.Operators[j]
+		if a.ValidatorCount != b.ValidatorCount {
+			return a.ValidatorCount > b.ValidatorCount
+		}
+		return a.ID < b.ID
+	})
+
+	if len(summary.Operators) > 0 {
+		summary.TopOperatorShare = summary.Operators[0].Share
+	}
+	covered := 0
+	for _, operator := range summary.Operators {
+		covered += operator.ValidatorCount
+		summary.NakamotoCoefficient++
+		if float64(covered) > operatorMinShare*float64(total) {
+			break
+		}
+	}
+	return summary
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// staticASNs maps domains to AS numbers.
+type staticASNs map[string]uint
+
+func (s staticASNs) ResolveDomainASN(domain string) (uint, error) {
+	return s[domain], nil
+}
+
+func TestAssignOperatorsGroupsByApexOwnerAndASN(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil, FetcherOptions{
+		ASNProvider: staticASNs{
+			"v1.example.co.uk": 64500,
+			"other.example":    64500,
+			"cloud-a.example":  16509,
+			"cloud-b.example":  16509,
+		},
+	})
+	fetcher.setSecondaryRegistryCache([]secondaryRegistryEntry{
+		{MasterKey: "nD4", Owner: "Acme Labs"},
+		{MasterKey: "nE5", Owner: "acme labs"},
+	})
+
+	validators := []*models.Validator{
+		{Address: "nA1", Domain: "v1.example.co.uk"},
+		{Address: "nB2", Domain: "v2.Example.co.uk"},
+		{Address: "nC3", Domain: "other.example"},
+		{Address: "nD4"},
+		{Address: "nE5"},
+		{Address: "nF6", Domain: "cloud-a.example"},
+		{Address: "nG7", Domain: "cloud-b.example"},
+		{Address: "nH8"},
+	}
+	fetcher.assignOperators(validators)
+
+	want := map[string]string{
+		"nA1": "example.co.uk", // same apex as nB2, same ASN as nC3
+		"nB2": "example.co.uk",
+		"nC3": "example.co.uk",
+		"nD4": "acme labs",
+		"nE5": "acme labs",
+		"nF6": "cloud-a.example", // shared hosting ASN does not group
+		"nG7": "cloud-b.example",
+		"nH8": "nH8",
+	}
+	for _, v := range validators {
+		if v.Operator != want[v.Address] {
+			t.Errorf("%s: expected operator %q, got %q", v.Address, want[v.Address], v.Operator)
+		}
+	}
+}
+
+func TestSummarizeOperators(t *testing.T) {
+	validators := []*models.Validator{
+		{Address: "nA1", Domain: "a.example", CountryCode: "DE", Operator: "example"},
+		{Address: "nA2", Domain: "b.example", CountryCode: "US", Operator: "example"},
+		{Address: "nA3", Domain: "b.example", CountryCode: "XX", Operator: "example"},
+		{Address: "nB1", CountryCode: "FR", Operator: "other"},
+		{Address: "nC1"},
+	}
+	summary := SummarizeOperators(validators)
+
+	if summary.ValidatorCount != 5 || summary.OperatorCount != 3 {
+		t.Fatalf("expected 5 validators in 3 operators, got %+v", summary)
+	}
+	top := summary.Operators[0]
+	if top.ID != "example" || top.ValidatorCount != 3 || top.Share != 0.6 || summary.TopOperatorShare != 0.6 {
+		t.Fatalf("unexpected top operator %+v", top)
+	}
+	if len(top.Domains) != 2 || len(top.Countries) != 2 || top.Countries[0] != "DE" {
+		t.Fatalf("expected distinct domains and mapped countries, got %+v", top)
+	}
+	if summary.Operators[1].ID != "nC1" {
+		t.Fatalf("expected a validator without operator to stand alone, got %+v", summary.Operators[1])
+	}
+	if summary.NakamotoCoefficient != 1 {
+		t.Fatalf("expected one operator to exceed 20%%, got %d", summary.NakamotoCoefficient)
+	}
+}
@@ -0,0 +1,156 @@
+package validator
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+const (
+	// maxPeerProbesPerCycle bounds the domains probed by one fetch cycle;
+	// the rest are picked up by later cycles.
+	maxPeerProbesPerCycle = 8
+
+	// peerProbeConcurrency bounds the domains probed at once.
+	peerProbeConcurrency = 4
+
+	// peerProbeTimeout bounds the DNS lookup and each connection attempt.
+	peerProbeTimeout = 3 * time.Second
+)
+
+// peerProbePorts are the standard rippled peer ports, tried in order.
+var peerProbePorts = []int{2459, 51235}
+
+// applyPeerReachability sets whether the peer port of each validator's
+// domain accepts TCP connections. Only domains resolving to a public IP are
+// probed, each at most once per peerProbeInterval; results are persisted
+// with the validator metadata. It does nothing unless probing is enabled.
+func (f *Fetcher) applyPeerReachability(ctx context.Context, validators []*models.Validator) {
+	if f.peerProbeInterval <= 0 {
+		return
+	}
+	now := f.clock.Now()
+	byDomain := make(map[string][]*models.Validator)
+	var domains []string
+	f.sourceStateMu.Lock()
+	for _, v := range validators {
+		if v == nil || v.Address == "" || !isProfileDomain(v.Domain) {
+			continue
+		}
+		entry := f.metadataCache[v.Address]
+		if entry != nil && entry.PeerDomain == v.Domain {
+			entry.applyPeerReachability(v)
+			if now.Sub(time.Unix(entry.PeerCheckedAt, 0)) < f.peerProbeInterval {
+				continue
+			}
+		}
+		domain := strings.ToLower(v.Domain)
+		if _, ok := byDomain[domain]; !ok {
+			if len(domains) == maxPeerProbesPerCycle {
+				continue
+			}
+			domains = append(domains, domain)
+		}
+		byDomain[domain] = append(byDomain[domain], v)
+	}
+	f.sourceStateMu.Unlock()
+
+	type probeResult struct {
+		reachable *bool
+		port      int
+	}
+	results := make([]probeResult, len(domains))
+	slots := make(chan struct{}, peerProbeConcurrency)
+	var wg sync.WaitGroup
+	for i, domain := range domains {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i].reachable, results[i].port = f.probePeerPort(ctx, domain)
+		}()
+	}
+	wg.Wait()
+
+	f.sourceStateMu.Lock()
+	defer f.sourceStateMu.Unlock()
+	for i, domain := range domains {
+		for _, v := range byDomain[domain] {
+			entry, ok := f.metadataCache[v.Address]
+			if !ok || entry == nil {
+				entry = &validatorMetadataEntry{Address: v.Address}
+				f.metadataCache[v.Address] = entry
+			}
+			// Domains without a public IP also wait for the next probe,
+			// so they are not looked up every cycle.
+			entry.PeerDomain = v.Domain
+			entry.PeerCheckedAt = now.Unix()
+			entry.PeerReachable, entry.PeerPort = results[i].reachable, results[i].port
+			entry.applyPeerReachability(v)
+		}
+	}
+}
+
+// applyPeerReachability sets the persisted probe result on v. Domains
+// without a public IP leave v unprobed.
+func (e *validatorMetadataEntry) applyPeerReachability(v *models.Validator) {
+	v.PeerReachable, v.PeerPort, v.PeerCheckedAt = nil, 0, 0
+	if e.PeerReachable == nil {
+		return
+	}
+	reachable := *e.PeerReachable
+	v.PeerReachable, v.PeerPort, v.PeerCheckedAt = &reachable, e.PeerPort, e.PeerCheckedAt
+}
+
+// probePeerPort resolves domain and connects to the peer ports of its first
+// public IP. It returns nil when the domain has no public IP, else whether a
+// port accepted the connection and which.
+func (f *Fetcher) probePeerPort(ctx context.Context, domain string) (*bool, int) {
+	lookupCtx, cancel := context.WithTimeout(ctx, peerProbeTimeout)
+	addresses, err := f.lookupHost(lookupCtx, domain)
+	cancel()
+	if err != nil {
+		f.logger.WithError(err).WithField("domain", domain).Debug("Failed to resolve validator domain for peer probe")
+	}
+	ip := ""
+	for _, address := range addresses {
+		if publicIP(address) {
+			ip = address
+			break
+		}
+	}
+	if ip == "" {
+		metrics.ValidatorPeerProbesTotal.WithLabelValues("skipped").Inc()
+		return nil, 0
+	}
+
+	reachable := false
+	for _, port := range peerProbePorts {
+		dialCtx, cancel := context.WithTimeout(ctx, peerProbeTimeout)
+		conn, err := f.peerDial(dialCtx, "tcp", net.JoinHostPort(ip, strconv.Itoa(port)))
+		cancel()
+		if err == nil {
+			conn.Close()
+			reachable = true
+			metrics.ValidatorPeerProbesTotal.WithLabelValues("reachable").Inc()
+			return &reachable, port
+		}
+	}
+	metrics.ValidatorPeerProbesTotal.WithLabelValues("unreachable").Inc()
+	return &reachable, 0
+}
+
+// publicIP reports whether address is a globally routable unicast IP, so
+// that a domain pointed at private ranges cannot make the service probe its
+// own network.
+func publicIP(address string) bool {
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
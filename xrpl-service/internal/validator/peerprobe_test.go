@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestApplyPeerReachability(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1_700_000_000, 0))
+	cachePath := filepath.Join(t.TempDir(), "validator-metadata-cache.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil, FetcherOptions{Clock: clk, PeerProbeInterval: time.Hour})
+
+	hosts := map[string][]string{
+		"open.example":     {"203.0.113.7"},
+		"closed.example":   {"198.51.100.9"},
+		"internal.example": {"10.0.0.5", "127.0.0.1"},
+	}
+	fetcher.lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		return hosts[host], nil
+	}
+	var mu sync.Mutex
+	var dialed []string
+	fetcher.peerDial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		mu.Lock()
+		dialed = append(dialed, address)
+		mu.Unlock()
+		if address == "203.0.113.7:51235" {
+			client, server := net.Pipe()
+			server.Close()
+			return client, nil
+		}
+		return nil, errors.New("connection refused")
+	}
+
+	validators := []*models.Validator{
+		{Address: "nOpen", Domain: "open.example"},
+		{Address: "nClosed", Domain: "closed.example"},
+		{Address: "nInternal", Domain: "internal.example"},
+		{Address: "nNone"},
+	}
+	fetcher.applyPeerReachability(context.Background(), validators)
+
+	if open := validators[0]; open.PeerReachable == nil || !*open.PeerReachable || open.PeerPort != 51235 || open.PeerCheckedAt != clk.Now().Unix() {
+		t.Fatalf("expected open.example reachable on 51235, got %+v", open)
+	}
+	if closed := validators[1]; closed.PeerReachable == nil || *closed.PeerReachable || closed.PeerPort != 0 {
+		t.Fatalf("expected closed.example unreachable, got %+v", closed)
+	}
+	if validators[2].PeerReachable != nil || validators[3].PeerReachable != nil {
+		t.Fatalf("expected domains without a public IP to stay unprobed, got %+v and %+v", validators[2], validators[3])
+	}
+	if len(dialed) != 4 {
+		t.Fatalf("expected only the public IPs to be dialed, got %v", dialed)
+	}
+
+	// Results are reused until the probe interval passes.
+	dialed = nil
+	clk.Advance(30 * time.Minute)
+	again := []*models.Validator{{Address: "nOpen", Domain: "open.example"}}
+	fetcher.applyPeerReachability(context.Background(), again)
+	if len(dialed) != 0 || again[0].PeerReachable == nil || !*again[0].PeerReachable {
+		t.Fatalf("expected the cached result without probing, got %+v after %v", again[0], dialed)
+	}
+	clk.Advance(time.Hour)
+	fetcher.applyPeerReachability(context.Background(), again)
+	if len(dialed) == 0 || again[0].PeerCheckedAt != clk.Now().Unix() {
+		t.Fatalf("expected a re-probe after the interval, got %+v", again[0])
+	}
+}
+
+func TestPublicIP(t *testing.T) {
+	for address, want := range map[string]bool{
+		"203.0.113.7": true,
+		"2001:db8::1": true,
+		"10.1.2.3":    false,
+		"192.168.1.1": false,
+		"127.0.0.1":   false,
+		"169.254.0.1": false,
+		"fd00::1":     false,
+		"not-an-ip":   false,
+		"0.0.0.0":     false,
+	} {
+		if got := publicIP(address); got != want {
+			t.Errorf("%s: expected %v, got %v", address, want, got)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package validator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+const (
+	// profileRefreshInterval is how long a domain's xrp-ledger.toml profile
+	// is reused before it is fetched again.
+	profileRefreshInterval = 24 * time.Hour
+
+	// maxProfileFetchesPerCycle bounds the xrp-ledger.toml requests made by
+	// one fetch cycle; the rest are picked up by later cycles.
+	maxProfileFetchesPerCycle = 16
+
+	profileFetchTimeout  = 10 * time.Second
+	maxProfileTOMLBytes  = 256 << 10
+	maxDescriptionLength = 280
+	maxOwnerLength       = 100
+)
+
+// defaultProfileURLTemplate locates a domain's xrp-ledger.toml.
+const defaultProfileURLTemplate = "https://%s/.well-known/xrp-ledger.toml"
+
+var twitterHandlePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+
+// validatorProfile is the optional social metadata of a validator.
+type validatorProfile struct {
+	Icon        string
+	Twitter     string
+	Description string
+}
+
+// validatorClaims are what a domain's xrp-ledger.toml asserts about a
+// validator, checked against observed behavior for its badges.
+type validatorClaims struct {
+	Listed bool   // the domain has a [[VALIDATORS]] stanza for the validator
+	Owner  string // organization named by the domain
+	UNL    string // list site the stanza says includes the validator
+}
+
+// sanitizeClaims keeps an owner name short enough to display and a UNL
+// claim only when it is an http(s) URL.
+func sanitizeClaims(listed bool, owner, unl string) validatorClaims {
+	if !listed {
+		return validatorClaims{}
+	}
+	claims := validatorClaims{Listed: true}
+	owner = strings.Join(strings.Fields(owner), " ")
+	if runes := []rune(owner); len(runes) > maxOwnerLength {
+		owner = string(runes[:maxOwnerLength-1]) + "…"
+	}
+	claims.Owner = owner
+	if parsed, err := url.Parse(strings.TrimSpace(unl)); err == nil &&
+		(parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != "" {
+		claims.UNL = parsed.String()
+	}
+	return claims
+}
+
+// apply sets the profile's non-empty fields on v. With overwrite unset only
+// fields v does not have yet are filled.
+func (p validatorProfile) apply(v *models.Validator, overwrite bool) {
+	if p.Icon != "" && (overwrite || v.Icon == "") {
+		v.Icon = p.Icon
+	}
+	if p.Twitter != "" && (overwrite || v.Twitter == "") {
+		v.Twitter = p.Twitter
+	}
+	if p.Description != "" && (overwrite || v.Description == "") {
+		v.Description = p.Description
+	}
+}
+
+// sanitizeProfile drops values the UI cannot use safely: icons must be
+// absolute http(s) URLs, Twitter handles are normalized from @handle or
+// profile URLs, and descriptions are trimmed to a tooltip's length.
+func sanitizeProfile(icon, twitter, description string) validatorProfile {
+	var profile validatorProfile
+
+	if parsed, err := url.Parse(strings.TrimSpace(icon)); err == nil &&
+		(parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != "" {
+		profile.Icon = parsed.String()
+	}
+
+	handle := strings.TrimSpace(twitter)
+	for _, prefix := range []string{"https://", "http://", "www.", "twitter.com/", "x.com/", "@"} {
+		handle = strings.TrimPrefix(handle, prefix)
+	}
+	handle = strings.TrimSuffix(handle, "/")
+	if twitterHandlePattern.MatchString(handle) {
+		profile.Twitter = handle
+	}
+
+	description = strings.Join(strings.Fields(description), " ")
+	if runes := []rune(description); len(runes) > maxDescriptionLength {
+		description = string(runes[:maxDescriptionLength-1]) + "…"
+	}
+	profile.Description = description
+
+	return profile
+}
+
+// applyDomainProfiles sets validator profiles from the [[VALIDATORS]]
+// stanzas of their domains' xrp-ledger.toml, over any registry values, and
+// records the domains' owner and UNL claims for applyBadges. The profile is
+// persisted with the validator metadata and a domain is fetched
+// again only after profileRefreshInterval; if that fetch fails the persisted
+// profile is kept.
+func (f *Fetcher) applyDomainProfiles(ctx context.Context, validators []*models.Validator) {
+	now := f.clock.Now()
+	byDomain := make(map[string][]*models.Validator)
+	var domains []string
+	f.sourceStateMu.Lock()
+	for _, v := range validators {
+		if v == nil || v.Address == "" || !isProfileDomain(v.Domain) {
+			continue
+		}
+		entry := f.metadataCache[v.Address]
+		if entry != nil && entry.ProfileDomain == v.Domain {
+			entry.profile().apply(v, true)
+			if now.Sub(time.Unix(entry.ProfileCheckedAt, 0)) < profileRefreshInterval {
+				continue
+			}
+		}
+		domain := strings.ToLower(v.Domain)
+		if _, ok := byDomain[domain]; !ok {
+			if len(domains) == maxProfileFetchesPerCycle {
+				continue
+			}
+			domains = append(domains, domain)
+		}
+		byDomain[domain] = append(byDomain[domain], v)
+	}
+	f.sourceStateMu.Unlock()
+
+	for _, domain := range domains {
+		stanzas, owner, err := f.fetchValidatorStanzas(ctx, domain)
+		if err != nil {
+			f.logger.WithError(err).WithField("domain", domain).Debug("Failed to fetch validator profile")
+		}
+
+		f.sourceStateMu.Lock()
+		for _, v := range byDomain[domain] {
+			entry, ok := f.metadataCache[v.Address]
+			if !ok || entry == nil {
+				entry = &validatorMetadataEntry{Address: v.Address}
+				f.metadataCache[v.Address] = entry
+				f.auditMetadata(v.Address, AuditFieldEntry, "", v.Address, AuditSourceProfile, now.Unix())
+			}
+			if err == nil || entry.ProfileDomain != v.Domain {
+				stanza, listed := stanzas[v.Address]
+				profile := sanitizeProfile(stanza["icon"], stanza["twitter"], stanza["desc"])
+				claims := sanitizeClaims(listed, owner, stanza["unl"])
+				for _, field := range []struct{ name, old, new string }{
+					{AuditFieldIcon, entry.Icon, profile.Icon},
+					{AuditFieldTwitter, entry.Twitter, profile.Twitter},
+					{AuditFieldDescription, entry.Description, profile.Description},
+					{AuditFieldOwner, entry.Owner, claims.Owner},
+				} {
+					if field.old != field.new {
+						f.auditMetadata(v.Address, field.name, field.old, field.new, AuditSourceProfile, now.Unix())
+					}
+				}
+				entry.Icon, entry.Twitter, entry.Description = profile.Icon, profile.Twitter, profile.Description
+				entry.ProfileListed, entry.Owner, entry.UNLClaim = claims.Listed, claims.Owner, claims.UNL
+			}
+			// Failed fetches also wait for the next refresh, so an
+			// unreachable domain is not requested every cycle.
+			entry.ProfileDomain = v.Domain
+			entry.ProfileCheckedAt = now.Unix()
+			entry.profile().apply(v, true)
+		}
+		f.sourceStateMu.Unlock()
+	}
+}
+
+// profile returns the persisted xrp-ledger.toml profile.
+func (e *validatorMetadataEntry) profile() validatorProfile {
+	return validatorProfile{Icon: e.Icon, Twitter: e.Twitter, Description: e.Description}
+}
+
+// claims returns the persisted xrp-ledger.toml claims.
+func (e *validatorMetadataEntry) claims() validatorClaims {
+	return validatorClaims{Listed: e.ProfileListed, Owner: e.Owner, UNL: e.UNLClaim}
+}
+
+// fetchValidatorStanzas fetches domain's xrp-ledger.toml and returns its
+// [[VALIDATORS]] stanzas keyed by public_key, and the owner it names.
+func (f *Fetcher) fetchValidatorStanzas(ctx context.Context, domain string) (map[string]map[string]string, string, error) {
+	template := f.profileURLTemplate
+	if template == "" {
+		template = defaultProfileURLTemplate
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, profileFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(fetchCtx, http.MethodGet, fmt.Sprintf(template, domain), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		err = xrpl.WrapTransportError(err)
+		metrics.UpstreamErrorsTotal.WithLabelValues("validator_toml", xrpl.Classify(err)).Inc()
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		statusErr := &xrpl.HTTPStatusError{StatusCode: resp.StatusCode}
+		metrics.UpstreamErrorsTotal.WithLabelValues("validator_toml", xrpl.Classify(statusErr)).Inc()
+		return nil, "", fmt.Errorf("xrp-ledger.toml returned status: %w", statusErr)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxProfileTOMLBytes))
+	if err != nil {
+		return nil, "", err
+	}
+
+	parsed, owner := parseValidatorTOML(data)
+	stanzas := make(map[string]map[string]string)
+	for _, stanza := range parsed {
+		if key := stanza["public_key"]; key != "" {
+			stanzas[key] = stanza
+		}
+	}
+	return stanzas, owner, nil
+}
+
+// isProfileDomain reports whether domain is a bare host name that can be
+// put in an xrp-ledger.toml URL.
+func isProfileDomain(domain string) bool {
+	return domain != "" && !strings.ContainsAny(domain, "/?#@:\\ \t")
+}
+
+// parseValidatorTOML extracts the string keys of each [[VALIDATORS]]
+// array-of-tables entry, and the owner: the name of the [OWNER] table, or
+// else of the first [[PRINCIPALS]] entry. It understands only the subset of
+// TOML that xrp-ledger.toml files use: table headers and single-line
+// key = "value" pairs. Other values and tables are skipped.
+func parseValidatorTOML(data []byte) ([]map[string]string, string) {
+	var stanzas []map[string]string
+	var current map[string]string
+	var owner, principal map[string]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			current = nil
+			switch strings.TrimSpace(stripComment(line)) {
+			case "[[VALIDATORS]]":
+				current = make(map[string]string)
+				stanzas = append(stanzas, current)
+			case "[OWNER]":
+				owner = make(map[string]string)
+				current = owner
+			case "[[PRINCIPALS]]":
+				if principal == nil {
+					principal = make(map[string]string)
+					current = principal
+				}
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if value, ok := parseTOMLString(strings.TrimSpace(raw)); ok {
+			current[strings.Trim(strings.TrimSpace(key), `"`)] = value
+		}
+	}
+	if name := owner["name"]; name != "" {
+		return stanzas, name
+	}
+	return stanzas, principal["name"]
+}
+
+// parseTOMLString parses a basic "..." or literal '...' string, ignoring a
+// trailing comment.
+func parseTOMLString(raw string) (string, bool) {
+	if len(raw) < 2 {
+		return "", false
+	}
+	switch raw[0] {
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", false
+		}
+		return raw[1 : end+1], true
+	case '"':
+		for i := 1; i < len(raw); i++ {
+			switch raw[i] {
+			case '\\':
+				i++
+			case '"':
+				value, err := strconv.Unquote(raw[:i+1])
+				return value, err == nil
+			}
+		}
+	}
+	return "", false
+}
+
+// stripComment removes a trailing # comment from a line without strings.
+func stripComment(line string) string {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
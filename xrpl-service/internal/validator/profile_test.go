@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+const testValidatorTOML = `
+[METADATA]
+modified = 2024-01-01T00:00:00.000Z
+
+[[VALIDATORS]]
+public_key = "nA1"   # primary
+network = "main"
+icon = "https://a.example/logo.png"
+twitter = "@a_validator"
+desc = "Operated by \"A\" in Paris"
+unl = "https://vl.ripple.com"
+
+[[VALIDATORS]]
+public_key = 'nA2'
+icon = "javascript:alert(1)"
+twitter = "https://x.com/not a handle"
+
+[[PRINCIPALS]]
+name = "A Validators"
+
+[[PRINCIPALS]]
+name = "ignored"
+`
+
+func TestParseValidatorStanzas(t *testing.T) {
+	stanzas, owner := parseValidatorTOML([]byte(testValidatorTOML))
+	if len(stanzas) != 2 {
+		t.Fatalf("expected 2 validator stanzas, got %+v", stanzas)
+	}
+	if owner != "A Validators" {
+		t.Fatalf("expected the first principal as owner, got %q", owner)
+	}
+	if _, owner := parseValidatorTOML([]byte("[OWNER]\nname = 'A Corp'\n" + testValidatorTOML)); owner != "A Corp" {
+		t.Fatalf("expected [OWNER] to take precedence, got %q", owner)
+	}
+	first := stanzas[0]
+	if first["public_key"] != "nA1" || first["desc"] != `Operated by "A" in Paris` || first["network"] != "main" {
+		t.Fatalf("unexpected first stanza %+v", first)
+	}
+	if stanzas[1]["public_key"] != "nA2" || stanzas[1]["name"] != "" {
+		t.Fatalf("unexpected second stanza %+v", stanzas[1])
+	}
+}
+
+func TestSanitizeProfile(t *testing.T) {
+	profile := sanitizeProfile("javascript:alert(1)", "https://twitter.com/ripple/", strings.Repeat("x ", 200))
+	if profile.Icon != "" {
+		t.Fatalf("expected non-http icon to be dropped, got %q", profile.Icon)
+	}
+	if profile.Twitter != "ripple" {
+		t.Fatalf("expected handle from profile URL, got %q", profile.Twitter)
+	}
+	if n := len([]rune(profile.Description)); n != maxDescriptionLength {
+		t.Fatalf("expected description truncated to %d runes, got %d", maxDescriptionLength, n)
+	}
+}
+
+func TestApplyDomainProfilesCachesPerDomain(t *testing.T) {
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/a.example/.well-known/xrp-ledger.toml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testValidatorTOML))
+	}))
+	defer upstream.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "validator-metadata-cache.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	fetcher.profileURLTemplate = upstream.URL + "/%s/.well-known/xrp-ledger.toml"
+
+	validators := []*models.Validator{
+		{Address: "nA1", Domain: "a.example", Twitter: "registry_handle"},
+		{Address: "nA2", Domain: "a.example", Icon: "https://registry.example/icon.png"},
+	}
+	fetcher.applyDomainProfiles(context.Background(), validators)
+
+	if requests.Load() != 1 {
+		t.Fatalf("expected one request for the shared domain, got %d", requests.Load())
+	}
+	first := validators[0]
+	if first.Icon != "https://a.example/logo.png" || first.Twitter != "a_validator" || first.Description != `Operated by "A" in Paris` {
+		t.Fatalf("expected the toml profile to override the registry, got %+v", first)
+	}
+	if second := validators[1]; second.Icon != "https://registry.example/icon.png" || second.Twitter != "" {
+		t.Fatalf("expected unsafe toml values to be dropped, got %+v", second)
+	}
+
+	// Later cycles reuse the persisted profile without fetching.
+	fetcher.updatePersistedMetadata(validators, nil, nil)
+	reloaded := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	reloaded.profileURLTemplate = fetcher.profileURLTemplate
+	again := []*models.Validator{{Address: "nA1", Domain: "a.example"}}
+	reloaded.applyDomainProfiles(context.Background(), again)
+	if requests.Load() != 1 {
+		t.Fatalf("expected the cached profile to be reused, got %d requests", requests.Load())
+	}
+	if again[0].Twitter != "a_validator" {
+		t.Fatalf("expected the persisted profile, got %+v", again[0])
+	}
+}
@@ -0,0 +1,158 @@
+package validator
+
+import (
+	"sync"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// Fetch cycle stages, in the order they run.
+const (
+	StageValidatorList     = "validator_list"
+	StageTrustedValidators = "trusted_validators"
+	StageSecondaryRegistry = "secondary_registry"
+	StageProfiles          = "profiles"
+	StagePeerProbes        = "peer_probes"
+	StageGeolocation       = "geolocation"
+	StagePersist           = "persist"
+)
+
+// CycleCallback receives each completed fetch cycle.
+type CycleCallback func(*models.FetchCycle)
+
+// fetchProgress tracks the stages of the running fetch cycle and keeps the
+// last completed one.
+type fetchProgress struct {
+	clock clock.Clock
+
+	mu        sync.Mutex
+	current   *models.FetchCycle
+	stage     *models.FetchStage
+	last      *models.FetchCycle
+	cycles    int64
+	callbacks []CycleCallback
+}
+
+func newFetchProgress(clk clock.Clock) *fetchProgress {
+	return &fetchProgress{clock: clk}
+}
+
+// begin starts a cycle.
+func (p *fetchProgress) begin() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = &models.FetchCycle{StartedAt: p.clock.Now().UnixMilli(), Stages: []*models.FetchStage{}}
+	p.stage = nil
+}
+
+// beginStage starts the named stage of the running cycle.
+func (p *fetchProgress) beginStage(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current == nil {
+		return
+	}
+	p.stage = &models.FetchStage{Name: name, StartedAt: p.clock.Now().UnixMilli(), Running: true}
+	p.current.Stages = append(p.current.Stages, p.stage)
+}
+
+// endStage completes the running stage with the number of validators known
+// after it and its error, if any.
+func (p *fetchProgress) endStage(validators int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stage == nil {
+		return
+	}
+	stage := p.stage
+	p.stage = nil
+	stage.Running = false
+	stage.DurationMS = p.clock.Now().UnixMilli() - stage.StartedAt
+	stage.Validators = validators
+	result := "success"
+	if err != nil {
+		stage.Error = err.Error()
+		result = "error"
+	}
+	metrics.ValidatorFetchStageDuration.WithLabelValues(stage.Name, result).Observe(float64(stage.DurationMS) / 1000)
+}
+
+// end completes the running cycle and notifies callbacks.
+func (p *fetchProgress) end(validators int, err error) {
+	p.mu.Lock()
+	if p.current == nil {
+		p.mu.Unlock()
+		return
+	}
+	cycle := p.current
+	p.current = nil
+	p.stage = nil
+	cycle.FinishedAt = p.clock.Now().UnixMilli()
+	cycle.DurationMS = cycle.FinishedAt - cycle.StartedAt
+	cycle.Validators = validators
+	if err != nil {
+		cycle.Error = err.Error()
+	}
+	p.last = cycle
+	p.cycles++
+	callbacks := make([]CycleCallback, len(p.callbacks))
+	copy(callbacks, p.callbacks)
+	p.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(copyCycle(cycle))
+	}
+}
+
+// status returns copies of the running and last cycles. Durations of the
+// running cycle and stage are the time elapsed so far.
+func (p *fetchProgress) status() *models.FetchStatus {
+	nowMS := p.clock.Now().UnixMilli()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := &models.FetchStatus{
+		Running: p.current != nil,
+		Last:    copyCycle(p.last),
+		Cycles:  p.cycles,
+	}
+	if p.current != nil {
+		status.Current = copyCycle(p.current)
+		status.Current.DurationMS = nowMS - p.current.StartedAt
+		for _, stage := range status.Current.Stages {
+			if stage.Running {
+				stage.DurationMS = nowMS - stage.StartedAt
+				status.Stage = stage.Name
+			}
+		}
+	}
+	return status
+}
+
+func copyCycle(cycle *models.FetchCycle) *models.FetchCycle {
+	if cycle == nil {
+		return nil
+	}
+	out := *cycle
+	out.Stages = make([]*models.FetchStage, len(cycle.Stages))
+	for i, stage := range cycle.Stages {
+		copy := *stage
+		out.Stages[i] = &copy
+	}
+	return &out
+}
+
+// AddCycleCallback registers a callback for completed fetch cycles.
+func (f *Fetcher) AddCycleCallback(callback CycleCallback) {
+	f.progress.mu.Lock()
+	defer f.progress.mu.Unlock()
+	f.progress.callbacks = append(f.progress.callbacks, callback)
+}
+
+// FetchStatus returns the progress of the running fetch cycle, if any, and
+// the last completed one.
+func (f *Fetcher) FetchStatus() *models.FetchStatus {
+	return f.progress.status()
+}
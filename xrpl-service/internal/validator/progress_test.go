@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestFetchProgressTracksStages(t *testing.T) {
+	fake := clock.NewFake(time.UnixMilli(1_700_000_000_000))
+	progress := newFetchProgress(fake)
+
+	var completed []*models.FetchCycle
+	progress.callbacks = append(progress.callbacks, func(cycle *models.FetchCycle) { completed = append(completed, cycle) })
+
+	if status := progress.status(); status.Running || status.Last != nil {
+		t.Fatalf("expected an idle status before the first cycle, got %+v", status)
+	}
+
+	progress.begin()
+	progress.beginStage(StageValidatorList)
+	fake.Advance(1500 * time.Millisecond)
+	progress.endStage(30, nil)
+	progress.beginStage(StageSecondaryRegistry)
+	fake.Advance(200 * time.Millisecond)
+
+	status := progress.status()
+	if !status.Running || status.Stage != StageSecondaryRegistry || len(status.Current.Stages) != 2 {
+		t.Fatalf("expected the registry stage to be running, got %+v", status)
+	}
+	if running := status.Current.Stages[1]; !running.Running || running.DurationMS != 200 {
+		t.Fatalf("expected the running stage's elapsed time, got %+v", running)
+	}
+	if done := status.Current.Stages[0]; done.DurationMS != 1500 || done.Validators != 30 {
+		t.Fatalf("expected the partial result of the list stage, got %+v", done)
+	}
+
+	progress.endStage(35, errors.New("registry returned status 503"))
+	fake.Advance(300 * time.Millisecond)
+	progress.end(35, nil)
+
+	status = progress.status()
+	if status.Running || status.Cycles != 1 || status.Last == nil {
+		t.Fatalf("expected one completed cycle, got %+v", status)
+	}
+	last := status.Last
+	if last.DurationMS != 2000 || last.Validators != 35 || last.Error != "" {
+		t.Fatalf("unexpected completed cycle %+v", last)
+	}
+	if last.Stages[1].Error != "registry returned status 503" || last.Stages[1].Running {
+		t.Fatalf("expected the stage-level error to be kept, got %+v", last.Stages[1])
+	}
+	if len(completed) != 1 || completed[0].DurationMS != 2000 {
+		t.Fatalf("expected one cycle callback, got %+v", completed)
+	}
+}
@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// loadProvisionalSnapshot restores the validator set of the last persisted
+// fetch cycle from the metadata cache, so that clients get validators while
+// the first fetch, which can take minutes on a cold geolocation cache, is
+// still running. Every cycle stamps the validators it saw with the same
+// LastSeenAt, so the newest stamp identifies that cycle's set.
+func (f *Fetcher) loadProvisionalSnapshot() {
+	now := f.clock.Now()
+	f.sourceStateMu.Lock()
+	var newest int64
+	for _, entry := range f.metadataCache {
+		if entry != nil && entry.LastSeenAt > newest {
+			newest = entry.LastSeenAt
+		}
+	}
+	validators := make(map[string]*models.Validator)
+	if newest > 0 {
+		for address, entry := range f.metadataCache {
+			if entry == nil || entry.LastSeenAt != newest {
+				continue
+			}
+			validators[address] = &models.Validator{
+				Address: address,
+				// Lists without an address field are keyed by the master
+				// public key, which the metadata cache does not keep
+				// separately.
+				PublicKey:        address,
+				Domain:           entry.Domain,
+				Name:             entry.Name,
+				Network:          f.network,
+				Latitude:         entry.Latitude,
+				Longitude:        entry.Longitude,
+				CountryCode:      entry.CountryCode,
+				City:             entry.City,
+				Approximate:      entry.Approximate,
+				StaleLocation:    f.locationExpired(entry, now),
+				Icon:             entry.Icon,
+				Twitter:          entry.Twitter,
+				Description:      entry.Description,
+				SigningKey:       entry.SigningKey,
+				ManifestSequence: entry.ManifestSequence,
+				LastUpdated:      entry.LastSeenAt,
+				IsActive:         true,
+			}
+			if f.peerProbeInterval > 0 && entry.PeerDomain == entry.Domain {
+				entry.applyPeerReachability(validators[address])
+			}
+		}
+	}
+	f.sourceStateMu.Unlock()
+	if len(validators) == 0 {
+		return
+	}
+	for _, v := range validators {
+		if v.CountryCode == "" {
+			v.CountryCode = "XX"
+		}
+		if v.City == "" {
+			v.City = "Unknown"
+		}
+		if v.Name == "" {
+			v.Name = v.Address
+		}
+	}
+
+	f.mu.Lock()
+	f.validators = validators
+	f.snapshotHash = SnapshotHash(validators)
+	f.provisionalSince = time.Unix(newest, 0)
+	f.mu.Unlock()
+	metrics.ValidatorsCount.Set(float64(len(validators)))
+
+	f.logger.WithFields(logrus.Fields{
+		"count":      len(validators),
+		"fetched_at": f.provisionalSince.UTC().Format(time.RFC3339),
+	}).Info("Serving provisional validators from the metadata cache until the first fetch completes")
+}
+
+// ProvisionalSince returns when the provisional validator set restored at
+// startup was fetched, or the zero time once a fetch cycle has completed or
+// if nothing was restored.
+func (f *Fetcher) ProvisionalSince() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.provisionalSince
+}
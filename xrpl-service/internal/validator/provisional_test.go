@@ -0,0 +1,73 @@
+package validator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/xrpltest"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+const (
+	trustedKeyA = "nHBidG3pZK11zQD6kpNDoAhDxH6WLGui6ZxSbUx7LSqLHsgzMPec"
+	trustedKeyB = "nHUon2tpyJEHHYGmxqeGu37cvPYHzrMtUNQFVdCgGNvEkjmCpTqK"
+)
+
+func TestProvisionalSnapshotServedUntilFirstFetch(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	previous := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil, FetcherOptions{Clock: fake})
+	previous.updatePersistedMetadata([]*models.Validator{{Address: "nGone", Domain: "gone.example"}}, nil, nil)
+	fake.Advance(time.Hour)
+	previous.updatePersistedMetadata([]*models.Validator{
+		{Address: trustedKeyA, Domain: "a.example", Latitude: 52.52, Longitude: 13.40, CountryCode: "DE", City: "Berlin"},
+		{Address: "nRemoved", Domain: "removed.example"},
+	}, nil, nil)
+
+	node := xrpltest.NewServer()
+	defer node.Close()
+	site := httptest.NewServer(http.NotFoundHandler())
+	defer site.Close()
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer registry.Close()
+	fetcher := NewFetcher(xrpl.NewClient(node.URL(), "", nil), time.Minute, nil, []string{site.URL}, registry.URL, cachePath, nil, 1, "mainnet", nil, FetcherOptions{Clock: fake})
+
+	// Only the set of the last persisted cycle is restored.
+	validators := fetcher.GetValidators()
+	if len(validators) != 2 {
+		t.Fatalf("expected the 2 validators of the last cycle, got %d", len(validators))
+	}
+	if v := fetcher.GetValidator(trustedKeyA); v == nil || v.City != "Berlin" || v.Latitude != 52.52 || !v.IsActive {
+		t.Fatalf("expected the cached location, got %+v", v)
+	}
+	if since := fetcher.ProvisionalSince(); !since.Equal(fake.Now()) {
+		t.Fatalf("expected the provisional set from %v, got %v", fake.Now(), since)
+	}
+	if !fetcher.GetLastUpdate().IsZero() {
+		t.Fatal("expected no completed fetch yet")
+	}
+
+	var updates []*models.ValidatorUpdate
+	fetcher.AddCallback(func(update *models.ValidatorUpdate) { updates = append(updates, update) })
+	if err := fetcher.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if !fetcher.ProvisionalSince().IsZero() {
+		t.Fatal("expected the provisional flag to clear after the first fetch")
+	}
+	if len(fetcher.GetValidators()) != 2 || fetcher.GetValidator("nRemoved") != nil || fetcher.GetValidator(trustedKeyB) == nil {
+		t.Fatalf("expected the fetched set to replace the provisional one, got %d validators", len(fetcher.GetValidators()))
+	}
+	// Clients that got the provisional set are told what changed.
+	if len(updates) != 1 || len(updates[0].Removals) != 1 || updates[0].Removals[0].Address != "nRemoved" {
+		t.Fatalf("expected the first fetch to push its changes, got %+v", updates)
+	}
+}
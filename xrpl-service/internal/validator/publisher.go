@@ -0,0 +1,262 @@
+package validator
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/timeutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Publisher key rotation kinds.
+const (
+	PublisherRotationSigning = "signing"
+	PublisherRotationMaster  = "master"
+)
+
+// recordPublisher tracks the publisher key and manifest of a validator list
+// site response, and reports whether the tracked state changed. Signing key
+// rotations announced by a manifest with a higher sequence are accepted. A
+// new master key is accepted when it follows the site's current key along a
+// configured rotation chain, or on first sight when no chains are
+// configured; any other key raises an alert on the site until it is
+// accepted. Lists are not rejected either way: their signatures are not
+// verified yet.
+func (f *Fetcher) recordPublisher(site string, result map[string]interface{}) bool {
+	publicKey, _ := result["public_key"].(string)
+	publicKey = strings.ToUpper(strings.TrimSpace(publicKey))
+	if publicKey == "" {
+		return false
+	}
+	encoded, _ := result["manifest"].(string)
+	m, err := parseManifest(encoded)
+	alert := ""
+	switch {
+	case err != nil || m.MasterKey != publicKey:
+		alert = models.PublisherAlertManifestMismatch
+	case m.Revoked():
+		alert = models.PublisherAlertRevoked
+	}
+
+	now := f.clock.Now().Unix()
+	f.sourceStateMu.Lock()
+	defer func() {
+		f.updatePublisherAlertsMetric()
+		f.sourceStateMu.Unlock()
+	}()
+
+	entry := f.publishers[site]
+	if entry == nil {
+		entry = &models.ValidatorListPublisher{Site: site, FirstSeenAt: now}
+		f.publishers[site] = entry
+	}
+	entry.LastSeenAt = now
+	if alert == "" && !f.publisherKeyAccepted(entry.MasterKey, publicKey) {
+		alert = models.PublisherAlertUnknownKey
+	}
+
+	logger := f.logger.WithFields(logrus.Fields{
+		"site":       site,
+		"public_key": publicKey,
+	})
+	if alert != "" {
+		if entry.Alert == alert && entry.PresentedKey == publicKey {
+			return false
+		}
+		entry.Alert = alert
+		entry.PresentedKey = publicKey
+		entry.AlertSince = now
+		fields := logrus.Fields{"alert": alert, "accepted_key": entry.MasterKey}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		logger.WithFields(fields).Error("Validator list publisher presented a key that is not accepted")
+		return true
+	}
+
+	changed := entry.Alert != ""
+	entry.Alert, entry.PresentedKey, entry.AlertSince = "", "", 0
+	switch {
+	case entry.MasterKey == "":
+		entry.MasterKey, entry.SigningKey, entry.ManifestSequence = publicKey, m.SigningKey, m.Sequence
+		logger.WithField("sequence", m.Sequence).Info("Tracking validator list publisher")
+		return true
+	case entry.MasterKey != publicKey:
+		f.recordPublisherRotation(entry, PublisherRotationMaster, entry.MasterKey, publicKey, m.Sequence, now)
+		entry.MasterKey, entry.SigningKey, entry.ManifestSequence = publicKey, m.SigningKey, m.Sequence
+		logger.Warn("Validator list publisher rotated to the next configured master key")
+		return true
+	case m.Sequence > entry.ManifestSequence:
+		if m.SigningKey != entry.SigningKey {
+			f.recordPublisherRotation(entry, PublisherRotationSigning, entry.SigningKey, m.SigningKey, m.Sequence, now)
+			logger.WithFields(logrus.Fields{
+				"signing_key": m.SigningKey,
+				"sequence":    m.Sequence,
+			}).Info("Validator list publisher rotated its signing key")
+		}
+		entry.SigningKey, entry.ManifestSequence = m.SigningKey, m.Sequence
+		return true
+	}
+	// Lower sequences come from stale lists and are ignored.
+	return changed
+}
+
+// recordListExpiration tracks the sequence and expiration of the list a
+// validator list site published, and reports whether they changed. A list
+// that has expired, or expires within listExpiryWarning, raises an expiry
+// alert on the site: rippled stops trusting an expired list, and its
+// validators drop out of the UNL until the publisher signs a new one.
+func (f *Fetcher) recordListExpiration(site string, blob map[string]interface{}) bool {
+	expiration, ok := timeutil.ParseRipple(blob["expiration"])
+	if !ok {
+		return false
+	}
+	sequence := jsonutil.Int64(blob, "sequence")
+	expiresAt := timeutil.RippleToUnix(expiration)
+	now := f.clock.Now()
+
+	f.sourceStateMu.Lock()
+	defer func() {
+		f.updateListExpiryMetrics()
+		f.sourceStateMu.Unlock()
+	}()
+
+	entry := f.publishers[site]
+	if entry == nil {
+		entry = &models.ValidatorListPublisher{Site: site, FirstSeenAt: now.Unix(), LastSeenAt: now.Unix()}
+		f.publishers[site] = entry
+	}
+	expiry := f.listExpiry(expiresAt, now)
+	changed := entry.ListSequence != sequence || entry.ListExpiration != expiresAt || entry.ListExpiry != expiry
+	if expiry != "" && expiry != entry.ListExpiry {
+		logger := f.logger.WithFields(logrus.Fields{
+			"site":       site,
+			"sequence":   sequence,
+			"expiration": timeutil.FromRipple(expiration).Format(time.RFC3339),
+		})
+		if expiry == models.ListExpiryExpired {
+			logger.Error("Validator list has expired")
+		} else {
+			logger.Warn("Validator list expires soon")
+		}
+	}
+	entry.ListSequence, entry.ListExpiration, entry.ListExpiry = sequence, expiresAt, expiry
+	return changed
+}
+
+// listExpiry returns the expiry alert of a list expiring at expiresAt, in
+// unix seconds, or "" for none.
+func (f *Fetcher) listExpiry(expiresAt int64, now time.Time) string {
+	switch {
+	case expiresAt == 0:
+		return ""
+	case now.Unix() >= expiresAt:
+		return models.ListExpiryExpired
+	case f.listExpiryWarning > 0 && now.Add(f.listExpiryWarning).Unix() >= expiresAt:
+		return models.ListExpiryExpiring
+	}
+	return ""
+}
+
+// updateListExpiryMetrics exports the list expirations and counts the
+// sites with an expiry alert. The caller holds sourceStateMu.
+func (f *Fetcher) updateListExpiryMetrics() {
+	now := f.clock.Now()
+	alerts := 0
+	for site, entry := range f.publishers {
+		if entry.ListExpiration == 0 {
+			continue
+		}
+		metrics.ValidatorListExpiration.WithLabelValues(site).Set(float64(entry.ListExpiration))
+		if f.listExpiry(entry.ListExpiration, now) != "" {
+			alerts++
+		}
+	}
+	metrics.ValidatorListExpiryAlerts.Set(float64(alerts))
+}
+
+// recordPublisherRotation appends a rotation to entry. The caller holds
+// sourceStateMu.
+func (f *Fetcher) recordPublisherRotation(entry *models.ValidatorListPublisher, kind, oldKey, newKey string, sequence uint32, now int64) {
+	entry.Rotations = append(entry.Rotations, &models.PublisherKeyRotation{
+		Kind:        kind,
+		OldKey:      oldKey,
+		NewKey:      newKey,
+		OldSequence: entry.ManifestSequence,
+		NewSequence: sequence,
+		RotatedAt:   now,
+	})
+	if len(entry.Rotations) > maxKeyRotations {
+		entry.Rotations = entry.Rotations[len(entry.Rotations)-maxKeyRotations:]
+	}
+	metrics.ValidatorListPublisherRotationsTotal.WithLabelValues(kind).Inc()
+}
+
+// publisherKeyAccepted reports whether a site whose accepted master key is
+// current may present key. Without configured chains the first key seen is
+// trusted and kept. With them key must be in a chain, and a site whose
+// current key is in a chain may only stay on it or move to a later key of
+// that chain.
+func (f *Fetcher) publisherKeyAccepted(current, key string) bool {
+	if len(f.publisherKeyChains) == 0 {
+		return current == "" || current == key
+	}
+	chain, to := f.publisherChainPosition(key)
+	if chain < 0 {
+		return false
+	}
+	currentChain, from := f.publisherChainPosition(current)
+	return currentChain < 0 || currentChain == chain && from <= to
+}
+
+// publisherChainPosition returns the chain holding key and its index in
+// it, or -1, -1.
+func (f *Fetcher) publisherChainPosition(key string) (int, int) {
+	for i, chain := range f.publisherKeyChains {
+		for j, k := range chain {
+			if k == key {
+				return i, j
+			}
+		}
+	}
+	return -1, -1
+}
+
+// updatePublisherAlertsMetric counts the sites with an alert. The caller
+// holds sourceStateMu.
+func (f *Fetcher) updatePublisherAlertsMetric() {
+	alerts := 0
+	for _, entry := range f.publishers {
+		if entry.Alert != "" {
+			alerts++
+		}
+	}
+	metrics.ValidatorListPublisherAlerts.Set(float64(alerts))
+}
+
+// ValidatorListPublishers returns the tracked publisher of every validator
+// list site, sorted by site, with list expiry alerts as of now.
+func (f *Fetcher) ValidatorListPublishers(ctx context.Context) []*models.ValidatorListPublisher {
+	now := f.clock.Now()
+	f.sourceStateMu.Lock()
+	defer f.sourceStateMu.Unlock()
+
+	publishers := make([]*models.ValidatorListPublisher, 0, len(f.publishers))
+	for _, entry := range f.publishers {
+		copy := *entry
+		copy.ListExpiry = f.listExpiry(entry.ListExpiration, now)
+		copy.Rotations = make([]*models.PublisherKeyRotation, 0, len(entry.Rotations))
+		for _, rotation := range entry.Rotations {
+			rotationCopy := *rotation
+			copy.Rotations = append(copy.Rotations, &rotationCopy)
+		}
+		publishers = append(publishers, &copy)
+	}
+	sort.Slice(publishers, func(i, j int) bool { return publishers[i].Site < publishers[j].Site })
+	return publishers
+}
@@ -0,0 +1,175 @@
+package validator
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func publisherResponse(sequence uint32, master, signing []byte) map[string]interface{} {
+	return map[string]interface{}{
+		"public_key": strings.ToLower(publisherKey(master)),
+		"manifest":   encodeManifest(sequence, master, signing, ""),
+	}
+}
+
+func publisherKey(key []byte) string {
+	m, _ := parseManifest(encodeManifest(1, key, nil, ""))
+	return m.MasterKey
+}
+
+func TestRecordPublisherTrustsFirstKeyAndTracksSigningRotations(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	clk := clock.NewFake(time.Unix(1_700_000_000, 0))
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil, FetcherOptions{Clock: clk})
+	const site = "https://vl.example"
+
+	if !fetcher.recordPublisher(site, publisherResponse(1, testKey(0xAA), testKey(0xB1))) {
+		t.Fatal("expected the first publisher key to be tracked")
+	}
+	clk.Advance(time.Hour)
+	if !fetcher.recordPublisher(site, publisherResponse(2, testKey(0xAA), testKey(0xB2))) {
+		t.Fatal("expected a signing key rotation to be recorded")
+	}
+	// A stale list still carrying the old manifest is not a rotation back.
+	if fetcher.recordPublisher(site, publisherResponse(1, testKey(0xAA), testKey(0xB1))) {
+		t.Fatal("expected a lower manifest sequence to be ignored")
+	}
+
+	publishers := fetcher.ValidatorListPublishers(context.Background())
+	if len(publishers) != 1 {
+		t.Fatalf("expected one publisher, got %+v", publishers)
+	}
+	p := publishers[0]
+	if p.MasterKey != publisherKey(testKey(0xAA)) || p.SigningKey != publisherKey(testKey(0xB2)) || p.ManifestSequence != 2 || p.Alert != "" {
+		t.Fatalf("unexpected publisher %+v", p)
+	}
+	if len(p.Rotations) != 1 || p.Rotations[0].Kind != PublisherRotationSigning || p.Rotations[0].OldSequence != 1 || p.Rotations[0].NewSequence != 2 {
+		t.Fatalf("unexpected rotations %+v", p.Rotations)
+	}
+
+	// Without configured chains, a different master key is not accepted and
+	// the trusted key is kept.
+	if !fetcher.recordPublisher(site, publisherResponse(1, testKey(0xCC), testKey(0xD1))) {
+		t.Fatal("expected an unknown key to raise an alert")
+	}
+	if fetcher.recordPublisher(site, publisherResponse(1, testKey(0xCC), testKey(0xD1))) {
+		t.Fatal("expected a repeated alert not to change the publisher")
+	}
+	p = fetcher.ValidatorListPublishers(context.Background())[0]
+	if p.Alert != models.PublisherAlertUnknownKey || p.PresentedKey != publisherKey(testKey(0xCC)) || p.MasterKey != publisherKey(testKey(0xAA)) {
+		t.Fatalf("expected an unknown_key alert keeping the trusted key, got %+v", p)
+	}
+
+	// The tracked publisher survives a restart.
+	if err := fetcher.persistMetadataCache(); err != nil {
+		t.Fatalf("persistMetadataCache failed: %v", err)
+	}
+	reloaded := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	if got := reloaded.ValidatorListPublishers(context.Background()); len(got) != 1 || got[0].Alert != models.PublisherAlertUnknownKey || len(got[0].Rotations) != 1 {
+		t.Fatalf("expected the publisher to be reloaded, got %+v", got)
+	}
+}
+
+func TestRecordPublisherFollowsConfiguredChains(t *testing.T) {
+	oldKey, newKey, otherKey := publisherKey(testKey(0xAA)), publisherKey(testKey(0xBB)), publisherKey(testKey(0xCC))
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", filepath.Join(t.TempDir(), "metadata.json"), nil, 1, "mainnet", nil, FetcherOptions{
+		PublisherKeyChains: [][]string{{oldKey, newKey}, {otherKey}},
+	})
+	const site = "https://vl.example"
+	publisher := func() *models.ValidatorListPublisher {
+		return fetcher.ValidatorListPublishers(context.Background())[0]
+	}
+
+	fetcher.recordPublisher(site, publisherResponse(5, testKey(0xAA), testKey(0xA1)))
+	if p := publisher(); p.MasterKey != oldKey || p.Alert != "" {
+		t.Fatalf("expected the configured key to be accepted, got %+v", p)
+	}
+
+	fetcher.recordPublisher(site, publisherResponse(1, testKey(0xBB), testKey(0xB1)))
+	p := publisher()
+	if p.MasterKey != newKey || p.ManifestSequence != 1 || p.Alert != "" {
+		t.Fatalf("expected the rotation along the chain to be accepted, got %+v", p)
+	}
+	if len(p.Rotations) != 1 || p.Rotations[0].Kind != PublisherRotationMaster || p.Rotations[0].OldKey != oldKey || p.Rotations[0].NewKey != newKey {
+		t.Fatalf("unexpected rotations %+v", p.Rotations)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		response map[string]interface{}
+		alert    string
+	}{
+		{name: "back along the chain", response: publisherResponse(9, testKey(0xAA), testKey(0xA2)), alert: models.PublisherAlertUnknownKey},
+		{name: "another chain", response: publisherResponse(1, testKey(0xCC), testKey(0xC1)), alert: models.PublisherAlertUnknownKey},
+		{name: "unconfigured key", response: publisherResponse(1, testKey(0xDD), testKey(0xD1)), alert: models.PublisherAlertUnknownKey},
+		{name: "manifest for another key", response: map[string]interface{}{
+			"public_key": newKey,
+			"manifest":   encodeManifest(2, testKey(0xDD), testKey(0xD1), ""),
+		}, alert: models.PublisherAlertManifestMismatch},
+		{name: "missing manifest", response: map[string]interface{}{"public_key": newKey}, alert: models.PublisherAlertManifestMismatch},
+		{name: "revoked", response: publisherResponse(revokedManifestSequence, testKey(0xBB), nil), alert: models.PublisherAlertRevoked},
+	} {
+		fetcher.recordPublisher(site, tc.response)
+		if p := publisher(); p.Alert != tc.alert || p.MasterKey != newKey {
+			t.Fatalf("%s: expected alert %s keeping %s, got %+v", tc.name, tc.alert, newKey, p)
+		}
+	}
+
+	// Presenting the accepted key again clears the alert.
+	fetcher.recordPublisher(site, publisherResponse(1, testKey(0xBB), testKey(0xB1)))
+	if p := publisher(); p.Alert != "" || p.PresentedKey != "" {
+		t.Fatalf("expected the alert to clear, got %+v", p)
+	}
+}
+
+func TestRecordListExpirationRaisesExpiryAlerts(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	clk := clock.NewFake(time.Unix(1_700_000_000, 0))
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil, FetcherOptions{
+		Clock:             clk,
+		ListExpiryWarning: 7 * 24 * time.Hour,
+	})
+	const site = "https://vl.example"
+	// Ripple-epoch seconds, 30 days out.
+	expiration := float64(1_700_000_000 - 946684800 + 30*24*3600)
+
+	if fetcher.recordListExpiration(site, map[string]interface{}{"sequence": float64(80)}) {
+		t.Fatal("expected a list without an expiration to be ignored")
+	}
+	if !fetcher.recordListExpiration(site, map[string]interface{}{"sequence": float64(80), "expiration": expiration}) {
+		t.Fatal("expected the first expiration to be recorded")
+	}
+	publisher := fetcher.ValidatorListPublishers(context.Background())[0]
+	if publisher.ListSequence != 80 || publisher.ListExpiration != 1_700_000_000+30*24*3600 || publisher.ListExpiry != "" {
+		t.Fatalf("unexpected publisher %+v", publisher)
+	}
+	if fetcher.recordListExpiration(site, map[string]interface{}{"sequence": float64(80), "expiration": expiration}) {
+		t.Fatal("expected an unchanged list to report no change")
+	}
+
+	clk.Advance(25 * 24 * time.Hour)
+	if got := fetcher.ValidatorListPublishers(context.Background())[0].ListExpiry; got != models.ListExpiryExpiring {
+		t.Fatalf("expected the list to be expiring between fetches, got %q", got)
+	}
+	if !fetcher.recordListExpiration(site, map[string]interface{}{"sequence": "80", "expiration": expiration}) {
+		t.Fatal("expected the alert to be recorded on the next fetch")
+	}
+
+	clk.Advance(5 * 24 * time.Hour)
+	fetcher.recordListExpiration(site, map[string]interface{}{"sequence": float64(80), "expiration": expiration})
+	if got := fetcher.ValidatorListPublishers(context.Background())[0].ListExpiry; got != models.ListExpiryExpired {
+		t.Fatalf("expected the list to be expired, got %q", got)
+	}
+
+	// A renewed list clears the alert.
+	fetcher.recordListExpiration(site, map[string]interface{}{"sequence": float64(81), "expiration": expiration + 90*24*3600})
+	if got := fetcher.ValidatorListPublishers(context.Background())[0]; got.ListExpiry != "" || got.ListSequence != 81 {
+		t.Fatalf("expected the renewed list to clear the alert, got %+v", got)
+	}
+}
@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+// maxKeyRotations bounds the recorded rotations per validator; the oldest
+// are dropped first.
+const maxKeyRotations = 20
+
+// RotationCallback receives a validator's signing key rotation.
+type RotationCallback func(*models.KeyRotation)
+
+// AddRotationCallback registers a callback for signing key rotations. They
+// run after the fetch cycle that first sees the new manifest, following the
+// cycle's validator update.
+func (f *Fetcher) AddRotationCallback(callback RotationCallback) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rotationCallbacks = append(f.rotationCallbacks, callback)
+}
+
+// recordSigningKey stores v's signing key in its metadata entry and reports
+// whether the entry changed. A new signing key under a higher manifest
+// sequence than recorded is returned as a rotation; manifests with a lower
+// sequence, e.g. from a stale list, are ignored.
+func recordSigningKey(entry *validatorMetadataEntry, v *models.Validator, now int64) (*models.KeyRotation, bool) {
+	if v.SigningKey == "" {
+		return nil, false
+	}
+	if entry.SigningKey != "" && v.ManifestSequence <= entry.ManifestSequence {
+		return nil, false
+	}
+	var rotation *models.KeyRotation
+	if entry.SigningKey != "" && entry.SigningKey != v.SigningKey {
+		rotation = &models.KeyRotation{
+			Address:       v.Address,
+			OldSigningKey: entry.SigningKey,
+			NewSigningKey: v.SigningKey,
+			OldSequence:   entry.ManifestSequence,
+			NewSequence:   v.ManifestSequence,
+			RotatedAt:     now,
+		}
+		entry.KeyRotations = append(entry.KeyRotations, rotation)
+		if len(entry.KeyRotations) > maxKeyRotations {
+			entry.KeyRotations = entry.KeyRotations[len(entry.KeyRotations)-maxKeyRotations:]
+		}
+	}
+	entry.SigningKey = v.SigningKey
+	entry.ManifestSequence = v.ManifestSequence
+	return rotation, true
+}
+
+// metadataEntryForKey returns the metadata entry of the validator with
+// master key key, or whose current or a past signing key is key, so that
+// records stay reachable by any key the validator has used. The caller holds
+// sourceStateMu.
+func (f *Fetcher) metadataEntryForKey(key string) *validatorMetadataEntry {
+	if entry := f.metadataCache[key]; entry != nil {
+		return entry
+	}
+	for _, entry := range f.metadataCache {
+		if entry == nil {
+			continue
+		}
+		if entry.SigningKey == key {
+			return entry
+		}
+		for _, rotation := range entry.KeyRotations {
+			if rotation.OldSigningKey == key || rotation.NewSigningKey == key {
+				return entry
+			}
+		}
+	}
+	return nil
+}
+
+// GetKeyHistory returns the recorded signing key rotations of a validator,
+// looked up by its master key or any signing key it has used, or
+// xrpl.ErrNotFound if it has never been seen.
+func (f *Fetcher) GetKeyHistory(ctx context.Context, key string) (*models.KeyHistory, error) {
+	f.sourceStateMu.Lock()
+	defer f.sourceStateMu.Unlock()
+
+	entry := f.metadataEntryForKey(key)
+	if entry == nil {
+		return nil, fmt.Errorf("validator %s: %w", key, xrpl.ErrNotFound)
+	}
+	history := &models.KeyHistory{
+		Address:          entry.Address,
+		SigningKey:       entry.SigningKey,
+		ManifestSequence: entry.ManifestSequence,
+		Rotations:        make([]*models.KeyRotation, 0, len(entry.KeyRotations)),
+	}
+	for _, rotation := range entry.KeyRotations {
+		copy := *rotation
+		history.Rotations = append(history.Rotations, &copy)
+	}
+	return history, nil
+}
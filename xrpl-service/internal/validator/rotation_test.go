@@ -0,0 +1,73 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+)
+
+func TestParseValidatorReadsManifest(t *testing.T) {
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", filepath.Join(t.TempDir(), "metadata.json"), nil, 1, "mainnet", nil)
+	encoded := encodeManifest(3, testKey(0xAA), testKey(0xBB), "")
+	v, err := fetcher.parseValidator(map[string]interface{}{
+		"validation_public_key": "EDAAAA",
+		"manifest":              encoded,
+	})
+	if err != nil {
+		t.Fatalf("parseValidator failed: %v", err)
+	}
+	if v.SigningKey == "" || v.ManifestSequence != 3 || v.Manifest != encoded || !v.IsActive {
+		t.Fatalf("expected the manifest signing key and sequence, got %+v", v)
+	}
+}
+
+func TestUpdatePersistedMetadataRecordsKeyRotations(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "metadata.json")
+	fetcher := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	update := func(signingKey string, sequence uint32) []*models.KeyRotation {
+		return fetcher.updatePersistedMetadata(
+			[]*models.Validator{{Address: "nA1", Domain: "a.example", SigningKey: signingKey, ManifestSequence: sequence}},
+			nil,
+			nil,
+		)
+	}
+
+	if rotations := update("n9Old", 1); len(rotations) != 0 {
+		t.Fatalf("expected the first signing key not to be a rotation, got %+v", rotations)
+	}
+	rotations := update("n9New", 2)
+	if len(rotations) != 1 {
+		t.Fatalf("expected one rotation, got %+v", rotations)
+	}
+	if r := rotations[0]; r.Address != "nA1" || r.OldSigningKey != "n9Old" || r.NewSigningKey != "n9New" || r.OldSequence != 1 || r.NewSequence != 2 {
+		t.Fatalf("unexpected rotation %+v", r)
+	}
+	// A stale list still carrying the old manifest is not a rotation back.
+	if rotations := update("n9Old", 1); len(rotations) != 0 {
+		t.Fatalf("expected a lower sequence to be ignored, got %+v", rotations)
+	}
+
+	// The rotation survives a restart and links the old and new keys to the
+	// validator.
+	reloaded := NewFetcher(nil, time.Minute, nil, nil, "", cachePath, nil, 1, "mainnet", nil)
+	for _, key := range []string{"nA1", "n9Old", "n9New"} {
+		history, err := reloaded.GetKeyHistory(context.Background(), key)
+		if err != nil {
+			t.Fatalf("GetKeyHistory(%s) failed: %v", key, err)
+		}
+		if history.Address != "nA1" || history.SigningKey != "n9New" || len(history.Rotations) != 1 {
+			t.Fatalf("unexpected key history for %s: %+v", key, history)
+		}
+	}
+	if domains, err := reloaded.GetDomainHistory(context.Background(), "n9Old"); err != nil || domains.Address != "nA1" {
+		t.Fatalf("expected the domain history under the master key, got %+v (%v)", domains, err)
+	}
+	if _, err := reloaded.GetKeyHistory(context.Background(), "nUnknown"); !errors.Is(err, xrpl.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
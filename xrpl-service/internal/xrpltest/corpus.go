@@ -0,0 +1,93 @@
+package xrpltest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+)
+
+// CorpusVersion is the layout of the stream message corpus under
+// testdata/corpus. Entries recorded with another version are rejected so a
+// format change cannot silently turn expectations into no-ops.
+const CorpusVersion = 1
+
+//go:embed testdata/corpus
+var corpusFS embed.FS
+
+// CorpusEntry is one anonymized stream message from the corpus together with
+// what the listener is expected to make of it.
+type CorpusEntry struct {
+	File        string                 `json:"-"`
+	Version     int                    `json:"version"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Message     map[string]interface{} `json:"message"`
+	Expect      CorpusExpect           `json:"expect"`
+}
+
+// CorpusExpect records the parse and enrichment results of a corpus message.
+// Fields that do not apply to a message are left empty.
+type CorpusExpect struct {
+	// Forwarded reports whether the message is forwarded as a payment.
+	Forwarded         bool     `json:"forwarded"`
+	Error             bool     `json:"error,omitempty"`
+	Amount            string   `json:"amount,omitempty"`
+	TransactionResult string   `json:"transaction_result,omitempty"`
+	CloseTime         uint32   `json:"close_time,omitempty"`
+	Candidates        []string `json:"candidates,omitempty"` // in enrichment order
+	CreatedAccounts   []string `json:"created_accounts,omitempty"`
+
+	Shape        *CorpusShape        `json:"shape,omitempty"`
+	DomainChange *CorpusDomainChange `json:"domain_change,omitempty"`
+}
+
+// CorpusShape is the expected composition of a validated transaction.
+type CorpusShape struct {
+	TransactionType  string   `json:"transaction_type"`
+	HasMemos         bool     `json:"has_memos,omitempty"`
+	LedgerEntryTypes []string `json:"ledger_entry_types,omitempty"`
+}
+
+// CorpusDomainChange is the expected Domain change of an AccountSet.
+type CorpusDomainChange struct {
+	Account   string `json:"account"`
+	OldDomain string `json:"old_domain,omitempty"`
+	NewDomain string `json:"new_domain,omitempty"`
+}
+
+// LoadCorpus returns every entry of the current corpus version, ordered by
+// file name.
+func LoadCorpus() ([]CorpusEntry, error) {
+	dir := fmt.Sprintf("testdata/corpus/v%d", CorpusVersion)
+	files, err := corpusFS.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read corpus: %w", err)
+	}
+
+	entries := make([]CorpusEntry, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || path.Ext(file.Name()) != ".json" {
+			continue
+		}
+		data, err := corpusFS.ReadFile(path.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read corpus entry %s: %w", file.Name(), err)
+		}
+		var entry CorpusEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("decode corpus entry %s: %w", file.Name(), err)
+		}
+		if entry.Version != CorpusVersion {
+			return nil, fmt.Errorf("corpus entry %s: version %d, want %d", file.Name(), entry.Version, CorpusVersion)
+		}
+		if entry.Message == nil {
+			return nil, fmt.Errorf("corpus entry %s: missing message", file.Name())
+		}
+		entry.File = file.Name()
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].File < entries[j].File })
+	return entries, nil
+}
@@ -0,0 +1,60 @@
+package xrpltest
+
+import (
+	"strconv"
+)
+
+// DefaultServerInfo returns a canned server_info result for a synced node.
+func DefaultServerInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"info": map[string]interface{}{
+			"build_version":    "2.3.0",
+			"complete_ledgers": "32570-90000000",
+			"network_id":       float64(0),
+			"peers":            float64(21),
+			"server_state":     "full",
+			"uptime":           float64(86400),
+			"validated_ledger": map[string]interface{}{
+				"age": float64(2),
+				"seq": float64(90000000),
+			},
+		},
+		"status": "success",
+	}
+}
+
+// DefaultValidators returns a canned validators result with two trusted keys.
+func DefaultValidators() map[string]interface{} {
+	return map[string]interface{}{
+		"trusted_validator_keys": []interface{}{
+			"nHBidG3pZK11zQD6kpNDoAhDxH6WLGui6ZxSbUx7LSqLHsgzMPec",
+			"nHUon2tpyJEHHYGmxqeGu37cvPYHzrMtUNQFVdCgGNvEkjmCpTqK",
+		},
+		"publisher_lists": []interface{}{},
+		"status":          "success",
+	}
+}
+
+// PaymentMessage builds a validated, successful XRP Payment stream message.
+func PaymentMessage(hash, account, destination string, drops int64, ledgerIndex uint32) map[string]interface{} {
+	return map[string]interface{}{
+		"type":          "transaction",
+		"validated":     true,
+		"engine_result": "tesSUCCESS",
+		"ledger_index":  float64(ledgerIndex),
+		"date":          float64(760000000),
+		"transaction": map[string]interface{}{
+			"TransactionType": "Payment",
+			"hash":            hash,
+			"Account":         account,
+			"Destination":     destination,
+			"Amount":          strconv.FormatInt(drops, 10),
+			"Fee":             "12",
+			"Flags":           float64(0),
+		},
+		"meta": map[string]interface{}{
+			"TransactionResult": "tesSUCCESS",
+			"delivered_amount":  strconv.FormatInt(drops, 10),
+		},
+	}
+}
@@ -0,0 +1,289 @@
+// Package xrpltest provides an in-process fake XRPL node serving JSON-RPC and
+// WebSocket traffic on a single httptest server, for tests that must run
+// without network access.
+package xrpltest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Server is a fake XRPL node. POST requests are answered as JSON-RPC and GET
+// requests are upgraded to a WebSocket that accepts subscribe commands and
+// receives messages injected with Emit.
+type Server struct {
+	httpServer *httptest.Server
+	upgrader   websocket.Upgrader
+
+	mu              sync.Mutex
+	results         map[string]map[string]interface{}
+	accountDomains  map[string]string
+	calls           map[string]int
+	subscribers     map[*subscriber]struct{}
+	subscriberAdded chan struct{}
+}
+
+type subscriber struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	streams map[string]struct{}
+}
+
+// NewServer starts a fake XRPL node with canned server_info and validators
+// responses on a random local port. Call Close when done.
+func NewServer() *Server {
+	s := newServer()
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// NewServerOn starts a fake XRPL node listening on addr, e.g. ":6006".
+func NewServerOn(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := newServer()
+	s.httpServer = httptest.NewUnstartedServer(http.HandlerFunc(s.handle))
+	s.httpServer.Listener.Close()
+	s.httpServer.Listener = listener
+	s.httpServer.Start()
+	return s, nil
+}
+
+func newServer() *Server {
+	s := &Server{
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		results:         make(map[string]map[string]interface{}),
+		accountDomains:  make(map[string]string),
+		calls:           make(map[string]int),
+		subscribers:     make(map[*subscriber]struct{}),
+		subscriberAdded: make(chan struct{}, 64),
+	}
+	s.results["server_info"] = DefaultServerInfo()
+	s.results["validators"] = DefaultValidators()
+	return s
+}
+
+// URL returns the JSON-RPC endpoint.
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// WSURL returns the WebSocket endpoint.
+func (s *Server) WSURL() string {
+	return "ws" + strings.TrimPrefix(s.httpServer.URL, "http")
+}
+
+// Close disconnects subscribers and stops the server.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for sub := range s.subscribers {
+		sub.conn.Close()
+	}
+	s.subscribers = make(map[*subscriber]struct{})
+	s.mu.Unlock()
+	s.httpServer.Close()
+}
+
+// SetResult overrides the canned result payload for a JSON-RPC method.
+func (s *Server) SetResult(method string, result map[string]interface{}) {
+	s.mu.Lock()
+	s.results[method] = result
+	s.mu.Unlock()
+}
+
+// SetAccountDomain registers the Domain returned by account_info for account.
+// Accounts without a registered domain are reported as actNotFound.
+func (s *Server) SetAccountDomain(account, domain string) {
+	s.mu.Lock()
+	s.accountDomains[account] = domain
+	s.mu.Unlock()
+}
+
+// Calls returns how many times a JSON-RPC method was requested.
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// WaitForSubscribers blocks until at least n WebSocket clients have
+// subscribed to a stream or the timeout elapses.
+func (s *Server) WaitForSubscribers(n int, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for {
+		if s.subscriberCount() >= n {
+			return true
+		}
+		select {
+		case <-s.subscriberAdded:
+		case <-deadline:
+			return s.subscriberCount() >= n
+		}
+	}
+}
+
+// Emit sends a stream message to every subscriber of stream.
+func (s *Server) Emit(stream string, msg interface{}) {
+	s.mu.Lock()
+	targets := make([]*subscriber, 0, len(s.subscribers))
+	for sub := range s.subscribers {
+		if _, ok := sub.streams[stream]; ok {
+			targets = append(targets, sub)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, sub := range targets {
+		sub.writeJSON(msg)
+	}
+}
+
+func (s *Server) subscriberCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for sub := range s.subscribers {
+		if len(sub.streams) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		s.handleWebSocket(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Method string                   `json:"method"`
+		Params []map[string]interface{} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := map[string]interface{}{}
+	if len(req.Params) > 0 && req.Params[0] != nil {
+		params = req.Params[0]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"result": s.resultFor(req.Method, params),
+	})
+}
+
+func (s *Server) resultFor(method string, params map[string]interface{}) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[method]++
+
+	if method == "account_info" {
+		account, _ := params["account"].(string)
+		domain, ok := s.accountDomains[account]
+		if !ok {
+			return map[string]interface{}{
+				"error":   "actNotFound",
+				"status":  "error",
+				"account": account,
+			}
+		}
+		accountData := map[string]interface{}{"Account": account}
+		if domain != "" {
+			accountData["Domain"] = strings.ToUpper(hex.EncodeToString([]byte(domain)))
+		}
+		return map[string]interface{}{
+			"account_data": accountData,
+			"status":       "success",
+			"validated":    true,
+		}
+	}
+
+	if result, ok := s.results[method]; ok {
+		return result
+	}
+	return map[string]interface{}{
+		"error":  "unknownCmd",
+		"status": "error",
+	}
+}
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	sub := &subscriber{conn: conn, streams: make(map[string]struct{})}
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		var cmd struct {
+			ID      interface{} `json:"id"`
+			Command string      `json:"command"`
+			Streams []string    `json:"streams"`
+		}
+		if err := conn.ReadJSON(&cmd); err != nil {
+			return
+		}
+
+		switch cmd.Command {
+		case "subscribe":
+			s.mu.Lock()
+			for _, stream := range cmd.Streams {
+				sub.streams[stream] = struct{}{}
+			}
+			s.mu.Unlock()
+			select {
+			case s.subscriberAdded <- struct{}{}:
+			default:
+			}
+		case "unsubscribe":
+			s.mu.Lock()
+			for _, stream := range cmd.Streams {
+				delete(sub.streams, stream)
+			}
+			s.mu.Unlock()
+		}
+
+		sub.writeJSON(map[string]interface{}{
+			"id":     cmd.ID,
+			"result": map[string]interface{}{},
+			"status": "success",
+			"type":   "response",
+		})
+	}
+}
+
+func (sub *subscriber) writeJSON(msg interface{}) {
+	sub.writeMu.Lock()
+	defer sub.writeMu.Unlock()
+	sub.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	sub.conn.WriteJSON(msg)
+}
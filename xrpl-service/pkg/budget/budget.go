@@ -0,0 +1,200 @@
+// Package budget shares outbound request ceilings between the subsystems
+// that call the same external hosts, so that a burst from one of them, say
+// transaction enrichment, cannot spend the rate limit another, like the
+// validator fetcher, depends on.
+package budget
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// Subsystems that send outbound requests.
+const (
+	SubsystemFetcher    = "fetcher"    // validator lists, registries, profiles and validator RPCs
+	SubsystemHealth     = "health"     // network health checks
+	SubsystemEnrichment = "enrichment" // transaction geo enrichment RPCs
+	SubsystemIssuers    = "issuers"    // issuer trust line graphs
+	SubsystemPeers      = "peers"      // peer collection
+	SubsystemResolver   = "resolver"   // GeoLite downloads and paid geolocation lookups
+)
+
+// DefaultDestination is the limits key applied to hosts without their own
+// entry. Each such host still gets its own budget.
+const DefaultDestination = "*"
+
+// Manager enforces a request ceiling per destination host, as a token
+// bucket, and shares it fairly between subsystems: when several are
+// waiting for the same host, the next request goes to the subsystem served
+// least recently, so an idle budget is there for whoever needs it but a
+// busy one cannot be monopolized. Hosts without a limit are not throttled.
+// A nil Manager allows everything.
+type Manager struct {
+	limits       map[string]models.OutboundBudget
+	clock        clock.Clock
+	mu           sync.Mutex
+	destinations map[string]*destination
+}
+
+// destination is the budget of one host.
+type destination struct {
+	rate    float64 // tokens per second
+	burst   float64
+	tokens  float64
+	updated time.Time
+
+	waiting   map[string]int    // subsystem -> requests waiting
+	lastGrant map[string]uint64 // subsystem -> grant sequence
+	grants    uint64
+	changed   chan struct{} // closed and replaced whenever waiters should look again
+}
+
+// NewManager returns a manager enforcing limits, keyed by host name or
+// DefaultDestination. A nil clock uses the system clock.
+func NewManager(limits map[string]models.OutboundBudget, clk clock.Clock) *Manager {
+	normalized := make(map[string]models.OutboundBudget, len(limits))
+	for host, limit := range limits {
+		normalized[strings.ToLower(strings.TrimSpace(host))] = limit
+	}
+	return &Manager{
+		limits:       normalized,
+		clock:        clock.OrReal(clk),
+		destinations: make(map[string]*destination),
+	}
+}
+
+// Wait blocks until subsystem may send a request to host, or ctx ends.
+func (m *Manager) Wait(ctx context.Context, host, subsystem string) error {
+	if m == nil {
+		return nil
+	}
+	host = strings.ToLower(host)
+	start := m.clock.Now()
+
+	m.mu.Lock()
+	d := m.destinationLocked(host, start)
+	if d == nil {
+		m.mu.Unlock()
+		return nil
+	}
+	d.waiting[subsystem]++
+	for {
+		d.refill(m.clock.Now())
+		if d.tokens >= 1 && d.turn(subsystem) {
+			d.tokens--
+			d.grant(subsystem)
+			m.mu.Unlock()
+			metrics.OutboundBudgetWaitSeconds.WithLabelValues(subsystem).Observe(m.clock.Since(start).Seconds())
+			return nil
+		}
+		// Out of tokens, wait for the next one; otherwise it is another
+		// subsystem's turn, so wait for it to take it.
+		var refilled <-chan time.Time
+		if d.tokens < 1 {
+			refilled = m.clock.After(d.untilToken())
+		}
+		changed := d.changed
+		m.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			m.mu.Lock()
+			d.leave(subsystem)
+			d.notify()
+			m.mu.Unlock()
+			metrics.OutboundBudgetAbandonedTotal.WithLabelValues(subsystem).Inc()
+			return fmt.Errorf("waiting for outbound budget for %s: %w", host, ctx.Err())
+		case <-refilled:
+		case <-changed:
+		}
+		m.mu.Lock()
+	}
+}
+
+// destinationLocked returns host's budget, creating it full on first use,
+// or nil when host has no limit.
+func (m *Manager) destinationLocked(host string, now time.Time) *destination {
+	if d, ok := m.destinations[host]; ok {
+		return d
+	}
+	limit, ok := m.limits[host]
+	if !ok {
+		limit, ok = m.limits[DefaultDestination]
+	}
+	if !ok || limit.RequestsPerSecond <= 0 {
+		return nil
+	}
+	burst := float64(limit.Burst)
+	if burst <= 0 {
+		burst = math.Max(1, math.Ceil(limit.RequestsPerSecond))
+	}
+	d := &destination{
+		rate:      limit.RequestsPerSecond,
+		burst:     burst,
+		tokens:    burst,
+		updated:   now,
+		waiting:   make(map[string]int),
+		lastGrant: make(map[string]uint64),
+		changed:   make(chan struct{}),
+	}
+	m.destinations[host] = d
+	return d
+}
+
+func (d *destination) refill(now time.Time) {
+	if elapsed := now.Sub(d.updated).Seconds(); elapsed > 0 {
+		d.tokens = math.Min(d.burst, d.tokens+elapsed*d.rate)
+	}
+	d.updated = now
+}
+
+// untilToken is how long until a whole token is available.
+func (d *destination) untilToken() time.Duration {
+	wait := time.Duration(math.Ceil((1 - d.tokens) / d.rate * float64(time.Second)))
+	if wait <= 0 {
+		return time.Nanosecond
+	}
+	return wait
+}
+
+// turn reports whether subsystem is the waiting subsystem served least
+// recently. Ties between subsystems never served go by name.
+func (d *destination) turn(subsystem string) bool {
+	mine := d.lastGrant[subsystem]
+	for other, waiting := range d.waiting {
+		if other == subsystem || waiting == 0 {
+			continue
+		}
+		theirs := d.lastGrant[other]
+		if theirs < mine || (theirs == mine && other < subsystem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *destination) grant(subsystem string) {
+	d.grants++
+	d.lastGrant[subsystem] = d.grants
+	d.leave(subsystem)
+	d.notify()
+}
+
+func (d *destination) leave(subsystem string) {
+	if d.waiting[subsystem]--; d.waiting[subsystem] <= 0 {
+		delete(d.waiting, subsystem)
+	}
+}
+
+func (d *destination) notify() {
+	close(d.changed)
+	d.changed = make(chan struct{})
+}
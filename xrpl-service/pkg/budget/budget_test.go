@@ -0,0 +1,133 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestManagerEnforcesDestinationCeiling(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1_700_000_000, 0))
+	m := NewManager(map[string]models.OutboundBudget{"xrplcluster.com": {RequestsPerSecond: 1, Burst: 2}}, clk)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := m.Wait(ctx, "XRPLCluster.com", SubsystemFetcher); err != nil {
+			t.Fatalf("burst request %d failed: %v", i, err)
+		}
+	}
+	done := make(chan error, 1)
+	go func() { done <- m.Wait(ctx, "xrplcluster.com", SubsystemHealth) }()
+	clk.BlockUntil(1)
+	select {
+	case <-done:
+		t.Fatal("expected the third request to wait for a token")
+	default:
+	}
+	clk.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+
+	// Hosts without a limit, and a nil manager, are not throttled.
+	for i := 0; i < 10; i++ {
+		if err := m.Wait(ctx, "vl.ripple.com", SubsystemFetcher); err != nil {
+			t.Fatalf("unlimited host failed: %v", err)
+		}
+	}
+	var unlimited *Manager
+	if err := unlimited.Wait(ctx, "xrplcluster.com", SubsystemFetcher); err != nil {
+		t.Fatalf("nil manager failed: %v", err)
+	}
+}
+
+func TestManagerSharesFairlyBetweenSubsystems(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1_700_000_000, 0))
+	m := NewManager(map[string]models.OutboundBudget{DefaultDestination: {RequestsPerSecond: 1, Burst: 2}}, clk)
+	ctx := context.Background()
+
+	// Enrichment spends the burst, then queues three more requests behind
+	// one from the fetcher.
+	for i := 0; i < 2; i++ {
+		if err := m.Wait(ctx, "xrplcluster.com", SubsystemEnrichment); err != nil {
+			t.Fatal(err)
+		}
+	}
+	granted := make(chan string, 4)
+	wait := func(subsystem string) {
+		if err := m.Wait(ctx, "xrplcluster.com", subsystem); err == nil {
+			granted <- subsystem
+		}
+	}
+	for i := 0; i < 3; i++ {
+		go wait(SubsystemEnrichment)
+	}
+	clk.BlockUntil(3)
+	go wait(SubsystemFetcher)
+	clk.BlockUntil(4)
+
+	clk.Advance(time.Second)
+	if got := <-granted; got != SubsystemFetcher {
+		t.Fatalf("expected the fetcher to get the next token, got %s", got)
+	}
+	clk.BlockUntil(3)
+	clk.Advance(time.Second)
+	if got := <-granted; got != SubsystemEnrichment {
+		t.Fatalf("expected enrichment to get the following token, got %s", got)
+	}
+}
+
+func TestManagerWaitHonorsContext(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1_700_000_000, 0))
+	m := NewManager(map[string]models.OutboundBudget{"xrplcluster.com": {RequestsPerSecond: 0.1}}, clk)
+	if err := m.Wait(context.Background(), "xrplcluster.com", SubsystemHealth); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Wait(ctx, "xrplcluster.com", SubsystemHealth) }()
+	clk.BlockUntil(1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTransportWaitsForBudget(t *testing.T) {
+	var hits atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer upstream.Close()
+
+	clk := clock.NewFake(time.Unix(1_700_000_000, 0))
+	m := NewManager(map[string]models.OutboundBudget{"127.0.0.1": {RequestsPerSecond: 1, Burst: 1}}, clk)
+	client := &http.Client{Transport: Transport(m, SubsystemFetcher, nil)}
+
+	resp, err := client.Get(upstream.URL)
+	if err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the over-budget request to time out, got %v", err)
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected only the first request to reach the host, got %d", hits.Load())
+	}
+
+	if Transport(nil, SubsystemFetcher, nil) != nil {
+		t.Fatal("expected a nil manager to leave the transport alone")
+	}
+}
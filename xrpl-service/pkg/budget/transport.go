@@ -0,0 +1,33 @@
+package budget
+
+import "net/http"
+
+// Transport returns a RoundTripper that waits for m's budget for each
+// request's host on behalf of subsystem before passing the request to base,
+// or to http.DefaultTransport when base is nil. The wait counts toward the
+// client's timeout. With a nil m it returns base.
+func Transport(m *Manager, subsystem string, base http.RoundTripper) http.RoundTripper {
+	if m == nil {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{manager: m, subsystem: subsystem, base: base}
+}
+
+type transport struct {
+	manager   *Manager
+	subsystem string
+	base      http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.manager.Wait(req.Context(), req.URL.Hostname(), t.subsystem); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
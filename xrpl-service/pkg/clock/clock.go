@@ -0,0 +1,49 @@
+// Package clock abstracts time so that tickers, cooldowns and TTLs can be
+// driven deterministically in tests.
+package clock
+
+import "time"
+
+// Clock tells the time and schedules wakeups. Real returns the system clock;
+// tests use a Fake.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks on C like time.Ticker. Reset changes the period,
+// which lets callers reschedule with jitter after each tick.
+type Ticker interface {
+	C() <-chan time.Time
+	Reset(d time.Duration)
+	Stop()
+}
+
+// Real returns the system clock.
+func Real() Clock {
+	return realClock{}
+}
+
+// OrReal returns c, or the system clock when c is nil, for optional Clock
+// fields in options structs.
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real()
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time   { return r.t.C }
+func (r realTicker) Reset(d time.Duration) { r.t.Reset(d) }
+func (r realTicker) Stop()                 { r.t.Stop() }
@@ -0,0 +1,180 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock that only moves when Advance or Set is called. Tickers and
+// After channels fire synchronously from Advance, in deadline order, so a
+// test can step through scheduled work without sleeping.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	added   chan struct{}
+}
+
+// fakeWaiter is a pending After channel or ticker.
+type fakeWaiter struct {
+	deadline time.Time
+	period   time.Duration // zero for After
+	ch       chan time.Time
+	stopped  bool
+}
+
+// NewFake returns a fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now, added: make(chan struct{}, 1)}
+}
+
+// Now returns the fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since returns the fake time elapsed since t.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After returns a channel that receives the fake time once it has advanced
+// by d.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{deadline: f.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- f.now
+		return w.ch
+	}
+	f.addLocked(w)
+	return w.ch
+}
+
+// NewTicker returns a ticker that ticks every d of fake time. Like
+// time.Ticker it drops ticks a slow receiver misses.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{deadline: f.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	f.addLocked(w)
+	return &fakeTicker{clock: f, waiter: w}
+}
+
+// Advance moves the clock forward by d, firing every After channel and
+// ticker whose deadline is reached.
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the clock to t, firing due waiters in deadline order. Moving
+// backwards fires nothing.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for {
+		due := f.nextDueLocked(t)
+		if due == nil {
+			break
+		}
+		f.now = due.deadline
+		select {
+		case due.ch <- f.now:
+		default:
+		}
+		if due.period > 0 {
+			due.deadline = due.deadline.Add(due.period)
+		} else {
+			due.stopped = true
+		}
+	}
+	f.now = t
+	f.pruneLocked()
+}
+
+// BlockUntil waits until at least n After channels or tickers are pending,
+// so a test can advance the clock only once a goroutine under test has
+// scheduled its wakeup.
+func (f *Fake) BlockUntil(n int) {
+	for {
+		f.mu.Lock()
+		f.pruneLocked()
+		pending := len(f.waiters)
+		f.mu.Unlock()
+		if pending >= n {
+			return
+		}
+		<-f.added
+	}
+}
+
+func (f *Fake) addLocked(w *fakeWaiter) {
+	f.waiters = append(f.waiters, w)
+	select {
+	case f.added <- struct{}{}:
+	default:
+	}
+}
+
+// nextDueLocked returns the pending waiter with the earliest deadline at or
+// before t.
+func (f *Fake) nextDueLocked(t time.Time) *fakeWaiter {
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+	})
+	for _, w := range f.waiters {
+		if w.stopped {
+			continue
+		}
+		if w.deadline.After(t) {
+			return nil
+		}
+		return w
+	}
+	return nil
+}
+
+func (f *Fake) pruneLocked() {
+	kept := f.waiters[:0]
+	for _, w := range f.waiters {
+		if !w.stopped {
+			kept = append(kept, w)
+		}
+	}
+	f.waiters = kept
+}
+
+type fakeTicker struct {
+	clock  *Fake
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.waiter.ch }
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Reset")
+	}
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.waiter.period = d
+	t.waiter.deadline = t.clock.now.Add(d)
+	if t.waiter.stopped {
+		t.waiter.stopped = false
+		t.clock.addLocked(t.waiter)
+	}
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.waiter.stopped = true
+	t.clock.pruneLocked()
+}
@@ -0,0 +1,66 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeFiresInDeadlineOrder(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	fake := NewFake(start)
+	ticker := fake.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	after := fake.After(15 * time.Second)
+
+	fake.Advance(9 * time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("ticker fired early")
+	case <-after:
+		t.Fatal("After fired early")
+	default:
+	}
+
+	fake.Advance(6 * time.Second)
+	if tick := <-ticker.C(); !tick.Equal(start.Add(10 * time.Second)) {
+		t.Fatalf("expected a tick at +10s, got %v", tick.Sub(start))
+	}
+	if fired := <-after; !fired.Equal(start.Add(15 * time.Second)) {
+		t.Fatalf("expected After at +15s, got %v", fired.Sub(start))
+	}
+	if now := fake.Now(); !now.Equal(start.Add(15 * time.Second)) {
+		t.Fatalf("expected the clock at +15s, got %v", now.Sub(start))
+	}
+
+	// Missed ticks are dropped, as with time.Ticker.
+	fake.Advance(time.Minute)
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Fatal("expected only one buffered tick")
+	default:
+	}
+
+	ticker.Reset(time.Hour)
+	fake.Advance(59 * time.Minute)
+	select {
+	case <-ticker.C():
+		t.Fatal("expected the reset period to apply")
+	default:
+	}
+	fake.Advance(time.Minute)
+	<-ticker.C()
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	fake := NewFake(time.Unix(0, 0))
+	done := make(chan struct{})
+	go func() {
+		<-fake.After(time.Second)
+		close(done)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	<-done
+}
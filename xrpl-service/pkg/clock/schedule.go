@@ -0,0 +1,58 @@
+package clock
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// Schedule spaces out periodic work so that instances started together with
+// the same interval do not hit upstreams in lockstep.
+type Schedule struct {
+	Interval time.Duration
+
+	// Jitter varies each wait uniformly by up to ±Jitter of Interval, e.g.
+	// 0.1 for ±10%. Zero keeps a fixed period.
+	Jitter float64
+
+	// Splay delays the first wait by a fixed offset; see SplayFor.
+	Splay time.Duration
+
+	// random returns a value in [0, 1); nil uses math/rand.
+	random func() float64
+}
+
+// First returns the wait before the first run after the initial one.
+func (s Schedule) First() time.Duration {
+	return s.Splay + s.Next()
+}
+
+// Next returns the wait before the following run. It is always positive for
+// a positive Interval.
+func (s Schedule) Next() time.Duration {
+	if s.Jitter <= 0 || s.Interval <= 0 {
+		return s.Interval
+	}
+	jitter := min(s.Jitter, 1)
+	random := s.random
+	if random == nil {
+		random = rand.Float64
+	}
+	wait := time.Duration(float64(s.Interval) * (1 + jitter*(2*random()-1)))
+	if wait <= 0 {
+		return s.Interval
+	}
+	return wait
+}
+
+// SplayFor returns an offset in [0, interval) derived from identity. The same
+// identity always gets the same offset, so each instance keeps its own phase
+// across restarts while different instances spread over the interval.
+func SplayFor(identity string, interval time.Duration) time.Duration {
+	if identity == "" || interval <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(identity))
+	return time.Duration(h.Sum64() % uint64(interval))
+}
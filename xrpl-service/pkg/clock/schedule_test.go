@@ -0,0 +1,59 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleJitterStaysInBounds(t *testing.T) {
+	schedule := Schedule{Interval: 100 * time.Second, Jitter: 0.2}
+	for _, random := range []float64{0, 0.25, 0.5, 0.999} {
+		schedule.random = func() float64 { return random }
+		wait := schedule.Next()
+		if wait < 80*time.Second || wait > 120*time.Second {
+			t.Fatalf("random %v: expected a wait within ±20%%, got %v", random, wait)
+		}
+	}
+	schedule.random = func() float64 { return 0 }
+	if wait := schedule.Next(); wait != 80*time.Second {
+		t.Fatalf("expected the lowest draw to wait 80s, got %v", wait)
+	}
+
+	fixed := Schedule{Interval: time.Minute}
+	if fixed.Next() != time.Minute {
+		t.Fatal("expected no jitter to keep the interval")
+	}
+
+	// Full jitter must never produce a non-positive ticker period.
+	full := Schedule{Interval: time.Minute, Jitter: 1, random: func() float64 { return 0 }}
+	if wait := full.Next(); wait <= 0 {
+		t.Fatalf("expected a positive wait, got %v", wait)
+	}
+}
+
+func TestScheduleFirstAddsSplay(t *testing.T) {
+	schedule := Schedule{Interval: time.Minute, Splay: 15 * time.Second}
+	if first := schedule.First(); first != 75*time.Second {
+		t.Fatalf("expected the first wait to include the splay, got %v", first)
+	}
+	if next := schedule.Next(); next != time.Minute {
+		t.Fatalf("expected later waits without the splay, got %v", next)
+	}
+}
+
+func TestSplayForIsStablePerIdentity(t *testing.T) {
+	interval := 5 * time.Minute
+	a := SplayFor("validator-service-a", interval)
+	if again := SplayFor("validator-service-a", interval); again != a {
+		t.Fatalf("expected a stable splay, got %v then %v", a, again)
+	}
+	if a < 0 || a >= interval {
+		t.Fatalf("expected a splay within the interval, got %v", a)
+	}
+	if b := SplayFor("validator-service-b", interval); b == a {
+		t.Fatalf("expected different identities to get different splays, both got %v", a)
+	}
+	if SplayFor("", interval) != 0 || SplayFor("a", 0) != 0 {
+		t.Fatal("expected no splay without an identity or interval")
+	}
+}
@@ -0,0 +1,52 @@
+package geolocation
+
+import (
+	"fmt"
+	"net"
+)
+
+// ResolveDomainASN returns the autonomous system number of the IP a domain
+// resolves to, or 0 without a GeoLite ASN DB. Results are kept for the life
+// of the resolver.
+func (r *Resolver) ResolveDomainASN(rawDomain string) (uint, error) {
+	if r.lookupASNByIP == nil {
+		return 0, nil
+	}
+	domain := normalizeDomain(rawDomain)
+	if domain == "" {
+		return 0, nil
+	}
+
+	r.mu.RLock()
+	asn, ok := r.asnCache[domain]
+	r.mu.RUnlock()
+	if ok {
+		return asn, nil
+	}
+
+	ip, err := r.resolveDomainIP(domain)
+	if err != nil {
+		return 0, err
+	}
+	asn, err = r.lookupASNByIP(ip)
+	if err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	r.asnCache[domain] = asn
+	r.mu.Unlock()
+	return asn, nil
+}
+
+func (r *Resolver) lookupGeoLiteASN(ip string) (uint, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return 0, fmt.Errorf("invalid IP: %s", ip)
+	}
+	r.dbMu.RLock()
+	defer r.dbMu.RUnlock()
+	if r.asnDB == nil {
+		return 0, ErrGeoLiteUnavailable
+	}
+	return r.asnDB.asn(parsed)
+}
@@ -0,0 +1,57 @@
+package geolocation
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+// BenchmarkGeoCacheGrowth reports the heap retained per entry by a cache
+// filled from lookups, as on a long-running instance: accounts spread over a
+// few cities, each lookup returning freshly allocated strings.
+func BenchmarkGeoCacheGrowth(b *testing.B) {
+	const entries = 20000
+	keys := make([]string, entries)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("account:r%033d", i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var retained int64
+	for i := 0; i < b.N; i++ {
+		retained += heapRetainedByCache(keys)
+	}
+	b.ReportMetric(float64(retained)/float64(b.N)/entries, "heap-B/entry")
+}
+
+// heapRetainedByCache fills a new resolver's cache with keys and returns how
+// much the heap grew while the resolver is alive.
+func heapRetainedByCache(keys []string) int64 {
+	locations := []models.GeoLocation{
+		{CountryCode: "US", City: "Ashburn", Latitude: 39.0437, Longitude: -77.4875},
+		{CountryCode: "DE", City: "Frankfurt am Main", Latitude: 50.1109, Longitude: 8.6821},
+		{CountryCode: "JP", City: "Tokyo", Latitude: 35.6895, Longitude: 139.6917},
+		{CountryCode: "SG", City: "Singapore", Latitude: 1.3521, Longitude: 103.8198},
+		{CountryCode: "XX", City: "Unknown"},
+	}
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	r := &Resolver{clock: clock.NewFake(time.Unix(1700000000, 0)), cache: make(map[string]*geoCacheEntry)}
+	for i, key := range keys {
+		geo := locations[i%len(locations)]
+		geo.CountryCode = strings.Clone(geo.CountryCode)
+		geo.City = strings.Clone(geo.City)
+		r.setCachedGeo(key, &geo)
+	}
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(r)
+	return int64(after.HeapAlloc) - int64(before.HeapAlloc)
+}
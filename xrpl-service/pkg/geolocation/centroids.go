@@ -0,0 +1,92 @@
+package geolocation
+
+// countryCentroids are approximate geographic centers of the countries in
+// countryNames, as [latitude, longitude]. They place lookups that resolve to
+// a country but no city, and are accurate to the country only.
+var countryCentroids = map[string][2]float64{
+	"AE": {23.42, 53.85},
+	"AR": {-38.42, -63.62},
+	"AT": {47.52, 14.55},
+	"AU": {-25.27, 133.78},
+	"BD": {23.68, 90.36},
+	"BE": {50.50, 4.47},
+	"BG": {42.73, 25.49},
+	"BH": {25.93, 50.64},
+	"BM": {32.32, -64.76},
+	"BR": {-14.24, -51.93},
+	"CA": {56.13, -106.35},
+	"CH": {46.82, 8.23},
+	"CL": {-35.68, -71.54},
+	"CN": {35.86, 104.20},
+	"CO": {4.57, -74.30},
+	"CR": {9.75, -83.75},
+	"CY": {35.13, 33.43},
+	"CZ": {49.82, 15.47},
+	"DE": {51.17, 10.45},
+	"DK": {56.26, 9.50},
+	"EE": {58.60, 25.01},
+	"EG": {26.82, 30.80},
+	"ES": {40.46, -3.75},
+	"FI": {61.92, 25.75},
+	"FR": {46.23, 2.21},
+	"GB": {55.38, -3.44},
+	"GE": {42.32, 43.36},
+	"GR": {39.07, 21.82},
+	"HK": {22.40, 114.11},
+	"HU": {47.16, 19.50},
+	"ID": {-0.79, 113.92},
+	"IE": {53.41, -8.24},
+	"IL": {31.05, 34.85},
+	"IN": {20.59, 78.96},
+	"IR": {32.43, 53.69},
+	"IS": {64.96, -19.02},
+	"IT": {41.87, 12.57},
+	"JP": {36.20, 138.25},
+	"KE": {-0.02, 37.91},
+	"KP": {40.34, 127.51},
+	"KR": {35.91, 127.77},
+	"KY": {19.51, -80.57},
+	"KZ": {48.02, 66.92},
+	"LI": {47.17, 9.56},
+	"LT": {55.17, 23.88},
+	"LU": {49.82, 6.13},
+	"LV": {56.88, 24.60},
+	"MA": {31.79, -7.09},
+	"MT": {35.94, 14.38},
+	"MX": {23.63, -102.55},
+	"MY": {4.21, 101.98},
+	"NG": {9.08, 8.68},
+	"NL": {52.13, 5.29},
+	"NO": {60.47, 8.47},
+	"NZ": {-40.90, 174.89},
+	"PA": {8.54, -80.78},
+	"PE": {-9.19, -75.02},
+	"PH": {12.88, 121.77},
+	"PK": {30.38, 69.35},
+	"PL": {51.92, 19.15},
+	"PT": {39.40, -8.22},
+	"QA": {25.35, 51.18},
+	"RO": {45.94, 24.97},
+	"RU": {61.52, 105.32},
+	"SA": {23.89, 45.08},
+	"SE": {60.13, 18.64},
+	"SG": {1.35, 103.82},
+	"SK": {48.67, 19.70},
+	"SV": {13.79, -88.90},
+	"TH": {15.87, 100.99},
+	"TR": {38.96, 35.24},
+	"TW": {23.70, 120.96},
+	"UA": {48.38, 31.17},
+	"US": {37.09, -95.71},
+	"UY": {-32.52, -55.77},
+	"VE": {6.42, -66.59},
+	"VN": {14.06, 108.28},
+	"ZA": {-30.56, 22.94},
+}
+
+// CountryCentroid returns the approximate center of the country with the
+// given ISO 3166-1 alpha-2 code, and whether the country is known.
+func CountryCentroid(countryCode string) (lat, lon float64, ok bool) {
+	centroid, ok := countryCentroids[countryCode]
+	return centroid[0], centroid[1], ok
+}
@@ -0,0 +1,16 @@
+package geolocation
+
+import "testing"
+
+func TestCountryCentroidsCoverNamedCountries(t *testing.T) {
+	for code := range countryNames {
+		lat, lon, ok := CountryCentroid(code)
+		if !ok {
+			t.Errorf("country %s has no centroid", code)
+			continue
+		}
+		if !ValidCoordinates(lat, lon) || !OnLand(lat, lon) {
+			t.Errorf("centroid of %s (%v, %v) fails the sanity checks", code, lat, lon)
+		}
+	}
+}
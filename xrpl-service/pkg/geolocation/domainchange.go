@@ -0,0 +1,76 @@
+package geolocation
+
+import (
+	"strings"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// Results of AccountDomainChanged, as counted in
+// xrpl_validator_geolocation_account_domain_changes_total.
+const (
+	DomainChangeResolved = "resolved" // the account was re-resolved at its new domain
+	DomainChangeCleared  = "cleared"  // the account no longer has a domain
+	DomainChangeFailed   = "failed"   // the new domain did not resolve; the next lookup retries
+)
+
+// AccountDomainChanged handles an account's Domain changing from oldDomain
+// to newDomain, as seen in a validated AccountSet. It drops the cached
+// location of the account and the cached domain and IP entries of both
+// domains, then re-resolves the account at its new domain, so a relocated
+// operator does not stay at its old location for as long as the cache
+// lives. An empty newDomain marks the account as having no domain.
+func (r *Resolver) AccountDomainChanged(account, oldDomain, newDomain string) string {
+	account = strings.TrimSpace(account)
+	oldDomain, newDomain = normalizeDomain(oldDomain), normalizeDomain(newDomain)
+	if account == "" || oldDomain == newDomain {
+		return ""
+	}
+
+	keys := []string{"account:" + account}
+	for _, domain := range []string{oldDomain, newDomain} {
+		if domain == "" {
+			continue
+		}
+		keys = append(keys, "domain:"+domain)
+		// The IP lookup is best effort: a domain that no longer resolves
+		// leaves nothing to invalidate.
+		if ip, err := r.resolveDomainIP(domain); err == nil {
+			keys = append(keys, "ip:"+ip)
+		}
+	}
+	r.mu.Lock()
+	for _, key := range keys {
+		delete(r.cache, key)
+	}
+	r.mu.Unlock()
+
+	result := DomainChangeCleared
+	if newDomain == "" {
+		r.markAccountMissing(account)
+	} else if geo, err := r.ResolveDomainGeo(newDomain); err != nil || geo == nil {
+		result = DomainChangeFailed
+		r.clearMissingAccount(account)
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"account": account,
+			"domain":  newDomain,
+		}).Debug("Failed to re-resolve account at its new domain")
+	} else {
+		result = DomainChangeResolved
+		r.setCachedGeo("account:"+account, geo)
+		r.clearMissingAccount(account)
+	}
+	if err := r.persistCache(); err != nil {
+		r.logger.WithError(err).Warn("Failed to persist geolocation cache")
+	}
+
+	metrics.GeolocationAccountDomainChangesTotal.WithLabelValues(result).Inc()
+	r.logger.WithFields(logrus.Fields{
+		"account":    account,
+		"old_domain": oldDomain,
+		"new_domain": newDomain,
+		"result":     result,
+	}).Info("Account domain changed; invalidated cached locations")
+	return result
+}
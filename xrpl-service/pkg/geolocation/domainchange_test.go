@@ -0,0 +1,53 @@
+package geolocation
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+)
+
+func TestAccountDomainChangedInvalidatesAndReresolves(t *testing.T) {
+	resolver := newTestResolver(t, filepath.Join(t.TempDir(), "geo-cache.json"))
+	ips := map[string]string{"old.example": "1.1.1.1", "new.example": "2.2.2.2"}
+	cities := map[string]string{"1.1.1.1": "Paris", "2.2.2.2": "Tokyo"}
+	resolver.dnsLookup = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP(ips[host])}, nil
+	}
+	resolver.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+		return &models.GeoLocation{CountryCode: "XX", City: cities[ip]}, nil
+	}
+
+	old, err := resolver.ResolveDomainGeo("old.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver.setCachedGeo("account:rAcct", old)
+
+	if result := resolver.AccountDomainChanged("rAcct", "old.example", "new.example"); result != DomainChangeResolved {
+		t.Fatalf("expected the account to be re-resolved, got %q", result)
+	}
+	if geo, ok := resolver.getCachedGeo("account:rAcct"); !ok || geo.City != "Tokyo" {
+		t.Fatalf("expected the account at its new domain, got %+v", geo)
+	}
+	for _, key := range []string{"domain:old.example", "ip:1.1.1.1"} {
+		if _, ok := resolver.getCachedGeo(key); ok {
+			t.Fatalf("expected %s to be invalidated", key)
+		}
+	}
+
+	if result := resolver.AccountDomainChanged("rAcct", "new.example", ""); result != DomainChangeCleared {
+		t.Fatalf("expected the domain to be cleared, got %q", result)
+	}
+	if _, ok := resolver.getCachedGeo("account:rAcct"); ok {
+		t.Fatal("expected no cached location for an account without a domain")
+	}
+	if !resolver.isAccountMissing("rAcct") {
+		t.Fatal("expected the account to be marked as having no domain")
+	}
+
+	if result := resolver.AccountDomainChanged("rAcct", "same.example", "same.example"); result != "" {
+		t.Fatalf("expected an unchanged domain to be ignored, got %q", result)
+	}
+}
@@ -0,0 +1,89 @@
+//go:build !nogeolite
+
+package geolocation
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/intern"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/oschwald/geoip2-golang"
+)
+
+// GeoLiteBuiltIn reports whether GeoLite MMDB support is compiled in. Build
+// with -tags nogeolite to leave it and its dependencies out.
+const GeoLiteBuiltIn = true
+
+// geoLiteDB is a GeoLite2 City or ASN database.
+type geoLiteDB struct {
+	reader *geoip2.Reader
+}
+
+func openIPDB(path string) (ipDB, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &geoLiteDB{reader: reader}, nil
+}
+
+func (d *geoLiteDB) city(ip net.IP) (*models.GeoLocation, error) {
+	record, err := d.reader.City(ip)
+	if err != nil {
+		return nil, fmt.Errorf("GeoLite lookup failed for %s: %w", ip, err)
+	}
+	return locationFromRecord(ip.String(), record)
+}
+
+func (d *geoLiteDB) asn(ip net.IP) (uint, error) {
+	record, err := d.reader.ASN(ip)
+	if err != nil {
+		return 0, fmt.Errorf("GeoLite ASN lookup failed for %s: %w", ip, err)
+	}
+	return record.AutonomousSystemNumber, nil
+}
+
+func (d *geoLiteDB) buildTime() time.Time {
+	return time.Unix(int64(d.reader.Metadata().BuildEpoch), 0)
+}
+
+func (d *geoLiteDB) Close() error {
+	return d.reader.Close()
+}
+
+// locationFromRecord converts a GeoLite City record. A record with a
+// country but no coordinates is placed at the country's centroid and
+// flagged approximate, so it at least shows in the right country.
+func locationFromRecord(ip string, record *geoip2.City) (*models.GeoLocation, error) {
+	countryCode := strings.ToUpper(strings.TrimSpace(record.Country.IsoCode))
+	lat := record.Location.Latitude
+	lng := record.Location.Longitude
+	approximate := false
+	if lat == 0 && lng == 0 {
+		var ok bool
+		if lat, lng, ok = CountryCentroid(countryCode); !ok {
+			return nil, fmt.Errorf("GeoLite record has no coordinates for %s: %w", ip, xrpl.ErrNotFound)
+		}
+		approximate = true
+	}
+
+	if countryCode == "" {
+		countryCode = "XX"
+	}
+	city := strings.TrimSpace(record.City.Names["en"])
+	if city == "" || approximate {
+		city = "Unknown"
+	}
+
+	return &models.GeoLocation{
+		Latitude:    lat,
+		Longitude:   lng,
+		CountryCode: intern.String(countryCode),
+		City:        intern.String(city),
+		Approximate: approximate,
+	}, nil
+}
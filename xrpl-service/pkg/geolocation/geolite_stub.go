@@ -0,0 +1,12 @@
+//go:build nogeolite
+
+package geolocation
+
+// GeoLiteBuiltIn reports whether GeoLite MMDB support is compiled in. This
+// build was made with -tags nogeolite, so resolvers answer from their cache
+// only.
+const GeoLiteBuiltIn = false
+
+func openIPDB(path string) (ipDB, error) {
+	return nil, ErrGeoLiteDisabled
+}
@@ -0,0 +1,35 @@
+//go:build !nogeolite
+
+package geolocation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/oschwald/geoip2-golang"
+)
+
+func TestLocationFromRecordFallsBackToCountryCentroid(t *testing.T) {
+	var record geoip2.City
+	record.Country.IsoCode = "de"
+	geo, err := locationFromRecord("192.0.2.1", &record)
+	if err != nil {
+		t.Fatalf("expected a country-only record to resolve, got %v", err)
+	}
+	if !geo.Approximate || geo.CountryCode != "DE" || geo.City != "Unknown" || geo.Latitude != 51.17 || geo.Longitude != 10.45 {
+		t.Fatalf("expected the German centroid flagged approximate, got %+v", geo)
+	}
+
+	record.Location.Latitude = 50.11
+	record.Location.Longitude = 8.68
+	record.City.Names = map[string]string{"en": "Frankfurt am Main"}
+	geo, err = locationFromRecord("192.0.2.1", &record)
+	if err != nil || geo.Approximate || geo.City != "Frankfurt am Main" {
+		t.Fatalf("expected exact coordinates to be kept, got %+v, %v", geo, err)
+	}
+
+	if _, err := locationFromRecord("192.0.2.1", &geoip2.City{}); !errors.Is(err, xrpl.ErrNotFound) {
+		t.Fatalf("expected a record without a country to be not found, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package geolocation
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewResolverWithRetry is NewResolver, except that a GeoLite DB that cannot
+// be downloaded or opened does not fail it. The resolver then starts from
+// its cache alone, so lookups that miss it fail with ErrGeoLiteUnavailable,
+// and tries to open the DB again every retryInterval until it succeeds or
+// the resolver is closed. GeoLiteInitError reports the failure meanwhile.
+func NewResolverWithRetry(logger *logrus.Logger, cfg ResolverConfig, retryInterval time.Duration) *Resolver {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	r, err := NewResolver(logger, cfg)
+	if err == nil {
+		return r
+	}
+
+	cfg = withDefaults(cfg)
+	logger.WithError(err).WithField("retry_interval", retryInterval.String()).
+		Error("GeoLite DB unavailable; starting without geolocation and retrying in the background")
+	r = newResolver(logger, cfg)
+	r.initErr = err
+	r.lookupGeoByIP = r.lookupGeoLiteIP
+	if cfg.ASNDBPath != "" {
+		r.lookupASNByIP = r.lookupGeoLiteASN
+	}
+	r.loadCache()
+	go r.retryInit(retryInterval)
+	return r
+}
+
+// GeoLiteInitError returns why the GeoLite DB has not opened yet, or nil
+// once it has (or when it opened at startup).
+func (r *Resolver) GeoLiteInitError() error {
+	r.dbMu.RLock()
+	defer r.dbMu.RUnlock()
+	return r.initErr
+}
+
+// retryInit opens the GeoLite DB every interval until it succeeds or the
+// resolver is closed.
+func (r *Resolver) retryInit(interval time.Duration) {
+	ticker := r.clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stopChan:
+			return
+		case <-ticker.C():
+		}
+		db, asnDB, err := openGeoLite(r.cfg, r.logger)
+
+		r.dbMu.Lock()
+		select {
+		case <-r.stopChan:
+			// Closed while opening; do not leak the readers
+			r.dbMu.Unlock()
+			if db != nil {
+				db.Close()
+			}
+			if asnDB != nil {
+				asnDB.Close()
+			}
+			return
+		default:
+		}
+		if err != nil {
+			r.initErr = err
+			r.dbMu.Unlock()
+			r.logger.WithError(err).Warn("GeoLite DB still unavailable; geolocation stays degraded")
+			continue
+		}
+		// A scheduled refresh may have swapped in a DB meanwhile
+		oldDB, oldASNDB := r.db, r.asnDB
+		r.db = db
+		r.asnDB = asnDB
+		r.initErr = nil
+		r.dbMu.Unlock()
+		if oldDB != nil {
+			oldDB.Close()
+		}
+		if oldASNDB != nil {
+			oldASNDB.Close()
+		}
+		r.logger.WithField("path", r.dbPath).Info("GeoLite DB opened; geolocation restored")
+		return
+	}
+}
@@ -0,0 +1,66 @@
+//go:build !nogeolite
+
+package geolocation
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestNewResolverWithRetryStartsDegraded(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "geo-cache.json")
+	seed := newTestResolver(t, cachePath)
+	seed.setCachedGeo("domain:seeded.example", &models.GeoLocation{Latitude: 52.52, Longitude: 13.405, CountryCode: "DE", City: "Berlin"})
+	if err := seed.persistCache(); err != nil {
+		t.Fatalf("failed to seed cache: %v", err)
+	}
+
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	dbPath := filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")
+	resolver := NewResolverWithRetry(logrus.New(), ResolverConfig{
+		CachePath:     cachePath,
+		GeoLiteDBPath: dbPath,
+		Clock:         fake,
+	}, time.Minute)
+	defer resolver.Close()
+	resolver.dnsLookup = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("192.0.2.7")}, nil
+	}
+
+	if err := resolver.GeoLiteInitError(); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected the missing DB to be reported, got %v", err)
+	}
+	if geo, err := resolver.ResolveDomainGeo("seeded.example"); err != nil || geo.City != "Berlin" {
+		t.Fatalf("expected the cached location, got %+v (%v)", geo, err)
+	}
+	if _, err := resolver.ResolveDomainGeo("unseeded.example"); !errors.Is(err, ErrGeoLiteUnavailable) {
+		t.Fatalf("expected ErrGeoLiteUnavailable, got %v", err)
+	}
+
+	// The next attempt finds a file, but not a readable DB
+	if err := os.WriteFile(dbPath, []byte("not an mmdb file"), 0o644); err != nil {
+		t.Fatalf("failed to write DB: %v", err)
+	}
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		err := resolver.GeoLiteInitError()
+		if err != nil && strings.Contains(err.Error(), "failed to open") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the retry to report the unreadable DB, got %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,222 @@
+package geolocation
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when no supported language is requested. Names
+// missing from a language's table also fall back to it.
+const DefaultLanguage = "en"
+
+// Languages are the languages of the bundled country and city names: English,
+// Spanish, French, German, Portuguese, Japanese and Simplified Chinese.
+var Languages = []string{"en", "es", "fr", "de", "pt", "ja", "zh"}
+
+// localized holds one name per language code.
+type localized map[string]string
+
+// countryNames covers the countries validators and busy accounts are
+// commonly located in, keyed by ISO 3166-1 alpha-2 code.
+var countryNames = map[string]localized{
+	"AE": {"en": "United Arab Emirates", "es": "Emiratos Árabes Unidos", "fr": "Émirats arabes unis", "de": "Vereinigte Arabische Emirate", "pt": "Emirados Árabes Unidos", "ja": "アラブ首長国連邦", "zh": "阿拉伯联合酋长国"},
+	"AR": {"en": "Argentina", "es": "Argentina", "fr": "Argentine", "de": "Argentinien", "pt": "Argentina", "ja": "アルゼンチン", "zh": "阿根廷"},
+	"AT": {"en": "Austria", "es": "Austria", "fr": "Autriche", "de": "Österreich", "pt": "Áustria", "ja": "オーストリア", "zh": "奥地利"},
+	"AU": {"en": "Australia", "es": "Australia", "fr": "Australie", "de": "Australien", "pt": "Austrália", "ja": "オーストラリア", "zh": "澳大利亚"},
+	"BD": {"en": "Bangladesh", "es": "Bangladés", "fr": "Bangladesh", "de": "Bangladesch", "pt": "Bangladesh", "ja": "バングラデシュ", "zh": "孟加拉国"},
+	"BE": {"en": "Belgium", "es": "Bélgica", "fr": "Belgique", "de": "Belgien", "pt": "Bélgica", "ja": "ベルギー", "zh": "比利时"},
+	"BG": {"en": "Bulgaria", "es": "Bulgaria", "fr": "Bulgarie", "de": "Bulgarien", "pt": "Bulgária", "ja": "ブルガリア", "zh": "保加利亚"},
+	"BH": {"en": "Bahrain", "es": "Baréin", "fr": "Bahreïn", "de": "Bahrain", "pt": "Barém", "ja": "バーレーン", "zh": "巴林"},
+	"BM": {"en": "Bermuda", "es": "Bermudas", "fr": "Bermudes", "de": "Bermuda", "pt": "Bermudas", "ja": "バミューダ", "zh": "百慕大"},
+	"BR": {"en": "Brazil", "es": "Brasil", "fr": "Brésil", "de": "Brasilien", "pt": "Brasil", "ja": "ブラジル", "zh": "巴西"},
+	"CA": {"en": "Canada", "es": "Canadá", "fr": "Canada", "de": "Kanada", "pt": "Canadá", "ja": "カナダ", "zh": "加拿大"},
+	"CH": {"en": "Switzerland", "es": "Suiza", "fr": "Suisse", "de": "Schweiz", "pt": "Suíça", "ja": "スイス", "zh": "瑞士"},
+	"CL": {"en": "Chile", "es": "Chile", "fr": "Chili", "de": "Chile", "pt": "Chile", "ja": "チリ", "zh": "智利"},
+	"CN": {"en": "China", "es": "China", "fr": "Chine", "de": "China", "pt": "China", "ja": "中国", "zh": "中国"},
+	"CO": {"en": "Colombia", "es": "Colombia", "fr": "Colombie", "de": "Kolumbien", "pt": "Colômbia", "ja": "コロンビア", "zh": "哥伦比亚"},
+	"CR": {"en": "Costa Rica", "es": "Costa Rica", "fr": "Costa Rica", "de": "Costa Rica", "pt": "Costa Rica", "ja": "コスタリカ", "zh": "哥斯达黎加"},
+	"CY": {"en": "Cyprus", "es": "Chipre", "fr": "Chypre", "de": "Zypern", "pt": "Chipre", "ja": "キプロス", "zh": "塞浦路斯"},
+	"CZ": {"en": "Czechia", "es": "Chequia", "fr": "Tchéquie", "de": "Tschechien", "pt": "Chéquia", "ja": "チェコ", "zh": "捷克"},
+	"DE": {"en": "Germany", "es": "Alemania", "fr": "Allemagne", "de": "Deutschland", "pt": "Alemanha", "ja": "ドイツ", "zh": "德国"},
+	"DK": {"en": "Denmark", "es": "Dinamarca", "fr": "Danemark", "de": "Dänemark", "pt": "Dinamarca", "ja": "デンマーク", "zh": "丹麦"},
+	"EE": {"en": "Estonia", "es": "Estonia", "fr": "Estonie", "de": "Estland", "pt": "Estônia", "ja": "エストニア", "zh": "爱沙尼亚"},
+	"EG": {"en": "Egypt", "es": "Egipto", "fr": "Égypte", "de": "Ägypten", "pt": "Egito", "ja": "エジプト", "zh": "埃及"},
+	"ES": {"en": "Spain", "es": "España", "fr": "Espagne", "de": "Spanien", "pt": "Espanha", "ja": "スペイン", "zh": "西班牙"},
+	"FI": {"en": "Finland", "es": "Finlandia", "fr": "Finlande", "de": "Finnland", "pt": "Finlândia", "ja": "フィンランド", "zh": "芬兰"},
+	"FR": {"en": "France", "es": "Francia", "fr": "France", "de": "Frankreich", "pt": "França", "ja": "フランス", "zh": "法国"},
+	"GB": {"en": "United Kingdom", "es": "Reino Unido", "fr": "Royaume-Uni", "de": "Vereinigtes Königreich", "pt": "Reino Unido", "ja": "イギリス", "zh": "英国"},
+	"GE": {"en": "Georgia", "es": "Georgia", "fr": "Géorgie", "de": "Georgien", "pt": "Geórgia", "ja": "ジョージア", "zh": "格鲁吉亚"},
+	"GR": {"en": "Greece", "es": "Grecia", "fr": "Grèce", "de": "Griechenland", "pt": "Grécia", "ja": "ギリシャ", "zh": "希腊"},
+	"HK": {"en": "Hong Kong", "es": "Hong Kong", "fr": "Hong Kong", "de": "Hongkong", "pt": "Hong Kong", "ja": "香港", "zh": "香港"},
+	"HU": {"en": "Hungary", "es": "Hungría", "fr": "Hongrie", "de": "Ungarn", "pt": "Hungria", "ja": "ハンガリー", "zh": "匈牙利"},
+	"ID": {"en": "Indonesia", "es": "Indonesia", "fr": "Indonésie", "de": "Indonesien", "pt": "Indonésia", "ja": "インドネシア", "zh": "印度尼西亚"},
+	"IE": {"en": "Ireland", "es": "Irlanda", "fr": "Irlande", "de": "Irland", "pt": "Irlanda", "ja": "アイルランド", "zh": "爱尔兰"},
+	"IL": {"en": "Israel", "es": "Israel", "fr": "Israël", "de": "Israel", "pt": "Israel", "ja": "イスラエル", "zh": "以色列"},
+	"IN": {"en": "India", "es": "India", "fr": "Inde", "de": "Indien", "pt": "Índia", "ja": "インド", "zh": "印度"},
+	"IR": {"en": "Iran", "es": "Irán", "fr": "Iran", "de": "Iran", "pt": "Irã", "ja": "イラン", "zh": "伊朗"},
+	"IS": {"en": "Iceland", "es": "Islandia", "fr": "Islande", "de": "Island", "pt": "Islândia", "ja": "アイスランド", "zh": "冰岛"},
+	"IT": {"en": "Italy", "es": "Italia", "fr": "Italie", "de": "Italien", "pt": "Itália", "ja": "イタリア", "zh": "意大利"},
+	"JP": {"en": "Japan", "es": "Japón", "fr": "Japon", "de": "Japan", "pt": "Japão", "ja": "日本", "zh": "日本"},
+	"KE": {"en": "Kenya", "es": "Kenia", "fr": "Kenya", "de": "Kenia", "pt": "Quênia", "ja": "ケニア", "zh": "肯尼亚"},
+	"KP": {"en": "North Korea", "es": "Corea del Norte", "fr": "Corée du Nord", "de": "Nordkorea", "pt": "Coreia do Norte", "ja": "北朝鮮", "zh": "朝鲜"},
+	"KR": {"en": "South Korea", "es": "Corea del Sur", "fr": "Corée du Sud", "de": "Südkorea", "pt": "Coreia do Sul", "ja": "韓国", "zh": "韩国"},
+	"KY": {"en": "Cayman Islands", "es": "Islas Caimán", "fr": "Îles Caïmans", "de": "Kaimaninseln", "pt": "Ilhas Cayman", "ja": "ケイマン諸島", "zh": "开曼群岛"},
+	"KZ": {"en": "Kazakhstan", "es": "Kazajistán", "fr": "Kazakhstan", "de": "Kasachstan", "pt": "Cazaquistão", "ja": "カザフスタン", "zh": "哈萨克斯坦"},
+	"LI": {"en": "Liechtenstein", "es": "Liechtenstein", "fr": "Liechtenstein", "de": "Liechtenstein", "pt": "Liechtenstein", "ja": "リヒテンシュタイン", "zh": "列支敦士登"},
+	"LT": {"en": "Lithuania", "es": "Lituania", "fr": "Lituanie", "de": "Litauen", "pt": "Lituânia", "ja": "リトアニア", "zh": "立陶宛"},
+	"LU": {"en": "Luxembourg", "es": "Luxemburgo", "fr": "Luxembourg", "de": "Luxemburg", "pt": "Luxemburgo", "ja": "ルクセンブルク", "zh": "卢森堡"},
+	"LV": {"en": "Latvia", "es": "Letonia", "fr": "Lettonie", "de": "Lettland", "pt": "Letônia", "ja": "ラトビア", "zh": "拉脱维亚"},
+	"MA": {"en": "Morocco", "es": "Marruecos", "fr": "Maroc", "de": "Marokko", "pt": "Marrocos", "ja": "モロッコ", "zh": "摩洛哥"},
+	"MT": {"en": "Malta", "es": "Malta", "fr": "Malte", "de": "Malta", "pt": "Malta", "ja": "マルタ", "zh": "马耳他"},
+	"MX": {"en": "Mexico", "es": "México", "fr": "Mexique", "de": "Mexiko", "pt": "México", "ja": "メキシコ", "zh": "墨西哥"},
+	"MY": {"en": "Malaysia", "es": "Malasia", "fr": "Malaisie", "de": "Malaysia", "pt": "Malásia", "ja": "マレーシア", "zh": "马来西亚"},
+	"NG": {"en": "Nigeria", "es": "Nigeria", "fr": "Nigeria", "de": "Nigeria", "pt": "Nigéria", "ja": "ナイジェリア", "zh": "尼日利亚"},
+	"NL": {"en": "Netherlands", "es": "Países Bajos", "fr": "Pays-Bas", "de": "Niederlande", "pt": "Países Baixos", "ja": "オランダ", "zh": "荷兰"},
+	"NO": {"en": "Norway", "es": "Noruega", "fr": "Norvège", "de": "Norwegen", "pt": "Noruega", "ja": "ノルウェー", "zh": "挪威"},
+	"NZ": {"en": "New Zealand", "es": "Nueva Zelanda", "fr": "Nouvelle-Zélande", "de": "Neuseeland", "pt": "Nova Zelândia", "ja": "ニュージーランド", "zh": "新西兰"},
+	"PA": {"en": "Panama", "es": "Panamá", "fr": "Panama", "de": "Panama", "pt": "Panamá", "ja": "パナマ", "zh": "巴拿马"},
+	"PE": {"en": "Peru", "es": "Perú", "fr": "Pérou", "de": "Peru", "pt": "Peru", "ja": "ペルー", "zh": "秘鲁"},
+	"PH": {"en": "Philippines", "es": "Filipinas", "fr": "Philippines", "de": "Philippinen", "pt": "Filipinas", "ja": "フィリピン", "zh": "菲律宾"},
+	"PK": {"en": "Pakistan", "es": "Pakistán", "fr": "Pakistan", "de": "Pakistan", "pt": "Paquistão", "ja": "パキスタン", "zh": "巴基斯坦"},
+	"PL": {"en": "Poland", "es": "Polonia", "fr": "Pologne", "de": "Polen", "pt": "Polônia", "ja": "ポーランド", "zh": "波兰"},
+	"PT": {"en": "Portugal", "es": "Portugal", "fr": "Portugal", "de": "Portugal", "pt": "Portugal", "ja": "ポルトガル", "zh": "葡萄牙"},
+	"QA": {"en": "Qatar", "es": "Catar", "fr": "Qatar", "de": "Katar", "pt": "Catar", "ja": "カタール", "zh": "卡塔尔"},
+	"RO": {"en": "Romania", "es": "Rumania", "fr": "Roumanie", "de": "Rumänien", "pt": "Romênia", "ja": "ルーマニア", "zh": "罗马尼亚"},
+	"RU": {"en": "Russia", "es": "Rusia", "fr": "Russie", "de": "Russland", "pt": "Rússia", "ja": "ロシア", "zh": "俄罗斯"},
+	"SA": {"en": "Saudi Arabia", "es": "Arabia Saudí", "fr": "Arabie saoudite", "de": "Saudi-Arabien", "pt": "Arábia Saudita", "ja": "サウジアラビア", "zh": "沙特阿拉伯"},
+	"SE": {"en": "Sweden", "es": "Suecia", "fr": "Suède", "de": "Schweden", "pt": "Suécia", "ja": "スウェーデン", "zh": "瑞典"},
+	"SG": {"en": "Singapore", "es": "Singapur", "fr": "Singapour", "de": "Singapur", "pt": "Singapura", "ja": "シンガポール", "zh": "新加坡"},
+	"SK": {"en": "Slovakia", "es": "Eslovaquia", "fr": "Slovaquie", "de": "Slowakei", "pt": "Eslováquia", "ja": "スロバキア", "zh": "斯洛伐克"},
+	"SV": {"en": "El Salvador", "es": "El Salvador", "fr": "Salvador", "de": "El Salvador", "pt": "El Salvador", "ja": "エルサルバドル", "zh": "萨尔瓦多"},
+	"TH": {"en": "Thailand", "es": "Tailandia", "fr": "Thaïlande", "de": "Thailand", "pt": "Tailândia", "ja": "タイ", "zh": "泰国"},
+	"TR": {"en": "Türkiye", "es": "Turquía", "fr": "Turquie", "de": "Türkei", "pt": "Turquia", "ja": "トルコ", "zh": "土耳其"},
+	"TW": {"en": "Taiwan", "es": "Taiwán", "fr": "Taïwan", "de": "Taiwan", "pt": "Taiwan", "ja": "台湾", "zh": "台湾"},
+	"UA": {"en": "Ukraine", "es": "Ucrania", "fr": "Ukraine", "de": "Ukraine", "pt": "Ucrânia", "ja": "ウクライナ", "zh": "乌克兰"},
+	"US": {"en": "United States", "es": "Estados Unidos", "fr": "États-Unis", "de": "Vereinigte Staaten", "pt": "Estados Unidos", "ja": "アメリカ合衆国", "zh": "美国"},
+	"UY": {"en": "Uruguay", "es": "Uruguay", "fr": "Uruguay", "de": "Uruguay", "pt": "Uruguai", "ja": "ウルグアイ", "zh": "乌拉圭"},
+	"VE": {"en": "Venezuela", "es": "Venezuela", "fr": "Venezuela", "de": "Venezuela", "pt": "Venezuela", "ja": "ベネズエラ", "zh": "委内瑞拉"},
+	"VN": {"en": "Vietnam", "es": "Vietnam", "fr": "Viêt Nam", "de": "Vietnam", "pt": "Vietnã", "ja": "ベトナム", "zh": "越南"},
+	"ZA": {"en": "South Africa", "es": "Sudáfrica", "fr": "Afrique du Sud", "de": "Südafrika", "pt": "África do Sul", "ja": "南アフリカ", "zh": "南非"},
+}
+
+// cityNames covers common data center and financial hub cities, keyed by
+// their English GeoLite name. Languages that use the English name are
+// omitted.
+var cityNames = map[string]localized{
+	"Amsterdam":         {"es": "Ámsterdam", "pt": "Amsterdã", "ja": "アムステルダム", "zh": "阿姆斯特丹"},
+	"Ashburn":           {"ja": "アッシュバーン", "zh": "阿什本"},
+	"Beijing":           {"es": "Pekín", "fr": "Pékin", "de": "Peking", "pt": "Pequim", "ja": "北京", "zh": "北京"},
+	"Berlin":            {"es": "Berlín", "pt": "Berlim", "ja": "ベルリン", "zh": "柏林"},
+	"Brussels":          {"es": "Bruselas", "fr": "Bruxelles", "de": "Brüssel", "pt": "Bruxelas", "ja": "ブリュッセル", "zh": "布鲁塞尔"},
+	"Buenos Aires":      {"ja": "ブエノスアイレス", "zh": "布宜诺斯艾利斯"},
+	"Chicago":           {"ja": "シカゴ", "zh": "芝加哥"},
+	"Copenhagen":        {"es": "Copenhague", "fr": "Copenhague", "de": "Kopenhagen", "pt": "Copenhague", "ja": "コペンハーゲン", "zh": "哥本哈根"},
+	"Dubai":             {"es": "Dubái", "fr": "Dubaï", "ja": "ドバイ", "zh": "迪拜"},
+	"Dublin":            {"es": "Dublín", "ja": "ダブリン", "zh": "都柏林"},
+	"Frankfurt am Main": {"es": "Fráncfort del Meno", "fr": "Francfort-sur-le-Main", "ja": "フランクフルト", "zh": "法兰克福"},
+	"Geneva":            {"es": "Ginebra", "fr": "Genève", "de": "Genf", "pt": "Genebra", "ja": "ジュネーヴ", "zh": "日内瓦"},
+	"Helsinki":          {"ja": "ヘルシンキ", "zh": "赫尔辛基"},
+	"Hong Kong":         {"de": "Hongkong", "ja": "香港", "zh": "香港"},
+	"Johannesburg":      {"es": "Johannesburgo", "fr": "Johannesbourg", "pt": "Joanesburgo", "ja": "ヨハネスブルグ", "zh": "约翰内斯堡"},
+	"Lisbon":            {"es": "Lisboa", "fr": "Lisbonne", "de": "Lissabon", "pt": "Lisboa", "ja": "リスボン", "zh": "里斯本"},
+	"London":            {"es": "Londres", "fr": "Londres", "pt": "Londres", "ja": "ロンドン", "zh": "伦敦"},
+	"Los Angeles":       {"es": "Los Ángeles", "ja": "ロサンゼルス", "zh": "洛杉矶"},
+	"Madrid":            {"ja": "マドリード", "zh": "马德里"},
+	"Mexico City":       {"es": "Ciudad de México", "fr": "Mexico", "de": "Mexiko-Stadt", "pt": "Cidade do México", "ja": "メキシコシティ", "zh": "墨西哥城"},
+	"Miami":             {"ja": "マイアミ", "zh": "迈阿密"},
+	"Milan":             {"es": "Milán", "de": "Mailand", "pt": "Milão", "ja": "ミラノ", "zh": "米兰"},
+	"Moscow":            {"es": "Moscú", "fr": "Moscou", "de": "Moskau", "pt": "Moscou", "ja": "モスクワ", "zh": "莫斯科"},
+	"Mumbai":            {"ja": "ムンバイ", "zh": "孟买"},
+	"Munich":            {"es": "Múnich", "de": "München", "pt": "Munique", "ja": "ミュンヘン", "zh": "慕尼黑"},
+	"New York":          {"es": "Nueva York", "pt": "Nova York", "ja": "ニューヨーク", "zh": "纽约"},
+	"Oslo":              {"ja": "オスロ", "zh": "奥斯陆"},
+	"Paris":             {"es": "París", "ja": "パリ", "zh": "巴黎"},
+	"San Francisco":     {"ja": "サンフランシスコ", "zh": "旧金山"},
+	"Seoul":             {"es": "Seúl", "fr": "Séoul", "pt": "Seul", "ja": "ソウル", "zh": "首尔"},
+	"Shanghai":          {"es": "Shanghái", "pt": "Xangai", "ja": "上海", "zh": "上海"},
+	"Singapore":         {"es": "Singapur", "fr": "Singapour", "de": "Singapur", "pt": "Singapura", "ja": "シンガポール", "zh": "新加坡"},
+	"Stockholm":         {"es": "Estocolmo", "pt": "Estocolmo", "ja": "ストックホルム", "zh": "斯德哥尔摩"},
+	"São Paulo":         {"ja": "サンパウロ", "zh": "圣保罗"},
+	"Sydney":            {"es": "Sídney", "ja": "シドニー", "zh": "悉尼"},
+	"Taipei":            {"es": "Taipéi", "de": "Taipeh", "pt": "Taipé", "ja": "台北", "zh": "台北"},
+	"Tokyo":             {"es": "Tokio", "de": "Tokio", "pt": "Tóquio", "ja": "東京", "zh": "东京"},
+	"Toronto":           {"ja": "トロント", "zh": "多伦多"},
+	"Vienna":            {"es": "Viena", "fr": "Vienne", "de": "Wien", "pt": "Viena", "ja": "ウィーン", "zh": "维也纳"},
+	"Warsaw":            {"es": "Varsovia", "fr": "Varsovie", "de": "Warschau", "pt": "Varsóvia", "ja": "ワルシャワ", "zh": "华沙"},
+	"Zurich":            {"es": "Zúrich", "de": "Zürich", "pt": "Zurique", "ja": "チューリッヒ", "zh": "苏黎世"},
+}
+
+// CountryName returns the name of an ISO 3166-1 alpha-2 country code in
+// lang, falling back to English, or "" for codes outside the table.
+func CountryName(code, lang string) string {
+	names, ok := countryNames[strings.ToUpper(code)]
+	if !ok {
+		return ""
+	}
+	if name, ok := names[lang]; ok {
+		return name
+	}
+	return names[DefaultLanguage]
+}
+
+// CountryCodes returns the codes of the bundled country names, sorted.
+func CountryCodes() []string {
+	codes := make([]string, 0, len(countryNames))
+	for code := range countryNames {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// CityName returns the name of a city, as resolved from GeoLite in English,
+// in lang. Cities outside the table keep their English name.
+func CityName(city, lang string) string {
+	if name, ok := cityNames[city][lang]; ok {
+		return name
+	}
+	return city
+}
+
+// MatchLanguage picks the supported language for a lang query parameter or,
+// when that is empty, an Accept-Language header. Region and script subtags
+// are ignored, so pt-BR matches pt and zh-Hans matches zh. It returns
+// DefaultLanguage when nothing matches.
+func MatchLanguage(query, acceptLanguage string) string {
+	if query != "" {
+		if lang, ok := supportedLanguage(query); ok {
+			return lang
+		}
+		return DefaultLanguage
+	}
+
+	best, bestQ := DefaultLanguage, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if lang, ok := supportedLanguage(tag); ok && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// supportedLanguage returns the supported language of a language tag.
+func supportedLanguage(tag string) (string, bool) {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	primary, _, _ = strings.Cut(primary, "_")
+	for _, lang := range Languages {
+		if lang == primary {
+			return lang, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,51 @@
+package geolocation
+
+import "testing"
+
+func TestMatchLanguage(t *testing.T) {
+	cases := []struct {
+		query, header, want string
+	}{
+		{"", "", "en"},
+		{"es", "de-DE", "es"},
+		{"xx", "de-DE", "en"},
+		{"", "pt-BR,pt;q=0.9,en;q=0.8", "pt"},
+		{"", "ko-KR, de;q=0.5, fr;q=0.7", "fr"},
+		{"", "zh-Hans-CN", "zh"},
+		{"", "fr;q=bad, ja;q=0.2", "ja"},
+	}
+	for _, tc := range cases {
+		if got := MatchLanguage(tc.query, tc.header); got != tc.want {
+			t.Errorf("MatchLanguage(%q, %q) = %q, want %q", tc.query, tc.header, got, tc.want)
+		}
+	}
+}
+
+func TestCountryAndCityNames(t *testing.T) {
+	if got := CountryName("de", "ja"); got != "ドイツ" {
+		t.Fatalf("expected Japanese name for DE, got %q", got)
+	}
+	if got := CountryName("DE", "ko"); got != "Germany" {
+		t.Fatalf("expected the English fallback, got %q", got)
+	}
+	if got := CountryName("XX", "en"); got != "" {
+		t.Fatalf("expected no name for an unknown code, got %q", got)
+	}
+	if got := CityName("Frankfurt am Main", "fr"); got != "Francfort-sur-le-Main" {
+		t.Fatalf("expected French city name, got %q", got)
+	}
+	if got := CityName("Berlin", "de"); got != "Berlin" {
+		t.Fatalf("expected the English name where it is the same, got %q", got)
+	}
+	if got := CityName("Smallville", "es"); got != "Smallville" {
+		t.Fatalf("expected unknown cities to keep their name, got %q", got)
+	}
+
+	for code, names := range countryNames {
+		for _, lang := range Languages {
+			if names[lang] == "" {
+				t.Errorf("country %s has no %s name", code, lang)
+			}
+		}
+	}
+}
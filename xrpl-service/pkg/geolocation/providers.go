@@ -0,0 +1,450 @@
+package geolocation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/cachefile"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/intern"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/budget"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+// Paid geolocation providers.
+const (
+	ProviderIPInfo  = "ipinfo"
+	ProviderMaxMind = "maxmind"
+)
+
+const (
+	defaultIPInfoURL         = "https://ipinfo.io"
+	defaultMaxMindURL        = "https://geoip.maxmind.com"
+	providerRequestTimeout   = 5 * time.Second
+	providerUsageFileName    = "geolocation-provider-usage.json"
+	providerUsageVersion     = 1
+	providerQuotaWarnPortion = 0.8
+)
+
+// Provider lookup results, the result label of
+// xrpl_validator_geolocation_provider_requests_total.
+const (
+	providerResultOK             = "ok"
+	providerResultNotFound       = "not_found"
+	providerResultError          = "error"
+	providerResultUnauthorized   = "unauthorized"
+	providerResultQuotaExhausted = "quota_exhausted"
+)
+
+// errProviderQuota is returned by a provider that spent its monthly quota.
+var errProviderQuota = errors.New("monthly quota exhausted")
+
+// errProviderUnauthorized is returned when a provider rejects its
+// credentials.
+var errProviderUnauthorized = errors.New("credentials rejected")
+
+// providerUsageFormat is the layout of the provider usage file.
+var providerUsageFormat = cachefile.Format{
+	Name:    "geolocation provider usage",
+	Version: providerUsageVersion,
+}
+
+// providerUsageFile records the requests sent to each provider in a month,
+// so that quotas survive restarts.
+type providerUsageFile struct {
+	Version   int                      `json:"version"`
+	Providers map[string]providerUsage `json:"providers"`
+}
+
+type providerUsage struct {
+	Month    string `json:"month"` // "2006-01", UTC
+	Requests int64  `json:"requests"`
+}
+
+// provider is a paid IP geolocation web service.
+type provider struct {
+	cfg    models.GeoProvider
+	client *http.Client
+
+	mu             sync.Mutex
+	usage          providerUsage
+	warnedMonth    string // month the quota warning was logged for
+	exhaustedMonth string // month the service reported its quota spent
+	secrets        map[string]fileSecret
+}
+
+// fileSecret is a credential read from a file, kept until the file changes.
+type fileSecret struct {
+	value   string
+	modTime time.Time
+}
+
+// ProviderUsagePath returns where the provider usage of a resolver whose
+// cache is at cachePath is kept.
+func ProviderUsagePath(cachePath string) string {
+	return filepath.Join(filepath.Dir(cachePath), providerUsageFileName)
+}
+
+// newProviders returns the configured providers with their request pacing
+// and the usage recorded for them.
+func (r *Resolver) newProviders(configs []models.GeoProvider, shared *budget.Manager) []*provider {
+	if len(configs) == 0 {
+		return nil
+	}
+	stored := r.loadProviderUsage()
+	providers := make([]*provider, 0, len(configs))
+	for _, cfg := range configs {
+		rate := cfg.RequestsPerSecond
+		if rate <= 0 {
+			rate = 1
+		}
+		pacing := budget.NewManager(map[string]models.OutboundBudget{
+			budget.DefaultDestination: {RequestsPerSecond: rate},
+		}, r.clock)
+		p := &provider{
+			cfg: cfg,
+			client: &http.Client{
+				Timeout:   providerRequestTimeout,
+				Transport: budget.Transport(pacing, budget.SubsystemResolver, budget.Transport(shared, budget.SubsystemResolver, nil)),
+			},
+			usage:   stored[cfg.Name],
+			secrets: make(map[string]fileSecret),
+		}
+		metrics.GeolocationProviderQuotaLimit.WithLabelValues(cfg.Name).Set(float64(cfg.MonthlyQuota))
+		metrics.GeolocationProviderQuotaUsed.WithLabelValues(cfg.Name).Set(float64(p.requestsIn(r.month())))
+		providers = append(providers, p)
+	}
+	return providers
+}
+
+// month returns the current UTC calendar month, which quotas reset on.
+func (r *Resolver) month() string {
+	return r.clock.Now().UTC().Format("2006-01")
+}
+
+// lookupIP locates ip with GeoLite and, when GeoLite cannot place it or only
+// places it in its country, with each provider in turn.
+func (r *Resolver) lookupIP(ip string) (*models.GeoLocation, error) {
+	geo, err := r.lookupGeoByIP(ip)
+	if len(r.providers) == 0 || (err == nil && geo != nil && !geo.Approximate) {
+		return geo, err
+	}
+	for _, p := range r.providers {
+		located, providerErr := r.lookupProvider(p, ip)
+		if providerErr == nil && located != nil {
+			return located, nil
+		}
+	}
+	return geo, err
+}
+
+// lookupProvider asks p to locate ip, counting the request against its
+// monthly quota.
+func (r *Resolver) lookupProvider(p *provider, ip string) (*models.GeoLocation, error) {
+	name := p.cfg.Name
+	month := r.month()
+	if !p.reserve(month) {
+		metrics.GeolocationProviderRequestsTotal.WithLabelValues(name, providerResultQuotaExhausted).Inc()
+		return nil, errProviderQuota
+	}
+	used := p.requestsIn(month)
+	metrics.GeolocationProviderQuotaUsed.WithLabelValues(name).Set(float64(used))
+	r.persistProviderUsage()
+	if p.shouldWarn(month, used) {
+		r.logger.WithFields(logrus.Fields{
+			"provider": name,
+			"used":     used,
+			"quota":    p.cfg.MonthlyQuota,
+		}).Warn("Geolocation provider is close to its monthly quota")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), providerRequestTimeout)
+	defer cancel()
+	var geo *models.GeoLocation
+	var err error
+	switch name {
+	case ProviderIPInfo:
+		geo, err = p.lookupIPInfo(ctx, ip)
+	case ProviderMaxMind:
+		geo, err = p.lookupMaxMind(ctx, ip)
+	default:
+		err = fmt.Errorf("unknown geolocation provider %q", name)
+	}
+
+	result := providerResultOK
+	switch {
+	case err == nil:
+	case errors.Is(err, xrpl.ErrNotFound):
+		result = providerResultNotFound
+	case errors.Is(err, errProviderQuota):
+		result = providerResultQuotaExhausted
+		p.markExhausted(month)
+		r.logger.WithField("provider", name).Warn("Geolocation provider reports its quota spent; skipping it until next month")
+	case errors.Is(err, errProviderUnauthorized):
+		result = providerResultUnauthorized
+		r.logger.WithError(err).WithField("provider", name).Warn("Geolocation provider rejected its credentials")
+	default:
+		result = providerResultError
+		r.logger.WithError(err).WithField("provider", name).Debug("Geolocation provider lookup failed")
+	}
+	metrics.GeolocationProviderRequestsTotal.WithLabelValues(name, result).Inc()
+	return geo, err
+}
+
+// reserve counts one request in month, rolling the count over on a new
+// month. It returns false once the quota is spent.
+func (p *provider) reserve(month string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.usage.Month != month {
+		p.usage = providerUsage{Month: month}
+	}
+	if p.exhaustedMonth == month {
+		return false
+	}
+	if p.cfg.MonthlyQuota > 0 && p.usage.Requests >= p.cfg.MonthlyQuota {
+		return false
+	}
+	p.usage.Requests++
+	return true
+}
+
+// requestsIn returns the requests sent in month.
+func (p *provider) requestsIn(month string) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.usage.Month != month {
+		return 0
+	}
+	return p.usage.Requests
+}
+
+// shouldWarn reports, once per month, that used crossed the warning portion
+// of the quota.
+func (p *provider) shouldWarn(month string, used int64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cfg.MonthlyQuota <= 0 || p.warnedMonth == month {
+		return false
+	}
+	if float64(used) < float64(p.cfg.MonthlyQuota)*providerQuotaWarnPortion {
+		return false
+	}
+	p.warnedMonth = month
+	return true
+}
+
+func (p *provider) markExhausted(month string) {
+	p.mu.Lock()
+	p.exhaustedMonth = month
+	p.mu.Unlock()
+}
+
+// secret returns value, or the contents of path when set, re-reading the
+// file whenever it changes.
+func (p *provider) secret(value, path string) (string, error) {
+	if path == "" {
+		return value, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	p.mu.Lock()
+	cached, ok := p.secrets[path]
+	p.mu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.value, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	cached = fileSecret{value: strings.TrimSpace(string(data)), modTime: info.ModTime()}
+	p.mu.Lock()
+	p.secrets[path] = cached
+	p.mu.Unlock()
+	return cached.value, nil
+}
+
+// baseURL returns the configured endpoint or fallback, without a trailing
+// slash.
+func (p *provider) baseURL(fallback string) string {
+	if base := strings.TrimSpace(p.cfg.URL); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	return fallback
+}
+
+// lookupIPInfo asks ipinfo.io for ip.
+func (p *provider) lookupIPInfo(ctx context.Context, ip string) (*models.GeoLocation, error) {
+	token, err := p.secret(p.cfg.Token, p.cfg.TokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("read ipinfo token: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL(defaultIPInfoURL)+"/"+url.PathEscape(ip)+"/json", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	body, err := p.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if jsonutil.Bool(body, "bogon") {
+		return nil, fmt.Errorf("ipinfo has no location for bogon %s: %w", ip, xrpl.ErrNotFound)
+	}
+
+	var lat, lng float64
+	located := false
+	if loc := jsonutil.String(body, "loc"); loc != "" {
+		latText, lngText, ok := strings.Cut(loc, ",")
+		var latErr, lngErr error
+		lat, latErr = strconv.ParseFloat(strings.TrimSpace(latText), 64)
+		lng, lngErr = strconv.ParseFloat(strings.TrimSpace(lngText), 64)
+		located = ok && latErr == nil && lngErr == nil
+	}
+	return providerLocation(ip, jsonutil.String(body, "country"), jsonutil.String(body, "city"), lat, lng, located)
+}
+
+// lookupMaxMind asks the MaxMind GeoIP2 City web service for ip.
+func (p *provider) lookupMaxMind(ctx context.Context, ip string) (*models.GeoLocation, error) {
+	licenseKey, err := p.secret(p.cfg.LicenseKey, p.cfg.LicenseKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read MaxMind license key: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL(defaultMaxMindURL)+"/geoip/v2.1/city/"+url.PathEscape(ip), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(p.cfg.AccountID, licenseKey)
+	body, err := p.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	location := jsonutil.Map(body, "location")
+	_, hasLat := location["latitude"]
+	_, hasLng := location["longitude"]
+	return providerLocation(
+		ip,
+		jsonutil.GetString(body, "country.iso_code"),
+		jsonutil.GetString(body, "city.names.en"),
+		jsonutil.Float64(location, "latitude"),
+		jsonutil.Float64(location, "longitude"),
+		hasLat && hasLng,
+	)
+}
+
+// do sends req and decodes the JSON object it answers with, mapping the
+// statuses the services share to errors.
+func (p *provider) do(req *http.Request) (map[string]interface{}, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%s has no location for the address: %w", p.cfg.Name, xrpl.ErrNotFound)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("%s: HTTP %d: %w", p.cfg.Name, resp.StatusCode, errProviderUnauthorized)
+	case resp.StatusCode == http.StatusPaymentRequired:
+		return nil, fmt.Errorf("%s: HTTP %d: %w", p.cfg.Name, resp.StatusCode, errProviderQuota)
+	case resp.StatusCode == http.StatusBadRequest && strings.Contains(string(data), "IP_ADDRESS_RESERVED"):
+		return nil, fmt.Errorf("%s has no location for a reserved address: %w", p.cfg.Name, xrpl.ErrNotFound)
+	default:
+		return nil, fmt.Errorf("%s: HTTP %d", p.cfg.Name, resp.StatusCode)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("%s: decode response: %w", p.cfg.Name, err)
+	}
+	return body, nil
+}
+
+// providerLocation builds a location from a provider answer. Like GeoLite
+// records, an answer with a country but no coordinates is placed at the
+// country's centroid and flagged approximate.
+func providerLocation(ip, countryCode, city string, lat, lng float64, located bool) (*models.GeoLocation, error) {
+	countryCode = strings.ToUpper(strings.TrimSpace(countryCode))
+	approximate := false
+	if !located || (lat == 0 && lng == 0) {
+		var ok bool
+		if lat, lng, ok = CountryCentroid(countryCode); !ok {
+			return nil, fmt.Errorf("provider answer has no coordinates for %s: %w", ip, xrpl.ErrNotFound)
+		}
+		approximate = true
+	}
+	if countryCode == "" {
+		countryCode = "XX"
+	}
+	city = strings.TrimSpace(city)
+	if city == "" || approximate {
+		city = "Unknown"
+	}
+	return &models.GeoLocation{
+		Latitude:    lat,
+		Longitude:   lng,
+		CountryCode: intern.String(countryCode),
+		City:        intern.String(city),
+		Approximate: approximate,
+	}, nil
+}
+
+// loadProviderUsage reads the recorded provider usage, keyed by provider.
+func (r *Resolver) loadProviderUsage() map[string]providerUsage {
+	data, _, err := providerUsageFormat.Load(r.providerUsagePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			r.logger.WithError(err).WithField("path", r.providerUsagePath).Warn("Failed to load geolocation provider usage")
+		}
+		return nil
+	}
+	var payload providerUsageFile
+	if err := json.Unmarshal(data, &payload); err != nil {
+		r.logger.WithError(err).WithField("path", r.providerUsagePath).Warn("Failed to parse geolocation provider usage")
+		return nil
+	}
+	return payload.Providers
+}
+
+// persistProviderUsage writes the usage of every provider.
+func (r *Resolver) persistProviderUsage() {
+	r.providerUsageMu.Lock()
+	defer r.providerUsageMu.Unlock()
+	payload := providerUsageFile{Version: providerUsageVersion, Providers: make(map[string]providerUsage, len(r.providers))}
+	for _, p := range r.providers {
+		p.mu.Lock()
+		payload.Providers[p.cfg.Name] = p.usage
+		p.mu.Unlock()
+	}
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err == nil {
+		err = providerUsageFormat.Write(r.providerUsagePath, data)
+	}
+	if err != nil {
+		r.logger.WithError(err).Warn("Failed to persist geolocation provider usage")
+	}
+}
@@ -0,0 +1,203 @@
+package geolocation
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+func newProviderResolver(t *testing.T, dir string, fake *clock.Fake, providers ...models.GeoProvider) *Resolver {
+	t.Helper()
+	r := newResolver(logrus.New(), ResolverConfig{
+		CachePath: filepath.Join(dir, "geo-cache.json"),
+		Providers: providers,
+		Clock:     fake,
+	})
+	r.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+		return nil, errors.New("no GeoLite record")
+	}
+	return r
+}
+
+func TestLookupIPFallsBackToIPInfo(t *testing.T) {
+	var auth atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth.Store(r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/8.8.8.8/json":
+			_, _ = w.Write([]byte(`{"ip":"8.8.8.8","city":"Mountain View","country":"US","loc":"37.4056,-122.0775"}`))
+		case "/10.0.0.1/json":
+			_, _ = w.Write([]byte(`{"ip":"10.0.0.1","bogon":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	r := newProviderResolver(t, t.TempDir(), clock.NewFake(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)),
+		models.GeoProvider{Name: ProviderIPInfo, Token: "secret", URL: server.URL + "/", RequestsPerSecond: 100})
+
+	geo, err := r.lookupIP("8.8.8.8")
+	if err != nil {
+		t.Fatalf("lookupIP failed: %v", err)
+	}
+	if geo.CountryCode != "US" || geo.City != "Mountain View" || geo.Latitude != 37.4056 || geo.Longitude != -122.0775 || geo.Approximate {
+		t.Fatalf("unexpected ipinfo location: %+v", geo)
+	}
+	if got := auth.Load(); got != "Bearer secret" {
+		t.Fatalf("expected a bearer token, got %v", got)
+	}
+	if _, err := r.lookupProvider(r.providers[0], "10.0.0.1"); !errors.Is(err, xrpl.ErrNotFound) {
+		t.Fatalf("expected a bogon to be not found, got %v", err)
+	}
+}
+
+func TestLookupIPUsesMaxMindForApproximateGeoLite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		account, key, ok := r.BasicAuth()
+		if !ok || account != "1234" || key != "license" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/geoip/v2.1/city/81.2.69.142" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"city":{"names":{"en":"London"}},"country":{"iso_code":"GB"},"location":{"latitude":51.5142,"longitude":-0.0931}}`))
+	}))
+	defer server.Close()
+
+	r := newProviderResolver(t, t.TempDir(), clock.NewFake(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)),
+		models.GeoProvider{Name: ProviderMaxMind, AccountID: "1234", LicenseKey: "license", URL: server.URL, RequestsPerSecond: 100})
+	r.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+		return &models.GeoLocation{CountryCode: "GB", City: "Unknown", Approximate: true}, nil
+	}
+
+	geo, err := r.lookupIP("81.2.69.142")
+	if err != nil {
+		t.Fatalf("lookupIP failed: %v", err)
+	}
+	if geo.City != "London" || geo.CountryCode != "GB" || geo.Approximate {
+		t.Fatalf("expected MaxMind to refine the approximate location, got %+v", geo)
+	}
+
+	r.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+		return &models.GeoLocation{CountryCode: "GB", City: "Leeds"}, nil
+	}
+	if geo, _ := r.lookupIP("81.2.69.142"); geo.City != "Leeds" {
+		t.Fatalf("expected a precise GeoLite location to be kept, got %+v", geo)
+	}
+	if used := r.providers[0].requestsIn("2026-03"); used != 1 {
+		t.Fatalf("expected one MaxMind request, got %d", used)
+	}
+}
+
+func TestProviderQuotaPersistsAndResetsMonthly(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"country":"DE","loc":"52.52,13.405","city":"Berlin"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	fake := clock.NewFake(time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC))
+	cfg := models.GeoProvider{Name: ProviderIPInfo, Token: "secret", URL: server.URL, RequestsPerSecond: 100, MonthlyQuota: 2}
+
+	first := newProviderResolver(t, dir, fake, cfg)
+	if _, err := first.lookupIP("1.1.1.1"); err != nil {
+		t.Fatalf("first lookup failed: %v", err)
+	}
+
+	// A restarted resolver continues from the recorded usage.
+	second := newProviderResolver(t, dir, fake, cfg)
+	if _, err := second.lookupIP("1.1.1.2"); err != nil {
+		t.Fatalf("second lookup failed: %v", err)
+	}
+	if _, err := second.lookupProvider(second.providers[0], "1.1.1.3"); !errors.Is(err, errProviderQuota) {
+		t.Fatalf("expected the quota to be spent, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected 2 requests within the quota, got %d", got)
+	}
+
+	fake.Advance(3 * 24 * time.Hour)
+	if _, err := second.lookupIP("1.1.1.3"); err != nil {
+		t.Fatalf("expected the quota to reset in April, got %v", err)
+	}
+	if used := second.providers[0].requestsIn("2026-04"); used != 1 {
+		t.Fatalf("expected one request counted in April, got %d", used)
+	}
+}
+
+func TestProviderPaymentRequiredSkipsRestOfMonth(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusPaymentRequired)
+	}))
+	defer server.Close()
+
+	r := newProviderResolver(t, t.TempDir(), clock.NewFake(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)),
+		models.GeoProvider{Name: ProviderIPInfo, Token: "secret", URL: server.URL, RequestsPerSecond: 100})
+	for _, ip := range []string{"1.1.1.1", "1.1.1.2"} {
+		if _, err := r.lookupProvider(r.providers[0], ip); !errors.Is(err, errProviderQuota) {
+			t.Fatalf("expected %s to report the quota spent, got %v", ip, err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected no requests after a 402, got %d", got)
+	}
+}
+
+func TestProviderRereadsTokenFile(t *testing.T) {
+	var auth atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth.Store(r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"country":"FR"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "ipinfo-token")
+	if err := os.WriteFile(tokenFile, []byte("old\n"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+	r := newProviderResolver(t, dir, clock.NewFake(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)),
+		models.GeoProvider{Name: ProviderIPInfo, TokenFile: tokenFile, URL: server.URL, RequestsPerSecond: 100})
+
+	geo, err := r.lookupProvider(r.providers[0], "1.1.1.1")
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if !geo.Approximate || geo.CountryCode != "FR" {
+		t.Fatalf("expected a country-only answer to be approximate, got %+v", geo)
+	}
+	if got := auth.Load(); got != "Bearer old" {
+		t.Fatalf("expected the file token, got %v", got)
+	}
+
+	if err := os.WriteFile(tokenFile, []byte("new"), 0o600); err != nil {
+		t.Fatalf("rotate token: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(tokenFile, later, later); err != nil {
+		t.Fatalf("touch token: %v", err)
+	}
+	if _, err := r.lookupProvider(r.providers[0], "1.1.1.2"); err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if got := auth.Load(); got != "Bearer new" {
+		t.Fatalf("expected the rotated token, got %v", got)
+	}
+}
@@ -0,0 +1,82 @@
+package geolocation
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+)
+
+// StartGeoLiteRefresh downloads the GeoLite DB again on schedule and swaps
+// it in without a restart, so that IP ranges that moved since startup
+// resolve correctly. Resolved domains and IPs stay cached; only new lookups
+// use the refreshed DB. A non-positive interval or a disabled GeoLite
+// disables refreshes. It stops when the resolver is closed.
+func (r *Resolver) StartGeoLiteRefresh(schedule clock.Schedule) {
+	if schedule.Interval <= 0 || r.geoLiteDisabled {
+		return
+	}
+	go func() {
+		ticker := r.clock.NewTicker(schedule.First())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stopChan:
+				return
+			case <-ticker.C():
+				ticker.Reset(schedule.Next())
+				if err := r.RefreshGeoLite(); err != nil {
+					r.logger.WithError(err).Warn("GeoLite DB refresh failed; keeping the current DB")
+				}
+			}
+		}
+	}()
+}
+
+// GeoLiteBuildTime returns when the GeoLite DB in use was built, or the zero
+// time without a DB.
+func (r *Resolver) GeoLiteBuildTime() time.Time {
+	r.dbMu.RLock()
+	defer r.dbMu.RUnlock()
+	if r.db == nil {
+		return time.Time{}
+	}
+	return r.db.buildTime()
+}
+
+// RefreshGeoLite downloads the GeoLite DB and, once it opens, moves it over
+// the configured path and swaps it in. On failure the current DB stays in
+// use, both now and after a restart.
+func (r *Resolver) RefreshGeoLite() error {
+	if r.geoLiteDisabled {
+		return ErrGeoLiteDisabled
+	}
+	if r.downloadURL == "" {
+		return fmt.Errorf("no GeoLite download URL configured")
+	}
+	stagingPath := r.dbPath + ".new"
+	if err := downloadFile(r.downloadURL, stagingPath, r.downloadTimeout, r.downloadTransport); err != nil {
+		return fmt.Errorf("failed to download GeoLite DB: %w", err)
+	}
+	db, err := openIPDB(stagingPath)
+	if err != nil {
+		os.Remove(stagingPath)
+		return fmt.Errorf("failed to open downloaded GeoLite DB: %w", err)
+	}
+	if err := os.Rename(stagingPath, r.dbPath); err != nil {
+		db.Close()
+		os.Remove(stagingPath)
+		return fmt.Errorf("failed to replace GeoLite DB at %s: %w", r.dbPath, err)
+	}
+
+	r.dbMu.Lock()
+	old := r.db
+	r.db = db
+	r.dbMu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+	r.logger.WithField("path", r.dbPath).Info("GeoLite DB refreshed")
+	return nil
+}
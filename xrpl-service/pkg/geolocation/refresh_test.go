@@ -0,0 +1,36 @@
+package geolocation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRefreshGeoLiteKeepsCurrentDBOnBadDownload(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not an mmdb file"))
+	}))
+	defer upstream.Close()
+
+	dbPath := filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")
+	if err := os.WriteFile(dbPath, []byte("current"), 0o644); err != nil {
+		t.Fatalf("failed to write DB: %v", err)
+	}
+	resolver := newTestResolver(t, filepath.Join(t.TempDir(), "geo-cache.json"))
+	resolver.dbPath = dbPath
+	resolver.downloadURL = upstream.URL
+	resolver.downloadTimeout = time.Second
+
+	if err := resolver.RefreshGeoLite(); err == nil {
+		t.Fatal("expected an unreadable download to fail the refresh")
+	}
+	if data, _ := os.ReadFile(dbPath); string(data) != "current" {
+		t.Fatalf("expected the current DB to be left in place, got %q", data)
+	}
+	if _, err := os.Stat(dbPath + ".new"); !os.IsNotExist(err) {
+		t.Fatalf("expected the staged download to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,750 @@
+// Package geolocation maps validator domains, IPs and XRPL accounts to
+// coordinates using GeoLite2 databases, with a persistent cache.
+package geolocation
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/cachefile"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/intern"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/jsonutil"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/budget"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultCachePath         = "data/geolocation-cache.json"
+	defaultGeoLiteDBPath     = "data/GeoLite2-City.mmdb"
+	defaultGeoLiteDownload   = "https://github.com/P3TERX/GeoLite.mmdb/raw/download/GeoLite2-City.mmdb"
+	defaultMissingAccountTTL = time.Hour
+	defaultDownloadTimeout   = 60 * time.Second
+	cacheVersion             = 2
+)
+
+// geoCacheEntry is a cached location, laid out to fit a 48-byte allocation
+// since a long-running instance holds one per account seen. Coordinates come
+// from GeoLite, which publishes four decimal places, so they are kept as
+// float32 and read back rounded to cachedCoordinatePlaces; country codes and
+// cities are interned.
+type geoCacheEntry struct {
+	CountryCode string  `json:"country_code"`
+	City        string  `json:"city"`
+	Latitude    float32 `json:"latitude"`
+	Longitude   float32 `json:"longitude"`
+	UpdatedAt   uint32  `json:"updated_at"` // unix seconds
+	Approximate bool    `json:"approximate,omitempty"`
+}
+
+// cachedCoordinatePlaces is how many decimal places of a cached coordinate
+// survive float32 storage, about a meter.
+const cachedCoordinatePlaces = 5
+
+// widenCoordinate converts a cached coordinate back to float64, dropping the
+// float32 rounding error, so that 35.6895 is not read back as
+// 35.68949890136719.
+func widenCoordinate(value float32) float64 {
+	scale := math.Pow(10, cachedCoordinatePlaces)
+	return math.Round(float64(value)*scale) / scale
+}
+
+type geoCacheFile struct {
+	Version int                       `json:"version"`
+	Entries map[string]*geoCacheEntry `json:"entries"`
+}
+
+// geoCacheFormat upgrades older geolocation caches on load.
+var geoCacheFormat = cachefile.Format{
+	Name:    "geolocation cache",
+	Version: cacheVersion,
+	Migrations: map[int]cachefile.MigrateFunc{
+		1: migrateGeoCacheV1,
+	},
+}
+
+// migrateGeoCacheV1 carries version 1 entries, which predate updated_at,
+// over to version 2. They keep a zero updated_at until next resolved.
+func migrateGeoCacheV1(doc cachefile.Document) error {
+	raw, ok := doc["entries"]
+	if !ok {
+		return nil
+	}
+	var entries map[string]*geoCacheEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return err
+	}
+	for key, entry := range entries {
+		if entry == nil {
+			delete(entries, key)
+		}
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	doc["entries"] = data
+	return nil
+}
+
+// ErrGeoLiteDisabled is returned for lookups that miss the cache of a
+// resolver running without GeoLite.
+var ErrGeoLiteDisabled = errors.New("GeoLite lookups are disabled")
+
+// ErrGeoLiteUnavailable is returned for lookups that miss the cache while
+// a GeoLite DB that failed to open is being retried.
+var ErrGeoLiteUnavailable = errors.New("GeoLite DB is not available yet")
+
+// ipDB is an opened IP database: GeoLite2 City for locations, GeoLite2 ASN
+// for AS numbers.
+type ipDB interface {
+	city(ip net.IP) (*models.GeoLocation, error)
+	asn(ip net.IP) (uint, error)
+	buildTime() time.Time
+	Close() error
+}
+
+type ResolverConfig struct {
+	CachePath          string
+	GeoLiteDBPath      string
+	GeoLiteDownloadURL string
+	AutoDownload       bool
+	MissingAccountTTL  time.Duration
+	DownloadTimeout    time.Duration
+	// DisableGeoLite runs the resolver without GeoLite DBs: nothing is
+	// downloaded or opened, and only cached domains, IPs and accounts
+	// resolve. It is implied when built with the nogeolite tag.
+	DisableGeoLite bool
+	// ASNDBPath is an optional GeoLite2 ASN DB for ResolveDomainASN.
+	ASNDBPath string
+	// Budget paces GeoLite downloads against per-host limits shared with
+	// other subsystems. Nil leaves them unpaced.
+	Budget *budget.Manager
+	// Clock expires missing-account entries and stamps cache entries. Nil
+	// uses the system clock.
+	Clock clock.Clock
+	// Providers are paid geolocation web services asked, in order, for IPs
+	// GeoLite cannot place or only places in their country.
+	Providers []models.GeoProvider
+	// ProviderUsagePath keeps the monthly request counts of Providers.
+	// Defaults to ProviderUsagePath(CachePath).
+	ProviderUsagePath string
+}
+
+// Resolver enriches validators and transactions with geolocation using GeoLite.
+type Resolver struct {
+	logger              *logrus.Logger
+	dbMu                sync.RWMutex
+	db                  ipDB
+	initErr             error // why the DB is not open yet, while retrying
+	geoLiteDisabled     bool
+	dbPath              string
+	cfg                 ResolverConfig // as given, for retrying the DB
+	downloadURL         string
+	downloadTimeout     time.Duration
+	downloadTransport   http.RoundTripper
+	stopChan            chan struct{}
+	stopOnce            sync.Once
+	cachePath           string
+	missingAccountTTL   time.Duration
+	dnsLookup           func(string) ([]net.IP, error)
+	lookupGeoByIP       func(string) (*models.GeoLocation, error)
+	asnDB               ipDB
+	lookupASNByIP       func(string) (uint, error)
+	clock               clock.Clock
+	mu                  sync.RWMutex
+	cache               map[string]*geoCacheEntry
+	missingAccountUntil map[string]time.Time
+	asnCache            map[string]uint
+	providers           []*provider
+	providerUsagePath   string
+	providerUsageMu     sync.Mutex // serializes usage file writes
+}
+
+// NewResolver creates a resolver backed by the GeoLite2 City database, or
+// by its cache alone when GeoLite is disabled.
+func NewResolver(logger *logrus.Logger, cfg ResolverConfig) (*Resolver, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	cfg = withDefaults(cfg)
+	if !cfg.DisableGeoLite && !GeoLiteBuiltIn {
+		logger.Warn("Built without GeoLite support (nogeolite); resolving locations from the geolocation cache only")
+		cfg.DisableGeoLite = true
+	}
+	if cfg.DisableGeoLite {
+		r := newResolver(logger, cfg)
+		r.geoLiteDisabled = true
+		r.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+			return nil, fmt.Errorf("no cached geolocation for ip %s: %w", ip, ErrGeoLiteDisabled)
+		}
+		if strings.TrimSpace(cfg.ASNDBPath) != "" {
+			logger.WithField("path", cfg.ASNDBPath).Warn("Ignoring GeoLite ASN DB while GeoLite is disabled")
+		}
+		r.loadCache()
+		return r, nil
+	}
+
+	db, asnDB, err := openGeoLite(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	r := newResolver(logger, cfg)
+	r.db = db
+	r.lookupGeoByIP = r.lookupGeoLiteIP
+	if asnDB != nil {
+		r.asnDB = asnDB
+		r.lookupASNByIP = r.lookupGeoLiteASN
+	}
+	r.loadCache()
+	return r, nil
+}
+
+// openGeoLite downloads the GeoLite City DB if missing and allowed, then
+// opens it and, when configured, the ASN DB.
+func openGeoLite(cfg ResolverConfig, logger *logrus.Logger) (db, asnDB ipDB, err error) {
+	if err := ensureGeoLiteDatabase(cfg, logger); err != nil {
+		return nil, nil, err
+	}
+
+	db, err = openIPDB(cfg.GeoLiteDBPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open GeoLite DB at %s: %w", cfg.GeoLiteDBPath, err)
+	}
+	if path := strings.TrimSpace(cfg.ASNDBPath); path != "" {
+		asnDB, err = openIPDB(path)
+		if err != nil {
+			db.Close()
+			return nil, nil, fmt.Errorf("failed to open GeoLite ASN DB at %s: %w", path, err)
+		}
+	}
+	return db, asnDB, nil
+}
+
+// newResolver returns a resolver without DBs or a loaded cache.
+func newResolver(logger *logrus.Logger, cfg ResolverConfig) *Resolver {
+	r := &Resolver{
+		logger:              logger,
+		dbPath:              cfg.GeoLiteDBPath,
+		cfg:                 cfg,
+		downloadURL:         strings.TrimSpace(cfg.GeoLiteDownloadURL),
+		downloadTimeout:     cfg.DownloadTimeout,
+		downloadTransport:   budget.Transport(cfg.Budget, budget.SubsystemResolver, nil),
+		stopChan:            make(chan struct{}),
+		cachePath:           cfg.CachePath,
+		missingAccountTTL:   cfg.MissingAccountTTL,
+		dnsLookup:           net.LookupIP,
+		clock:               clock.OrReal(cfg.Clock),
+		cache:               make(map[string]*geoCacheEntry),
+		missingAccountUntil: make(map[string]time.Time),
+		asnCache:            make(map[string]uint),
+	}
+	r.providerUsagePath = cfg.ProviderUsagePath
+	if strings.TrimSpace(r.providerUsagePath) == "" {
+		r.providerUsagePath = ProviderUsagePath(cfg.CachePath)
+	}
+	r.providers = r.newProviders(cfg.Providers, cfg.Budget)
+	return r
+}
+
+func withDefaults(cfg ResolverConfig) ResolverConfig {
+	if strings.TrimSpace(cfg.CachePath) == "" {
+		cfg.CachePath = defaultCachePath
+	}
+	if strings.TrimSpace(cfg.GeoLiteDBPath) == "" {
+		cfg.GeoLiteDBPath = defaultGeoLiteDBPath
+	}
+	if strings.TrimSpace(cfg.GeoLiteDownloadURL) == "" {
+		cfg.GeoLiteDownloadURL = defaultGeoLiteDownload
+	}
+	if cfg.MissingAccountTTL <= 0 {
+		cfg.MissingAccountTTL = defaultMissingAccountTTL
+	}
+	if cfg.DownloadTimeout <= 0 {
+		cfg.DownloadTimeout = defaultDownloadTimeout
+	}
+	return cfg
+}
+
+func ensureGeoLiteDatabase(cfg ResolverConfig, logger *logrus.Logger) error {
+	if _, err := os.Stat(cfg.GeoLiteDBPath); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to access GeoLite DB path %s: %w", cfg.GeoLiteDBPath, err)
+	}
+
+	if !cfg.AutoDownload {
+		return fmt.Errorf("GeoLite DB not found at %s and auto-download is disabled", cfg.GeoLiteDBPath)
+	}
+	if strings.TrimSpace(cfg.GeoLiteDownloadURL) == "" {
+		return fmt.Errorf("GeoLite DB not found at %s and no download URL configured", cfg.GeoLiteDBPath)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"path": cfg.GeoLiteDBPath,
+		"url":  cfg.GeoLiteDownloadURL,
+	}).Info("GeoLite DB missing; downloading")
+
+	transport := budget.Transport(cfg.Budget, budget.SubsystemResolver, nil)
+	if err := downloadFile(cfg.GeoLiteDownloadURL, cfg.GeoLiteDBPath, cfg.DownloadTimeout, transport); err != nil {
+		return fmt.Errorf("failed to download GeoLite DB: %w", err)
+	}
+
+	logger.WithField("path", cfg.GeoLiteDBPath).Info("GeoLite DB downloaded")
+	return nil
+}
+
+func downloadFile(url, destination string, timeout time.Duration, transport http.RoundTripper) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Transport: transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download returned status %d", resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return err
+	}
+
+	tmpPath := destination + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, destination)
+}
+
+// Close stops GeoLite refreshes and releases the underlying GeoLite readers.
+func (r *Resolver) Close() error {
+	if r == nil {
+		return nil
+	}
+	if r.stopChan != nil {
+		r.stopOnce.Do(func() { close(r.stopChan) })
+	}
+	r.dbMu.Lock()
+	defer r.dbMu.Unlock()
+	if r.asnDB != nil {
+		r.asnDB.Close()
+	}
+	if r.db == nil {
+		return nil
+	}
+	return r.db.Close()
+}
+
+// EnrichValidator resolves the validator domain against GeoLite data.
+func (r *Resolver) EnrichValidator(validator *models.Validator) error {
+	if validator == nil {
+		return fmt.Errorf("validator is nil")
+	}
+	if strings.TrimSpace(validator.Domain) == "" {
+		return fmt.Errorf("no domain available for geolocation")
+	}
+
+	geo, err := r.ResolveDomainGeo(validator.Domain)
+	if err != nil {
+		return err
+	}
+	if geo == nil {
+		return fmt.Errorf("no geolocation found for validator domain")
+	}
+
+	validator.Latitude = geo.Latitude
+	validator.Longitude = geo.Longitude
+	validator.CountryCode = geo.CountryCode
+	validator.City = geo.City
+	validator.Approximate = geo.Approximate
+	return nil
+}
+
+// ResolveAccountGeo resolves a transaction account to geolocation by reading the
+// account domain from XRPL and then resolving that domain through GeoLite.
+func (r *Resolver) ResolveAccountGeo(ctx context.Context, client xrpl.NodeClient, account string) (*models.GeoLocation, error) {
+	account = strings.TrimSpace(account)
+	if account == "" {
+		return nil, nil
+	}
+
+	if geo, ok := r.getCachedGeo("account:" + account); ok {
+		geo.ValidatorAddress = account
+		return geo, nil
+	}
+	if r.isAccountMissing(account) {
+		return nil, nil
+	}
+	if client == nil {
+		return nil, fmt.Errorf("XRPL client is nil")
+	}
+
+	domain, err := fetchAccountDomain(ctx, client, account)
+	if err != nil {
+		if errors.Is(err, xrpl.ErrNotFound) {
+			r.markAccountMissing(account)
+		}
+		return nil, err
+	}
+	if strings.TrimSpace(domain) == "" {
+		r.markAccountMissing(account)
+		return nil, nil
+	}
+
+	geo, err := r.ResolveDomainGeo(domain)
+	if err != nil {
+		return nil, err
+	}
+	if geo == nil {
+		return nil, nil
+	}
+
+	geo.ValidatorAddress = account
+	r.setCachedGeo("account:"+account, geo)
+	if err := r.persistCache(); err != nil {
+		r.logger.WithError(err).Warn("Failed to persist geolocation cache")
+	}
+	r.clearMissingAccount(account)
+	return geo, nil
+}
+
+// ResolveDomainGeo resolves a domain via DNS and then GeoLite.
+func (r *Resolver) ResolveDomainGeo(rawDomain string) (*models.GeoLocation, error) {
+	domain := normalizeDomain(rawDomain)
+	if domain == "" {
+		return nil, fmt.Errorf("invalid domain")
+	}
+
+	if geo, ok := r.getCachedGeo("domain:" + domain); ok {
+		return geo, nil
+	}
+
+	ip, err := r.resolveDomainIP(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	if geo, ok := r.getCachedGeo("ip:" + ip); ok {
+		r.setCachedGeo("domain:"+domain, geo)
+		if err := r.persistCache(); err != nil {
+			r.logger.WithError(err).Warn("Failed to persist geolocation cache")
+		}
+		return geo, nil
+	}
+
+	geo, err := r.lookupIP(ip)
+	if err != nil {
+		return nil, err
+	}
+	if geo == nil {
+		return nil, fmt.Errorf("no geolocation found for ip %s: %w", ip, xrpl.ErrNotFound)
+	}
+
+	r.setCachedGeo("ip:"+ip, geo)
+	r.setCachedGeo("domain:"+domain, geo)
+	if err := r.persistCache(); err != nil {
+		r.logger.WithError(err).Warn("Failed to persist geolocation cache")
+	}
+	return geo, nil
+}
+
+// ResolveIPGeo resolves an IP address via GeoLite, using the shared IP cache.
+func (r *Resolver) ResolveIPGeo(ip string) (*models.GeoLocation, error) {
+	geo, looked, err := r.resolveIPGeo(ip)
+	if err != nil {
+		return nil, err
+	}
+	if looked {
+		if err := r.persistCache(); err != nil {
+			r.logger.WithError(err).Warn("Failed to persist geolocation cache")
+		}
+	}
+	return geo, nil
+}
+
+// ResolveIPsGeo resolves IP addresses like ResolveIPGeo, writing the cache
+// file once for the batch. It returns the locations found by IP; addresses
+// that cannot be resolved are left out.
+func (r *Resolver) ResolveIPsGeo(ips []string) map[string]*models.GeoLocation {
+	locations := make(map[string]*models.GeoLocation, len(ips))
+	persist := false
+	for _, ip := range ips {
+		geo, looked, err := r.resolveIPGeo(ip)
+		if err != nil {
+			r.logger.WithError(err).WithField("ip", ip).Debug("Failed to resolve IP geolocation")
+			continue
+		}
+		persist = persist || looked
+		locations[ip] = geo
+	}
+	if persist {
+		if err := r.persistCache(); err != nil {
+			r.logger.WithError(err).Warn("Failed to persist geolocation cache")
+		}
+	}
+	return locations
+}
+
+// resolveIPGeo resolves ip from the cache or GeoLite, caching the result.
+// looked reports whether it was looked up, so that the cache needs writing.
+func (r *Resolver) resolveIPGeo(ip string) (geo *models.GeoLocation, looked bool, err error) {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return nil, false, fmt.Errorf("invalid IP: %s", ip)
+	}
+	ip = parsed.String()
+
+	if geo, ok := r.getCachedGeo("ip:" + ip); ok {
+		return geo, false, nil
+	}
+	geo, err = r.lookupIP(ip)
+	if err != nil {
+		return nil, false, err
+	}
+	if geo == nil {
+		return nil, false, fmt.Errorf("no geolocation found for ip %s: %w", ip, xrpl.ErrNotFound)
+	}
+	r.setCachedGeo("ip:"+ip, geo)
+	return geo, true, nil
+}
+
+func (r *Resolver) lookupGeoLiteIP(ip string) (*models.GeoLocation, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("invalid IP: %s", ip)
+	}
+	r.dbMu.RLock()
+	defer r.dbMu.RUnlock()
+	if r.db == nil {
+		return nil, fmt.Errorf("no cached geolocation for ip %s: %w", ip, ErrGeoLiteUnavailable)
+	}
+	return r.db.city(parsed)
+}
+
+func (r *Resolver) resolveDomainIP(domain string) (string, error) {
+	ips, err := r.dnsLookup(domain)
+	if err != nil {
+		err = xrpl.WrapDNSError(err)
+		metrics.UpstreamErrorsTotal.WithLabelValues("dns", xrpl.Classify(err)).Inc()
+		return "", fmt.Errorf("failed to resolve domain %s: %w", domain, err)
+	}
+	if len(ips) == 0 {
+		return "", fmt.Errorf("domain %s resolved with no IPs: %w", domain, xrpl.ErrNotFound)
+	}
+	return pickIP(ips), nil
+}
+
+func pickIP(ips []net.IP) string {
+	for _, candidate := range ips {
+		if candidate.To4() != nil {
+			return candidate.String()
+		}
+	}
+	return ips[0].String()
+}
+
+func fetchAccountDomain(ctx context.Context, client xrpl.NodeClient, account string) (string, error) {
+	resp, err := client.Command(ctx, "account_info", map[string]interface{}{
+		"account":      account,
+		"ledger_index": "validated",
+		"strict":       true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	r := jsonutil.NewReader(resp)
+	result := r.Map("result")
+	if err := r.Err(); err != nil {
+		return "", fmt.Errorf("%w: account_info: %w", xrpl.ErrDecode, err)
+	}
+
+	domainHex := jsonutil.GetString(result, "account_data.Domain")
+	if domainHex == "" {
+		return "", nil
+	}
+
+	domainRaw, err := hex.DecodeString(domainHex)
+	if err != nil {
+		return "", fmt.Errorf("%w: account domain: %w", xrpl.ErrDecode, err)
+	}
+
+	domain := strings.TrimSpace(strings.Trim(string(domainRaw), "\x00"))
+	return normalizeDomain(domain), nil
+}
+
+func normalizeDomain(raw string) string {
+	domain := strings.TrimSpace(raw)
+	domain = strings.TrimPrefix(domain, "http://")
+	domain = strings.TrimPrefix(domain, "https://")
+	domain = strings.TrimSuffix(domain, "/")
+	domain = strings.TrimSuffix(domain, ".")
+	if host, _, err := net.SplitHostPort(domain); err == nil {
+		domain = host
+	}
+	return strings.ToLower(strings.TrimSpace(domain))
+}
+
+func (r *Resolver) isAccountMissing(account string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	until, ok := r.missingAccountUntil[account]
+	if !ok {
+		return false
+	}
+	if r.clock.Now().After(until) {
+		delete(r.missingAccountUntil, account)
+		return false
+	}
+	return true
+}
+
+func (r *Resolver) markAccountMissing(account string) {
+	r.mu.Lock()
+	r.missingAccountUntil[account] = r.clock.Now().Add(r.missingAccountTTL)
+	r.mu.Unlock()
+}
+
+func (r *Resolver) clearMissingAccount(account string) {
+	r.mu.Lock()
+	delete(r.missingAccountUntil, account)
+	r.mu.Unlock()
+}
+
+func (r *Resolver) getCachedGeo(key string) (*models.GeoLocation, bool) {
+	r.mu.RLock()
+	entry, ok := r.cache[key]
+	r.mu.RUnlock()
+	if !ok || entry == nil {
+		return nil, false
+	}
+
+	return &models.GeoLocation{
+		Latitude:    widenCoordinate(entry.Latitude),
+		Longitude:   widenCoordinate(entry.Longitude),
+		CountryCode: entry.CountryCode,
+		City:        entry.City,
+		Approximate: entry.Approximate,
+	}, true
+}
+
+func (r *Resolver) setCachedGeo(key string, geo *models.GeoLocation) {
+	if geo == nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.cache[key] = &geoCacheEntry{
+		CountryCode: intern.String(geo.CountryCode),
+		City:        intern.String(geo.City),
+		Latitude:    float32(geo.Latitude),
+		Longitude:   float32(geo.Longitude),
+		Approximate: geo.Approximate,
+		UpdatedAt:   uint32(r.clock.Now().Unix()),
+	}
+	r.mu.Unlock()
+}
+
+func (r *Resolver) loadCache() {
+	data, migratedFrom, err := geoCacheFormat.Load(r.cachePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			r.logger.WithError(err).WithField("path", r.cachePath).Warn("Failed to load geolocation cache")
+		}
+		return
+	}
+	if migratedFrom != 0 {
+		r.logger.WithFields(logrus.Fields{
+			"path":   r.cachePath,
+			"from":   migratedFrom,
+			"to":     cacheVersion,
+			"backup": cachefile.BackupPath(r.cachePath, migratedFrom),
+		}).Info("Migrated geolocation cache")
+	}
+
+	var payload geoCacheFile
+	if err := json.Unmarshal(data, &payload); err != nil {
+		r.logger.WithError(err).WithField("path", r.cachePath).Warn("Failed to parse geolocation cache")
+		return
+	}
+	if payload.Entries == nil {
+		return
+	}
+	for key, entry := range payload.Entries {
+		if entry == nil {
+			delete(payload.Entries, key)
+			continue
+		}
+		entry.CountryCode = intern.String(entry.CountryCode)
+		entry.City = intern.String(entry.City)
+	}
+
+	r.mu.Lock()
+	r.cache = payload.Entries
+	r.mu.Unlock()
+
+	r.logger.WithFields(logrus.Fields{
+		"path":    r.cachePath,
+		"entries": len(payload.Entries),
+	}).Info("Loaded geolocation cache")
+}
+
+func (r *Resolver) persistCache() error {
+	r.mu.RLock()
+	payload := geoCacheFile{
+		Version: cacheVersion,
+		Entries: make(map[string]*geoCacheEntry, len(r.cache)),
+	}
+	for key, entry := range r.cache {
+		if entry == nil {
+			continue
+		}
+		copy := *entry
+		payload.Entries[key] = &copy
+	}
+	r.mu.RUnlock()
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+	return geoCacheFormat.Write(r.cachePath, data)
+}
@@ -0,0 +1,402 @@
+package geolocation
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/cachefile"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/clock"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/xrpl"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestResolver(t *testing.T, cachePath string) *Resolver {
+	t.Helper()
+	return &Resolver{
+		logger:              logrus.New(),
+		cachePath:           cachePath,
+		missingAccountTTL:   time.Hour,
+		clock:               clock.Real(),
+		cache:               make(map[string]*geoCacheEntry),
+		missingAccountUntil: make(map[string]time.Time),
+		asnCache:            make(map[string]uint),
+	}
+}
+
+func TestResolveDomainGeoCachesByDomainAndIP(t *testing.T) {
+	resolver := newTestResolver(t, filepath.Join(t.TempDir(), "geo-cache.json"))
+
+	dnsCalls := 0
+	lookupCalls := 0
+	resolver.dnsLookup = func(host string) ([]net.IP, error) {
+		dnsCalls++
+		if host != "example.com" {
+			t.Fatalf("unexpected host lookup: %s", host)
+		}
+		return []net.IP{net.ParseIP("1.2.3.4")}, nil
+	}
+	resolver.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+		lookupCalls++
+		if ip != "1.2.3.4" {
+			t.Fatalf("unexpected ip lookup: %s", ip)
+		}
+		return &models.GeoLocation{
+			Latitude:    40.7128,
+			Longitude:   -74.0060,
+			CountryCode: "US",
+			City:        "New York",
+		}, nil
+	}
+
+	first, err := resolver.ResolveDomainGeo("https://Example.com/")
+	if err != nil {
+		t.Fatalf("ResolveDomainGeo first call failed: %v", err)
+	}
+	second, err := resolver.ResolveDomainGeo("example.com")
+	if err != nil {
+		t.Fatalf("ResolveDomainGeo second call failed: %v", err)
+	}
+
+	if first == nil || second == nil {
+		t.Fatalf("expected geolocation result for both calls")
+	}
+	if first.City != "New York" || second.City != "New York" {
+		t.Fatalf("expected city New York, got %q and %q", first.City, second.City)
+	}
+	if dnsCalls != 1 {
+		t.Fatalf("expected 1 DNS lookup, got %d", dnsCalls)
+	}
+	if lookupCalls != 1 {
+		t.Fatalf("expected 1 IP geolocation lookup, got %d", lookupCalls)
+	}
+}
+
+func TestResolveIPsGeoPersistsOncePerBatch(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "geo-cache.json")
+	resolver := newTestResolver(t, cachePath)
+	resolver.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+		if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+			t.Fatalf("expected the cache written after the batch, found it before looking up %s", ip)
+		}
+		if ip == "192.0.2.1" {
+			return nil, nil
+		}
+		return &models.GeoLocation{Latitude: 50.11, Longitude: 8.68, CountryCode: "DE", City: "Frankfurt"}, nil
+	}
+
+	locations := resolver.ResolveIPsGeo([]string{"203.0.113.10", "192.0.2.1", "198.51.100.7", "not-an-ip"})
+	if len(locations) != 2 || locations["203.0.113.10"] == nil || locations["198.51.100.7"] == nil {
+		t.Fatalf("expected the 2 located IPs, got %v", locations)
+	}
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("expected the cache to be written: %v", err)
+	}
+	if !strings.Contains(string(data), "ip:203.0.113.10") || !strings.Contains(string(data), "ip:198.51.100.7") {
+		t.Fatalf("expected both lookups persisted, got %s", data)
+	}
+}
+
+func TestResolveAccountGeoCachesAccountLookup(t *testing.T) {
+	resolver := newTestResolver(t, filepath.Join(t.TempDir(), "geo-cache.json"))
+
+	dnsCalls := 0
+	resolver.dnsLookup = func(host string) ([]net.IP, error) {
+		dnsCalls++
+		if host != "example.com" {
+			t.Fatalf("expected normalized domain example.com, got %s", host)
+		}
+		return []net.IP{net.ParseIP("8.8.8.8")}, nil
+	}
+	resolver.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+		return &models.GeoLocation{
+			Latitude:    37.3860,
+			Longitude:   -122.0840,
+			CountryCode: "US",
+			City:        "Mountain View",
+		}, nil
+	}
+
+	client := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		if method != "account_info" {
+			t.Fatalf("unexpected method: %s", method)
+		}
+		return map[string]interface{}{
+			"result": map[string]interface{}{
+				"account_data": map[string]interface{}{
+					"Domain": hex.EncodeToString([]byte("https://Example.com/")),
+				},
+			},
+		}, nil
+	})
+
+	account := "rSourceAccount"
+	first, err := resolver.ResolveAccountGeo(context.Background(), client, account)
+	if err != nil {
+		t.Fatalf("ResolveAccountGeo first call failed: %v", err)
+	}
+	second, err := resolver.ResolveAccountGeo(context.Background(), client, account)
+	if err != nil {
+		t.Fatalf("ResolveAccountGeo second call failed: %v", err)
+	}
+
+	if first == nil || second == nil {
+		t.Fatalf("expected geolocation result for both calls")
+	}
+	if first.ValidatorAddress != account || second.ValidatorAddress != account {
+		t.Fatalf("expected validator address %s on both calls", account)
+	}
+	if calls := client.CommandCalls("account_info"); calls != 1 {
+		t.Fatalf("expected 1 account_info call, got %d", calls)
+	}
+	if dnsCalls != 1 {
+		t.Fatalf("expected 1 DNS call, got %d", dnsCalls)
+	}
+}
+
+func TestResolveAccountGeoCachesMissingDomain(t *testing.T) {
+	resolver := newTestResolver(t, filepath.Join(t.TempDir(), "geo-cache.json"))
+	resolver.dnsLookup = func(host string) ([]net.IP, error) {
+		t.Fatalf("dns lookup should not be called for missing domain")
+		return nil, nil
+	}
+	resolver.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+		t.Fatalf("geo lookup should not be called for missing domain")
+		return nil, nil
+	}
+
+	client := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"result": map[string]interface{}{
+				"account_data": map[string]interface{}{},
+			},
+		}, nil
+	})
+
+	account := "rNoDomain"
+	for i := 0; i < 2; i++ {
+		geo, err := resolver.ResolveAccountGeo(context.Background(), client, account)
+		if err != nil {
+			t.Fatalf("ResolveAccountGeo call %d failed: %v", i+1, err)
+		}
+		if geo != nil {
+			t.Fatalf("expected nil geolocation for account without domain")
+		}
+	}
+
+	if calls := client.CommandCalls(""); calls != 1 {
+		t.Fatalf("expected missing account negative-cache to avoid repeat calls, got %d", calls)
+	}
+}
+
+func TestResolveAccountGeoNegativeCachesOnlyNotFoundErrors(t *testing.T) {
+	resolver := newTestResolver(t, filepath.Join(t.TempDir(), "geo-cache.json"))
+
+	notFound := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		return nil, &xrpl.RPCError{Method: method, Code: "actNotFound"}
+	})
+	for i := 0; i < 2; i++ {
+		resolver.ResolveAccountGeo(context.Background(), notFound, "rMissing")
+	}
+	if calls := notFound.CommandCalls(""); calls != 1 {
+		t.Fatalf("expected actNotFound to be negative-cached, got %d calls", calls)
+	}
+
+	unavailable := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		return nil, &xrpl.HTTPStatusError{StatusCode: http.StatusServiceUnavailable}
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := resolver.ResolveAccountGeo(context.Background(), unavailable, "rFlaky"); !errors.Is(err, xrpl.ErrUpstreamUnavailable) {
+			t.Fatalf("expected upstream unavailable error, got %v", err)
+		}
+	}
+	if calls := unavailable.CommandCalls(""); calls != 2 {
+		t.Fatalf("expected transient errors not to be negative-cached, got %d calls", calls)
+	}
+}
+
+func TestResolveAccountGeoRetriesMissingAccountAfterTTL(t *testing.T) {
+	resolver := newTestResolver(t, filepath.Join(t.TempDir(), "geo-cache.json"))
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	resolver.clock = fake
+
+	client := xrpl.NewMockClient(func(method string, params interface{}) (interface{}, error) {
+		return nil, &xrpl.RPCError{Method: method, Code: "actNotFound"}
+	})
+	resolver.ResolveAccountGeo(context.Background(), client, "rMissing")
+	fake.Advance(resolver.missingAccountTTL - time.Second)
+	resolver.ResolveAccountGeo(context.Background(), client, "rMissing")
+	if calls := client.CommandCalls(""); calls != 1 {
+		t.Fatalf("expected the account to stay negative-cached within the TTL, got %d calls", calls)
+	}
+
+	fake.Advance(2 * time.Second)
+	resolver.ResolveAccountGeo(context.Background(), client, "rMissing")
+	if calls := client.CommandCalls(""); calls != 2 {
+		t.Fatalf("expected the account to be looked up again after the TTL, got %d calls", calls)
+	}
+}
+
+func TestResolveDomainGeoLoadsFromPersistedCache(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "geo-cache.json")
+
+	writer := newTestResolver(t, cachePath)
+	writer.dnsLookup = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("9.9.9.9")}, nil
+	}
+	writer.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+		return &models.GeoLocation{
+			Latitude:    48.8566,
+			Longitude:   2.3522,
+			CountryCode: "FR",
+			City:        "Paris",
+		}, nil
+	}
+	if _, err := writer.ResolveDomainGeo("example.org"); err != nil {
+		t.Fatalf("failed to prime cache: %v", err)
+	}
+
+	reader := newTestResolver(t, cachePath)
+	reader.dnsLookup = func(host string) ([]net.IP, error) {
+		t.Fatalf("dns lookup should not run when domain cache is loaded")
+		return nil, nil
+	}
+	reader.lookupGeoByIP = func(ip string) (*models.GeoLocation, error) {
+		t.Fatalf("geo lookup should not run when domain cache is loaded")
+		return nil, nil
+	}
+	reader.loadCache()
+
+	geo, err := reader.ResolveDomainGeo("example.org")
+	if err != nil {
+		t.Fatalf("ResolveDomainGeo from persisted cache failed: %v", err)
+	}
+	if geo == nil || geo.City != "Paris" {
+		t.Fatalf("expected persisted Paris geolocation, got %+v", geo)
+	}
+}
+
+func TestLoadCacheMigratesVersion1Cache(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "geo-cache.json")
+	v1 := `{"version":1,"entries":{"domain:example.org":{"country_code":"FR","city":"Paris","latitude":48.8566,"longitude":2.3522}}}`
+	if err := os.WriteFile(cachePath, []byte(v1), 0o644); err != nil {
+		t.Fatalf("failed to write v1 cache: %v", err)
+	}
+
+	resolver := newTestResolver(t, cachePath)
+	resolver.dnsLookup = func(host string) ([]net.IP, error) {
+		t.Fatalf("dns lookup should not run when the migrated cache is loaded")
+		return nil, nil
+	}
+	resolver.loadCache()
+
+	geo, err := resolver.ResolveDomainGeo("example.org")
+	if err != nil || geo == nil || geo.City != "Paris" {
+		t.Fatalf("expected the v1 Paris entry to survive migration, got %+v (%v)", geo, err)
+	}
+	if _, err := os.Stat(cachefile.BackupPath(cachePath, 1)); err != nil {
+		t.Fatalf("expected a v1 backup: %v", err)
+	}
+	data, _ := os.ReadFile(cachePath)
+	if !strings.Contains(string(data), `"version": 2`) {
+		t.Fatalf("expected the cache to be rewritten at version 2, got %s", data)
+	}
+}
+
+func TestResolveDomainASNCachesByDomain(t *testing.T) {
+	resolver := newTestResolver(t, filepath.Join(t.TempDir(), "geo-cache.json"))
+	if asn, err := resolver.ResolveDomainASN("example.org"); asn != 0 || err != nil {
+		t.Fatalf("expected no ASN without an ASN DB, got %d (%v)", asn, err)
+	}
+
+	lookups := 0
+	resolver.dnsLookup = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("203.0.113.10")}, nil
+	}
+	resolver.lookupASNByIP = func(ip string) (uint, error) {
+		lookups++
+		if ip != "203.0.113.10" {
+			t.Fatalf("unexpected IP %s", ip)
+		}
+		return 64500, nil
+	}
+	for i := 0; i < 2; i++ {
+		asn, err := resolver.ResolveDomainASN("https://Example.org/")
+		if err != nil || asn != 64500 {
+			t.Fatalf("expected AS64500, got %d (%v)", asn, err)
+		}
+	}
+	if lookups != 1 {
+		t.Fatalf("expected one ASN lookup, got %d", lookups)
+	}
+}
+
+func TestCachedGeoKeepsCoordinatesAndSharesNames(t *testing.T) {
+	r := newTestResolver(t, "")
+	r.setCachedGeo("domain:a.example", &models.GeoLocation{Latitude: 35.6895, Longitude: 139.6917, CountryCode: "JP", City: strings.Clone("Tokyo")})
+	r.setCachedGeo("domain:b.example", &models.GeoLocation{Latitude: -33.8688, Longitude: 151.2093, CountryCode: "AU", City: strings.Clone("Tokyo")})
+
+	a, ok := r.getCachedGeo("domain:a.example")
+	if !ok || a.Latitude != 35.6895 || a.Longitude != 139.6917 {
+		t.Fatalf("expected the cached coordinates back exactly, got %+v", a)
+	}
+	b, _ := r.getCachedGeo("domain:b.example")
+	if b.Latitude != -33.8688 || b.Longitude != 151.2093 {
+		t.Fatalf("expected the cached coordinates back exactly, got %+v", b)
+	}
+	if unsafe.StringData(a.City) != unsafe.StringData(b.City) {
+		t.Fatal("expected cached city names to be interned")
+	}
+}
+
+func TestDisabledGeoLiteResolvesFromCacheOnly(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "geo-cache.json")
+	seed := newTestResolver(t, cachePath)
+	seed.setCachedGeo("domain:seeded.example", &models.GeoLocation{Latitude: 52.52, Longitude: 13.405, CountryCode: "DE", City: "Berlin"})
+	if err := seed.persistCache(); err != nil {
+		t.Fatalf("failed to seed cache: %v", err)
+	}
+
+	// The DB path does not exist and auto-download is off, so an enabled
+	// resolver would fail to start.
+	resolver, err := NewResolver(logrus.New(), ResolverConfig{
+		CachePath:      cachePath,
+		GeoLiteDBPath:  filepath.Join(t.TempDir(), "missing.mmdb"),
+		ASNDBPath:      filepath.Join(t.TempDir(), "missing-asn.mmdb"),
+		DisableGeoLite: true,
+	})
+	if err != nil {
+		t.Fatalf("NewResolver failed: %v", err)
+	}
+	defer resolver.Close()
+	resolver.dnsLookup = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("192.0.2.7")}, nil
+	}
+
+	geo, err := resolver.ResolveDomainGeo("seeded.example")
+	if err != nil || geo.City != "Berlin" {
+		t.Fatalf("expected the seeded location, got %+v (%v)", geo, err)
+	}
+	if _, err := resolver.ResolveDomainGeo("unseeded.example"); !errors.Is(err, ErrGeoLiteDisabled) {
+		t.Fatalf("expected ErrGeoLiteDisabled, got %v", err)
+	}
+	if asn, err := resolver.ResolveDomainASN("seeded.example"); asn != 0 || err != nil {
+		t.Fatalf("expected no AS number without GeoLite, got %d (%v)", asn, err)
+	}
+	if !resolver.GeoLiteBuildTime().IsZero() {
+		t.Fatal("expected no GeoLite build time")
+	}
+	if err := resolver.RefreshGeoLite(); !errors.Is(err, ErrGeoLiteDisabled) {
+		t.Fatalf("expected refreshes to be disabled, got %v", err)
+	}
+}
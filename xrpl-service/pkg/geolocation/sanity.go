@@ -0,0 +1,59 @@
+package geolocation
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+// landCells is a coarse land mask in 10° cells. Rows run from 90°N down to
+// 90°S and columns from 180°W eastward; '#' marks a cell containing any land,
+// including small islands, and '.' open ocean. Polar rows are all land so
+// that only placements in open ocean are rejected.
+var landCells = [18]string{
+	"####################################", // 90..80
+	"####################################", // 80..70
+	"####################################", // 70..60
+	"##############..####################", // 60..50
+	".....########....#################..", // 50..40
+	".....#######..###################...", // 40..30
+	"###...#####.....##################..", // 30..20
+	".##...#######..################.#.##", // 20..10
+	"###.....######.#####################", // 10..0
+	"#####...#######.#.######.#.#########", // 0..-10
+	"#####.....#####..########..#########", // -10..-20
+	"#.####.#.#######..######.....#######", // -20..-30
+	"#........####...#..####..#...#####.#", // -30..-40
+	"#.........##.....#....#.###.....#.##", // -40..-50
+	"..........######..#.......#......##.", // -50..-60
+	"####################################", // -60..-70
+	"####################################", // -70..-80
+	"####################################", // -80..-90
+}
+
+// ValidCoordinates reports whether lat and lon are finite and in range.
+func ValidCoordinates(lat, lon float64) bool {
+	if math.IsNaN(lat) || math.IsNaN(lon) || math.IsInf(lat, 0) || math.IsInf(lon, 0) {
+		return false
+	}
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// OnLand reports whether valid coordinates fall in a 10° cell that contains
+// land. It only catches placements in open ocean, such as swapped or
+// truncated coordinates; coastal errors pass.
+func OnLand(lat, lon float64) bool {
+	row := int((90 - lat) / 10)
+	col := int((lon + 180) / 10)
+	row = min(max(row, 0), len(landCells)-1)
+	col = min(max(col, 0), len(landCells[row])-1)
+	return landCells[row][col] == '#'
+}
+
+// DistanceKm returns the great-circle distance between two points.
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
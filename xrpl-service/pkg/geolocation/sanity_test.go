@@ -0,0 +1,69 @@
+package geolocation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOnLandAcceptsDataCenterLocations(t *testing.T) {
+	places := map[string][2]float64{
+		"New York":      {40.7128, -74.0060},
+		"Ashburn":       {39.0438, -77.4874},
+		"San Jose":      {37.3382, -121.8863},
+		"Frankfurt":     {50.1109, 8.6821},
+		"London":        {51.5074, -0.1278},
+		"Reykjavik":     {64.1466, -21.9426},
+		"Singapore":     {1.3521, 103.8198},
+		"Tokyo":         {35.6762, 139.6503},
+		"Sydney":        {-33.8688, 151.2093},
+		"Auckland":      {-36.8485, 174.7633},
+		"Sao Paulo":     {-23.5505, -46.6333},
+		"Cape Town":     {-33.9249, 18.4241},
+		"Honolulu":      {21.3069, -157.8583},
+		"Bermuda":       {32.3078, -64.7505},
+		"Ponta Delgada": {37.7412, -25.6756},
+		"Port Louis":    {-20.1609, 57.5012},
+	}
+	for name, p := range places {
+		if !OnLand(p[0], p[1]) {
+			t.Errorf("expected %s (%v, %v) to be on land", name, p[0], p[1])
+		}
+	}
+}
+
+func TestOnLandRejectsOpenOcean(t *testing.T) {
+	points := [][2]float64{
+		{45, -160},          // North Pacific
+		{-45, -120},         // South Pacific
+		{-35, -5},           // South Atlantic
+		{-30, 80},           // Indian Ocean
+		{35, -45},           // North Atlantic
+		{33.8688, 151.2093}, // Sydney with the latitude sign lost
+	}
+	for _, p := range points {
+		if OnLand(p[0], p[1]) {
+			t.Errorf("expected (%v, %v) to be open ocean", p[0], p[1])
+		}
+	}
+}
+
+func TestValidCoordinates(t *testing.T) {
+	if !ValidCoordinates(90, 180) || !ValidCoordinates(-90, -180) {
+		t.Fatal("expected range bounds to be valid")
+	}
+	for _, p := range [][2]float64{{91, 0}, {0, -181}, {math.NaN(), 0}, {0, math.Inf(1)}} {
+		if ValidCoordinates(p[0], p[1]) {
+			t.Errorf("expected (%v, %v) to be invalid", p[0], p[1])
+		}
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	// London to New York is about 5570 km.
+	if d := DistanceKm(51.5074, -0.1278, 40.7128, -74.0060); d < 5500 || d > 5650 {
+		t.Fatalf("expected about 5570 km, got %.0f", d)
+	}
+	if d := DistanceKm(10, 20, 10, 20); d != 0 {
+		t.Fatalf("expected no distance, got %v", d)
+	}
+}
@@ -0,0 +1,987 @@
+// Package models holds the validator, transaction and stream event types
+// served by the validator service, with their JSON encodings.
+package models
+
+// Validator represents an XRPL validator with geolocation data
+type Validator struct {
+	// Validator Identifier
+	Address   string `json:"address"`    // Base58 validator address
+	PublicKey string `json:"public_key"` // Hex-encoded public key
+	Domain    string `json:"domain"`     // Domain name if available
+	Name      string `json:"name"`       // Human-readable name
+
+	// Network Info
+	Network string `json:"network"` // "altnet", "mainnet", etc.
+
+	// Geolocation Data
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	CountryCode string  `json:"country_code"`
+	City        string  `json:"city"`
+	Approximate bool    `json:"approximate,omitempty"` // placed at the country centroid
+
+	// StaleLocation is set when the location is kept by the coverage lock
+	// and has not been re-confirmed by geolocation within the lock TTL.
+	StaleLocation bool `json:"stale_location,omitempty"`
+
+	// Profile, from the operator's xrp-ledger.toml or the secondary registry
+	Icon        string `json:"icon,omitempty"`        // http(s) image URL
+	Twitter     string `json:"twitter,omitempty"`     // handle without @
+	Description string `json:"description,omitempty"` // at most 280 characters
+
+	// Trust badges, from the claims of the operator's xrp-ledger.toml
+	// checked against what the service observes
+	Owner           string `json:"owner,omitempty"`            // organization the xrp-ledger.toml names
+	VerifiedOwner   bool   `json:"verified_owner,omitempty"`   // named by a domain the validator's manifest attests
+	ConsistentVotes bool   `json:"consistent_votes,omitempty"` // included by the UNL its stanza claims
+
+	// Publishers are the attribution list sites whose latest list
+	// includes the validator
+	Publishers []string `json:"publishers,omitempty"`
+
+	// Operator is the ID of the operator cluster the validator belongs to
+	Operator string `json:"operator,omitempty"`
+
+	// Links are the validator's pages on the configured block explorers
+	Links []*ExplorerLink `json:"links,omitempty"`
+
+	// Peer port reachability of the domain's IP, when probing is enabled
+	// and the domain resolves to a public IP
+	PeerReachable *bool `json:"peer_reachable,omitempty"`
+	PeerPort      int   `json:"peer_port,omitempty"`       // port that accepted the connection
+	PeerCheckedAt int64 `json:"peer_checked_at,omitempty"` // Unix timestamp
+
+	// Current ephemeral signing key (hex) and the manifest sequence that
+	// announced it
+	SigningKey       string `json:"signing_key,omitempty"`
+	ManifestSequence uint32 `json:"manifest_sequence,omitempty"`
+
+	// Manifest is the base64 manifest as published in the validator list,
+	// republished by /validators/export
+	Manifest string `json:"-"`
+
+	// Metadata
+	LastUpdated int64 `json:"last_updated"` // Unix timestamp
+	IsActive    bool  `json:"is_active"`
+}
+
+// DomainChange records a validator's domain changing from OldDomain to
+// NewDomain. OldDomain is empty the first time a domain is seen.
+type DomainChange struct {
+	OldDomain string `json:"old_domain"`
+	NewDomain string `json:"new_domain"`
+	Source    string `json:"source"` // "validator_list", "secondary_registry"
+	ChangedAt int64  `json:"changed_at"`
+}
+
+// DomainHistory is the recorded domain audit trail of one validator, oldest
+// change first.
+type DomainHistory struct {
+	Address       string          `json:"address"`
+	CurrentDomain string          `json:"current_domain"`
+	Changes       []*DomainChange `json:"changes"`
+}
+
+// KeyRotation records a validator moving to a new signing key, announced by
+// a manifest with a higher sequence. Address is the validator's master key,
+// which does not change.
+type KeyRotation struct {
+	Address       string `json:"address"`
+	OldSigningKey string `json:"old_signing_key"`
+	NewSigningKey string `json:"new_signing_key"`
+	OldSequence   uint32 `json:"old_sequence"`
+	NewSequence   uint32 `json:"new_sequence"`
+	RotatedAt     int64  `json:"rotated_at"`
+}
+
+// KeyHistory is the recorded signing key rotations of one validator, oldest
+// first.
+type KeyHistory struct {
+	Address          string         `json:"address"`
+	SigningKey       string         `json:"signing_key"`
+	ManifestSequence uint32         `json:"manifest_sequence"`
+	Rotations        []*KeyRotation `json:"rotations"`
+}
+
+// Validator list publisher alerts.
+const (
+	PublisherAlertUnknownKey       = "unknown_key"       // master key outside the configured chains, or an unexpected change
+	PublisherAlertManifestMismatch = "manifest_mismatch" // manifest missing, unparseable or for another key
+	PublisherAlertRevoked          = "revoked"           // manifest revokes the publisher key
+)
+
+// Validator list expiry alerts.
+const (
+	ListExpiryExpiring = "expiring" // expires within the configured warning period
+	ListExpiryExpired  = "expired"  // rippled no longer trusts the list
+)
+
+// PublisherKeyRotation records a validator list publisher moving to a new
+// key: a new signing key announced by its manifest, or a new master key along
+// a configured rotation chain.
+type PublisherKeyRotation struct {
+	Kind        string `json:"kind"` // "signing" or "master"
+	OldKey      string `json:"old_key"`
+	NewKey      string `json:"new_key"`
+	OldSequence uint32 `json:"old_sequence"`
+	NewSequence uint32 `json:"new_sequence"`
+	RotatedAt   int64  `json:"rotated_at"`
+}
+
+// ValidatorListPublisher is the key a validator list site publishes under.
+// MasterKey and SigningKey are the last accepted keys; while Alert is set the
+// site presents PresentedKey instead.
+type ValidatorListPublisher struct {
+	Site             string                  `json:"site"`
+	MasterKey        string                  `json:"master_key,omitempty"`
+	SigningKey       string                  `json:"signing_key,omitempty"`
+	ManifestSequence uint32                  `json:"manifest_sequence"`
+	FirstSeenAt      int64                   `json:"first_seen_at"`
+	LastSeenAt       int64                   `json:"last_seen_at"`
+	Rotations        []*PublisherKeyRotation `json:"rotations,omitempty"`
+	Alert            string                  `json:"alert,omitempty"`
+	PresentedKey     string                  `json:"presented_key,omitempty"`
+	AlertSince       int64                   `json:"alert_since,omitempty"`
+	ListSequence     int64                   `json:"list_sequence,omitempty"`   // sequence of the last list fetched
+	ListExpiration   int64                   `json:"list_expiration,omitempty"` // unix seconds the last list fetched expires
+	ListExpiry       string                  `json:"list_expiry,omitempty"`     // "expiring" or "expired"
+}
+
+// ValidatorNote is an operator annotation on a validator, e.g. "contacted
+// about domain mismatch".
+type ValidatorNote struct {
+	Text      string   `json:"text"`
+	Tags      []string `json:"tags,omitempty"`
+	Author    string   `json:"author,omitempty"`
+	CreatedAt int64    `json:"created_at"` // unix milliseconds
+}
+
+// ValidatorNotes is the operator notes of one validator, oldest first.
+type ValidatorNotes struct {
+	Address string           `json:"address"`
+	Notes   []*ValidatorNote `json:"notes"`
+}
+
+// MetadataAuditRecord is one change to a validator's persisted metadata.
+// The old value is kept, so an overwritten value can be recovered from the
+// audit trail.
+type MetadataAuditRecord struct {
+	Timestamp int64  `json:"timestamp"` // unix seconds
+	Address   string `json:"address"`
+	Field     string `json:"field"` // "entry", "domain", "name", "location", "signing_key", "icon", "twitter", "description", "owner", "note"
+	OldValue  string `json:"old_value,omitempty"`
+	NewValue  string `json:"new_value,omitempty"`
+	Source    string `json:"source"` // what made the change, e.g. "geolocation", "coverage_lock", "validator_list"
+}
+
+// MetadataAuditFilter selects metadata audit records. Zero fields match
+// every record; Limit bounds how many of the newest are returned.
+type MetadataAuditFilter struct {
+	Address string
+	Field   string
+	Source  string
+	Since   int64 // unix seconds
+	Limit   int
+}
+
+// NetworkReport summarizes one reporting period for webhooks and status
+// pages.
+type NetworkReport struct {
+	Period      string `json:"period"` // "daily", "weekly"
+	PeriodStart int64  `json:"period_start"`
+	PeriodEnd   int64  `json:"period_end"`
+	Network     string `json:"network"`
+
+	Validators       ValidatorCountSummary   `json:"validators"`
+	Decentralization DecentralizationSummary `json:"decentralization"`
+	Volume           VolumeSummary           `json:"volume"`
+	TopCorridors     []*CorridorSummary      `json:"top_corridors"`
+}
+
+// ValidatorCountSummary compares the validator set at the start and end of a
+// report period.
+type ValidatorCountSummary struct {
+	Start   int      `json:"start"`
+	End     int      `json:"end"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// DecentralizationSummary describes how validators are spread across
+// countries. NakamotoCoefficient is the fewest countries whose validators
+// together exceed 20% of the mapped set, enough to stall consensus.
+type DecentralizationSummary struct {
+	MappedValidators    int     `json:"mapped_validators"`
+	Countries           int     `json:"countries"`
+	TopCountry          string  `json:"top_country"`
+	TopCountryShare     float64 `json:"top_country_share"`
+	CountryHHI          float64 `json:"country_hhi"`
+	NakamotoCoefficient int     `json:"nakamoto_coefficient"`
+}
+
+// DecentralizationSnapshot counts validators per country, AS number and
+// operator at one point in time. Validators without a known country or AS
+// number are left out of those counts.
+type DecentralizationSnapshot struct {
+	Timestamp  int64          `json:"timestamp"` // unix seconds
+	Validators int            `json:"validators"`
+	Countries  map[string]int `json:"countries"`
+	ASNs       map[string]int `json:"asns"` // Keyed "AS<number>"
+	Operators  map[string]int `json:"operators"`
+}
+
+// DecentralizationDelta is the change in validators of one country, AS
+// number or operator between two snapshots.
+type DecentralizationDelta struct {
+	Key    string `json:"key"`
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+	Change int    `json:"change"`
+}
+
+// DecentralizationPoint identifies a side of a comparison. Live is set when
+// it is the current validator set rather than a stored snapshot.
+type DecentralizationPoint struct {
+	Timestamp  int64 `json:"timestamp"` // unix seconds
+	Validators int   `json:"validators"`
+	Live       bool  `json:"live,omitempty"`
+}
+
+// DecentralizationComparison is the change in validator spread between two
+// points, largest changes first.
+type DecentralizationComparison struct {
+	From      DecentralizationPoint    `json:"from"`
+	To        DecentralizationPoint    `json:"to"`
+	Countries []*DecentralizationDelta `json:"countries"`
+	ASNs      []*DecentralizationDelta `json:"asns"`
+	Operators []*DecentralizationDelta `json:"operators"`
+}
+
+// Census is the payload of a daily validator census artifact, a signed
+// record of the validator set and where it runs on one UTC day.
+type Census struct {
+	Version           int                       `json:"version"`
+	Date              string                    `json:"date"` // UTC day, YYYY-MM-DD
+	Network           string                    `json:"network,omitempty"`
+	GeneratedAt       int64                     `json:"generated_at"`          // unix seconds
+	ValidatorsFetched int64                     `json:"validators_fetched_at"` // unix seconds of the fetch the set is from
+	Count             int                       `json:"count"`
+	Validators        []*Validator              `json:"validators"`
+	Decentralization  DecentralizationSummary   `json:"decentralization"`
+	Spread            *DecentralizationSnapshot `json:"spread,omitempty"`  // validators per country, AS number and operator
+	Sources           *SourceStatus             `json:"sources,omitempty"` // validator sources of that fetch
+}
+
+// CensusEntry lists one stored census artifact at /census.
+type CensusEntry struct {
+	Date string `json:"date"` // UTC day, YYYY-MM-DD
+	Name string `json:"name"` // file name
+	Size int64  `json:"size"` // bytes
+	URL  string `json:"url"`  // download path
+}
+
+// Operator aggregates the validators run by one entity. Validators sharing
+// a domain apex, registry owner or non-hosting AS number are grouped; ID is
+// the group's smallest domain apex, else owner, else AS number, else the
+// address of its only validator.
+type Operator struct {
+	ID             string   `json:"id"`
+	ValidatorCount int      `json:"validator_count"`
+	Share          float64  `json:"share"` // Of all validators
+	Validators     []string `json:"validators"`
+	Domains        []string `json:"domains,omitempty"`
+	Countries      []string `json:"countries,omitempty"`
+}
+
+// OperatorSummary describes how validators are spread across operators.
+// NakamotoCoefficient is the fewest operators whose validators together
+// exceed 20% of the set.
+type OperatorSummary struct {
+	ValidatorCount      int         `json:"validator_count"`
+	OperatorCount       int         `json:"operator_count"`
+	TopOperatorShare    float64     `json:"top_operator_share"`
+	NakamotoCoefficient int         `json:"nakamoto_coefficient"`
+	Operators           []*Operator `json:"operators"`
+}
+
+// VolumeSummary totals the payments visualized during a report period.
+type VolumeSummary struct {
+	Transactions int64   `json:"transactions"`
+	Payments     int64   `json:"payments"`
+	XRP          float64 `json:"xrp"`
+	Flagged      int64   `json:"flagged"` // Transactions matching the compliance watchlist
+}
+
+// CorridorSummary totals payments between two countries.
+type CorridorSummary struct {
+	SourceCountry      string  `json:"source_country"`
+	DestinationCountry string  `json:"destination_country"`
+	Payments           int64   `json:"payments"`
+	XRP                float64 `json:"xrp"`
+}
+
+// Transaction represents an XRP Ledger transaction
+type Transaction struct {
+	// Transaction Identifier
+	Hash        string `json:"hash"` // Transaction hash
+	LedgerIndex uint32 `json:"ledger_index"`
+
+	// Parties Involved
+	Account     string `json:"account"`     // Source account
+	Destination string `json:"destination"` // Destination account
+
+	// Transaction Details
+	TransactionType string `json:"transaction_type"` // "Payment", "TrustSet", etc.
+	Amount          string `json:"amount"`           // Amount in drops or JSON object
+	Fee             string `json:"fee"`              // Fee in drops
+
+	// DisplayWeight sizes the transaction's arc, computed from the amount
+	// in the configured weight mode
+	DisplayWeight float64 `json:"display_weight,omitempty"`
+
+	// Status
+	TransactionResult string `json:"transaction_result"` // "tesSUCCESS", etc.
+
+	// Timestamp
+	Timestamp    int64  `json:"timestamp"`                // Unix ledger close time, or receipt time when unknown
+	CloseTime    uint32 `json:"close_time"`               // Ledger close time, seconds since the Ripple epoch
+	CloseTimeISO string `json:"close_time_iso,omitempty"` // Ledger close time, RFC 3339 UTC
+
+	// Metadata
+	Validated     bool              `json:"validated"`
+	Provisional   bool              `json:"provisional,omitempty"` // Previewed from transactions_proposed; settled by a later TxSettlement
+	Locations     []*GeoLocation    `json:"locations,omitempty"`   // Mapped account endpoints for hotspot/activity layers
+	Tags          map[string]string `json:"tags,omitempty"`        // Labels added by custom transaction processors
+	Links         []*ExplorerLink   `json:"links,omitempty"`       // Pages on the configured block explorers
+	GeoCandidates []string          `json:"-"`                     // Internal candidate accounts for enrichment
+
+	// Ledger objects
+	CreatedAccounts []string `json:"created_accounts,omitempty"` // Accounts whose AccountRoot this transaction created
+
+	// Compliance
+	Flagged     bool     `json:"flagged,omitempty"`      // Matched the compliance watchlist
+	FlagReasons []string `json:"flag_reasons,omitempty"` // "source_country:KP", "destination_account", etc.
+
+	// Processing times, unix milliseconds, for latency from ledger close to screen
+	ReceivedAt  int64 `json:"received_at,omitempty"`  // Read from the upstream stream
+	EnrichedAt  int64 `json:"enriched_at,omitempty"`  // Geo enrichment finished; unset when forwarded without it
+	BroadcastAt int64 `json:"broadcast_at,omitempty"` // Handed to WebSocket clients
+}
+
+// NewAccountStats counts accounts created on the ledger over a window, by
+// the location of the account that funded them.
+type NewAccountStats struct {
+	Freshness
+	Window    string              `json:"window"` // "24h", "7d"
+	Since     int64               `json:"since"`  // unix seconds the window starts at
+	Total     int                 `json:"total"`
+	Unlocated int                 `json:"unlocated"` // funding source could not be geolocated
+	Countries map[string]int      `json:"countries"`
+	Regions   []*NewAccountRegion `json:"regions"`
+}
+
+// NewAccountRegion is one city-level point of the new account heatmap.
+type NewAccountRegion struct {
+	CountryCode string  `json:"country_code"`
+	City        string  `json:"city"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Count       int     `json:"count"`
+}
+
+// LedgerFees totals the transaction fees one validated ledger destroyed.
+type LedgerFees struct {
+	LedgerIndex  uint32 `json:"ledger_index"`
+	CloseTime    int64  `json:"close_time,omitempty"` // unix seconds
+	Transactions int    `json:"transactions"`
+	FeeDrops     int64  `json:"fee_drops"`
+}
+
+// FeeBurn is pushed as a fee_burn event for each validated ledger, with the
+// running totals as of that ledger.
+type FeeBurn struct {
+	LedgerFees
+	CumulativeDrops int64   `json:"cumulative_drops"` // since the service started
+	XRPPerHour1h    float64 `json:"xrp_per_hour_1h"`
+	XRPPerHour24h   float64 `json:"xrp_per_hour_24h"`
+}
+
+// BurnWindow is the fee burn over a rolling window.
+type BurnWindow struct {
+	Ledgers      int64   `json:"ledgers"`
+	Transactions int64   `json:"transactions"`
+	Drops        int64   `json:"drops"`
+	XRPPerHour   float64 `json:"xrp_per_hour"`
+}
+
+// BurnTotals summarizes the transaction fees destroyed since the service
+// started, with rolling burn rates.
+type BurnTotals struct {
+	Freshness
+	Since           int64                  `json:"since"` // unix seconds
+	LastLedgerIndex uint32                 `json:"last_ledger_index"`
+	Ledgers         int64                  `json:"ledgers"`
+	Transactions    int64                  `json:"transactions"`
+	Drops           int64                  `json:"drops"`
+	XRP             float64                `json:"xrp"`
+	Windows         map[string]*BurnWindow `json:"windows"` // "1h", "24h"
+}
+
+// TransactionShape describes a streamed transaction for the ledger
+// composition statistics. Shapes are taken before the listener's filters.
+type TransactionShape struct {
+	TransactionType  string
+	SizeBytes        int // JSON size of the transaction object
+	HasMemos         bool
+	LedgerEntryTypes []string // ledger objects created, modified or deleted
+}
+
+// SizeBucket counts transactions up to MaxBytes in size and above the
+// previous bucket's bound.
+type SizeBucket struct {
+	MaxBytes int   `json:"max_bytes,omitempty"` // unset on the last, unbounded bucket
+	Count    int64 `json:"count"`
+}
+
+// TxDistributions summarizes the composition of every streamed transaction
+// since the service started.
+type TxDistributions struct {
+	Freshness
+	Since         int64            `json:"since"` // unix seconds
+	Transactions  int64            `json:"transactions"`
+	WithMemos     int64            `json:"with_memos"`
+	MeanSizeBytes float64          `json:"mean_size_bytes"`
+	Sizes         []*SizeBucket    `json:"sizes"`
+	Types         map[string]int64 `json:"types"`
+	ObjectTypes   map[string]int64 `json:"object_types"` // per affected ledger object
+}
+
+// ActivityStats totals the streamed transactions and validator states of a
+// rolling window of the last 24 hours.
+type ActivityStats struct {
+	Freshness
+	Window       string           `json:"window"` // "1h", "24h"
+	Since        int64            `json:"since"`  // unix seconds the window starts at
+	Transactions int64            `json:"transactions"`
+	Payments     int64            `json:"payments"`
+	XRP          float64          `json:"xrp"` // XRP amounts of payments
+	Flagged      int64            `json:"flagged"`
+	Types        map[string]int64 `json:"types"`
+	Validators   *ValidatorRange  `json:"validators,omitempty"` // omitted before the first validator sample
+}
+
+// ValidatorRange is the span of validator counts sampled over a window,
+// with the latest sample.
+type ValidatorRange struct {
+	Latest       int `json:"latest"`
+	Min          int `json:"min"`
+	Max          int `json:"max"`
+	LatestActive int `json:"latest_active"`
+}
+
+// Hotspots ranks the cities transactions were sent from or to over a
+// window.
+type Hotspots struct {
+	Freshness
+	Window   string     `json:"window"`
+	Since    int64      `json:"since"` // unix seconds
+	Hotspots []*Hotspot `json:"hotspots"`
+}
+
+// Hotspot is one city-level point of the activity heatmap. Transactions
+// count each transaction once per end located there.
+type Hotspot struct {
+	CountryCode  string  `json:"country_code"`
+	City         string  `json:"city"`
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	Transactions int64   `json:"transactions"`
+}
+
+// ActivityTimeline is the per-minute activity of a window, oldest first,
+// for replaying it without the individual transactions.
+type ActivityTimeline struct {
+	Freshness
+	Window string           `json:"window"`
+	Since  int64            `json:"since"` // unix seconds
+	Points []*ActivityPoint `json:"points"`
+}
+
+// ActivityPoint is one minute of activity. Minutes with neither
+// transactions nor a validator sample are omitted. Validators is the latest
+// validator count sampled by the end of the minute, omitted before the
+// first sample.
+type ActivityPoint struct {
+	Minute       int64      `json:"minute"` // unix seconds the minute starts at
+	Transactions int64      `json:"transactions"`
+	Payments     int64      `json:"payments"`
+	XRP          float64    `json:"xrp"`
+	Validators   *int       `json:"validators,omitempty"`
+	Hotspots     []*Hotspot `json:"hotspots,omitempty"` // busiest cities of the minute, at most 3
+}
+
+// TransactionSummary is the reduced form of a Transaction sent to WebSocket
+// clients that have exceeded their bandwidth budget.
+type TransactionSummary struct {
+	Hash            string         `json:"hash"`
+	TransactionType string         `json:"transaction_type"`
+	Amount          string         `json:"amount"`
+	DisplayWeight   float64        `json:"display_weight,omitempty"`
+	Locations       []*GeoLocation `json:"locations,omitempty"`
+	Summary         bool           `json:"summary"`
+	Flagged         bool           `json:"flagged,omitempty"`
+}
+
+// TxGeoUpdate carries locations resolved for a transaction after it was
+// already broadcast without them.
+type TxGeoUpdate struct {
+	Hash        string         `json:"hash"`
+	LedgerIndex uint32         `json:"ledger_index"`
+	Locations   []*GeoLocation `json:"locations"`
+	EnrichedAt  int64          `json:"enriched_at,omitempty"` // unix milliseconds
+}
+
+// Settlement statuses of a provisional transaction.
+const (
+	SettlementConfirmed = "confirmed"
+	SettlementCancelled = "cancelled"
+)
+
+// Reasons a provisional transaction was cancelled.
+const (
+	SettlementReasonRejected = "rejected" // validated, but failed or filtered out
+	SettlementReasonExpired  = "expired"  // not validated before its last ledger or the preview TTL
+)
+
+// TxSettlement reports the validated outcome of a transaction that was
+// forwarded provisionally.
+type TxSettlement struct {
+	Hash              string `json:"hash"`
+	Status            string `json:"status"`                       // SettlementConfirmed or SettlementCancelled
+	Reason            string `json:"reason,omitempty"`             // set when cancelled
+	LedgerIndex       uint32 `json:"ledger_index,omitempty"`       // ledger that validated it
+	TransactionResult string `json:"transaction_result,omitempty"` // validated engine result
+	SettledAt         int64  `json:"settled_at"`                   // unix milliseconds
+}
+
+// GeoLocation represents geographic location data
+type GeoLocation struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	CountryCode      string  `json:"country_code"`
+	City             string  `json:"city"`
+	Approximate      bool    `json:"approximate,omitempty"` // placed at the country centroid
+	ValidatorAddress string  `json:"validator_address,omitempty"`
+	Role             string  `json:"role,omitempty"` // LocationRole* on transaction locations
+}
+
+// ServerStatus represents XRPL server health status
+type ServerStatus struct {
+	Connected       bool   `json:"connected"`
+	ServerState     string `json:"server_state"`
+	LedgerIndex     uint32 `json:"ledger_index"`
+	NetworkID       uint16 `json:"network_id"`
+	PeerCount       int    `json:"peer_count"`
+	CompleteLedgers string `json:"complete_ledgers"`
+	Uptime          int64  `json:"uptime"`
+	LastSync        int64  `json:"last_sync"`
+
+	// ValidatedLedgerAge is the age in seconds of the last validated ledger.
+	ValidatedLedgerAge int64 `json:"validated_ledger_age"`
+
+	// AmendmentBlocked is set when the server lacks an enabled amendment and
+	// can no longer follow the network. ReportingMode is set for reporting
+	// mode servers, which do not take part in consensus.
+	AmendmentBlocked bool `json:"amendment_blocked"`
+	ReportingMode    bool `json:"reporting_mode"`
+
+	// Parsed complete_ledgers history
+	CompleteLedgerSpan int64         `json:"complete_ledger_span"`
+	OldestLedger       uint32        `json:"oldest_ledger"`
+	LedgerGaps         []LedgerRange `json:"ledger_gaps,omitempty"`
+}
+
+// Freshness reports when the data behind a response was last updated and
+// where it came from, as also sent in the X-Data-Last-Updated and
+// X-Data-Source headers.
+type Freshness struct {
+	DataLastUpdated int64  `json:"data_last_updated,omitempty"` // unix seconds
+	DataSource      string `json:"data_source,omitempty"`
+}
+
+// LoadSignals are the normalized load indicators served at /load for
+// autoscalers. Percentages are of each signal's capacity or target and may
+// exceed 100 for the upstream lag.
+type LoadSignals struct {
+	Timestamp                        int64   `json:"timestamp"`
+	LoadPercent                      float64 `json:"load_percent"` // highest of the percentages below
+	EnrichmentQueueSaturationPercent float64 `json:"enrichment_queue_saturation_percent"`
+	BroadcastSaturationPercent       float64 `json:"broadcast_saturation_percent"`
+	UpstreamLagSeconds               float64 `json:"upstream_lag_seconds"`
+	UpstreamLagPercent               float64 `json:"upstream_lag_percent"`
+	UpstreamLagTargetSeconds         float64 `json:"upstream_lag_target_seconds"`
+	WebSocketClients                 int     `json:"websocket_clients"`
+}
+
+// UpstreamStatus is the state of the transaction stream and of geolocation
+// enrichment, reported by /health.
+type UpstreamStatus struct {
+	Connected                bool  `json:"connected"`
+	Subscribed               bool  `json:"subscribed"`
+	LastTransactionAt        int64 `json:"last_transaction_at,omitempty"` // unix milliseconds
+	EnrichmentQueueDepth     int   `json:"enrichment_queue_depth"`
+	EnrichmentQueueCapacity  int   `json:"enrichment_queue_capacity"`
+	LateEnrichmentQueueDepth int   `json:"late_enrichment_queue_depth"`
+}
+
+// LedgerRange is an inclusive range of ledger sequence numbers.
+type LedgerRange struct {
+	Start uint32 `json:"start"`
+	End   uint32 `json:"end"`
+}
+
+// ServerStatusChange describes a material change between two polled server statuses.
+type ServerStatusChange struct {
+	Previous *ServerStatus  `json:"previous,omitempty"`
+	Current  *ServerStatus  `json:"current"`
+	Reasons  []string       `json:"reasons"` // "initial", "server_state", "ledger_lag", "history_shrink", "amendment_blocked", "reporting_mode"
+	Lagging  bool           `json:"lagging"`
+	Alerts   []*ServerAlert `json:"alerts,omitempty"`
+}
+
+// ServerAlert reports a server condition that needs operator attention
+// starting (Active) or clearing.
+type ServerAlert struct {
+	Condition   string `json:"condition"` // "amendment_blocked", "reporting_mode"
+	Active      bool   `json:"active"`
+	ServerState string `json:"server_state"`
+	Message     string `json:"message"`
+}
+
+// WatchdogAlert reports a stalled pipeline starting (Active) or recovering.
+type WatchdogAlert struct {
+	Check    string `json:"check"` // "transactions_stalled", "validator_fetch_stalled"
+	Active   bool   `json:"active"`
+	LastSeen int64  `json:"last_seen"` // unix seconds of the last transaction or successful fetch, 0 if never
+	Message  string `json:"message"`
+}
+
+// SLOAlert reports the validator set leaving or returning to an
+// operator-configured bound.
+type SLOAlert struct {
+	Check   string  `json:"check"` // "validator_count_low", "validator_count_high", "validator_coverage_low"
+	Active  bool    `json:"active"`
+	Value   float64 `json:"value"` // validator count, or mapped coverage in percent
+	Bound   float64 `json:"bound"`
+	Message string  `json:"message"`
+}
+
+// Anomaly reports a network metric that moved unusually far from its recent
+// moving average, and is sent again with Active false once it settles.
+type Anomaly struct {
+	Metric        string                 `json:"metric"` // "tx_rate", "avg_payment_xrp", "validator_count", "geo_concentration"
+	Active        bool                   `json:"active"`
+	Direction     string                 `json:"direction"` // "spike", "drop"
+	Value         float64                `json:"value"`
+	Mean          float64                `json:"mean"`
+	StdDev        float64                `json:"std_dev"`
+	ZScore        float64                `json:"z_score"`
+	WindowSeconds int                    `json:"window_seconds"`
+	DetectedAt    int64                  `json:"detected_at"`       // unix seconds the anomaly started
+	Context       map[string]interface{} `json:"context,omitempty"` // e.g. "transactions", "top_country"
+	Message       string                 `json:"message"`
+}
+
+// ValidatorDelta identifies a validator and, for upserts, the JSON fields
+// that changed since the previous fetch cycle.
+type ValidatorDelta struct {
+	Address string                 `json:"address"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+
+	// Countries are the validator's country codes before and after the
+	// change, so filtered views can tell whether the validator is theirs.
+	// They are not sent.
+	Countries []string `json:"-"`
+}
+
+// ValidatorUpdate describes the differences between two validator fetch cycles.
+type ValidatorUpdate struct {
+	Upserts  []*ValidatorDelta `json:"upserts"`
+	Removals []*ValidatorDelta `json:"removals"`
+}
+
+// StreamEvent is a non-transaction message pushed to WebSocket clients.
+type StreamEvent struct {
+	Type        string      `json:"type"` // "server_status", "validator_upsert", etc.
+	Timestamp   int64       `json:"timestamp"`
+	Data        interface{} `json:"data"`
+	BroadcastAt int64       `json:"broadcast_at,omitempty"` // unix milliseconds, when handed to clients
+
+	// Countries and Transaction scope the event for filtered views and are
+	// not sent: the country codes of the validator a validator event
+	// concerns, and the provisional transaction a tx_settlement settles.
+	Countries   []string     `json:"-"`
+	Transaction *Transaction `json:"-"`
+}
+
+// WebSocket subprotocols of the /transactions stream, negotiated through
+// Sec-WebSocket-Protocol. Clients that offer none get StreamProtocolV1.
+const (
+	// StreamProtocolV1 sends transactions as bare objects and everything
+	// else as StreamEvents.
+	StreamProtocolV1 = "xrplviz.v1"
+
+	// StreamProtocolV2 sends every message as a StreamEvent, transactions
+	// with type "transaction", and opens with a "hello" event.
+	StreamProtocolV2 = "xrplviz.v2"
+)
+
+// StreamHello is the "hello" event opening a StreamProtocolV2 stream.
+type StreamHello struct {
+	Protocol      string   `json:"protocol"`
+	Protocols     []string `json:"protocols"` // supported by the server, newest first
+	ServerVersion string   `json:"server_version"`
+	Shape         string   `json:"shape"`
+	Topics        []string `json:"topics,omitempty"`
+}
+
+// PeerInfo describes a single peer connection of the local XRPL server.
+type PeerInfo struct {
+	IP        string       `json:"ip"`
+	Version   string       `json:"version"`
+	Inbound   bool         `json:"inbound"`
+	LatencyMs int64        `json:"latency_ms"`
+	Uptime    int64        `json:"uptime"`
+	Location  *GeoLocation `json:"location,omitempty"`
+}
+
+// PeerSummary aggregates the local XRPL server's peer connections.
+type PeerSummary struct {
+	Total     int            `json:"total"`
+	Inbound   int            `json:"inbound"`
+	Outbound  int            `json:"outbound"`
+	Versions  map[string]int `json:"versions"`
+	Located   int            `json:"located"`
+	Peers     []*PeerInfo    `json:"peers"`
+	Timestamp int64          `json:"timestamp"`
+}
+
+// IssuerGraph is a snapshot of an issuer's trust lines for an
+// issuer-centric network view: the issuer at the center and its largest
+// holders around it.
+type IssuerGraph struct {
+	Issuer      string            `json:"issuer"`
+	Location    *GeoLocation      `json:"location,omitempty"`
+	Obligations map[string]string `json:"obligations"` // currency -> total issued
+	TrustLines  int               `json:"trust_lines"` // lines scanned
+	Truncated   bool              `json:"truncated"`   // the issuer has more lines than were scanned
+	Located     int               `json:"located"`
+	Holders     []*IssuerHolder   `json:"holders"`
+	UpdatedAt   int64             `json:"updated_at"`
+}
+
+// IssuerHolder is an account holding an issuer's tokens. Share is its
+// largest line as a fraction of that currency's obligations.
+type IssuerHolder struct {
+	Account  string       `json:"account"`
+	Share    float64      `json:"share"`
+	Lines    []*TrustLine `json:"lines"`
+	Location *GeoLocation `json:"location,omitempty"`
+}
+
+// TrustLine is a holder's balance of one of the issuer's currencies.
+type TrustLine struct {
+	Currency string `json:"currency"`
+	Balance  string `json:"balance"`
+	Limit    string `json:"limit"`
+}
+
+// EnrichmentRule sets transaction tags when its When expression holds, e.g.
+// {"name":"exchange_flow","when":"tags.source_label != \"\" && tags.dest_label != \"\"","set":{"category":"exchange_flow"}}.
+type EnrichmentRule struct {
+	Name string            `json:"name"`
+	When string            `json:"when"`
+	Set  map[string]string `json:"set"`
+}
+
+// WatchlistStatus describes the compliance watchlist for /admin/watchlist.
+type WatchlistStatus struct {
+	Enabled      bool  `json:"enabled"`
+	Countries    int   `json:"countries"`
+	Accounts     int   `json:"accounts"`
+	FlaggedTotal int64 `json:"flagged_total"` // Transactions flagged since startup
+}
+
+// Account label review statuses. A label without a status has not been
+// through review and is applied like an approved one.
+const (
+	LabelStatusPending  = "pending"
+	LabelStatusApproved = "approved"
+	LabelStatusRejected = "rejected"
+)
+
+// AccountLabel names an account, e.g. an exchange hot wallet. Labels are
+// applied to transactions as the source_label and dest_label tags.
+type AccountLabel struct {
+	Account     string `json:"account"`
+	Label       string `json:"label"`
+	SubmittedBy string `json:"submitted_by"`     // Who submitted or last corrected the label
+	Source      string `json:"source,omitempty"` // Evidence for the label, e.g. a URL
+	Status      string `json:"status,omitempty"` // Review status; pending and rejected labels are not applied
+	CreatedAt   int64  `json:"created_at"`       // unix milliseconds
+	UpdatedAt   int64  `json:"updated_at"`       // unix milliseconds
+}
+
+// Amount units of streamed transactions.
+const (
+	UnitsDrops = "drops"
+	UnitsXRP   = "xrp"
+)
+
+// ClientPreferences are the stream defaults of one API key, applied to
+// WebSocket clients connecting with it. Query parameters override them.
+type ClientPreferences struct {
+	Name            string   `json:"name"`                        // API key name from API_KEYS
+	MinPaymentDrops int64    `json:"min_payment_drops,omitempty"` // streams only payments of at least this amount
+	Channels        []string `json:"channels,omitempty"`          // "transactions", "server_status", ...; empty allows all
+	Units           string   `json:"units,omitempty"`             // amount and fee units, drops (default) or xrp
+	Shape           string   `json:"shape,omitempty"`             // transaction wire shape, as ?shape=
+	Topics          []string `json:"topics,omitempty"`            // corridor topics, as ?topics=
+	Provisional     bool     `json:"provisional,omitempty"`       // as ?provisional=true
+	UpdatedAt       int64    `json:"updated_at,omitempty"`        // unix milliseconds
+}
+
+// FetchStage is the progress of one stage of a validator fetch cycle.
+type FetchStage struct {
+	Name       string `json:"name"`
+	StartedAt  int64  `json:"started_at"`        // unix milliseconds
+	DurationMS int64  `json:"duration_ms"`       // elapsed so far while running
+	Validators int    `json:"validators"`        // validators known after the stage
+	Error      string `json:"error,omitempty"`   // stage-level failure; the cycle may still succeed
+	Running    bool   `json:"running,omitempty"` // the stage is in progress
+}
+
+// FetchCycle summarizes a validator fetch cycle, running or completed.
+type FetchCycle struct {
+	StartedAt  int64         `json:"started_at"`            // unix milliseconds
+	FinishedAt int64         `json:"finished_at,omitempty"` // unix milliseconds; 0 while running
+	DurationMS int64         `json:"duration_ms"`
+	Validators int           `json:"validators"`
+	Error      string        `json:"error,omitempty"` // why the cycle failed
+	Stages     []*FetchStage `json:"stages"`
+}
+
+// SourceStatus lists the validator sources, by fetch stage name, that
+// succeeded and failed in the fetch cycle that built the current snapshot.
+type SourceStatus struct {
+	OK     []string `json:"sources_ok"`
+	Failed []string `json:"sources_failed"`
+}
+
+// FetchStatus is the validator fetcher's progress for /admin/fetch-status.
+type FetchStatus struct {
+	Running bool        `json:"running"`
+	Stage   string      `json:"stage,omitempty"`   // stage in progress
+	Current *FetchCycle `json:"current,omitempty"` // cycle in progress
+	Last    *FetchCycle `json:"last,omitempty"`    // last completed cycle
+	Cycles  int64       `json:"cycles"`            // completed cycles since startup
+}
+
+// IngestionStatus describes whether upstream ingestion is paused, for
+// /admin/ingestion and /health.
+type IngestionStatus struct {
+	Paused bool   `json:"paused"`
+	Since  int64  `json:"since,omitempty"`  // unix seconds the current pause started
+	Reason string `json:"reason,omitempty"` // operator-supplied, e.g. "provider maintenance"
+}
+
+// TxFilter is the set of stream filters a transaction must pass to be
+// forwarded, as previewed by /admin/filters/preview.
+type TxFilter struct {
+	MinPaymentDrops  int64    `json:"min_payment_drops"` // applies to Payments only
+	TransactionTypes []string `json:"transaction_types"` // e.g. "Payment", "OfferCreate"
+	AllowedResults   []string `json:"allowed_results"`   // exact codes or "prefix*"
+}
+
+// FilterProjection is the traffic one filter would have forwarded over the
+// replayed window.
+type FilterProjection struct {
+	Matched   int            `json:"matched"`
+	PerSecond float64        `json:"per_second"`
+	PerMinute float64        `json:"per_minute"`
+	ByType    map[string]int `json:"by_type,omitempty"`
+}
+
+// FilterPreview compares the live filter with a candidate over recently
+// buffered traffic.
+type FilterPreview struct {
+	WindowSeconds float64          `json:"window_seconds"` // span of traffic replayed
+	Sampled       int              `json:"sampled"`        // validated transactions in the span
+	Live          TxFilter         `json:"live"`
+	Candidate     TxFilter         `json:"candidate"` // with omitted fields taken from the live filter
+	Current       FilterProjection `json:"current"`
+	Projected     FilterProjection `json:"projected"`
+}
+
+// OriginPolicy restricts WebSocket clients connecting from one origin. Zero
+// values mean unlimited.
+type OriginPolicy struct {
+	MaxConnections       int      `json:"max_connections"`
+	Channels             []string `json:"channels"` // "transactions", "server_status", ...; empty allows all
+	MaxMessagesPerSecond float64  `json:"max_messages_per_second"`
+}
+
+// View is a named tenant namespace served under /t/{name}/, filtering the
+// shared validator and transaction data for one embed. Empty fields do not
+// filter.
+type View struct {
+	AllowedOrigins  []string `json:"allowed_origins"`   // replaces CORS_ALLOWED_ORIGINS for the view's routes
+	MinPaymentDrops int64    `json:"min_payment_drops"` // streams only payments of at least this amount
+	Countries       []string `json:"countries"`         // ISO country codes of validators and transaction endpoints
+	Channels        []string `json:"channels"`          // "transactions", "server_status", ...
+}
+
+// Explorer holds the page URL templates of one block explorer. Templates
+// substitute {hash}, {account}, {ledger} and {validator} respectively; an
+// empty template leaves that kind of page unlinked.
+type Explorer struct {
+	Name        string `json:"name"`
+	Transaction string `json:"transaction,omitempty"`
+	Account     string `json:"account,omitempty"`
+	Ledger      string `json:"ledger,omitempty"`
+	Validator   string `json:"validator,omitempty"`
+}
+
+// ExplorerLink holds one explorer's pages for a transaction or validator,
+// so every client renders the same deep links.
+type ExplorerLink struct {
+	Explorer    string `json:"explorer"`
+	Transaction string `json:"transaction,omitempty"`
+	Account     string `json:"account,omitempty"`
+	Destination string `json:"destination,omitempty"`
+	Ledger      string `json:"ledger,omitempty"`
+	Validator   string `json:"validator,omitempty"`
+}
+
+// GeoProvider configures a paid IP geolocation web service, consulted in
+// order when GeoLite cannot place an IP. Secrets can be read from files
+// instead, which are re-read when they change so keys rotate without a
+// restart.
+type GeoProvider struct {
+	Name              string  `json:"name"` // "ipinfo" or "maxmind"
+	Token             string  `json:"token,omitempty"`
+	TokenFile         string  `json:"token_file,omitempty"`
+	AccountID         string  `json:"account_id,omitempty"`
+	LicenseKey        string  `json:"license_key,omitempty"`
+	LicenseKeyFile    string  `json:"license_key_file,omitempty"`
+	URL               string  `json:"url,omitempty"`                 // overrides the service's base URL
+	RequestsPerSecond float64 `json:"requests_per_second,omitempty"` // 0 means 1
+	MonthlyQuota      int64   `json:"monthly_quota,omitempty"`       // requests per UTC calendar month, 0 unlimited
+}
+
+// OutboundBudget caps the requests sent to one external host, shared by
+// every subsystem that calls it.
+type OutboundBudget struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"` // requests allowed at once after idling; 0 means one second's worth
+}
@@ -0,0 +1,105 @@
+package models
+
+import "encoding/json"
+
+// Roles tagged on Transaction.Locations entries.
+const (
+	LocationRoleSource      = "source"
+	LocationRoleDestination = "destination"
+	LocationRoleExtra       = "extra"
+)
+
+// Wire shapes for transactions. Older consumers read role-tagged
+// source_info/dest_info/extra_info fields; newer ones read the ordered
+// locations list.
+const (
+	TransactionShapeLocations = "locations"
+	TransactionShapeRoles     = "roles"
+	TransactionShapeBoth      = "both"
+)
+
+// transactionFields has the fields of Transaction without its methods, so it
+// can be embedded by the adapters below without recursing into them.
+type transactionFields Transaction
+
+// roleTaggedTransaction adds the source_info/dest_info/extra_info shape to a
+// transaction. Its Locations field shadows the embedded one.
+type roleTaggedTransaction struct {
+	*transactionFields
+	Locations  []*GeoLocation `json:"locations,omitempty"`
+	SourceInfo *GeoLocation   `json:"source_info,omitempty"`
+	DestInfo   *GeoLocation   `json:"dest_info,omitempty"`
+	ExtraInfo  []*GeoLocation `json:"extra_info,omitempty"`
+}
+
+// RoleInfo splits the transaction's locations by role. Untagged locations are
+// assigned by position: source, destination, then extra.
+func (t *Transaction) RoleInfo() (source *GeoLocation, destination *GeoLocation, extra []*GeoLocation) {
+	for i, location := range t.Locations {
+		if location == nil {
+			continue
+		}
+		role := location.Role
+		if role == "" {
+			switch i {
+			case 0:
+				role = LocationRoleSource
+			case 1:
+				role = LocationRoleDestination
+			default:
+				role = LocationRoleExtra
+			}
+		}
+		switch {
+		case role == LocationRoleSource && source == nil:
+			source = location
+		case role == LocationRoleDestination && destination == nil:
+			destination = location
+		default:
+			extra = append(extra, location)
+		}
+	}
+	return source, destination, extra
+}
+
+// MarshalTransaction encodes t in the given shape. Unknown shapes fall back
+// to TransactionShapeLocations, which is also what json.Marshal produces.
+func MarshalTransaction(t *Transaction, shape string) ([]byte, error) {
+	if t == nil || (shape != TransactionShapeRoles && shape != TransactionShapeBoth) {
+		return json.Marshal(t)
+	}
+	wire := roleTaggedTransaction{transactionFields: (*transactionFields)(t)}
+	wire.SourceInfo, wire.DestInfo, wire.ExtraInfo = t.RoleInfo()
+	if shape == TransactionShapeBoth {
+		wire.Locations = t.Locations
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON accepts either shape. When only source_info/dest_info/
+// extra_info are present, Locations is rebuilt from them in that order.
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	wire := roleTaggedTransaction{transactionFields: (*transactionFields)(t)}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	t.Locations = wire.Locations
+	if len(t.Locations) > 0 {
+		return nil
+	}
+	appendRole := func(location *GeoLocation, role string) {
+		if location == nil {
+			return
+		}
+		if location.Role == "" {
+			location.Role = role
+		}
+		t.Locations = append(t.Locations, location)
+	}
+	appendRole(wire.SourceInfo, LocationRoleSource)
+	appendRole(wire.DestInfo, LocationRoleDestination)
+	for _, location := range wire.ExtraInfo {
+		appendRole(location, LocationRoleExtra)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testRoleTransaction() *Transaction {
+	return &Transaction{
+		Hash:            "ABC123",
+		TransactionType: "Payment",
+		Amount:          "1000000",
+		Locations: []*GeoLocation{
+			{Latitude: 1, Longitude: 2, City: "Source", Role: LocationRoleSource},
+			{Latitude: 3, Longitude: 4, City: "Destination", Role: LocationRoleDestination},
+			{Latitude: 5, Longitude: 6, City: "Issuer", Role: LocationRoleExtra},
+		},
+	}
+}
+
+func TestMarshalTransactionShapes(t *testing.T) {
+	tx := testRoleTransaction()
+
+	tests := []struct {
+		shape         string
+		wantLocations bool
+		wantRoles     bool
+	}{
+		{shape: TransactionShapeLocations, wantLocations: true, wantRoles: false},
+		{shape: "", wantLocations: true, wantRoles: false},
+		{shape: TransactionShapeRoles, wantLocations: false, wantRoles: true},
+		{shape: TransactionShapeBoth, wantLocations: true, wantRoles: true},
+	}
+	for _, tt := range tests {
+		data, err := MarshalTransaction(tx, tt.shape)
+		if err != nil {
+			t.Fatalf("shape %q: marshal failed: %v", tt.shape, err)
+		}
+		body := string(data)
+		if got := strings.Contains(body, `"locations"`); got != tt.wantLocations {
+			t.Errorf("shape %q: locations present = %t, want %t: %s", tt.shape, got, tt.wantLocations, body)
+		}
+		if got := strings.Contains(body, `"source_info"`) && strings.Contains(body, `"dest_info"`) && strings.Contains(body, `"extra_info"`); got != tt.wantRoles {
+			t.Errorf("shape %q: role fields present = %t, want %t: %s", tt.shape, got, tt.wantRoles, body)
+		}
+		if !strings.Contains(body, `"hash":"ABC123"`) {
+			t.Errorf("shape %q: expected transaction fields, got %s", tt.shape, body)
+		}
+	}
+}
+
+func TestTransactionUnmarshalRoleShape(t *testing.T) {
+	data, err := MarshalTransaction(testRoleTransaction(), TransactionShapeRoles)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var tx Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if tx.Hash != "ABC123" {
+		t.Errorf("Expected Hash ABC123, got %s", tx.Hash)
+	}
+	if len(tx.Locations) != 3 {
+		t.Fatalf("Expected 3 locations rebuilt from role fields, got %d", len(tx.Locations))
+	}
+	if tx.Locations[0].City != "Source" || tx.Locations[1].Role != LocationRoleDestination || tx.Locations[2].City != "Issuer" {
+		t.Errorf("Unexpected rebuilt locations: %+v %+v %+v", tx.Locations[0], tx.Locations[1], tx.Locations[2])
+	}
+}
+
+func TestRoleInfoAssignsUntaggedLocationsByPosition(t *testing.T) {
+	tx := &Transaction{Locations: []*GeoLocation{{City: "A"}, {City: "B"}, {City: "C"}}}
+
+	source, destination, extra := tx.RoleInfo()
+	if source == nil || source.City != "A" || destination == nil || destination.City != "B" || len(extra) != 1 || extra[0].City != "C" {
+		t.Fatalf("unexpected role split: %+v %+v %+v", source, destination, extra)
+	}
+}
@@ -0,0 +1,200 @@
+// Package visualizer embeds the validator service's ingestion and
+// enrichment engine in other Go programs, without its HTTP server:
+//
+//	cfg := visualizer.DefaultConfig()
+//	svc, err := visualizer.New(cfg)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	txs := make(chan *models.Transaction, 256)
+//	defer svc.SubscribeTransactions(txs)()
+//	if err := svc.Start(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+//	defer svc.Stop(context.Background())
+package visualizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/config"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/engine"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/transaction"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Config is the service configuration, with the fields documented in the
+// service README. Settings that only concern the HTTP server are ignored.
+type Config = config.Config
+
+// DefaultConfig returns the configuration the service binary would use:
+// its defaults, overridden by environment variables.
+func DefaultConfig() *Config {
+	return config.NewConfig()
+}
+
+// Processor observes or mutates transactions after enrichment and before
+// they are streamed, e.g. to add proprietary Tags. Processors run in
+// registration order on the engine's dispatch goroutine, with a one second
+// timeout per call, so they must be fast. An error is logged and the
+// transaction is still streamed.
+type Processor = transaction.Processor
+
+// Options controls optional embedding behavior.
+type Options struct {
+	// Logger receives the engine's logs. Nil logs JSON to stderr at the
+	// configured LOG_LEVEL.
+	Logger *logrus.Logger
+
+	// Processors run on every transaction after the ones the config
+	// enables (watchlist, labels, rules and TX_PROCESSOR_COMMAND). They
+	// require ingesting from XRPL: a replica upstream streams transactions
+	// it already processed.
+	Processors []Processor
+}
+
+// Service is an embedded ingestion and enrichment engine. It runs once:
+// after Stop it cannot be started again.
+type Service struct {
+	cfg        *Config
+	logger     *logrus.Logger
+	processors []Processor
+
+	mu       sync.Mutex
+	pipeline *engine.Engine
+	cancel   context.CancelFunc
+	stopped  bool
+
+	transactions subscribers[*models.Transaction]
+	geoUpdates   subscribers[*models.TxGeoUpdate]
+}
+
+// New validates cfg and returns a service ready to Start.
+func New(cfg *Config, opts ...Options) (*Service, error) {
+	if cfg == nil {
+		return nil, errors.New("visualizer: nil config")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("visualizer: invalid config: %w", err)
+	}
+	var options Options
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	if len(options.Processors) > 0 && cfg.ReplicaUpstreamURL != "" {
+		return nil, errors.New("visualizer: processors are not supported with a replica upstream")
+	}
+	logger := options.Logger
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetFormatter(&logrus.JSONFormatter{})
+		if level, err := logrus.ParseLevel(cfg.LogLevel); err == nil {
+			logger.SetLevel(level)
+		}
+	}
+	return &Service{cfg: cfg, logger: logger, processors: options.Processors}, nil
+}
+
+// Start starts ingesting validators and transactions. It returns once the
+// pipeline is running; the data arrives in the background until ctx is
+// canceled or Stop is called.
+func (s *Service) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pipeline != nil {
+		return errors.New("visualizer: already started")
+	}
+	if s.stopped {
+		return errors.New("visualizer: stopped services cannot be restarted")
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	pipeline, err := engine.Start(runCtx, s.cfg, s.logger, s.processors...)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("visualizer: %w", err)
+	}
+	pipeline.Transactions.AddCallback(s.transactions.publish)
+	pipeline.Transactions.AddGeoUpdateCallback(s.geoUpdates.publish)
+	s.pipeline = pipeline
+	s.cancel = cancel
+	return nil
+}
+
+// Stop stops ingestion and closes upstream connections, giving them until
+// ctx ends, whose error it returns. Stopping a service that is not running
+// does nothing.
+func (s *Service) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	if s.pipeline == nil {
+		return nil
+	}
+	s.cancel()
+	s.pipeline.Stop(ctx)
+	s.pipeline = nil
+	return ctx.Err()
+}
+
+// Validators returns the validators currently known, or nil before Start.
+// The validators are shared with the engine and must not be modified.
+func (s *Service) Validators() []*models.Validator {
+	s.mu.Lock()
+	pipeline := s.pipeline
+	s.mu.Unlock()
+	if pipeline == nil {
+		return nil
+	}
+	return pipeline.Validators.GetValidators()
+}
+
+// SubscribeTransactions delivers every transaction the engine streams to
+// ch, with the locations known at that point, until the returned function
+// is called. Locations resolved later arrive through SubscribeGeoUpdates.
+// Sends never block the engine: a transaction that does not fit in ch is
+// dropped, so give ch room for bursts. Subscribing before Start misses
+// nothing.
+func (s *Service) SubscribeTransactions(ch chan<- *models.Transaction) (unsubscribe func()) {
+	return s.transactions.add(ch)
+}
+
+// SubscribeGeoUpdates delivers locations resolved for transactions after
+// they were streamed, like SubscribeTransactions.
+func (s *Service) SubscribeGeoUpdates(ch chan<- *models.TxGeoUpdate) (unsubscribe func()) {
+	return s.geoUpdates.add(ch)
+}
+
+// subscribers fans values out to channels without blocking.
+type subscribers[T any] struct {
+	mu    sync.RWMutex
+	chans map[chan<- T]struct{}
+}
+
+func (s *subscribers[T]) add(ch chan<- T) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.chans == nil {
+		s.chans = make(map[chan<- T]struct{})
+	}
+	s.chans[ch] = struct{}{}
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.chans, ch)
+	}
+}
+
+func (s *subscribers[T]) publish(value T) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch := range s.chans {
+		select {
+		case ch <- value:
+		default:
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package visualizer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/xrpltest"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/models"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+func TestServiceEmbedsReplicaPipeline(t *testing.T) {
+	upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/validators":
+			validators := []*models.Validator{{Address: "nA", Domain: "a.example"}}
+			json.NewEncoder(w).Encode(map[string]interface{}{"validators": validators, "count": len(validators)})
+		case "/health":
+			json.NewEncoder(w).Encode(map[string]interface{}{"min_payment_drops": 1000000})
+		case "/transactions":
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"hash":"ABC","amount":"5000000"}`))
+			conn.WriteJSON(&models.StreamEvent{Type: "tx_geo_update", Data: &models.TxGeoUpdate{Hash: "ABC"}})
+			conn.ReadMessage()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	cfg := DefaultConfig()
+	cfg.ReplicaUpstreamURL = upstream.URL
+	cfg.ReplicaOrigin = "https://embed.example"
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	svc, err := New(cfg, Options{Logger: logger})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	txs := make(chan *models.Transaction, 4)
+	geoUpdates := make(chan *models.TxGeoUpdate, 4)
+	svc.SubscribeTransactions(txs)
+	svc.SubscribeGeoUpdates(geoUpdates)
+	if err := svc.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := svc.Start(context.Background()); err == nil {
+		t.Fatal("expected a second Start to fail")
+	}
+
+	select {
+	case tx := <-txs:
+		if tx.Hash != "ABC" {
+			t.Fatalf("unexpected transaction %+v", tx)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a transaction")
+	}
+	select {
+	case update := <-geoUpdates:
+		if update.Hash != "ABC" {
+			t.Fatalf("unexpected geo update %+v", update)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a geo update")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(svc.Validators()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if validators := svc.Validators(); len(validators) != 1 || validators[0].Address != "nA" {
+		t.Fatalf("unexpected validators %+v", validators)
+	}
+
+	if err := svc.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if svc.Validators() != nil {
+		t.Fatal("expected no validators after Stop")
+	}
+	if err := svc.Start(context.Background()); err == nil {
+		t.Fatal("expected a stopped service not to restart")
+	}
+}
+
+type tagProcessor struct{}
+
+func (tagProcessor) Name() string { return "tag" }
+
+func (tagProcessor) Process(ctx context.Context, tx *models.Transaction) error {
+	tx.Tags = map[string]string{"desk": "treasury"}
+	return nil
+}
+
+func TestServiceRunsRegisteredProcessors(t *testing.T) {
+	node := xrpltest.NewServer()
+	defer node.Close()
+
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.PublicXRPLJSONRPCURL = node.URL()
+	cfg.PublicXRPLWebSocketURL = node.WSURL()
+	cfg.TransactionJSONRPCURL = node.URL()
+	cfg.TransactionWebSocketURL = node.WSURL()
+	cfg.ValidatorListSites = []string{node.URL()}
+	cfg.SecondaryValidatorRegistryURL = node.URL()
+	cfg.NetworkHealthJSONRPCURLs = []string{node.URL()}
+	cfg.ValidatorMetadataCachePath = filepath.Join(dir, "validator-metadata-cache.json")
+	cfg.GeoCachePath = filepath.Join(dir, "geo-cache.json")
+	cfg.GeoLiteEnabled = false
+	cfg.MinPaymentDrops = 1
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	svc, err := New(cfg, Options{Logger: logger, Processors: []Processor{tagProcessor{}}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	txs := make(chan *models.Transaction, 4)
+	svc.SubscribeTransactions(txs)
+	if err := svc.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer svc.Stop(context.Background())
+	if !node.WaitForSubscribers(1, 2*time.Second) {
+		t.Fatal("expected the service to subscribe to transactions")
+	}
+	node.Emit("transactions", xrpltest.PaymentMessage("ABC", "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "rLHzPsX6oXkzU9cRHEwKmMSWJfpJ9nE4VY", 25_000_000, 90000001))
+	select {
+	case tx := <-txs:
+		if tx.Hash != "ABC" || tx.Tags["desk"] != "treasury" {
+			t.Fatalf("expected the processor's tag, got %+v", tx)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a transaction")
+	}
+
+	cfg = DefaultConfig()
+	cfg.ReplicaUpstreamURL = "http://replica.example"
+	cfg.ReplicaOrigin = "https://embed.example"
+	if _, err := New(cfg, Options{Processors: []Processor{tagProcessor{}}}); err == nil {
+		t.Fatal("expected processors to be refused with a replica upstream")
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ListenPort = 0
+	if _, err := New(cfg); err == nil {
+		t.Fatal("expected an invalid config to fail")
+	}
+	if _, err := New(nil); err == nil {
+		t.Fatal("expected a nil config to fail")
+	}
+}
+
+func TestSubscribersDropWhenFull(t *testing.T) {
+	var subs subscribers[int]
+	full := make(chan int)
+	buffered := make(chan int, 1)
+	subs.add(full)
+	unsubscribe := subs.add(buffered)
+	subs.publish(1)
+	if got := <-buffered; got != 1 {
+		t.Fatalf("expected 1, got %d", got)
+	}
+	unsubscribe()
+	subs.publish(2)
+	if len(buffered) != 0 {
+		t.Fatal("expected no delivery after unsubscribing")
+	}
+}
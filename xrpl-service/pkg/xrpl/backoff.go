@@ -0,0 +1,126 @@
+package xrpl
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Default reconnect policy.
+const (
+	DefaultBackoffInitial     = time.Second
+	DefaultBackoffMax         = time.Minute
+	DefaultBackoffJitter      = 0.2
+	DefaultCircuitThreshold   = 10
+	DefaultCircuitOpenTimeout = 5 * time.Minute
+)
+
+// Circuit states reported by Backoff.State.
+const (
+	CircuitClosed = "closed"
+	CircuitOpen   = "open"
+)
+
+// BackoffPolicy paces reconnect attempts to a stream upstream: waits grow
+// exponentially from Initial to Max, and after CircuitThreshold consecutive
+// failures the circuit opens and attempts pause for CircuitOpenTimeout. One
+// attempt is made when it elapses; a failure opens the circuit again.
+// Zero fields take the defaults above.
+type BackoffPolicy struct {
+	Initial time.Duration
+	Max     time.Duration
+
+	// Jitter varies each wait uniformly by up to ±Jitter of it, e.g. 0.2
+	// for ±20%, so that clients dropped together do not reconnect in
+	// lockstep. Negative disables jitter.
+	Jitter float64
+
+	// CircuitThreshold is how many consecutive failures open the circuit.
+	// Negative never opens it.
+	CircuitThreshold   int
+	CircuitOpenTimeout time.Duration
+}
+
+func (p BackoffPolicy) withDefaults() BackoffPolicy {
+	if p.Initial <= 0 {
+		p.Initial = DefaultBackoffInitial
+	}
+	if p.Max <= 0 {
+		p.Max = DefaultBackoffMax
+	}
+	if p.Max < p.Initial {
+		p.Max = p.Initial
+	}
+	if p.Jitter == 0 {
+		p.Jitter = DefaultBackoffJitter
+	}
+	if p.CircuitThreshold == 0 {
+		p.CircuitThreshold = DefaultCircuitThreshold
+	}
+	if p.CircuitOpenTimeout <= 0 {
+		p.CircuitOpenTimeout = DefaultCircuitOpenTimeout
+	}
+	return p
+}
+
+// Backoff tracks the consecutive reconnect failures of one stream under a
+// policy. It is not safe for concurrent use; each stream's reconnect loop
+// owns its own.
+type Backoff struct {
+	policy   BackoffPolicy
+	failures int
+
+	// random returns a value in [0, 1); nil uses math/rand.
+	random func() float64
+}
+
+// NewBackoff creates a Backoff with no failures recorded.
+func NewBackoff(policy BackoffPolicy) *Backoff {
+	return &Backoff{policy: policy.withDefaults()}
+}
+
+// Failure records a failed attempt and returns the wait before the next
+// one.
+func (b *Backoff) Failure() time.Duration {
+	b.failures++
+	if b.State() == CircuitOpen {
+		return b.policy.CircuitOpenTimeout
+	}
+
+	wait := b.policy.Initial
+	for i := 1; i < b.failures && wait < b.policy.Max; i++ {
+		wait *= 2
+	}
+	wait = min(wait, b.policy.Max)
+	if b.policy.Jitter <= 0 {
+		return wait
+	}
+	random := b.random
+	if random == nil {
+		random = rand.Float64
+	}
+	jittered := time.Duration(float64(wait) * (1 + min(b.policy.Jitter, 1)*(2*random()-1)))
+	if jittered <= 0 {
+		return wait
+	}
+	return jittered
+}
+
+// Reset clears the failures after a successful attempt, closing the
+// circuit.
+func (b *Backoff) Reset() {
+	b.failures = 0
+}
+
+// Failures returns the consecutive failures since the last success.
+func (b *Backoff) Failures() int {
+	return b.failures
+}
+
+// State returns CircuitOpen once the failures reach the threshold, else
+// CircuitClosed.
+func (b *Backoff) State() string {
+	if b.policy.CircuitThreshold > 0 && b.failures >= b.policy.CircuitThreshold {
+		return CircuitOpen
+	}
+	return CircuitClosed
+}
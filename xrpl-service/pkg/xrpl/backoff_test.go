@@ -0,0 +1,55 @@
+package xrpl
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDoublesUpToMax(t *testing.T) {
+	backoff := NewBackoff(BackoffPolicy{Initial: time.Second, Max: 5 * time.Second, Jitter: -1, CircuitThreshold: -1})
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, expected := range want {
+		if wait := backoff.Failure(); wait != expected {
+			t.Fatalf("failure %d: expected %v, got %v", i+1, expected, wait)
+		}
+	}
+
+	backoff.Reset()
+	if wait := backoff.Failure(); wait != time.Second {
+		t.Fatalf("expected Reset to restart from the initial wait, got %v", wait)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	backoff := NewBackoff(BackoffPolicy{Initial: 10 * time.Second, Jitter: 0.2})
+	backoff.random = func() float64 { return 0 }
+	if wait := backoff.Failure(); wait != 8*time.Second {
+		t.Fatalf("expected the lowest jitter to wait 8s, got %v", wait)
+	}
+	backoff.Reset()
+	backoff.random = func() float64 { return 0.5 }
+	if wait := backoff.Failure(); wait != 10*time.Second {
+		t.Fatalf("expected the middle jitter to wait 10s, got %v", wait)
+	}
+}
+
+func TestBackoffOpensCircuit(t *testing.T) {
+	backoff := NewBackoff(BackoffPolicy{Initial: time.Second, Jitter: -1, CircuitThreshold: 3, CircuitOpenTimeout: time.Hour})
+	backoff.Failure()
+	backoff.Failure()
+	if backoff.State() != CircuitClosed {
+		t.Fatalf("expected the circuit closed below the threshold, got %s", backoff.State())
+	}
+	if wait := backoff.Failure(); wait != time.Hour || backoff.State() != CircuitOpen {
+		t.Fatalf("expected the circuit to open with a 1h wait, got %v in state %s", wait, backoff.State())
+	}
+	// A failed probe keeps the circuit open.
+	if wait := backoff.Failure(); wait != time.Hour {
+		t.Fatalf("expected the circuit to stay open, got %v", wait)
+	}
+
+	backoff.Reset()
+	if backoff.State() != CircuitClosed || backoff.Failures() != 0 {
+		t.Fatalf("expected Reset to close the circuit, got %s after %d failures", backoff.State(), backoff.Failures())
+	}
+}
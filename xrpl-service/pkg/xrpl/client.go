@@ -0,0 +1,578 @@
+// Package xrpl is a client for XRPL nodes: JSON-RPC commands over HTTP and
+// stream subscriptions over WebSocket, with classified upstream errors.
+package xrpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/pkg/budget"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// NodeClient defines the interface for XRPL interactions
+type NodeClient interface {
+	// Connect establishes connection to XRPL
+	Connect(ctx context.Context) error
+
+	// Close closes the connection
+	Close() error
+
+	// IsConnected returns connection status
+	IsConnected() bool
+
+	// Command sends a JSON-RPC command and gets response
+	Command(ctx context.Context, method string, params interface{}) (interface{}, error)
+
+	// Subscribe subscribes to streams (used for transactions, ledger_closed, etc)
+	Subscribe(ctx context.Context, streams []string, callback func(interface{})) error
+
+	// Unsubscribe unsubscribes from streams
+	Unsubscribe(ctx context.Context, streams []string) error
+
+	// GetValidators fetches validator info
+	GetValidators(ctx context.Context) (interface{}, error)
+
+	// GetServerInfo fetches server status
+	GetServerInfo(ctx context.Context) (interface{}, error)
+}
+
+const (
+	// dnsCycleQuietPeriod is how long the stream must be idle before a
+	// connection pinned to a stale IP is cycled.
+	dnsCycleQuietPeriod = time.Second
+	// dnsCycleMaxDelay bounds how long a cycle waits for a quiet moment.
+	dnsCycleMaxDelay = 30 * time.Second
+
+	defaultMessageBufferSize = 4096
+	defaultDecodeWorkers     = 2
+	defaultMaxMessageBytes   = 1 << 20
+	defaultMaxMessageDepth   = 64
+)
+
+// Client implements NodeClient
+type Client struct {
+	jsonRPCURL   string
+	websocketURL string
+	wsConn       *websocket.Conn
+	httpClient   *http.Client
+	logger       *logrus.Logger
+	callbacks    []func(interface{})
+	streams      []string
+	mu           sync.RWMutex
+	connected    bool
+
+	// Health-aware DNS: the WebSocket host is re-resolved periodically and
+	// the connection is cycled when its IP drops out of the answer.
+	dnsRefreshInterval time.Duration
+	quietPeriod        time.Duration
+	maxCycleDelay      time.Duration
+	lookupHost         func(ctx context.Context, host string) ([]string, error)
+	remoteIP           string
+	cycledFor          []string
+	lastMessageAt      time.Time
+	dnsWatchOnce       sync.Once
+	stopChan           chan struct{}
+	stopOnce           sync.Once
+
+	// Stream messages pass from the read loop through a bounded buffer to
+	// decode workers, and reach the callbacks in arrival order.
+	host          string
+	frames        chan *frame // arrival order, consumed by dispatchLoop
+	decodeQueue   chan *frame // one longer than frames, so enqueue never blocks on it
+	decodeWorkers int
+	pipelineOnce  sync.Once
+	dropped       atomic.Int64
+
+	// Payload guards against oversized or deeply nested stream messages.
+	maxMessageBytes int64
+	maxMessageDepth int
+}
+
+// ClientOptions controls optional client behaviour.
+type ClientOptions struct {
+	// DNSRefreshInterval is how often the WebSocket host is re-resolved.
+	// Zero disables re-resolution.
+	DNSRefreshInterval time.Duration
+
+	// Budget, when set, paces the client's JSON-RPC requests against the
+	// limits of the RPC host, on behalf of Subsystem.
+	Budget    *budget.Manager
+	Subsystem string
+
+	// MessageBufferSize is how many stream messages may wait for decoding
+	// and dispatch; messages arriving while it is full are dropped so that
+	// a slow callback cannot stall the socket. Defaults to 4096.
+	MessageBufferSize int
+
+	// DecodeWorkers is how many stream messages are decoded in parallel.
+	// Callbacks still run one message at a time, in arrival order.
+	// Defaults to 2.
+	DecodeWorkers int
+
+	// MaxMessageBytes bounds a stream message. A larger message closes the
+	// connection, since the rest of it cannot be skipped, and the client
+	// reconnects as after any read error. Defaults to 1 MiB.
+	MaxMessageBytes int64
+
+	// MaxMessageDepth bounds how deeply objects and arrays may nest in a
+	// stream message; deeper messages are dropped before decoding.
+	// Defaults to 64.
+	MaxMessageDepth int
+}
+
+// NewClient creates a new XRPL client
+func NewClient(jsonRPCURL, websocketURL string, logger *logrus.Logger, opts ...ClientOptions) *Client {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	var options ClientOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	bufferSize := options.MessageBufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultMessageBufferSize
+	}
+	decodeWorkers := options.DecodeWorkers
+	if decodeWorkers <= 0 {
+		decodeWorkers = defaultDecodeWorkers
+	}
+	maxMessageBytes := options.MaxMessageBytes
+	if maxMessageBytes <= 0 {
+		maxMessageBytes = defaultMaxMessageBytes
+	}
+	maxMessageDepth := options.MaxMessageDepth
+	if maxMessageDepth <= 0 {
+		maxMessageDepth = defaultMaxMessageDepth
+	}
+	host := websocketURL
+	if parsed, err := url.Parse(websocketURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	return &Client{
+		jsonRPCURL:         jsonRPCURL,
+		websocketURL:       websocketURL,
+		httpClient:         &http.Client{Timeout: 15 * time.Second, Transport: budget.Transport(options.Budget, options.Subsystem, nil)},
+		logger:             logger,
+		callbacks:          make([]func(interface{}), 0),
+		dnsRefreshInterval: options.DNSRefreshInterval,
+		quietPeriod:        dnsCycleQuietPeriod,
+		maxCycleDelay:      dnsCycleMaxDelay,
+		lookupHost:         net.DefaultResolver.LookupHost,
+		stopChan:           make(chan struct{}),
+		host:               host,
+		frames:             make(chan *frame, bufferSize),
+		decodeQueue:        make(chan *frame, bufferSize+1),
+		decodeWorkers:      decodeWorkers,
+		maxMessageBytes:    maxMessageBytes,
+		maxMessageDepth:    maxMessageDepth,
+	}
+}
+
+// Connect establishes WebSocket connection to XRPL
+func (c *Client) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to connect to XRPL WebSocket")
+		return err
+	}
+
+	c.wsConn = conn
+	c.remoteIP = remoteIP(conn)
+	c.connected = true
+	c.logger.Info("Connected to XRPL WebSocket")
+
+	// Start read loop for handling incoming messages
+	c.pipelineOnce.Do(c.startPipeline)
+	go c.readLoop(conn)
+
+	if c.dnsRefreshInterval > 0 {
+		c.dnsWatchOnce.Do(func() {
+			go c.watchDNS()
+		})
+	}
+
+	return nil
+}
+
+func (c *Client) dial(ctx context.Context) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+	}
+	conn, _, err := dialer.DialContext(ctx, c.websocketURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadLimit(c.maxMessageBytes)
+	return conn, nil
+}
+
+// remoteIP returns the peer IP of a WebSocket connection.
+func remoteIP(conn *websocket.Conn) string {
+	host, _, err := net.SplitHostPort(conn.UnderlyingConn().RemoteAddr().String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	c.stopOnce.Do(func() {
+		close(c.stopChan)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.wsConn != nil {
+		c.connected = false
+		return c.wsConn.Close()
+	}
+	return nil
+}
+
+// IsConnected returns connection status
+func (c *Client) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connected
+}
+
+// Command sends a JSON-RPC command via HTTP
+func (c *Client) Command(ctx context.Context, method string, params interface{}) (interface{}, error) {
+	payload := map[string]interface{}{
+		"method":  method,
+		"params":  []interface{}{params},
+		"id":      1,
+		"jsonrpc": "2.0",
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.jsonRPCURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	result, err := c.doCommand(req, method)
+	if err != nil {
+		metrics.UpstreamErrorsTotal.WithLabelValues("xrpl_rpc", Classify(err)).Inc()
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) doCommand(req *http.Request, method string) (map[string]interface{}, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Callers decide how loud a failure is: routine ones such as
+		// health probes of an unreachable endpoint are not errors here.
+		c.logger.WithError(err).WithField("method", method).Debug("RPC command failed")
+		return nil, WrapTransportError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 120))
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(snippet))}
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %s response: %w", ErrDecode, method, err)
+	}
+
+	// Check for JSON-RPC error response, either top-level or rippled's
+	// {"result": {"status": "error", "error": "..."}} form.
+	if errorResult, ok := result["error"]; ok {
+		return nil, newRPCError(method, errorResult, result)
+	}
+	if inner, ok := result["result"].(map[string]interface{}); ok {
+		if status, _ := inner["status"].(string); status == "error" {
+			return nil, newRPCError(method, inner["error"], inner)
+		}
+	}
+
+	return result, nil
+}
+
+// Subscribe subscribes to XRPL streams
+func (c *Client) Subscribe(ctx context.Context, streams []string, callback func(interface{})) error {
+	c.mu.RLock()
+	if !c.connected || c.wsConn == nil {
+		c.mu.RUnlock()
+		return fmt.Errorf("not connected to XRPL")
+	}
+	c.mu.RUnlock()
+
+	// Send subscribe command
+	cmd := map[string]interface{}{
+		"command": "subscribe",
+		"streams": streams,
+	}
+
+	c.mu.Lock()
+	if callback != nil {
+		c.callbacks = append(c.callbacks, callback)
+	}
+	for _, stream := range streams {
+		if !containsString(c.streams, stream) {
+			c.streams = append(c.streams, stream)
+		}
+	}
+	if err := c.wsConn.WriteJSON(cmd); err != nil {
+		c.mu.Unlock()
+		c.logger.WithError(err).Error("Failed to send subscribe command")
+		return err
+	}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Unsubscribe unsubscribes from streams
+func (c *Client) Unsubscribe(ctx context.Context, streams []string) error {
+	c.mu.RLock()
+	if !c.connected || c.wsConn == nil {
+		c.mu.RUnlock()
+		return fmt.Errorf("not connected to XRPL")
+	}
+	c.mu.RUnlock()
+
+	cmd := map[string]interface{}{
+		"command": "unsubscribe",
+		"streams": streams,
+	}
+
+	c.mu.Lock()
+	remaining := c.streams[:0]
+	for _, stream := range c.streams {
+		if !containsString(streams, stream) {
+			remaining = append(remaining, stream)
+		}
+	}
+	c.streams = remaining
+	if err := c.wsConn.WriteJSON(cmd); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// GetValidators fetches validator information
+func (c *Client) GetValidators(ctx context.Context) (interface{}, error) {
+	return c.Command(ctx, "manifest", map[string]interface{}{})
+}
+
+// GetServerInfo fetches server information
+func (c *Client) GetServerInfo(ctx context.Context) (interface{}, error) {
+	return c.Command(ctx, "server_info", map[string]interface{}{})
+}
+
+// readLoop reads incoming messages from conn until it fails and queues
+// them for decoding. A failure only marks the client disconnected while
+// conn is still the current connection, so a connection retired by
+// cycleConnection exits quietly.
+func (c *Client) readLoop(conn *websocket.Conn) {
+	for {
+		c.mu.RLock()
+		if !c.connected {
+			c.mu.RUnlock()
+			break
+		}
+		c.mu.RUnlock()
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if errors.Is(err, websocket.ErrReadLimit) {
+				c.dropped.Add(1)
+				metrics.UpstreamMessagesRejectedTotal.WithLabelValues(c.host, "too_large").Inc()
+				c.logger.WithField("max_bytes", c.maxMessageBytes).Warn("XRPL stream message exceeds the size limit; closing the connection")
+			}
+			c.mu.Lock()
+			if c.wsConn == conn {
+				c.logger.WithError(err).Warn("WebSocket read error")
+				c.connected = false
+			}
+			c.mu.Unlock()
+			break
+		}
+
+		c.mu.Lock()
+		c.lastMessageAt = time.Now()
+		c.mu.Unlock()
+		c.enqueue(data)
+	}
+}
+
+// watchDNS re-resolves the WebSocket host every dnsRefreshInterval until the
+// client is closed. Hosts given as IP literals are not watched.
+func (c *Client) watchDNS() {
+	parsed, err := url.Parse(c.websocketURL)
+	if err != nil || parsed.Hostname() == "" || net.ParseIP(parsed.Hostname()) != nil {
+		return
+	}
+	host := parsed.Hostname()
+
+	ticker := time.NewTicker(c.dnsRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stopChan:
+			return
+		case <-ticker.C:
+			if c.checkDNS(host) {
+				c.cycleWhenQuiet(host)
+			}
+		}
+	}
+}
+
+// checkDNS resolves host and reports whether the connected IP is missing
+// from the answer. Each distinct answer triggers at most one cycle, so a
+// dialer that keeps picking an address the resolver does not return cannot
+// cause a reconnect loop.
+func (c *Client) checkDNS(host string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	addrs, err := c.lookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		c.logger.WithError(err).WithField("host", host).Debug("Failed to re-resolve XRPL WebSocket host")
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected || c.remoteIP == "" || containsString(addrs, c.remoteIP) || sameAddrs(c.cycledFor, addrs) {
+		return false
+	}
+	c.cycledFor = addrs
+	c.logger.WithFields(logrus.Fields{
+		"host":        host,
+		"remote_ip":   c.remoteIP,
+		"resolved_ip": addrs,
+	}).Info("XRPL WebSocket host moved, cycling connection")
+	return true
+}
+
+// cycleWhenQuiet waits until no message has arrived for quietPeriod, or at
+// most maxCycleDelay, and then cycles the connection.
+func (c *Client) cycleWhenQuiet(host string) {
+	deadline := time.Now().Add(c.maxCycleDelay)
+	ticker := time.NewTicker(c.quietPeriod / 4)
+	defer ticker.Stop()
+	for {
+		c.mu.RLock()
+		idle := time.Since(c.lastMessageAt)
+		c.mu.RUnlock()
+		if idle >= c.quietPeriod || !time.Now().Before(deadline) {
+			break
+		}
+		select {
+		case <-c.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := c.cycleConnection(ctx); err != nil {
+		metrics.UpstreamDNSChangesTotal.WithLabelValues(host, "error").Inc()
+		c.logger.WithError(err).WithField("host", host).Warn("Failed to cycle XRPL WebSocket connection")
+		return
+	}
+	metrics.UpstreamDNSChangesTotal.WithLabelValues(host, "cycled").Inc()
+}
+
+// cycleConnection dials a replacement connection, swaps it in, retires the
+// old one and resubscribes to the current streams. Idle pooled JSON-RPC
+// connections are dropped too so the next command dials a fresh address.
+func (c *Client) cycleConnection(ctx context.Context) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if !c.connected {
+		c.mu.Unlock()
+		conn.Close()
+		return fmt.Errorf("client disconnected during cycle")
+	}
+	old := c.wsConn
+	c.wsConn = conn
+	c.remoteIP = remoteIP(conn)
+	streams := append([]string(nil), c.streams...)
+	var subscribeErr error
+	if len(streams) > 0 {
+		subscribeErr = conn.WriteJSON(map[string]interface{}{
+			"command": "subscribe",
+			"streams": streams,
+		})
+	}
+	if subscribeErr != nil {
+		// Leave the client disconnected so its owner reconnects and
+		// resubscribes as after any other drop.
+		c.connected = false
+	}
+	c.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+	c.httpClient.CloseIdleConnections()
+	if subscribeErr != nil {
+		conn.Close()
+		return fmt.Errorf("failed to resubscribe after cycle: %w", subscribeErr)
+	}
+	go c.readLoop(conn)
+	return nil
+}
+
+func sameAddrs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, addr := range a {
+		if !containsString(b, addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,347 @@
+package xrpl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/xrpltest"
+	"github.com/sirupsen/logrus"
+)
+
+func TestCommandReturnsErrorForNonOKStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "", nil)
+	_, err := client.GetServerInfo(context.Background())
+	if err == nil {
+		t.Fatal("expected error for HTTP 429 response")
+	}
+	if !errors.Is(err, ErrRateLimited) || IsRetryable(err) {
+		t.Fatalf("expected non-retryable rate limit error, got %v", err)
+	}
+}
+
+func TestCommandClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantClass string
+	}{
+		{name: "result level not found", status: http.StatusOK, body: `{"result":{"status":"error","error":"actNotFound","error_message":"Account not found."}}`, wantClass: "not_found"},
+		{name: "top level error", status: http.StatusOK, body: `{"error":"slowDown"}`, wantClass: "rate_limited"},
+		{name: "not synced", status: http.StatusOK, body: `{"result":{"status":"error","error":"noNetwork"}}`, wantClass: "upstream_unavailable"},
+		{name: "unknown rpc error", status: http.StatusOK, body: `{"result":{"status":"error","error":"invalidParams"}}`, wantClass: "other"},
+		{name: "bad gateway", status: http.StatusBadGateway, body: "upstream", wantClass: "upstream_unavailable"},
+		{name: "malformed body", status: http.StatusOK, body: `{"result":`, wantClass: "decode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := NewClient(srv.URL, "", nil).Command(context.Background(), "account_info", nil)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := Classify(err); got != tt.wantClass {
+				t.Fatalf("Classify(%v) = %q, want %q", err, got, tt.wantClass)
+			}
+		})
+	}
+}
+
+func TestCommandClassifiesUnreachableUpstream(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	_, err := NewClient(url, "", logger).Command(context.Background(), "server_info", nil)
+	if !errors.Is(err, ErrUpstreamUnavailable) || !IsRetryable(err) {
+		t.Fatalf("expected retryable upstream unavailable error, got %v", err)
+	}
+	// The caller reports the failure; the client does not log it as an error.
+	if logs.Len() != 0 {
+		t.Fatalf("expected no log at the default level, got %s", logs.String())
+	}
+}
+
+func TestMockClientEmitsToSubscribers(t *testing.T) {
+	mock := NewMockClient(nil)
+	received := 0
+	if err := mock.Subscribe(context.Background(), []string{"transactions"}, func(interface{}) { received++ }); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	mock.Emit(map[string]interface{}{"type": "transaction"})
+
+	if received != 1 {
+		t.Fatalf("expected 1 delivered message, got %d", received)
+	}
+	if streams := mock.Streams(); len(streams) != 1 || streams[0] != "transactions" {
+		t.Fatalf("expected transactions stream subscription, got %+v", streams)
+	}
+}
+
+func TestClientCyclesConnectionWhenDNSMoves(t *testing.T) {
+	fake := xrpltest.NewServer()
+	defer fake.Close()
+
+	var mu sync.Mutex
+	answer := []string{"127.0.0.1"}
+	client := NewClient(fake.URL(), strings.Replace(fake.WSURL(), "127.0.0.1", "localhost", 1), nil, ClientOptions{
+		DNSRefreshInterval: 10 * time.Millisecond,
+	})
+	client.quietPeriod = 20 * time.Millisecond
+	client.lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), answer...), nil
+	}
+	defer client.Close()
+
+	received := make(chan interface{}, 4)
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if err := client.Subscribe(context.Background(), []string{"transactions"}, func(msg interface{}) { received <- msg }); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if !fake.WaitForSubscribers(1, time.Second) {
+		t.Fatal("expected initial subscription")
+	}
+	overlap := map[string]interface{}{"type": "transaction", "validated": true, "transaction": map[string]interface{}{"hash": "A"}}
+	fake.Emit("transactions", overlap)
+	if !waitForHash(received, "A") {
+		t.Fatal("expected the first transaction on the original connection")
+	}
+	client.mu.RLock()
+	original := client.wsConn
+	client.mu.RUnlock()
+
+	mu.Lock()
+	answer = []string{"192.0.2.10"}
+	mu.Unlock()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		client.mu.RLock()
+		cycled := client.wsConn != original
+		client.mu.RUnlock()
+		if cycled {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected connection to be cycled after DNS change")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !fake.WaitForSubscribers(1, time.Second) || !client.IsConnected() {
+		t.Fatal("expected the replacement connection to be subscribed")
+	}
+	// Drain responses to the subscribe commands before emitting.
+	time.Sleep(50 * time.Millisecond)
+	for len(received) > 0 {
+		<-received
+	}
+	// The replacement connection repeats a transaction the original one
+	// already delivered; it reaches the callbacks once.
+	fake.Emit("transactions", overlap)
+	fake.Emit("transactions", map[string]interface{}{"type": "transaction", "validated": true, "transaction": map[string]interface{}{"hash": "B"}})
+	select {
+	case msg := <-received:
+		if hash := transactionHash(msg); hash != "B" {
+			t.Fatalf("expected the repeated transaction to be dropped, got %q", hash)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected stream messages on the replacement connection")
+	}
+}
+
+func transactionHash(msg interface{}) string {
+	msgMap, _ := msg.(map[string]interface{})
+	txn, _ := msgMap["transaction"].(map[string]interface{})
+	hash, _ := txn["hash"].(string)
+	return hash
+}
+
+// waitForHash reads received until the transaction hash arrives, skipping
+// command responses.
+func waitForHash(received <-chan interface{}, hash string) bool {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case msg := <-received:
+			if transactionHash(msg) == hash {
+				return true
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+func TestClientBuffersStreamBehindSlowCallbacks(t *testing.T) {
+	fake := xrpltest.NewServer()
+	defer fake.Close()
+
+	client := NewClient(fake.URL(), fake.WSURL(), nil, ClientOptions{MessageBufferSize: 2, DecodeWorkers: 4})
+	defer client.Close()
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	blocked := make(chan struct{})
+	release := make(chan struct{})
+	received := make(chan float64, 16)
+	if err := client.Subscribe(context.Background(), []string{"transactions"}, func(msg interface{}) {
+		msgMap, _ := msg.(map[string]interface{})
+		seq, ok := msgMap["seq"].(float64)
+		if !ok {
+			return
+		}
+		if seq == 0 {
+			close(blocked)
+			<-release
+		}
+		received <- seq
+	}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if !fake.WaitForSubscribers(1, time.Second) {
+		t.Fatal("expected subscription")
+	}
+
+	fake.Emit("transactions", map[string]interface{}{"type": "transaction", "seq": 0})
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		t.Fatal("expected the first message to reach the callback")
+	}
+
+	// The socket keeps being read while the callback is stuck: two messages
+	// wait in the buffer and the rest are dropped.
+	for seq := 1; seq <= 10; seq++ {
+		fake.Emit("transactions", map[string]interface{}{"type": "transaction", "seq": seq})
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for client.dropped.Load() < 8 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 8 dropped messages, got %d", client.dropped.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !client.IsConnected() {
+		t.Fatal("expected the connection to survive the slow callback")
+	}
+
+	close(release)
+	for want := 0.0; want <= 2; want++ {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Fatalf("expected message %v in arrival order, got %v", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected message %v", want)
+		}
+	}
+	select {
+	case got := <-received:
+		t.Fatalf("expected overflow to be dropped, got message %v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestExceedsDepth(t *testing.T) {
+	tests := []struct {
+		data string
+		want bool
+	}{
+		{data: `{"a":{"b":[1,2]}}`, want: false},
+		{data: `{"a":{"b":[[1]]}}`, want: true},
+		{data: `{"a":"[[[[{{{{"}`, want: false},
+		{data: `{"a":"\"[[[[","b":1}`, want: false},
+		{data: `[]`, want: false},
+	}
+	for _, tt := range tests {
+		if got := exceedsDepth([]byte(tt.data), 3); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.data, tt.want, got)
+		}
+	}
+}
+
+func TestClientRejectsStreamMessagesBeyondLimits(t *testing.T) {
+	fake := xrpltest.NewServer()
+	defer fake.Close()
+
+	client := NewClient(fake.URL(), fake.WSURL(), nil, ClientOptions{MaxMessageBytes: 4096, MaxMessageDepth: 8})
+	defer client.Close()
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	received := make(chan map[string]interface{}, 8)
+	if err := client.Subscribe(context.Background(), []string{"transactions"}, func(msg interface{}) {
+		if msgMap, ok := msg.(map[string]interface{}); ok && msgMap["type"] == "transaction" {
+			received <- msgMap
+		}
+	}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if !fake.WaitForSubscribers(1, time.Second) {
+		t.Fatal("expected subscription")
+	}
+
+	var nested interface{} = "leaf"
+	for i := 0; i < 10; i++ {
+		nested = map[string]interface{}{"n": nested}
+	}
+	fake.Emit("transactions", map[string]interface{}{"type": "transaction", "seq": 1, "meta": nested})
+	fake.Emit("transactions", map[string]interface{}{"type": "transaction", "seq": 2})
+	select {
+	case msg := <-received:
+		if msg["seq"] != 2.0 {
+			t.Fatalf("expected the nested message to be dropped, got %v", msg["seq"])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the message within the limits")
+	}
+	if !client.IsConnected() {
+		t.Fatal("expected a nested message to leave the connection open")
+	}
+
+	fake.Emit("transactions", map[string]interface{}{"type": "transaction", "seq": 3, "memo": strings.Repeat("x", 8192)})
+	deadline := time.Now().Add(2 * time.Second)
+	for client.IsConnected() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected an oversized message to close the connection")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case msg := <-received:
+		t.Fatalf("expected the oversized message to be rejected, got %v", msg["seq"])
+	case <-time.After(50 * time.Millisecond):
+	}
+	if got := client.dropped.Load(); got != 2 {
+		t.Fatalf("expected 2 rejected messages, got %d", got)
+	}
+}
@@ -0,0 +1,97 @@
+package xrpl
+
+import "sync"
+
+// Streams accepted by the subscribe command.
+const (
+	StreamTransactions         = "transactions"
+	StreamTransactionsProposed = "transactions_proposed"
+	StreamLedger               = "ledger"
+	StreamValidations          = "validations"
+	StreamServer               = "server"
+	StreamConsensus            = "consensus"
+)
+
+// streamMessageTypes maps the type field of stream messages to the stream
+// that sends them. Transaction messages are routed by their validated flag
+// instead.
+var streamMessageTypes = map[string]string{
+	"ledgerClosed":       StreamLedger,
+	"validationReceived": StreamValidations,
+	"serverStatus":       StreamServer,
+	"consensusPhase":     StreamConsensus,
+}
+
+// IsStream reports whether name is a stream a Dispatcher can route.
+func IsStream(name string) bool {
+	switch name {
+	case StreamTransactions, StreamTransactionsProposed:
+		return true
+	}
+	for _, stream := range streamMessageTypes {
+		if stream == name {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamHandler handles one message of a subscribed stream.
+type StreamHandler func(msg map[string]interface{})
+
+// Dispatcher routes the messages of one subscription to handlers registered
+// per stream, so several consumers can share a connection. Validated
+// transactions go to StreamTransactions handlers and unvalidated ones to
+// StreamTransactionsProposed handlers; transactions_proposed also carries
+// validated transactions, so subscribing to it alone still feeds both.
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]StreamHandler
+}
+
+// NewDispatcher creates a dispatcher with no handlers.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string][]StreamHandler)}
+}
+
+// Handle registers a handler for stream's messages.
+func (d *Dispatcher) Handle(stream string, handler StreamHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[stream] = append(d.handlers[stream], handler)
+}
+
+// Dispatch passes msg to the handlers of its stream. Command responses and
+// messages of unknown streams are dropped. It is the callback given to
+// Subscribe.
+func (d *Dispatcher) Dispatch(msg interface{}) {
+	msgMap, ok := msg.(map[string]interface{})
+	if !ok {
+		return
+	}
+	stream := streamOf(msgMap)
+	if stream == "" {
+		return
+	}
+
+	d.mu.RLock()
+	handlers := make([]StreamHandler, len(d.handlers[stream]))
+	copy(handlers, d.handlers[stream])
+	d.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(msgMap)
+	}
+}
+
+// streamOf returns the stream msg belongs to, or "" for anything else.
+func streamOf(msg map[string]interface{}) string {
+	msgType, _ := msg["type"].(string)
+	if msgType == "transaction" {
+		if validated, _ := msg["validated"].(bool); validated {
+			return StreamTransactions
+		}
+		return StreamTransactionsProposed
+	}
+	return streamMessageTypes[msgType]
+}
@@ -0,0 +1,52 @@
+package xrpl
+
+import "testing"
+
+func TestDispatcherRoutesByStream(t *testing.T) {
+	dispatcher := NewDispatcher()
+	got := map[string]int{}
+	for _, stream := range []string{StreamTransactions, StreamTransactionsProposed, StreamLedger, StreamValidations, StreamServer, StreamConsensus} {
+		stream := stream
+		dispatcher.Handle(stream, func(msg map[string]interface{}) { got[stream]++ })
+	}
+
+	messages := []interface{}{
+		map[string]interface{}{"type": "transaction", "validated": true},
+		map[string]interface{}{"type": "transaction", "validated": false},
+		map[string]interface{}{"type": "transaction"},
+		map[string]interface{}{"type": "ledgerClosed", "ledger_index": float64(100)},
+		map[string]interface{}{"type": "validationReceived"},
+		map[string]interface{}{"type": "serverStatus"},
+		map[string]interface{}{"type": "consensusPhase", "consensus": "accepted"},
+		map[string]interface{}{"type": "response", "status": "success"},
+		"not a message",
+	}
+	for _, msg := range messages {
+		dispatcher.Dispatch(msg)
+	}
+
+	want := map[string]int{
+		StreamTransactions:         1,
+		StreamTransactionsProposed: 2,
+		StreamLedger:               1,
+		StreamValidations:          1,
+		StreamServer:               1,
+		StreamConsensus:            1,
+	}
+	for stream, count := range want {
+		if got[stream] != count {
+			t.Errorf("expected %d %s messages, got %d", count, stream, got[stream])
+		}
+	}
+}
+
+func TestIsStream(t *testing.T) {
+	for _, stream := range []string{"transactions", "transactions_proposed", "ledger", "validations", "server", "consensus"} {
+		if !IsStream(stream) {
+			t.Errorf("expected %s to be a stream", stream)
+		}
+	}
+	if IsStream("peer_status") || IsStream("") {
+		t.Error("expected unsupported streams to be rejected")
+	}
+}
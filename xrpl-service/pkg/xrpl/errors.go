@@ -0,0 +1,188 @@
+package xrpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Error classes shared by the client, validator fetcher and geolocation
+// resolver. Callers test them with errors.Is to make retry and negative-cache
+// decisions instead of matching on error strings.
+var (
+	// ErrRateLimited means the upstream asked us to back off.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNotFound means the requested object does not exist upstream and
+	// retrying will not help.
+	ErrNotFound = errors.New("not found")
+	// ErrUpstreamUnavailable means the upstream could not be reached or is
+	// temporarily unable to answer.
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrDecode means the upstream answered with a payload we could not parse.
+	ErrDecode = errors.New("decode failed")
+)
+
+// rippled error codes grouped by class. actMalformed is treated as not found
+// because a malformed address can never resolve.
+var (
+	notFoundRPCCodes = map[string]struct{}{
+		"actNotFound":      {},
+		"actMalformed":     {},
+		"lgrNotFound":      {},
+		"txnNotFound":      {},
+		"objectNotFound":   {},
+		"malformedAddress": {},
+	}
+	rateLimitedRPCCodes = map[string]struct{}{
+		"slowDown": {},
+		"tooBusy":  {},
+	}
+	unavailableRPCCodes = map[string]struct{}{
+		"noNetwork":        {},
+		"notSynced":        {},
+		"noCurrent":        {},
+		"noClosed":         {},
+		"amendmentBlocked": {},
+		"notReady":         {},
+	}
+)
+
+// RPCError is an error result returned by rippled for a JSON-RPC command.
+type RPCError struct {
+	Method  string
+	Code    string
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s: JSON-RPC error %s: %s", e.Method, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s: JSON-RPC error %s", e.Method, e.Code)
+}
+
+// Unwrap maps the rippled error code onto one of the shared error classes.
+func (e *RPCError) Unwrap() error {
+	if _, ok := notFoundRPCCodes[e.Code]; ok {
+		return ErrNotFound
+	}
+	if _, ok := rateLimitedRPCCodes[e.Code]; ok {
+		return ErrRateLimited
+	}
+	if _, ok := unavailableRPCCodes[e.Code]; ok {
+		return ErrUpstreamUnavailable
+	}
+	return nil
+}
+
+// HTTPStatusError is returned when an upstream answers with a non-2xx status.
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("http %d: %s", e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("http %d", e.StatusCode)
+}
+
+// Unwrap maps the status code onto one of the shared error classes.
+func (e *HTTPStatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone:
+		return ErrNotFound
+	case e.StatusCode >= 500:
+		return ErrUpstreamUnavailable
+	}
+	return nil
+}
+
+// newRPCError builds an RPCError from an "error" value found in a rippled
+// response, which is either a code string or an object.
+func newRPCError(method string, raw interface{}, container map[string]interface{}) *RPCError {
+	rpcErr := &RPCError{Method: method}
+	switch typed := raw.(type) {
+	case string:
+		rpcErr.Code = typed
+	case map[string]interface{}:
+		rpcErr.Code, _ = typed["code"].(string)
+		if rpcErr.Code == "" {
+			if code, ok := typed["code"].(float64); ok {
+				rpcErr.Code = fmt.Sprintf("%d", int(code))
+			}
+		}
+		rpcErr.Message, _ = typed["message"].(string)
+	default:
+		rpcErr.Code = fmt.Sprintf("%v", raw)
+	}
+	if rpcErr.Message == "" && container != nil {
+		rpcErr.Message, _ = container["error_message"].(string)
+	}
+	return rpcErr
+}
+
+// WrapTransportError classifies an HTTP transport failure as
+// ErrUpstreamUnavailable, leaving context cancellation untouched.
+func WrapTransportError(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
+}
+
+// WrapDNSError classifies a DNS lookup failure: NXDOMAIN becomes ErrNotFound
+// and anything else ErrUpstreamUnavailable.
+func WrapDNSError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return WrapTransportError(err)
+}
+
+// IsRetryable reports whether repeating the same request soon could succeed.
+// Rate limits, missing objects, decode failures and cancellation are not.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return !errors.Is(err, ErrRateLimited) &&
+		!errors.Is(err, ErrNotFound) &&
+		!errors.Is(err, ErrDecode) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded)
+}
+
+// Classify returns a low-cardinality label for err, used for error metrics.
+func Classify(err error) string {
+	switch {
+	case err == nil:
+		return "none"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrDecode):
+		return "decode"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, ErrUpstreamUnavailable):
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return "timeout"
+		}
+		return "upstream_unavailable"
+	}
+	return "other"
+}
@@ -0,0 +1,170 @@
+package xrpl
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	_ NodeClient = (*Client)(nil)
+	_ NodeClient = (*MockClient)(nil)
+)
+
+// MockClient is an in-memory NodeClient shared by package tests. Command
+// responses come from CommandFunc and stream messages are delivered with Emit.
+type MockClient struct {
+	CommandFunc func(method string, params interface{}) (interface{}, error)
+
+	mu           sync.Mutex
+	connected    bool
+	connectCalls int
+	connectErr   error
+	commandCalls map[string]int
+	callbacks    []func(interface{})
+	streams      []string
+}
+
+// NewMockClient creates a connected mock client.
+func NewMockClient(commandFunc func(method string, params interface{}) (interface{}, error)) *MockClient {
+	return &MockClient{
+		CommandFunc: commandFunc,
+		connected:   true,
+	}
+}
+
+// Connect marks the mock as connected unless SetConnectErr set an error.
+func (m *MockClient) Connect(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connectCalls++
+	if m.connectErr != nil {
+		return m.connectErr
+	}
+	m.connected = true
+	return nil
+}
+
+// SetConnectErr makes later Connect calls fail with err, or succeed again
+// when err is nil.
+func (m *MockClient) SetConnectErr(err error) {
+	m.mu.Lock()
+	m.connectErr = err
+	m.mu.Unlock()
+}
+
+// ConnectCalls returns how many times Connect was called.
+func (m *MockClient) ConnectCalls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.connectCalls
+}
+
+// Close marks the mock as disconnected.
+func (m *MockClient) Close() error {
+	m.mu.Lock()
+	m.connected = false
+	m.mu.Unlock()
+	return nil
+}
+
+// IsConnected returns connection status
+func (m *MockClient) IsConnected() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.connected
+}
+
+// Command records the call and delegates to CommandFunc.
+func (m *MockClient) Command(ctx context.Context, method string, params interface{}) (interface{}, error) {
+	m.mu.Lock()
+	if m.commandCalls == nil {
+		m.commandCalls = make(map[string]int)
+	}
+	m.commandCalls[method]++
+	commandFunc := m.CommandFunc
+	m.mu.Unlock()
+
+	if commandFunc == nil {
+		return nil, errors.New("mock command func not set")
+	}
+	return commandFunc(method, params)
+}
+
+// Subscribe records the streams and registers the callback for Emit.
+func (m *MockClient) Subscribe(ctx context.Context, streams []string, callback func(interface{})) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.connected {
+		return errors.New("not connected to XRPL")
+	}
+	m.streams = append(m.streams, streams...)
+	if callback != nil {
+		m.callbacks = append(m.callbacks, callback)
+	}
+	return nil
+}
+
+// Unsubscribe forgets the given streams.
+func (m *MockClient) Unsubscribe(ctx context.Context, streams []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	remove := make(map[string]struct{}, len(streams))
+	for _, stream := range streams {
+		remove[stream] = struct{}{}
+	}
+	kept := m.streams[:0]
+	for _, stream := range m.streams {
+		if _, ok := remove[stream]; !ok {
+			kept = append(kept, stream)
+		}
+	}
+	m.streams = kept
+	return nil
+}
+
+// GetValidators fetches validator information
+func (m *MockClient) GetValidators(ctx context.Context) (interface{}, error) {
+	return m.Command(ctx, "manifest", map[string]interface{}{})
+}
+
+// GetServerInfo fetches server information
+func (m *MockClient) GetServerInfo(ctx context.Context) (interface{}, error) {
+	return m.Command(ctx, "server_info", map[string]interface{}{})
+}
+
+// Emit delivers a stream message to all subscribed callbacks.
+func (m *MockClient) Emit(msg interface{}) {
+	m.mu.Lock()
+	callbacks := make([]func(interface{}), len(m.callbacks))
+	copy(callbacks, m.callbacks)
+	m.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(msg)
+	}
+}
+
+// CommandCalls returns how many times a method was invoked. An empty method
+// returns the total across all methods.
+func (m *MockClient) CommandCalls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if method != "" {
+		return m.commandCalls[method]
+	}
+	total := 0
+	for _, calls := range m.commandCalls {
+		total += calls
+	}
+	return total
+}
+
+// Streams returns the currently subscribed streams.
+func (m *MockClient) Streams() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]string, len(m.streams))
+	copy(out, m.streams)
+	return out
+}
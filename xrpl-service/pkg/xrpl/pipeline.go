@@ -0,0 +1,201 @@
+package xrpl
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/bcarrillodev/xrpl-visualizer/xrpl-service/internal/metrics"
+)
+
+// frame is a stream message on its way from the read loop to the
+// callbacks.
+type frame struct {
+	data    []byte
+	msg     interface{} // nil when data is not valid JSON
+	decoded chan struct{}
+}
+
+// startPipeline starts the decode workers and the dispatcher, which run
+// until the client is closed.
+func (c *Client) startPipeline() {
+	for i := 0; i < c.decodeWorkers; i++ {
+		go c.decodeLoop()
+	}
+	go c.dispatchLoop()
+}
+
+// enqueue queues a raw message for decoding and dispatch, or drops it when
+// the buffer is full.
+func (c *Client) enqueue(data []byte) {
+	f := &frame{data: data, decoded: make(chan struct{})}
+	select {
+	case c.frames <- f:
+	default:
+		c.dropped.Add(1)
+		metrics.UpstreamMessagesDroppedTotal.WithLabelValues(c.host, "buffer_full").Inc()
+		return
+	}
+	metrics.UpstreamMessageBufferDepth.WithLabelValues(c.host).Set(float64(len(c.frames)))
+	select {
+	case c.decodeQueue <- f:
+	case <-c.stopChan:
+	}
+}
+
+// decodeLoop decodes queued messages until the client is closed.
+func (c *Client) decodeLoop() {
+	for {
+		select {
+		case <-c.stopChan:
+			return
+		case f := <-c.decodeQueue:
+			var msg interface{}
+			if exceedsDepth(f.data, c.maxMessageDepth) {
+				c.dropped.Add(1)
+				metrics.UpstreamMessagesRejectedTotal.WithLabelValues(c.host, "too_deep").Inc()
+				c.logger.WithField("max_depth", c.maxMessageDepth).Debug("Dropping XRPL stream message nested beyond the depth limit")
+			} else if err := json.Unmarshal(f.data, &msg); err != nil {
+				c.dropped.Add(1)
+				metrics.UpstreamMessagesDroppedTotal.WithLabelValues(c.host, "decode").Inc()
+				c.logger.WithError(err).Debug("Dropping undecodable XRPL stream message")
+			} else {
+				f.msg = msg
+			}
+			f.data = nil
+			close(f.decoded)
+		}
+	}
+}
+
+// exceedsDepth reports whether objects and arrays in data nest deeper than
+// max. It only tracks brackets outside strings, so it is cheap enough to run
+// before decoding; malformed JSON is left for the decoder to reject.
+func exceedsDepth(data []byte, max int) bool {
+	depth := 0
+	inString, escaped := false, false
+	for _, b := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+			continue
+		}
+		switch b {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > max {
+				return true
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return false
+}
+
+// recentStreamKeys is how many transaction and ledger keys the dispatcher
+// remembers. While cycleConnection swaps connections both may deliver the
+// same messages; that overlap spans a ledger or two.
+const recentStreamKeys = 4096
+
+// recentKeys is a bounded set of the most recently added keys.
+type recentKeys struct {
+	seen  map[string]struct{}
+	order []string
+	next  int
+}
+
+func newRecentKeys(size int) *recentKeys {
+	return &recentKeys{seen: make(map[string]struct{}, size), order: make([]string, size)}
+}
+
+// add records key and reports whether it was new, evicting the oldest key
+// once the set is full.
+func (r *recentKeys) add(key string) bool {
+	if _, ok := r.seen[key]; ok {
+		return false
+	}
+	if oldest := r.order[r.next]; oldest != "" {
+		delete(r.seen, oldest)
+	}
+	r.order[r.next] = key
+	r.next = (r.next + 1) % len(r.order)
+	r.seen[key] = struct{}{}
+	return true
+}
+
+// streamMessageKey identifies a transaction by hash and validation state,
+// or a closed ledger by index. Other messages have no key and are never
+// treated as repeats.
+func streamMessageKey(msg interface{}) string {
+	msgMap, ok := msg.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	switch msgMap["type"] {
+	case "transaction":
+		txn, _ := msgMap["transaction"].(map[string]interface{})
+		hash, _ := txn["hash"].(string)
+		if hash == "" {
+			hash, _ = msgMap["hash"].(string)
+		}
+		if hash == "" {
+			return ""
+		}
+		validated, _ := msgMap["validated"].(bool)
+		return "tx:" + hash + ":" + strconv.FormatBool(validated)
+	case "ledgerClosed":
+		index, ok := msgMap["ledger_index"].(float64)
+		if !ok {
+			return ""
+		}
+		return "ledger:" + strconv.FormatFloat(index, 'f', -1, 64)
+	}
+	return ""
+}
+
+// dispatchLoop passes decoded messages to the callbacks in arrival order
+// until the client is closed. A transaction or ledger close already
+// dispatched, e.g. by the connection a cycle replaced, is dropped.
+func (c *Client) dispatchLoop() {
+	recent := newRecentKeys(recentStreamKeys)
+	for {
+		var f *frame
+		select {
+		case <-c.stopChan:
+			return
+		case f = <-c.frames:
+		}
+		select {
+		case <-c.stopChan:
+			return
+		case <-f.decoded:
+		}
+		metrics.UpstreamMessageBufferDepth.WithLabelValues(c.host).Set(float64(len(c.frames)))
+		if f.msg == nil {
+			continue
+		}
+		if key := streamMessageKey(f.msg); key != "" && !recent.add(key) {
+			metrics.UpstreamMessagesDroppedTotal.WithLabelValues(c.host, "duplicate").Inc()
+			continue
+		}
+
+		c.mu.RLock()
+		callbacks := make([]func(interface{}), len(c.callbacks))
+		copy(callbacks, c.callbacks)
+		c.mu.RUnlock()
+
+		for _, callback := range callbacks {
+			if callback != nil {
+				callback(f.msg)
+			}
+		}
+	}
+}